@@ -0,0 +1,241 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustering
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/resources"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+// The Clustering trait configures an in-memory data grid, that the Integration replicas share, to back
+// Camel clustered route policies and idempotent repositories.
+//
+// With the `hazelcast` provider, an embedded Hazelcast member is started in each replica, and the members discover
+// each other with the Kubernetes API. With the `infinispan` provider, the Integration connects to a remote Infinispan server.
+//
+// The Camel cluster service is registered in the Camel context, so that clustered route policies, e.g.,
+// `from("master:lockname:timer:tick")`, only start the routes on the leader replica. An idempotent repository, that's
+// shared by the replicas, is also registered as the `clusterIdempotentRepository` bean, e.g.,
+// `idempotentConsumer(header("id")).idempotentRepository("#bean:clusterIdempotentRepository")`.
+//
+// NOTE: with the `hazelcast` provider, this trait adds special permissions to the integration service account in order to read pods and endpoints.
+// It's recommended to use a different service account than "default" when running the integration.
+//
+// NOTE: the master trait, that's enabled automatically when the `master` component is used, must be disabled,
+// so that the Kubernetes cluster service it configures does not compete with this one.
+//
+// +camel-k:trait=clustering.
+type clusteringTrait struct {
+	trait.BaseTrait `property:",squash"`
+	// The data grid provider, either `hazelcast` or `infinispan` (default `hazelcast`).
+	Provider string `property:"provider" json:"provider,omitempty"`
+	// The name of the cluster the replicas join, or the Infinispan cache storing the idempotent repository entries.
+	// Defaults to the Integration name.
+	ClusterName string `property:"cluster-name" json:"clusterName,omitempty"`
+	// Registers the `clusterIdempotentRepository` bean (default `true`).
+	IdempotentRepository *bool `property:"idempotent-repository" json:"idempotentRepository,omitempty"`
+	// The comma separated list of the remote Infinispan server addresses, e.g., `infinispan:11222`.
+	// It is required with the `infinispan` provider.
+	InfinispanHosts string `property:"infinispan-hosts" json:"infinispanHosts,omitempty"`
+	// The name of the Secret, containing the `username` and `password` keys, used to authenticate to the remote Infinispan server.
+	InfinispanSecret string `property:"infinispan-secret" json:"infinispanSecret,omitempty"`
+}
+
+// NewClusteringTrait --.
+func NewClusteringTrait() trait.Trait {
+	return &clusteringTrait{
+		BaseTrait: trait.NewBaseTrait("clustering", trait.TraitOrderBeforeControllerCreation),
+	}
+}
+
+const (
+	hazelcastProvider  = "hazelcast"
+	infinispanProvider = "infinispan"
+
+	infinispanUsernameEnvVar = "CAMEL_K_CLUSTERING_INFINISPAN_USERNAME"
+	infinispanPasswordEnvVar = "CAMEL_K_CLUSTERING_INFINISPAN_PASSWORD"
+)
+
+func (t *clusteringTrait) Configure(e *trait.Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInPhase(v1.IntegrationPhaseInitialization) && !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	switch t.provider() {
+	case hazelcastProvider:
+	case infinispanProvider:
+		if t.InfinispanHosts == "" {
+			return false, fmt.Errorf("the infinispan-hosts option is required with the %s provider", infinispanProvider)
+		}
+	default:
+		return false, fmt.Errorf("unsupported clustering provider %s, must be %s or %s", t.Provider, hazelcastProvider, infinispanProvider)
+	}
+
+	return true, nil
+}
+
+func (t *clusteringTrait) Apply(e *trait.Environment) error {
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, fmt.Sprintf("camel:%s", t.provider()))
+		return nil
+	}
+
+	clusterName := t.ClusterName
+	if clusterName == "" {
+		clusterName = e.Integration.Name
+	}
+
+	var properties []string
+	switch t.provider() {
+	case hazelcastProvider:
+		if err := t.addRoleBinding(e); err != nil {
+			return err
+		}
+		// Hazelcast overrides its default configuration with the HZ_ prefixed environment variables
+		envvar.SetVal(&e.EnvVars, "HZ_CLUSTERNAME", clusterName)
+		envvar.SetVal(&e.EnvVars, "HZ_NETWORK_JOIN_MULTICAST_ENABLED", "false")
+		envvar.SetVal(&e.EnvVars, "HZ_NETWORK_JOIN_KUBERNETES_ENABLED", "true")
+		envvar.SetVal(&e.EnvVars, "HZ_NETWORK_JOIN_KUBERNETES_NAMESPACE", e.Integration.Namespace)
+		envvar.SetVal(&e.EnvVars, "HZ_NETWORK_JOIN_KUBERNETES_PODLABELNAME", v1.IntegrationLabel)
+		envvar.SetVal(&e.EnvVars, "HZ_NETWORK_JOIN_KUBERNETES_PODLABELVALUE", e.Integration.Name)
+
+		properties = append(properties,
+			"camel.beans.clusterHazelcastInstance=#class:com.hazelcast.core.Hazelcast#newHazelcastInstance",
+			"camel.beans.clusterService=#class:org.apache.camel.component.hazelcast.cluster.HazelcastClusterService",
+			"camel.beans.clusterService.hazelcastInstance=#bean:clusterHazelcastInstance",
+		)
+		if pointer.BoolDeref(t.IdempotentRepository, true) {
+			properties = append(properties, fmt.Sprintf(
+				"camel.beans.clusterIdempotentRepository=#class:org.apache.camel.processor.idempotent.hazelcast.HazelcastIdempotentRepository(#bean:clusterHazelcastInstance, '%s')", clusterName))
+		}
+	case infinispanProvider:
+		properties = append(properties,
+			"camel.beans.clusterService=#class:org.apache.camel.component.infinispan.remote.cluster.InfinispanRemoteClusterService",
+			fmt.Sprintf("camel.beans.clusterService.hosts=%s", t.InfinispanHosts),
+		)
+		if pointer.BoolDeref(t.IdempotentRepository, true) {
+			properties = append(properties,
+				fmt.Sprintf("camel.beans.clusterIdempotentRepository=#class:org.apache.camel.component.infinispan.remote.InfinispanRemoteIdempotentRepository('%s')", clusterName),
+				fmt.Sprintf("camel.beans.clusterIdempotentRepository.configuration.hosts=%s", t.InfinispanHosts),
+			)
+		}
+		if t.InfinispanSecret != "" {
+			t.addSecretEnvVar(e, infinispanUsernameEnvVar, "username")
+			t.addSecretEnvVar(e, infinispanPasswordEnvVar, "password")
+			beans := []string{"clusterService"}
+			if pointer.BoolDeref(t.IdempotentRepository, true) {
+				beans = append(beans, "clusterIdempotentRepository.configuration")
+			}
+			for _, bean := range beans {
+				properties = append(properties,
+					fmt.Sprintf("camel.beans.%s.username={{env:%s}}", bean, infinispanUsernameEnvVar),
+					fmt.Sprintf("camel.beans.%s.password={{env:%s}}", bean, infinispanPasswordEnvVar),
+				)
+			}
+		}
+	}
+
+	for _, p := range properties {
+		e.Integration.Status.Configuration = append(e.Integration.Status.Configuration,
+			v1.ConfigurationSpec{Type: "property", Value: p},
+		)
+	}
+
+	return nil
+}
+
+func (t *clusteringTrait) provider() string {
+	if t.Provider == "" {
+		return hazelcastProvider
+	}
+	return strings.ToLower(t.Provider)
+}
+
+func (t *clusteringTrait) addSecretEnvVar(e *trait.Environment, name string, key string) {
+	envvar.SetVar(&e.EnvVars, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: t.InfinispanSecret,
+				},
+				Key: key,
+			},
+		},
+	})
+}
+
+// addRoleBinding grants the Integration service account the permissions required by the Hazelcast Kubernetes discovery.
+func (t *clusteringTrait) addRoleBinding(e *trait.Environment) error {
+	serviceAccount := e.Integration.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+
+	templateData := struct {
+		Namespace      string
+		Name           string
+		ServiceAccount string
+	}{
+		Namespace:      e.Integration.Namespace,
+		Name:           fmt.Sprintf("%s-clustering", e.Integration.Name),
+		ServiceAccount: serviceAccount,
+	}
+
+	role, err := loadResource(e, "clustering-role.tmpl", templateData)
+	if err != nil {
+		return err
+	}
+	roleBinding, err := loadResource(e, "clustering-role-binding.tmpl", templateData)
+	if err != nil {
+		return err
+	}
+
+	e.Resources.Add(role)
+	e.Resources.Add(roleBinding)
+
+	return nil
+}
+
+func loadResource(e *trait.Environment, name string, params interface{}) (ctrl.Object, error) {
+	data, err := resources.TemplateResource(fmt.Sprintf("/addons/clustering/%s", name), params)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := kubernetes.LoadResourceFromYaml(e.Client.GetScheme(), data)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,152 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustering
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+func TestClusteringDisabled(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+
+	clustering := NewClusteringTrait()
+	enabled, err := clustering.Configure(e)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+}
+
+func TestClusteringInvalidConfiguration(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+
+	clustering := NewClusteringTrait()
+	clustering.(*clusteringTrait).Enabled = pointer.Bool(true)
+	clustering.(*clusteringTrait).Provider = "redis"
+	_, err := clustering.Configure(e)
+	assert.NotNil(t, err)
+
+	clustering.(*clusteringTrait).Provider = infinispanProvider
+	_, err = clustering.Configure(e)
+	assert.NotNil(t, err)
+}
+
+func TestClusteringDependencies(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseInitialization)
+
+	clustering := NewClusteringTrait()
+	clustering.(*clusteringTrait).Enabled = pointer.Bool(true)
+	ok, err := clustering.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, clustering.Apply(e))
+
+	assert.Contains(t, e.Integration.Status.Dependencies, "camel:hazelcast")
+	assert.Empty(t, e.Integration.Status.Configuration)
+}
+
+func TestClusteringHazelcast(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+
+	clustering := NewClusteringTrait()
+	clustering.(*clusteringTrait).Enabled = pointer.Bool(true)
+	ok, err := clustering.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, clustering.Apply(e))
+
+	assert.Equal(t, "hello", envvar.Get(e.EnvVars, "HZ_CLUSTERNAME").Value)
+	assert.Equal(t, "ns", envvar.Get(e.EnvVars, "HZ_NETWORK_JOIN_KUBERNETES_NAMESPACE").Value)
+	assert.Equal(t, "hello", envvar.Get(e.EnvVars, "HZ_NETWORK_JOIN_KUBERNETES_PODLABELVALUE").Value)
+
+	roles := 0
+	e.Resources.Visit(func(o runtime.Object) {
+		switch r := o.(type) {
+		case *rbacv1.Role:
+			roles++
+			assert.Equal(t, "hello-clustering", r.Name)
+		case *rbacv1.RoleBinding:
+			assert.Equal(t, "default", r.Subjects[0].Name)
+		}
+	})
+	assert.Equal(t, 1, roles)
+	assert.Contains(t, e.Integration.Status.Configuration, v1.ConfigurationSpec{
+		Type:  "property",
+		Value: "camel.beans.clusterService=#class:org.apache.camel.component.hazelcast.cluster.HazelcastClusterService",
+	})
+	assert.Contains(t, e.Integration.Status.Configuration, v1.ConfigurationSpec{
+		Type:  "property",
+		Value: "camel.beans.clusterIdempotentRepository=#class:org.apache.camel.processor.idempotent.hazelcast.HazelcastIdempotentRepository(#bean:clusterHazelcastInstance, 'hello')",
+	})
+}
+
+func TestClusteringInfinispan(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+
+	clustering := NewClusteringTrait()
+	clustering.(*clusteringTrait).Enabled = pointer.Bool(true)
+	clustering.(*clusteringTrait).Provider = infinispanProvider
+	clustering.(*clusteringTrait).InfinispanHosts = "infinispan:11222"
+	clustering.(*clusteringTrait).InfinispanSecret = "infinispan-credentials"
+	clustering.(*clusteringTrait).IdempotentRepository = pointer.Bool(false)
+	ok, err := clustering.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, clustering.Apply(e))
+
+	assert.Equal(t, 0, e.Resources.Size())
+	assert.Equal(t, "infinispan-credentials", envvar.Get(e.EnvVars, infinispanPasswordEnvVar).ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, []v1.ConfigurationSpec{
+		{Type: "property", Value: "camel.beans.clusterService=#class:org.apache.camel.component.infinispan.remote.cluster.InfinispanRemoteClusterService"},
+		{Type: "property", Value: "camel.beans.clusterService.hosts=infinispan:11222"},
+		{Type: "property", Value: "camel.beans.clusterService.username={{env:CAMEL_K_CLUSTERING_INFINISPAN_USERNAME}}"},
+		{Type: "property", Value: "camel.beans.clusterService.password={{env:CAMEL_K_CLUSTERING_INFINISPAN_PASSWORD}}"},
+	}, e.Integration.Status.Configuration)
+}
+
+func createEnvironment(t *testing.T, phase v1.IntegrationPhase) *trait.Environment {
+	t.Helper()
+
+	client, err := test.NewFakeClient()
+	assert.Nil(t, err)
+
+	return &trait.Environment{
+		Ctx:    context.TODO(),
+		Client: client,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "hello",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: phase,
+			},
+		},
+		Resources: kubernetes.NewCollection(),
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/property"
+	"github.com/apache/camel-k/pkg/util/uri"
+)
+
+// endpointTriggers infers the KEDA triggers scaling the Integration from the consumer endpoints of a Camel component.
+// It returns no trigger when the endpoint options do not allow to reach the event source the same way the consumer does.
+type endpointTriggers func(e *trait.Environment, endpoint string) []kedaTrigger
+
+// endpointScalers are the Camel components whose consumer endpoints are mapped to a KEDA scaler.
+var endpointScalers = map[string]endpointTriggers{
+	"kafka":    kafkaTriggers,
+	"rabbitmq": rabbitmqTriggers,
+}
+
+var (
+	placeholderRegexp = regexp.MustCompile(`{{([^{}]+)}}`)
+	jaasOptionRegexp  = regexp.MustCompile(`(username|password)\s*=\s*"([^"]*)"`)
+)
+
+func (t *kedaTrait) populateTriggersFromEndpoints(e *trait.Environment, fromURIs []string) {
+	for _, endpoint := range fromURIs {
+		component := uri.GetComponent(endpoint)
+		triggers, ok := endpointScalers[component]
+		if !ok {
+			continue
+		}
+		found := triggers(e, endpoint)
+		if len(found) == 0 {
+			t.L.ForIntegration(e.Integration).Infof("No KEDA trigger inferred from the %s consumer, as its options cannot be resolved", component)
+		}
+		t.Triggers = append(t.Triggers, found...)
+	}
+}
+
+// kafkaTriggers maps the Kafka consumers to the `kafka` scaler, that scales on the lag of their consumer group.
+func kafkaTriggers(e *trait.Environment, endpoint string) []kedaTrigger {
+	brokers := getEndpointOption(e, endpoint, "kafka", "brokers")
+	groupID := getEndpointOption(e, endpoint, "kafka", "groupId")
+	topics, _ := resolvePlaceholders(e, getEndpointPath(endpoint))
+	if brokers == "" || groupID == "" || topics == "" {
+		return nil
+	}
+
+	authentication, ok := kafkaAuthentication(e, endpoint)
+	if !ok {
+		return nil
+	}
+
+	var triggers []kedaTrigger
+	for _, topic := range strings.Split(topics, ",") {
+		triggers = append(triggers, kedaTrigger{
+			Type: "kafka",
+			Metadata: map[string]string{
+				"bootstrapServers": brokers,
+				"consumerGroup":    groupID,
+				"topic":            strings.TrimSpace(topic),
+			},
+			authentication: authentication,
+		})
+	}
+	return triggers
+}
+
+// kafkaAuthentication returns the KEDA authentication parameters matching the security options of the Kafka endpoint,
+// and whether they are supported by the scaler.
+func kafkaAuthentication(e *trait.Environment, endpoint string) (map[string]string, bool) {
+	protocol := getEndpointOption(e, endpoint, "kafka", "securityProtocol")
+	authentication := make(map[string]string)
+	if protocol == "SSL" || protocol == "SASL_SSL" {
+		authentication["tls"] = "enable"
+	}
+	if protocol == "SASL_PLAINTEXT" || protocol == "SASL_SSL" {
+		switch getEndpointOption(e, endpoint, "kafka", "saslMechanism") {
+		case "", "PLAIN":
+			authentication["sasl"] = "plaintext"
+		case "SCRAM-SHA-256":
+			authentication["sasl"] = "scram_sha256"
+		case "SCRAM-SHA-512":
+			authentication["sasl"] = "scram_sha512"
+		default:
+			return nil, false
+		}
+		jaas := getEndpointOption(e, endpoint, "kafka", "saslJaasConfig")
+		for _, option := range jaasOptionRegexp.FindAllStringSubmatch(jaas, -1) {
+			authentication[option[1]] = option[2]
+		}
+		if authentication["username"] == "" || authentication["password"] == "" {
+			return nil, false
+		}
+	}
+	if len(authentication) == 0 {
+		return nil, true
+	}
+	return authentication, true
+}
+
+// rabbitmqTriggers maps the RabbitMQ consumers to the `rabbitmq` scaler, that scales on the depth of their queue.
+// The connection URL of the broker, that holds the credentials, is provided as authentication parameter.
+func rabbitmqTriggers(e *trait.Environment, endpoint string) []kedaTrigger {
+	options := make(map[string]string)
+	for option, def := range map[string]string{
+		"queue":      "",
+		"hostname":   "",
+		"portNumber": "5672",
+		"username":   "guest",
+		"password":   "guest",
+		"vhost":      "/",
+	} {
+		options[option] = getEndpointOption(e, endpoint, "rabbitmq", option)
+		if options[option] == "" {
+			options[option] = def
+		}
+	}
+	// the consumers without queue name declare a queue of their own, that cannot be known in advance
+	if options["queue"] == "" || options["hostname"] == "" {
+		return nil
+	}
+
+	host := url.URL{
+		Scheme: "amqp",
+		User:   url.UserPassword(options["username"], options["password"]),
+		Host:   net.JoinHostPort(options["hostname"], options["portNumber"]),
+	}
+	if vhost := options["vhost"]; vhost != "/" {
+		host.Path = "/" + vhost
+		host.RawPath = "/" + url.PathEscape(vhost)
+	}
+
+	return []kedaTrigger{
+		{
+			Type: "rabbitmq",
+			Metadata: map[string]string{
+				"protocol":  "amqp",
+				"queueName": options["queue"],
+			},
+			authentication: map[string]string{
+				"host": host.String(),
+			},
+		},
+	}
+}
+
+// getEndpointOption returns the value of the option set on the endpoint, or on the component, with the property
+// placeholders resolved. It returns an empty string when the placeholders cannot be resolved, e.g., when the properties
+// are provided by Secrets mounted into the Integration.
+func getEndpointOption(e *trait.Environment, endpoint string, component string, option string) string {
+	value := uri.GetQueryParameter(endpoint, option)
+	if value == "" {
+		value = getIntegrationProperty(e, fmt.Sprintf("camel.component.%s.%s", component, toKebabCase(option)))
+	}
+	if value == "" {
+		value = getIntegrationProperty(e, fmt.Sprintf("camel.component.%s.%s", component, option))
+	}
+	resolved, ok := resolvePlaceholders(e, value)
+	if !ok {
+		return ""
+	}
+	return resolved
+}
+
+// getEndpointPath returns the context path of the endpoint, e.g., the Kafka topics.
+func getEndpointPath(endpoint string) string {
+	path := strings.TrimPrefix(endpoint, uri.GetComponent(endpoint)+":")
+	path = strings.TrimPrefix(path, "//")
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// resolvePlaceholders replaces the property placeholders of the value with the properties of the Integration,
+// and returns whether they have all been resolved.
+func resolvePlaceholders(e *trait.Environment, value string) (string, bool) {
+	ok := true
+	resolved := placeholderRegexp.ReplaceAllStringFunc(value, func(placeholder string) string {
+		key := strings.TrimSpace(placeholderRegexp.FindStringSubmatch(placeholder)[1])
+		defaultValue := ""
+		if i := strings.Index(key, ":"); i >= 0 {
+			key, defaultValue = key[:i], key[i+1:]
+		}
+		if v := getIntegrationProperty(e, key); v != "" {
+			return v
+		}
+		if defaultValue == "" {
+			ok = false
+		}
+		return defaultValue
+	})
+	return resolved, ok
+}
+
+// getIntegrationProperty returns the value of the property configured on the Integration.
+func getIntegrationProperty(e *trait.Environment, key string) string {
+	for _, c := range e.Integration.Spec.Configuration {
+		if c.Type != "property" {
+			continue
+		}
+		if v, err := property.DecodePropertyFileValue(c.Value, key); err == nil && v != "" {
+			return v
+		}
+	}
+	return e.ApplicationProperties[key]
+}
+
+func toKebabCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteRune('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -62,7 +62,9 @@ const (
 
 // The KEDA trait can be used for automatic integration with KEDA autoscalers.
 // The trait can be either manually configured using the `triggers` option or automatically configured
-// via markers in the Kamelets.
+// via markers in the Kamelets, and from the options of the Kafka and RabbitMQ consumer endpoints.
+// It generates a ScaledObject targeting the Integration, or its KameletBinding, so that it can scale to zero
+// without Knative.
 //
 // For information on how to use KEDA enabled Kamelets with the KEDA trait, refer to
 // xref:ROOT:kamelets/kamelets-user.adoc#kamelet-keda-user[the KEDA section in the Kamelets user guide].
@@ -74,7 +76,9 @@ const (
 // +camel-k:trait=keda.
 type kedaTrait struct {
 	trait.BaseTrait `property:",squash"`
-	// Enables automatic configuration of the trait. Allows the trait to infer KEDA triggers from the Kamelets.
+	// Enables automatic configuration of the trait. Allows the trait to infer KEDA triggers from the Kamelets,
+	// and from the Kafka and RabbitMQ consumer endpoints, i.e., `kafka` triggers scaling on the consumer group lag,
+	// and `rabbitmq` triggers scaling on the queue depth.
 	Auto *bool `property:"auto" json:"auto,omitempty"`
 	// Set the spec->replicas field on the top level controller to an explicit value if missing, to allow KEDA to recognize it as a scalable resource.
 	HackControllerReplicas *bool `property:"hack-controller-replicas" json:"hackControllerReplicas,omitempty"`
@@ -119,7 +123,7 @@ func (t *kedaTrait) Configure(e *trait.Environment) (bool, error) {
 	}
 
 	if t.Auto == nil || *t.Auto {
-		if err := t.populateTriggersFromKamelets(e); err != nil {
+		if err := t.populateTriggers(e); err != nil {
 			return false, err
 		}
 	}
@@ -305,27 +309,41 @@ func (t *kedaTrait) getTopControllerReference(e *trait.Environment) *v1.ObjectRe
 	}
 }
 
-func (t *kedaTrait) populateTriggersFromKamelets(e *trait.Environment) error {
+// populateTriggers infers the triggers from the consumer endpoints of the Integration, either from the markers of
+// the Kamelets they reference, or from the options of the endpoints whose component is mapped to a KEDA scaler.
+func (t *kedaTrait) populateTriggers(e *trait.Environment) error {
 	sources, err := kubernetes.ResolveIntegrationSources(e.Ctx, e.Client, e.Integration, e.Resources)
 	if err != nil {
 		return err
 	}
-	kameletURIs := make(map[string][]string)
+	var fromURIs []string
 	metadata.Each(e.CamelCatalog, sources, func(_ int, meta metadata.IntegrationMetadata) bool {
-		for _, kameletURI := range meta.FromURIs {
-			if kameletStr := source.ExtractKamelet(kameletURI); kameletStr != "" && camelv1alpha1.ValidKameletName(kameletStr) {
-				kamelet := kameletStr
-				if strings.Contains(kamelet, "/") {
-					kamelet = kamelet[0:strings.Index(kamelet, "/")]
-				}
-				uriList := kameletURIs[kamelet]
-				util.StringSliceUniqueAdd(&uriList, kameletURI)
-				sort.Strings(uriList)
-				kameletURIs[kamelet] = uriList
-			}
+		for _, fromURI := range meta.FromURIs {
+			util.StringSliceUniqueAdd(&fromURIs, fromURI)
 		}
 		return true
 	})
+	sort.Strings(fromURIs)
+
+	if err := t.populateTriggersFromKamelets(e, fromURIs); err != nil {
+		return err
+	}
+	t.populateTriggersFromEndpoints(e, fromURIs)
+
+	return nil
+}
+
+func (t *kedaTrait) populateTriggersFromKamelets(e *trait.Environment, fromURIs []string) error {
+	kameletURIs := make(map[string][]string)
+	for _, kameletURI := range fromURIs {
+		if kameletStr := source.ExtractKamelet(kameletURI); kameletStr != "" && camelv1alpha1.ValidKameletName(kameletStr) {
+			kamelet := kameletStr
+			if strings.Contains(kamelet, "/") {
+				kamelet = kamelet[0:strings.Index(kamelet, "/")]
+			}
+			kameletURIs[kamelet] = append(kameletURIs[kamelet], kameletURI)
+		}
+	}
 
 	if len(kameletURIs) == 0 {
 		return nil
@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/apache/camel-k/pkg/client"
+)
+
+const (
+	// The labels set by the OpenTelemetry Operator on the Services of the OpenTelemetryCollector resources
+	collectorSelector = "app.kubernetes.io/managed-by=opentelemetry-operator,app.kubernetes.io/component=opentelemetry-collector"
+
+	otlpGRPCPortName = "otlp-grpc"
+	otlpHTTPPortName = "otlp-http"
+)
+
+// findCollectorEndpoint returns the OTLP endpoint, for the given protocol, of the OpenTelemetry Collector deployed in
+// the namespace, or an empty string if there is none. The first one, in alphabetical order, is returned when multiple
+// collectors are deployed.
+func findCollectorEndpoint(ctx context.Context, c client.Client, namespace string, protocol string) (string, error) {
+	lst, err := c.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: collectorSelector,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	portName := otlpGRPCPortName
+	if protocol == protocolHTTPProtobuf {
+		portName = otlpHTTPPortName
+	}
+
+	var candidates []string
+	for _, svc := range lst.Items {
+		if strings.HasSuffix(svc.Name, "-headless") {
+			continue
+		}
+		for _, port := range svc.Spec.Ports {
+			if port.Name == portName && port.Port > 0 {
+				candidates = append(candidates, fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", svc.Name, svc.Namespace, port.Port))
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	sort.Strings(candidates)
+
+	return candidates[0], nil
+}
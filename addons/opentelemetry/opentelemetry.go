@@ -0,0 +1,222 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetry
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+// The OpenTelemetry trait can be used to automatically publish tracing information, using the OTLP protocol,
+// to an OpenTelemetry collector. It supersedes the `tracing` trait, that relies on the deprecated OpenTracing API,
+// and cannot be enabled together with it.
+//
+// The trait is able to automatically discover the OTLP endpoint of an OpenTelemetry Collector, deployed by the
+// OpenTelemetry Operator in the namespace of the Integration.
+//
+// The headers sent along with the exported data, e.g., to authenticate to a managed tracing backend, are read from
+// a Secret, and never copied into the Integration configuration.
+//
+// The OpenTelemetry trait is disabled by default.
+//
+// +camel-k:trait=opentelemetry.
+type openTelemetryTrait struct {
+	trait.BaseTrait `property:",squash"`
+	// Enables automatic configuration of the trait, including automatic discovery of the OpenTelemetry Collector.
+	Auto *bool `property:"auto" json:"auto,omitempty"`
+	// The name of the service that publishes tracing data (defaults to the integration name)
+	ServiceName string `property:"service-name" json:"serviceName,omitempty"`
+	// The target endpoint of the OTLP exporter (automatically discovered by default)
+	Endpoint string `property:"endpoint" json:"endpoint,omitempty"`
+	// The OTLP transport protocol, either `grpc` or `http/protobuf` (default `grpc`)
+	Protocol string `property:"protocol" json:"protocol,omitempty"`
+	// The Secret key containing the headers sent by the OTLP exporter, as comma-separated `key=value` pairs,
+	// with the `secret-name/key` syntax. The `headers` key is used when only the Secret name is set.
+	HeadersSecret string `property:"headers-secret" json:"headersSecret,omitempty"`
+	// The sampler, e.g., `parentbased_always_on` or `traceidratio` (default `parentbased_always_on`)
+	Sampler *string `property:"sampler" json:"sampler,omitempty"`
+	// The sampler specific argument, e.g., the sampling ratio for the `traceidratio` sampler
+	SamplerArg *string `property:"sampler-arg" json:"samplerArg,omitempty"`
+}
+
+const (
+	openTelemetryTraitID = "opentelemetry"
+
+	openTelemetryDependency = "mvn:org.apache.camel.quarkus:camel-quarkus-opentelemetry"
+
+	protocolGRPC         = "grpc"
+	protocolHTTPProtobuf = "http/protobuf"
+
+	defaultHeadersSecretKey = "headers"
+	headersEnvVar           = "CAMEL_K_OTEL_EXPORTER_HEADERS"
+
+	propEndpoint    = "propEndpoint"
+	propProtocol    = "propProtocol"
+	propHeaders     = "propHeaders"
+	propServiceName = "propServiceName"
+	propSampler     = "propSampler"
+	propSamplerArg  = "propSamplerArg"
+)
+
+var (
+	openTelemetryProperties = map[v1.RuntimeProvider]map[string]string{
+		v1.RuntimeProviderQuarkus: {
+			propEndpoint:    "quarkus.otel.exporter.otlp.traces.endpoint",
+			propProtocol:    "quarkus.otel.exporter.otlp.traces.protocol",
+			propHeaders:     "quarkus.otel.exporter.otlp.traces.headers",
+			propServiceName: "quarkus.otel.service.name",
+			propSampler:     "quarkus.otel.traces.sampler",
+			propSamplerArg:  "quarkus.otel.traces.sampler.arg",
+		},
+	}
+
+	defaultSampler = "parentbased_always_on"
+)
+
+// NewOpenTelemetryTrait --.
+func NewOpenTelemetryTrait() trait.Trait {
+	return &openTelemetryTrait{
+		BaseTrait: trait.NewBaseTrait(openTelemetryTraitID, trait.TraitOrderBeforeControllerCreation),
+	}
+}
+
+func (t *openTelemetryTrait) Configure(e *trait.Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInPhase(v1.IntegrationPhaseInitialization) && !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	switch t.Protocol {
+	case "":
+		t.Protocol = protocolGRPC
+	case protocolGRPC, protocolHTTPProtobuf:
+	default:
+		return false, fmt.Errorf("unsupported OTLP protocol %q, must be either %s or %s", t.Protocol, protocolGRPC, protocolHTTPProtobuf)
+	}
+
+	if t.HeadersSecret != "" {
+		if _, _, err := t.headersSecretKey(); err != nil {
+			return false, err
+		}
+	}
+
+	if pointer.BoolDeref(t.Auto, true) {
+		if t.Endpoint == "" && t.Client != nil {
+			endpoint, err := findCollectorEndpoint(e.Ctx, t.Client, e.Integration.Namespace, t.Protocol)
+			if err != nil {
+				return false, err
+			}
+			if endpoint != "" {
+				t.L.Infof("Using OpenTelemetry Collector endpoint: %s", endpoint)
+				t.Endpoint = endpoint
+			}
+		}
+
+		if t.ServiceName == "" {
+			t.ServiceName = e.Integration.Name
+		}
+
+		if t.Sampler == nil {
+			t.Sampler = &defaultSampler
+		}
+	}
+
+	return true, nil
+}
+
+// InfluencesTraits makes sure the exporter environment variables are set before the container trait consumes them.
+func (t *openTelemetryTrait) InfluencesTraits() []trait.ID {
+	return []trait.ID{"container"}
+}
+
+func (t *openTelemetryTrait) Apply(e *trait.Environment) error {
+	util.StringSliceUniqueAdd(&e.Integration.Status.Capabilities, v1.CapabilityTracing)
+
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, openTelemetryDependency)
+		return nil
+	}
+
+	if e.CamelCatalog == nil {
+		return nil
+	}
+	properties := openTelemetryProperties[e.CamelCatalog.CamelCatalogSpec.Runtime.Provider]
+
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+	setProperty := func(name string, value string) {
+		if prop := properties[name]; prop != "" && value != "" {
+			e.ApplicationProperties[prop] = value
+		}
+	}
+
+	setProperty(propEndpoint, t.Endpoint)
+	setProperty(propProtocol, t.Protocol)
+	setProperty(propServiceName, t.ServiceName)
+	if t.Sampler != nil {
+		setProperty(propSampler, *t.Sampler)
+	}
+	if t.SamplerArg != nil {
+		setProperty(propSamplerArg, *t.SamplerArg)
+	}
+
+	if t.HeadersSecret != "" {
+		name, key, err := t.headersSecretKey()
+		if err != nil {
+			return err
+		}
+		envvar.SetVar(&e.EnvVars, corev1.EnvVar{
+			Name: headersEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: name,
+					},
+					Key: key,
+				},
+			},
+		})
+		setProperty(propHeaders, fmt.Sprintf("${%s}", headersEnvVar))
+	}
+
+	return nil
+}
+
+// headersSecretKey returns the name and key of the Secret containing the exporter headers.
+func (t *openTelemetryTrait) headersSecretKey() (string, string, error) {
+	name, key := t.HeadersSecret, defaultHeadersSecretKey
+	if i := strings.Index(t.HeadersSecret, "/"); i >= 0 {
+		name, key = t.HeadersSecret[:i], t.HeadersSecret[i+1:]
+	}
+	if name == "" || key == "" {
+		return "", "", fmt.Errorf("illegal OpenTelemetry headers secret %q, syntax: secret-name[/secret-key]", t.HeadersSecret)
+	}
+	return name, key, nil
+}
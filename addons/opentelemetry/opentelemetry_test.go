@@ -0,0 +1,177 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentelemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestOpenTelemetryTraitDependency(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseInitialization)
+	otel := newTestOpenTelemetryTrait(t)
+
+	ok, err := otel.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, otel.Apply(e))
+
+	assert.Contains(t, e.Integration.Status.Dependencies, "mvn:org.apache.camel.quarkus:camel-quarkus-opentelemetry")
+	assert.Contains(t, e.Integration.Status.Capabilities, v1.CapabilityTracing)
+	assert.Empty(t, e.ApplicationProperties)
+}
+
+func TestOpenTelemetryTraitCollectorDiscovery(t *testing.T) {
+	collector := func(name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      name,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "opentelemetry-operator",
+					"app.kubernetes.io/component":  "opentelemetry-collector",
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Name: "otlp-grpc", Port: 4317},
+					{Name: "otlp-http", Port: 4318},
+				},
+			},
+		}
+	}
+
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+	otel := newTestOpenTelemetryTrait(t, collector("otel-collector"), collector("otel-collector-headless"))
+
+	ok, err := otel.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, otel.Apply(e))
+
+	assert.Equal(t, "http://otel-collector.ns.svc.cluster.local:4317", e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.endpoint"])
+	assert.Equal(t, "grpc", e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.protocol"])
+	assert.Equal(t, "test", e.ApplicationProperties["quarkus.otel.service.name"])
+	assert.Equal(t, "parentbased_always_on", e.ApplicationProperties["quarkus.otel.traces.sampler"])
+	assert.Empty(t, e.ApplicationProperties["quarkus.otel.traces.sampler.arg"])
+	assert.Empty(t, e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.headers"])
+
+	e = createEnvironment(t, v1.IntegrationPhaseDeploying)
+	otel = newTestOpenTelemetryTrait(t, collector("otel-collector"))
+	otel.Protocol = "http/protobuf"
+
+	ok, err = otel.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, otel.Apply(e))
+
+	assert.Equal(t, "http://otel-collector.ns.svc.cluster.local:4318", e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.endpoint"])
+	assert.Equal(t, "http/protobuf", e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.protocol"])
+}
+
+func TestOpenTelemetryTraitHeadersSecret(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+	otel := newTestOpenTelemetryTrait(t)
+	otel.Endpoint = "https://otlp.example.com:4317"
+	otel.HeadersSecret = "otlp-credentials"
+	otel.Sampler = pointer.String("traceidratio")
+	otel.SamplerArg = pointer.String("0.1")
+
+	ok, err := otel.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, otel.Apply(e))
+
+	assert.Equal(t, "https://otlp.example.com:4317", e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.endpoint"])
+	assert.Equal(t, "${CAMEL_K_OTEL_EXPORTER_HEADERS}", e.ApplicationProperties["quarkus.otel.exporter.otlp.traces.headers"])
+	assert.Equal(t, "traceidratio", e.ApplicationProperties["quarkus.otel.traces.sampler"])
+	assert.Equal(t, "0.1", e.ApplicationProperties["quarkus.otel.traces.sampler.arg"])
+	assert.Len(t, e.EnvVars, 1)
+	assert.Equal(t, "CAMEL_K_OTEL_EXPORTER_HEADERS", e.EnvVars[0].Name)
+	assert.Equal(t, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "otlp-credentials"}, Key: "headers"},
+		*e.EnvVars[0].ValueFrom.SecretKeyRef)
+
+	otel.HeadersSecret = "otlp-credentials/api-key"
+	e.EnvVars = nil
+	assert.Nil(t, otel.Apply(e))
+	assert.Equal(t, "api-key", e.EnvVars[0].ValueFrom.SecretKeyRef.Key)
+}
+
+func TestOpenTelemetryTraitInvalidConfiguration(t *testing.T) {
+	e := createEnvironment(t, v1.IntegrationPhaseDeploying)
+	otel := newTestOpenTelemetryTrait(t)
+	otel.Protocol = "http/json"
+
+	_, err := otel.Configure(e)
+	assert.NotNil(t, err)
+
+	otel = newTestOpenTelemetryTrait(t)
+	otel.HeadersSecret = "otlp-credentials/"
+
+	_, err = otel.Configure(e)
+	assert.NotNil(t, err)
+}
+
+func newTestOpenTelemetryTrait(t *testing.T, objects ...runtime.Object) *openTelemetryTrait {
+	t.Helper()
+
+	client, err := test.NewFakeClient(objects...)
+	assert.Nil(t, err)
+
+	otel, ok := NewOpenTelemetryTrait().(*openTelemetryTrait)
+	assert.True(t, ok)
+	otel.Enabled = pointer.Bool(true)
+	otel.InjectClient(client)
+
+	return otel
+}
+
+func createEnvironment(t *testing.T, phase v1.IntegrationPhase) *trait.Environment {
+	t.Helper()
+
+	catalog, err := camel.QuarkusCatalog()
+	assert.Nil(t, err)
+
+	return &trait.Environment{
+		Ctx:                   context.TODO(),
+		CamelCatalog:          catalog,
+		ApplicationProperties: make(map[string]string),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "test",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: phase,
+			},
+		},
+	}
+}
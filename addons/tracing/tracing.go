@@ -18,6 +18,8 @@ limitations under the License.
 package tracing
 
 import (
+	"errors"
+
 	"k8s.io/utils/pointer"
 
 	"github.com/apache/camel-k/addons/tracing/discovery"
@@ -31,7 +33,8 @@ import (
 //
 // The trait is able to automatically discover the tracing endpoint available in the namespace (supports **Jaeger**).
 //
-// The Tracing trait is disabled by default.
+// The Tracing trait is disabled by default. It cannot be enabled together with the `opentelemetry` trait,
+// that should be preferred, as OpenTracing is deprecated in favour of OpenTelemetry.
 //
 // +camel-k:trait=tracing.
 type tracingTrait struct {
@@ -82,6 +85,11 @@ func (t *tracingTrait) Configure(e *trait.Environment) (bool, error) {
 		return false, nil
 	}
 
+	// The opentelemetry trait has the same order, and is configured first
+	if e.GetTrait("opentelemetry") != nil {
+		return false, errors.New("the tracing and opentelemetry traits cannot be both enabled")
+	}
+
 	if pointer.BoolDeref(t.Auto, true) {
 		if t.Endpoint == "" {
 			for _, locator := range discovery.TracingLocators {
@@ -0,0 +1,194 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"fmt"
+	"time"
+
+	vpav1 "github.com/apache/camel-k/addons/vpa/duck/v1"
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// recommendationRefreshInterval is the delay after which the recommendation is reported again into the Integration status.
+const recommendationRefreshInterval = 5 * time.Minute
+
+// The VPA trait creates a https://github.com/kubernetes/autoscaler/tree/master/vertical-pod-autoscaler[Vertical Pod Autoscaler]
+// targeting the Integration Deployment, and reports the resources it recommends for the Integration container
+// into the Integration status, under `status.resourceRecommendation`.
+//
+// With the default `Off` update mode, the autoscaler only computes the recommendation, so that it can be used
+// to right-size the requests set with the `container.request-cpu` and `container.request-memory` options.
+// The other update modes let the autoscaler apply the recommended resources to the Integration Pods.
+//
+// The Vertical Pod Autoscaler must be installed in the cluster. The trait only supports the `deployment` controller strategy.
+//
+// The VPA trait is disabled by default.
+//
+// +camel-k:trait=vpa.
+type vpaTrait struct {
+	trait.BaseTrait `property:",squash"`
+	// Controls when the recommended resources are applied to the Integration Pods, one of `Off`, `Initial`, `Recreate` or `Auto` (default `Off`).
+	UpdateMode string `property:"update-mode" json:"updateMode,omitempty"`
+	// The lower bound of the CPU recommended for the Integration container.
+	MinAllowedCPU string `property:"min-allowed-cpu" json:"minAllowedCPU,omitempty"`
+	// The lower bound of the memory recommended for the Integration container.
+	MinAllowedMemory string `property:"min-allowed-memory" json:"minAllowedMemory,omitempty"`
+	// The upper bound of the CPU recommended for the Integration container.
+	MaxAllowedCPU string `property:"max-allowed-cpu" json:"maxAllowedCPU,omitempty"`
+	// The upper bound of the memory recommended for the Integration container.
+	MaxAllowedMemory string `property:"max-allowed-memory" json:"maxAllowedMemory,omitempty"`
+}
+
+// NewVpaTrait --.
+func NewVpaTrait() trait.Trait {
+	return &vpaTrait{
+		BaseTrait: trait.NewBaseTrait("vpa", trait.TraitOrderPostProcessResources),
+	}
+}
+
+func (t *vpaTrait) Configure(e *trait.Environment) (bool, error) {
+	if e.Integration == nil || !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if t.Enabled == nil || !*t.Enabled {
+		// Clear the recommendation reported while the trait was enabled
+		e.Integration.Status.ResourceRecommendation = nil
+		return false, nil
+	}
+
+	if _, err := t.updateMode(); err != nil {
+		return false, err
+	}
+
+	strategy, err := e.DetermineControllerStrategy()
+	if err != nil {
+		return false, err
+	}
+	if strategy != trait.ControllerStrategyDeployment {
+		t.L.ForIntegration(e.Integration).Infof("Vertical Pod Autoscaler not supported with %s controller strategy", strategy)
+		e.Integration.Status.ResourceRecommendation = nil
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (t *vpaTrait) Apply(e *trait.Environment) error {
+	mode, err := t.updateMode()
+	if err != nil {
+		return err
+	}
+
+	containerName := e.GetIntegrationContainerName()
+	policy := vpav1.ContainerResourcePolicy{
+		ContainerName:       containerName,
+		ControlledResources: &[]corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory},
+	}
+	if policy.MinAllowed, err = resourceList(t.MinAllowedCPU, t.MinAllowedMemory); err != nil {
+		return err
+	}
+	if policy.MaxAllowed, err = resourceList(t.MaxAllowedCPU, t.MaxAllowedMemory); err != nil {
+		return err
+	}
+
+	vpa := vpav1.NewVerticalPodAutoscaler(e.Integration.Namespace, e.Integration.Name)
+	vpa.Labels = map[string]string{
+		v1.IntegrationLabel: e.Integration.Name,
+	}
+	vpa.Spec = vpav1.VerticalPodAutoscalerSpec{
+		TargetRef: &autoscalingv1.CrossVersionObjectReference{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       e.Integration.Name,
+		},
+		UpdatePolicy: &vpav1.PodUpdatePolicy{
+			UpdateMode: &mode,
+		},
+		ResourcePolicy: &vpav1.PodResourcePolicy{
+			ContainerPolicies: []vpav1.ContainerResourcePolicy{policy},
+		},
+	}
+	e.Resources.Add(&vpa)
+
+	// Register a post action that reports the recommendation, once the deployer
+	// has updated the autoscaler with its actual state
+	e.PostActions = append(e.PostActions, func(env *trait.Environment) error {
+		reportRecommendation(env, &vpa, containerName)
+		return nil
+	})
+
+	// The recommendation is computed asynchronously, so make sure it gets reported regularly
+	e.RequeueNoLaterThan(recommendationRefreshInterval)
+
+	return nil
+}
+
+func (t *vpaTrait) updateMode() (vpav1.UpdateMode, error) {
+	switch mode := vpav1.UpdateMode(t.UpdateMode); mode {
+	case "":
+		return vpav1.UpdateModeOff, nil
+	case vpav1.UpdateModeOff, vpav1.UpdateModeInitial, vpav1.UpdateModeRecreate, vpav1.UpdateModeAuto:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported VPA update mode %q, must be one of %s, %s, %s or %s", t.UpdateMode,
+			vpav1.UpdateModeOff, vpav1.UpdateModeInitial, vpav1.UpdateModeRecreate, vpav1.UpdateModeAuto)
+	}
+}
+
+func resourceList(cpu, memory string) (corev1.ResourceList, error) {
+	if cpu == "" && memory == "" {
+		return nil, nil
+	}
+	resources := make(corev1.ResourceList)
+	for name, value := range map[corev1.ResourceName]string{corev1.ResourceCPU: cpu, corev1.ResourceMemory: memory} {
+		if value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s quantity %q: %w", name, value, err)
+		}
+		resources[name] = q
+	}
+	return resources, nil
+}
+
+func reportRecommendation(e *trait.Environment, vpa *vpav1.VerticalPodAutoscaler, containerName string) {
+	if vpa.Status.Recommendation == nil {
+		// Keep the last reported recommendation until the autoscaler computes a new one
+		return
+	}
+	for _, r := range vpa.Status.Recommendation.ContainerRecommendations {
+		if r.ContainerName != containerName {
+			continue
+		}
+		e.Integration.Status.ResourceRecommendation = &v1.ResourceRecommendation{
+			Target:     r.Target.DeepCopy(),
+			LowerBound: r.LowerBound.DeepCopy(),
+			UpperBound: r.UpperBound.DeepCopy(),
+		}
+		return
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vpa
+
+import (
+	"context"
+	"testing"
+
+	vpav1 "github.com/apache/camel-k/addons/vpa/duck/v1"
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testingTrue = true
+
+func TestVpaDisabledByDefault(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	env := createBasicTestEnvironment()
+	env.Integration.Status.ResourceRecommendation = &v1.ResourceRecommendation{}
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.False(t, res)
+	assert.Nil(t, env.Integration.Status.ResourceRecommendation)
+}
+
+func TestVpaDefaultUpdateMode(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	env := createBasicTestEnvironment()
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.True(t, res)
+	assert.NoError(t, vpa.Apply(env))
+
+	autoscaler := getVerticalPodAutoscaler(env)
+	assert.NotNil(t, autoscaler)
+	assert.Equal(t, "integration-name", autoscaler.Name)
+	assert.Equal(t, "Deployment", autoscaler.Spec.TargetRef.Kind)
+	assert.Equal(t, "apps/v1", autoscaler.Spec.TargetRef.APIVersion)
+	assert.Equal(t, "integration-name", autoscaler.Spec.TargetRef.Name)
+	assert.Equal(t, vpav1.UpdateModeOff, *autoscaler.Spec.UpdatePolicy.UpdateMode)
+	assert.Len(t, autoscaler.Spec.ResourcePolicy.ContainerPolicies, 1)
+	policy := autoscaler.Spec.ResourcePolicy.ContainerPolicies[0]
+	assert.Equal(t, "integration", policy.ContainerName)
+	assert.ElementsMatch(t, []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}, *policy.ControlledResources)
+	assert.Nil(t, policy.MinAllowed)
+	assert.Nil(t, policy.MaxAllowed)
+	assert.NotZero(t, env.RequeueAfter)
+}
+
+func TestVpaUpdateModeAndBounds(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	vpa.UpdateMode = "Auto"
+	vpa.MinAllowedCPU = "100m"
+	vpa.MaxAllowedMemory = "1Gi"
+	env := createBasicTestEnvironment()
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.True(t, res)
+	assert.NoError(t, vpa.Apply(env))
+
+	autoscaler := getVerticalPodAutoscaler(env)
+	assert.NotNil(t, autoscaler)
+	assert.Equal(t, vpav1.UpdateModeAuto, *autoscaler.Spec.UpdatePolicy.UpdateMode)
+	policy := autoscaler.Spec.ResourcePolicy.ContainerPolicies[0]
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}, policy.MinAllowed)
+	assert.Equal(t, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}, policy.MaxAllowed)
+}
+
+func TestVpaInvalidUpdateMode(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	vpa.UpdateMode = "Sometimes"
+	env := createBasicTestEnvironment()
+
+	res, err := vpa.Configure(env)
+	assert.Error(t, err)
+	assert.False(t, res)
+}
+
+func TestVpaInvalidBound(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	vpa.MaxAllowedCPU = "lots"
+	env := createBasicTestEnvironment()
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.True(t, res)
+	assert.Error(t, vpa.Apply(env))
+}
+
+func TestVpaReportRecommendation(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	env := createBasicTestEnvironment()
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.True(t, res)
+	assert.NoError(t, vpa.Apply(env))
+
+	// Simulate the autoscaler status returned when the deployer applies the resources
+	autoscaler := getVerticalPodAutoscaler(env)
+	assert.NotNil(t, autoscaler)
+	autoscaler.Status.Recommendation = &vpav1.RecommendedPodResources{
+		ContainerRecommendations: []vpav1.RecommendedContainerResources{
+			{
+				ContainerName: "sidecar",
+				Target:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+			},
+			{
+				ContainerName: "integration",
+				Target: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("250m"),
+					corev1.ResourceMemory: resource.MustParse("300Mi"),
+				},
+				LowerBound: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("200Mi"),
+				},
+				UpperBound: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+	for _, action := range env.PostActions {
+		assert.NoError(t, action(env))
+	}
+
+	recommendation := env.Integration.Status.ResourceRecommendation
+	assert.NotNil(t, recommendation)
+	assert.Equal(t, resource.MustParse("250m"), recommendation.Target[corev1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("300Mi"), recommendation.Target[corev1.ResourceMemory])
+	assert.Equal(t, resource.MustParse("100m"), recommendation.LowerBound[corev1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("1Gi"), recommendation.UpperBound[corev1.ResourceMemory])
+}
+
+func TestVpaKeepsRecommendationUntilComputed(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	env := createBasicTestEnvironment()
+	previous := &v1.ResourceRecommendation{
+		Target: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+	}
+	env.Integration.Status.ResourceRecommendation = previous
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.True(t, res)
+	assert.NoError(t, vpa.Apply(env))
+	for _, action := range env.PostActions {
+		assert.NoError(t, action(env))
+	}
+
+	assert.Equal(t, previous, env.Integration.Status.ResourceRecommendation)
+}
+
+func TestVpaNotSupportedWithKnativeService(t *testing.T) {
+	vpa, _ := NewVpaTrait().(*vpaTrait)
+	vpa.Enabled = &testingTrue
+	env := createBasicTestEnvironment()
+	env.ConfiguredTraits = append(env.ConfiguredTraits, &strategySelector{
+		Trait:    NewVpaTrait(),
+		strategy: trait.ControllerStrategyKnativeService,
+	})
+
+	res, err := vpa.Configure(env)
+	assert.NoError(t, err)
+	assert.False(t, res)
+	assert.Nil(t, getVerticalPodAutoscaler(env))
+}
+
+// strategySelector forces the controller strategy of the test environment.
+type strategySelector struct {
+	trait.Trait
+	strategy trait.ControllerStrategy
+}
+
+func (s *strategySelector) SelectControllerStrategy(*trait.Environment) (*trait.ControllerStrategy, error) {
+	return &s.strategy, nil
+}
+
+func (s *strategySelector) ControllerStrategySelectorOrder() int {
+	return 0
+}
+
+func getVerticalPodAutoscaler(e *trait.Environment) *vpav1.VerticalPodAutoscaler {
+	var res *vpav1.VerticalPodAutoscaler
+	for _, o := range e.Resources.Items() {
+		if vpa, ok := o.(*vpav1.VerticalPodAutoscaler); ok {
+			if res != nil {
+				panic("multiple VerticalPodAutoscalers found in env")
+			}
+			res = vpa
+		}
+	}
+	return res
+}
+
+func createBasicTestEnvironment() *trait.Environment {
+	return &trait.Environment{
+		Catalog: trait.NewCatalog(nil),
+		Ctx:     context.Background(),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      "integration-name",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		CamelCatalog: &camel.RuntimeCatalog{
+			CamelCatalogSpec: v1.CamelCatalogSpec{
+				Runtime: v1.RuntimeSpec{
+					Version:  "0.0.1",
+					Provider: v1.RuntimeProviderQuarkus,
+				},
+			},
+		},
+		Resources:             kubernetes.NewCollection(),
+		ApplicationProperties: make(map[string]string),
+	}
+}
@@ -49,7 +49,9 @@ const (
 	// camel-k-operator-custom-resource-definitions
 	// camel-k-operator-bind-addressable-resolver
 	// camel-k-operator-local-registry
-	ExpKubeClusterRoles = 4
+	// camel-k-operator-upload
+	// camel-k-operator-namespaces
+	ExpKubeClusterRoles = 6
 
 	// camel-k-operator-openshift
 	ExpOSPromoteRoles = 1
@@ -19,9 +19,11 @@ package apis
 
 import (
 	serving "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 )
 
 func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
 	AddToSchemes = append(AddToSchemes, serving.AddToScheme)
+	AddToSchemes = append(AddToSchemes, servingv1beta1.AddToScheme)
 }
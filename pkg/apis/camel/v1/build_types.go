@@ -37,6 +37,13 @@ type BuildSpec struct {
 	// and its phase set to BuildPhaseFailed.
 	// +kubebuilder:validation:Format=duration
 	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// The HTTP proxy the Build tasks connect through, e.g., to resolve the Maven artifacts, or to publish the image.
+	// The proxy environment variables of the operator are used if not set.
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+	// The labels selecting the nodes the builder pod is scheduled onto, when using the `pod` strategy.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// The tolerations of the builder pod, when using the `pod` strategy.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
 // Task represents the abstract task. Only one of the task should be configured to represent the specific task chosen.
@@ -51,6 +58,8 @@ type Task struct {
 	Spectrum *SpectrumTask `json:"spectrum,omitempty"`
 	// a S2iTask, for S2I strategy
 	S2i *S2iTask `json:"s2i,omitempty"`
+	// an ExportTask, to export the published image as an OCI archive
+	Export *ExportTask `json:"export,omitempty"`
 }
 
 // BaseTask is a base for the struct hierarchy
@@ -79,6 +88,10 @@ type BuilderTask struct {
 	Maven MavenBuildSpec `json:"maven,omitempty"`
 	// workspace directory to use
 	BuildDir string `json:"buildDir,omitempty"`
+	// the scan of the dependencies against a vulnerability database
+	VulnerabilityScan *VulnerabilityScanSpec `json:"vulnerabilityScan,omitempty"`
+	// the compute resources of the builder container, when using the `pod` strategy
+	ContainerResources corev1.ResourceRequirements `json:"containerResources,omitempty"`
 }
 
 // MavenBuildSpec defines the Maven configuration plus additional repositories to use
@@ -146,6 +159,35 @@ type S2iTask struct {
 	Tag string `json:"tag,omitempty"`
 }
 
+// ExportTask is used to export the published image as an OCI image layout archive into a persistent volume
+type ExportTask struct {
+	BaseTask `json:",inline"`
+	// the image to export, when not reported by the previous tasks
+	Image string `json:"image,omitempty"`
+	// the registry the image is pulled from
+	Registry RegistrySpec `json:"registry,omitempty"`
+	// the Persistent Volume Claim the archive is written into
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+	// the path of the archive, relative to the volume root
+	Path string `json:"path,omitempty"`
+}
+
+// ImageExportStatus describes the OCI archive an image has been exported into
+type ImageExportStatus struct {
+	// the Persistent Volume Claim holding the archive
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+	// the path of the archive, relative to the volume root
+	Path string `json:"path,omitempty"`
+	// the image exported into the archive
+	Image string `json:"image,omitempty"`
+	// the digest of the image manifest, that is preserved when the archive is imported into another registry
+	Digest string `json:"digest,omitempty"`
+	// the SHA-256 digest of the archive file, to verify its integrity once transferred
+	ArchiveDigest string `json:"archiveDigest,omitempty"`
+	// the size of the archive file, in bytes
+	Size int64 `json:"size,omitempty"`
+}
+
 // BuildStatus defines the observed state of Build
 type BuildStatus struct {
 	// ObservedGeneration is the most recent generation observed for this Build.
@@ -160,12 +202,20 @@ type BuildStatus struct {
 	BaseImage string `json:"baseImage,omitempty"`
 	// a list of artifacts contained in the build
 	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// the vulnerabilities found in the artifacts (if scanned)
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+	// the OCI archive the image has been exported into (if exported)
+	Export *ImageExportStatus `json:"export,omitempty"`
 	// the error description (if any)
 	Error string `json:"error,omitempty"`
 	// the reason of the failure (if any)
 	Failure *Failure `json:"failure,omitempty"`
 	// the time when it started
 	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// the stages the build went through, with their timings
+	Stages []BuildStage `json:"stages,omitempty"`
+	// the name of the builder pod, when using the `pod` strategy
+	Pod string `json:"pod,omitempty"`
 	// a list of conditions occurred during the build
 	Conditions []BuildCondition `json:"conditions,omitempty"`
 	// how long it took for the build
@@ -174,6 +224,23 @@ type BuildStatus struct {
 	Duration string `json:"duration,omitempty"`
 }
 
+// BuildStage records the execution of a Build stage, that is either a group of steps of the builder task,
+// e.g., the dependency resolution, or one of the other tasks, e.g., the image build and push
+type BuildStage struct {
+	// the name of the stage
+	Name string `json:"name"`
+	// the name of the task the stage belongs to
+	Task string `json:"task,omitempty"`
+	// the time when the stage started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// how long it took for the stage
+	Duration string `json:"duration,omitempty"`
+	// whether the stage reused the output of a previous build (if known)
+	CacheHit *bool `json:"cacheHit,omitempty"`
+	// additional information about the stage, e.g., the cache that has been used
+	Message string `json:"message,omitempty"`
+}
+
 // BuildPhase --
 type BuildPhase string
 
@@ -27,10 +27,21 @@ const (
 	TraitAnnotationPrefix = "trait.camel.apache.org/"
 	// OperatorIDAnnotation operator id annotation label
 	OperatorIDAnnotation = "camel.apache.org/operator.id"
+	// OperatorDefaultPriorityAnnotation platform annotation electing its operator as the default one, with the given priority
+	OperatorDefaultPriorityAnnotation = "camel.apache.org/operator.default-priority"
 	// SecondaryPlatformAnnotation secondary platform annotation label
 	SecondaryPlatformAnnotation = "camel.apache.org/secondary.platform"
 	// PlatformSelectorAnnotation platform id annotation label
 	PlatformSelectorAnnotation = "camel.apache.org/platform.id"
+	// EnvironmentProfileAnnotation selects the environment profile of an Integration
+	EnvironmentProfileAnnotation = "camel.apache.org/environment-profile"
+	// ChecksumAnnotation includes, or excludes, a mounted configmap or secret from the checksum of the Integration Pods
+	ChecksumAnnotation = "camel.apache.org/checksum"
+	// ReconcileAnnotation set to `paused` on an Integration, or on an IntegrationPlatform for all its Integrations,
+	// stops the operator from mutating the resources owned by the Integrations, while their status is still updated
+	ReconcileAnnotation = "camel.apache.org/reconcile"
+	// ReconcilePaused is the value of the reconcile annotation pausing the reconciliation
+	ReconcilePaused = "paused"
 )
 
 // BuildStrategy specifies how the Build should be executed.
@@ -80,6 +91,56 @@ type Artifact struct {
 	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
 }
 
+// VulnerabilityScanSpec configures the scan of the dependencies of the builds against a vulnerability database
+type VulnerabilityScanSpec struct {
+	// the URL of the OSV compatible vulnerability database API, e.g., an offline mirror (default `https://api.osv.dev`)
+	Database string `json:"database,omitempty"`
+	// the severity from which the vulnerabilities found fail the build (none by default)
+	// +kubebuilder:validation:Enum=Critical;High;Moderate;Low
+	FailThreshold VulnerabilitySeverity `json:"failThreshold,omitempty"`
+}
+
+// VulnerabilitySeverity is the severity of a vulnerability
+type VulnerabilitySeverity string
+
+const (
+	// VulnerabilitySeverityCritical --
+	VulnerabilitySeverityCritical VulnerabilitySeverity = "Critical"
+	// VulnerabilitySeverityHigh --
+	VulnerabilitySeverityHigh VulnerabilitySeverity = "High"
+	// VulnerabilitySeverityModerate --
+	VulnerabilitySeverityModerate VulnerabilitySeverity = "Moderate"
+	// VulnerabilitySeverityLow --
+	VulnerabilitySeverityLow VulnerabilitySeverity = "Low"
+	// VulnerabilitySeverityUnknown identifies the vulnerabilities the database does not rate
+	VulnerabilitySeverityUnknown VulnerabilitySeverity = "Unknown"
+)
+
+// VulnerabilitySeverities is the list of the severities, from the most to the least severe
+var VulnerabilitySeverities = []VulnerabilitySeverity{
+	VulnerabilitySeverityCritical,
+	VulnerabilitySeverityHigh,
+	VulnerabilitySeverityModerate,
+	VulnerabilitySeverityLow,
+	VulnerabilitySeverityUnknown,
+}
+
+// VulnerabilitySummary reports the number of vulnerabilities found in the dependencies, per severity
+type VulnerabilitySummary struct {
+	// the number of critical vulnerabilities
+	Critical int `json:"critical"`
+	// the number of high vulnerabilities
+	High int `json:"high"`
+	// the number of moderate vulnerabilities
+	Moderate int `json:"moderate"`
+	// the number of low vulnerabilities
+	Low int `json:"low"`
+	// the number of vulnerabilities with an unknown severity
+	Unknown int `json:"unknown"`
+	// the vulnerability database the dependencies have been checked against
+	Database string `json:"database,omitempty"`
+}
+
 // Failure represent a message specifying the reason and the time of an event failure
 type Failure struct {
 	// a short text specifying the reason
@@ -143,6 +204,16 @@ type RegistrySpec struct {
 	Organization string `json:"organization,omitempty"`
 }
 
+// ProxySpec provides the configuration of the HTTP proxy
+type ProxySpec struct {
+	// the proxy URL of the HTTP requests, e.g., `http://proxy.example.com:3128`
+	HTTPProxy string `json:"httpProxy,omitempty"`
+	// the proxy URL of the HTTPS requests
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	// the comma separated list of the hosts, domains, IP addresses or CIDRs that are accessed directly
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
 // ValueSource --
 type ValueSource struct {
 	// Selects a key of a ConfigMap.
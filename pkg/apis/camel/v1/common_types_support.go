@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 )
 
 func (in *Artifact) String() string {
@@ -40,6 +41,54 @@ func (in *ConfigurationSpec) String() string {
 	return fmt.Sprintf("%s=%s", in.Type, in.Value)
 }
 
+// Add accounts for a vulnerability of the given severity.
+func (in *VulnerabilitySummary) Add(severity VulnerabilitySeverity) {
+	switch severity {
+	case VulnerabilitySeverityCritical:
+		in.Critical++
+	case VulnerabilitySeverityHigh:
+		in.High++
+	case VulnerabilitySeverityModerate:
+		in.Moderate++
+	case VulnerabilitySeverityLow:
+		in.Low++
+	default:
+		in.Unknown++
+	}
+}
+
+// Count returns the number of vulnerabilities of the given severity.
+func (in *VulnerabilitySummary) Count(severity VulnerabilitySeverity) int {
+	switch severity {
+	case VulnerabilitySeverityCritical:
+		return in.Critical
+	case VulnerabilitySeverityHigh:
+		return in.High
+	case VulnerabilitySeverityModerate:
+		return in.Moderate
+	case VulnerabilitySeverityLow:
+		return in.Low
+	default:
+		return in.Unknown
+	}
+}
+
+// CountFrom returns the number of vulnerabilities of the given severity or more severe.
+func (in *VulnerabilitySummary) CountFrom(threshold VulnerabilitySeverity) int {
+	count := 0
+	for _, severity := range VulnerabilitySeverities {
+		count += in.Count(severity)
+		if severity == threshold {
+			break
+		}
+	}
+	return count
+}
+
+func (in *VulnerabilitySummary) String() string {
+	return fmt.Sprintf("critical=%d, high=%d, moderate=%d, low=%d, unknown=%d", in.Critical, in.High, in.Moderate, in.Low, in.Unknown)
+}
+
 func (in *RuntimeSpec) CapabilityDependencies(capability string) []MavenArtifact {
 	deps := make([]MavenArtifact, 0)
 
@@ -50,6 +99,39 @@ func (in *RuntimeSpec) CapabilityDependencies(capability string) []MavenArtifact
 	return deps
 }
 
+// ResolveCapabilities returns the capabilities whose dependencies are all included into the given dependencies,
+// along with the dependencies they have been resolved to, sorted by capability name.
+func (in *RuntimeSpec) ResolveCapabilities(dependencies []string) []CapabilityMapping {
+	available := make(map[string]bool, len(dependencies))
+	for _, d := range dependencies {
+		available[d] = true
+	}
+
+	mappings := make([]CapabilityMapping, 0)
+	for name, capability := range in.Capabilities {
+		if len(capability.Dependencies) == 0 {
+			continue
+		}
+		resolved := make([]string, 0, len(capability.Dependencies))
+		for _, artifact := range capability.Dependencies {
+			if id := artifact.GetDependencyID(); available[id] {
+				resolved = append(resolved, id)
+			}
+		}
+		if len(resolved) == len(capability.Dependencies) {
+			mappings = append(mappings, CapabilityMapping{
+				Name:         name,
+				Dependencies: resolved,
+			})
+		}
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].Name < mappings[j].Name
+	})
+
+	return mappings
+}
+
 // MarshalJSON returns m as the JSON encoding of m.
 func (m RawMessage) MarshalJSON() ([]byte, error) {
 	if m == nil {
@@ -63,6 +63,9 @@ type IntegrationSpec struct {
 	Resources []ResourceSpec `json:"resources,omitempty"`
 	// the reference of the `IntegrationKit` which is used for this Integration
 	IntegrationKit *corev1.ObjectReference `json:"integrationKit,omitempty"`
+	// the image digest of the `IntegrationKit` which is used for this Integration, e.g. `sha256:...`,
+	// looked up in the namespace of the `integrationKit` reference, if any, or of the platform
+	IntegrationKitDigest string `json:"integrationKitDigest,omitempty"`
 	// the list of Camel or Maven dependencies required by the Integration
 	Dependencies []string `json:"dependencies,omitempty"`
 	// the profile needed to run this Integration
@@ -81,6 +84,20 @@ type IntegrationSpec struct {
 	Repositories []string `json:"repositories,omitempty"`
 	// custom SA to use for the Integration
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// named sets of configuration and traits, one of which can be activated per environment,
+	// using the `camel.apache.org/environment-profile` annotation or the platform default environment profile
+	Profiles []EnvironmentProfileSpec `json:"profiles,omitempty"`
+}
+
+// EnvironmentProfileSpec is a named set of configuration and traits, that overrides the ones of the Integration
+// when the profile is active
+type EnvironmentProfileSpec struct {
+	// the name of the profile, e.g., dev, stage or prod
+	Name string `json:"name"`
+	// the configuration properties set when the profile is active
+	Configuration []ConfigurationSpec `json:"configuration,omitempty"`
+	// the traits configured when the profile is active
+	Traits map[string]TraitSpec `json:"traits,omitempty"`
 }
 
 // IntegrationStatus defines the observed state of Integration
@@ -99,10 +116,14 @@ type IntegrationStatus struct {
 	Profile TraitProfile `json:"profile,omitempty"`
 	// the reference of the `IntegrationKit` which is used for this Integration
 	IntegrationKit *corev1.ObjectReference `json:"integrationKit,omitempty"`
+	// the vulnerabilities found in the dependencies of the kit (if scanned)
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
 	// The IntegrationPlatform watching this Integration
 	Platform string `json:"platform,omitempty"`
 	// a list of sources generated for this Integration
 	GeneratedSources []SourceSpec `json:"generatedSources,omitempty"`
+	// the Kamelets used by this Integration, at the generation it has been initialized with
+	Kamelets []IntegrationKamelet `json:"kamelets,omitempty"`
 	// Deprecated:
 	// a list of resources generated for this Integration
 	GeneratedResources []ResourceSpec `json:"generatedResources,omitempty"`
@@ -125,6 +146,22 @@ type IntegrationStatus struct {
 	Capabilities []string `json:"capabilities,omitempty"`
 	// the timestamp representing the last time when this integration was initialized.
 	InitializationTimestamp *metav1.Time `json:"lastInitTimestamp,omitempty"`
+	// the resource usage observed for the Integration container, and the resources recommended from it (if profiled)
+	ResourceProfile *ResourceProfile `json:"resourceProfile,omitempty"`
+	// the resources recommended for the Integration container by the Vertical Pod Autoscaler (if enabled)
+	ResourceRecommendation *ResourceRecommendation `json:"resourceRecommendation,omitempty"`
+	// the progress of the canary rollout of the Integration revisions (if enabled)
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+// IntegrationKamelet references a Kamelet used by an Integration
+type IntegrationKamelet struct {
+	// the namespace of the Kamelet
+	Namespace string `json:"namespace"`
+	// the name of the Kamelet
+	Name string `json:"name"`
+	// the generation of the Kamelet
+	Generation int64 `json:"generation"`
 }
 
 // +kubebuilder:object:root=true
@@ -146,6 +183,15 @@ const (
 	// IntegrationKind --
 	IntegrationKind string = "Integration"
 
+	// IntegrationPreviewLabel labels a preview Integration with the identifier of its preview, e.g., a pull request
+	IntegrationPreviewLabel = "camel.apache.org/preview"
+	// IntegrationPreviewExpirationAnnotation sets the time, in RFC 3339 format, after which a preview Integration is deleted
+	IntegrationPreviewExpirationAnnotation = "camel.apache.org/preview.expiration"
+	// IntegrationTemporaryLabel labels an Integration to be deleted once the temporary Integration TTL of its platform is elapsed
+	IntegrationTemporaryLabel = "camel.apache.org/temporary"
+	// IntegrationRolloutCanaryLabel labels the Pods of the Integration revision being rolled out
+	IntegrationRolloutCanaryLabel = "camel.apache.org/rollout.canary"
+
 	// IntegrationPhaseNone --
 	IntegrationPhaseNone IntegrationPhase = ""
 	// IntegrationPhaseInitialization --
@@ -158,9 +204,18 @@ const (
 	IntegrationPhaseDeploying IntegrationPhase = "Deploying"
 	// IntegrationPhaseRunning --
 	IntegrationPhaseRunning IntegrationPhase = "Running"
+	// IntegrationPhaseCompleted --
+	IntegrationPhaseCompleted IntegrationPhase = "Completed"
 	// IntegrationPhaseError --
 	IntegrationPhaseError IntegrationPhase = "Error"
 
+	// RolloutPhaseProgressing --
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePromoted --
+	RolloutPhasePromoted RolloutPhase = "Promoted"
+	// RolloutPhaseRolledBack --
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+
 	// IntegrationConditionKitAvailable --
 	IntegrationConditionKitAvailable IntegrationConditionType = "IntegrationKitAvailable"
 	// IntegrationConditionPlatformAvailable --
@@ -173,6 +228,8 @@ const (
 	IntegrationConditionKnativeServiceAvailable IntegrationConditionType = "KnativeServiceAvailable"
 	// IntegrationConditionCronJobAvailable --
 	IntegrationConditionCronJobAvailable IntegrationConditionType = "CronJobAvailable"
+	// IntegrationConditionJobAvailable --
+	IntegrationConditionJobAvailable IntegrationConditionType = "JobAvailable"
 	// IntegrationConditionExposureAvailable --
 	IntegrationConditionExposureAvailable IntegrationConditionType = "ExposureAvailable"
 	// IntegrationConditionPrometheusAvailable --
@@ -183,11 +240,27 @@ const (
 	IntegrationConditionProbesAvailable IntegrationConditionType = "ProbesAvailable"
 	// IntegrationConditionReady --
 	IntegrationConditionReady IntegrationConditionType = "Ready"
+	// IntegrationConditionSmokeTestPassed --
+	IntegrationConditionSmokeTestPassed IntegrationConditionType = "SmokeTestPassed"
+	// IntegrationConditionReconcilePaused --
+	IntegrationConditionReconcilePaused IntegrationConditionType = "ReconcilePaused"
+	// IntegrationConditionSecretsScanPassed --
+	IntegrationConditionSecretsScanPassed IntegrationConditionType = "SecretsScanPassed"
+	// IntegrationConditionResourceRecommendationAvailable --
+	IntegrationConditionResourceRecommendationAvailable IntegrationConditionType = "ResourceRecommendationAvailable"
+	// IntegrationConditionDeploymentProgressing reports the progress of the Integration Deployment rollout
+	IntegrationConditionDeploymentProgressing IntegrationConditionType = "DeploymentProgressing"
+	// IntegrationConditionTraitOverrides reports the trait properties overridden by the Integration annotations
+	IntegrationConditionTraitOverrides IntegrationConditionType = "TraitOverrides"
+	// IntegrationConditionRestartBudgetExceeded reports the Integration Pods crash-loop beyond the restart budget
+	IntegrationConditionRestartBudgetExceeded IntegrationConditionType = "RestartBudgetExceeded"
 
 	// IntegrationConditionKitAvailableReason --
 	IntegrationConditionKitAvailableReason string = "IntegrationKitAvailable"
 	// IntegrationConditionPlatformAvailableReason --
 	IntegrationConditionPlatformAvailableReason string = "IntegrationPlatformAvailable"
+	// IntegrationConditionTraitOverridesReason --
+	IntegrationConditionTraitOverridesReason string = "TraitAnnotations"
 	// IntegrationConditionDeploymentAvailableReason --
 	IntegrationConditionDeploymentAvailableReason string = "DeploymentAvailable"
 	// IntegrationConditionDeploymentNotAvailableReason --
@@ -206,6 +279,10 @@ const (
 	IntegrationConditionIngressAvailableReason string = "IngressAvailable"
 	// IntegrationConditionIngressNotAvailableReason --
 	IntegrationConditionIngressNotAvailableReason string = "IngressNotAvailable"
+	// IntegrationConditionGatewayAPIAvailableReason --
+	IntegrationConditionGatewayAPIAvailableReason string = "GatewayAPIAvailable"
+	// IntegrationConditionGatewayAPINotAvailableReason --
+	IntegrationConditionGatewayAPINotAvailableReason string = "GatewayAPINotAvailable"
 	// IntegrationConditionKnativeServiceAvailableReason --
 	IntegrationConditionKnativeServiceAvailableReason string = "KnativeServiceAvailable"
 	// IntegrationConditionKnativeServiceNotAvailableReason --
@@ -214,6 +291,10 @@ const (
 	IntegrationConditionCronJobAvailableReason string = "CronJobAvailableReason"
 	// IntegrationConditionCronJobNotAvailableReason --
 	IntegrationConditionCronJobNotAvailableReason string = "CronJobNotAvailableReason"
+	// IntegrationConditionJobAvailableReason --
+	IntegrationConditionJobAvailableReason string = "JobAvailable"
+	// IntegrationConditionJobNotAvailableReason --
+	IntegrationConditionJobNotAvailableReason string = "JobNotAvailable"
 	// IntegrationConditionPrometheusAvailableReason --
 	IntegrationConditionPrometheusAvailableReason string = "PrometheusAvailable"
 	// IntegrationConditionJolokiaAvailableReason --
@@ -227,6 +308,10 @@ const (
 	IntegrationConditionDeploymentReadyReason string = "DeploymentReady"
 	// IntegrationConditionDeploymentProgressingReason --
 	IntegrationConditionDeploymentProgressingReason string = "DeploymentProgressing"
+	// IntegrationConditionStatefulSetReadyReason --
+	IntegrationConditionStatefulSetReadyReason string = "StatefulSetReady"
+	// IntegrationConditionStatefulSetProgressingReason --
+	IntegrationConditionStatefulSetProgressingReason string = "StatefulSetProgressing"
 	// IntegrationConditionCronJobCreatedReason --
 	IntegrationConditionCronJobCreatedReason string = "CronJobCreated"
 	// IntegrationConditionCronJobActiveReason --
@@ -235,13 +320,63 @@ const (
 	IntegrationConditionLastJobSucceededReason string = "LastJobSucceeded"
 	// IntegrationConditionLastJobFailedReason --
 	IntegrationConditionLastJobFailedReason string = "LastJobFailed"
+	// IntegrationConditionJobActiveReason --
+	IntegrationConditionJobActiveReason string = "JobActive"
+	// IntegrationConditionJobCompletedReason --
+	IntegrationConditionJobCompletedReason string = "JobCompleted"
+	// IntegrationConditionJobFailedReason --
+	IntegrationConditionJobFailedReason string = "JobFailed"
 	// IntegrationConditionRuntimeNotReadyReason --
 	IntegrationConditionRuntimeNotReadyReason string = "RuntimeNotReady"
+	// IntegrationConditionRouteNotReadyReason --
+	IntegrationConditionRouteNotReadyReason string = "RouteNotReady"
 	// IntegrationConditionErrorReason --
 	IntegrationConditionErrorReason string = "Error"
+	// IntegrationConditionSmokeTestPassedReason --
+	IntegrationConditionSmokeTestPassedReason string = "SmokeTestPassed"
+	// IntegrationConditionSmokeTestFailedReason --
+	IntegrationConditionSmokeTestFailedReason string = "SmokeTestFailed"
+	// IntegrationConditionSmokeTestRolledBackReason --
+	IntegrationConditionSmokeTestRolledBackReason string = "RolledBack"
+	// IntegrationConditionRestartBudgetExceededReason --
+	IntegrationConditionRestartBudgetExceededReason string = "CrashLooping"
+	// IntegrationConditionRestartBudgetScaledToZeroReason --
+	IntegrationConditionRestartBudgetScaledToZeroReason string = "ScaledToZero"
+	// IntegrationConditionReconcilePausedReason --
+	IntegrationConditionReconcilePausedReason string = "ReconcilePaused"
+	// IntegrationConditionSecretsScanPassedReason --
+	IntegrationConditionSecretsScanPassedReason string = "SecretsScanPassed"
+	// IntegrationConditionInlineCredentialsReason is used when credentials are hard-coded into the Integration sources
+	IntegrationConditionInlineCredentialsReason string = "InlineCredentials"
+	// IntegrationConditionResourceRecommendationAvailableReason --
+	IntegrationConditionResourceRecommendationAvailableReason string = "ResourceRecommendationAvailable"
+	// IntegrationConditionResourceProfilingReason --
+	IntegrationConditionResourceProfilingReason string = "ResourceProfiling"
+	// IntegrationConditionResourceMetricsNotAvailableReason --
+	IntegrationConditionResourceMetricsNotAvailableReason string = "ResourceMetricsNotAvailable"
+	// IntegrationConditionDeploymentRolloutCompleteReason --
+	IntegrationConditionDeploymentRolloutCompleteReason string = "RolloutComplete"
+	// IntegrationConditionDeploymentRolloutProgressingReason --
+	IntegrationConditionDeploymentRolloutProgressingReason string = "RolloutProgressing"
+	// IntegrationConditionDeploymentProgressDeadlineExceededReason --
+	IntegrationConditionDeploymentProgressDeadlineExceededReason string = "ProgressDeadlineExceeded"
+	// IntegrationConditionDeploymentQuotaExceededReason is used when the Pods cannot be created because of a resource quota
+	IntegrationConditionDeploymentQuotaExceededReason string = "QuotaExceeded"
+	// IntegrationConditionDeploymentReplicaFailureReason is used when the Pods cannot be created, e.g., because of an admission policy
+	IntegrationConditionDeploymentReplicaFailureReason string = "ReplicaFailure"
+	// IntegrationConditionDeploymentUnschedulableReason --
+	IntegrationConditionDeploymentUnschedulableReason string = "Unschedulable"
+	// IntegrationConditionDeploymentImagePullFailedReason --
+	IntegrationConditionDeploymentImagePullFailedReason string = "ImagePullFailed"
+	// IntegrationConditionDeploymentContainerConfigErrorReason is used when the containers cannot be created, e.g., because of a missing Secret
+	IntegrationConditionDeploymentContainerConfigErrorReason string = "ContainerConfigError"
+	// IntegrationConditionDeploymentCrashLoopingReason --
+	IntegrationConditionDeploymentCrashLoopingReason string = "CrashLooping"
 
 	// IntegrationConditionUnsupportedLanguageReason --
 	IntegrationConditionUnsupportedLanguageReason string = "UnsupportedLanguage"
+	// IntegrationConditionUnsupportedDependencyReason --
+	IntegrationConditionUnsupportedDependencyReason string = "UnsupportedDependency"
 
 	// IntegrationConditionKameletsAvailable --
 	IntegrationConditionKameletsAvailable IntegrationConditionType = "KameletsAvailable"
@@ -249,6 +384,21 @@ const (
 	IntegrationConditionKameletsAvailableReason string = "KameletsAvailable"
 	// IntegrationConditionKameletsNotAvailableReason --
 	IntegrationConditionKameletsNotAvailableReason string = "KameletsNotAvailable"
+	// IntegrationConditionKameletsUpToDate --
+	IntegrationConditionKameletsUpToDate IntegrationConditionType = "KameletsUpToDate"
+	// IntegrationConditionKameletsUpdatedReason is used when the Integration is rebuilt, as the Kamelets it uses have been updated
+	IntegrationConditionKameletsUpdatedReason string = "KameletsUpdated"
+	// IntegrationConditionKameletsUpdatePendingReason is used when the Integration must be rebuilt for the Kamelets update to roll out
+	IntegrationConditionKameletsUpdatePendingReason string = "KameletsUpdatePending"
+
+	// IntegrationConditionDependenciesReachable --
+	IntegrationConditionDependenciesReachable IntegrationConditionType = "DependenciesReachable"
+	// IntegrationConditionDependenciesReachableReason --
+	IntegrationConditionDependenciesReachableReason string = "DependenciesReachable"
+	// IntegrationConditionDependenciesWaitingReason is used while the Integration Pods wait for their dependencies to be reachable
+	IntegrationConditionDependenciesWaitingReason string = "WaitingForDependencies"
+	// IntegrationConditionDependenciesUnreachableReason is used when the dependencies are still unreachable after the timeout
+	IntegrationConditionDependenciesUnreachableReason string = "DependenciesUnreachable"
 )
 
 // IntegrationCondition describes the state of a resource at a certain point.
@@ -269,6 +419,65 @@ type IntegrationCondition struct {
 	Message string `json:"message,omitempty"`
 }
 
+// ResourceProfile aggregates the resource usage of the Integration container, sampled over an observation window
+type ResourceProfile struct {
+	// the start of the current observation window
+	WindowStart metav1.Time `json:"windowStart,omitempty"`
+	// the time of the last sample
+	LastSampleTime metav1.Time `json:"lastSampleTime,omitempty"`
+	// the number of samples taken during the current observation window
+	Samples int `json:"samples,omitempty"`
+	// the average usage, across the samples and the Pods, during the current observation window
+	Average corev1.ResourceList `json:"average,omitempty"`
+	// the peak usage, across the samples and the Pods, during the current observation window
+	Peak corev1.ResourceList `json:"peak,omitempty"`
+	// the requests and limits recommended from the last complete observation window
+	Recommendation *corev1.ResourceRequirements `json:"recommendation,omitempty"`
+}
+
+// ResourceRecommendation reports the resources recommended by the Vertical Pod Autoscaler for the Integration container
+type ResourceRecommendation struct {
+	// the recommended requests
+	Target corev1.ResourceList `json:"target,omitempty"`
+	// the lowest requests recommended, below which the container is likely to be under-provisioned
+	LowerBound corev1.ResourceList `json:"lowerBound,omitempty"`
+	// the highest requests recommended, above which the container is likely to be over-provisioned
+	UpperBound corev1.ResourceList `json:"upperBound,omitempty"`
+}
+
+// RolloutStatus reports the progress of the canary rollout of a new revision of the Integration
+type RolloutStatus struct {
+	// the phase of the rollout
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// the digest of the revision receiving the traffic not routed to the canary
+	StableDigest string `json:"stableDigest,omitempty"`
+	// the digest of the revision being rolled out
+	CanaryDigest string `json:"canaryDigest,omitempty"`
+	// the Knative revision receiving the traffic not routed to the canary
+	StableRevision string `json:"stableRevision,omitempty"`
+	// the Knative revision being rolled out
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+	// the index of the current step
+	Step int32 `json:"step,omitempty"`
+	// the percentage of the traffic routed to the canary
+	Weight int32 `json:"weight,omitempty"`
+	// the time the current step started
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	// the number of canary Pods that are ready
+	CanaryReadyReplicas int32 `json:"canaryReadyReplicas,omitempty"`
+	// the number of restarts of the canary Integration containers
+	CanaryRestarts int32 `json:"canaryRestarts,omitempty"`
+	// the number of exchanges processed by the canary routes, when the rollout has a failure rate threshold
+	CanaryExchanges int64 `json:"canaryExchanges,omitempty"`
+	// the number of exchanges failed by the canary routes, when the rollout has a failure rate threshold
+	CanaryFailedExchanges int64 `json:"canaryFailedExchanges,omitempty"`
+	// a human-readable message indicating why the canary has been promoted or rolled back
+	Message string `json:"message,omitempty"`
+}
+
+// RolloutPhase --
+type RolloutPhase string
+
 // PodSpecTemplate represent a template used to deploy an Integration `Pod`
 type PodSpecTemplate struct {
 	// the specification
@@ -20,6 +20,7 @@ package v1
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +41,20 @@ func NewIntegration(namespace string, name string) Integration {
 	}
 }
 
+// GetPreviewExpiration returns the time after which the preview Integration is deleted,
+// or nil if the Integration is not a preview.
+func (in *Integration) GetPreviewExpiration() (*time.Time, error) {
+	value, ok := in.Annotations[IntegrationPreviewExpirationAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	expiration, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid preview expiration %q: %w", value, err)
+	}
+	return &expiration, nil
+}
+
 func NewIntegrationList() IntegrationList {
 	return IntegrationList{
 		TypeMeta: metav1.TypeMeta{
@@ -57,6 +72,8 @@ func (in *Integration) Initialize() {
 	in.Status = IntegrationStatus{
 		Phase:   IntegrationPhaseInitialization,
 		Profile: profile,
+		// The rollout tracks the revisions deployed across initializations
+		Rollout: in.Status.Rollout,
 	}
 }
 
@@ -210,6 +227,15 @@ func (in *IntegrationStatus) Configurations() []ConfigurationSpec {
 	return in.Configuration
 }
 
+// IsReconcilePaused returns whether the operator must refrain from mutating the resources owned by the Integration,
+// as requested with the `camel.apache.org/reconcile` annotation, either on the Integration or on its platform.
+func (in *Integration) IsReconcilePaused(platform *IntegrationPlatform) bool {
+	if in != nil && in.Annotations[ReconcileAnnotation] == ReconcilePaused {
+		return true
+	}
+	return platform != nil && platform.Annotations[ReconcileAnnotation] == ReconcilePaused
+}
+
 func (in *Integration) Configurations() []ConfigurationSpec {
 	if in == nil {
 		return []ConfigurationSpec{}
@@ -222,6 +248,46 @@ func (in *Integration) Configurations() []ConfigurationSpec {
 	return answer
 }
 
+// ActiveEnvironmentProfile returns the environment profile of the Integration that is activated, either by the
+// `camel.apache.org/environment-profile` annotation, or by the platform default environment profile.
+// It returns nil when no profile is active, and an error when the profile selected by the annotation is not defined.
+func (in *Integration) ActiveEnvironmentProfile(platform *IntegrationPlatform) (*EnvironmentProfileSpec, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	if name := in.Annotations[EnvironmentProfileAnnotation]; name != "" {
+		if profile := in.getEnvironmentProfile(name); profile != nil {
+			return profile, nil
+		}
+		return nil, fmt.Errorf("environment profile %s is not defined by integration %s", name, in.Name)
+	}
+
+	if platform != nil && platform.Status.EnvironmentProfile != "" {
+		// the Integration may not need to override anything for the platform default profile
+		return in.getEnvironmentProfile(platform.Status.EnvironmentProfile), nil
+	}
+
+	return nil, nil
+}
+
+func (in *Integration) getEnvironmentProfile(name string) *EnvironmentProfileSpec {
+	for i := range in.Spec.Profiles {
+		if in.Spec.Profiles[i].Name == name {
+			return &in.Spec.Profiles[i]
+		}
+	}
+	return nil
+}
+
+func (in *EnvironmentProfileSpec) Configurations() []ConfigurationSpec {
+	if in == nil {
+		return []ConfigurationSpec{}
+	}
+
+	return in.Configuration
+}
+
 func NewSourceSpec(name string, content string, language Language) SourceSpec {
 	return SourceSpec{
 		DataSpec: DataSpec{
@@ -293,6 +359,50 @@ func (in *Integration) SetIntegrationKit(kit *IntegrationKit) {
 		image = kit.Spec.Image
 	}
 	in.Status.Image = image
+	in.Status.Vulnerabilities = kit.Status.Vulnerabilities.DeepCopy()
+}
+
+// RecommendationString returns the recommended requests and limits, e.g., `requests: cpu=100m, memory=256Mi; limits: cpu=500m, memory=256Mi`.
+func (in *ResourceProfile) RecommendationString() string {
+	if in == nil || in.Recommendation == nil {
+		return ""
+	}
+	return fmt.Sprintf("requests: %s; limits: %s",
+		formatResourceList(in.Recommendation.Requests), formatResourceList(in.Recommendation.Limits))
+}
+
+// String returns the requests recommended by the autoscaler and their bounds,
+// e.g., `cpu=250m, memory=300Mi (lower bound: cpu=100m, memory=200Mi; upper bound: cpu=1, memory=1Gi)`.
+func (in *ResourceRecommendation) String() string {
+	if in == nil || len(in.Target) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s (lower bound: %s; upper bound: %s)",
+		formatResourceList(in.Target), formatResourceList(in.LowerBound), formatResourceList(in.UpperBound))
+}
+
+// String returns the progress of the rollout, e.g., `Progressing (step 2, 25% of the traffic to the canary)`.
+func (in *RolloutStatus) String() string {
+	if in == nil || in.Phase == "" {
+		return ""
+	}
+	if in.Phase == RolloutPhaseProgressing {
+		return fmt.Sprintf("%s (step %d, %d%% of the traffic to the canary)", in.Phase, in.Step+1, in.Weight)
+	}
+	if in.Message != "" {
+		return fmt.Sprintf("%s (%s)", in.Phase, in.Message)
+	}
+	return string(in.Phase)
+}
+
+func formatResourceList(resources corev1.ResourceList) string {
+	values := make([]string, 0, 2)
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if q, ok := resources[name]; ok {
+			values = append(values, fmt.Sprintf("%s=%s", name, q.String()))
+		}
+	}
+	return strings.Join(values, ", ")
 }
 
 func (in *Integration) GetIntegrationKitNamespace(p *IntegrationPlatform) string {
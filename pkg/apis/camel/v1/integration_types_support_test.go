@@ -98,3 +98,35 @@ func TestGetConfigurationProperty(t *testing.T) {
 	v6 := integration.GetConfigurationProperty("key6")
 	assert.Equal(t, "", v6)
 }
+
+func TestActiveEnvironmentProfile(t *testing.T) {
+	it := NewIntegration("ns", "it")
+	it.Spec.Profiles = []EnvironmentProfileSpec{
+		{Name: "dev"},
+		{Name: "prod"},
+	}
+	platform := NewIntegrationPlatform("ns", "camel-k")
+
+	profile, err := it.ActiveEnvironmentProfile(&platform)
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+
+	platform.Status.EnvironmentProfile = "prod"
+	profile, err = it.ActiveEnvironmentProfile(&platform)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", profile.Name)
+
+	platform.Status.EnvironmentProfile = "stage"
+	profile, err = it.ActiveEnvironmentProfile(&platform)
+	assert.NoError(t, err)
+	assert.Nil(t, profile)
+
+	it.Annotations = map[string]string{EnvironmentProfileAnnotation: "dev"}
+	profile, err = it.ActiveEnvironmentProfile(&platform)
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", profile.Name)
+
+	it.Annotations[EnvironmentProfileAnnotation] = "missing"
+	_, err = it.ActiveEnvironmentProfile(nil)
+	assert.Error(t, err)
+}
@@ -79,6 +79,12 @@ type IntegrationKitStatus struct {
 	Digest string `json:"digest,omitempty"`
 	// list of artifacts used by the kit
 	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// the dependencies the capabilities provided by the kit have been resolved to
+	Capabilities []CapabilityMapping `json:"capabilities,omitempty"`
+	// the vulnerabilities found in the artifacts (if scanned)
+	Vulnerabilities *VulnerabilitySummary `json:"vulnerabilities,omitempty"`
+	// the OCI archive the image has been exported into (if exported)
+	Export *ImageExportStatus `json:"export,omitempty"`
 	// failure reason (if any)
 	Failure *Failure `json:"failure,omitempty"`
 	// the runtime version for which this kit was configured
@@ -93,6 +99,14 @@ type IntegrationKitStatus struct {
 	Conditions []IntegrationKitCondition `json:"conditions,omitempty"`
 }
 
+// CapabilityMapping records the dependencies a capability has been resolved to
+type CapabilityMapping struct {
+	// the name of the capability, e.g., `rest`
+	Name string `json:"name"`
+	// the dependencies providing the capability
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // IntegrationKitList contains a list of IntegrationKit
@@ -130,9 +144,27 @@ const (
 	// IntegrationKitLayoutNative labels a kit using the Quarkus native packaging
 	IntegrationKitLayoutNative = "native"
 
+	// IntegrationKitAppCDSLabel labels a kit whose image embeds an AppCDS archive
+	IntegrationKitAppCDSLabel = "camel.apache.org/kit.appcds"
+
 	// IntegrationKitPriorityLabel labels the kit priority
 	IntegrationKitPriorityLabel = "camel.apache.org/kit.priority"
 
+	// IntegrationKitCredentialsNamespaceLabel labels a kit built with the Maven repository credentials of the given namespace,
+	// so that it is only reused by the Integrations of that namespace
+	IntegrationKitCredentialsNamespaceLabel = "camel.apache.org/kit.credentials.namespace"
+
+	// IntegrationKitPoolLabel labels a kit built ahead of time for the IntegrationPlatform kit pool
+	IntegrationKitPoolLabel = "camel.apache.org/kit.pool"
+
+	// IntegrationKitNameLabel labels a Build run into the build farm with the name of its kit
+	IntegrationKitNameLabel = "camel.apache.org/kit.name"
+	// IntegrationKitNamespaceLabel labels a Build run into the build farm with the namespace of its kit
+	IntegrationKitNamespaceLabel = "camel.apache.org/kit.namespace"
+
+	// IntegrationKitPrePullLabel labels the DaemonSet pre-pulling the image of a kit with the name of the kit
+	IntegrationKitPrePullLabel = "camel.apache.org/kit.prepull"
+
 	// IntegrationKitPhaseNone --
 	IntegrationKitPhaseNone IntegrationKitPhase = ""
 	// IntegrationKitPhaseInitialization --
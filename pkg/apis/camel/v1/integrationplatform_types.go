@@ -42,8 +42,66 @@ type IntegrationPlatformSpec struct {
 	Configuration []ConfigurationSpec `json:"configuration,omitempty"`
 	// configuration to be executed to all Kamelets controlled by this IntegrationPlatform
 	Kamelet IntegrationPlatformKameletSpec `json:"kamelet,omitempty"`
+	// the HTTP proxy that the builds, and the Integrations, controlled by this IntegrationPlatform connect through.
+	// It overrides the proxy environment variables of the operator.
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+	// the environment profile activated for the Integrations controlled by this IntegrationPlatform,
+	// unless they select one with the `camel.apache.org/environment-profile` annotation
+	EnvironmentProfile string `json:"environmentProfile,omitempty"`
+	// the automatic deletion of the temporary Integrations and of the failed Builds
+	Cleanup IntegrationPlatformCleanupSpec `json:"cleanup,omitempty"`
+	// the conventions used by the ingress and route traits to expose the Integrations controlled by this IntegrationPlatform
+	Exposure IntegrationPlatformExposureSpec `json:"exposure,omitempty"`
+	// the detection of the credentials hard-coded into the sources of the Integrations controlled by this IntegrationPlatform
+	SecretsScan IntegrationPlatformSecretsScanSpec `json:"secretsScan,omitempty"`
+	// the labels added by default to the Pods of the Integrations controlled by this IntegrationPlatform, e.g. for policy engines.
+	// They can be overridden with the `pod-labels` option of the container trait
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// the annotations added by default to the Pods of the Integrations controlled by this IntegrationPlatform, e.g. AppArmor profiles.
+	// They can be overridden with the `pod-annotations` option of the container trait
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
 }
 
+// IntegrationPlatformCleanupSpec configures the automatic deletion of the resources that are no longer needed
+type IntegrationPlatformCleanupSpec struct {
+	// how long the Integrations labelled with `camel.apache.org/temporary=true` are kept after their creation
+	TemporaryIntegrationTTL *metav1.Duration `json:"temporaryIntegrationTTL,omitempty"`
+	// how long the Builds in the Error phase are kept before being deleted. They are kept indefinitely if not set
+	FailedBuildRetention *metav1.Duration `json:"failedBuildRetention,omitempty"`
+}
+
+// IntegrationPlatformExposureSpec configures the hosts the Integrations are exposed with, when not set on the Integrations
+type IntegrationPlatformExposureSpec struct {
+	// the template of the host exposed for each Integration, e.g. `{name}.{namespace}.apps.example.com`.
+	// The `{name}` and `{namespace}` placeholders are replaced with the name and the namespace of the Integration
+	HostTemplate string `json:"hostTemplate,omitempty"`
+	// secures the exposed hosts with TLS, using a wildcard certificate matching the host template
+	TLS bool `json:"tls,omitempty"`
+	// the name of the Secret holding the wildcard certificate referenced by the Ingresses. It must be present in the namespace
+	// of the Integrations. The default certificate of the ingress controller, or of the OpenShift router, is used if not set
+	TLSSecret string `json:"tlsSecret,omitempty"`
+}
+
+// IntegrationPlatformSecretsScanSpec configures the detection of the credentials hard-coded into the Integration sources,
+// e.g., the passwords in the endpoint URIs or the AWS access keys, performed when the Integrations are initialized
+type IntegrationPlatformSecretsScanSpec struct {
+	// what to do when inline credentials are found (default `Warn`)
+	// +kubebuilder:validation:Enum=Ignore;Warn;Block
+	Policy SecretsScanPolicy `json:"policy,omitempty"`
+}
+
+// SecretsScanPolicy is the action taken when inline credentials are found in the Integration sources
+type SecretsScanPolicy string
+
+const (
+	// SecretsScanPolicyIgnore disables the scan
+	SecretsScanPolicyIgnore SecretsScanPolicy = "Ignore"
+	// SecretsScanPolicyWarn reports the credentials found with the SecretsScanPassed condition
+	SecretsScanPolicyWarn SecretsScanPolicy = "Warn"
+	// SecretsScanPolicyBlock fails the Integrations the credentials are found in
+	SecretsScanPolicyBlock SecretsScanPolicy = "Block"
+)
+
 // IntegrationPlatformResourcesSpec contains platform related resources.
 // Deprecated: not used
 type IntegrationPlatformResourcesSpec struct {
@@ -151,6 +209,68 @@ type IntegrationPlatformBuildSpec struct {
 	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
 	//
 	PublishStrategyOptions map[string]string `json:"PublishStrategyOptions,omitempty"`
+	// a set of kits to build ahead of time and keep available for the Integrations
+	KitPool *IntegrationPlatformKitPoolSpec `json:"kitPool,omitempty"`
+	// pre-pull the images of the built kits onto the cluster nodes
+	ImagePrePull *IntegrationPlatformImagePrePullSpec `json:"imagePrePull,omitempty"`
+	// run the builds of all the namespaces into a dedicated namespace
+	BuildFarm *IntegrationPlatformBuildFarmSpec `json:"buildFarm,omitempty"`
+	// check the dependencies of the builds against a vulnerability database
+	VulnerabilityScan *VulnerabilityScanSpec `json:"vulnerabilityScan,omitempty"`
+	// export the built images as OCI archives, e.g., to transfer them to disconnected environments
+	ImageExport *IntegrationPlatformImageExportSpec `json:"imageExport,omitempty"`
+}
+
+// IntegrationPlatformImageExportSpec configures the export of the kit images, once they are published, as OCI image layout
+// archives into a persistent volume. The archives can then be transferred, e.g., to disconnected environments, and imported
+// into their registries. The export requires the `pod` build strategy.
+type IntegrationPlatformImageExportSpec struct {
+	// the Persistent Volume Claim the archives are written into, that must exist in the namespace the builds are run into
+	PersistentVolumeClaim string `json:"persistentVolumeClaim"`
+	// the directory of the volume the archives are written into (the volume root by default)
+	Path string `json:"path,omitempty"`
+}
+
+// IntegrationPlatformBuildFarmSpec configures a dedicated namespace where the builds of all the namespaces are run,
+// so that the builder pods, and the privileges they may require, are kept apart from the Integrations.
+// The images are published for the namespaces of the kits, which are used as the registry organization by default.
+type IntegrationPlatformBuildFarmSpec struct {
+	// the namespace the builds are run into
+	Namespace string `json:"namespace"`
+	// the labels selecting the nodes the builder pods are scheduled onto
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// the tolerations of the builder pods, e.g., to be scheduled onto dedicated nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// IntegrationPlatformImagePrePullSpec configures the pre-pulling of the kit images onto the cluster nodes, so that
+// the Integration pods, e.g., Knative services scaling from zero, can start without waiting for the image to be pulled.
+type IntegrationPlatformImagePrePullSpec struct {
+	// enables the pre-pulling of the kit images, once they are built
+	Enabled *bool `json:"enabled,omitempty"`
+	// the labels selecting the nodes to pre-pull the images onto (all the nodes by default)
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// the image providing the statically linked `/bin/busybox` binary, run from the kit images to keep them in use on the nodes
+	// (default `docker.io/library/busybox:1.35.0-musl`)
+	HelperImage string `json:"helperImage,omitempty"`
+}
+
+// IntegrationPlatformKitPoolSpec defines a warm pool of kits that the platform builds ahead of time,
+// so that the first Integrations requiring the same dependencies can skip the initial build.
+type IntegrationPlatformKitPoolSpec struct {
+	// how often the platform checks that the pooled kits are available (default `10m`)
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// the kits to keep warm
+	Kits []IntegrationPlatformPooledKitSpec `json:"kits,omitempty"`
+}
+
+// IntegrationPlatformPooledKitSpec defines a kit of the pool, identified by its set of dependencies
+type IntegrationPlatformPooledKitSpec struct {
+	// the name identifying the kit in the pool
+	Name string `json:"name"`
+	// the list of dependencies the kit is built with, ie, `camel:timer` (the runtime dependencies are added automatically).
+	// An Integration can use the kit only when it requires exactly the same set of dependencies.
+	Dependencies []string `json:"dependencies,omitempty"`
 }
 
 // IntegrationPlatformKameletSpec define the behavior for all the Kamelets controller by the IntegrationPlatform
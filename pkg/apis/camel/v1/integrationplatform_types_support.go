@@ -20,6 +20,7 @@ package v1
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -200,6 +201,23 @@ func (b IntegrationPlatformBuildSpec) GetTimeout() metav1.Duration {
 	return *b.Timeout
 }
 
+// GetInterval returns the specified interval between two checks of the kit pool or a default one
+func (p IntegrationPlatformKitPoolSpec) GetInterval() metav1.Duration {
+	if p.Interval == nil {
+		return metav1.Duration{Duration: 10 * time.Minute}
+	}
+	return *p.Interval
+}
+
+// GetHost returns the host exposed for the Integration with the given name and namespace, or an empty string
+// if no host template is configured
+func (e IntegrationPlatformExposureSpec) GetHost(name string, namespace string) string {
+	if e.HostTemplate == "" {
+		return ""
+	}
+	return strings.NewReplacer("{name}", name, "{namespace}", namespace).Replace(e.HostTemplate)
+}
+
 var _ ResourceCondition = IntegrationPlatformCondition{}
 
 // GetConditions --
@@ -128,6 +128,25 @@ func (in *BuildSpec) DeepCopyInto(out *BuildSpec) {
 		}
 	}
 	out.Timeout = in.Timeout
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildSpec.
@@ -140,6 +159,30 @@ func (in *BuildSpec) DeepCopy() *BuildSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildStage) DeepCopyInto(out *BuildStage) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CacheHit != nil {
+		in, out := &in.CacheHit, &out.CacheHit
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStage.
+func (in *BuildStage) DeepCopy() *BuildStage {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 	*out = *in
@@ -148,6 +191,16 @@ func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 		*out = make([]Artifact, len(*in))
 		copy(*out, *in)
 	}
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = new(VulnerabilitySummary)
+		**out = **in
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ImageExportStatus)
+		**out = **in
+	}
 	if in.Failure != nil {
 		in, out := &in.Failure, &out.Failure
 		*out = new(Failure)
@@ -157,6 +210,13 @@ func (in *BuildStatus) DeepCopyInto(out *BuildStatus) {
 		in, out := &in.StartedAt, &out.StartedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]BuildStage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]BuildCondition, len(*in))
@@ -228,6 +288,12 @@ func (in *BuilderTask) DeepCopyInto(out *BuilderTask) {
 		copy(*out, *in)
 	}
 	in.Maven.DeepCopyInto(&out.Maven)
+	if in.VulnerabilityScan != nil {
+		in, out := &in.VulnerabilityScan, &out.VulnerabilityScan
+		*out = new(VulnerabilityScanSpec)
+		**out = **in
+	}
+	in.ContainerResources.DeepCopyInto(&out.ContainerResources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuilderTask.
@@ -524,6 +590,26 @@ func (in *Capability) DeepCopy() *Capability {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapabilityMapping) DeepCopyInto(out *CapabilityMapping) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapabilityMapping.
+func (in *CapabilityMapping) DeepCopy() *CapabilityMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(CapabilityMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
@@ -559,6 +645,50 @@ func (in *DataSpec) DeepCopy() *DataSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentProfileSpec) DeepCopyInto(out *EnvironmentProfileSpec) {
+	*out = *in
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = make([]ConfigurationSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Traits != nil {
+		in, out := &in.Traits, &out.Traits
+		*out = make(map[string]TraitSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentProfileSpec.
+func (in *EnvironmentProfileSpec) DeepCopy() *EnvironmentProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportTask) DeepCopyInto(out *ExportTask) {
+	*out = *in
+	out.BaseTask = in.BaseTask
+	out.Registry = in.Registry
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportTask.
+func (in *ExportTask) DeepCopy() *ExportTask {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Failure) DeepCopyInto(out *Failure) {
 	*out = *in
@@ -612,6 +742,21 @@ func (in *Flow) DeepCopy() *Flow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageExportStatus) DeepCopyInto(out *ImageExportStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageExportStatus.
+func (in *ImageExportStatus) DeepCopy() *ImageExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Integration) DeepCopyInto(out *Integration) {
 	*out = *in
@@ -660,6 +805,21 @@ func (in *IntegrationCondition) DeepCopy() *IntegrationCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationKamelet) DeepCopyInto(out *IntegrationKamelet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationKamelet.
+func (in *IntegrationKamelet) DeepCopy() *IntegrationKamelet {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationKamelet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationKit) DeepCopyInto(out *IntegrationKit) {
 	*out = *in
@@ -781,6 +941,23 @@ func (in *IntegrationKitStatus) DeepCopyInto(out *IntegrationKitStatus) {
 		*out = make([]Artifact, len(*in))
 		copy(*out, *in)
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]CapabilityMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = new(VulnerabilitySummary)
+		**out = **in
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ImageExportStatus)
+		**out = **in
+	}
 	if in.Failure != nil {
 		in, out := &in.Failure, &out.Failure
 		*out = new(Failure)
@@ -864,6 +1041,35 @@ func (in *IntegrationPlatform) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformBuildFarmSpec) DeepCopyInto(out *IntegrationPlatformBuildFarmSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformBuildFarmSpec.
+func (in *IntegrationPlatformBuildFarmSpec) DeepCopy() *IntegrationPlatformBuildFarmSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformBuildFarmSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformBuildSpec) DeepCopyInto(out *IntegrationPlatformBuildSpec) {
 	*out = *in
@@ -886,6 +1092,31 @@ func (in *IntegrationPlatformBuildSpec) DeepCopyInto(out *IntegrationPlatformBui
 			(*out)[key] = val
 		}
 	}
+	if in.KitPool != nil {
+		in, out := &in.KitPool, &out.KitPool
+		*out = new(IntegrationPlatformKitPoolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePrePull != nil {
+		in, out := &in.ImagePrePull, &out.ImagePrePull
+		*out = new(IntegrationPlatformImagePrePullSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuildFarm != nil {
+		in, out := &in.BuildFarm, &out.BuildFarm
+		*out = new(IntegrationPlatformBuildFarmSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VulnerabilityScan != nil {
+		in, out := &in.VulnerabilityScan, &out.VulnerabilityScan
+		*out = new(VulnerabilityScanSpec)
+		**out = **in
+	}
+	if in.ImageExport != nil {
+		in, out := &in.ImageExport, &out.ImageExport
+		*out = new(IntegrationPlatformImageExportSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformBuildSpec.
@@ -898,6 +1129,31 @@ func (in *IntegrationPlatformBuildSpec) DeepCopy() *IntegrationPlatformBuildSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformCleanupSpec) DeepCopyInto(out *IntegrationPlatformCleanupSpec) {
+	*out = *in
+	if in.TemporaryIntegrationTTL != nil {
+		in, out := &in.TemporaryIntegrationTTL, &out.TemporaryIntegrationTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FailedBuildRetention != nil {
+		in, out := &in.FailedBuildRetention, &out.FailedBuildRetention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformCleanupSpec.
+func (in *IntegrationPlatformCleanupSpec) DeepCopy() *IntegrationPlatformCleanupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformCleanupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformCondition) DeepCopyInto(out *IntegrationPlatformCondition) {
 	*out = *in
@@ -915,6 +1171,48 @@ func (in *IntegrationPlatformCondition) DeepCopy() *IntegrationPlatformCondition
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformImageExportSpec) DeepCopyInto(out *IntegrationPlatformImageExportSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformImageExportSpec.
+func (in *IntegrationPlatformImageExportSpec) DeepCopy() *IntegrationPlatformImageExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformImageExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformImagePrePullSpec) DeepCopyInto(out *IntegrationPlatformImagePrePullSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformImagePrePullSpec.
+func (in *IntegrationPlatformImagePrePullSpec) DeepCopy() *IntegrationPlatformImagePrePullSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformImagePrePullSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformKameletRepositorySpec) DeepCopyInto(out *IntegrationPlatformKameletRepositorySpec) {
 	*out = *in
@@ -930,6 +1228,21 @@ func (in *IntegrationPlatformKameletRepositorySpec) DeepCopy() *IntegrationPlatf
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformExposureSpec) DeepCopyInto(out *IntegrationPlatformExposureSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformExposureSpec.
+func (in *IntegrationPlatformExposureSpec) DeepCopy() *IntegrationPlatformExposureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformExposureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformKameletSpec) DeepCopyInto(out *IntegrationPlatformKameletSpec) {
 	*out = *in
@@ -950,6 +1263,33 @@ func (in *IntegrationPlatformKameletSpec) DeepCopy() *IntegrationPlatformKamelet
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformKitPoolSpec) DeepCopyInto(out *IntegrationPlatformKitPoolSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Kits != nil {
+		in, out := &in.Kits, &out.Kits
+		*out = make([]IntegrationPlatformPooledKitSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformKitPoolSpec.
+func (in *IntegrationPlatformKitPoolSpec) DeepCopy() *IntegrationPlatformKitPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformKitPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformList) DeepCopyInto(out *IntegrationPlatformList) {
 	*out = *in
@@ -982,6 +1322,26 @@ func (in *IntegrationPlatformList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformPooledKitSpec) DeepCopyInto(out *IntegrationPlatformPooledKitSpec) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformPooledKitSpec.
+func (in *IntegrationPlatformPooledKitSpec) DeepCopy() *IntegrationPlatformPooledKitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformPooledKitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformResourcesSpec) DeepCopyInto(out *IntegrationPlatformResourcesSpec) {
 	*out = *in
@@ -997,6 +1357,21 @@ func (in *IntegrationPlatformResourcesSpec) DeepCopy() *IntegrationPlatformResou
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationPlatformSecretsScanSpec) DeepCopyInto(out *IntegrationPlatformSecretsScanSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformSecretsScanSpec.
+func (in *IntegrationPlatformSecretsScanSpec) DeepCopy() *IntegrationPlatformSecretsScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationPlatformSecretsScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatformSpec) DeepCopyInto(out *IntegrationPlatformSpec) {
 	*out = *in
@@ -1015,6 +1390,28 @@ func (in *IntegrationPlatformSpec) DeepCopyInto(out *IntegrationPlatformSpec) {
 		copy(*out, *in)
 	}
 	in.Kamelet.DeepCopyInto(&out.Kamelet)
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxySpec)
+		**out = **in
+	}
+	in.Cleanup.DeepCopyInto(&out.Cleanup)
+	out.Exposure = in.Exposure
+	out.SecretsScan = in.SecretsScan
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationPlatformSpec.
@@ -1118,6 +1515,13 @@ func (in *IntegrationSpec) DeepCopyInto(out *IntegrationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]EnvironmentProfileSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationSpec.
@@ -1143,6 +1547,11 @@ func (in *IntegrationStatus) DeepCopyInto(out *IntegrationStatus) {
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = new(VulnerabilitySummary)
+		**out = **in
+	}
 	if in.GeneratedSources != nil {
 		in, out := &in.GeneratedSources, &out.GeneratedSources
 		*out = make([]SourceSpec, len(*in))
@@ -1150,6 +1559,11 @@ func (in *IntegrationStatus) DeepCopyInto(out *IntegrationStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Kamelets != nil {
+		in, out := &in.Kamelets, &out.Kamelets
+		*out = make([]IntegrationKamelet, len(*in))
+		copy(*out, *in)
+	}
 	if in.GeneratedResources != nil {
 		in, out := &in.GeneratedResources, &out.GeneratedResources
 		*out = make([]ResourceSpec, len(*in))
@@ -1183,6 +1597,21 @@ func (in *IntegrationStatus) DeepCopyInto(out *IntegrationStatus) {
 		in, out := &in.InitializationTimestamp, &out.InitializationTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.ResourceProfile != nil {
+		in, out := &in.ResourceProfile, &out.ResourceProfile
+		*out = new(ResourceProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRecommendation != nil {
+		in, out := &in.ResourceRecommendation, &out.ResourceRecommendation
+		*out = new(ResourceRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationStatus.
@@ -1432,6 +1861,21 @@ func (in Properties) DeepCopy() Properties {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySpec.
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublishTask) DeepCopyInto(out *PublishTask) {
 	*out = *in
@@ -1514,6 +1958,78 @@ func (in *RepositoryPolicy) DeepCopy() *RepositoryPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceProfile) DeepCopyInto(out *ResourceProfile) {
+	*out = *in
+	in.WindowStart.DeepCopyInto(&out.WindowStart)
+	in.LastSampleTime.DeepCopyInto(&out.LastSampleTime)
+	if in.Average != nil {
+		in, out := &in.Average, &out.Average
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Peak != nil {
+		in, out := &in.Peak, &out.Peak
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Recommendation != nil {
+		in, out := &in.Recommendation, &out.Recommendation
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceProfile.
+func (in *ResourceProfile) DeepCopy() *ResourceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LowerBound != nil {
+		in, out := &in.LowerBound, &out.LowerBound
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.UpperBound != nil {
+		in, out := &in.UpperBound, &out.UpperBound
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSpec) DeepCopyInto(out *ResourceSpec) {
 	*out = *in
@@ -1530,6 +2046,25 @@ func (in *ResourceSpec) DeepCopy() *ResourceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeSpec) DeepCopyInto(out *RuntimeSpec) {
 	*out = *in
@@ -1674,6 +2209,11 @@ func (in *Task) DeepCopyInto(out *Task) {
 		*out = new(S2iTask)
 		**out = **in
 	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ExportTask)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Task.
@@ -1746,3 +2286,33 @@ func (in *ValueSource) DeepCopy() *ValueSource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilityScanSpec) DeepCopyInto(out *VulnerabilityScanSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilityScanSpec.
+func (in *VulnerabilityScanSpec) DeepCopy() *VulnerabilityScanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilityScanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VulnerabilitySummary) DeepCopyInto(out *VulnerabilitySummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VulnerabilitySummary.
+func (in *VulnerabilitySummary) DeepCopy() *VulnerabilitySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(VulnerabilitySummary)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -32,6 +32,7 @@ import (
 // +genclient:method=UpdateScale,verb=update,subresource=scale,input=k8s.io/api/autoscaling/v1.Scale,result=k8s.io/api/autoscaling/v1.Scale
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The Kamelet Binding phase"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="The Kamelet Binding readiness"
 // +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`,description="The number of pods"
 
 // KameletBinding is the Schema for the kamelets binding API
@@ -128,6 +129,17 @@ type KameletBindingConditionType string
 const (
 	// KameletBindingConditionReady --
 	KameletBindingConditionReady KameletBindingConditionType = "Ready"
+	// KameletBindingConditionSourceReady reports the readiness of the source, when it refers to a resource reporting it
+	KameletBindingConditionSourceReady KameletBindingConditionType = "SourceReady"
+	// KameletBindingConditionSinkReady reports the readiness of the sink, when it refers to a resource reporting it
+	KameletBindingConditionSinkReady KameletBindingConditionType = "SinkReady"
+
+	// KameletBindingConditionEndpointReadyReason --
+	KameletBindingConditionEndpointReadyReason string = "EndpointReady"
+	// KameletBindingConditionEndpointNotReadyReason --
+	KameletBindingConditionEndpointNotReadyReason string = "EndpointNotReady"
+	// KameletBindingConditionEndpointNotFoundReason --
+	KameletBindingConditionEndpointNotFoundReason string = "EndpointNotFound"
 )
 
 // KameletBindingPhase --
@@ -62,7 +62,9 @@ func (t *builderTask) Do(ctx context.Context) v1.BuildStatus {
 		Path:      buildDir,
 		Namespace: t.build.Namespace,
 		Build:     *t.task,
+		BuildName: t.build.Name,
 		BaseImage: t.task.BaseImage,
+		Proxy:     t.build.Spec.Proxy,
 	}
 
 	// Add sources
@@ -102,6 +104,8 @@ func (t *builderTask) Do(ctx context.Context) v1.BuildStatus {
 
 	t.log.Infof("steps: %v", steps)
 
+	stages := make([]v1.BuildStage, 0)
+
 steps:
 	for _, step := range steps {
 		select {
@@ -123,6 +127,7 @@ steps:
 
 			start := time.Now()
 			err := step.execute(&c)
+			stages = recordStage(stages, t.task.Name, step, start)
 			if err != nil {
 				l.Infof("step failed with error: %s", err.Error())
 				result.Failed(err)
@@ -133,6 +138,9 @@ steps:
 		}
 	}
 
+	recordImageCache(stages, &c)
+	result.Stages = stages
+
 	if result.Phase == v1.BuildPhaseInterrupted {
 		t.log.Infof("build task %s interrupted", t.task.Name)
 		return result
@@ -141,6 +149,7 @@ steps:
 	result.BaseImage = c.BaseImage
 	result.Artifacts = make([]v1.Artifact, 0, len(c.Artifacts))
 	result.Artifacts = append(result.Artifacts, c.Artifacts...)
+	result.Vulnerabilities = c.Vulnerabilities
 
 	t.log.Infof("dependencies: %s", t.task.Dependencies)
 	t.log.Infof("artifacts: %s", artifactIDs(c.Artifacts))
@@ -20,9 +20,12 @@ package builder
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/cancellable"
 	"github.com/apache/camel-k/pkg/util/test"
@@ -73,3 +76,95 @@ func TestFailure(t *testing.T) {
 	assert.Equal(t, v1.BuildPhaseFailed, status.Phase)
 	assert.Equal(t, "an error", status.Error)
 }
+
+type stageTestSteps struct {
+	Step1 Step
+	Step2 Step
+	Step3 Step
+	Step4 Step
+}
+
+func TestStages(t *testing.T) {
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+
+	b := New(c)
+
+	steps := stageTestSteps{
+		Step1: NewStep(InitPhase, func(i *builderContext) error {
+			return nil
+		}),
+		Step2: NewStep(ProjectGenerationPhase, func(i *builderContext) error {
+			return nil
+		}),
+		Step3: NewStep(ProjectGenerationPhase+1, func(i *builderContext) error {
+			return nil
+		}),
+		Step4: NewStep(ApplicationPackagePhase, func(i *builderContext) error {
+			i.ReusedImage = "kit-image"
+			return nil
+		}),
+	}
+
+	registerSteps(steps)
+
+	build := &v1.Build{
+		Spec: v1.BuildSpec{
+			Tasks: []v1.Task{
+				{
+					Builder: &v1.BuilderTask{
+						BaseTask: v1.BaseTask{
+							Name: "builder",
+						},
+						Steps: StepIDsFor(
+							steps.Step1,
+							steps.Step2,
+							steps.Step3,
+							steps.Step4,
+						),
+					},
+				},
+			},
+		},
+	}
+
+	ctx := cancellable.NewContext()
+	status := b.Build(build).TaskByName("builder").Do(ctx)
+	assert.Empty(t, status.Error)
+	assert.Len(t, status.Stages, 3)
+	assert.Equal(t, "initialization", status.Stages[0].Name)
+	assert.Equal(t, "project generation", status.Stages[1].Name)
+	assert.Equal(t, "package", status.Stages[2].Name)
+	for _, stage := range status.Stages {
+		assert.Equal(t, "builder", stage.Task)
+		assert.NotNil(t, stage.StartedAt)
+		assert.NotEmpty(t, stage.Duration)
+	}
+	assert.Nil(t, status.Stages[0].CacheHit)
+	assert.True(t, *status.Stages[2].CacheHit)
+	assert.Contains(t, status.Stages[2].Message, "kit-image")
+}
+
+func TestTaskStage(t *testing.T) {
+	enabled := true
+	startedAt := metav1.Now()
+	finishedAt := metav1.NewTime(startedAt.Add(90 * time.Second))
+
+	stage := NewTaskStage(v1.Task{
+		Kaniko: &v1.KanikoTask{
+			BaseTask: v1.BaseTask{Name: "kaniko"},
+			Cache: v1.KanikoTaskCache{
+				Enabled:               &enabled,
+				PersistentVolumeClaim: "kaniko-cache",
+			},
+		},
+	}, startedAt, finishedAt)
+
+	assert.NotNil(t, stage)
+	assert.Equal(t, "image build and push", stage.Name)
+	assert.Equal(t, "kaniko", stage.Task)
+	assert.Equal(t, "1m30s", stage.Duration)
+	assert.Contains(t, stage.Message, "kaniko-cache")
+
+	assert.Nil(t, NewTaskStage(v1.Task{Builder: &v1.BuilderTask{}}, startedAt, finishedAt))
+}
@@ -0,0 +1,215 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/log"
+)
+
+// ImageExportDir is the directory the volume holding the exported image archives is mounted into.
+const ImageExportDir = "/export"
+
+// ociImageRefNameAnnotation is the annotation the tools importing OCI image layouts read the image tag from.
+const ociImageRefNameAnnotation = "org.opencontainers.image.ref.name"
+
+type exportTask struct {
+	c     client.Client
+	build *v1.Build
+	task  *v1.ExportTask
+}
+
+var _ Task = &exportTask{}
+
+func (t *exportTask) Do(ctx context.Context) v1.BuildStatus {
+	status := v1.BuildStatus{}
+
+	// The publish task may have reported the image, e.g., the base image when no new layer is needed
+	image := t.build.Status.Image
+	if image == "" {
+		image = t.task.Image
+	}
+	if image == "" {
+		return status.Failed(errors.New("no image to export"))
+	}
+
+	var options []name.Option
+	if t.task.Registry.Insecure && strings.HasPrefix(image, t.task.Registry.Address) {
+		options = append(options, name.Insecure)
+	}
+	ref, err := name.ParseReference(image, options...)
+	if err != nil {
+		return status.Failed(err)
+	}
+
+	if t.task.Registry.Secret != "" {
+		var registryConfigDir string
+		registryConfigDir, err = mountSecret(ctx, t.c, t.build.Namespace, t.task.Registry.Secret)
+		if err != nil {
+			return status.Failed(err)
+		}
+		defer os.RemoveAll(registryConfigDir)
+		// The default keychain resolves the credentials from the Docker configuration directory
+		if err := os.Setenv("DOCKER_CONFIG", registryConfigDir); err != nil {
+			return status.Failed(err)
+		}
+	}
+
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx))
+	if err != nil {
+		return status.Failed(err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return status.Failed(err)
+	}
+
+	archive := path.Join(ImageExportDir, t.task.Path)
+	if err := os.MkdirAll(path.Dir(archive), 0o755); err != nil {
+		return status.Failed(err)
+	}
+	// Stage the image layout into the target volume, as images can exceed the container ephemeral storage
+	layoutDir, err := ioutil.TempDir(path.Dir(archive), ".export-")
+	if err != nil {
+		return status.Failed(err)
+	}
+	defer os.RemoveAll(layoutDir)
+
+	p, err := layout.Write(layoutDir, empty.Index)
+	if err != nil {
+		return status.Failed(err)
+	}
+	annotations := map[string]string{
+		ociImageRefNameAnnotation: ref.Name(),
+	}
+	if err := p.AppendImage(img, layout.WithAnnotations(annotations)); err != nil {
+		return status.Failed(err)
+	}
+
+	archiveDigest, size, err := writeArchive(layoutDir, archive)
+	if err != nil {
+		return status.Failed(err)
+	}
+
+	log.Infof("Image %s@%s exported into %s", ref.Name(), digest, archive)
+
+	status.Export = &v1.ImageExportStatus{
+		PersistentVolumeClaim: t.task.PersistentVolumeClaim,
+		Path:                  t.task.Path,
+		Image:                 ref.Name(),
+		Digest:                digest.String(),
+		ArchiveDigest:         archiveDigest,
+		Size:                  size,
+	}
+
+	return status
+}
+
+// writeArchive writes the content of the directory into a tar archive, and returns the archive SHA-256 digest and size.
+func writeArchive(dir string, archive string) (string, int64, error) {
+	// Write the archive aside and rename it once complete, so that a partial archive is never left at the target path
+	tmp := archive + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp)
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	tw := tar.NewWriter(io.MultiWriter(file, hash, counter))
+
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer util.CloseQuietly(f)
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot write archive %s: %w", archive, err)
+	}
+
+	if err := os.Rename(tmp, archive); err != nil {
+		return "", 0, err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), counter.n, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,71 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteArchive(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "export-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	layoutDir := path.Join(tmpDir, "layout")
+	assert.Nil(t, os.MkdirAll(path.Join(layoutDir, "blobs", "sha256"), 0o755))
+	assert.Nil(t, ioutil.WriteFile(path.Join(layoutDir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(layoutDir, "index.json"), []byte(`{"schemaVersion":2}`), 0o644))
+	assert.Nil(t, ioutil.WriteFile(path.Join(layoutDir, "blobs", "sha256", "abc"), []byte("blob"), 0o644))
+
+	archive := path.Join(tmpDir, "image.tar")
+	digest, size, err := writeArchive(layoutDir, archive)
+	assert.Nil(t, err)
+
+	content, err := ioutil.ReadFile(archive)
+	assert.Nil(t, err)
+	sum := sha256.Sum256(content)
+	assert.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), digest)
+	assert.Equal(t, int64(len(content)), size)
+
+	_, err = os.Stat(archive + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	f, err := os.Open(archive)
+	assert.Nil(t, err)
+	defer f.Close()
+	entries := make([]string, 0)
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		entries = append(entries, header.Name)
+	}
+	assert.ElementsMatch(t, []string{"blobs/", "blobs/sha256/", "blobs/sha256/abc", "index.json", "oci-layout"}, entries)
+}
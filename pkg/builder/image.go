@@ -18,9 +18,14 @@ limitations under the License.
 package builder
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -30,12 +35,15 @@ import (
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util"
 	"github.com/apache/camel-k/pkg/util/defaults"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 const (
 	ContextDir      = "context"
 	DeploymentDir   = "/deployments"
 	DependenciesDir = "dependencies"
+	// AppCDSArchive is the name of the AppCDS archive, relative to the deployment directory
+	AppCDSArchive = "app-cds.jsa"
 )
 
 func init() {
@@ -48,6 +56,7 @@ type imageSteps struct {
 	StandardImageContext    Step
 	ExecutableDockerfile    Step
 	JvmDockerfile           Step
+	JvmAppCDSDockerfile     Step
 }
 
 var Image = imageSteps{
@@ -56,6 +65,7 @@ var Image = imageSteps{
 	StandardImageContext:    NewStep(ApplicationPackagePhase, standardImageContext),
 	ExecutableDockerfile:    NewStep(ApplicationPackagePhase+1, executableDockerfile),
 	JvmDockerfile:           NewStep(ApplicationPackagePhase+1, jvmDockerfile),
+	JvmAppCDSDockerfile:     NewStep(ApplicationPackagePhase+1, jvmAppCDSDockerfile),
 }
 
 type artifactsSelector func(ctx *builderContext) error
@@ -119,6 +129,53 @@ func jvmDockerfile(ctx *builderContext) error {
 	return nil
 }
 
+// jvmAppCDSDockerfile generates a Dockerfile that bakes an AppCDS archive into the image.
+// The archive is dumped from the list of the classes loaded by a training run of the runtime,
+// with the JVM of the base image, as the archive is only valid for the exact same JVM.
+func jvmAppCDSDockerfile(ctx *builderContext) error {
+	if ctx.Catalog == nil {
+		return errors.New("the Camel catalog is required to generate the AppCDS archive")
+	}
+
+	classpath := strings.Join(AppCDSClasspath(ctx.Artifacts), ":")
+	classList := "/tmp/app-cds.classlist"
+
+	// The training run starts the runtime without any routes, and stops it once started.
+	// It is tolerated to fail, e.g., when a dependency requires some configuration to start,
+	// in which case the archive only contains the classes loaded so far.
+	// #nosec G202
+	dockerfile := []byte(`
+		FROM ` + ctx.BaseImage + `
+		ADD . ` + DeploymentDir + `
+		RUN cd ` + DeploymentDir + ` \
+			&& (timeout 300 java -Xshare:off -XX:DumpLoadedClassList=` + classList + ` -Dcamel.main.duration-max-seconds=10 -cp ` + classpath + ` ` + ctx.Catalog.Runtime.ApplicationClass + ` || true) \
+			&& java -Xshare:dump -XX:SharedClassListFile=` + classList + ` -XX:SharedArchiveFile=` + path.Join(DeploymentDir, AppCDSArchive) + ` -cp ` + classpath + ` \
+			&& rm -f ` + classList + `
+		USER 1000
+	`)
+
+	err := ioutil.WriteFile(path.Join(ctx.Path, ContextDir, "Dockerfile"), dockerfile, 0o400)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AppCDSClasspath returns the class path the AppCDS archive is dumped with. The class path the JVM is launched with
+// must start with the same entries for the archive to be used, so that it is composed of the sorted jar artifacts only.
+func AppCDSClasspath(artifacts []v1.Artifact) []string {
+	classpath := make([]string, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		if strings.HasSuffix(artifact.Target, ".jar") {
+			classpath = append(classpath, artifact.Target)
+		}
+	}
+	sort.Strings(classpath)
+
+	return classpath
+}
+
 func incrementalImageContext(ctx *builderContext) error {
 	images, err := listPublishedImages(ctx)
 	if err != nil {
@@ -131,6 +188,7 @@ func incrementalImageContext(ctx *builderContext) error {
 		bestImage, commonLibs := findBestImage(images, ctx.Artifacts)
 		if bestImage.Image != "" {
 			ctx.BaseImage = bestImage.Image
+			ctx.ReusedImage = bestImage.Image
 			ctx.SelectedArtifacts = make([]v1.Artifact, 0)
 
 			for _, entry := range ctx.Artifacts {
@@ -175,6 +233,68 @@ func imageContext(ctx *builderContext, selector artifactsSelector) error {
 		}
 	}
 
+	if !upload.HasDependency(ctx.Build.Dependencies) {
+		return nil
+	}
+	fetcher, err := uploadFetcher(ctx)
+	if err != nil {
+		return err
+	}
+	return copyUploads(ctx.C, ctx.Build.Dependencies, fetcher, contextDir)
+}
+
+// uploadFetcher returns the store of the operator for the builds it runs, and the client of the operator API
+// for the builder Pods, that are authenticated with the token of their service account.
+func uploadFetcher(ctx *builderContext) (upload.Fetcher, error) {
+	if dir := upload.Dir(); dir != "" {
+		return upload.NewStore(dir), nil
+	}
+	operatorURL := os.Getenv(upload.URLEnvVariable)
+	if operatorURL == "" || ctx.Client == nil {
+		return nil, errors.New("the uploaded files are not available to the build, " +
+			"the operator must be installed with a persistent volume for the uploads")
+	}
+	config := ctx.Client.GetConfig()
+	token := config.BearerToken
+	if token == "" && config.BearerTokenFile != "" {
+		data, err := ioutil.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	return upload.NewClient(operatorURL, token, ctx.Namespace, ctx.BuildName), nil
+}
+
+// copyUploads adds the files uploaded to the operator to the image context.
+func copyUploads(ctx context.Context, dependencies []string, fetcher upload.Fetcher, contextDir string) error {
+	for _, d := range dependencies {
+		if !strings.HasPrefix(d, upload.DependencyPrefix) {
+			continue
+		}
+		namespace, digest, targetPath, err := upload.ParseDependency(d)
+		if err != nil {
+			return err
+		}
+		target := path.Join(contextDir, targetPath)
+		if err := os.MkdirAll(path.Dir(target), 0o700); err != nil {
+			return err
+		}
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		err = fetcher.Fetch(ctx, namespace, digest, f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if errors.Is(err, upload.ErrNotFound) {
+			return fmt.Errorf("uploaded file %s not found in namespace %s", digest, namespace)
+		} else if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
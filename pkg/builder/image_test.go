@@ -18,6 +18,11 @@ limitations under the License.
 package builder
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +33,7 @@ import (
 	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/cancellable"
 	"github.com/apache/camel-k/pkg/util/test"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 func TestListPublishedImages(t *testing.T) {
@@ -92,3 +98,70 @@ func TestListPublishedImages(t *testing.T) {
 	assert.Len(t, i, 1)
 	assert.Equal(t, "image-2", i[0].Image)
 }
+
+func TestCopyUploads(t *testing.T) {
+	store := upload.NewStore(t.TempDir())
+	id, err := store.Create("ns")
+	assert.Nil(t, err)
+	_, err = store.Append("ns", id, 0, strings.NewReader("data"))
+	assert.Nil(t, err)
+	digest, err := upload.Digest(strings.NewReader("data"))
+	assert.Nil(t, err)
+	assert.Nil(t, store.Commit("ns", id, digest))
+
+	contextDir := t.TempDir()
+	dependencies := []string{
+		"camel:log",
+		upload.Dependency("ns", digest, "camel-k-resources/1a2b3c4d/data.bin"),
+	}
+	assert.Nil(t, copyUploads(context.Background(), dependencies, store, contextDir))
+
+	data, err := ioutil.ReadFile(path.Join(contextDir, "camel-k-resources/1a2b3c4d/data.bin"))
+	assert.Nil(t, err)
+	assert.Equal(t, "data", string(data))
+
+	err = copyUploads(context.Background(), []string{upload.Dependency("other", digest, "data.bin")}, store, contextDir)
+	assert.NotNil(t, err)
+}
+
+func TestUploadFetcher(t *testing.T) {
+	ctx := &builderContext{Namespace: "ns", BuildName: "build"}
+
+	_, err := uploadFetcher(ctx)
+	assert.NotNil(t, err)
+
+	dir := t.TempDir()
+	os.Setenv(upload.DirEnvVariable, dir)
+	defer os.Unsetenv(upload.DirEnvVariable)
+	fetcher, err := uploadFetcher(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, upload.NewStore(dir), fetcher)
+}
+
+func TestJvmAppCDSDockerfile(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	ctx := builderContext{
+		Catalog:   catalog,
+		Path:      t.TempDir(),
+		BaseImage: "adoptopenjdk/openjdk11:slim",
+		Artifacts: []v1.Artifact{
+			{ID: "quarkus-run.jar", Target: "dependencies/quarkus-run.jar"},
+			{ID: "quarkus-application.dat", Target: "dependencies/quarkus/quarkus-application.dat"},
+			{ID: "camel-core.jar", Target: "dependencies/lib/main/camel-core.jar"},
+			{ID: "camel-k-integration.jar", Target: "dependencies/app/camel-k-integration.jar"},
+		},
+	}
+	assert.Nil(t, os.MkdirAll(path.Join(ctx.Path, ContextDir), 0o700))
+	assert.Nil(t, jvmAppCDSDockerfile(&ctx))
+
+	data, err := ioutil.ReadFile(path.Join(ctx.Path, ContextDir, "Dockerfile"))
+	assert.Nil(t, err)
+	dockerfile := string(data)
+	classpath := "dependencies/app/camel-k-integration.jar:dependencies/lib/main/camel-core.jar:dependencies/quarkus-run.jar"
+	assert.Contains(t, dockerfile, "FROM adoptopenjdk/openjdk11:slim")
+	assert.Contains(t, dockerfile, "-XX:DumpLoadedClassList=/tmp/app-cds.classlist -Dcamel.main.duration-max-seconds=10 -cp "+classpath+" "+catalog.Runtime.ApplicationClass)
+	assert.Contains(t, dockerfile, "-Xshare:dump -XX:SharedClassListFile=/tmp/app-cds.classlist -XX:SharedArchiveFile=/deployments/app-cds.jsa -cp "+classpath)
+	assert.NotContains(t, dockerfile, "quarkus-application.dat")
+}
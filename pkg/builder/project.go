@@ -109,12 +109,21 @@ func generateProjectSettings(ctx *builderContext) error {
 	if err != nil {
 		return err
 	}
+	if val == "" && len(ctx.Build.Maven.Servers) > 0 {
+		// The servers are injected into empty user settings, the repositories being
+		// declared in the global settings
+		val = emptyMavenSettings
+	}
 	val = injectServersIntoMavenSettings(val, ctx.Build.Maven.Servers)
 	if val != "" {
 		ctx.Maven.UserSettings = []byte(val)
 	}
 
-	settings, err := maven.NewSettings(maven.DefaultRepositories, maven.ProxyFromEnvironment)
+	var proxies maven.SettingsOption = maven.ProxyFromEnvironment
+	if ctx.Proxy != nil {
+		proxies = maven.Proxies(ctx.Proxy.HTTPProxy, ctx.Proxy.HTTPSProxy, ctx.Proxy.NoProxy)
+	}
+	settings, err := maven.NewSettings(maven.DefaultRepositories, proxies)
 	if err != nil {
 		return err
 	}
@@ -135,6 +144,11 @@ func generateProjectSettings(ctx *builderContext) error {
 	return nil
 }
 
+const emptyMavenSettings = `<?xml version="1.0" encoding="UTF-8"?>
+<settings xmlns="http://maven.apache.org/SETTINGS/1.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://maven.apache.org/SETTINGS/1.0.0 https://maven.apache.org/xsd/settings-1.0.0.xsd">
+</settings>
+`
+
 func injectServersIntoMavenSettings(settings string, servers []v1.Server) string {
 	if servers == nil || len(servers) < 1 {
 		return settings
@@ -489,6 +489,41 @@ func TestInjectServersIntoCustomMavenSettings(t *testing.T) {
 	assert.Equal(t, removeWhitespaces(expectedCustomSettingsWithExtraServers), removeWhitespaces(newSettings))
 }
 
+func TestInjectServersIntoEmptyMavenSettings(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+
+	ctx := builderContext{
+		Catalog:   catalog,
+		Client:    c,
+		Namespace: "ns",
+		Build: v1.BuilderTask{
+			Runtime: catalog.Runtime,
+			Maven: v1.MavenBuildSpec{
+				Servers: []v1.Server{
+					{
+						ID:       "private",
+						Username: "user",
+						Password: "secret",
+					},
+				},
+			},
+		},
+	}
+
+	err = Project.GenerateProjectSettings.execute(&ctx)
+	assert.Nil(t, err)
+
+	settings := string(ctx.Maven.UserSettings)
+	assert.Contains(t, settings, "<servers>")
+	assert.Contains(t, settings, "<id>private</id>")
+	assert.Contains(t, settings, "<username>user</username>")
+	assert.Contains(t, settings, "<password>secret</password>")
+}
+
 func removeWhitespaces(s string) string {
 	re := regexp.MustCompile(`\s`)
 	return re.ReplaceAllString(s, "")
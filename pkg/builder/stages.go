@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+// stageName returns the name of the Build stage the builder steps of the given phase are grouped into.
+func stageName(phase int32) string {
+	switch {
+	case phase < ProjectGenerationPhase:
+		return "initialization"
+	case phase < ProjectBuildPhase:
+		return "project generation"
+	case phase < ApplicationPackagePhase:
+		return "dependency resolution"
+	case phase < ApplicationPublishPhase:
+		return "package"
+	default:
+		return "publish"
+	}
+}
+
+// recordStage accounts the execution of the step, started at the given time, into the stage its phase belongs to.
+func recordStage(stages []v1.BuildStage, task string, step Step, start time.Time) []v1.BuildStage {
+	name := stageName(step.Phase())
+	if len(stages) == 0 || stages[len(stages)-1].Name != name {
+		stages = append(stages, v1.BuildStage{
+			Name:      name,
+			Task:      task,
+			StartedAt: &metav1.Time{Time: start},
+		})
+	}
+	stage := &stages[len(stages)-1]
+	stage.Duration = time.Since(stage.StartedAt.Time).String()
+	return stages
+}
+
+// recordImageCache reports whether the image of another kit has been reused as base layer, in the package stage.
+func recordImageCache(stages []v1.BuildStage, c *builderContext) {
+	for i := range stages {
+		if stages[i].Name != stageName(ApplicationPackagePhase) {
+			continue
+		}
+		stages[i].CacheHit = pointer.Bool(c.ReusedImage != "")
+		if c.ReusedImage != "" {
+			stages[i].Message = fmt.Sprintf("reused image %s as base layer, %d/%d artifacts added",
+				c.ReusedImage, len(c.SelectedArtifacts), len(c.Artifacts))
+		}
+	}
+}
+
+// NewTaskStage returns the stage recording the execution of the task, or nil for the builder task,
+// whose stages are recorded per group of steps.
+func NewTaskStage(task v1.Task, startedAt metav1.Time, finishedAt metav1.Time) *v1.BuildStage {
+	stage := v1.BuildStage{
+		Task:      TaskName(task),
+		StartedAt: &startedAt,
+		Duration:  finishedAt.Sub(startedAt.Time).String(),
+	}
+	switch {
+	case task.Buildah != nil, task.Spectrum != nil, task.S2i != nil:
+		stage.Name = "image build and push"
+	case task.Kaniko != nil:
+		stage.Name = "image build and push"
+		if pointer.BoolDeref(task.Kaniko.Cache.Enabled, false) {
+			stage.Message = fmt.Sprintf("layers cached in %s", task.Kaniko.Cache.PersistentVolumeClaim)
+		}
+	case task.Export != nil:
+		stage.Name = "image export"
+	default:
+		return nil
+	}
+	return &stage
+}
+
+// TaskName returns the name of the task, whatever its type.
+func TaskName(task v1.Task) string {
+	switch {
+	case task.Builder != nil:
+		return task.Builder.Name
+	case task.Buildah != nil:
+		return task.Buildah.Name
+	case task.Kaniko != nil:
+		return task.Kaniko.Name
+	case task.Spectrum != nil:
+		return task.Spectrum.Name
+	case task.S2i != nil:
+		return task.S2i.Name
+	case task.Export != nil:
+		return task.Export.Name
+	}
+	return ""
+}
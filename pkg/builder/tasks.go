@@ -62,6 +62,11 @@ func (b *Build) Task(task v1.Task) Task {
 			build: b.build,
 			task:  task.S2i,
 		}
+	case task.Export != nil:
+		return &unsupportedTask{
+			build: b.build,
+			name:  task.Export.Name,
+		}
 	}
 
 	return &emptyTask{
@@ -138,6 +143,12 @@ func (b *Build) TaskByName(name string) Task {
 				build: b.build,
 				task:  task.S2i,
 			}
+		case task.Export != nil && task.Export.Name == name:
+			return &exportTask{
+				c:     b.builder.client,
+				build: b.build,
+				task:  task.Export,
+			}
 		}
 	}
 	return &missingTask{
@@ -77,11 +77,15 @@ type builderContext struct {
 	C                 context.Context
 	Catalog           *camel.RuntimeCatalog
 	Build             v1.BuilderTask
+	BuildName         string
 	BaseImage         string
+	ReusedImage       string
 	Namespace         string
+	Proxy             *v1.ProxySpec
 	Path              string
 	Artifacts         []v1.Artifact
 	SelectedArtifacts []v1.Artifact
+	Vulnerabilities   *v1.VulnerabilitySummary
 	Resources         []resource
 	Maven             struct {
 		Project          maven.Project
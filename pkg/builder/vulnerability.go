@@ -0,0 +1,185 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/osv"
+)
+
+func init() {
+	registerSteps(Vulnerability)
+}
+
+type vulnerabilitySteps struct {
+	ScanDependencies Step
+}
+
+var Vulnerability = vulnerabilitySteps{
+	// Executed once the dependencies are computed
+	ScanDependencies: NewStep(ProjectBuildPhase+2, scanDependencies),
+}
+
+func scanDependencies(ctx *builderContext) error {
+	scan := ctx.Build.VulnerabilityScan
+	if scan == nil {
+		return nil
+	}
+
+	var packages []osv.Package
+	for _, artifact := range ctx.Artifacts {
+		if !strings.HasSuffix(artifact.Location, ".jar") {
+			continue
+		}
+		gavs, err := mavenCoordinates(artifact.Location)
+		if err != nil {
+			return errors.Wrapf(err, "cannot read the Maven coordinates of %s", artifact.ID)
+		}
+		packages = append(packages, gavs...)
+	}
+	packages = uniquePackages(packages)
+
+	client := osv.NewClient(scan.Database)
+	ids, err := client.Query(ctx.C, osv.EcosystemMaven, packages)
+	if err != nil {
+		return errors.Wrap(err, "cannot query the vulnerability database")
+	}
+
+	summary := v1.VulnerabilitySummary{
+		Database: scan.Database,
+	}
+	if summary.Database == "" {
+		summary.Database = osv.DefaultDatabase
+	}
+
+	severities := make(map[string]v1.VulnerabilitySeverity)
+	for i, vulns := range ids {
+		for _, id := range vulns {
+			if _, ok := severities[id]; !ok {
+				vuln, err := client.Get(ctx.C, id)
+				if err != nil {
+					return errors.Wrapf(err, "cannot get vulnerability %s", id)
+				}
+				severities[id] = severityOf(vuln)
+				summary.Add(severities[id])
+			}
+			log.Infof("Dependency %s:%s is affected by vulnerability %s (%s)", packages[i].Name, packages[i].Version, id, severities[id])
+		}
+	}
+
+	ctx.Vulnerabilities = &summary
+
+	if scan.FailThreshold != "" {
+		if count := summary.CountFrom(scan.FailThreshold); count > 0 {
+			return fmt.Errorf("found %d vulnerabilities of severity %s or more in the dependencies (%s)", count, scan.FailThreshold, summary.String())
+		}
+	}
+
+	return nil
+}
+
+// mavenCoordinates returns the Maven coordinates declared by the pom.properties files packaged into the jar,
+// i.e., the ones of the artifact itself, and of the artifacts it shades, if any.
+func mavenCoordinates(jar string) ([]osv.Package, error) {
+	r, err := zip.OpenReader(jar)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var packages []osv.Package
+	for _, f := range r.File {
+		if !strings.HasPrefix(f.Name, "META-INF/maven/") || path.Base(f.Name) != "pom.properties" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		properties := make(map[string]string)
+		scanner := bufio.NewScanner(rc)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if i := strings.Index(line, "="); i > 0 {
+				properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+			}
+		}
+		err = scanner.Err()
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if properties["groupId"] != "" && properties["artifactId"] != "" && properties["version"] != "" {
+			packages = append(packages, osv.Package{
+				Name:    properties["groupId"] + ":" + properties["artifactId"],
+				Version: properties["version"],
+			})
+		}
+	}
+
+	return packages, nil
+}
+
+func uniquePackages(packages []osv.Package) []osv.Package {
+	set := make(map[osv.Package]bool)
+	unique := make([]osv.Package, 0, len(packages))
+	for _, p := range packages {
+		if !set[p] {
+			set[p] = true
+			unique = append(unique, p)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		if unique[i].Name == unique[j].Name {
+			return unique[i].Version < unique[j].Version
+		}
+		return unique[i].Name < unique[j].Name
+	})
+	return unique
+}
+
+// severityOf maps the severity rated by the database, e.g., for the GitHub advisories, to the vulnerability severity.
+func severityOf(vuln *osv.Vulnerability) v1.VulnerabilitySeverity {
+	switch vuln.Severity() {
+	case "CRITICAL":
+		return v1.VulnerabilitySeverityCritical
+	case "HIGH":
+		return v1.VulnerabilitySeverityHigh
+	case "MODERATE", "MEDIUM":
+		return v1.VulnerabilitySeverityModerate
+	case "LOW":
+		return v1.VulnerabilitySeverityLow
+	default:
+		return v1.VulnerabilitySeverityUnknown
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"archive/zip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestScanDependencies(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "vulnerability-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	jar := path.Join(tmpDir, "com.fasterxml.jackson.core.jackson-databind-2.9.8.jar")
+	f, err := os.Create(jar)
+	assert.Nil(t, err)
+	w := zip.NewWriter(f)
+	pom, err := w.Create("META-INF/maven/com.fasterxml.jackson.core/jackson-databind/pom.properties")
+	assert.Nil(t, err)
+	_, err = pom.Write([]byte("#Generated by Maven\ngroupId=com.fasterxml.jackson.core\nartifactId=jackson-databind\nversion=2.9.8\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	assert.Nil(t, f.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-1"},{"id":"GHSA-2"}]}]}`))
+		case "/v1/vulns/GHSA-1":
+			_, _ = w.Write([]byte(`{"id":"GHSA-1","database_specific":{"severity":"HIGH"}}`))
+		case "/v1/vulns/GHSA-2":
+			_, _ = w.Write([]byte(`{"id":"GHSA-2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := builderContext{
+		C: context.TODO(),
+		Build: v1.BuilderTask{
+			VulnerabilityScan: &v1.VulnerabilityScanSpec{
+				Database: server.URL,
+			},
+		},
+		Artifacts: []v1.Artifact{
+			{ID: path.Base(jar), Location: jar},
+			{ID: "quarkus-application.dat", Location: path.Join(tmpDir, "quarkus-application.dat")},
+		},
+	}
+
+	assert.Nil(t, scanDependencies(&ctx))
+	assert.Equal(t, &v1.VulnerabilitySummary{High: 1, Unknown: 1, Database: server.URL}, ctx.Vulnerabilities)
+
+	ctx.Build.VulnerabilityScan.FailThreshold = v1.VulnerabilitySeverityModerate
+	assert.NotNil(t, scanDependencies(&ctx))
+
+	ctx.Build.VulnerabilityScan.FailThreshold = v1.VulnerabilitySeverityCritical
+	assert.Nil(t, scanDependencies(&ctx))
+}
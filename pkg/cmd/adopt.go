@@ -0,0 +1,142 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+)
+
+func newCmdAdopt(rootCmdOptions *RootCmdOptions) (*cobra.Command, *adoptCmdOptions) {
+	options := adoptCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:   "adopt [integration...]",
+		Short: "Reassign integrations to an operator",
+		Long: `Reassign the given integrations to an operator, or all the integrations of the namespace whose operator ` +
+			`is not known from any platform anymore when none is given.`,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().String("operator-id", "", "The id of the operator adopting the integrations, defaults to the default operator")
+
+	return &cmd, &options
+}
+
+type adoptCmdOptions struct {
+	*RootCmdOptions
+	OperatorID string `mapstructure:"operator-id"`
+}
+
+func (o *adoptCmdOptions) run(cmd *cobra.Command, args []string) error {
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	operatorIDs, err := platform.GetOperatorIDs(o.Context, c)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve the installed operators")
+	}
+
+	operatorID := o.OperatorID
+	if operatorID == "" {
+		if operatorID, _, err = platform.GetDefaultOperatorID(o.Context, c); err != nil {
+			return err
+		}
+	} else if !operatorIDs[operatorID] {
+		return fmt.Errorf("no platform found for operator %q", operatorID)
+	}
+
+	integrations, err := o.integrationsToAdopt(c, args, operatorIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range integrations {
+		it := i
+		if isIntegrationOwned(it) {
+			fmt.Fprintf(cmd.OutOrStdout(), "Skipping integration %s, which is managed by its owner\n", it.Name)
+			continue
+		}
+		if err := o.adopt(c, &it, operatorID); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Integration %s adopted\n", it.Name)
+	}
+
+	return nil
+}
+
+func (o *adoptCmdOptions) integrationsToAdopt(c client.Client, names []string, operatorIDs map[string]bool) ([]v1.Integration, error) {
+	if len(names) == 0 {
+		list := v1.NewIntegrationList()
+		if err := c.List(o.Context, &list, k8sclient.InNamespace(o.Namespace)); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("could not retrieve integrations from namespace %s", o.Namespace))
+		}
+		orphans := make([]v1.Integration, 0)
+		for _, it := range list.Items {
+			it := it
+			if platform.IsOrphan(&it, operatorIDs) {
+				orphans = append(orphans, it)
+			}
+		}
+		return orphans, nil
+	}
+
+	integrations := make([]v1.Integration, 0, len(names))
+	for _, n := range names {
+		it := v1.NewIntegration(o.Namespace, n)
+		key := k8sclient.ObjectKey{
+			Name:      n,
+			Namespace: o.Namespace,
+		}
+		if err := c.Get(o.Context, key, &it); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("could not find integration %s in namespace %s", n, o.Namespace))
+		}
+		integrations = append(integrations, it)
+	}
+	return integrations, nil
+}
+
+func (o *adoptCmdOptions) adopt(c client.Client, it *v1.Integration, operatorID string) error {
+	target := it.DeepCopy()
+	if operatorID == "" {
+		// The integration is handled by the operator without id
+		delete(target.Annotations, v1.OperatorIDAnnotation)
+	} else {
+		if target.Annotations == nil {
+			target.Annotations = make(map[string]string)
+		}
+		target.Annotations[v1.OperatorIDAnnotation] = operatorID
+	}
+	if err := c.Patch(o.Context, target, k8sclient.MergeFrom(it)); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not adopt integration %s in namespace %s", it.Name, o.Namespace))
+	}
+	return nil
+}
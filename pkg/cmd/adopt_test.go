@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdAdopt = "adopt"
+
+func initializeAdoptCmdOptions(t *testing.T, c client.Client) *cobra.Command {
+	t.Helper()
+
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	adoptCmd, _ := newCmdAdopt(&options)
+	rootCmd.AddCommand(adoptCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return rootCmd
+}
+
+func newAdoptTestClient(t *testing.T) client.Client {
+	t.Helper()
+
+	pl := v1.NewIntegrationPlatform("operators", "camel-k")
+	pl.Annotations = map[string]string{
+		v1.OperatorIDAnnotation:              "operator-a",
+		v1.OperatorDefaultPriorityAnnotation: "10",
+	}
+	integration := func(name string, operatorID string) *v1.Integration {
+		it := v1.NewIntegration("default", name)
+		if operatorID != "" {
+			it.Annotations = map[string]string{
+				v1.OperatorIDAnnotation: operatorID,
+			}
+		}
+		return &it
+	}
+
+	c, err := test.NewFakeClient(
+		&pl,
+		integration("orphan", "operator-gone"),
+		integration("assigned", "operator-a"),
+		integration("unassigned", ""),
+	)
+	assert.Nil(t, err)
+	return c
+}
+
+func operatorIDOf(t *testing.T, c client.Client, name string) string {
+	t.Helper()
+
+	it := v1.NewIntegration("default", name)
+	err := c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&it), &it)
+	assert.Nil(t, err)
+	return it.Annotations[v1.OperatorIDAnnotation]
+}
+
+func TestAdoptOrphans(t *testing.T) {
+	c := newAdoptTestClient(t)
+	rootCmd := initializeAdoptCmdOptions(t, c)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdAdopt, "-n", "default")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "Integration orphan adopted")
+	assert.NotContains(t, output, "Integration assigned adopted")
+	assert.Equal(t, "operator-a", operatorIDOf(t, c, "orphan"))
+	assert.Equal(t, "", operatorIDOf(t, c, "unassigned"))
+}
+
+func TestAdoptNamedIntegration(t *testing.T) {
+	c := newAdoptTestClient(t)
+	rootCmd := initializeAdoptCmdOptions(t, c)
+
+	_, err := test.ExecuteCommand(rootCmd, cmdAdopt, "-n", "default", "unassigned", "--operator-id", "operator-a")
+	assert.Nil(t, err)
+	assert.Equal(t, "operator-a", operatorIDOf(t, c, "unassigned"))
+	assert.Equal(t, "operator-gone", operatorIDOf(t, c, "orphan"))
+}
+
+func TestAdoptUnknownOperator(t *testing.T) {
+	c := newAdoptTestClient(t)
+	rootCmd := initializeAdoptCmdOptions(t, c)
+
+	_, err := test.ExecuteCommand(rootCmd, cmdAdopt, "-n", "default", "--operator-id", "operator-unknown")
+	assert.NotNil(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/apache/camel-k/pkg/util/reference"
 	"github.com/apache/camel-k/pkg/util/uri"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -54,6 +55,7 @@ func newCmdBind(rootCmdOptions *RootCmdOptions) (*cobra.Command, *bindCmdOptions
 
 	cmd.Flags().StringArrayP("connect", "c", nil, "A ServiceBinding or Provisioned Service that the integration should bind to, specified as [[apigroup/]version:]kind:[namespace/]name")
 	cmd.Flags().String("error-handler", "", `Add error handler (none|log|sink:<endpoint>). Sink endpoints are expected in the format "[[apigroup/]version:]kind:[namespace/]name", plain Camel URIs or Kamelet name.`)
+	cmd.Flags().BoolP("interactive", "i", false, "Select the Kamelets to bind among the ones available in the namespace and prompt for their required properties")
 	cmd.Flags().String("name", "", "Name for the binding")
 	cmd.Flags().StringP("output", "o", "", "Output format. One of: json|yaml")
 	cmd.Flags().StringArrayP("property", "p", nil, `Add a binding property in the form of "source.<key>=<value>", "sink.<key>=<value>", "error-handler.<key>=<value>" or "step-<n>.<key>=<value>"`)
@@ -74,6 +76,7 @@ const (
 type bindCmdOptions struct {
 	*RootCmdOptions
 	ErrorHandler string   `mapstructure:"error-handler" yaml:",omitempty"`
+	Interactive  bool     `mapstructure:"interactive" yaml:",omitempty"`
 	Name         string   `mapstructure:"name" yaml:",omitempty"`
 	Connects     []string `mapstructure:"connects" yaml:",omitempty"`
 	OutputFormat string   `mapstructure:"output" yaml:",omitempty"`
@@ -81,6 +84,8 @@ type bindCmdOptions struct {
 	SkipChecks   bool     `mapstructure:"skip-checks" yaml:",omitempty"`
 	Steps        []string `mapstructure:"steps" yaml:",omitempty"`
 	Traits       []string `mapstructure:"traits" yaml:",omitempty"`
+	// secret holds the sensitive properties entered in interactive mode
+	secret *corev1.Secret
 }
 
 func (o *bindCmdOptions) preRunE(cmd *cobra.Command, args []string) error {
@@ -92,6 +97,12 @@ func (o *bindCmdOptions) preRunE(cmd *cobra.Command, args []string) error {
 }
 
 func (o *bindCmdOptions) runE(cmd *cobra.Command, args []string) error {
+	if o.Interactive {
+		var err error
+		if args, err = o.runWizard(cmd, args); err != nil {
+			return err
+		}
+	}
 	if err := o.validate(cmd, args); err != nil {
 		return err
 	}
@@ -218,9 +229,26 @@ func (o *bindCmdOptions) run(cmd *cobra.Command, args []string) error {
 	}
 
 	if o.OutputFormat != "" {
+		if o.secret != nil {
+			if err := showOutput(cmd, o.secret, o.OutputFormat, client.GetScheme()); err != nil {
+				return err
+			}
+			if o.OutputFormat == "yaml" {
+				fmt.Fprintln(cmd.OutOrStdout(), "---")
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout())
+			}
+		}
 		return showOutput(cmd, &binding, o.OutputFormat, client.GetScheme())
 	}
 
+	if o.secret != nil {
+		if err := kubernetes.ReplaceResource(o.Context, client, o.secret); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), `secret "`+o.secret.Name+`" configured`)
+	}
+
 	existed := false
 	err = client.Create(o.Context, &binding)
 	if err != nil && k8serrors.IsAlreadyExists(err) {
@@ -239,12 +267,12 @@ func (o *bindCmdOptions) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func showOutput(cmd *cobra.Command, binding *v1alpha1.KameletBinding, outputFormat string, scheme runtime.ObjectTyper) error {
+func showOutput(cmd *cobra.Command, obj runtime.Object, outputFormat string, scheme runtime.ObjectTyper) error {
 	printer := printers.NewTypeSetter(scheme)
 	printer.Delegate = &kubernetes.CLIPrinter{
 		Format: outputFormat,
 	}
-	return printer.PrintObj(binding, cmd.OutOrStdout())
+	return printer.PrintObj(obj, cmd.OutOrStdout())
 }
 
 func (o *bindCmdOptions) parseErrorHandler() (*v1alpha1.ErrorHandlerSpec, error) {
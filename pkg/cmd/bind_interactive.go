@@ -0,0 +1,471 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/uri"
+)
+
+// passwordDescriptor marks the Kamelet properties that hold credentials.
+const passwordDescriptor = "urn:alm:descriptor:com.tectonic.ui:password"
+
+// bindWizard prompts the user on the command input and output.
+type bindWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	// terminal is set when the input is a terminal, so that sensitive values are not echoed
+	terminal *os.File
+}
+
+func newBindWizard(cmd *cobra.Command) *bindWizard {
+	w := bindWizard{
+		in:  bufio.NewReader(cmd.InOrStdin()),
+		out: cmd.OutOrStdout(),
+	}
+	if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		w.terminal = f
+	}
+	return &w
+}
+
+func (w *bindWizard) ask(prompt string) (string, error) {
+	fmt.Fprint(w.out, prompt)
+	line, err := w.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (w *bindWizard) askSensitive(prompt string) (string, error) {
+	if w.terminal == nil {
+		return w.ask(prompt)
+	}
+	fmt.Fprint(w.out, prompt)
+	value, err := term.ReadPassword(int(w.terminal.Fd()))
+	fmt.Fprintln(w.out)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+func (w *bindWizard) confirm(prompt string) (bool, error) {
+	for {
+		answer, err := w.ask(prompt + " [Y/n]: ")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "", "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// selectEndpoint lets the user pick one of the Kamelets of the given type, by number or name, or enter a Camel URI.
+func (w *bindWizard) selectEndpoint(kamelets []v1alpha1.Kamelet, kameletType string, namespace string) (string, error) {
+	candidates := kameletsOfType(kamelets, kameletType)
+	w.listKamelets(candidates, kameletType, namespace)
+	for {
+		answer, err := w.ask(fmt.Sprintf("Select the %s (number, Kamelet name or Camel URI): ", kameletType))
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			continue
+		}
+		if endpoint, ok := resolveEndpoint(candidates, kamelets, answer); ok {
+			return endpoint, nil
+		}
+		fmt.Fprintf(w.out, "%q is neither a listed Kamelet nor a Camel URI\n", answer)
+	}
+}
+
+// selectActions lets the user pick the action Kamelets to apply, in order.
+func (w *bindWizard) selectActions(kamelets []v1alpha1.Kamelet, namespace string) ([]string, error) {
+	candidates := kameletsOfType(kamelets, v1alpha1.KameletTypeAction)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	w.listKamelets(candidates, v1alpha1.KameletTypeAction, namespace)
+	for {
+		answer, err := w.ask("Select the actions to apply, in order (comma separated numbers or names, empty for none): ")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			return nil, nil
+		}
+		steps := make([]string, 0)
+		valid := true
+		for _, item := range strings.Split(answer, ",") {
+			step, ok := resolveEndpoint(candidates, kamelets, strings.TrimSpace(item))
+			if !ok {
+				fmt.Fprintf(w.out, "%q is neither a listed Kamelet nor a Camel URI\n", strings.TrimSpace(item))
+				valid = false
+				break
+			}
+			steps = append(steps, step)
+		}
+		if valid {
+			return steps, nil
+		}
+	}
+}
+
+func (w *bindWizard) listKamelets(kamelets []v1alpha1.Kamelet, kameletType string, namespace string) {
+	if len(kamelets) == 0 {
+		fmt.Fprintf(w.out, "No %s Kamelet found in namespace %q\n", kameletType, namespace)
+		return
+	}
+	fmt.Fprintf(w.out, "Available %s Kamelets:\n", kameletType)
+	for i, k := range kamelets {
+		title := ""
+		if k.Spec.Definition != nil && k.Spec.Definition.Title != "" {
+			title = " - " + k.Spec.Definition.Title
+		}
+		fmt.Fprintf(w.out, "  %d) %s%s\n", i+1, k.Name, title)
+	}
+}
+
+// askProperty prompts for the value of a Kamelet property until it is valid against the property schema.
+func (w *bindWizard) askProperty(kamelet string, name string, prop v1alpha1.JSONSchemaProp) (string, error) {
+	label := name
+	if prop.Title != "" {
+		label = fmt.Sprintf("%s (%s)", prop.Title, name)
+	}
+	if prop.Description != "" {
+		fmt.Fprintf(w.out, "%s: %s\n", label, prop.Description)
+	}
+	def := schemaValueString(prop.Default)
+	prompt := fmt.Sprintf("%s %s", kamelet, label)
+	if len(prop.Enum) > 0 {
+		choices := make([]string, 0, len(prop.Enum))
+		for i := range prop.Enum {
+			choices = append(choices, schemaValueString(&prop.Enum[i]))
+		}
+		prompt += fmt.Sprintf(" {%s}", strings.Join(choices, "|"))
+	}
+	if def != "" {
+		prompt += fmt.Sprintf(" [%s]", def)
+	}
+	prompt += ": "
+
+	for {
+		var value string
+		var err error
+		if isSensitiveProperty(prop) {
+			value, err = w.askSensitive(prompt)
+		} else {
+			value, err = w.ask(prompt)
+		}
+		if err != nil {
+			return "", err
+		}
+		if value == "" {
+			value = def
+		}
+		if value == "" {
+			fmt.Fprintf(w.out, "property %q is required\n", name)
+			continue
+		}
+		if err := validateSchemaValue(prop, value); err != nil {
+			fmt.Fprintf(w.out, "invalid value for property %q: %v\n", name, err)
+			continue
+		}
+		return value, nil
+	}
+}
+
+// runWizard interactively completes the source, steps and sink of the binding, with the required properties of
+// the selected Kamelets. Sensitive properties can be stored in a Secret mounted by the integration.
+func (o *bindCmdOptions) runWizard(cmd *cobra.Command, args []string) ([]string, error) {
+	if len(args) > 2 {
+		return nil, errors.New("too many arguments: expected source and sink")
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return nil, err
+	}
+	list := v1alpha1.NewKameletList()
+	if err := c.List(o.Context, &list, client.InNamespace(o.Namespace)); err != nil {
+		return nil, err
+	}
+	kamelets := list.Items
+	sort.Slice(kamelets, func(i, j int) bool {
+		return kamelets[i].Name < kamelets[j].Name
+	})
+
+	w := newBindWizard(cmd)
+	endpoints := append([]string{}, args...)
+	if len(endpoints) < 1 {
+		source, err := w.selectEndpoint(kamelets, v1alpha1.KameletTypeSource, o.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, source)
+	}
+	if len(o.Steps) == 0 && len(args) < 2 {
+		if o.Steps, err = w.selectActions(kamelets, o.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if len(endpoints) < 2 {
+		sink, err := w.selectEndpoint(kamelets, v1alpha1.KameletTypeSink, o.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, sink)
+	}
+
+	if o.Name == "" {
+		source, err := o.decode(endpoints[0], sourceKey)
+		if err != nil {
+			return nil, err
+		}
+		sink, err := o.decode(endpoints[1], sinkKey)
+		if err != nil {
+			return nil, err
+		}
+		def := o.nameFor(source, sink)
+		for o.Name == "" {
+			name, err := w.ask(fmt.Sprintf("Binding name [%s]: ", def))
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				name = def
+			}
+			if kubernetes.SanitizeName(name) != name {
+				fmt.Fprintf(w.out, "%q is not a valid name, try %q\n", name, kubernetes.SanitizeName(name))
+				continue
+			}
+			o.Name = name
+		}
+	}
+
+	keys := []string{sourceKey}
+	values := []string{endpoints[0]}
+	for idx, step := range o.Steps {
+		keys = append(keys, fmt.Sprintf("%s%d", stepKeyPrefix, idx))
+		values = append(values, step)
+	}
+	keys = append(keys, sinkKey)
+	values = append(values, endpoints[1])
+
+	secretData := make(map[string]string)
+	for i, key := range keys {
+		endpoint, err := o.decode(values[i], key)
+		if err != nil {
+			return nil, err
+		}
+		if endpoint.Ref == nil || endpoint.Ref.Kind != "Kamelet" {
+			continue
+		}
+		for j := range kamelets {
+			if kamelets[j].Name == endpoint.Ref.Name {
+				if err := o.promptProperties(w, key, &kamelets[j], secretData); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	if len(secretData) > 0 {
+		o.secret = &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: o.Namespace,
+				Name:      o.Name + "-credentials",
+			},
+			StringData: secretData,
+		}
+		o.Traits = append(o.Traits, fmt.Sprintf("mount.configs=secret:%s", o.secret.Name))
+	}
+
+	return endpoints, nil
+}
+
+// promptProperties prompts for the required properties of the Kamelet that are not already set with --property.
+func (o *bindCmdOptions) promptProperties(w *bindWizard, key string, kamelet *v1alpha1.Kamelet, secretData map[string]string) error {
+	if kamelet.Spec.Definition == nil {
+		return nil
+	}
+	explicit := o.getProperties(key)
+	for _, name := range kamelet.Spec.Definition.Required {
+		if _, ok := explicit[name]; ok {
+			continue
+		}
+		prop := kamelet.Spec.Definition.Properties[name]
+		value, err := w.askProperty(kamelet.Name, name, prop)
+		if err != nil {
+			return err
+		}
+		if isSensitiveProperty(prop) {
+			store, err := w.confirm(fmt.Sprintf("Store %q in a Secret instead of the binding?", name))
+			if err != nil {
+				return err
+			}
+			if store {
+				secretKey := fmt.Sprintf("%s.%s", key, name)
+				secretData[secretKey] = value
+				value = fmt.Sprintf("{{%s}}", secretKey)
+			}
+		}
+		o.Properties = append(o.Properties, fmt.Sprintf("%s.%s=%s", key, name, value))
+	}
+	return nil
+}
+
+func kameletsOfType(kamelets []v1alpha1.Kamelet, kameletType string) []v1alpha1.Kamelet {
+	res := make([]v1alpha1.Kamelet, 0)
+	for _, k := range kamelets {
+		if k.Labels[v1alpha1.KameletTypeLabel] == kameletType {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+// resolveEndpoint resolves an answer given as the number of a listed Kamelet, the name of a Kamelet or a Camel URI.
+func resolveEndpoint(candidates []v1alpha1.Kamelet, kamelets []v1alpha1.Kamelet, answer string) (string, bool) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n >= 1 && n <= len(candidates) {
+			return candidates[n-1].Name, true
+		}
+		return "", false
+	}
+	for _, k := range kamelets {
+		if k.Name == answer {
+			return answer, true
+		}
+	}
+	return answer, uri.HasCamelURIFormat(answer)
+}
+
+func isSensitiveProperty(prop v1alpha1.JSONSchemaProp) bool {
+	return prop.Format == "password" || util.StringSliceExists(prop.XDescriptors, passwordDescriptor)
+}
+
+func schemaValueString(value *v1alpha1.JSON) string {
+	if value == nil || len(value.RawMessage) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(value.RawMessage, &v); err != nil {
+		return string(value.RawMessage)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// validateSchemaValue checks the value against the type, enumeration, bounds, length and pattern of the property schema.
+func validateSchemaValue(prop v1alpha1.JSONSchemaProp, value string) error {
+	if len(prop.Enum) > 0 {
+		found := false
+		for i := range prop.Enum {
+			if schemaValueString(&prop.Enum[i]) == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("must be one of the enumerated values")
+		}
+	}
+
+	switch prop.Type {
+	case "integer", "number":
+		var n float64
+		var err error
+		if prop.Type == "integer" {
+			var i int64
+			i, err = strconv.ParseInt(value, 10, 64)
+			n = float64(i)
+		} else {
+			n, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("must be of type %s", prop.Type)
+		}
+		if prop.Minimum != nil {
+			if min, err := prop.Minimum.Float64(); err == nil && (n < min || (prop.ExclusiveMinimum && n == min)) {
+				return fmt.Errorf("must be greater than %s", prop.Minimum)
+			}
+		}
+		if prop.Maximum != nil {
+			if max, err := prop.Maximum.Float64(); err == nil && (n > max || (prop.ExclusiveMaximum && n == max)) {
+				return fmt.Errorf("must be less than %s", prop.Maximum)
+			}
+		}
+		if prop.MultipleOf != nil {
+			if m, err := prop.MultipleOf.Float64(); err == nil && m != 0 && math.Mod(n, m) != 0 {
+				return fmt.Errorf("must be a multiple of %s", prop.MultipleOf)
+			}
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("must be of type boolean")
+		}
+	default:
+		length := int64(utf8.RuneCountInString(value))
+		if prop.MinLength != nil && length < *prop.MinLength {
+			return fmt.Errorf("must be at least %d characters long", *prop.MinLength)
+		}
+		if prop.MaxLength != nil && length > *prop.MaxLength {
+			return fmt.Errorf("must be at most %d characters long", *prop.MaxLength)
+		}
+		if prop.Pattern != "" {
+			re, err := regexp.Compile(prop.Pattern)
+			if err == nil && !re.MatchString(value) {
+				return fmt.Errorf("must match pattern %q", prop.Pattern)
+			}
+		}
+	}
+
+	return nil
+}
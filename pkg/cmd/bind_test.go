@@ -18,11 +18,16 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	"github.com/apache/camel-k/pkg/util/test"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
 )
 
 const cmdBind = "bind"
@@ -188,3 +193,90 @@ spec:
 status: {}
 `, output)
 }
+
+func TestBindInteractive(t *testing.T) {
+	_, bindCmd, options := initializeBindCmdOptions(t)
+	kamelets := []*v1alpha1.Kamelet{
+		testBindKamelet("timer-source", v1alpha1.KameletTypeSource, "message", map[string]v1alpha1.JSONSchemaProp{
+			"message": {Type: "string", MinLength: pointer.Int64(1)},
+		}),
+		testBindKamelet("kafka-sink", v1alpha1.KameletTypeSink, "topic,password", map[string]v1alpha1.JSONSchemaProp{
+			"topic":    {Type: "string", Title: "Topic Names"},
+			"password": {Type: "string", Format: "password"},
+		}),
+		testBindKamelet("log-sink", v1alpha1.KameletTypeSink, "", nil),
+	}
+	for _, k := range kamelets {
+		assert.Nil(t, options._client.Create(context.TODO(), k))
+	}
+
+	bindCmd.SetIn(strings.NewReader("1\nfoo\n1\n\n\nhello\nmy-topic\nsecret123\ny\n"))
+	output, err := test.ExecuteCommand(bindCmd, cmdBind, "--interactive", "-o", "yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, output, `Available source Kamelets:
+  1) timer-source
+Select the source (number, Kamelet name or Camel URI): `)
+	assert.Contains(t, output, `"foo" is neither a listed Kamelet nor a Camel URI`)
+	assert.Contains(t, output, `Available sink Kamelets:
+  1) kafka-sink
+  2) log-sink`)
+	assert.Contains(t, output, `property "message" is required`)
+	assert.Contains(t, output, `kind: Secret
+metadata:
+  creationTimestamp: null
+  name: timer-source-to-kafka-sink-credentials
+stringData:
+  sink.password: secret123
+---`)
+	assert.Contains(t, output, `  name: timer-source-to-kafka-sink
+spec:
+  integration:
+    traits:
+      mount:
+        configuration:
+          configs:
+          - secret:timer-source-to-kafka-sink-credentials
+  sink:
+    properties:
+      password: '{{sink.password}}'
+      topic: my-topic
+    ref:
+      apiVersion: camel.apache.org/v1alpha1
+      kind: Kamelet
+      name: kafka-sink
+  source:
+    properties:
+      message: hello
+    ref:
+      apiVersion: camel.apache.org/v1alpha1
+      kind: Kamelet
+      name: timer-source
+`)
+}
+
+func TestBindValidateSchemaValue(t *testing.T) {
+	max := json.Number("10")
+	assert.Nil(t, validateSchemaValue(v1alpha1.JSONSchemaProp{Type: "integer", Maximum: &max}, "10"))
+	assert.EqualError(t, validateSchemaValue(v1alpha1.JSONSchemaProp{Type: "integer", Maximum: &max}, "11"), "must be less than 10")
+	assert.EqualError(t, validateSchemaValue(v1alpha1.JSONSchemaProp{Type: "integer"}, "1.5"), "must be of type integer")
+	assert.EqualError(t, validateSchemaValue(v1alpha1.JSONSchemaProp{Type: "boolean"}, "maybe"), "must be of type boolean")
+	assert.EqualError(t, validateSchemaValue(v1alpha1.JSONSchemaProp{Type: "string", Pattern: "^[a-z]+$"}, "ABC"), `must match pattern "^[a-z]+$"`)
+	assert.EqualError(t, validateSchemaValue(v1alpha1.JSONSchemaProp{
+		Type: "string",
+		Enum: []v1alpha1.JSON{{RawMessage: v1alpha1.RawMessage(`"a"`)}, {RawMessage: v1alpha1.RawMessage(`"b"`)}},
+	}, "c"), "must be one of the enumerated values")
+}
+
+func testBindKamelet(name string, kameletType string, required string, props map[string]v1alpha1.JSONSchemaProp) *v1alpha1.Kamelet {
+	kamelet := v1alpha1.NewKamelet("default", name)
+	kamelet.Labels = map[string]string{
+		v1alpha1.KameletTypeLabel: kameletType,
+	}
+	kamelet.Spec.Definition = &v1alpha1.JSONSchemaProps{
+		Properties: props,
+	}
+	if required != "" {
+		kamelet.Spec.Definition.Required = strings.Split(required, ",")
+	}
+	return &kamelet
+}
@@ -0,0 +1,235 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+// lastAppliedConfigurationAnnotation is set by kubectl apply, and must not be carried over to a clone.
+const lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+func newCmdClone(rootCmdOptions *RootCmdOptions) (*cobra.Command, *cloneCmdOptions) {
+	options := cloneCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:   "clone integration --name [name] ...",
+		Short: "Clone an Integration",
+		Long: `Create a copy of an Integration, with the given Camel properties and trait properties overridden, ` +
+			`e.g., to run the same routes for another tenant or region.`,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().String("name", "", "The name of the clone")
+	cmd.Flags().StringArray("set", nil, "Override a Camel property of the clone, as <key=value>")
+	cmd.Flags().StringArrayP("trait", "t", nil, "Override a trait property of the clone, as <trait>.<property>=<value>")
+	cmd.Flags().Bool("reuse-kit", false, "Run the clone with the kit of the cloned Integration, instead of looking up or building one")
+	cmd.Flags().StringP("output", "o", "", "Output format. One of: json|yaml")
+
+	return &cmd, &options
+}
+
+type cloneCmdOptions struct {
+	*RootCmdOptions
+	Name         string   `mapstructure:"name"`
+	Set          []string `mapstructure:"set"`
+	Traits       []string `mapstructure:"traits"`
+	ReuseKit     bool     `mapstructure:"reuse-kit"`
+	OutputFormat string   `mapstructure:"output"`
+}
+
+func (o *cloneCmdOptions) validate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("clone expects an Integration name argument")
+	}
+	if o.Name == "" {
+		return errors.New("clone expects the name of the clone as --name argument")
+	}
+	if o.Name == args[0] {
+		return errors.New("the clone must have a different name than the cloned Integration")
+	}
+	if errs := validation.IsDNS1123Subdomain(o.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid clone name %q: %s", o.Name, strings.Join(errs, ", "))
+	}
+	for _, s := range o.Set {
+		if !strings.Contains(s, "=") {
+			return fmt.Errorf("invalid property %q, expected <key=value>", s)
+		}
+	}
+	return nil
+}
+
+func (o *cloneCmdOptions) run(cmd *cobra.Command, args []string) error {
+	if err := o.validate(args); err != nil {
+		return err
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	source := v1.NewIntegration(o.Namespace, args[0])
+	key := k8sclient.ObjectKey{
+		Name:      args[0],
+		Namespace: o.Namespace,
+	}
+	if err := c.Get(o.Context, key, &source); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not find integration %s in namespace %s", args[0], o.Namespace))
+	}
+
+	catalog := trait.NewCatalog(c)
+	if err := validateTraits(catalog, o.Traits); err != nil {
+		return err
+	}
+	overrides, err := configureTraits(o.Traits, catalog)
+	if err != nil {
+		return err
+	}
+
+	clone, err := o.clone(&source, overrides)
+	if err != nil {
+		return err
+	}
+
+	if o.OutputFormat != "" {
+		return showIntegrationOutput(cmd, clone, o.OutputFormat, c.GetScheme())
+	}
+
+	if err := c.Create(o.Context, clone); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not create integration %s in namespace %s", clone.Name, o.Namespace))
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Integration \"%s\" created from \"%s\"\n", clone.Name, source.Name)
+	return nil
+}
+
+// clone returns a copy of the source Integration, with the overridden Camel properties and traits.
+func (o *cloneCmdOptions) clone(source *v1.Integration, traits map[string]v1.TraitSpec) (*v1.Integration, error) {
+	clone := v1.NewIntegration(o.Namespace, o.Name)
+	for k, v := range source.Labels {
+		if clone.Labels == nil {
+			clone.Labels = make(map[string]string)
+		}
+		clone.Labels[k] = v
+	}
+	for k, v := range source.Annotations {
+		if k == lastAppliedConfigurationAnnotation {
+			continue
+		}
+		if clone.Annotations == nil {
+			clone.Annotations = make(map[string]string)
+		}
+		clone.Annotations[k] = v
+	}
+	clone.Spec = *source.Spec.DeepCopy()
+
+	if o.ReuseKit {
+		if source.Status.IntegrationKit == nil {
+			return nil, fmt.Errorf("integration %s has no kit to reuse", source.Name)
+		}
+		clone.Spec.IntegrationKit = source.Status.IntegrationKit.DeepCopy()
+	}
+
+	if clone.Spec.Traits == nil {
+		clone.Spec.Traits = make(map[string]v1.TraitSpec)
+	}
+	for id, spec := range traits {
+		merged, err := mergeTraitSpec(clone.Spec.Traits[id], spec, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not override trait %s", id)
+		}
+		clone.Spec.Traits[id] = merged
+	}
+	if len(o.Set) > 0 {
+		merged, err := mergeTraitSpec(clone.Spec.Traits["camel"], v1.TraitSpec{}, o.Set)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not override the Camel properties")
+		}
+		clone.Spec.Traits["camel"] = merged
+	}
+	if len(clone.Spec.Traits) == 0 {
+		clone.Spec.Traits = nil
+	}
+
+	return &clone, nil
+}
+
+// mergeTraitSpec overrides the properties of the base trait configuration with those of the given configuration.
+// The properties, in the <key=value> form, replace the entries of the `properties` list with the same key.
+func mergeTraitSpec(base v1.TraitSpec, override v1.TraitSpec, properties []string) (v1.TraitSpec, error) {
+	config := make(map[string]interface{})
+	if len(base.Configuration.RawMessage) > 0 {
+		if err := json.Unmarshal(base.Configuration.RawMessage, &config); err != nil {
+			return base, err
+		}
+	}
+	if len(override.Configuration.RawMessage) > 0 {
+		overrideConfig := make(map[string]interface{})
+		if err := json.Unmarshal(override.Configuration.RawMessage, &overrideConfig); err != nil {
+			return base, err
+		}
+		for k, v := range overrideConfig {
+			config[k] = v
+		}
+	}
+
+	if len(properties) > 0 {
+		var merged []interface{}
+		if existing, ok := config["properties"].([]interface{}); ok {
+			for _, p := range existing {
+				if s, ok := p.(string); !ok || !hasPropertyKey(properties, s) {
+					merged = append(merged, p)
+				}
+			}
+		}
+		for _, p := range properties {
+			merged = append(merged, p)
+		}
+		config["properties"] = merged
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return base, err
+	}
+	return v1.TraitSpec{Configuration: v1.TraitConfiguration{RawMessage: data}}, nil
+}
+
+func hasPropertyKey(properties []string, property string) bool {
+	key := strings.SplitN(property, "=", 2)[0]
+	for _, p := range properties {
+		if strings.SplitN(p, "=", 2)[0] == key {
+			return true
+		}
+	}
+	return false
+}
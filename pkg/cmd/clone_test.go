@@ -0,0 +1,155 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdClone = "clone"
+
+func initializeCloneCmdOptions(t *testing.T, c client.Client) *cobra.Command {
+	t.Helper()
+
+	options := RootCmdOptions{
+		Context:   context.Background(),
+		Namespace: "default",
+		_client:   c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	cloneCmd, _ := newCmdClone(&options)
+	rootCmd.AddCommand(cloneCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return rootCmd
+}
+
+func newCloneTestClient(t *testing.T) client.Client {
+	t.Helper()
+
+	it := v1.NewIntegration("default", "orders-eu")
+	it.Labels = map[string]string{"team": "shop"}
+	it.Annotations = map[string]string{
+		v1.OperatorIDAnnotation:            "operator-a",
+		lastAppliedConfigurationAnnotation: "{}",
+	}
+	it.Spec.Sources = []v1.SourceSpec{v1.NewSourceSpec("routes.yaml", "- from: timer:tick", v1.LanguageYaml)}
+	it.Spec.Traits = map[string]v1.TraitSpec{
+		"camel": traitSpec(t, map[string]interface{}{
+			"properties": []string{"region=eu", "tenant=acme"},
+		}),
+		"container": traitSpec(t, map[string]interface{}{
+			"requestMemory": "256Mi",
+		}),
+	}
+	it.Status.IntegrationKit = &corev1.ObjectReference{Namespace: "default", Name: "kit-orders"}
+
+	c, err := test.NewFakeClient(&it)
+	assert.Nil(t, err)
+	return c
+}
+
+func traitSpec(t *testing.T, config map[string]interface{}) v1.TraitSpec {
+	t.Helper()
+
+	data, err := json.Marshal(config)
+	assert.Nil(t, err)
+	return v1.TraitSpec{Configuration: v1.TraitConfiguration{RawMessage: data}}
+}
+
+func traitConfig(t *testing.T, it *v1.Integration, id string) map[string]interface{} {
+	t.Helper()
+
+	config := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(it.Spec.Traits[id].Configuration.RawMessage, &config))
+	return config
+}
+
+func TestCloneIntegration(t *testing.T) {
+	c := newCloneTestClient(t)
+	rootCmd := initializeCloneCmdOptions(t, c)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdClone, "orders-eu", "--name", "orders-us",
+		"--set", "region=us", "--set", "currency=usd", "-t", "container.request-cpu=500m")
+	assert.Nil(t, err)
+	assert.Equal(t, "Integration \"orders-us\" created from \"orders-eu\"\n", output)
+
+	clone := v1.NewIntegration("default", "orders-us")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&clone), &clone))
+	assert.Equal(t, "shop", clone.Labels["team"])
+	assert.Equal(t, "operator-a", clone.Annotations[v1.OperatorIDAnnotation])
+	assert.NotContains(t, clone.Annotations, lastAppliedConfigurationAnnotation)
+	assert.Len(t, clone.Spec.Sources, 1)
+	assert.Nil(t, clone.Spec.IntegrationKit)
+
+	assert.ElementsMatch(t, []interface{}{"tenant=acme", "region=us", "currency=usd"}, traitConfig(t, &clone, "camel")["properties"])
+	container := traitConfig(t, &clone, "container")
+	assert.Equal(t, "256Mi", container["requestMemory"])
+	assert.Equal(t, "500m", container["requestCPU"])
+}
+
+func TestCloneIntegrationReusingKit(t *testing.T) {
+	c := newCloneTestClient(t)
+	rootCmd := initializeCloneCmdOptions(t, c)
+
+	_, err := test.ExecuteCommand(rootCmd, cmdClone, "orders-eu", "--name", "orders-us", "--reuse-kit")
+	assert.Nil(t, err)
+
+	clone := v1.NewIntegration("default", "orders-us")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&clone), &clone))
+	assert.NotNil(t, clone.Spec.IntegrationKit)
+	assert.Equal(t, "kit-orders", clone.Spec.IntegrationKit.Name)
+}
+
+func TestCloneIntegrationOutput(t *testing.T) {
+	c := newCloneTestClient(t)
+	rootCmd := initializeCloneCmdOptions(t, c)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdClone, "orders-eu", "--name", "orders-us", "-o", "yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "name: orders-us")
+
+	list := v1.NewIntegrationList()
+	assert.Nil(t, c.List(context.TODO(), &list))
+	assert.Len(t, list.Items, 1)
+}
+
+func TestCloneIntegrationInvalidName(t *testing.T) {
+	c := newCloneTestClient(t)
+	rootCmd := initializeCloneCmdOptions(t, c)
+
+	_, err := test.ExecuteCommand(rootCmd, cmdClone, "orders-eu", "--name", "orders-eu")
+	assert.NotNil(t, err)
+
+	_, err = test.ExecuteCommand(rootCmd, cmdClone, "orders-eu", "--name", "Orders_US")
+	assert.NotNil(t, err)
+}
@@ -82,6 +82,7 @@ func newCmdDescribe(rootCmdOptions *RootCmdOptions) *cobra.Command {
 		Long:  `Describe a Camel K resource.`,
 	}
 
+	cmd.AddCommand(cmdOnly(newDescribeBuildCmd(rootCmdOptions)))
 	cmd.AddCommand(cmdOnly(newDescribeKitCmd(rootCmdOptions)))
 	cmd.AddCommand(cmdOnly(newDescribeIntegrationCmd(rootCmdOptions)))
 	cmd.AddCommand(cmdOnly(newDescribePlatformCmd(rootCmdOptions)))
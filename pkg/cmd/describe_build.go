@@ -0,0 +1,148 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/indentedwriter"
+)
+
+func newDescribeBuildCmd(rootCmdOptions *RootCmdOptions) (*cobra.Command, *describeBuildCommandOptions) {
+	options := describeBuildCommandOptions{
+		rootCmdOptions,
+	}
+
+	cmd := cobra.Command{
+		Use:     "build",
+		Short:   "Describe a Build",
+		Long:    `Describe a Build, with the timeline of its stages.`,
+		PreRunE: decode(&options),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(cmd, args); err != nil {
+				return err
+			}
+			if err := options.run(cmd, args); err != nil {
+				fmt.Fprintln(cmd.ErrOrStderr(), err.Error())
+			}
+
+			return nil
+		},
+	}
+
+	return &cmd, &options
+}
+
+type describeBuildCommandOptions struct {
+	*RootCmdOptions
+}
+
+func (command *describeBuildCommandOptions) validate(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("describe expects a build name argument")
+	}
+	return nil
+}
+
+func (command *describeBuildCommandOptions) run(cmd *cobra.Command, args []string) error {
+	c, err := command.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	build := v1.NewBuild(command.Namespace, args[0])
+	buildKey := ctrl.ObjectKey{
+		Namespace: command.Namespace,
+		Name:      args[0],
+	}
+
+	if err := c.Get(command.Context, buildKey, build); err == nil {
+		if desc, err := command.describeBuild(cmd, build); err == nil {
+			fmt.Fprint(cmd.OutOrStdout(), desc)
+		} else {
+			fmt.Fprintln(cmd.ErrOrStderr(), err)
+		}
+	} else {
+		fmt.Fprintln(cmd.OutOrStdout(), "Build '"+args[0]+"' does not exist")
+	}
+
+	return nil
+}
+
+func (command *describeBuildCommandOptions) describeBuild(cmd *cobra.Command, build *v1.Build) (string, error) {
+	return indentedwriter.IndentedString(func(out io.Writer) error {
+		w := indentedwriter.NewWriter(cmd.OutOrStdout())
+
+		describeObjectMeta(w, build.ObjectMeta)
+
+		w.Writef(0, "Phase:\t%s\n", build.Status.Phase)
+		w.Writef(0, "Strategy:\t%s\n", build.Spec.Strategy)
+		if build.Status.Pod != "" {
+			w.Writef(0, "Pod:\t%s\n", build.Status.Pod)
+		}
+		w.Writef(0, "Base Image:\t%s\n", build.Status.BaseImage)
+		w.Writef(0, "Image:\t%s\n", build.Status.Image)
+		w.Writef(0, "Digest:\t%s\n", build.Status.Digest)
+		if build.Status.StartedAt != nil {
+			w.Writef(0, "Started At:\t%s\n", build.Status.StartedAt.Format(time.RFC1123Z))
+		}
+		w.Writef(0, "Duration:\t%s\n", build.Status.Duration)
+		if build.Status.Error != "" {
+			w.Writef(0, "Error:\t%s\n", build.Status.Error)
+		}
+
+		if len(build.Status.Stages) > 0 {
+			w.Writef(0, "Stages:\n")
+			w.Writef(1, "TASK\tSTAGE\tSTARTED\tDURATION\tCACHE\n")
+			for _, stage := range build.Status.Stages {
+				started := ""
+				if stage.StartedAt != nil {
+					started = stage.StartedAt.Format(time.RFC3339)
+				}
+				w.Writef(1, "%s\t%s\t%s\t%s\t%s\n", stage.Task, stage.Name, started, stage.Duration, describeStageCache(stage))
+			}
+		}
+
+		return nil
+	})
+}
+
+func describeStageCache(stage v1.BuildStage) string {
+	cache := ""
+	if stage.CacheHit != nil {
+		cache = "miss"
+		if *stage.CacheHit {
+			cache = "hit"
+		}
+	}
+	if stage.Message != "" {
+		if cache != "" {
+			cache += ": "
+		}
+		cache += stage.Message
+	}
+	return cache
+}
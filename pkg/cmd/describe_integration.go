@@ -25,10 +25,20 @@ import (
 
 	"github.com/spf13/cobra"
 
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/metadata"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/dsl"
 	"github.com/apache/camel-k/pkg/util/indentedwriter"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
 )
 
 func newDescribeIntegrationCmd(rootCmdOptions *RootCmdOptions) (*cobra.Command, *describeIntegrationCommandOptions) {
@@ -55,19 +65,27 @@ func newDescribeIntegrationCmd(rootCmdOptions *RootCmdOptions) (*cobra.Command,
 	}
 
 	cmd.Flags().BoolVar(&options.showSourceContent, "show-source-content", false, "Print source content")
+	cmd.Flags().BoolVar(&options.showEffectiveConfig, "show-effective-config", false, "Print the environment variables and properties resolved from the platform and integration configuration")
+	cmd.Flags().StringVar(&options.graph, "graph", "", "Print the graph of the routes, statically extracted from the sources, instead of the description. One of: dot|mermaid, e.g. --graph=mermaid")
+	cmd.Flags().Lookup("graph").NoOptDefVal = "dot"
 
 	return &cmd, &options
 }
 
 type describeIntegrationCommandOptions struct {
 	*RootCmdOptions
-	showSourceContent bool `mapstructure:"show-source-content"`
+	showSourceContent   bool   `mapstructure:"show-source-content"`
+	showEffectiveConfig bool   `mapstructure:"show-effective-config"`
+	graph               string `mapstructure:"graph"`
 }
 
 func (command *describeIntegrationCommandOptions) validate(_ *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return errors.New("describe expects an integration name argument")
 	}
+	if command.graph != "" && command.graph != "dot" && command.graph != "mermaid" {
+		return fmt.Errorf("invalid graph format %q, should be one of: dot|mermaid", command.graph)
+	}
 	return nil
 }
 
@@ -84,7 +102,10 @@ func (command *describeIntegrationCommandOptions) run(cmd *cobra.Command, args [
 	}
 
 	if err := c.Get(command.Context, key, &ctx); err == nil {
-		if desc, err := command.describeIntegration(cmd, ctx); err == nil {
+		if command.graph != "" {
+			return command.describeGraph(cmd, c, ctx)
+		}
+		if desc, err := command.describeIntegration(cmd, c, ctx); err == nil {
 			fmt.Fprint(cmd.OutOrStdout(), desc)
 		} else {
 			fmt.Fprintln(cmd.ErrOrStderr(), err)
@@ -96,7 +117,7 @@ func (command *describeIntegrationCommandOptions) run(cmd *cobra.Command, args [
 	return nil
 }
 
-func (command *describeIntegrationCommandOptions) describeIntegration(cmd *cobra.Command, i v1.Integration) (string, error) {
+func (command *describeIntegrationCommandOptions) describeIntegration(cmd *cobra.Command, c client.Client, i v1.Integration) (string, error) {
 	return indentedwriter.IndentedString(func(out io.Writer) error {
 		w := indentedwriter.NewWriter(cmd.OutOrStdout())
 
@@ -112,6 +133,18 @@ func (command *describeIntegrationCommandOptions) describeIntegration(cmd *cobra
 		w.Writef(0, "Kit:\t%s\n", kit)
 		w.Writef(0, "Image:\t%s\n", i.Status.Image)
 		w.Writef(0, "Version:\t%s\n", i.Status.Version)
+		if i.Status.Vulnerabilities != nil {
+			w.Writef(0, "Vulnerabilities:\t%s\n", i.Status.Vulnerabilities.String())
+		}
+		if recommendation := i.Status.ResourceProfile.RecommendationString(); recommendation != "" {
+			w.Writef(0, "Resource Recommendation:\t%s\n", recommendation)
+		}
+		if recommendation := i.Status.ResourceRecommendation.String(); recommendation != "" {
+			w.Writef(0, "VPA Recommendation:\t%s\n", recommendation)
+		}
+		if rollout := i.Status.Rollout.String(); rollout != "" {
+			w.Writef(0, "Rollout:\t%s\n", rollout)
+		}
 
 		if len(i.Spec.Configuration) > 0 {
 			w.Writef(0, "Configuration:\n")
@@ -121,6 +154,12 @@ func (command *describeIntegrationCommandOptions) describeIntegration(cmd *cobra
 			}
 		}
 
+		if command.showEffectiveConfig {
+			if err := command.describeEffectiveConfiguration(w, c, &i); err != nil {
+				return err
+			}
+		}
+
 		if len(i.Status.Dependencies) > 0 {
 			w.Writef(0, "Dependencies:\n")
 			for _, dependency := range i.Status.Dependencies {
@@ -189,3 +228,80 @@ func (command *describeIntegrationCommandOptions) describeIntegration(cmd *cobra
 		return describeTraits(w, i.Spec.Traits)
 	})
 }
+
+// describeEffectiveConfiguration prints the environment variables and properties of the Integration, along with
+// the source they are resolved from. The environment variables injected by the traits are not included.
+func (command *describeIntegrationCommandOptions) describeEffectiveConfiguration(w *indentedwriter.Writer, c client.Client, i *v1.Integration) error {
+	pl, err := platform.GetForResource(command.Context, c, i)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		pl = nil
+	}
+
+	var kit *v1.IntegrationKit
+	if i.Status.IntegrationKit != nil {
+		kit, err = kubernetes.GetIntegrationKit(command.Context, c, i.Status.IntegrationKit.Name, i.GetIntegrationKitNamespace(pl))
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	env, properties, err := trait.EffectiveConfiguration(pl, kit, i)
+	if err != nil {
+		return err
+	}
+
+	if len(env) > 0 || len(properties) > 0 {
+		w.Writef(0, "Effective Configuration:\n")
+		w.Writef(1, "Type\tName\tValue\tSource\n")
+		for _, v := range env {
+			w.Writef(1, "env\t%s\t%s\t%s\n", v.Name, v.Value, v.Source)
+		}
+		for _, v := range properties {
+			w.Writef(1, "property\t%s\t%s\t%s\n", v.Name, v.Value, v.Source)
+		}
+	}
+
+	return nil
+}
+
+func (command *describeIntegrationCommandOptions) describeGraph(cmd *cobra.Command, c client.Client, i v1.Integration) error {
+	sources := i.Sources()
+	if len(i.Spec.Flows) > 0 && len(i.Status.GeneratedSources) == 0 {
+		// the flows are only turned into a generated source once the integration is initialized
+		content, err := dsl.ToYamlDSL(i.Spec.Flows)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, v1.SourceSpec{
+			DataSpec: v1.DataSpec{
+				Name:    "flows.yaml",
+				Content: string(content),
+			},
+		})
+	}
+	sources, err := kubernetes.ResolveSources(sources, func(name string) (*corev1.ConfigMap, error) {
+		return kubernetes.GetConfigMap(command.Context, c, name, i.Namespace)
+	})
+	if err != nil {
+		return err
+	}
+
+	catalog, err := camel.DefaultCatalog()
+	if err != nil {
+		return err
+	}
+	graph, err := metadata.Graph(catalog, sources)
+	if err != nil {
+		return err
+	}
+
+	if command.graph == "mermaid" {
+		fmt.Fprint(cmd.OutOrStdout(), graph.Mermaid())
+	} else {
+		fmt.Fprint(cmd.OutOrStdout(), graph.DOT(i.Name))
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -102,6 +103,12 @@ func (command *describeKitCommandOptions) describeIntegrationKit(cmd *cobra.Comm
 		w.Writef(0, "Runtime Version:\t%s\n", kit.Status.RuntimeVersion)
 		w.Writef(0, "Image:\t%s\n", kit.Status.Image)
 		w.Writef(0, "Version:\t%s\n", kit.Status.Version)
+		if kit.Status.Vulnerabilities != nil {
+			w.Writef(0, "Vulnerabilities:\t%s\n", kit.Status.Vulnerabilities.String())
+		}
+		if export := kit.Status.Export; export != nil {
+			w.Writef(0, "Export:\t%s:%s (%s)\n", export.PersistentVolumeClaim, export.Path, export.ArchiveDigest)
+		}
 
 		if len(kit.Status.Artifacts) > 0 {
 			w.Writef(0, "Artifacts:\t\n")
@@ -110,6 +117,13 @@ func (command *describeKitCommandOptions) describeIntegrationKit(cmd *cobra.Comm
 			}
 		}
 
+		if len(kit.Status.Capabilities) > 0 {
+			w.Writef(0, "Capabilities:\t\n")
+			for _, capability := range kit.Status.Capabilities {
+				w.Writef(1, "%s:\t%s\n", capability.Name, strings.Join(capability.Dependencies, ", "))
+			}
+		}
+
 		if len(kit.Spec.Configuration) > 0 {
 			w.Writef(0, "Configuration:\n")
 			for _, config := range kit.Spec.Configuration {
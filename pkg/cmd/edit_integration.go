@@ -0,0 +1,470 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/schema"
+	"github.com/apache/camel-k/pkg/trait/traittest"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+func newEditIntegrationCmd(rootCmdOptions *RootCmdOptions) (*cobra.Command, *editIntegrationCmdOptions) {
+	options := editIntegrationCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+
+	cmd := cobra.Command{
+		Use:     "integration <name>",
+		Aliases: []string{"it"},
+		Short:   "Edit an Integration",
+		Long: `Edit an Integration in the editor set by the KUBE_EDITOR, or the EDITOR, environment variable, vi by default.
+The traits configuration of the edited Integration is validated against the schema of the operator, and its sources
+are checked. The changes of the resources generated for the Integration are then printed, by applying the traits
+bundled with the CLI to the Integration, before and after the edit, against a fake cluster, and the edit is applied
+once confirmed. When the edit is invalid, or cannot be applied, it is kept in a temporary file.`,
+		Example: `  kamel edit integration hello
+  KUBE_EDITOR="code --wait" kamel edit it hello --dry-run`,
+		Args:    options.validate,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Validate the edit, and print the changes of the generated resources, without applying it")
+	cmd.Flags().BoolP("yes", "y", false, "Apply the edit without confirmation")
+	cmd.Flags().String("operator-namespace", "", "The namespace of the operator the traits schema is retrieved from, defaults to the namespace of the integration")
+	cmd.Flags().Int32("operator-port", 8082, "The port of the operator schema endpoint")
+
+	return &cmd, &options
+}
+
+type editIntegrationCmdOptions struct {
+	*RootCmdOptions
+	DryRun            bool   `mapstructure:"dry-run"`
+	Yes               bool   `mapstructure:"yes"`
+	OperatorNamespace string `mapstructure:"operator-namespace"`
+	OperatorPort      int32  `mapstructure:"operator-port"`
+}
+
+func (o *editIntegrationCmdOptions) validate(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("edit integration expects an integration name argument")
+	}
+
+	return nil
+}
+
+func (o *editIntegrationCmdOptions) run(cmd *cobra.Command, args []string) error {
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	it := v1.NewIntegration(o.Namespace, args[0])
+	if err := c.Get(o.Context, ctrl.ObjectKeyFromObject(&it), &it); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("integration %s not found in namespace %s", it.Name, it.Namespace)
+		}
+		return err
+	}
+	for _, ref := range it.OwnerReferences {
+		if ref.Kind == v1alpha1.KameletBindingKind {
+			return fmt.Errorf("integration %s is managed by Kamelet binding %s, edit the binding instead", it.Name, ref.Name)
+		}
+	}
+
+	original, err := kubernetes.ToYAML(editableIntegration(&it))
+	if err != nil {
+		return err
+	}
+	file, content, err := o.edit(original, it.Name+"-*.yaml")
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(content, original) {
+		fmt.Fprintln(cmd.OutOrStdout(), "Edit cancelled, no changes made")
+		return os.Remove(file)
+	}
+
+	if err := o.apply(cmd, c, &it, content); err != nil {
+		return fmt.Errorf("%w, the edit is kept in %s", err, file)
+	}
+
+	return os.Remove(file)
+}
+
+// editableIntegration returns the part of the Integration that can be edited, i.e., its labels, annotations and spec.
+func editableIntegration(it *v1.Integration) *v1.Integration {
+	e := v1.NewIntegration(it.Namespace, it.Name)
+	e.Labels = it.Labels
+	e.Annotations = it.Annotations
+	e.Spec = *it.Spec.DeepCopy()
+	return &e
+}
+
+// edit writes the content into a temporary file, matching the given pattern, opens it in the editor, and returns the
+// path of the file, and the edited content.
+func (o *editIntegrationCmdOptions) edit(content []byte, pattern string) (string, []byte, error) {
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", nil, err
+	}
+
+	editor := editorCommand()
+	e := exec.CommandContext(o.Context, editor[0], append(editor[1:], f.Name())...)
+	e.Stdin = os.Stdin
+	e.Stdout = os.Stdout
+	e.Stderr = os.Stderr
+	if err := e.Run(); err != nil {
+		os.Remove(f.Name())
+		return "", nil, fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	edited, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return "", nil, err
+	}
+
+	return f.Name(), edited, nil
+}
+
+// editorCommand returns the command line of the editor, set by the KUBE_EDITOR, or the EDITOR, environment variable.
+func editorCommand() []string {
+	for _, env := range []string{"KUBE_EDITOR", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(env)); len(editor) > 0 {
+			return editor
+		}
+	}
+	return []string{"vi"}
+}
+
+// apply validates the edited content, prints the changes of the generated resources, and updates the Integration
+// once confirmed.
+func (o *editIntegrationCmdOptions) apply(cmd *cobra.Command, c client.Client, it *v1.Integration, content []byte) error {
+	data, err := k8syaml.ToJSON(content)
+	if err != nil {
+		return fmt.Errorf("invalid integration: %w", err)
+	}
+	edited := v1.Integration{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Fail on misspelled fields, rather than dropping them silently
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&edited); err != nil {
+		return fmt.Errorf("invalid integration: %w", err)
+	}
+	if edited.Name != it.Name || edited.Namespace != it.Namespace {
+		return errors.New("the name and the namespace of the integration cannot be changed")
+	}
+
+	out := cmd.OutOrStdout()
+	violations, err := o.check(cmd, c, &edited)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		fmt.Fprintf(out, "Integration %s is invalid:\n", it.Name)
+		for _, v := range violations {
+			fmt.Fprintf(out, "    %s\n", v)
+		}
+		return fmt.Errorf("integration %s is invalid", it.Name)
+	}
+
+	target := it.DeepCopy()
+	target.Labels = edited.Labels
+	target.Annotations = edited.Annotations
+	target.Spec = edited.Spec
+
+	if err := o.preview(cmd, c, it, target); err != nil {
+		return err
+	}
+	if o.DryRun {
+		return nil
+	}
+	if !o.Yes {
+		ok, err := confirmEdit(cmd, fmt.Sprintf("Apply the edit of integration %s? [y/N]: ", it.Name))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(out, "Edit cancelled, no changes made")
+			return nil
+		}
+	}
+
+	if err := c.Update(o.Context, target); err != nil {
+		if k8serrors.IsConflict(err) {
+			return fmt.Errorf("integration %s has been modified since the edit started", it.Name)
+		}
+		return err
+	}
+	fmt.Fprintf(out, "Integration %s edited\n", it.Name)
+
+	return nil
+}
+
+// check validates the traits configuration of the Integration against the traits schema, and its sources.
+func (o *editIntegrationCmdOptions) check(cmd *cobra.Command, c client.Client, it *v1.Integration) ([]string, error) {
+	s, err := o.traitsSchema(cmd, c)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(it.Spec.Traits)
+	if err != nil {
+		return nil, err
+	}
+	var traits interface{}
+	if err := json.Unmarshal(data, &traits); err != nil {
+		return nil, err
+	}
+
+	violations := schema.Validate(s, traits, "spec.traits")
+	return append(violations, validateSources(it.Spec.Sources)...), nil
+}
+
+// traitsSchema returns the traits schema served by the operator, so that the addons it bundles are validated too, or
+// the one of the traits bundled with the CLI, when the operator cannot be reached.
+func (o *editIntegrationCmdOptions) traitsSchema(cmd *cobra.Command, c client.Client) (map[string]interface{}, error) {
+	namespace := o.OperatorNamespace
+	if namespace == "" {
+		namespace = o.Namespace
+	}
+	data, err := fetchSchema(o.Context, c, namespace, o.OperatorPort, schema.TraitsSchema)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v, the traits are validated against the traits bundled with the CLI\n", err)
+		if data, err = schema.Generate(schema.TraitsSchema); err != nil {
+			return nil, err
+		}
+	}
+
+	s := make(map[string]interface{})
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	// The schema leaves the traits open, while unknown traits are ignored by the operator
+	s["additionalProperties"] = false
+
+	return s, nil
+}
+
+// validateSources checks that the sources have a name, a content and a known language, and that the YAML and XML
+// sources are well-formed.
+func validateSources(sources []v1.SourceSpec) []string {
+	violations := make([]string, 0)
+	for i, s := range sources {
+		path := fmt.Sprintf("spec.sources[%d]", i)
+		if s.Name == "" {
+			violations = append(violations, path+": missing name")
+		}
+		if s.Content == "" && len(s.RawContent) == 0 && s.ContentRef == "" {
+			violations = append(violations, path+": missing content, or content reference")
+			continue
+		}
+		language := s.InferLanguage()
+		if language == "" {
+			violations = append(violations, fmt.Sprintf("%s: unknown language, set the language, or use the extension of a supported language", path))
+			continue
+		}
+		if s.Content == "" || s.Compression || s.Type != v1.SourceTypeDefault {
+			continue
+		}
+		if err := checkSourceSyntax(language, s.Content); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: invalid %s source: %v", path, language, err))
+		}
+	}
+	return violations
+}
+
+func checkSourceSyntax(language v1.Language, content string) error {
+	switch language {
+	case v1.LanguageYaml:
+		var routes []interface{}
+		if err := yaml.Unmarshal([]byte(content), &routes); err != nil {
+			return err
+		}
+		for i, r := range routes {
+			if m, ok := r.(map[interface{}]interface{}); !ok || len(m) != 1 {
+				return fmt.Errorf("element %d must be a mapping with a single key, e.g., from or route", i)
+			}
+		}
+	case v1.LanguageXML:
+		decoder := xml.NewDecoder(strings.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// preview prints the changes of the resources generated for the Integration by the edit. The traits bundled with the
+// CLI are applied, against a fake cluster populated with the platform of the Integration, and a synthetic kit, so
+// that the resources only differ by the edit.
+func (o *editIntegrationCmdOptions) preview(cmd *cobra.Command, c client.Client, before *v1.Integration, after *v1.Integration) error {
+	t := traittest.TraitTest{}
+	if pl, err := platform.GetOrFindForResource(o.Context, c, before, true); err == nil && pl != nil {
+		spec := pl.Status.IntegrationPlatformSpec.DeepCopy()
+		// The runtime of the Camel catalog bundled with the CLI
+		spec.Build.RuntimeVersion = ""
+		t.Spec.Platform = spec
+	}
+	generate := func(it *v1.Integration) ([]unstructured.Unstructured, error) {
+		t.Spec.Integration = *it.DeepCopy()
+		t.Spec.Integration.Spec.IntegrationKit = nil
+		t.Spec.Integration.Status = v1.IntegrationStatus{}
+		return traittest.Generate(o.Context, t)
+	}
+
+	previous, err := generate(before)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the changes of the generated resources cannot be previewed: %v\n", err)
+		return nil
+	}
+	next, err := generate(after)
+	if err != nil {
+		return fmt.Errorf("the traits cannot be applied to the edited integration: %w", err)
+	}
+
+	diff, err := diffResources(previous, next)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Fprintln(cmd.OutOrStdout(), "No changes of the generated resources")
+		return nil
+	}
+	fmt.Fprint(cmd.OutOrStdout(), diff)
+
+	return nil
+}
+
+// diffResources returns the unified diff, in YAML, of the resources that are added, removed or changed.
+func diffResources(before []unstructured.Unstructured, after []unstructured.Unstructured) (string, error) {
+	index := func(resources []unstructured.Unstructured, keys map[string]bool) map[string]*unstructured.Unstructured {
+		m := make(map[string]*unstructured.Unstructured, len(resources))
+		for i := range resources {
+			key := resources[i].GetKind() + "/" + resources[i].GetName()
+			m[key] = &resources[i]
+			keys[key] = true
+		}
+		return m
+	}
+	keys := make(map[string]bool)
+	previous := index(before, keys)
+	next := index(after, keys)
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, k := range sorted {
+		from, err := resourceYAML(previous[k])
+		if err != nil {
+			return "", err
+		}
+		to, err := resourceYAML(next[k])
+		if err != nil {
+			return "", err
+		}
+		if from == to {
+			continue
+		}
+		diff := difflib.UnifiedDiff{
+			A:        difflib.SplitLines(from),
+			B:        difflib.SplitLines(to),
+			FromFile: "a/" + k,
+			ToFile:   "b/" + k,
+			Context:  3,
+		}
+		if from == "" {
+			diff.FromFile = "/dev/null"
+		}
+		if to == "" {
+			diff.ToFile = "/dev/null"
+		}
+		text, err := difflib.GetUnifiedDiffString(diff)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text)
+	}
+
+	return b.String(), nil
+}
+
+func resourceYAML(u *unstructured.Unstructured) (string, error) {
+	if u == nil {
+		return "", nil
+	}
+	data, err := util.MapToYAML(u.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func confirmEdit(cmd *cobra.Command, prompt string) (bool, error) {
+	fmt.Fprint(cmd.OutOrStdout(), prompt)
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
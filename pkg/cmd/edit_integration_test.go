@@ -0,0 +1,149 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdEdit = "edit"
+
+func initializeEditIntegrationCmdOptions(t *testing.T) (*cobra.Command, client.Client) {
+	t.Helper()
+
+	replicas := int32(1)
+	it := v1.NewIntegration("default", "hello")
+	it.Spec.Replicas = &replicas
+	it.Spec.Sources = []v1.SourceSpec{
+		v1.NewSourceSpec("hello.yaml", "- from:\n    uri: \"timer:tick\"\n    steps:\n    - to: \"log:info\"\n", ""),
+	}
+	it.Spec.Traits = map[string]v1.TraitSpec{
+		"container": test.TraitSpecFromMap(t, map[string]interface{}{
+			"port": 8080,
+		}),
+	}
+	c, err := test.NewFakeClient(&it)
+	assert.Nil(t, err)
+
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	rootCmd.AddCommand(newCmdEdit(&options))
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return rootCmd, c
+}
+
+func setEditor(t *testing.T, editor string) {
+	t.Helper()
+
+	previous, ok := os.LookupEnv("KUBE_EDITOR")
+	assert.Nil(t, os.Setenv("KUBE_EDITOR", editor))
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv("KUBE_EDITOR", previous)
+		} else {
+			os.Unsetenv("KUBE_EDITOR")
+		}
+	})
+}
+
+func replicasOf(t *testing.T, c client.Client) int32 {
+	t.Helper()
+
+	it := v1.NewIntegration("default", "hello")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&it), &it))
+	return *it.Spec.Replicas
+}
+
+func TestEditIntegration(t *testing.T) {
+	rootCmd, c := initializeEditIntegrationCmdOptions(t)
+	setEditor(t, `sed -i s/\(replicas:.\)1/\13/`)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdEdit, "integration", "hello", "-n", "default", "--yes")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "--- a/Deployment/hello")
+	assert.Contains(t, output, "-  replicas: 1")
+	assert.Contains(t, output, "+  replicas: 3")
+	assert.Contains(t, output, "Integration hello edited")
+	assert.Equal(t, int32(3), replicasOf(t, c))
+}
+
+func TestEditIntegrationDryRun(t *testing.T) {
+	rootCmd, c := initializeEditIntegrationCmdOptions(t)
+	setEditor(t, `sed -i s/\(replicas:.\)1/\13/`)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdEdit, "it", "hello", "-n", "default", "--dry-run")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "+  replicas: 3")
+	assert.NotContains(t, output, "Integration hello edited")
+	assert.Equal(t, int32(1), replicasOf(t, c))
+}
+
+func TestEditIntegrationUnchanged(t *testing.T) {
+	rootCmd, _ := initializeEditIntegrationCmdOptions(t)
+	setEditor(t, "true")
+
+	output, err := test.ExecuteCommand(rootCmd, cmdEdit, "integration", "hello", "-n", "default")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "Edit cancelled, no changes made")
+}
+
+func TestEditIntegrationInvalidTrait(t *testing.T) {
+	rootCmd, c := initializeEditIntegrationCmdOptions(t)
+	setEditor(t, "sed -i s/8080/not-a-port/")
+
+	output, err := test.ExecuteCommand(rootCmd, cmdEdit, "integration", "hello", "-n", "default", "--yes")
+	assert.NotNil(t, err)
+	assert.Contains(t, output, "spec.traits.container.configuration.port: expected integer, found string")
+	assert.Contains(t, err.Error(), "the edit is kept in")
+	assert.Equal(t, int32(1), replicasOf(t, c))
+}
+
+func TestValidateSources(t *testing.T) {
+	sources := []v1.SourceSpec{
+		v1.NewSourceSpec("valid.yaml", "- from:\n    uri: \"timer:tick\"\n", ""),
+		v1.NewSourceSpec("invalid.yaml", "from:\n  uri: \"timer:tick\"\n", ""),
+		v1.NewSourceSpec("invalid.xml", "<routes><route></routes>", ""),
+		v1.NewSourceSpec("unknown.txt", "hello", ""),
+		v1.NewSourceSpec("empty.groovy", "", ""),
+		v1.NewSourceSpec("", "from('timer:tick')", v1.LanguageGroovy),
+	}
+
+	violations := validateSources(sources)
+	assert.Len(t, violations, 5)
+	assert.Contains(t, violations[0], "spec.sources[1]: invalid yaml source")
+	assert.Contains(t, violations[1], "spec.sources[2]: invalid xml source")
+	assert.Equal(t, "spec.sources[3]: unknown language, set the language, or use the extension of a supported language", violations[2])
+	assert.Equal(t, "spec.sources[4]: missing content, or content reference", violations[3])
+	assert.Equal(t, "spec.sources[5]: missing name", violations[4])
+}
@@ -46,6 +46,8 @@ func newCmdGet(rootCmdOptions *RootCmdOptions) (*cobra.Command, *getCmdOptions)
 		RunE:    options.run,
 	}
 
+	cmd.AddCommand(cmdOnly(newCmdGetBindings(rootCmdOptions)))
+
 	return &cmd, &options
 }
 
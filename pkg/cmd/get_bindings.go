@@ -0,0 +1,96 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+)
+
+func newCmdGetBindings(rootCmdOptions *RootCmdOptions) (*cobra.Command, *getBindingsCmdOptions) {
+	options := getBindingsCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:     "bindings [binding]",
+		Aliases: []string{"kameletbindings", "klb"},
+		Short:   "Get Kamelet Bindings deployed on Kubernetes",
+		Long:    `Get the status of Kamelet Bindings deployed on Kubernetes, accounting for the health of their source and sink.`,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	return &cmd, &options
+}
+
+type getBindingsCmdOptions struct {
+	*RootCmdOptions
+}
+
+func (o *getBindingsCmdOptions) run(cmd *cobra.Command, args []string) error {
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	options := []k8sclient.ListOption{
+		k8sclient.InNamespace(o.Namespace),
+	}
+	if len(args) == 1 {
+		options = append(options, k8sclient.MatchingFields{
+			"metadata.name": args[0],
+		})
+	}
+
+	bindingList := v1alpha1.NewKameletBindingList()
+	if err := c.List(o.Context, &bindingList, options...); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "NAME\tPHASE\tREADY\tSOURCE\tSINK\tREASON")
+	for _, binding := range bindingList.Items {
+		ready, reason := "", ""
+		if condition := binding.Status.GetCondition(v1alpha1.KameletBindingConditionReady); condition != nil {
+			ready = string(condition.Status)
+			reason = condition.Reason
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			binding.Name,
+			string(binding.Status.Phase),
+			ready,
+			bindingConditionStatus(binding, v1alpha1.KameletBindingConditionSourceReady),
+			bindingConditionStatus(binding, v1alpha1.KameletBindingConditionSinkReady),
+			reason)
+	}
+
+	return w.Flush()
+}
+
+func bindingConditionStatus(binding v1alpha1.KameletBinding, conditionType v1alpha1.KameletBindingConditionType) string {
+	if condition := binding.Status.GetCondition(conditionType); condition != nil {
+		return string(condition.Status)
+	}
+	return ""
+}
@@ -19,16 +19,25 @@ package cmd
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
-	"github.com/apache/camel-k/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml2 "gopkg.in/yaml.v2"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/resources"
-	"github.com/spf13/cobra"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/uri"
 )
 
 func newCmdInit(rootCmdOptions *RootCmdOptions) (*cobra.Command, *initCmdOptions) {
@@ -38,7 +47,7 @@ func newCmdInit(rootCmdOptions *RootCmdOptions) (*cobra.Command, *initCmdOptions
 	cmd := cobra.Command{
 		Use:     "init [flags] IntegrationFile.java",
 		Short:   "Initialize empty Camel K files",
-		Long:    `Initialize empty Camel K integrations and other resources.`,
+		Long:    `Initialize empty Camel K integrations and other resources, such as Kamelets (*.kamelet.yaml) and Kamelet bindings (*.kameletbinding.yaml).`,
 		PreRunE: decode(&options),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := options.validate(cmd, args); err != nil {
@@ -54,11 +63,64 @@ func newCmdInit(rootCmdOptions *RootCmdOptions) (*cobra.Command, *initCmdOptions
 		},
 	}
 
+	cmd.Flags().String("from-openapi", "", "Generate the REST endpoints of a YAML integration from the given OpenAPI specification file")
+	cmd.Flags().String("kamelet-type", "", "The type of the Kamelet to scaffold. One of: source|sink|action")
+	cmd.Flags().String("kamelet-uri", "", "The Camel URI the scaffolded Kamelet consumes from (source) or produces to (sink and action)")
+	cmd.Flags().StringArray("kamelet-property", nil, `Add a property to the scaffolded Kamelet in the form of "<name>[:<type>][=<default>]". Properties without a default value are required`)
+	cmd.Flags().String("source", "", "The source of the scaffolded binding, either a Kamelet name or a plain Camel URI")
+	cmd.Flags().String("sink", "", "The sink of the scaffolded binding, either a Kamelet name or a plain Camel URI")
+	cmd.Flags().StringArrayP("property", "p", nil, `Add a binding property in the form of "source.<key>=<value>" or "sink.<key>=<value>"`)
+
 	return &cmd, &options
 }
 
+const (
+	kameletFileSuffix        = ".kamelet.yaml"
+	kameletBindingFileSuffix = ".kameletbinding.yaml"
+
+	kameletBindingTemplate  = "kameletbinding"
+	kameletScaffoldTemplate = "kamelet-scaffold"
+	openAPITemplate         = "openapi"
+)
+
+// defaultKameletURIs are the endpoints used by a scaffolded Kamelet, when no URI is provided.
+var defaultKameletURIs = map[string]string{
+	"source": "timer:tick",
+	"sink":   "log:info",
+	"action": "log:info",
+}
+
+// wellKnownKamelets lists the required properties of the Kamelets commonly used as binding sources and sinks,
+// so that the scaffolded bindings come with the properties to be filled in.
+var wellKnownKamelets = map[string][]string{
+	"aws-s3-sink":    {"bucketNameOrArn", "accessKey", "secretKey", "region"},
+	"aws-s3-source":  {"bucketNameOrArn", "accessKey", "secretKey", "region"},
+	"aws-sqs-sink":   {"queueNameOrArn", "accessKey", "secretKey", "region"},
+	"aws-sqs-source": {"queueNameOrArn", "accessKey", "secretKey", "region"},
+	"http-sink":      {"url"},
+	"kafka-sink":     {"topic", "bootstrapServers", "user", "password"},
+	"kafka-source":   {"topic", "bootstrapServers", "user", "password"},
+	"log-sink":       {},
+	"slack-sink":     {"channel", "webhookUrl"},
+	"timer-source":   {"message"},
+}
+
+// openAPIMethods are the HTTP methods supported by the REST DSL, in the order they are generated.
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+var kameletPropertyTypes = []string{"string", "integer", "number", "boolean"}
+
+var operationIDDisallowedChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
 type initCmdOptions struct {
 	*RootCmdOptions
+	FromOpenAPI       string   `mapstructure:"from-openapi" yaml:",omitempty"`
+	KameletType       string   `mapstructure:"kamelet-type" yaml:",omitempty"`
+	KameletURI        string   `mapstructure:"kamelet-uri" yaml:",omitempty"`
+	KameletProperties []string `mapstructure:"kamelet-properties" yaml:",omitempty"`
+	Source            string   `mapstructure:"source" yaml:",omitempty"`
+	Sink              string   `mapstructure:"sink" yaml:",omitempty"`
+	Properties        []string `mapstructure:"properties" yaml:",omitempty"`
 }
 
 func (o *initCmdOptions) validate(_ *cobra.Command, args []string) error {
@@ -67,40 +129,88 @@ func (o *initCmdOptions) validate(_ *cobra.Command, args []string) error {
 	}
 
 	fileName := args[0]
-	if o.extractLanguage(fileName) == nil {
-		return fmt.Errorf("unsupported file type: %s", fileName)
+	isBinding := strings.HasSuffix(fileName, kameletBindingFileSuffix)
+	var language *v1.Language
+	if !isBinding {
+		if language = o.extractLanguage(fileName); language == nil {
+			return fmt.Errorf("unsupported file type: %s", fileName)
+		}
+	}
+
+	if o.FromOpenAPI != "" && (language == nil || *language != v1.LanguageYaml) {
+		return errors.New("--from-openapi can only be used to initialize YAML integrations")
+	}
+	if o.hasKameletFlags() && (language == nil || *language != v1.LanguageKamelet) {
+		return fmt.Errorf("--kamelet-type, --kamelet-uri and --kamelet-property can only be used to initialize Kamelets (*%s)", kameletFileSuffix)
+	}
+	if o.hasBindingFlags() && !isBinding {
+		return fmt.Errorf("--source, --sink and --property can only be used to initialize Kamelet bindings (*%s)", kameletBindingFileSuffix)
+	}
+
+	if o.KameletType != "" {
+		if _, ok := defaultKameletURIs[o.KameletType]; !ok {
+			return fmt.Errorf("unsupported Kamelet type %q: must be one of source, sink or action", o.KameletType)
+		}
+	}
+	for _, p := range o.KameletProperties {
+		if _, err := parseKameletProperty(p); err != nil {
+			return err
+		}
+	}
+	for _, p := range o.Properties {
+		if _, _, _, err := parseBindingProperty(p); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (o *initCmdOptions) init(_ *cobra.Command, args []string) error {
+func (o *initCmdOptions) hasKameletFlags() bool {
+	return o.KameletType != "" || o.KameletURI != "" || len(o.KameletProperties) > 0
+}
+
+func (o *initCmdOptions) hasBindingFlags() bool {
+	return o.Source != "" || o.Sink != "" || len(o.Properties) > 0
+}
+
+func (o *initCmdOptions) init(cmd *cobra.Command, args []string) error {
 	fileName := args[0]
+	if strings.HasSuffix(fileName, kameletBindingFileSuffix) {
+		return o.initKameletBinding(cmd, fileName)
+	}
+
 	language := o.extractLanguage(fileName)
-	return o.writeFromTemplate(*language, fileName)
+	switch {
+	case *language == v1.LanguageKamelet && o.hasKameletFlags():
+		return o.initKamelet(fileName)
+	case *language == v1.LanguageYaml && o.FromOpenAPI != "":
+		return o.initFromOpenAPI(fileName)
+	}
+
+	return o.writeFromTemplate(string(*language), fileName, o.templateParameters(fileName))
 }
 
-func (o *initCmdOptions) writeFromTemplate(language v1.Language, fileName string) error {
+func (o *initCmdOptions) templateParameters(fileName string) map[string]interface{} {
 	simpleName := filepath.Base(fileName)
 	if idx := strings.Index(simpleName, "."); idx >= 0 {
 		simpleName = simpleName[:idx]
 	}
 
-	type TemplateParameters struct {
-		Name string
-	}
-	params := TemplateParameters{
-		Name: simpleName,
+	return map[string]interface{}{
+		"Name": simpleName,
 	}
+}
 
-	rawData, err := resources.ResourceAsString(fmt.Sprintf("/templates/%s.tmpl", language))
+func (o *initCmdOptions) writeFromTemplate(templateName string, fileName string, params map[string]interface{}) error {
+	rawData, err := resources.ResourceAsString(fmt.Sprintf("/templates/%s.tmpl", templateName))
 	if err != nil {
 		return err
 	}
 	if rawData == "" {
-		return fmt.Errorf("cannot find template for language %s", string(language))
+		return fmt.Errorf("cannot find template %s", templateName)
 	}
-	tmpl, err := template.New(string(language)).Parse(rawData)
+	tmpl, err := template.New(templateName).Parse(rawData)
 	if err != nil {
 		return err
 	}
@@ -110,8 +220,328 @@ func (o *initCmdOptions) writeFromTemplate(language v1.Language, fileName string
 	})
 }
 
+type kameletProperty struct {
+	Name    string
+	Title   string
+	Type    string
+	Default string
+}
+
+// parseKameletProperty parses a property in the form of "<name>[:<type>][=<default>]".
+func parseKameletProperty(value string) (kameletProperty, error) {
+	property := kameletProperty{
+		Type: "string",
+	}
+
+	def := ""
+	hasDefault := false
+	if idx := strings.Index(value, "="); idx >= 0 {
+		value, def, hasDefault = value[:idx], value[idx+1:], true
+	}
+	if idx := strings.Index(value, ":"); idx >= 0 {
+		value, property.Type = value[:idx], value[idx+1:]
+	}
+	property.Name = value
+	property.Title = titleFromName(value)
+
+	if property.Name == "" {
+		return property, fmt.Errorf(`property %q does not follow format "<name>[:<type>][=<default>]"`, value)
+	}
+	if !util.StringSliceExists(kameletPropertyTypes, property.Type) {
+		return property, fmt.Errorf("unsupported type %q for property %q: must be one of %s",
+			property.Type, property.Name, strings.Join(kameletPropertyTypes, ", "))
+	}
+	if !hasDefault {
+		return property, nil
+	}
+
+	var err error
+	switch property.Type {
+	case "integer":
+		_, err = strconv.ParseInt(def, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(def, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(def)
+	default:
+		def = strconv.Quote(def)
+	}
+	if err != nil {
+		return property, fmt.Errorf("invalid default value %q for %s property %q", def, property.Type, property.Name)
+	}
+	property.Default = def
+
+	return property, nil
+}
+
+func (o *initCmdOptions) initKamelet(fileName string) error {
+	kameletType := o.KameletType
+	if kameletType == "" {
+		kameletType = "source"
+	}
+	endpoint := o.KameletURI
+	if endpoint == "" {
+		endpoint = defaultKameletURIs[kameletType]
+	}
+	if err := validateCatalogURI(endpoint); err != nil {
+		return err
+	}
+
+	properties := make([]kameletProperty, 0, len(o.KameletProperties))
+	required := make([]string, 0)
+	for _, p := range o.KameletProperties {
+		property, err := parseKameletProperty(p)
+		if err != nil {
+			return err
+		}
+		properties = append(properties, property)
+		if property.Default == "" {
+			required = append(required, property.Name)
+		}
+	}
+
+	params := o.templateParameters(fileName)
+	params["Type"] = kameletType
+	params["Title"] = titleFromName(params["Name"].(string))
+	params["URI"] = endpoint
+	params["Properties"] = properties
+	params["Required"] = required
+
+	return o.writeFromTemplate(kameletScaffoldTemplate, fileName, params)
+}
+
+type bindingEndpoint struct {
+	Kamelet    string
+	URI        string
+	Properties []bindingProperty
+}
+
+type bindingProperty struct {
+	Key   string
+	Value string
+}
+
+// parseBindingProperty parses a property in the form of "[source|sink].<key>=<value>".
+func parseBindingProperty(prop string) (string, string, string, error) {
+	parts := strings.SplitN(prop, "=", 2)
+	if len(parts) != 2 {
+		return "", "", "", fmt.Errorf(`property %q does not follow format "[source|sink].<key>=<value>"`, prop)
+	}
+	keyParts := strings.SplitN(parts[0], ".", 2)
+	if len(keyParts) != 2 || (keyParts[0] != sourceKey && keyParts[0] != sinkKey) {
+		return "", "", "", fmt.Errorf(`property key %q does not start with "source." or "sink."`, parts[0])
+	}
+	return keyParts[0], keyParts[1], parts[1], nil
+}
+
+func (o *initCmdOptions) initKameletBinding(cmd *cobra.Command, fileName string) error {
+	source := o.Source
+	if source == "" {
+		source = "timer-source"
+	}
+	sink := o.Sink
+	if sink == "" {
+		sink = "log-sink"
+	}
+
+	sourceEndpoint, err := o.bindingEndpoint(cmd, sourceKey, source)
+	if err != nil {
+		return err
+	}
+	sinkEndpoint, err := o.bindingEndpoint(cmd, sinkKey, sink)
+	if err != nil {
+		return err
+	}
+
+	params := o.templateParameters(fileName)
+	params["Source"] = sourceEndpoint
+	params["Sink"] = sinkEndpoint
+
+	return o.writeFromTemplate(kameletBindingTemplate, fileName, params)
+}
+
+func (o *initCmdOptions) bindingEndpoint(cmd *cobra.Command, key string, value string) (bindingEndpoint, error) {
+	endpoint := bindingEndpoint{}
+	properties := make(map[string]string)
+
+	if uri.HasCamelURIFormat(value) {
+		if err := validateCatalogURI(value); err != nil {
+			return endpoint, err
+		}
+		endpoint.URI = value
+	} else {
+		opposite := sinkKey
+		if key == sinkKey {
+			opposite = sourceKey
+		}
+		if strings.HasSuffix(value, "-"+opposite) {
+			return endpoint, fmt.Errorf("the Kamelet %q cannot be used as a binding %s", value, key)
+		}
+		if required, ok := wellKnownKamelets[value]; ok {
+			for _, p := range required {
+				properties[p] = fmt.Sprintf("<%s>", p)
+			}
+		} else {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: Kamelet %q is not a well-known Kamelet, its required properties must be added manually\n", value)
+		}
+		endpoint.Kamelet = value
+	}
+
+	for _, p := range o.Properties {
+		tp, k, v, err := parseBindingProperty(p)
+		if err != nil {
+			return endpoint, err
+		}
+		if tp == key {
+			properties[k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		endpoint.Properties = append(endpoint.Properties, bindingProperty{Key: k, Value: properties[k]})
+	}
+
+	return endpoint, nil
+}
+
+// validateCatalogURI checks that the URI scheme belongs to a component of the bundled Camel catalog.
+func validateCatalogURI(value string) error {
+	if !uri.HasCamelURIFormat(value) {
+		return fmt.Errorf("%q is not a valid Camel URI", value)
+	}
+	catalog, err := camel.DefaultCatalog()
+	if err != nil {
+		return err
+	}
+	scheme := uri.GetComponent(value)
+	if _, ok := catalog.GetScheme(scheme); !ok {
+		return fmt.Errorf("unknown component %q in URI %q: not found in the Camel catalog %s", scheme, value, catalog.GetCamelVersion())
+	}
+	return nil
+}
+
+type openAPIOperation struct {
+	ID   string
+	Path string
+}
+
+type openAPIVerb struct {
+	Method     string
+	Operations []openAPIOperation
+}
+
+type openAPIDocument struct {
+	Swagger  string `yaml:"swagger"`
+	OpenAPI  string `yaml:"openapi"`
+	BasePath string `yaml:"basePath"`
+	Servers  []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths map[string]map[string]interface{} `yaml:"paths"`
+}
+
+func (o *initCmdOptions) initFromOpenAPI(fileName string) error {
+	data, err := util.ReadFile(o.FromOpenAPI)
+	if err != nil {
+		return err
+	}
+	doc := openAPIDocument{}
+	if err := yaml2.Unmarshal(data, &doc); err != nil {
+		return errors.Wrapf(err, "cannot parse OpenAPI specification %s", o.FromOpenAPI)
+	}
+	if doc.Swagger == "" && doc.OpenAPI == "" {
+		return fmt.Errorf("%s is not an OpenAPI specification", o.FromOpenAPI)
+	}
+
+	basePath := doc.BasePath
+	if basePath == "" && len(doc.Servers) > 0 {
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+			basePath = u.Path
+		}
+	}
+	if basePath == "/" {
+		basePath = ""
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	verbs := make([]openAPIVerb, 0)
+	operations := make([]openAPIOperation, 0)
+	for _, method := range openAPIMethods {
+		verb := openAPIVerb{Method: method}
+		for _, p := range paths {
+			op, ok := doc.Paths[p][method]
+			if !ok {
+				continue
+			}
+			id := ""
+			if m, ok := op.(map[interface{}]interface{}); ok {
+				if v, ok := m["operationId"].(string); ok {
+					id = v
+				}
+			}
+			if id == "" {
+				id = strings.Trim(operationIDDisallowedChars.ReplaceAllString(method+"-"+p, "-"), "-")
+			}
+			operation := openAPIOperation{ID: id, Path: p}
+			verb.Operations = append(verb.Operations, operation)
+			operations = append(operations, operation)
+		}
+		if len(verb.Operations) > 0 {
+			verbs = append(verbs, verb)
+		}
+	}
+	if len(operations) == 0 {
+		return fmt.Errorf("no operation found in OpenAPI specification %s", o.FromOpenAPI)
+	}
+
+	params := o.templateParameters(fileName)
+	params["Specification"] = filepath.Base(o.FromOpenAPI)
+	params["Path"] = basePath
+	params["Verbs"] = verbs
+	params["Operations"] = operations
+
+	return o.writeFromTemplate(openAPITemplate, fileName, params)
+}
+
+// titleFromName turns a dash or camel case name into a human readable title.
+func titleFromName(name string) string {
+	words := make([]string, 0)
+	current := make([]rune, 0, len(name))
+	for _, r := range name {
+		switch {
+		case r == '-' || r == '_' || r == '.':
+			if len(current) > 0 {
+				words = append(words, string(current))
+			}
+			current = current[:0]
+			continue
+		case unicode.IsUpper(r) && len(current) > 0:
+			words = append(words, string(current))
+			current = current[:0]
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
 func (o *initCmdOptions) extractLanguage(fileName string) *v1.Language {
-	if strings.HasSuffix(fileName, ".kamelet.yaml") {
+	if strings.HasSuffix(fileName, kameletFileSuffix) {
 		language := v1.LanguageKamelet
 		return &language
 	}
@@ -0,0 +1,384 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/install"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/registry"
+)
+
+const (
+	// developersRoleBindingName is the name of the RoleBinding granting the developers the edition of the Camel K resources.
+	developersRoleBindingName = "camel-k-developers"
+	// developersClusterRoleName is the name of the ClusterRole installed with the operator, granting the edition of the Camel K resources.
+	developersClusterRoleName = "camel-k-edit"
+)
+
+func newCmdInitNamespace(rootCmdOptions *RootCmdOptions) (*cobra.Command, *initNamespaceCmdOptions) {
+	options := initNamespaceCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:   "init-namespace",
+		Short: "Prepare a namespace for running integrations",
+		Long: `Prepare the current namespace for running integrations with an operator installed in another namespace: ` +
+			`create the tenant configuration layered on top of the IntegrationPlatform, i.e., the registry, its credentials and the default environment profile, ` +
+			`grant the developers the edition of the Camel K resources, create the builder service account when needed, and verify the build prerequisites. ` +
+			`Each step is reported, and the command fails if the namespace is not ready.`,
+		Args:    cobra.NoArgs,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().String("operator-namespace", "", "The namespace of the IntegrationPlatform, when none is installed in the current namespace")
+	cmd.Flags().String("registry", "", "The address of the registry the images of the namespace are published to")
+	cmd.Flags().String("organization", "", "The organization of the registry the images are published to, defaults to the namespace when the registry is set")
+	cmd.Flags().String("registry-secret", "", "The secret holding the credentials of the registry")
+	cmd.Flags().String("registry-ca", "", "The ConfigMap holding the CA certificates of the registry")
+	cmd.Flags().Bool("registry-insecure", false, "Whether the registry is insecure")
+	cmd.Flags().String("registry-auth-file", "", "A docker registry configuration file, the registry secret is created from")
+	cmd.Flags().String("registry-auth-server", "", "The docker registry authentication server")
+	cmd.Flags().String("registry-auth-username", "", "The docker registry authentication username, the registry secret is created from")
+	cmd.Flags().String("registry-auth-password", "", "The docker registry authentication password")
+	cmd.Flags().Bool("kit-sharing", false, "Allow the integrations to reuse the kits built in the platform namespace")
+	cmd.Flags().String("environment-profile", "", "The environment profile activated by default for the integrations of the namespace")
+	cmd.Flags().StringArray("user", nil, "A user granted the edition of the Camel K resources of the namespace")
+	cmd.Flags().StringArray("group", nil, "A group granted the edition of the Camel K resources of the namespace")
+	cmd.Flags().Bool("force", false, "Overwrite the existing tenant configuration, registry secret and developers role binding")
+
+	return &cmd, &options
+}
+
+type initNamespaceCmdOptions struct {
+	*RootCmdOptions
+	OperatorNamespace    string   `mapstructure:"operator-namespace"`
+	Registry             string   `mapstructure:"registry"`
+	Organization         string   `mapstructure:"organization"`
+	RegistrySecret       string   `mapstructure:"registry-secret"`
+	RegistryCA           string   `mapstructure:"registry-ca"`
+	RegistryInsecure     bool     `mapstructure:"registry-insecure"`
+	RegistryAuthFile     string   `mapstructure:"registry-auth-file"`
+	RegistryAuthServer   string   `mapstructure:"registry-auth-server"`
+	RegistryAuthUsername string   `mapstructure:"registry-auth-username"`
+	RegistryAuthPassword string   `mapstructure:"registry-auth-password"`
+	KitSharing           bool     `mapstructure:"kit-sharing"`
+	EnvironmentProfile   string   `mapstructure:"environment-profile"`
+	Users                []string `mapstructure:"users"`
+	Groups               []string `mapstructure:"groups"`
+	Force                bool     `mapstructure:"force"`
+}
+
+// initNamespaceStep reports the outcome of a step of the namespace initialization.
+type initNamespaceStep struct {
+	Name    string
+	Details string
+	Err     error
+	Skipped bool
+}
+
+func (o *initNamespaceCmdOptions) validate() error {
+	auth := o.registryAuth()
+	if o.RegistrySecret != "" && (auth.IsSet() || o.RegistryAuthFile != "") {
+		return errors.New("incompatible options combinations: you cannot set both registry-secret and registry-auth-[*] settings")
+	}
+	if auth.IsSet() && o.RegistryAuthFile != "" {
+		return errors.New("incompatible options combinations: you cannot set registry-auth-file with other registry-auth-[*] settings")
+	}
+	return nil
+}
+
+func (o *initNamespaceCmdOptions) run(cmd *cobra.Command, _ []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	tenant := &platform.Tenant{
+		Namespace: o.Namespace,
+		Registry: v1.RegistrySpec{
+			Address:      o.Registry,
+			Organization: o.Organization,
+			Secret:       o.RegistrySecret,
+			CA:           o.RegistryCA,
+			Insecure:     o.RegistryInsecure,
+		},
+		KitSharing:         o.KitSharing,
+		EnvironmentProfile: o.EnvironmentProfile,
+	}
+
+	var pl *v1.IntegrationPlatform
+	report := make([]initNamespaceStep, 0, 6)
+	report = append(report, runInitNamespaceStep("platform", func() (string, error) {
+		pl, err = o.findPlatform(c)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s/%s (%s)", pl.Namespace, pl.Name, pl.Status.Phase), nil
+	}))
+	report = append(report, o.registrySecret(c, tenant))
+	report = append(report, o.overlay(c, tenant))
+	report = append(report, o.developers(c))
+	if pl == nil {
+		report = append(report,
+			initNamespaceStep{Name: "builder", Skipped: true, Details: "no platform found"},
+			initNamespaceStep{Name: "build", Skipped: true, Details: "no platform found"})
+	} else {
+		report = append(report, o.builder(c, tenant, pl), o.verifyBuild(c, tenant, pl))
+	}
+
+	printInitNamespaceReport(cmd, report)
+
+	for _, s := range report {
+		if s.Err != nil {
+			return fmt.Errorf("namespace %s is not ready, step %s failed", o.Namespace, s.Name)
+		}
+	}
+	return nil
+}
+
+func runInitNamespaceStep(name string, run func() (string, error)) initNamespaceStep {
+	details, err := run()
+	return initNamespaceStep{
+		Name:    name,
+		Details: details,
+		Err:     err,
+	}
+}
+
+func printInitNamespaceReport(cmd *cobra.Command, report []initNamespaceStep) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "STEP\tSTATUS\tDETAILS")
+	for _, s := range report {
+		status, details := "OK", s.Details
+		switch {
+		case s.Skipped:
+			status = "SKIPPED"
+		case s.Err != nil:
+			status, details = "FAILED", s.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, status, details)
+	}
+	w.Flush()
+}
+
+// findPlatform returns the IntegrationPlatform installed in the namespace, or in the operator namespace.
+func (o *initNamespaceCmdOptions) findPlatform(c client.Client) (*v1.IntegrationPlatform, error) {
+	namespaces := []string{o.Namespace}
+	if o.OperatorNamespace != "" && o.OperatorNamespace != o.Namespace {
+		namespaces = append(namespaces, o.OperatorNamespace)
+	}
+	for _, namespace := range namespaces {
+		list, err := platform.ListPrimaryPlatforms(o.Context, c, namespace)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if platform.IsActive(&list.Items[i]) {
+				return &list.Items[i], nil
+			}
+		}
+	}
+	if o.OperatorNamespace == "" {
+		return nil, fmt.Errorf("no active integration platform found in namespace %s, set the --operator-namespace flag to use the one of the operator", o.Namespace)
+	}
+	return nil, fmt.Errorf("no active integration platform found in namespaces %s and %s", o.Namespace, o.OperatorNamespace)
+}
+
+func (o *initNamespaceCmdOptions) registryAuth() registry.Auth {
+	return registry.Auth{
+		Server:   o.RegistryAuthServer,
+		Username: o.RegistryAuthUsername,
+		Password: o.RegistryAuthPassword,
+		Registry: o.Registry,
+	}
+}
+
+// registrySecret creates the registry secret from the registry authentication settings, if any,
+// and references it from the tenant configuration.
+func (o *initNamespaceCmdOptions) registrySecret(c client.Client, tenant *platform.Tenant) initNamespaceStep {
+	auth := o.registryAuth()
+	if !auth.IsSet() && o.RegistryAuthFile == "" {
+		return initNamespaceStep{Name: "registry-secret", Skipped: true, Details: "no registry credentials set"}
+	}
+
+	return runInitNamespaceStep("registry-secret", func() (string, error) {
+		var secret string
+		var err error
+		if auth.IsSet() {
+			secret, err = install.RegistrySecretOrCollect(o.Context, c, o.Namespace, auth, nil, o.Force)
+		} else {
+			secret, err = install.RegistrySecretFromFileOrCollect(o.Context, c, o.Namespace, o.RegistryAuthFile, nil, o.Force)
+		}
+		if err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return "", errors.Wrap(err, "use the --force flag to overwrite it")
+			}
+			return "", err
+		}
+		tenant.Registry.Secret = secret
+		return "created secret " + secret, nil
+	})
+}
+
+// overlay creates the tenant configuration the namespace layers on top of the IntegrationPlatform, if any is set.
+func (o *initNamespaceCmdOptions) overlay(c client.Client, tenant *platform.Tenant) initNamespaceStep {
+	cm := tenant.ConfigMap()
+	if len(cm.Data) == 0 {
+		return initNamespaceStep{Name: "overlay", Skipped: true, Details: "the namespace uses the platform configuration"}
+	}
+
+	return runInitNamespaceStep("overlay", func() (string, error) {
+		if err := install.ObjectOrCollect(o.Context, c, o.Namespace, nil, o.Force, cm); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return "", errors.Wrap(err, "use the --force flag to overwrite it")
+			}
+			return "", err
+		}
+		return "created configmap " + cm.Name, nil
+	})
+}
+
+// developers grants the users and groups the edition of the Camel K resources of the namespace.
+func (o *initNamespaceCmdOptions) developers(c client.Client) initNamespaceStep {
+	if len(o.Users) == 0 && len(o.Groups) == 0 {
+		return initNamespaceStep{Name: "rbac", Skipped: true, Details: "no users nor groups set"}
+	}
+
+	return runInitNamespaceStep("rbac", func() (string, error) {
+		rb := newDevelopersRoleBinding(o.Namespace, o.Users, o.Groups)
+		if err := install.ObjectOrCollect(o.Context, c, o.Namespace, nil, o.Force, rb); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return "", errors.Wrap(err, "use the --force flag to overwrite it")
+			}
+			return "", err
+		}
+		return fmt.Sprintf("bound %d subjects to clusterrole %s", len(rb.Subjects), developersClusterRoleName), nil
+	})
+}
+
+func newDevelopersRoleBinding(namespace string, users []string, groups []string) *rbacv1.RoleBinding {
+	subjects := make([]rbacv1.Subject, 0, len(users)+len(groups))
+	for _, user := range users {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.UserKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     user,
+		})
+	}
+	for _, group := range groups {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     rbacv1.GroupKind,
+			APIGroup: rbacv1.GroupName,
+			Name:     group,
+		})
+	}
+
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      developersRoleBindingName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "camel-k",
+			},
+		},
+		Subjects: subjects,
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			APIGroup: rbacv1.GroupName,
+			Name:     developersClusterRoleName,
+		},
+	}
+}
+
+// builder creates the service account of the builder Pods in the namespace, when the platform builds in Pods
+// and the namespace is not the platform one, as the kits of the namespace are built in it.
+func (o *initNamespaceCmdOptions) builder(c client.Client, tenant *platform.Tenant, pl *v1.IntegrationPlatform) initNamespaceStep {
+	if pl.Status.Build.BuildStrategy != v1.BuildStrategyPod {
+		return initNamespaceStep{Name: "builder", Skipped: true, Details: "the builds run in the operator"}
+	}
+	if pl.Namespace == o.Namespace {
+		return initNamespaceStep{Name: "builder", Skipped: true, Details: "the builds run in the platform namespace"}
+	}
+
+	return runInitNamespaceStep("builder", func() (string, error) {
+		if err := tenant.CreateBuilderServiceAccount(o.Context, c, pl); err != nil {
+			return "", err
+		}
+		return "service account " + platform.BuilderServiceAccount, nil
+	})
+}
+
+// verifyBuild checks the prerequisites of the builds of the namespace, i.e., the platform is ready,
+// and the registry the images are published to is set, with its secret and CA available.
+func (o *initNamespaceCmdOptions) verifyBuild(c client.Client, tenant *platform.Tenant, pl *v1.IntegrationPlatform) initNamespaceStep {
+	return runInitNamespaceStep("build", func() (string, error) {
+		if pl.Status.Phase != v1.IntegrationPlatformPhaseReady {
+			return "", fmt.Errorf("integration platform %s is not ready (phase %q)", pl.Name, pl.Status.Phase)
+		}
+
+		effective := tenant.ApplyTo(pl)
+		strategy := effective.Status.Build.PublishStrategy
+		reg := effective.Status.Build.Registry
+		if strategy == v1.IntegrationPlatformBuildPublishStrategyS2I {
+			return fmt.Sprintf("publishing with %s", strategy), nil
+		}
+		if reg.Address == "" {
+			return "", errors.New("no registry set, the images cannot be published")
+		}
+
+		namespace := tenant.RegistryNamespace(pl)
+		if reg.Secret != "" {
+			if _, err := kubernetes.GetSecret(o.Context, c, reg.Secret, namespace); err != nil {
+				return "", errors.Wrapf(err, "cannot get registry secret %s/%s", namespace, reg.Secret)
+			}
+		}
+		if reg.CA != "" {
+			if _, err := kubernetes.GetConfigMap(o.Context, c, reg.CA, namespace); err != nil {
+				return "", errors.Wrapf(err, "cannot get registry CA configmap %s/%s", namespace, reg.CA)
+			}
+		}
+
+		image := reg.Address
+		if reg.Organization != "" {
+			image += "/" + reg.Organization
+		}
+		return fmt.Sprintf("publishing to %s with %s", image, strategy), nil
+	})
+}
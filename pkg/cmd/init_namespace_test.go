@@ -0,0 +1,141 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdInitNamespace = "init-namespace"
+
+func initializeInitNamespaceCmdOptions(t *testing.T, initObjs ...runtime.Object) (*initNamespaceCmdOptions, *cobra.Command, client.Client) {
+	t.Helper()
+
+	c, err := test.NewFakeClient(initObjs...)
+	assert.Nil(t, err)
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	initNamespaceCmd, initNamespaceOptions := newCmdInitNamespace(&options)
+	rootCmd.AddCommand(initNamespaceCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return initNamespaceOptions, rootCmd, c
+}
+
+func newInitNamespaceTestPlatform() *v1.IntegrationPlatform {
+	pl := v1.NewIntegrationPlatform("camel-k", platform.DefaultPlatformName)
+	pl.Status.Phase = v1.IntegrationPlatformPhaseReady
+	pl.Status.Cluster = v1.IntegrationPlatformClusterKubernetes
+	pl.Status.Build.BuildStrategy = v1.BuildStrategyPod
+	pl.Status.Build.PublishStrategy = v1.IntegrationPlatformBuildPublishStrategyKaniko
+	pl.Status.Build.Registry.Address = "registry.example.com"
+	return &pl
+}
+
+func TestInitNamespace(t *testing.T) {
+	_, rootCmd, c := initializeInitNamespaceCmdOptions(t, newInitNamespaceTestPlatform())
+
+	output, err := test.ExecuteCommand(rootCmd, cmdInitNamespace, "-n", "team-a", "--operator-namespace", "camel-k",
+		"--registry", "registry.team-a.example.com", "--registry-auth-username", "team-a", "--registry-auth-password", "secret",
+		"--environment-profile", "dev", "--user", "alice", "--group", "developers")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "platform\tOK\tcamel-k/camel-k (Ready)")
+	assert.Contains(t, output, "registry-secret\tOK\tcreated secret camel-k-registry-secret")
+	assert.Contains(t, output, "overlay\tOK\tcreated configmap camel-k-tenant")
+	assert.Contains(t, output, "rbac\tOK\tbound 2 subjects to clusterrole camel-k-edit")
+	assert.Contains(t, output, "builder\tOK\tservice account camel-k-builder")
+	assert.Contains(t, output, "build\tOK\tpublishing to registry.team-a.example.com/team-a with Kaniko")
+
+	cm := corev1.ConfigMap{}
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKey{Namespace: "team-a", Name: platform.TenantConfigMapName}, &cm))
+	assert.Equal(t, map[string]string{
+		"registry.address":    "registry.team-a.example.com",
+		"registry.secret":     "camel-k-registry-secret",
+		"environment.profile": "dev",
+	}, cm.Data)
+
+	rb := rbacv1.RoleBinding{}
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKey{Namespace: "team-a", Name: developersRoleBindingName}, &rb))
+	assert.Equal(t, developersClusterRoleName, rb.RoleRef.Name)
+	assert.Len(t, rb.Subjects, 2)
+	assert.Equal(t, rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "alice"}, rb.Subjects[0])
+	assert.Equal(t, rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "developers"}, rb.Subjects[1])
+
+	sa := corev1.ServiceAccount{}
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKey{Namespace: "team-a", Name: platform.BuilderServiceAccount}, &sa))
+}
+
+func TestInitNamespaceWithPlatformConfiguration(t *testing.T) {
+	_, rootCmd, _ := initializeInitNamespaceCmdOptions(t, newInitNamespaceTestPlatform())
+
+	output, err := test.ExecuteCommand(rootCmd, cmdInitNamespace, "-n", "team-a", "--operator-namespace", "camel-k")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "registry-secret\tSKIPPED")
+	assert.Contains(t, output, "overlay\tSKIPPED\tthe namespace uses the platform configuration")
+	assert.Contains(t, output, "rbac\tSKIPPED")
+	assert.Contains(t, output, "build\tOK\tpublishing to registry.example.com with Kaniko")
+}
+
+func TestInitNamespaceMissingRegistrySecret(t *testing.T) {
+	_, rootCmd, _ := initializeInitNamespaceCmdOptions(t, newInitNamespaceTestPlatform())
+
+	output, err := test.ExecuteCommand(rootCmd, cmdInitNamespace, "-n", "team-a", "--operator-namespace", "camel-k",
+		"--registry-secret", "team-a-registry")
+	assert.NotNil(t, err)
+	assert.Equal(t, "namespace team-a is not ready, step build failed", err.Error())
+	assert.Contains(t, output, "overlay\tOK")
+	assert.Contains(t, output, "build\tFAILED\tcannot get registry secret team-a/team-a-registry")
+}
+
+func TestInitNamespaceWithoutPlatform(t *testing.T) {
+	_, rootCmd, _ := initializeInitNamespaceCmdOptions(t)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdInitNamespace, "-n", "team-a")
+	assert.NotNil(t, err)
+	assert.Equal(t, "namespace team-a is not ready, step platform failed", err.Error())
+	assert.Contains(t, output, "platform\tFAILED\tno active integration platform found in namespace team-a")
+	assert.Contains(t, output, "builder\tSKIPPED\tno platform found")
+	assert.Contains(t, output, "build\tSKIPPED\tno platform found")
+}
+
+func TestInitNamespaceIncompatibleRegistryOptions(t *testing.T) {
+	_, rootCmd, _ := initializeInitNamespaceCmdOptions(t, newInitNamespaceTestPlatform())
+
+	_, err := test.ExecuteCommand(rootCmd, cmdInitNamespace, "-n", "team-a", "--registry-secret", "s", "--registry-auth-username", "u")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "you cannot set both registry-secret and registry-auth-[*] settings")
+}
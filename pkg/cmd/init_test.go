@@ -0,0 +1,247 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdInit = "init"
+
+// nolint: unparam
+func initializeInitCmdOptions(t *testing.T) (*initCmdOptions, *cobra.Command, RootCmdOptions) {
+	t.Helper()
+
+	options, rootCmd := kamelTestPreAddCommandInit()
+	initCmdOptions := addTestInitCmd(*options, rootCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return initCmdOptions, rootCmd, *options
+}
+
+func addTestInitCmd(options RootCmdOptions, rootCmd *cobra.Command) *initCmdOptions {
+	// add a testing version of init Command
+	initCmd, initOptions := newCmdInit(&options)
+	initCmd.Args = test.ArbitraryArgs
+	rootCmd.AddCommand(initCmd)
+	return initOptions
+}
+
+func initAndRead(t *testing.T, fileName string, args ...string) (string, error) {
+	t.Helper()
+
+	content := ""
+	err := util.WithTempDir("camel-k-test-", func(dir string) error {
+		_, rootCmd, _ := initializeInitCmdOptions(t)
+		file := path.Join(dir, fileName)
+		if _, err := test.ExecuteCommand(rootCmd, append([]string{cmdInit, file}, args...)...); err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(file)
+		content = string(data)
+		return err
+	})
+
+	return content, err
+}
+
+func TestInitUnsupportedFile(t *testing.T) {
+	_, rootCmd, _ := initializeInitCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInit, "routes.unknown")
+	assert.EqualError(t, err, "unsupported file type: routes.unknown")
+}
+
+func TestInitKameletScaffold(t *testing.T) {
+	content, err := initAndRead(t, "my-source.kamelet.yaml",
+		"--kamelet-uri", "timer:tick",
+		"--kamelet-property", "period:integer=1000",
+		"--kamelet-property", "message")
+	assert.Nil(t, err)
+	assert.Equal(t, `apiVersion: camel.apache.org/v1alpha1
+kind: Kamelet
+metadata:
+  name: my-source
+  labels:
+    camel.apache.org/kamelet.type: "source"
+spec:
+  definition:
+    title: "My Source"
+    description: "TODO: describe what the my-source Kamelet does"
+    required:
+      - message
+    type: object
+    properties:
+      period:
+        title: Period
+        description: "TODO: describe the period property"
+        type: integer
+        default: 1000
+      message:
+        title: Message
+        description: "TODO: describe the message property"
+        type: string
+  template:
+    from:
+      uri: "timer:tick"
+      parameters:
+        period: "{{period}}"
+        message: "{{message}}"
+      steps:
+        - to: "kamelet:sink"
+`, content)
+}
+
+func TestInitKameletSinkScaffold(t *testing.T) {
+	content, err := initAndRead(t, "my-sink.kamelet.yaml", "--kamelet-type", "sink")
+	assert.Nil(t, err)
+	assert.Contains(t, content, `camel.apache.org/kamelet.type: "sink"`)
+	assert.Contains(t, content, `uri: "kamelet:source"`)
+	assert.Contains(t, content, `uri: "log:info"`)
+}
+
+func TestInitKameletInvalidFlags(t *testing.T) {
+	_, err := initAndRead(t, "my-action.kamelet.yaml", "--kamelet-type", "processor")
+	assert.EqualError(t, err, `unsupported Kamelet type "processor": must be one of source, sink or action`)
+
+	_, err = initAndRead(t, "my-source.kamelet.yaml", "--kamelet-property", "period:duration")
+	assert.EqualError(t, err, `unsupported type "duration" for property "period": must be one of string, integer, number, boolean`)
+
+	_, err = initAndRead(t, "my-source.kamelet.yaml", "--kamelet-property", "period:integer=often")
+	assert.EqualError(t, err, `invalid default value "often" for integer property "period"`)
+
+	_, err = initAndRead(t, "my-source.kamelet.yaml", "--kamelet-uri", "unknown:tick")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `unknown component "unknown" in URI "unknown:tick"`)
+
+	_, err = initAndRead(t, "routes.yaml", "--kamelet-type", "source")
+	assert.NotNil(t, err)
+}
+
+func TestInitKameletBinding(t *testing.T) {
+	content, err := initAndRead(t, "my-binding.kameletbinding.yaml",
+		"--source", "kafka-source",
+		"--sink", "log:info",
+		"-p", "source.topic=my-topic",
+		"-p", "sink.showHeaders=true")
+	assert.Nil(t, err)
+	assert.Equal(t, `apiVersion: camel.apache.org/v1alpha1
+kind: KameletBinding
+metadata:
+  name: my-binding
+spec:
+  source:
+    ref:
+      kind: Kamelet
+      apiVersion: camel.apache.org/v1alpha1
+      name: kafka-source
+    properties:
+      bootstrapServers: "<bootstrapServers>"
+      password: "<password>"
+      topic: "my-topic"
+      user: "<user>"
+  sink:
+    uri: "log:info"
+    properties:
+      showHeaders: "true"
+`, content)
+}
+
+func TestInitKameletBindingInvalidEndpoints(t *testing.T) {
+	_, err := initAndRead(t, "my-binding.kameletbinding.yaml", "--source", "log-sink")
+	assert.EqualError(t, err, `the Kamelet "log-sink" cannot be used as a binding source`)
+
+	_, err = initAndRead(t, "my-binding.kameletbinding.yaml", "--sink", "unknown:sink")
+	assert.NotNil(t, err)
+
+	_, err = initAndRead(t, "my-binding.kameletbinding.yaml", "-p", "step-1.key=value")
+	assert.EqualError(t, err, `property key "step-1.key" does not start with "source." or "sink."`)
+}
+
+func TestInitFromOpenAPI(t *testing.T) {
+	err := util.WithTempDir("camel-k-test-", func(dir string) error {
+		spec := path.Join(dir, "petstore.yaml")
+		err := ioutil.WriteFile(spec, []byte(`
+openapi: "3.0.0"
+servers:
+  - url: http://petstore.swagger.io/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      operationId: createPets
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+    get:
+      summary: Info for a specific pet
+`), 0o400)
+		assert.Nil(t, err)
+
+		content, err := initAndRead(t, "api.yaml", "--from-openapi", spec)
+		assert.Nil(t, err)
+		assert.Equal(t, `# camel-k: language=yaml
+
+# REST endpoints generated from petstore.yaml
+- rest:
+    path: "/v1"
+    get:
+      - path: "/pets"
+        to: "direct:listPets"
+      - path: "/pets/{petId}"
+        to: "direct:get-pets-petId"
+    post:
+      - path: "/pets"
+        to: "direct:createPets"
+
+- from:
+    uri: "direct:listPets"
+    steps:
+      - setBody:
+          constant: "listPets is not implemented yet"
+
+- from:
+    uri: "direct:get-pets-petId"
+    steps:
+      - setBody:
+          constant: "get-pets-petId is not implemented yet"
+
+- from:
+    uri: "direct:createPets"
+    steps:
+      - setBody:
+          constant: "createPets is not implemented yet"
+`, content)
+
+		_, err = initAndRead(t, "Routes.java", "--from-openapi", spec)
+		assert.EqualError(t, err, "--from-openapi can only be used to initialize YAML integrations")
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+}
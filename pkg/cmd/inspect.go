@@ -0,0 +1,182 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/metadata"
+	"github.com/apache/camel-k/pkg/util"
+)
+
+func newCmdInspect(rootCmdOptions *RootCmdOptions) (*cobra.Command, *inspectCmdOptions) {
+	options := inspectCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+
+	cmd := cobra.Command{
+		Use:   "inspect [files to inspect]",
+		Short: "Inspect integration sources with the operator",
+		Long: `Output the metadata extracted by the operator from a list of integration files, i.e., the components,
+the endpoints URIs, the required capabilities and the dependencies, without deploying the integration.
+The operator is looked up in the current namespace, unless the --operator-namespace flag is set.`,
+		Args:    options.validate,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output format. One of: json|yaml")
+	cmd.Flags().String("operator-namespace", "", "The namespace of the operator, defaults to the current namespace")
+	cmd.Flags().Int32("operator-port", 8082, "The port of the operator inspect endpoint")
+
+	return &cmd, &options
+}
+
+type inspectCmdOptions struct {
+	*RootCmdOptions
+	OutputFormat      string `mapstructure:"output"`
+	OperatorNamespace string `mapstructure:"operator-namespace"`
+	OperatorPort      int32  `mapstructure:"operator-port"`
+}
+
+func (o *inspectCmdOptions) validate(_ *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("no integration files have been provided")
+	}
+
+	return nil
+}
+
+func (o *inspectCmdOptions) run(cmd *cobra.Command, args []string) error {
+	switch o.OutputFormat {
+	case "", "json", "yaml":
+	default:
+		return errors.New("unknown output format: " + o.OutputFormat)
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	sources, err := ResolveSources(o.Context, args, false, cmd)
+	if err != nil {
+		return err
+	}
+	request := metadata.InspectRequest{
+		Sources: make([]v1.SourceSpec, 0, len(sources)),
+	}
+	for _, source := range sources {
+		request.Sources = append(request.Sources, v1.SourceSpec{
+			DataSpec: v1.DataSpec{
+				Name:    source.Name,
+				Content: source.Content,
+			},
+		})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	data, err := o.inspect(c, body)
+	if err != nil {
+		return err
+	}
+
+	switch o.OutputFormat {
+	case "json":
+		out := bytes.Buffer{}
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), out.String())
+	case "yaml":
+		out, err := util.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), string(out))
+	default:
+		inspection := metadata.Inspection{}
+		if err := json.Unmarshal(data, &inspection); err != nil {
+			return err
+		}
+		printInspection(cmd, inspection)
+	}
+
+	return nil
+}
+
+// inspect calls the inspect endpoint of the operator, through the API server Pod proxy, so that the endpoint
+// does not have to be exposed outside the cluster.
+func (o *inspectCmdOptions) inspect(c client.Client, body []byte) ([]byte, error) {
+	namespace := o.OperatorNamespace
+	if namespace == "" {
+		namespace = o.Namespace
+	}
+
+	pod, err := getRunningOperatorPod(o.Context, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.CoreV1().RESTClient().Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", pod.Name, o.OperatorPort)).
+		SubResource("proxy").
+		Suffix(metadata.InspectPath).
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw(o.Context)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect the integration files with operator %s: %w", pod.Name, err)
+	}
+
+	return data, nil
+}
+
+func printInspection(cmd *cobra.Command, inspection metadata.Inspection) {
+	out := cmd.OutOrStdout()
+	printList := func(title string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "%s:\n", title)
+		for _, v := range values {
+			fmt.Fprintf(out, "  %s\n", v)
+		}
+	}
+
+	printList("Components", inspection.Components)
+	printList("From URIs", inspection.FromURIs)
+	printList("To URIs", inspection.ToURIs)
+	printList("Kamelets", inspection.Kamelets)
+	printList("Dependencies", inspection.Dependencies)
+	printList("Required capabilities", inspection.RequiredCapabilities)
+	fmt.Fprintf(out, "Exposes HTTP services: %t\n", inspection.ExposesHTTPServices)
+	fmt.Fprintf(out, "Passive endpoints: %t\n", inspection.PassiveEndpoints)
+}
@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdInspect = "inspect"
+
+// nolint: unparam
+func initializeInspectCmdOptions(t *testing.T) (*inspectCmdOptions, *cobra.Command, RootCmdOptions) {
+	t.Helper()
+
+	options, rootCmd := kamelTestPreAddCommandInit()
+	inspectCmdOptions := addTestInspectCmd(*options, rootCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return inspectCmdOptions, rootCmd, *options
+}
+
+func addTestInspectCmd(options RootCmdOptions, rootCmd *cobra.Command) *inspectCmdOptions {
+	// add a testing version of inspect Command
+	inspectCmd, inspectOptions := newCmdInspect(&options)
+	inspectCmd.RunE = func(c *cobra.Command, args []string) error {
+		return nil
+	}
+	inspectCmd.PostRunE = func(c *cobra.Command, args []string) error {
+		return nil
+	}
+	rootCmd.AddCommand(inspectCmd)
+	return inspectOptions
+}
+
+func TestInspectNoFile(t *testing.T) {
+	_, rootCmd, _ := initializeInspectCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInspect)
+	assert.NotNil(t, err)
+}
+
+func TestInspectFlags(t *testing.T) {
+	inspectCmdOptions, rootCmd, _ := initializeInspectCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInspect, "route.java", "-o", "yaml",
+		"--operator-namespace", "camel-k", "--operator-port", "9090")
+	assert.Nil(t, err)
+	assert.Equal(t, "yaml", inspectCmdOptions.OutputFormat)
+	assert.Equal(t, "camel-k", inspectCmdOptions.OperatorNamespace)
+	assert.Equal(t, int32(9090), inspectCmdOptions.OperatorPort)
+}
+
+func TestInspectDefaultFlags(t *testing.T) {
+	inspectCmdOptions, rootCmd, _ := initializeInspectCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInspect, "route.java")
+	assert.Nil(t, err)
+	assert.Equal(t, "", inspectCmdOptions.OutputFormat)
+	assert.Equal(t, "", inspectCmdOptions.OperatorNamespace)
+	assert.Equal(t, int32(8082), inspectCmdOptions.OperatorPort)
+}
@@ -34,6 +34,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/kubectl/pkg/cmd/set/env"
@@ -45,6 +46,7 @@ import (
 	"github.com/apache/camel-k/pkg/client"
 	"github.com/apache/camel-k/pkg/install"
 	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/buildcache"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/maven"
 	"github.com/apache/camel-k/pkg/util/olm"
@@ -143,6 +145,17 @@ func newCmdInstall(rootCmdOptions *RootCmdOptions) (*cobra.Command, *installCmdO
 	cmd.Flags().StringArray("operator-resources", nil, "Define the resources requests and limits assigned to the operator Pod as <requestType.requestResource=value> (i.e., limits.memory=256Mi)")
 	cmd.Flags().StringArray("operator-env-vars", nil, "Add an environment variable to set in the operator Pod(s), as <name=value>")
 	cmd.Flags().StringP("log-level", "z", "info", "The level of operator logging (default - info): info or 0, debug or 1")
+	cmd.Flags().Float32("operator-api-qps", 0, "The maximum queries per second of the operator requests to the Kubernetes API server (defaults to 20)")
+	cmd.Flags().Int("operator-api-burst", 0, "The maximum burst of the operator requests to the Kubernetes API server (defaults to 200)")
+	cmd.Flags().Bool("operator-flow-schema", false, "Create a FlowSchema and a PriorityLevelConfiguration dedicated to the operator requests (requires API Priority and Fairness)")
+	cmd.Flags().Int32("operator-flow-concurrency-shares", 30, "The assured concurrency shares of the operator PriorityLevelConfiguration")
+	cmd.Flags().String("operator-upload-storage-size", "", "Create a PersistentVolumeClaim of the given size (i.e., 10Gi) for the files uploaded with kamel run --upload, that are not accepted otherwise")
+	cmd.Flags().String("operator-upload-storage-class", "", "The storage class of the PersistentVolumeClaim of the uploaded files")
+	cmd.Flags().String("operator-upload-access-mode", string(corev1.ReadWriteOnce), "The access mode of the PersistentVolumeClaim of the uploaded files, that must be ReadWriteMany to run several operator replicas")
+	cmd.Flags().String("operator-build-cache-store", "", "The store of the records of the running builds, so that the builds interrupted by an operator restart are resumed: memory (default) or configmap")
+	cmd.Flags().String("operator-build-cache-storage-size", "", "Create a PersistentVolumeClaim of the given size (i.e., 10Gi) for the Maven local repository of the builds run by the operator, that is not persisted otherwise")
+	cmd.Flags().String("operator-build-cache-storage-class", "", "The storage class of the PersistentVolumeClaim of the Maven local repository")
+	cmd.Flags().String("operator-build-cache-access-mode", string(corev1.ReadWriteOnce), "The access mode of the PersistentVolumeClaim of the Maven local repository, that must be ReadWriteMany to run several operator replicas")
 
 	// save
 	cmd.Flags().Bool("save", false, "Save the install parameters into the default kamel configuration file (kamel-config.yaml)")
@@ -198,6 +211,17 @@ type installCmdOptions struct {
 	ResourcesRequirements    []string `mapstructure:"operator-resources"`
 	LogLevel                 string   `mapstructure:"log-level"`
 	EnvVars                  []string `mapstructure:"operator-env-vars"`
+	OperatorAPIQPS           float32  `mapstructure:"operator-api-qps"`
+	OperatorAPIBurst         int      `mapstructure:"operator-api-burst"`
+	OperatorFlowSchema       bool     `mapstructure:"operator-flow-schema"`
+	OperatorFlowShares       int32    `mapstructure:"operator-flow-concurrency-shares"`
+	OperatorUploadSize       string   `mapstructure:"operator-upload-storage-size"`
+	OperatorUploadClass      string   `mapstructure:"operator-upload-storage-class"`
+	OperatorUploadAccessMode string   `mapstructure:"operator-upload-access-mode"`
+	OperatorBuildCacheStore  string   `mapstructure:"operator-build-cache-store"`
+	OperatorBuildCacheSize   string   `mapstructure:"operator-build-cache-storage-size"`
+	OperatorBuildCacheClass  string   `mapstructure:"operator-build-cache-storage-class"`
+	OperatorBuildCacheMode   string   `mapstructure:"operator-build-cache-access-mode"`
 
 	registry         v1.RegistrySpec
 	registryAuth     registry.Auth
@@ -305,6 +329,27 @@ func (o *installCmdOptions) install(cobraCmd *cobra.Command, _ []string) error {
 					Enabled: o.Monitoring,
 					Port:    o.MonitoringPort,
 				},
+				APIClient: install.OperatorAPIClientConfiguration{
+					QPS:   o.OperatorAPIQPS,
+					Burst: o.OperatorAPIBurst,
+				},
+				FlowControl: install.OperatorFlowControlConfiguration{
+					Enabled:           o.OperatorFlowSchema,
+					ConcurrencyShares: o.OperatorFlowShares,
+				},
+				Upload: install.OperatorStorageConfiguration{
+					StorageSize:  o.OperatorUploadSize,
+					StorageClass: o.OperatorUploadClass,
+					AccessMode:   o.OperatorUploadAccessMode,
+				},
+				BuildCache: install.OperatorBuildCacheConfiguration{
+					Store: o.OperatorBuildCacheStore,
+					Storage: install.OperatorStorageConfiguration{
+						StorageSize:  o.OperatorBuildCacheSize,
+						StorageClass: o.OperatorBuildCacheClass,
+						AccessMode:   o.OperatorBuildCacheMode,
+					},
+				},
 				Tolerations:           o.Tolerations,
 				NodeSelectors:         o.NodeSelectors,
 				ResourcesRequirements: o.ResourcesRequirements,
@@ -605,6 +650,30 @@ func (o *installCmdOptions) validate(_ *cobra.Command, _ []string) error {
 		result = multierr.Append(result, err)
 	}
 
+	if o.OperatorAPIQPS < 0 || o.OperatorAPIBurst < 0 {
+		err := fmt.Errorf("the operator API client QPS and burst cannot be negative")
+		result = multierr.Append(result, err)
+	}
+
+	if o.OperatorFlowSchema && o.OperatorFlowShares <= 0 {
+		err := fmt.Errorf("the operator flow concurrency shares must be positive")
+		result = multierr.Append(result, err)
+	}
+
+	if o.OperatorUploadSize != "" {
+		result = multierr.Append(result, validateOperatorStorage("upload", o.OperatorUploadSize, o.OperatorUploadAccessMode))
+	}
+
+	switch o.OperatorBuildCacheStore {
+	case "", buildcache.StoreMemory, buildcache.StoreConfigMap:
+	default:
+		err := fmt.Errorf("unsupported operator build cache store %s, expected %s or %s", o.OperatorBuildCacheStore, buildcache.StoreMemory, buildcache.StoreConfigMap)
+		result = multierr.Append(result, err)
+	}
+	if o.OperatorBuildCacheSize != "" {
+		result = multierr.Append(result, validateOperatorStorage("build cache", o.OperatorBuildCacheSize, o.OperatorBuildCacheMode))
+	}
+
 	if o.TraitProfile != "" {
 		tp := v1.TraitProfileByName(o.TraitProfile)
 		if tp == v1.TraitProfile("") {
@@ -669,6 +738,20 @@ func (o *installCmdOptions) validate(_ *cobra.Command, _ []string) error {
 	return result
 }
 
+// validateOperatorStorage validates the size and access mode of a persistent volume of the operator.
+func validateOperatorStorage(name string, size string, accessMode string) error {
+	var result error
+	if _, err := resource.ParseQuantity(size); err != nil {
+		result = multierr.Append(result, fmt.Errorf("invalid operator %s storage size %s: %w", name, size, err))
+	}
+	switch corev1.PersistentVolumeAccessMode(accessMode) {
+	case corev1.ReadWriteOnce, corev1.ReadWriteMany:
+	default:
+		result = multierr.Append(result, fmt.Errorf("unsupported operator %s access mode %s, expected ReadWriteOnce or ReadWriteMany", name, accessMode))
+	}
+	return result
+}
+
 func decodeMavenSettings(mavenSettings string) (v1.ValueSource, error) {
 	sub := make([]string, 0)
 	rex := regexp.MustCompile(`^(configmap|secret):([a-zA-Z0-9][a-zA-Z0-9-]*)(/([a-zA-Z0-9].*))?$`)
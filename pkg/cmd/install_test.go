@@ -198,6 +198,73 @@ func TestInstallMonitoringFlag(t *testing.T) {
 	assert.Equal(t, int32(7777), installCmdOptions.MonitoringPort)
 }
 
+func TestInstallOperatorAPIClientFlags(t *testing.T) {
+	installCmdOptions, rootCmd, _ := initializeInstallCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInstall,
+		"--operator-api-qps", "50.5",
+		"--operator-api-burst", "500")
+	assert.Nil(t, err)
+	assert.Equal(t, float32(50.5), installCmdOptions.OperatorAPIQPS)
+	assert.Equal(t, 500, installCmdOptions.OperatorAPIBurst)
+}
+
+func TestInstallOperatorAPIClientNegativeFlags(t *testing.T) {
+	installCmdOptions, rootCmd, _ := initializeInstallCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInstall, "--operator-api-burst", "-1")
+	assert.Nil(t, err)
+	assert.NotNil(t, installCmdOptions.validate(nil, nil))
+}
+
+func TestInstallOperatorFlowSchemaFlags(t *testing.T) {
+	installCmdOptions, rootCmd, _ := initializeInstallCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInstall,
+		"--operator-flow-schema",
+		"--operator-flow-concurrency-shares", "100")
+	assert.Nil(t, err)
+	assert.Equal(t, true, installCmdOptions.OperatorFlowSchema)
+	assert.Equal(t, int32(100), installCmdOptions.OperatorFlowShares)
+}
+
+func TestInstallOperatorUploadStorageFlags(t *testing.T) {
+	installCmdOptions, rootCmd, _ := initializeInstallCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInstall,
+		"--operator-upload-storage-size", "10Gi",
+		"--operator-upload-storage-class", "nfs",
+		"--operator-upload-access-mode", "ReadWriteMany")
+	assert.Nil(t, err)
+	assert.Equal(t, "10Gi", installCmdOptions.OperatorUploadSize)
+	assert.Equal(t, "nfs", installCmdOptions.OperatorUploadClass)
+	assert.Equal(t, "ReadWriteMany", installCmdOptions.OperatorUploadAccessMode)
+	assert.Nil(t, installCmdOptions.validate(nil, nil))
+
+	installCmdOptions.OperatorUploadSize = "ten"
+	assert.NotNil(t, installCmdOptions.validate(nil, nil))
+	installCmdOptions.OperatorUploadSize = "10Gi"
+	installCmdOptions.OperatorUploadAccessMode = "ReadOnlyMany"
+	assert.NotNil(t, installCmdOptions.validate(nil, nil))
+}
+
+func TestInstallOperatorBuildCacheFlags(t *testing.T) {
+	installCmdOptions, rootCmd, _ := initializeInstallCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdInstall,
+		"--operator-build-cache-store", "configmap",
+		"--operator-build-cache-storage-size", "20Gi",
+		"--operator-build-cache-storage-class", "nfs",
+		"--operator-build-cache-access-mode", "ReadWriteMany")
+	assert.Nil(t, err)
+	assert.Equal(t, "configmap", installCmdOptions.OperatorBuildCacheStore)
+	assert.Equal(t, "20Gi", installCmdOptions.OperatorBuildCacheSize)
+	assert.Equal(t, "nfs", installCmdOptions.OperatorBuildCacheClass)
+	assert.Equal(t, "ReadWriteMany", installCmdOptions.OperatorBuildCacheMode)
+	assert.Nil(t, installCmdOptions.validate(nil, nil))
+
+	installCmdOptions.OperatorBuildCacheStore = "redis"
+	assert.NotNil(t, installCmdOptions.validate(nil, nil))
+	installCmdOptions.OperatorBuildCacheStore = "memory"
+	installCmdOptions.OperatorBuildCacheSize = "twenty"
+	assert.NotNil(t, installCmdOptions.validate(nil, nil))
+}
+
 func TestInstallOlmFalseFlag(t *testing.T) {
 	installCmdOptions, rootCmd, _ := initializeInstallCmdOptions(t)
 	_, err := test.ExecuteCommand(rootCmd, cmdInstall, "--olm=false")
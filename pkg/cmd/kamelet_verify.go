@@ -0,0 +1,300 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+const (
+	// kameletVerifyMarker prefixes the log line of the mock counterpart, whenever an exchange has gone through the Kamelet.
+	kameletVerifyMarker = "kamelet-verify: exchange completed"
+	// kameletVerifyLabel labels the integrations generated to verify a Kamelet.
+	kameletVerifyLabel = "camel.apache.org/kamelet.verify"
+)
+
+func newKameletVerifyCmd(rootCmdOptions *RootCmdOptions) (*cobra.Command, *kameletVerifyCommandOptions) {
+	options := kameletVerifyCommandOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+
+	cmd := cobra.Command{
+		Use:   "verify <kamelet>",
+		Short: "Verify a Kamelet by running it against a mock counterpart",
+		Long: `Verify a Kamelet, by running a throwaway integration that consumes the events of a source Kamelet and logs them, ` +
+			`or that sends the events of a timer to a sink or an action Kamelet. The verification passes once an exchange has gone through the Kamelet. ` +
+			`The required properties of the Kamelet are set with the --property flag, or default to the examples of its definition.`,
+		Args:    options.validateArgs,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().StringArrayP("property", "p", nil, "A property of the Kamelet, with the name=value format")
+	cmd.Flags().String("payload", "Camel K kamelet verification", "The body of the events sent to a sink or an action Kamelet")
+	cmd.Flags().String("name", "", "The name of the throwaway integration, defaults to the Kamelet name suffixed with -verify")
+	cmd.Flags().String("timeout", "15m", "The maximum duration of the verification")
+	cmd.Flags().Bool("keep", false, "Keep the throwaway integration and its kit once verified")
+
+	return &cmd, &options
+}
+
+type kameletVerifyCommandOptions struct {
+	*RootCmdOptions
+	Properties []string `mapstructure:"properties"`
+	Payload    string   `mapstructure:"payload"`
+	Name       string   `mapstructure:"name"`
+	Timeout    string   `mapstructure:"timeout"`
+	Keep       bool     `mapstructure:"keep"`
+}
+
+func (o *kameletVerifyCommandOptions) validateArgs(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("verify expects a single kamelet name argument")
+	}
+	return nil
+}
+
+func (o *kameletVerifyCommandOptions) run(cmd *cobra.Command, args []string) error {
+	timeout, err := time.ParseDuration(o.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid timeout %q", o.Timeout)
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	kamelet := v1alpha1.NewKamelet(o.Namespace, args[0])
+	if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(&kamelet), &kamelet); err != nil {
+		return errors.Wrapf(err, "could not find kamelet %s in namespace %s", args[0], o.Namespace)
+	}
+	if kamelet.Status.Phase != v1alpha1.KameletPhaseReady {
+		return fmt.Errorf("kamelet %s is not ready (phase %q)", kamelet.Name, kamelet.Status.Phase)
+	}
+
+	it, err := o.newVerification(c, &kamelet)
+	if err != nil {
+		return err
+	}
+
+	// The verification goes through the same stages as the self-test canary
+	selftest := selftestCmdOptions{
+		RootCmdOptions: o.RootCmdOptions,
+	}
+	deadline := time.Now().Add(timeout)
+	stages := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{"create", func() (string, error) { return selftest.create(c, it) }},
+		{"build", func() (string, error) { return selftest.waitForKit(c, it, deadline) }},
+		{"deploy", func() (string, error) { return selftest.waitForPhase(c, it, v1.IntegrationPhaseRunning, deadline) }},
+		{"exchange", func() (string, error) { return o.waitForExchange(c, it, &selftest, deadline) }},
+	}
+
+	report := make([]selftestStage, 0, len(stages)+1)
+	failed := false
+	for _, s := range stages {
+		if failed {
+			report = append(report, selftestStage{Name: s.name, Skipped: true})
+			continue
+		}
+		report = append(report, runSelftestStage(s.name, s.run))
+		failed = report[len(report)-1].Err != nil
+	}
+
+	if o.Keep {
+		report = append(report, selftestStage{Name: "cleanup", Skipped: true, Details: "kept integration " + it.Name})
+	} else {
+		report = append(report, runSelftestStage("cleanup", func() (string, error) {
+			return selftest.cleanup(c, it, time.Now().Add(5*time.Minute))
+		}))
+	}
+
+	printSelftestReport(cmd, report)
+
+	for _, s := range report {
+		if s.Err != nil {
+			return fmt.Errorf("verification of kamelet %s failed at stage %s", kamelet.Name, s.Name)
+		}
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Kamelet %s verified\n", kamelet.Name)
+	return nil
+}
+
+// newVerification returns the integration exercising the given Kamelet against a mock counterpart,
+// i.e., a log endpoint for a source Kamelet, and a timer for a sink or an action Kamelet.
+func (o *kameletVerifyCommandOptions) newVerification(c client.Client, kamelet *v1alpha1.Kamelet) (*v1.Integration, error) {
+	properties, err := o.kameletProperties(kamelet)
+	if err != nil {
+		return nil, err
+	}
+
+	name := o.Name
+	if name == "" {
+		name = kamelet.Name + "-verify"
+	}
+	it := v1.NewIntegration(o.Namespace, name)
+	it.Labels = map[string]string{
+		kameletVerifyLabel: kamelet.Name,
+	}
+	it.Spec.Sources = []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:    "verify.yaml",
+				Content: o.verificationFlow(kamelet),
+			},
+			Language: v1.LanguageYaml,
+		},
+	}
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		it.Spec.Configuration = append(it.Spec.Configuration, v1.ConfigurationSpec{
+			Type:  "property",
+			Value: fmt.Sprintf("camel.kamelet.%s.%s=%s", kamelet.Name, k, properties[k]),
+		})
+	}
+
+	traits, err := configureTraits([]string{
+		// The verification passes on the logs of the integration, not on its health
+		"health.enabled=false",
+	}, trait.NewCatalog(c))
+	if err != nil {
+		return nil, err
+	}
+	it.Spec.Traits = traits
+
+	return &it, nil
+}
+
+func (o *kameletVerifyCommandOptions) verificationFlow(kamelet *v1alpha1.Kamelet) string {
+	if kamelet.Labels[v1alpha1.KameletTypeLabel] == v1alpha1.KameletTypeSource {
+		return fmt.Sprintf(`- from:
+    uri: "kamelet:%s"
+    steps:
+      - log: "%s: ${body}"
+`, kamelet.Name, kameletVerifyMarker)
+	}
+
+	payload, _ := json.Marshal(o.Payload)
+	return fmt.Sprintf(`- from:
+    uri: "timer:kamelet-verify?period=5000"
+    steps:
+      - setBody:
+          constant: %s
+      - to: "kamelet:%s"
+      - log: "%s: ${body}"
+`, payload, kamelet.Name, kameletVerifyMarker)
+}
+
+// kameletProperties returns the properties set with the property flag, completed with the examples
+// of the required properties of the Kamelet that have no default value.
+func (o *kameletVerifyCommandOptions) kameletProperties(kamelet *v1alpha1.Kamelet) (map[string]string, error) {
+	properties := make(map[string]string, len(o.Properties))
+	for _, p := range o.Properties {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid property %q, it must be in the name=value format", p)
+		}
+		properties[kv[0]] = kv[1]
+	}
+
+	if kamelet.Spec.Definition == nil {
+		return properties, nil
+	}
+
+	missing := make([]string, 0)
+	for _, name := range kamelet.Spec.Definition.Required {
+		if _, ok := properties[name]; ok {
+			continue
+		}
+		prop := kamelet.Spec.Definition.Properties[name]
+		if prop.Default != nil {
+			continue
+		}
+		if prop.Example == nil {
+			missing = append(missing, name)
+			continue
+		}
+		var example interface{}
+		if err := json.Unmarshal(prop.Example.RawMessage, &example); err != nil {
+			return nil, errors.Wrapf(err, "invalid example of property %s", name)
+		}
+		if s, ok := example.(string); ok {
+			properties[name] = s
+		} else {
+			properties[name] = string(prop.Example.RawMessage)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing values for the required properties %s of kamelet %s, that have no example, set them with the --property flag",
+			strings.Join(missing, ", "), kamelet.Name)
+	}
+
+	return properties, nil
+}
+
+// waitForExchange waits for the mock counterpart to log that an exchange has gone through the Kamelet.
+func (o *kameletVerifyCommandOptions) waitForExchange(c client.Client, it *v1.Integration, selftest *selftestCmdOptions, deadline time.Time) (string, error) {
+	var details string
+	err := selftest.poll(deadline, func() (bool, error) {
+		pods := corev1.PodList{}
+		if err := c.List(o.Context, &pods, k8sclient.InNamespace(it.Namespace), k8sclient.MatchingLabels{v1.IntegrationLabel: it.Name}); err != nil {
+			return false, err
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			logs, err := c.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(o.Context)
+			if err != nil {
+				return false, nil
+			}
+			for _, line := range strings.Split(string(logs), "\n") {
+				if idx := strings.Index(line, kameletVerifyMarker); idx >= 0 {
+					details = strings.TrimSpace(line[idx:])
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	return details, err
+}
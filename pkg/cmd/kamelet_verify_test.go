@@ -0,0 +1,174 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdKamelet = "kamelet"
+
+func initializeKameletVerifyCmdOptions(t *testing.T, initObjs ...runtime.Object) (*kameletVerifyCommandOptions, *cobra.Command, client.Client) {
+	t.Helper()
+
+	c, err := test.NewFakeClient(initObjs...)
+	assert.Nil(t, err)
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	kameletCmd := cobra.Command{Use: cmdKamelet}
+	verifyCmd, verifyOptions := newKameletVerifyCmd(&options)
+	kameletCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(&kameletCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return verifyOptions, rootCmd, c
+}
+
+func newKameletVerifyTestKamelet(name string, kameletType string) *v1alpha1.Kamelet {
+	kamelet := v1alpha1.NewKamelet("default", name)
+	kamelet.Labels = map[string]string{
+		v1alpha1.KameletTypeLabel: kameletType,
+	}
+	kamelet.Spec.Definition = &v1alpha1.JSONSchemaProps{
+		Required: []string{"topic", "partitions", "brokers", "format"},
+		Properties: map[string]v1alpha1.JSONSchemaProp{
+			"topic": {
+				Type:    "string",
+				Example: &v1alpha1.JSON{RawMessage: []byte(`"orders"`)},
+			},
+			"partitions": {
+				Type:    "integer",
+				Example: &v1alpha1.JSON{RawMessage: []byte(`3`)},
+			},
+			"brokers": {
+				Type: "string",
+			},
+			"format": {
+				Type:    "string",
+				Default: &v1alpha1.JSON{RawMessage: []byte(`"json"`)},
+			},
+		},
+	}
+	kamelet.Status.Phase = v1alpha1.KameletPhaseReady
+	return &kamelet
+}
+
+func TestKameletVerifyProperties(t *testing.T) {
+	options, _, _ := initializeKameletVerifyCmdOptions(t)
+	kamelet := newKameletVerifyTestKamelet("kafka-source", v1alpha1.KameletTypeSource)
+
+	options.Properties = []string{"brokers=kafka:9092", "topic=payments"}
+	properties, err := options.kameletProperties(kamelet)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"brokers":    "kafka:9092",
+		"topic":      "payments",
+		"partitions": "3",
+	}, properties)
+
+	options.Properties = nil
+	_, err = options.kameletProperties(kamelet)
+	assert.NotNil(t, err)
+	assert.Equal(t, "missing values for the required properties brokers of kamelet kafka-source, that have no example, set them with the --property flag", err.Error())
+
+	options.Properties = []string{"brokers"}
+	_, err = options.kameletProperties(kamelet)
+	assert.NotNil(t, err)
+	assert.Equal(t, `invalid property "brokers", it must be in the name=value format`, err.Error())
+}
+
+func TestKameletVerifySource(t *testing.T) {
+	options, _, c := initializeKameletVerifyCmdOptions(t)
+	options.Namespace = "default"
+	options.Properties = []string{"brokers=kafka:9092"}
+	kamelet := newKameletVerifyTestKamelet("kafka-source", v1alpha1.KameletTypeSource)
+
+	it, err := options.newVerification(c, kamelet)
+	assert.Nil(t, err)
+	assert.Equal(t, "kafka-source-verify", it.Name)
+	assert.Equal(t, "kafka-source", it.Labels[kameletVerifyLabel])
+	assert.Len(t, it.Spec.Sources, 1)
+	assert.Equal(t, `- from:
+    uri: "kamelet:kafka-source"
+    steps:
+      - log: "kamelet-verify: exchange completed: ${body}"
+`, it.Spec.Sources[0].Content)
+	assert.Len(t, it.Spec.Configuration, 3)
+	assert.Equal(t, "camel.kamelet.kafka-source.brokers=kafka:9092", it.Spec.Configuration[0].Value)
+	assert.Equal(t, "camel.kamelet.kafka-source.partitions=3", it.Spec.Configuration[1].Value)
+	assert.Equal(t, "camel.kamelet.kafka-source.topic=orders", it.Spec.Configuration[2].Value)
+	assert.Contains(t, it.Spec.Traits, "health")
+}
+
+func TestKameletVerifySink(t *testing.T) {
+	options, _, c := initializeKameletVerifyCmdOptions(t)
+	options.Namespace = "default"
+	options.Name = "sink-check"
+	options.Payload = `{"id": 1}`
+	options.Properties = []string{"brokers=kafka:9092"}
+	kamelet := newKameletVerifyTestKamelet("kafka-sink", v1alpha1.KameletTypeSink)
+
+	it, err := options.newVerification(c, kamelet)
+	assert.Nil(t, err)
+	assert.Equal(t, "sink-check", it.Name)
+	assert.Equal(t, `- from:
+    uri: "timer:kamelet-verify?period=5000"
+    steps:
+      - setBody:
+          constant: "{\"id\": 1}"
+      - to: "kamelet:kafka-sink"
+      - log: "kamelet-verify: exchange completed: ${body}"
+`, it.Spec.Sources[0].Content)
+}
+
+func TestKameletVerifyNotReady(t *testing.T) {
+	kamelet := newKameletVerifyTestKamelet("kafka-sink", v1alpha1.KameletTypeSink)
+	kamelet.Status.Phase = v1alpha1.KameletPhaseError
+	_, rootCmd, _ := initializeKameletVerifyCmdOptions(t, kamelet)
+
+	_, err := test.ExecuteCommand(rootCmd, cmdKamelet, "verify", "kafka-sink", "-n", "default")
+	assert.NotNil(t, err)
+	assert.Equal(t, `kamelet kafka-sink is not ready (phase "Error")`, err.Error())
+}
+
+func TestKameletVerifyTimeout(t *testing.T) {
+	kamelet := newKameletVerifyTestKamelet("kafka-sink", v1alpha1.KameletTypeSink)
+	_, rootCmd, _ := initializeKameletVerifyCmdOptions(t, kamelet)
+
+	// No operator reconciles the integration, so that the build stage times out
+	output, err := test.ExecuteCommand(rootCmd, cmdKamelet, "verify", "kafka-sink", "-n", "default", "-p", "brokers=kafka:9092", "--timeout", "1s")
+	assert.NotNil(t, err)
+	assert.Equal(t, "verification of kamelet kafka-sink failed at stage build", err.Error())
+	assert.Contains(t, output, "create\tOK")
+	assert.Contains(t, output, "exchange\tSKIPPED")
+	assert.Contains(t, output, "cleanup\tOK")
+}
@@ -0,0 +1,243 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+func newCmdMigrateOperator(rootCmdOptions *RootCmdOptions) (*cobra.Command, *migrateOperatorCmdOptions) {
+	options := migrateOperatorCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:   "migrate-operator [integration...]",
+		Short: "Migrate integrations from an operator to another",
+		Long: `Migrate the given integrations, or all the integrations and Kamelet bindings of the namespace managed by ` +
+			`an operator when none is given, to another operator, e.g., a newer version installed side by side. ` +
+			`The integration kits are transferred to the new operator when they are compatible with its version, ` +
+			`otherwise the integrations are rebuilt by the new operator, and keep running until they are redeployed.`,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().String("from", "", "The id of the operator the integrations are migrated from, the operator without id if empty")
+	cmd.Flags().String("to", "", "The id of the operator the integrations are migrated to")
+	cmd.Flags().Bool("dry-run", false, "Print the migration of the integrations without applying it")
+
+	return &cmd, &options
+}
+
+type migrateOperatorCmdOptions struct {
+	*RootCmdOptions
+	From   string `mapstructure:"from"`
+	To     string `mapstructure:"to"`
+	DryRun bool   `mapstructure:"dry-run"`
+}
+
+func (o *migrateOperatorCmdOptions) run(cmd *cobra.Command, args []string) error {
+	if o.From == o.To {
+		return errors.New("the operators to migrate the integrations from and to must be different, use the --from and --to flags")
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	platforms := v1.NewIntegrationPlatformList()
+	if err := c.List(o.Context, &platforms); err != nil {
+		return errors.Wrap(err, "could not retrieve the installed operators")
+	}
+	from := o.operatorPlatform(platforms, o.From)
+	to := o.operatorPlatform(platforms, o.To)
+	if to == nil {
+		return fmt.Errorf("no platform found for operator %q", o.To)
+	}
+	if to.Status.Version == "" {
+		return fmt.Errorf("the platform %s of operator %q is not initialized yet", to.Name, o.To)
+	}
+
+	if len(args) == 0 {
+		if err := o.migrateBindings(cmd, c, from, to); err != nil {
+			return err
+		}
+	}
+
+	integrations, err := o.integrationsToMigrate(c, args)
+	if err != nil {
+		return err
+	}
+	for _, i := range integrations {
+		it := i
+		if err := o.migrateIntegration(cmd, c, &it, from, to); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// operatorPlatform returns the platform of the operator, preferably from the current namespace,
+// as a global operator platform lives in the operator namespace.
+func (o *migrateOperatorCmdOptions) operatorPlatform(platforms v1.IntegrationPlatformList, operatorID string) *v1.IntegrationPlatform {
+	var found *v1.IntegrationPlatform
+	for i := range platforms.Items {
+		p := &platforms.Items[i]
+		if p.Annotations[v1.OperatorIDAnnotation] != operatorID {
+			continue
+		}
+		if p.Namespace == o.Namespace {
+			return p
+		}
+		if found == nil {
+			found = p
+		}
+	}
+	return found
+}
+
+func (o *migrateOperatorCmdOptions) integrationsToMigrate(c client.Client, names []string) ([]v1.Integration, error) {
+	if len(names) == 0 {
+		list := v1.NewIntegrationList()
+		if err := c.List(o.Context, &list, k8sclient.InNamespace(o.Namespace)); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("could not retrieve integrations from namespace %s", o.Namespace))
+		}
+		integrations := make([]v1.Integration, 0, len(list.Items))
+		for _, it := range list.Items {
+			if it.Annotations[v1.OperatorIDAnnotation] == o.From {
+				integrations = append(integrations, it)
+			}
+		}
+		return integrations, nil
+	}
+
+	integrations := make([]v1.Integration, 0, len(names))
+	for _, n := range names {
+		it := v1.NewIntegration(o.Namespace, n)
+		if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(&it), &it); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("could not find integration %s in namespace %s", n, o.Namespace))
+		}
+		if it.Annotations[v1.OperatorIDAnnotation] != o.From {
+			return nil, fmt.Errorf("integration %s is not managed by operator %q", n, o.From)
+		}
+		integrations = append(integrations, it)
+	}
+	return integrations, nil
+}
+
+// migrateBindings reassigns the Kamelet bindings first, so that their controller, in the new operator,
+// does not revert the annotations of the integrations they own.
+func (o *migrateOperatorCmdOptions) migrateBindings(cmd *cobra.Command, c client.Client, from *v1.IntegrationPlatform, to *v1.IntegrationPlatform) error {
+	list := v1alpha1.NewKameletBindingList()
+	if err := c.List(o.Context, &list, k8sclient.InNamespace(o.Namespace)); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not retrieve Kamelet bindings from namespace %s", o.Namespace))
+	}
+	for _, b := range list.Items {
+		binding := b
+		if binding.Annotations[v1.OperatorIDAnnotation] != o.From {
+			continue
+		}
+		if !o.DryRun {
+			target := binding.DeepCopy()
+			target.Annotations = o.reassign(target.Annotations, from, to)
+			if err := c.Patch(o.Context, target, k8sclient.MergeFrom(&binding)); err != nil {
+				return errors.Wrap(err, fmt.Sprintf("could not migrate Kamelet binding %s in namespace %s", binding.Name, o.Namespace))
+			}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Kamelet binding %s migrated to operator %q\n", binding.Name, o.To)
+	}
+	return nil
+}
+
+// migrateIntegration reassigns the integration, and its kit when it's been built for the version of the new operator,
+// so that the integration keeps running without being rebuilt. Otherwise, its status is cleared, so that the new
+// operator rebuilds it, as the status and the kit of an older version may not be supported.
+func (o *migrateOperatorCmdOptions) migrateIntegration(cmd *cobra.Command, c client.Client, it *v1.Integration, from *v1.IntegrationPlatform, to *v1.IntegrationPlatform) error {
+	var kit *v1.IntegrationKit
+	if ref := it.Status.IntegrationKit; ref != nil && it.Status.Version == to.Status.Version {
+		k, err := kubernetes.GetIntegrationKit(o.Context, c, ref.Name, ref.Namespace)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("could not find kit %s of integration %s", ref.Name, it.Name))
+		}
+		if k.Status.Version == to.Status.Version {
+			kit = k
+		}
+	}
+
+	if o.DryRun {
+		if kit != nil {
+			fmt.Fprintf(cmd.OutOrStdout(), "Integration %s would be migrated to operator %q with kit %s\n", it.Name, o.To, kit.Name)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Integration %s would be migrated to operator %q and rebuilt for version %s\n", it.Name, o.To, to.Status.Version)
+		}
+		return nil
+	}
+
+	if kit != nil && kit.Annotations[v1.OperatorIDAnnotation] == o.From {
+		target := kit.DeepCopy()
+		target.Annotations = o.reassign(target.Annotations, from, to)
+		if err := c.Patch(o.Context, target, k8sclient.MergeFrom(kit)); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("could not transfer kit %s of integration %s", kit.Name, it.Name))
+		}
+	}
+
+	target := it.DeepCopy()
+	target.Annotations = o.reassign(target.Annotations, from, to)
+	if err := c.Patch(o.Context, target, k8sclient.MergeFrom(it)); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not migrate integration %s in namespace %s", it.Name, o.Namespace))
+	}
+
+	if kit != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "Integration %s migrated to operator %q with kit %s\n", it.Name, o.To, kit.Name)
+		return nil
+	}
+
+	target.Status = v1.IntegrationStatus{}
+	if err := c.Status().Update(o.Context, target); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("could not rebuild integration %s in namespace %s", it.Name, o.Namespace))
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Integration %s migrated to operator %q and rebuilt for version %s\n", it.Name, o.To, to.Status.Version)
+	return nil
+}
+
+// reassign sets the operator id annotation, and the platform selector when it selects the platform of the previous operator.
+func (o *migrateOperatorCmdOptions) reassign(annotations map[string]string, from *v1.IntegrationPlatform, to *v1.IntegrationPlatform) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if o.To == "" {
+		delete(annotations, v1.OperatorIDAnnotation)
+	} else {
+		annotations[v1.OperatorIDAnnotation] = o.To
+	}
+	if selected, ok := annotations[v1.PlatformSelectorAnnotation]; ok && from != nil && selected == from.Name {
+		annotations[v1.PlatformSelectorAnnotation] = to.Name
+	}
+	return annotations
+}
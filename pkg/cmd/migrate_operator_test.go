@@ -0,0 +1,161 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdMigrateOperator = "migrate-operator"
+
+func initializeMigrateOperatorCmdOptions(t *testing.T, c client.Client) *cobra.Command {
+	t.Helper()
+
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	migrateCmd, _ := newCmdMigrateOperator(&options)
+	rootCmd.AddCommand(migrateCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return rootCmd
+}
+
+func newMigrateOperatorTestClient(t *testing.T) client.Client {
+	t.Helper()
+
+	platform := func(namespace string, operatorID string, version string) *v1.IntegrationPlatform {
+		pl := v1.NewIntegrationPlatform(namespace, "camel-k")
+		pl.Annotations = map[string]string{
+			v1.OperatorIDAnnotation: operatorID,
+		}
+		pl.Status.Version = version
+		return &pl
+	}
+	kit := func(name string, version string) *v1.IntegrationKit {
+		k := v1.NewIntegrationKit("default", name)
+		k.Annotations = map[string]string{
+			v1.OperatorIDAnnotation: "operator-a",
+		}
+		k.Status.Version = version
+		return k
+	}
+	integration := func(name string, kit string, version string) *v1.Integration {
+		it := v1.NewIntegration("default", name)
+		it.Annotations = map[string]string{
+			v1.OperatorIDAnnotation: "operator-a",
+		}
+		it.Status.Phase = v1.IntegrationPhaseRunning
+		it.Status.Version = version
+		it.Status.IntegrationKit = &corev1.ObjectReference{Namespace: "default", Name: kit}
+		return &it
+	}
+	binding := v1alpha1.NewKameletBinding("default", "binding")
+	binding.Annotations = map[string]string{
+		v1.OperatorIDAnnotation: "operator-a",
+	}
+
+	c, err := test.NewFakeClient(
+		platform("operators-a", "operator-a", "1.8.0"),
+		platform("operators-b", "operator-b", "1.9.0"),
+		kit("kit-compatible", "1.9.0"),
+		kit("kit-outdated", "1.8.0"),
+		integration("compatible", "kit-compatible", "1.9.0"),
+		integration("outdated", "kit-outdated", "1.8.0"),
+		&binding,
+	)
+	assert.Nil(t, err)
+	return c
+}
+
+func TestMigrateOperatorTransfersCompatibleKit(t *testing.T) {
+	c := newMigrateOperatorTestClient(t)
+	rootCmd := initializeMigrateOperatorCmdOptions(t, c)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdMigrateOperator, "-n", "default", "compatible", "--from", "operator-a", "--to", "operator-b")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "Integration compatible migrated to operator \"operator-b\" with kit kit-compatible")
+
+	it := v1.NewIntegration("default", "compatible")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&it), &it))
+	assert.Equal(t, "operator-b", it.Annotations[v1.OperatorIDAnnotation])
+	assert.Equal(t, v1.IntegrationPhaseRunning, it.Status.Phase)
+
+	kit := v1.NewIntegrationKit("default", "kit-compatible")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(kit), kit))
+	assert.Equal(t, "operator-b", kit.Annotations[v1.OperatorIDAnnotation])
+	assert.Equal(t, "operator-a", operatorIDOf(t, c, "outdated"))
+}
+
+func TestMigrateOperatorRebuildsOutdatedIntegration(t *testing.T) {
+	c := newMigrateOperatorTestClient(t)
+	rootCmd := initializeMigrateOperatorCmdOptions(t, c)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdMigrateOperator, "-n", "default", "--from", "operator-a", "--to", "operator-b")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "Kamelet binding binding migrated to operator \"operator-b\"")
+	assert.Contains(t, output, "Integration outdated migrated to operator \"operator-b\" and rebuilt for version 1.9.0")
+
+	it := v1.NewIntegration("default", "outdated")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&it), &it))
+	assert.Equal(t, "operator-b", it.Annotations[v1.OperatorIDAnnotation])
+	assert.Equal(t, v1.IntegrationPhaseNone, it.Status.Phase)
+
+	kit := v1.NewIntegrationKit("default", "kit-outdated")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(kit), kit))
+	assert.Equal(t, "operator-a", kit.Annotations[v1.OperatorIDAnnotation])
+
+	binding := v1alpha1.NewKameletBinding("default", "binding")
+	assert.Nil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&binding), &binding))
+	assert.Equal(t, "operator-b", binding.Annotations[v1.OperatorIDAnnotation])
+}
+
+func TestMigrateOperatorDryRun(t *testing.T) {
+	c := newMigrateOperatorTestClient(t)
+	rootCmd := initializeMigrateOperatorCmdOptions(t, c)
+
+	output, err := test.ExecuteCommand(rootCmd, cmdMigrateOperator, "-n", "default", "--from", "operator-a", "--to", "operator-b", "--dry-run")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "Integration outdated would be migrated to operator \"operator-b\" and rebuilt for version 1.9.0")
+	assert.Equal(t, "operator-a", operatorIDOf(t, c, "outdated"))
+	assert.Equal(t, "operator-a", operatorIDOf(t, c, "compatible"))
+}
+
+func TestMigrateOperatorUnknownOperator(t *testing.T) {
+	c := newMigrateOperatorTestClient(t)
+	rootCmd := initializeMigrateOperatorCmdOptions(t, c)
+
+	_, err := test.ExecuteCommand(rootCmd, cmdMigrateOperator, "-n", "default", "--from", "operator-a", "--to", "operator-unknown")
+	assert.NotNil(t, err)
+}
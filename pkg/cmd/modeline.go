@@ -98,7 +98,7 @@ func createKamelWithModelineCommand(ctx context.Context, args []string) (*cobra.
 	}
 
 	isLocalBuild := target.Name() == buildCmdName && target.Parent().Name() == localCmdName
-	isInspect := target.Name() == inspectCmdName
+	isInspect := target.Name() == inspectCmdName && target.Parent().Name() == localCmdName
 
 	if target.Name() != runCmdName && !isLocalBuild && !isInspect {
 		return rootCmd, args, nil
@@ -126,6 +126,8 @@ func createKamelWithModelineCommand(ctx context.Context, args []string) (*cobra.
 	files := make([]string, 0, len(fg.Args())+len(additionalSources))
 	files = append(files, fg.Args()...)
 	files = append(files, additionalSources...)
+	// The standard input can only be read once, so that the modelines of the sources it provides are ignored
+	files, _ = splitStdinSources(files)
 
 	opts, err := extractModelineOptions(ctx, files, rootCmd)
 	if err != nil {
@@ -40,19 +40,25 @@ func newCmdOperator() (*cobra.Command, *operatorCmdOptions) {
 
 	cmd.Flags().Int32("health-port", 8081, "The port of the health endpoint")
 	cmd.Flags().Int32("monitoring-port", 8080, "The port of the metrics endpoint")
+	cmd.Flags().Int32("inspect-port", 8082, "The port of the inspect and schema endpoints, or 0 to disable them")
 	cmd.Flags().Bool("leader-election", true, "Use leader election")
 	cmd.Flags().String("leader-election-id", platform.OperatorLockName, "Use the given ID as the leader election Lease name")
+	cmd.Flags().Float32("api-qps", 20, "The maximum queries per second of the requests made to the Kubernetes API server")
+	cmd.Flags().Int("api-burst", 200, "The maximum burst of the requests made to the Kubernetes API server, above the queries per second")
 
 	return &cmd, &options
 }
 
 type operatorCmdOptions struct {
-	HealthPort       int32  `mapstructure:"health-port"`
-	MonitoringPort   int32  `mapstructure:"monitoring-port"`
-	LeaderElection   bool   `mapstructure:"leader-election"`
-	LeaderElectionID string `mapstructure:"leader-election-id"`
+	HealthPort       int32   `mapstructure:"health-port"`
+	MonitoringPort   int32   `mapstructure:"monitoring-port"`
+	InspectPort      int32   `mapstructure:"inspect-port"`
+	LeaderElection   bool    `mapstructure:"leader-election"`
+	LeaderElectionID string  `mapstructure:"leader-election-id"`
+	APIQPS           float32 `mapstructure:"api-qps"`
+	APIBurst         int     `mapstructure:"api-burst"`
 }
 
 func (o *operatorCmdOptions) run(_ *cobra.Command, _ []string) {
-	operator.Run(o.HealthPort, o.MonitoringPort, o.LeaderElection, o.LeaderElectionID)
+	operator.Run(o.HealthPort, o.MonitoringPort, o.InspectPort, o.LeaderElection, o.LeaderElectionID, o.APIQPS, o.APIBurst)
 }
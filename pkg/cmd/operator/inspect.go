@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/apache/camel-k/pkg/metadata"
+	"github.com/apache/camel-k/pkg/util/camel"
+)
+
+// maxInspectRequestSize limits the size of the sources that can be inspected at once.
+const maxInspectRequestSize = 10 * 1024 * 1024
+
+// inspectHandler extracts the metadata of Integration sources, using the operator catalog, so that they can be
+// validated without being deployed.
+func inspectHandler(catalog *camel.RuntimeCatalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		request := metadata.InspectRequest{}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxInspectRequestSize)).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid inspect request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(request.Sources) == 0 {
+			http.Error(w, "no sources to inspect", http.StatusBadRequest)
+			return
+		}
+
+		inspection, err := metadata.Inspect(catalog, request.Sources)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(inspection); err != nil {
+			log.Error(err, "unable to write the inspect response")
+		}
+	}
+}
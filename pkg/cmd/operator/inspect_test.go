@@ -0,0 +1,80 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/metadata"
+	"github.com/apache/camel-k/pkg/util/camel"
+)
+
+func TestInspectHandler(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	body := `{"sources": [{"name": "routes.java", "content": "from(\"timer:tick\").to(\"log:info\");"}]}`
+	request := httptest.NewRequest(http.MethodPost, metadata.InspectPath, strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+
+	inspectHandler(catalog).ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	inspection := metadata.Inspection{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &inspection))
+	assert.Equal(t, []string{"log", "timer"}, inspection.Components)
+	assert.Equal(t, []string{"timer:tick"}, inspection.FromURIs)
+	assert.Equal(t, []string{"log:info"}, inspection.ToURIs)
+	assert.ElementsMatch(t, []string{"camel:log", "camel:timer"}, inspection.Dependencies)
+}
+
+func TestInspectHandlerErrors(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+	}{
+		{name: "method", method: http.MethodGet, code: http.StatusMethodNotAllowed},
+		{name: "malformed", method: http.MethodPost, body: `{"sources": `, code: http.StatusBadRequest},
+		{name: "empty", method: http.MethodPost, body: `{"sources": []}`, code: http.StatusBadRequest},
+		{name: "language", method: http.MethodPost, body: `{"sources": [{"name": "routes.txt", "content": ""}]}`, code: http.StatusUnprocessableEntity},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, metadata.InspectPath, strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+
+			inspectHandler(catalog).ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.code, recorder.Code)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"flag"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
 	"runtime"
 	"strconv"
@@ -61,9 +62,11 @@ import (
 	"github.com/apache/camel-k/pkg/event"
 	"github.com/apache/camel-k/pkg/install"
 	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/trait"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	camelLog "github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 var log = logf.Log.WithName("cmd")
@@ -91,7 +94,7 @@ func (l loglvl) Enabled(lvl zapcore.Level) bool {
 }
 
 // Run starts the Camel K operator.
-func Run(healthPort, monitoringPort int32, leaderElection bool, leaderElectionID string) {
+func Run(healthPort, monitoringPort, inspectPort int32, leaderElection bool, leaderElectionID string, apiQPS float32, apiBurst int) {
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	flag.Parse()
@@ -141,11 +144,16 @@ func Run(healthPort, monitoringPort int32, leaderElection bool, leaderElectionID
 
 	cfg, err := config.GetConfig()
 	exitOnError(err, "cannot get client config")
-	// Increase maximum burst that is used by client-side throttling,
-	// to prevent the requests made to apply the bundled Kamelets
-	// from being throttled.
-	cfg.QPS = 20
-	cfg.Burst = 200
+	// The defaults increase the maximum burst that is used by client-side throttling,
+	// to prevent the requests made to apply the bundled Kamelets from being throttled.
+	// They can be tuned on large clusters, where the operator may otherwise be throttled,
+	// or may overwhelm the API server.
+	if apiQPS <= 0 || apiBurst <= 0 {
+		exitOnError(fmt.Errorf("the API client QPS and burst must be positive, got %v and %d", apiQPS, apiBurst), "invalid API client configuration")
+	}
+	cfg.QPS = apiQPS
+	cfg.Burst = apiBurst
+	log.Info(fmt.Sprintf("API client QPS: %v, burst: %d", apiQPS, apiBurst))
 	c, err := client.NewClientWithConfig(false, cfg)
 	exitOnError(err, "cannot initialize client")
 
@@ -176,7 +184,9 @@ func Run(healthPort, monitoringPort int32, leaderElection bool, leaderElectionID
 	}
 
 	// Set the operator container image if it runs in-container
-	platform.OperatorImage, err = getOperatorImage(context.TODO(), c)
+	operatorPod, err := getOperatorPod(context.TODO(), c)
+	exitOnError(err, "cannot get operator pod")
+	platform.OperatorImage, err = getOperatorImage(operatorPod)
 	exitOnError(err, "cannot get operator container image")
 
 	if ok, err := kubernetes.CheckPermission(context.TODO(), c, coordination.GroupName, "leases", operatorNamespace, "", "create"); err != nil || !ok {
@@ -193,6 +203,9 @@ func Run(healthPort, monitoringPort int32, leaderElection bool, leaderElectionID
 	exitOnError(err, "cannot create Integration label selector")
 	selector := labels.NewSelector().Add(*hasIntegrationLabel)
 
+	hasPrePullLabel, err := labels.NewRequirement(v1.IntegrationKitPrePullLabel, selection.Exists, []string{})
+	exitOnError(err, "cannot create image pre-pull label selector")
+
 	mgr, err := manager.New(c.GetConfig(), manager.Options{
 		Namespace:                     watchNamespace,
 		EventBroadcaster:              broadcaster,
@@ -211,6 +224,7 @@ func Run(healthPort, monitoringPort int32, leaderElection bool, leaderElectionID
 					&batchv1beta1.CronJob{}: {Label: selector},
 					&batchv1.Job{}:          {Label: selector},
 					&servingv1.Service{}:    {Label: selector},
+					&appsv1.DaemonSet{}:     {Label: labels.NewSelector().Add(*hasPrePullLabel)},
 				},
 			},
 		),
@@ -225,15 +239,46 @@ func Run(healthPort, monitoringPort int32, leaderElection bool, leaderElectionID
 		"unable to set up field indexer for status.phase: %v",
 	)
 
+	// The external traits must be registered before the controllers create any trait catalog
+	if operatorNamespace != "" {
+		if err := trait.LoadExternalTraits(context.TODO(), c, operatorNamespace); err != nil {
+			log.Info("Cannot load the external traits: skipping.")
+			log.V(8).Info("Error while loading the external traits", "error", err)
+		}
+	}
+
 	log.Info("Configuring manager")
 	exitOnError(mgr.AddHealthzCheck("health-probe", healthz.Ping), "Unable add liveness check")
 	exitOnError(apis.AddToScheme(mgr.GetScheme()), "")
 	exitOnError(controller.AddToManager(mgr), "")
+	if inspectPort > 0 {
+		var store *upload.Store
+		if dir := upload.Dir(); dir != "" {
+			store = upload.NewStore(dir)
+			pruner, err := newUploadPruner(store, mgr.GetClient())
+			exitOnError(err, "cannot create the upload store pruner")
+			exitOnError(mgr.Add(pruner), "")
+			if operatorPod != nil && operatorPod.Status.PodIP != "" {
+				// The builder Pods fetch the uploaded files from the operator
+				platform.OperatorUploadURL = "http://" + net.JoinHostPort(operatorPod.Status.PodIP, strconv.Itoa(int(inspectPort)))
+			}
+		}
+		lease := ctrl.ObjectKey{Namespace: operatorNamespace, Name: leaderElectionID}
+		apiServer, err := newAPIServer(inspectPort, c, store, mgr.Elected(), lease)
+		exitOnError(err, "cannot create the API server")
+		exitOnError(mgr.Add(apiServer), "")
+	}
 
 	log.Info("Installing operator resources")
 	installCtx, installCancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer installCancel()
 	install.OperatorStartupOptionalTools(installCtx, c, watchNamespace, operatorNamespace, log)
+	if operatorNamespace != "" {
+		if err := installSchemas(installCtx, c, operatorNamespace); err != nil {
+			log.Info("Cannot install the JSON schemas ConfigMap: skipping.")
+			log.V(8).Info("Error while installing the JSON schemas ConfigMap", "error", err)
+		}
+	}
 
 	log.Info("Starting the manager")
 	exitOnError(mgr.Start(signals.SetupSignalHandler()), "manager exited non-zero")
@@ -248,19 +293,27 @@ func getWatchNamespace() (string, error) {
 	return ns, nil
 }
 
-// getOperatorImage returns the image currently used by the running operator if present (when running out of cluster, it may be absent).
-func getOperatorImage(ctx context.Context, c ctrl.Reader) (string, error) {
+// getOperatorPod returns the Pod of the running operator if present (when running out of cluster, it may be absent).
+func getOperatorPod(ctx context.Context, c ctrl.Reader) (*corev1.Pod, error) {
 	ns := platform.GetOperatorNamespace()
 	name := platform.GetOperatorPodName()
 	if ns == "" || name == "" {
-		return "", nil
+		return nil, nil
 	}
 
 	pod := corev1.Pod{}
 	if err := c.Get(ctx, ctrl.ObjectKey{Namespace: ns, Name: name}, &pod); err != nil && k8serrors.IsNotFound(err) {
-		return "", nil
+		return nil, nil
 	} else if err != nil {
-		return "", err
+		return nil, err
+	}
+	return &pod, nil
+}
+
+// getOperatorImage returns the image currently used by the running operator if present (when running out of cluster, it may be absent).
+func getOperatorImage(pod *corev1.Pod) (string, error) {
+	if pod == nil {
+		return "", nil
 	}
 	if len(pod.Spec.Containers) == 0 {
 		return "", fmt.Errorf("no containers found in operator pod")
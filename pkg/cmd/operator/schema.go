@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/schema"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+// schemasConfigMapName is the name of the ConfigMap that contains the JSON schemas, so that they can be
+// retrieved from the cluster without accessing the operator endpoint.
+const schemasConfigMapName = "camel-k-schemas"
+
+// schemaHandler serves the JSON schemas of the traits configuration and of the YAML DSL.
+func schemaHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, schema.Path)
+		if !isSchema(name) {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := schema.Generate(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/schema+json")
+		if _, err := w.Write(data); err != nil {
+			log.Error(err, "unable to write the schema response")
+		}
+	}
+}
+
+func isSchema(name string) bool {
+	for _, n := range schema.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// installSchemas creates, or updates, the ConfigMap containing the JSON schemas in the operator namespace.
+func installSchemas(ctx context.Context, c client.Client, namespace string) error {
+	cm := corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      schemasConfigMapName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "camel-k",
+			},
+		},
+		Data: make(map[string]string),
+	}
+	for _, name := range schema.Names() {
+		data, err := schema.Generate(name)
+		if err != nil {
+			return err
+		}
+		cm.Data[name] = string(data)
+	}
+
+	return kubernetes.ReplaceResource(ctx, c, &cm)
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/schema"
+)
+
+func TestSchemaHandler(t *testing.T) {
+	for _, name := range schema.Names() {
+		request := httptest.NewRequest(http.MethodGet, schema.Path+name, nil)
+		recorder := httptest.NewRecorder()
+
+		schemaHandler().ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/schema+json", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), "\"$schema\"")
+	}
+}
+
+func TestSchemaHandlerErrors(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, schema.Path+"unknown.json", nil)
+	recorder := httptest.NewRecorder()
+	schemaHandler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	request = httptest.NewRequest(http.MethodPost, schema.Path+schema.TraitsSchema, nil)
+	recorder = httptest.NewRecorder()
+	schemaHandler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
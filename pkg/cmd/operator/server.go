@@ -0,0 +1,144 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/metadata"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/schema"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/upload"
+)
+
+// apiServer serves the endpoints that tools, e.g., the CLI or IDE plugins, use to work against the exact
+// operator version, i.e., the sources inspection, the JSON schemas, and the upload of the files added to the builds.
+type apiServer struct {
+	server *http.Server
+}
+
+var _ manager.Runnable = &apiServer{}
+var _ manager.LeaderElectionRunnable = &apiServer{}
+
+// newAPIServer returns the server of the API endpoints. The uploads are forwarded to the leader, elected with the given
+// Lease, as it runs the builds and prunes the store, that is nil when no persistent volume is mounted for the uploads.
+func newAPIServer(port int32, c client.Client, store *upload.Store, elected <-chan struct{}, lease ctrl.ObjectKey) (*apiServer, error) {
+	catalog, err := camel.DefaultCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metadata.InspectPath, inspectHandler(catalog))
+	mux.Handle(schema.Path, schemaHandler())
+	mux.Handle(upload.Path, forwardToLeader(elected, leaderAddress(c, lease), port, uploadHandler(reviewToken(c), store, c)))
+
+	return &apiServer{
+		server: &http.Server{
+			Addr:              ":" + strconv.Itoa(int(port)),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Start serves the endpoints until the context is done.
+func (s *apiServer) Start(ctx context.Context) error {
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("Starting the API server", "address", s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return s.server.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection returns false, so that all the operator replicas serve the endpoints, the uploads being forwarded
+// to the leader.
+func (s *apiServer) NeedLeaderElection() bool {
+	return false
+}
+
+// forwardToLeader serves the requests when the operator is the leader, and forwards them to the leader otherwise.
+func forwardToLeader(elected <-chan struct{}, leader func(context.Context) (string, error), port int32, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-elected:
+			next.ServeHTTP(w, r)
+			return
+		default:
+		}
+
+		if r.Header.Get(forwardedHeader) != "" {
+			http.Error(w, "the operator is not the leader", http.StatusServiceUnavailable)
+			return
+		}
+		address, err := leader(r.Context())
+		if err != nil {
+			log.Error(err, "unable to find the operator leader")
+			http.Error(w, "unable to find the operator leader", http.StatusServiceUnavailable)
+			return
+		}
+		r.Header.Set(forwardedHeader, "true")
+		httputil.NewSingleHostReverseProxy(&url.URL{
+			Scheme: "http",
+			Host:   net.JoinHostPort(address, strconv.Itoa(int(port))),
+		}).ServeHTTP(w, r)
+	})
+}
+
+// leaderAddress returns the function resolving the IP of the operator Pod holding the leader election Lease.
+func leaderAddress(c ctrl.Reader, lease ctrl.ObjectKey) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		name, err := platform.GetOperatorLeaderPodName(ctx, c, lease)
+		if err != nil {
+			return "", err
+		}
+		pod := corev1.Pod{}
+		if err := c.Get(ctx, ctrl.ObjectKey{Namespace: lease.Namespace, Name: name}, &pod); err != nil {
+			return "", err
+		}
+		if pod.Status.PodIP == "" {
+			return "", fmt.Errorf("the operator leader %s has no IP", name)
+		}
+		return pod.Status.PodIP, nil
+	}
+}
@@ -0,0 +1,365 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/upload"
+)
+
+// maxUploadChunkSize limits the size of each chunk of an upload, the size of the uploaded files being unlimited.
+const maxUploadChunkSize = 8 * 1024 * 1024
+
+const (
+	// forwardedHeader marks the requests forwarded to the leader, so that they are not forwarded again.
+	forwardedHeader = "X-Camel-K-Forwarded"
+	// uploadRetentionEnvVariable is the environment variable setting how long the unused files are kept in the store.
+	uploadRetentionEnvVariable = "KAMEL_UPLOAD_RETENTION"
+	defaultUploadRetention     = 24 * time.Hour
+	uploadPruneInterval        = 1 * time.Hour
+)
+
+// uploadAuthorizer checks that the user owning the token is granted the access to the resource.
+type uploadAuthorizer func(ctx context.Context, token string, attributes authorizationv1.ResourceAttributes) error
+
+// reviewToken authenticates the users with TokenReviews, and authorizes their access with SubjectAccessReviews.
+func reviewToken(c kubernetes.Interface) uploadAuthorizer {
+	return func(ctx context.Context, token string, attributes authorizationv1.ResourceAttributes) error {
+		if token == "" {
+			return errUnauthenticated
+		}
+		review, err := c.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		if !review.Status.Authenticated {
+			return errUnauthenticated
+		}
+
+		user := review.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for k, v := range user.Extra {
+			extra[k] = authorizationv1.ExtraValue(v)
+		}
+		access, err := c.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: &attributes,
+				User:               user.Username,
+				UID:                user.UID,
+				Groups:             user.Groups,
+				Extra:              extra,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		if !access.Status.Allowed {
+			return fmt.Errorf("%w: user %s cannot %s %s in namespace %s", errUnauthorized,
+				user.Username, attributes.Verb, attributes.Resource, attributes.Namespace)
+		}
+
+		return nil
+	}
+}
+
+var (
+	errUnauthenticated = errors.New("unauthenticated")
+	errUnauthorized    = errors.New("forbidden")
+)
+
+// uploadHandler receives the files uploaded by the CLI, in chunks, and stores them for the builds, so that they are
+// neither limited in size, nor stored in ConfigMaps. An upload is created with `POST /upload/<namespace>`,
+// its chunks are sent with `PUT /upload/<namespace>/<id>?offset=<size received so far>`, and it's committed with
+// `POST /upload/<namespace>/<id>?digest=sha256:<hex>`. The users must be allowed to create Integrations
+// in the namespace. The builder Pods fetch the files added to their Build with
+// `GET /upload/<namespace>/sha256:<hex>?build=<build namespace>/<build name>`, and must be allowed to get the Build.
+func uploadHandler(authorize uploadAuthorizer, store *upload.Store, builds ctrl.Reader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if store == nil {
+			http.Error(w, "the upload store is not configured, the operator must be installed with a persistent volume for the uploads",
+				http.StatusServiceUnavailable)
+			return
+		}
+
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, upload.Path), "/"), "/")
+		namespace := parts[0]
+		if namespace == "" || len(parts) > 2 {
+			http.NotFound(w, r)
+			return
+		}
+
+		attributes := authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "create",
+			Group:     v1.SchemeGroupVersion.Group,
+			Resource:  "integrations",
+		}
+		var build ctrl.ObjectKey
+		if len(parts) == 2 && r.Method == http.MethodGet {
+			ref := strings.SplitN(r.URL.Query().Get(upload.BuildParam), "/", 2)
+			if len(ref) != 2 || ref[0] == "" || ref[1] == "" {
+				http.Error(w, "invalid build, expected <namespace>/<name>", http.StatusBadRequest)
+				return
+			}
+			build = ctrl.ObjectKey{Namespace: ref[0], Name: ref[1]}
+			attributes = authorizationv1.ResourceAttributes{
+				Namespace: build.Namespace,
+				Verb:      "get",
+				Group:     v1.SchemeGroupVersion.Group,
+				Resource:  "builds",
+				Name:      build.Name,
+			}
+		}
+
+		if err := authorize(r.Context(), r.Header.Get(upload.TokenHeader), attributes); err != nil {
+			switch {
+			case errors.Is(err, errUnauthenticated):
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			case errors.Is(err, errUnauthorized):
+				http.Error(w, err.Error(), http.StatusForbidden)
+			default:
+				log.Error(err, "unable to review the upload token")
+				http.Error(w, "unable to review the token", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		var response upload.Response
+		var err error
+		status := http.StatusOK
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodGet:
+			serveUpload(w, r, store, builds, build, namespace, parts[1])
+			return
+		case len(parts) == 1 && r.Method == http.MethodPost:
+			response.ID, err = store.Create(namespace)
+			status = http.StatusCreated
+		case len(parts) == 2 && r.Method == http.MethodPut:
+			var offset int64
+			if offset, err = strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64); err != nil {
+				http.Error(w, "invalid chunk offset", http.StatusBadRequest)
+				return
+			}
+			response.Size, err = store.Append(namespace, parts[1], offset, http.MaxBytesReader(w, r.Body, maxUploadChunkSize))
+		case len(parts) == 2 && r.Method == http.MethodPost:
+			response.Digest = r.URL.Query().Get("digest")
+			err = store.Commit(namespace, parts[1], response.Digest)
+			status = http.StatusCreated
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		switch {
+		case errors.Is(err, upload.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case errors.Is(err, upload.ErrOffsetMismatch):
+			// the response tells the client where to resume from
+			status = http.StatusConflict
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Error(err, "unable to write the upload response")
+		}
+	}
+}
+
+// serveUpload sends the uploaded file to the builder Pod, provided the file is added to its Build.
+func serveUpload(w http.ResponseWriter, r *http.Request, store *upload.Store, builds ctrl.Reader, key ctrl.ObjectKey,
+	namespace string, digest string) {
+	build := v1.NewBuild(key.Namespace, key.Name)
+	if err := builds.Get(r.Context(), key, build); err != nil {
+		if k8serrors.IsNotFound(err) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Error(err, "unable to get the build", "build", key.String())
+		http.Error(w, "unable to get the build", http.StatusInternalServerError)
+		return
+	}
+	if !hasUploadDependency(build, upload.Ref(namespace, digest)) {
+		http.Error(w, upload.ErrNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	location, err := store.Path(namespace, digest)
+	if errors.Is(err, upload.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Error(err, "unable to read the upload store")
+		http.Error(w, "unable to read the upload store", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeFile(w, r, location)
+}
+
+// hasUploadDependency returns true if one of the builder tasks of the Build adds the uploaded file to the image.
+func hasUploadDependency(build *v1.Build, ref string) bool {
+	for _, task := range build.Spec.Tasks {
+		if task.Builder == nil {
+			continue
+		}
+		for _, d := range task.Builder.Dependencies {
+			if strings.HasPrefix(d, ref+"@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// uploadPruner periodically removes the files that are not used anymore by the Integrations, the IntegrationKits
+// or the Builds. It only runs on the leader, that is the replica the uploads are forwarded to.
+type uploadPruner struct {
+	store     *upload.Store
+	client    ctrl.Reader
+	retention time.Duration
+}
+
+var _ manager.Runnable = &uploadPruner{}
+var _ manager.LeaderElectionRunnable = &uploadPruner{}
+
+func newUploadPruner(store *upload.Store, c ctrl.Reader) (*uploadPruner, error) {
+	retention := defaultUploadRetention
+	if value, ok := os.LookupEnv(uploadRetentionEnvVariable); ok {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", uploadRetentionEnvVariable, err)
+		}
+		retention = d
+	}
+
+	return &uploadPruner{
+		store:     store,
+		client:    c,
+		retention: retention,
+	}, nil
+}
+
+// Start prunes the store periodically until the context is done.
+func (p *uploadPruner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(uploadPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := p.prune(ctx); err != nil {
+			log.Error(err, "unable to prune the upload store")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns true, so that only the leader prunes the store.
+func (p *uploadPruner) NeedLeaderElection() bool {
+	return true
+}
+
+func (p *uploadPruner) prune(ctx context.Context) error {
+	inUse, err := p.uploadsInUse(ctx)
+	if err != nil {
+		// nothing is removed if the files in use are not known
+		return err
+	}
+	removed, err := p.store.Prune(p.retention, func(namespace, digest string) bool {
+		return inUse[upload.Ref(namespace, digest)]
+	})
+	for _, ref := range removed {
+		log.Info("Removed unused uploaded file", "file", ref)
+	}
+	return err
+}
+
+// uploadsInUse returns the references to the uploaded files that are used by the Integrations, the IntegrationKits
+// or the Builds, so that the Integrations can be rebuilt.
+func (p *uploadPruner) uploadsInUse(ctx context.Context) (map[string]bool, error) {
+	inUse := make(map[string]bool)
+	addDependencies := func(dependencies []string) {
+		for _, d := range dependencies {
+			if namespace, digest, _, err := upload.ParseDependency(d); err == nil {
+				inUse[upload.Ref(namespace, digest)] = true
+			}
+		}
+	}
+
+	integrations := v1.NewIntegrationList()
+	if err := p.client.List(ctx, &integrations); err != nil {
+		return nil, err
+	}
+	for _, it := range integrations.Items {
+		addDependencies(it.Spec.Dependencies)
+		addDependencies(it.Status.Dependencies)
+		for _, s := range it.Sources() {
+			if upload.IsRef(s.ContentRef) {
+				inUse[s.ContentRef] = true
+			}
+		}
+	}
+
+	kits := v1.NewIntegrationKitList()
+	if err := p.client.List(ctx, &kits); err != nil {
+		return nil, err
+	}
+	for _, kit := range kits.Items {
+		addDependencies(kit.Spec.Dependencies)
+	}
+
+	builds := v1.NewBuildList()
+	if err := p.client.List(ctx, &builds); err != nil {
+		return nil, err
+	}
+	for _, build := range builds.Items {
+		for _, task := range build.Spec.Tasks {
+			if task.Builder != nil {
+				addDependencies(task.Builder.Dependencies)
+			}
+		}
+	}
+
+	return inUse, nil
+}
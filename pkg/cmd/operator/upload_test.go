@@ -0,0 +1,207 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/test"
+	"github.com/apache/camel-k/pkg/util/upload"
+)
+
+func TestUploadHandler(t *testing.T) {
+	digest, err := upload.Digest(strings.NewReader("hello world"))
+	assert.Nil(t, err)
+	build := v1.NewBuild("build-ns", "build")
+	build.Spec.Tasks = []v1.Task{{Builder: &v1.BuilderTask{
+		Dependencies: []string{upload.Dependency("ns", digest, "camel-k-resources/1a2b3c4d/data.txt")},
+	}}}
+	c, err := test.NewFakeClient(build)
+	assert.Nil(t, err)
+	var attributes authorizationv1.ResourceAttributes
+	authorize := func(_ context.Context, _ string, a authorizationv1.ResourceAttributes) error {
+		attributes = a
+		return nil
+	}
+	handler := uploadHandler(authorize, upload.NewStore(t.TempDir()), c)
+
+	send := func(method, path, body string) (int, upload.Response) {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		response := upload.Response{}
+		if recorder.Header().Get("Content-Type") == "application/json" {
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		}
+		return recorder.Code, response
+	}
+
+	code, response := send(http.MethodPost, upload.Path+"ns", "")
+	assert.Equal(t, http.StatusCreated, code)
+	id := response.ID
+	assert.NotEmpty(t, id)
+
+	code, response = send(http.MethodPut, fmt.Sprintf("%sns/%s?offset=0", upload.Path, id), "hello ")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(6), response.Size)
+
+	code, response = send(http.MethodPut, fmt.Sprintf("%sns/%s?offset=0", upload.Path, id), "hello ")
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, int64(6), response.Size)
+
+	code, _ = send(http.MethodPut, fmt.Sprintf("%sns/%s?offset=6", upload.Path, id), "world")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, response = send(http.MethodPost, fmt.Sprintf("%sns/%s?digest=%s", upload.Path, id, digest), "")
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, digest, response.Digest)
+	assert.Equal(t, "integrations", attributes.Resource)
+	assert.Equal(t, "create", attributes.Verb)
+
+	// the builder Pods fetch the files added to their Build
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%sns/%s?build=build-ns/build", upload.Path, digest), nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "hello world", recorder.Body.String())
+	assert.Equal(t, "builds", attributes.Resource)
+	assert.Equal(t, "get", attributes.Verb)
+	assert.Equal(t, "build-ns", attributes.Namespace)
+	assert.Equal(t, "build", attributes.Name)
+
+	code, _ = send(http.MethodGet, fmt.Sprintf("%sother/%s?build=build-ns/build", upload.Path, digest), "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = send(http.MethodGet, fmt.Sprintf("%sns/%s?build=build-ns/missing", upload.Path, digest), "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = send(http.MethodGet, fmt.Sprintf("%sns/%s", upload.Path, digest), "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = send(http.MethodPut, fmt.Sprintf("%sns/%s?offset=11", upload.Path, id), "!")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = send(http.MethodGet, upload.Path+"ns", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestUploadHandlerAuthorization(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authenticated bool
+		allowed       bool
+		code          int
+	}{
+		{name: "missing", code: http.StatusUnauthorized},
+		{name: "invalid", token: "invalid", code: http.StatusUnauthorized},
+		{name: "forbidden", token: "token", authenticated: true, code: http.StatusForbidden},
+		{name: "allowed", token: "token", authenticated: true, allowed: true, code: http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewSimpleClientset()
+			c.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review, _ := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				review.Status.Authenticated = tc.authenticated
+				review.Status.User.Username = "developer"
+				return true, review, nil
+			})
+			c.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review, _ := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				assert.Equal(t, "developer", review.Spec.User)
+				assert.Equal(t, "ns", review.Spec.ResourceAttributes.Namespace)
+				assert.Equal(t, "integrations", review.Spec.ResourceAttributes.Resource)
+				assert.Equal(t, "create", review.Spec.ResourceAttributes.Verb)
+				review.Status.Allowed = tc.allowed
+				return true, review, nil
+			})
+
+			request := httptest.NewRequest(http.MethodPost, upload.Path+"ns", nil)
+			if tc.token != "" {
+				request.Header.Set(upload.TokenHeader, tc.token)
+			}
+			recorder := httptest.NewRecorder()
+
+			uploadHandler(reviewToken(c), upload.NewStore(t.TempDir()), nil).ServeHTTP(recorder, request)
+
+			assert.Equal(t, tc.code, recorder.Code)
+		})
+	}
+}
+
+func TestUploadHandlerWithoutStore(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, upload.Path+"ns", nil)
+	recorder := httptest.NewRecorder()
+
+	uploadHandler(func(context.Context, string, authorizationv1.ResourceAttributes) error { return nil }, nil, nil).
+		ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestForwardToLeader(t *testing.T) {
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get(forwardedHeader))
+		_, _ = w.Write([]byte("leader"))
+	}))
+	defer leader.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(leader.URL, "http://"))
+	assert.Nil(t, err)
+	p, err := strconv.Atoi(port)
+	assert.Nil(t, err)
+	address := func(context.Context) (string, error) { return host, nil }
+	local := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("local"))
+	})
+	serve := func(handler http.Handler, forwarded bool) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, upload.Path+"ns", nil)
+		if forwarded {
+			request.Header.Set(forwardedHeader, "true")
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	elected := make(chan struct{})
+	handler := forwardToLeader(elected, address, int32(p), local)
+
+	recorder := serve(handler, false)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "leader", recorder.Body.String())
+	// the requests are not forwarded twice
+	assert.Equal(t, http.StatusServiceUnavailable, serve(handler, true).Code)
+
+	close(elected)
+	assert.Equal(t, "local", serve(handler, false).Body.String())
+}
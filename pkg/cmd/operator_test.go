@@ -61,6 +61,7 @@ func TestOperatorNoFlag(t *testing.T) {
 	// Check default expected values
 	assert.Equal(t, int32(8081), operatorCmdOptions.HealthPort)
 	assert.Equal(t, int32(8080), operatorCmdOptions.MonitoringPort)
+	assert.Equal(t, int32(8082), operatorCmdOptions.InspectPort)
 }
 
 func TestOperatorNonExistingFlag(t *testing.T) {
@@ -76,6 +77,13 @@ func TestOperatorHealthPortFlag(t *testing.T) {
 	assert.Equal(t, int32(7171), operatorCmdOptions.HealthPort)
 }
 
+func TestOperatorInspectPortFlag(t *testing.T) {
+	operatorCmdOptions, rootCmd, _ := initializeOperatorCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdOperator, "--inspect-port", "0")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), operatorCmdOptions.InspectPort)
+}
+
 func TestOperatorMonitoringPortFlag(t *testing.T) {
 	operatorCmdOptions, rootCmd, _ := initializeOperatorCmdOptions(t)
 	_, err := test.ExecuteCommand(rootCmd, cmdOperator, "--monitoring-port", "7172")
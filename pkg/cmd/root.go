@@ -139,18 +139,28 @@ func addKamelSubcommands(cmd *cobra.Command, options *RootCmdOptions) {
 	cmd.AddCommand(cmdOnly(newCmdInstall(options)))
 	cmd.AddCommand(cmdOnly(newCmdUninstall(options)))
 	cmd.AddCommand(cmdOnly(newCmdLog(options)))
+	cmd.AddCommand(cmdOnly(newCmdInspect(options)))
+	cmd.AddCommand(cmdOnly(newCmdSchema(options)))
+	cmd.AddCommand(cmdOnly(newCmdTraitTest(options)))
 	cmd.AddCommand(newCmdKit(options))
 	cmd.AddCommand(cmdOnly(newCmdReset(options)))
 	cmd.AddCommand(newCmdDescribe(options))
+	cmd.AddCommand(newCmdEdit(options))
 	cmd.AddCommand(cmdOnly(newCmdRebuild(options)))
 	cmd.AddCommand(cmdOnly(newCmdOperator()))
 	cmd.AddCommand(cmdOnly(newCmdBuilder(options)))
 	cmd.AddCommand(cmdOnly(newCmdInit(options)))
+	cmd.AddCommand(cmdOnly(newCmdInitNamespace(options)))
 	cmd.AddCommand(cmdOnly(newCmdDebug(options)))
 	cmd.AddCommand(cmdOnly(newCmdDump(options)))
 	cmd.AddCommand(newCmdLocal(options))
 	cmd.AddCommand(cmdOnly(newCmdBind(options)))
 	cmd.AddCommand(cmdOnly(newCmdPromote(options)))
+	cmd.AddCommand(cmdOnly(newCmdClone(options)))
+	cmd.AddCommand(cmdOnly(newCmdAdopt(options)))
+	cmd.AddCommand(cmdOnly(newCmdMigrateOperator(options)))
+	cmd.AddCommand(cmdOnly(newCmdVerifyBuild(options)))
+	cmd.AddCommand(cmdOnly(newCmdSelftest(options)))
 	cmd.AddCommand(newCmdKamelet(options))
 }
 
@@ -64,6 +64,7 @@ import (
 	platformutil "github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/trait"
 	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/dsl"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
@@ -98,7 +99,7 @@ func newCmdRun(rootCmdOptions *RootCmdOptions) (*cobra.Command, *runCmdOptions)
 	cmd.Flags().StringArrayP("connect", "c", nil, "A Service that the integration should bind to, specified as [[apigroup/]version:]kind:[namespace/]name")
 	cmd.Flags().StringArrayP("dependency", "d", nil, "A dependency that should be included, e.g., \"-d camel-mail\" for a Camel component, \"-d mvn:org.my:app:1.0\" for a Maven dependency or \"file://localPath[?targetPath=<path>&registry=<registry URL>&skipChecksums=<true>&skipPOM=<true>]\" for local files (experimental)")
 	cmd.Flags().BoolP("wait", "w", false, "Wait for the integration to be running")
-	cmd.Flags().StringP("kit", "k", "", "The kit used to run the integration")
+	cmd.Flags().StringP("kit", "k", "", "The kit used to run the integration, referenced by name or by image digest, optionally prefixed with its namespace, e.g., \"shared/my-kit\" or \"shared/sha256:...\"")
 	cmd.Flags().StringArrayP("property", "p", nil, "Add a runtime property or properties file (syntax: [my-key=my-value|file:/path/to/my-conf.properties])")
 	cmd.Flags().StringArray("build-property", nil, "Add a build time property or properties file (syntax: [my-key=my-value|file:/path/to/my-conf.properties])")
 	cmd.Flags().StringArray("config", nil, "Add a runtime configuration from a Configmap, a Secret or a file (syntax: [configmap|secret|file]:name[/key], where name represents the local file path or the configmap/secret name and key optionally represents the configmap/secret key to be filtered)")
@@ -107,8 +108,10 @@ func newCmdRun(rootCmdOptions *RootCmdOptions) (*cobra.Command, *runCmdOptions)
 	cmd.Flags().Bool("logs", false, "Print integration logs")
 	cmd.Flags().Bool("sync", false, "Synchronize the local source file with the cluster, republishing at each change")
 	cmd.Flags().Bool("dev", false, "Enable Dev mode (equivalent to \"-w --logs --sync\")")
+	cmd.Flags().Bool("dev-runner", true, "Run the integration in Dev mode with the prebuilt JBang runner image, hot-reloading the sources, instead of building an image")
 	cmd.Flags().Bool("use-flows", true, "Write yaml sources as Flow objects in the integration custom resource")
 	cmd.Flags().String("profile", "", "Trait profile used for deployment")
+	cmd.Flags().String("config-profile", "", "The configuration profile activated at runtime, e.g. \"staging\". The properties files named application-<profile>.properties only apply to that profile")
 	cmd.Flags().StringArrayP("trait", "t", nil, "Configure a trait. E.g. \"-t service.enabled=false\"")
 	cmd.Flags().StringP("output", "o", "", "Output format. One of: json|yaml")
 	cmd.Flags().Bool("compression", false, "Enable storage of sources and resources as a compressed binary blobs")
@@ -117,8 +120,14 @@ func newCmdRun(rootCmdOptions *RootCmdOptions) (*cobra.Command, *runCmdOptions)
 	cmd.Flags().StringArrayP("env", "e", nil, "Set an environment variable in the integration container. E.g \"-e MY_VAR=my-value\"")
 	cmd.Flags().StringArray("annotation", nil, "Add an annotation to the integration. E.g. \"--annotation my.company=hello\"")
 	cmd.Flags().StringArray("label", nil, "Add a label to the integration. E.g. \"--label my.company=hello\"")
-	cmd.Flags().StringArray("source", nil, "Add source file to your integration, this is added to the list of files listed as arguments of the command")
+	cmd.Flags().StringArray("source", nil, "Add source file to your integration, this is added to the list of files listed as arguments of the command. Use \"-\" to read it from the standard input")
+	cmd.Flags().StringArray("language", nil, "The language of the sources read from the standard input, set once for all of them, or once for each in order. E.g. \"--language groovy\". Defaults to yaml")
 	cmd.Flags().String("pod-template", "", "The path of the YAML file containing a PodSpec template to be used for the Integration pods")
+	cmd.Flags().String("preview", "", "Run the integration as a preview, e.g., of a pull request, suffixing its name and exposed hosts with the preview identifier. E.g. \"--preview pr-123\"")
+	cmd.Flags().String("preview-ttl", "72h", "How long the preview integration is kept before being deleted")
+	cmd.Flags().Bool("upload", false, "Upload the sources, and the local resource and configuration files, to the operator, that adds them to the integration image, instead of storing them in the integration and in ConfigMaps")
+	cmd.Flags().String("operator-namespace", "", "The namespace of the operator the files are uploaded to, defaults to the current namespace")
+	cmd.Flags().Int32("operator-port", 8082, "The port of the operator upload endpoint")
 
 	cmd.Flags().Bool("save", false, "Save the run parameters into the default kamel configuration file (kamel-config.yaml)")
 
@@ -129,34 +138,42 @@ func newCmdRun(rootCmdOptions *RootCmdOptions) (*cobra.Command, *runCmdOptions)
 }
 
 type runCmdOptions struct {
-	*RootCmdOptions `json:"-"`
-	Compression     bool     `mapstructure:"compression" yaml:",omitempty"`
-	Wait            bool     `mapstructure:"wait" yaml:",omitempty"`
-	Logs            bool     `mapstructure:"logs" yaml:",omitempty"`
-	Sync            bool     `mapstructure:"sync" yaml:",omitempty"`
-	Dev             bool     `mapstructure:"dev" yaml:",omitempty"`
-	UseFlows        bool     `mapstructure:"use-flows" yaml:",omitempty"`
-	Save            bool     `mapstructure:"save" yaml:",omitempty" kamel:"omitsave"`
-	IntegrationKit  string   `mapstructure:"kit" yaml:",omitempty"`
-	IntegrationName string   `mapstructure:"name" yaml:",omitempty"`
-	Profile         string   `mapstructure:"profile" yaml:",omitempty"`
-	OutputFormat    string   `mapstructure:"output" yaml:",omitempty"`
-	PodTemplate     string   `mapstructure:"pod-template" yaml:",omitempty"`
-	Connects        []string `mapstructure:"connects" yaml:",omitempty"`
-	Resources       []string `mapstructure:"resources" yaml:",omitempty"`
-	OpenAPIs        []string `mapstructure:"open-apis" yaml:",omitempty"`
-	Dependencies    []string `mapstructure:"dependencies" yaml:",omitempty"`
-	Properties      []string `mapstructure:"properties" yaml:",omitempty"`
-	BuildProperties []string `mapstructure:"build-properties" yaml:",omitempty"`
-	Configs         []string `mapstructure:"configs" yaml:",omitempty"`
-	Repositories    []string `mapstructure:"maven-repositories" yaml:",omitempty"`
-	Traits          []string `mapstructure:"traits" yaml:",omitempty"`
-	Volumes         []string `mapstructure:"volumes" yaml:",omitempty"`
-	EnvVars         []string `mapstructure:"envs" yaml:",omitempty"`
-	Labels          []string `mapstructure:"labels" yaml:",omitempty"`
-	Annotations     []string `mapstructure:"annotations" yaml:",omitempty"`
-	Sources         []string `mapstructure:"sources" yaml:",omitempty"`
-	RegistryOptions url.Values
+	*RootCmdOptions   `json:"-"`
+	Compression       bool     `mapstructure:"compression" yaml:",omitempty"`
+	Wait              bool     `mapstructure:"wait" yaml:",omitempty"`
+	Logs              bool     `mapstructure:"logs" yaml:",omitempty"`
+	Sync              bool     `mapstructure:"sync" yaml:",omitempty"`
+	Dev               bool     `mapstructure:"dev" yaml:",omitempty"`
+	DevRunner         bool     `mapstructure:"dev-runner" yaml:",omitempty"`
+	UseFlows          bool     `mapstructure:"use-flows" yaml:",omitempty"`
+	Save              bool     `mapstructure:"save" yaml:",omitempty" kamel:"omitsave"`
+	IntegrationKit    string   `mapstructure:"kit" yaml:",omitempty"`
+	IntegrationName   string   `mapstructure:"name" yaml:",omitempty"`
+	Profile           string   `mapstructure:"profile" yaml:",omitempty"`
+	ConfigProfile     string   `mapstructure:"config-profile" yaml:",omitempty"`
+	OutputFormat      string   `mapstructure:"output" yaml:",omitempty"`
+	PodTemplate       string   `mapstructure:"pod-template" yaml:",omitempty"`
+	Preview           string   `mapstructure:"preview" yaml:",omitempty"`
+	PreviewTTL        string   `mapstructure:"preview-ttl" yaml:",omitempty"`
+	Upload            bool     `mapstructure:"upload" yaml:",omitempty"`
+	OperatorNamespace string   `mapstructure:"operator-namespace" yaml:",omitempty"`
+	OperatorPort      int32    `mapstructure:"operator-port" yaml:",omitempty"`
+	Connects          []string `mapstructure:"connects" yaml:",omitempty"`
+	Resources         []string `mapstructure:"resources" yaml:",omitempty"`
+	OpenAPIs          []string `mapstructure:"open-apis" yaml:",omitempty"`
+	Dependencies      []string `mapstructure:"dependencies" yaml:",omitempty"`
+	Properties        []string `mapstructure:"properties" yaml:",omitempty"`
+	BuildProperties   []string `mapstructure:"build-properties" yaml:",omitempty"`
+	Configs           []string `mapstructure:"configs" yaml:",omitempty"`
+	Repositories      []string `mapstructure:"maven-repositories" yaml:",omitempty"`
+	Traits            []string `mapstructure:"traits" yaml:",omitempty"`
+	Volumes           []string `mapstructure:"volumes" yaml:",omitempty"`
+	EnvVars           []string `mapstructure:"envs" yaml:",omitempty"`
+	Labels            []string `mapstructure:"labels" yaml:",omitempty"`
+	Annotations       []string `mapstructure:"annotations" yaml:",omitempty"`
+	Sources           []string `mapstructure:"sources" yaml:",omitempty"`
+	Languages         []string `mapstructure:"languages" yaml:",omitempty"`
+	RegistryOptions   url.Values
 }
 
 func (o *runCmdOptions) preRunE(cmd *cobra.Command, args []string) error {
@@ -231,16 +248,51 @@ func (o *runCmdOptions) decode(cmd *cobra.Command, args []string) error {
 
 func (o *runCmdOptions) validateArgs(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 {
+		// An Integration can be created from OpenAPI specs only, e.g., to be deployed as a mock service
+		if openAPIs, err := cmd.Flags().GetStringArray("open-api"); err == nil && len(openAPIs) > 0 {
+			return nil
+		}
+		// The sources can be set with the source flag only, e.g., to read them from the standard input
+		if sources, err := cmd.Flags().GetStringArray("source"); err == nil && len(sources) > 0 {
+			return nil
+		}
 		return errors.New("run expects at least 1 argument, received 0")
 	}
 
-	if _, err := ResolveSources(context.Background(), args, false, cmd); err != nil {
+	sources, _ := splitRoutesArtifacts(args)
+	// The standard input is only read once, when the integration is created
+	sources, _ = splitStdinSources(sources)
+	if _, err := ResolveSources(context.Background(), sources, false, cmd); err != nil {
 		return errors.Wrap(err, "One of the provided sources is not reachable")
 	}
 
 	return nil
 }
 
+func (o *runCmdOptions) hasPrebuiltRoutes() bool {
+	for _, t := range o.Traits {
+		if strings.HasPrefix(t, "prebuilt-routes.classes=") || strings.HasPrefix(t, "prebuilt-routes.packages=") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRoutesArtifacts separates the sources from the artifacts containing prebuilt routes,
+// i.e., local jar files, and Maven artifacts.
+func splitRoutesArtifacts(locations []string) ([]string, []string) {
+	sources := make([]string, 0, len(locations))
+	artifacts := make([]string, 0)
+	for _, location := range locations {
+		if isJar(location) || strings.HasPrefix(location, "mvn:") {
+			artifacts = append(artifacts, location)
+		} else {
+			sources = append(sources, location)
+		}
+	}
+	return sources, artifacts
+}
+
 func (o *runCmdOptions) validate() error {
 	for _, volume := range o.Volumes {
 		volumeConfig := strings.Split(volume, ":")
@@ -260,6 +312,18 @@ func (o *runCmdOptions) validate() error {
 		return fmt.Errorf("cannot use --dev with -o/--output option")
 	}
 
+	if o.OutputFormat != "" && o.Upload {
+		return fmt.Errorf("cannot use --upload with -o/--output option")
+	}
+
+	if err := o.validatePreview(); err != nil {
+		return err
+	}
+
+	if err := o.validateLanguages(); err != nil {
+		return err
+	}
+
 	for _, label := range o.Labels {
 		parts := strings.Split(label, "=")
 		if len(parts) != 2 {
@@ -307,6 +371,13 @@ func (o *runCmdOptions) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	locations := make([]string, 0, len(args)+len(o.Sources))
+	locations = append(locations, args...)
+	locations = append(locations, o.Sources...)
+	if _, stdin := splitStdinSources(locations); stdin > 0 && (o.Sync || o.Dev) {
+		return errors.New("cannot use --sync or --dev with sources read from the standard input")
+	}
+
 	catalog := trait.NewCatalog(c)
 	integration, err := o.createOrUpdateIntegration(cmd, c, args, catalog)
 	if err != nil {
@@ -354,7 +425,7 @@ func (o *runCmdOptions) run(cmd *cobra.Command, args []string) error {
 
 			if integrationPhase == nil || *integrationPhase == v1.IntegrationPhaseError {
 				return fmt.Errorf("integration \"%s\" deployment failed", integration.Name)
-			} else if *integrationPhase == v1.IntegrationPhaseRunning {
+			} else if *integrationPhase == v1.IntegrationPhaseRunning || *integrationPhase == v1.IntegrationPhaseCompleted {
 				break
 			}
 
@@ -368,6 +439,11 @@ func (o *runCmdOptions) run(cmd *cobra.Command, args []string) error {
 			integration.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
 		}
 	}
+	if o.Preview != "" && o.OutputFormat == "" {
+		if err := o.printPreview(cmd, c, integration); err != nil {
+			return err
+		}
+	}
 	if o.Logs || o.Dev {
 		err = k8slog.Print(o.Context, cmd, c, integration, cmd.OutOrStdout())
 		if err != nil {
@@ -413,7 +489,8 @@ func (o *runCmdOptions) waitForIntegrationReady(cmd *cobra.Command, c client.Cli
 			// TODO remove this log when we make sure that events are always created
 			fmt.Fprintf(cmd.OutOrStdout(), "Progress: integration %q in phase %s\n", integration.Name, string(i.Status.Phase))
 		}
-		if i.Status.Phase == v1.IntegrationPhaseRunning || i.Status.Phase == v1.IntegrationPhaseError {
+		if i.Status.Phase == v1.IntegrationPhaseRunning || i.Status.Phase == v1.IntegrationPhaseCompleted ||
+			i.Status.Phase == v1.IntegrationPhaseError {
 			return false
 		}
 
@@ -485,10 +562,32 @@ func (o *runCmdOptions) syncIntegration(cmd *cobra.Command, c client.Client, sou
 }
 
 // nolint: gocyclo
+// useDevRunner returns true when the integration can be run in Dev mode with the JBang runner image,
+// that is when neither a kit nor a container image is provided, and the jbang trait is not configured explicitly.
+func (o *runCmdOptions) useDevRunner() bool {
+	if !o.Dev || !o.DevRunner || o.IntegrationKit != "" {
+		return false
+	}
+	for _, t := range o.Traits {
+		if strings.HasPrefix(t, "jbang.") || strings.HasPrefix(t, "container.image=") {
+			return false
+		}
+	}
+	return true
+}
+
 func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.Client, sources []string, catalog trait.Finder) (*v1.Integration, error) {
 	namespace := o.Namespace
 	name := o.GetIntegrationName(sources)
 
+	srcs := make([]string, 0, len(sources)+len(o.Sources))
+	srcs = append(srcs, sources...)
+	srcs = append(srcs, o.Sources...)
+	srcs, stdin := splitStdinSources(srcs)
+
+	if name == "" && stdin > 0 {
+		return nil, errors.New("the integration name must be set with --name when the sources are read from the standard input")
+	}
 	if name == "" {
 		return nil, errors.New("unable to determine integration name")
 	}
@@ -517,20 +616,15 @@ func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.C
 		}
 	}
 
-	var integrationKit *corev1.ObjectReference
-	if o.IntegrationKit != "" {
-		integrationKit = &corev1.ObjectReference{
-			Namespace: namespace,
-			Name:      o.IntegrationKit,
-		}
-	}
+	integrationKit, integrationKitDigest := parseIntegrationKitReference(namespace, o.IntegrationKit)
 
 	integration.Spec = v1.IntegrationSpec{
-		Dependencies:   make([]string, 0, len(o.Dependencies)),
-		IntegrationKit: integrationKit,
-		Configuration:  make([]v1.ConfigurationSpec, 0),
-		Repositories:   o.Repositories,
-		Profile:        v1.TraitProfileByName(o.Profile),
+		Dependencies:         make([]string, 0, len(o.Dependencies)),
+		IntegrationKit:       integrationKit,
+		IntegrationKitDigest: integrationKitDigest,
+		Configuration:        make([]v1.ConfigurationSpec, 0),
+		Repositories:         o.Repositories,
+		Profile:              v1.TraitProfileByName(o.Profile),
 	}
 
 	for _, label := range o.Labels {
@@ -553,14 +647,28 @@ func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.C
 		}
 	}
 
-	srcs := make([]string, 0, len(sources)+len(o.Sources))
-	srcs = append(srcs, sources...)
-	srcs = append(srcs, o.Sources...)
+	if o.Preview != "" {
+		if err := o.applyPreview(integration); err != nil {
+			return nil, err
+		}
+	}
+
+	srcs, artifacts := splitRoutesArtifacts(srcs)
+	if len(artifacts) > 0 && !o.hasPrebuiltRoutes() {
+		return nil, errors.New("the routes to load from the jar artifacts must be set with the prebuilt-routes.classes or prebuilt-routes.packages trait")
+	}
 
 	resolvedSources, err := ResolveSources(context.Background(), srcs, o.Compression, cmd)
 	if err != nil {
 		return nil, err
 	}
+	if stdin > 0 {
+		stdinSources, err := o.resolveStdinSources(cmd, name, stdin)
+		if err != nil {
+			return nil, err
+		}
+		resolvedSources = append(resolvedSources, stdinSources...)
+	}
 
 	for _, source := range resolvedSources {
 		if o.UseFlows && !o.Compression && (strings.HasSuffix(source.Name, ".yaml") || strings.HasSuffix(source.Name, ".yml")) {
@@ -585,11 +693,64 @@ func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.C
 		return nil, err
 	}
 
-	err = o.parseAndConvertToTrait(cmd, c, integration, o.Resources, resource.ParseResource, func(c *resource.Config) string { return c.String() }, "mount.resources")
+	resourceItems, configItems := o.Resources, o.Configs
+	imageDependencies := make([]string, 0)
+	if o.Upload {
+		// The sources and the local files are uploaded to the operator and added to the Integration image,
+		// instead of being stored in the Integration and in ConfigMaps
+		u, err := newUploader(o.Context, c, o.OperatorNamespace, o.OperatorPort, namespace)
+		if err != nil {
+			return nil, err
+		}
+		dependencies, err := u.uploadSources(o.Context, integration.Spec.Sources)
+		if err != nil {
+			return nil, err
+		}
+		imageDependencies = append(imageDependencies, dependencies...)
+		for _, files := range []struct {
+			items      *[]string
+			parse      func(string) (*resource.Config, error)
+			defaultDir string
+		}{
+			{items: &resourceItems, parse: resource.ParseResource, defaultDir: camel.ResourcesDefaultMountPath},
+			{items: &configItems, parse: resource.ParseConfig, defaultDir: camel.ConfigResourcesMountPath},
+		} {
+			remaining, dependencies, imageResources, err := u.uploadFiles(o.Context, *files.items, files.parse, files.defaultDir)
+			if err != nil {
+				return nil, err
+			}
+			*files.items = remaining
+			imageDependencies = append(imageDependencies, dependencies...)
+			for _, imageResource := range imageResources {
+				o.Traits = append(o.Traits, convertToTrait(imageResource, "mount.resources"))
+			}
+		}
+	}
+
+	// The resources that are too large to be stored in a ConfigMap are added to the Integration image
+	resources := make([]string, 0, len(resourceItems))
+	for _, r := range resourceItems {
+		config, err := resource.ParseResource(r)
+		if err != nil {
+			return nil, err
+		}
+		if !isLargeFileResource(config) {
+			resources = append(resources, r)
+			continue
+		}
+		dependency, imageResource, err := convertFileToImageResource(config)
+		if err != nil {
+			return nil, err
+		}
+		o.PrintfVerboseOutf(cmd, "Resource %s is too large to be stored in a ConfigMap, adding it to the Integration image \n", config.Name())
+		imageDependencies = append(imageDependencies, dependency)
+		o.Traits = append(o.Traits, convertToTrait(imageResource, "mount.resources"))
+	}
+	err = o.parseAndConvertToTrait(cmd, c, integration, resources, resource.ParseResource, func(c *resource.Config) string { return c.String() }, "mount.resources")
 	if err != nil {
 		return nil, err
 	}
-	err = o.parseAndConvertToTrait(cmd, c, integration, o.Configs, resource.ParseConfig, func(c *resource.Config) string { return c.String() }, "mount.configs")
+	err = o.parseAndConvertToTrait(cmd, c, integration, configItems, resource.ParseConfig, func(c *resource.Config) string { return c.String() }, "mount.configs")
 	if err != nil {
 		return nil, err
 	}
@@ -598,8 +759,19 @@ func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.C
 		return nil, err
 	}
 
+	// The artifacts containing prebuilt routes are added to the Integration classpath
+	dependencies := make([]string, 0, len(o.Dependencies)+len(artifacts)+len(imageDependencies))
+	dependencies = append(dependencies, o.Dependencies...)
+	dependencies = append(dependencies, imageDependencies...)
+	for _, artifact := range artifacts {
+		if isJar(artifact) && !strings.HasPrefix(artifact, "mvn:") {
+			artifact = "file://" + artifact
+		}
+		dependencies = append(dependencies, artifact)
+	}
+
 	var platform *v1.IntegrationPlatform
-	for _, item := range o.Dependencies {
+	for _, item := range dependencies {
 		// TODO: accept URLs
 		if strings.HasPrefix(item, "file://") {
 			if platform == nil {
@@ -659,6 +831,9 @@ func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.C
 		o.Traits = append(o.Traits, buildPropsTraits...)
 	}
 
+	if o.ConfigProfile != "" {
+		o.Traits = append(o.Traits, fmt.Sprintf("camel.profile=%s", o.ConfigProfile))
+	}
 	for _, item := range o.Volumes {
 		o.Traits = append(o.Traits, fmt.Sprintf("mount.volumes=%s", item))
 	}
@@ -668,6 +843,9 @@ func (o *runCmdOptions) createOrUpdateIntegration(cmd *cobra.Command, c client.C
 	for _, item := range o.Connects {
 		o.Traits = append(o.Traits, fmt.Sprintf("service-binding.services=%s", item))
 	}
+	if o.useDevRunner() {
+		o.Traits = append(o.Traits, "jbang.enabled=true")
+	}
 	if len(o.Traits) > 0 {
 		traits, err := configureTraits(o.Traits, catalog)
 		if err != nil {
@@ -754,9 +932,12 @@ func (o *runCmdOptions) GetIntegrationName(sources []string) string {
 	if o.IntegrationName != "" {
 		name = o.IntegrationName
 		name = kubernetes.SanitizeName(name)
-	} else if len(sources) == 1 {
+	} else if len(sources) == 1 && sources[0] != stdinLocation {
 		name = kubernetes.SanitizeName(sources[0])
 	}
+	if name != "" && o.Preview != "" && !strings.HasSuffix(name, "-"+o.Preview) {
+		name += "-" + o.Preview
+	}
 	return name
 }
 
@@ -21,11 +21,14 @@ import (
 	"context"
 	"crypto/sha1" //nolint
 	"fmt"
+	"os"
 	"path"
+	"regexp"
 	"strings"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/resource"
 	"github.com/magiconair/properties"
@@ -85,6 +88,39 @@ func parseConfigAndGenCm(ctx context.Context, cmd *cobra.Command, c client.Clien
 	return nil, nil
 }
 
+// maxConfigMapDataSize is the size above which a resource file is not stored in a ConfigMap,
+// as ConfigMap objects are limited to 1MiB, metadata included.
+const maxConfigMapDataSize = 1000 * 1000
+
+// isLargeFileResource returns true if the local file resource is too large to be stored in a ConfigMap.
+func isLargeFileResource(config *resource.Config) bool {
+	if config.StorageType() != resource.StorageTypeFile || config.ContentType() != resource.ContentTypeData {
+		return false
+	}
+	info, err := os.Stat(config.Name())
+	if err != nil {
+		// The error is reported when the content is loaded
+		return false
+	}
+
+	return !info.IsDir() && info.Size() > maxConfigMapDataSize
+}
+
+// convertFileToImageResource returns the dependency uploading the local file resource to the image registry, so that
+// it's added to the Integration image, and the image resource that copies it to the same destination path
+// the ConfigMap would be mounted at.
+func convertFileToImageResource(config *resource.Config) (string, string, error) {
+	if isJar(config.Name()) || isPom(config.Name()) {
+		return "", "", fmt.Errorf("resource %s is too large to be stored in a ConfigMap", config.Name())
+	}
+	targetPath, imageResource, err := imageResourceFor(config, camel.ResourcesDefaultMountPath)
+	if err != nil {
+		return "", "", fmt.Errorf("resource %s is too large to be stored in a ConfigMap, and its name cannot be stored in the Integration image", config.Name())
+	}
+
+	return fmt.Sprintf("file://%s?targetPath=%s", config.Name(), path.Dir(targetPath)), imageResource, nil
+}
+
 func binaryOrTextResource(fileName string, data []byte, contentType string, base64Compression bool, resourceType v1.ResourceType, destinationPath string) (v1.ResourceSpec, error) {
 	resourceSpec := v1.ResourceSpec{
 		DataSpec: v1.DataSpec{
@@ -136,6 +172,11 @@ func mergePropertiesWithPrecedence(items []string) (*properties.Properties, erro
 		}
 		// We consider file props to have a lower priority versus single properties
 		if strings.HasPrefix(item, "file:") {
+			if profile := propertyFileProfile(strings.TrimPrefix(item, "file:")); profile != "" {
+				if prop, err = withProfilePrefix(prop, profile); err != nil {
+					return nil, err
+				}
+			}
 			loPrecedenceProps.Merge(prop)
 		} else {
 			hiPrecedenceProps.Merge(prop)
@@ -147,6 +188,33 @@ func mergePropertiesWithPrecedence(items []string) (*properties.Properties, erro
 	return loPrecedenceProps, nil
 }
 
+// profilePropertyFileRegexp matches the properties files holding the configuration of a profile, e.g. application-staging.properties.
+var profilePropertyFileRegexp = regexp.MustCompile(`^application-([a-zA-Z0-9][a-zA-Z0-9_-]*)\.properties$`)
+
+// propertyFileProfile returns the profile the properties file applies to, following the Quarkus naming convention,
+// or an empty string if the file applies to all the profiles.
+func propertyFileProfile(file string) string {
+	if match := profilePropertyFileRegexp.FindStringSubmatch(path.Base(file)); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// withProfilePrefix prefixes the properties with the profile, e.g. %staging.my.key, unless they are already bound to a profile.
+func withProfilePrefix(props *properties.Properties, profile string) (*properties.Properties, error) {
+	res := properties.NewProperties()
+	for _, key := range props.Keys() {
+		value, _ := props.Get(key)
+		if !strings.HasPrefix(key, "%") {
+			key = "%" + profile + "." + key
+		}
+		if _, _, err := res.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 // The function parse the value and if it is a file (file:/path/), it will parse as property file
 // otherwise return a single property built from the item passed as `key=value`.
 func extractProperties(value string) (*properties.Properties, error) {
@@ -160,3 +228,18 @@ func extractProperties(value string) (*properties.Properties, error) {
 func keyValueProps(value string) (*properties.Properties, error) {
 	return properties.Load([]byte(value), properties.UTF8)
 }
+
+// parseIntegrationKitReference parses the reference of the kit the Integration is pinned to, i.e., its name or its
+// image digest, optionally prefixed with the kit namespace, e.g., shared/sha256:.... The kit defaults to the given namespace.
+func parseIntegrationKitReference(namespace string, kit string) (*corev1.ObjectReference, string) {
+	if kit == "" {
+		return nil, ""
+	}
+	if i := strings.Index(kit, "/"); i >= 0 {
+		namespace, kit = kit[:i], kit[i+1:]
+	}
+	if strings.HasPrefix(kit, "sha256:") {
+		return &corev1.ObjectReference{Namespace: namespace}, kit
+	}
+	return &corev1.ObjectReference{Namespace: namespace, Name: kit}, ""
+}
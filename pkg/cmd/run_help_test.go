@@ -20,9 +20,13 @@ package cmd
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/util/resource"
 )
 
 func TestFilterFileLocation(t *testing.T) {
@@ -102,3 +106,39 @@ func TestExtractPropertiesFromFileAndSingleValue(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "override", val)
 }
+
+func TestConvertLargeFileResourceToImageResource(t *testing.T) {
+	var tmpFile *os.File
+	var err error
+	if tmpFile, err = ioutil.TempFile("", "camel-k-*.bin"); err != nil {
+		t.Error(err)
+	}
+
+	assert.Nil(t, tmpFile.Close())
+	assert.Nil(t, ioutil.WriteFile(tmpFile.Name(), []byte("small"), 0o600))
+
+	config, err := resource.ParseResource("file:" + tmpFile.Name())
+	assert.Nil(t, err)
+	assert.False(t, isLargeFileResource(config))
+
+	assert.Nil(t, os.Truncate(tmpFile.Name(), maxConfigMapDataSize+1))
+	assert.True(t, isLargeFileResource(config))
+
+	dependency, imageResource, err := convertFileToImageResource(config)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(dependency, "file://"+tmpFile.Name()+"?targetPath=camel-k-resources/"))
+
+	image, err := resource.ParseResource(imageResource)
+	assert.Nil(t, err)
+	assert.Equal(t, resource.StorageTypeImage, image.StorageType())
+	assert.True(t, strings.HasSuffix(image.Name(), "/"+filepath.Base(tmpFile.Name())))
+	assert.Equal(t, "/etc/camel/resources/"+filepath.Base(tmpFile.Name()), image.DestinationPath())
+
+	config, err = resource.ParseResource("file:" + tmpFile.Name() + "@/var/data/large.bin")
+	assert.Nil(t, err)
+	_, imageResource, err = convertFileToImageResource(config)
+	assert.Nil(t, err)
+	image, err = resource.ParseResource(imageResource)
+	assert.Nil(t, err)
+	assert.Equal(t, "/var/data/large.bin", image.DestinationPath())
+}
@@ -0,0 +1,159 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	networking "k8s.io/api/networking/v1"
+
+	serving "knative.dev/serving/pkg/apis/serving/v1"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+)
+
+var previewRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+func (o *runCmdOptions) validatePreview() error {
+	if o.Preview == "" {
+		return nil
+	}
+	if len(o.Preview) > 32 || !previewRegexp.MatchString(o.Preview) {
+		return fmt.Errorf(`invalid preview %q, it must consist of at most 32 lower case alphanumeric characters or '-', e.g., "pr-123"`, o.Preview)
+	}
+	ttl, err := time.ParseDuration(o.PreviewTTL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid preview TTL %s", o.PreviewTTL)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("invalid preview TTL %s, it must be positive", o.PreviewTTL)
+	}
+	return nil
+}
+
+// applyPreview labels the Integration with the preview and sets its expiration. The route and ingress hosts
+// are suffixed with the preview, so that the previews of the same Integration can be exposed side by side.
+func (o *runCmdOptions) applyPreview(integration *v1.Integration) error {
+	ttl, err := time.ParseDuration(o.PreviewTTL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid preview TTL %s", o.PreviewTTL)
+	}
+
+	if integration.Labels == nil {
+		integration.Labels = make(map[string]string)
+	}
+	integration.Labels[v1.IntegrationPreviewLabel] = o.Preview
+	if integration.Annotations == nil {
+		integration.Annotations = make(map[string]string)
+	}
+	integration.Annotations[v1.IntegrationPreviewExpirationAnnotation] = time.Now().Add(ttl).UTC().Format(time.RFC3339)
+
+	for i, t := range o.Traits {
+		for _, prefix := range []string{"route.host=", "ingress.host="} {
+			if strings.HasPrefix(t, prefix) {
+				o.Traits[i] = prefix + previewHost(strings.TrimPrefix(t, prefix), o.Preview)
+			}
+		}
+	}
+
+	return nil
+}
+
+// previewHost suffixes the first label of the host with the preview, e.g., "hello.example.com"
+// becomes "hello-pr-123.example.com".
+func previewHost(host string, preview string) string {
+	suffix := "-" + preview
+	parts := strings.SplitN(host, ".", 2)
+	if strings.HasSuffix(parts[0], suffix) {
+		return host
+	}
+	parts[0] += suffix
+	return strings.Join(parts, ".")
+}
+
+// printPreview prints the status of the preview as Markdown, so that it can be posted as is,
+// e.g., as a comment of the pull request under review.
+func (o *runCmdOptions) printPreview(cmd *cobra.Command, c client.Client, integration *v1.Integration) error {
+	it := v1.NewIntegration(integration.Namespace, integration.Name)
+	if err := c.Get(o.Context, ctrl.ObjectKeyFromObject(&it), &it); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "\n**Preview `%s`**\n\n", o.Preview)
+	fmt.Fprintf(out, "- Integration: `%s` in namespace `%s`\n", it.Name, it.Namespace)
+	if it.Status.Phase != "" {
+		fmt.Fprintf(out, "- Phase: %s\n", it.Status.Phase)
+	}
+	if url := getIntegrationURL(o.Context, c, &it); url != "" {
+		fmt.Fprintf(out, "- URL: %s\n", url)
+	}
+	if expiration, ok := it.Annotations[v1.IntegrationPreviewExpirationAnnotation]; ok {
+		fmt.Fprintf(out, "- Expires: %s\n", expiration)
+	}
+
+	return nil
+}
+
+// getIntegrationURL returns the URL the Integration is exposed at, through a route, an ingress or a Knative service,
+// or an empty string if it is not exposed, or not yet.
+func getIntegrationURL(ctx context.Context, c client.Client, integration *v1.Integration) string {
+	key := ctrl.ObjectKey{
+		Namespace: integration.Namespace,
+		Name:      integration.Name,
+	}
+
+	route := routev1.Route{}
+	if err := c.Get(ctx, key, &route); err == nil && route.Spec.Host != "" {
+		if route.Spec.TLS != nil {
+			return "https://" + route.Spec.Host
+		}
+		return "http://" + route.Spec.Host
+	}
+
+	ingress := networking.Ingress{}
+	if err := c.Get(ctx, key, &ingress); err == nil {
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host == "" {
+				continue
+			}
+			if len(ingress.Spec.TLS) > 0 {
+				return "https://" + rule.Host
+			}
+			return "http://" + rule.Host
+		}
+	}
+
+	service := serving.Service{}
+	if err := c.Get(ctx, key, &service); err == nil && service.Status.URL != nil {
+		return service.Status.URL.String()
+	}
+
+	return ""
+}
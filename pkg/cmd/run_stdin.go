@@ -0,0 +1,151 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+// stdinLocation is the location of the sources read from the standard input.
+const stdinLocation = "-"
+
+var javaClassRegexp = regexp.MustCompile(`(?m)^\s*(?:public\s+|final\s+|abstract\s+)*class\s+([A-Za-z_$][A-Za-z0-9_$]*)`)
+
+func (o *runCmdOptions) validateLanguages() error {
+	for _, l := range o.Languages {
+		if !isLanguage(v1.Language(l)) {
+			names := make([]string, 0, len(v1.Languages))
+			for _, language := range v1.Languages {
+				names = append(names, string(language))
+			}
+			return fmt.Errorf("unsupported language %q, it must be one of %s", l, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+func isLanguage(language v1.Language) bool {
+	for _, l := range v1.Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// splitStdinSources separates the locations of the sources read from the standard input from the others,
+// and returns the number of the former.
+func splitStdinSources(locations []string) ([]string, int) {
+	others := make([]string, 0, len(locations))
+	count := 0
+	for _, location := range locations {
+		if location == stdinLocation {
+			count++
+		} else {
+			others = append(others, location)
+		}
+	}
+	return others, count
+}
+
+// resolveStdinSources reads the given number of sources from the standard input, separated by lines consisting
+// of ---, when there are several of them. The sources are named after the integration, or after the class they
+// declare for Java, with the extension of the language they are set with, YAML by default.
+func (o *runCmdOptions) resolveStdinSources(cmd *cobra.Command, name string, count int) ([]Source, error) {
+	if len(o.Languages) > 1 && len(o.Languages) != count {
+		return nil, fmt.Errorf("%d languages are set for %d sources read from the standard input, set either one language for all of them, or one for each", len(o.Languages), count)
+	}
+
+	content, err := ioutil.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return nil, fmt.Errorf("cannot read sources from the standard input: %w", err)
+	}
+	parts := splitStdin(string(content), count)
+	if len(parts) != count {
+		return nil, fmt.Errorf("expected %d sources from the standard input, separated by --- lines, found %d", count, len(parts))
+	}
+
+	sources := make([]Source, 0, count)
+	for i, part := range parts {
+		language := v1.LanguageYaml
+		switch {
+		case len(o.Languages) == 1:
+			language = v1.Language(o.Languages[0])
+		case len(o.Languages) > 1:
+			language = v1.Language(o.Languages[i])
+		}
+
+		sourceName := name
+		if count > 1 {
+			sourceName = fmt.Sprintf("%s-%d", name, i+1)
+		}
+		if language == v1.LanguageJavaSource {
+			// The Java sources must be named after their class
+			if match := javaClassRegexp.FindStringSubmatch(part); match != nil {
+				sourceName = match[1]
+			}
+		}
+
+		source := Source{
+			Name:     sourceName + "." + string(language),
+			Origin:   stdinLocation,
+			Location: stdinLocation,
+			Compress: o.Compression,
+		}
+		if err := source.setContent([]byte(part)); err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// splitStdin splits the content into the given number of parts, on the lines consisting of ---. The blank parts,
+// e.g., before a leading YAML document separator, are ignored.
+func splitStdin(content string, count int) []string {
+	if count == 1 {
+		return []string{content}
+	}
+
+	parts := make([]string, 0, count)
+	var b strings.Builder
+	add := func() {
+		if strings.TrimSpace(b.String()) != "" {
+			parts = append(parts, b.String())
+		}
+		b.Reset()
+	}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimSpace(line) == "---" {
+			add()
+			continue
+		}
+		b.WriteString(line)
+	}
+	add()
+
+	return parts
+}
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
@@ -30,6 +31,8 @@ import (
 	"github.com/apache/camel-k/pkg/util/test"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -129,6 +132,32 @@ func TestRunDevFlag(t *testing.T) {
 	assert.Equal(t, true, runCmdOptions.Dev)
 }
 
+func TestRunDevRunner(t *testing.T) {
+	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdRun, "--dev", integrationSource)
+	assert.Nil(t, err)
+	assert.True(t, runCmdOptions.DevRunner)
+	assert.True(t, runCmdOptions.useDevRunner())
+
+	runCmdOptions.Traits = []string{"container.image=quay.io/acme/routes:1.0"}
+	assert.False(t, runCmdOptions.useDevRunner())
+
+	runCmdOptions.Traits = []string{"jbang.hot-reload=false"}
+	assert.False(t, runCmdOptions.useDevRunner())
+
+	runCmdOptions.Traits = nil
+	runCmdOptions.IntegrationKit = "my-kit"
+	assert.False(t, runCmdOptions.useDevRunner())
+}
+
+func TestRunDevRunnerDisabled(t *testing.T) {
+	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdRun, "--dev", "--dev-runner=false", integrationSource)
+	assert.Nil(t, err)
+	assert.False(t, runCmdOptions.DevRunner)
+	assert.False(t, runCmdOptions.useDevRunner())
+}
+
 func TestRunDevModeOutputFlag(t *testing.T) {
 	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
 	_, err := test.ExecuteCommand(rootCmd, cmdRun, "--dev", "-o", "yaml", integrationSource)
@@ -139,6 +168,14 @@ func TestRunDevModeOutputFlag(t *testing.T) {
 		err.Error())
 }
 
+func TestRunUploadOutputFlag(t *testing.T) {
+	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdRun, "--upload", "-o", "yaml", integrationSource)
+	assert.True(t, runCmdOptions.Upload)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot use --upload with -o/--output option", err.Error())
+}
+
 func TestRunEnvFlag(t *testing.T) {
 	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
 	_, err := test.ExecuteCommand(rootCmd, cmdRun,
@@ -291,6 +328,50 @@ func TestRunProperty(t *testing.T) {
 	assert.Equal(t, `trait.properties=key = value\nnewline`, properties[0])
 }
 
+func TestRunConfigProfileFlag(t *testing.T) {
+	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
+	_, err := test.ExecuteCommand(rootCmd, cmdRun, "--config-profile", "staging", integrationSource)
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", runCmdOptions.ConfigProfile)
+}
+
+func TestParseIntegrationKitReference(t *testing.T) {
+	ref, digest := parseIntegrationKitReference("ns", "")
+	assert.Nil(t, ref)
+	assert.Equal(t, "", digest)
+
+	ref, digest = parseIntegrationKitReference("ns", "my-kit")
+	assert.Equal(t, &corev1.ObjectReference{Namespace: "ns", Name: "my-kit"}, ref)
+	assert.Equal(t, "", digest)
+
+	ref, digest = parseIntegrationKitReference("ns", "shared/my-kit")
+	assert.Equal(t, &corev1.ObjectReference{Namespace: "shared", Name: "my-kit"}, ref)
+	assert.Equal(t, "", digest)
+
+	ref, digest = parseIntegrationKitReference("ns", "shared/sha256:0123456789abcdef")
+	assert.Equal(t, &corev1.ObjectReference{Namespace: "shared"}, ref)
+	assert.Equal(t, "sha256:0123456789abcdef", digest)
+}
+
+func TestMergeProfilePropertyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camel-k-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "application-staging.properties")
+	assert.Nil(t, ioutil.WriteFile(file, []byte("a=staging\n%prod.b=prod\n"), 0o400))
+
+	props, err := mergePropertiesWithPrecedence([]string{"file:" + file, "a=default"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"%staging.a", "%prod.b", "a"}, props.Keys())
+	assert.Equal(t, "staging", props.GetString("%staging.a", ""))
+	assert.Equal(t, "prod", props.GetString("%prod.b", ""))
+	assert.Equal(t, "default", props.GetString("a", ""))
+
+	assert.Equal(t, "", propertyFileProfile("/tmp/application.properties"))
+	assert.Equal(t, "", propertyFileProfile("/tmp/my-staging.properties"))
+}
+
 func TestRunResourceFlag(t *testing.T) {
 	runCmdOptions, rootCmd, _ := initializeRunCmdOptions(t)
 	_, err := test.ExecuteCommand(rootCmd, cmdRun,
@@ -490,6 +571,15 @@ func TestRunValidateArgs(t *testing.T) {
 	assert.Equal(t, "One of the provided sources is not reachable: missing file or unsupported scheme in missing_file", err.Error())
 }
 
+func TestRunValidateArgsWithOpenAPIOnly(t *testing.T) {
+	runCmdOptions, _, _ := initializeRunCmdOptions(t)
+	runCmd, _ := newCmdRun(runCmdOptions.RootCmdOptions)
+	assert.Nil(t, runCmd.Flags().Set("open-api", "file:pets.yaml"))
+
+	err := runCmdOptions.validateArgs(runCmd, []string{})
+	assert.Nil(t, err)
+}
+
 func TestRunBinaryResource(t *testing.T) {
 	binaryResourceSpec, err := binaryOrTextResource("file.ext", []byte{1, 2, 3, 4}, "application/octet-stream", false, v1.ResourceTypeData, "")
 	assert.Nil(t, err)
@@ -661,6 +751,64 @@ status: {}
 `, fileName, fileName), output)
 }
 
+func TestRunPrebuiltRoutes(t *testing.T) {
+	runCmdOptions, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	output, err := test.ExecuteCommand(runCmd, cmdRun, "mvn:org.acme:routes:1.0", "--name", "routes", "-o", "yaml", "-t", "prebuilt-routes.packages=org.acme.routes")
+	assert.Equal(t, "yaml", runCmdOptions.OutputFormat)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `apiVersion: camel.apache.org/v1
+kind: Integration
+metadata:
+  creationTimestamp: null
+  name: routes
+spec:
+  dependencies:
+  - mvn:org.acme:routes:1.0
+  traits:
+    prebuilt-routes:
+      configuration:
+        packages:
+        - org.acme.routes
+status: {}
+`, output)
+}
+
+func TestRunPrebuiltRoutesWithoutTrait(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	_, err := test.ExecuteCommand(runCmd, cmdRun, "mvn:org.acme:routes:1.0", "--name", "routes", "-o", "yaml")
+	assert.NotNil(t, err)
+	assert.Equal(t, "the routes to load from the jar artifacts must be set with the prebuilt-routes.classes or prebuilt-routes.packages trait", err.Error())
+}
+
+func TestRunPreview(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	output, err := test.ExecuteCommand(runCmd, cmdRun, "mvn:org.acme:routes:1.0", "--name", "routes", "-o", "yaml",
+		"-t", "prebuilt-routes.packages=org.acme.routes", "-t", "route.host=routes.example.com", "--preview", "pr-123")
+
+	assert.Nil(t, err)
+	assert.Contains(t, output, "name: routes-pr-123\n")
+	assert.Contains(t, output, "camel.apache.org/preview: pr-123\n")
+	assert.Contains(t, output, "camel.apache.org/preview.expiration: ")
+	assert.Contains(t, output, "host: routes-pr-123.example.com\n")
+}
+
+func TestRunPreviewInvalid(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	_, err := test.ExecuteCommand(runCmd, cmdRun, integrationSource, "-o", "yaml", "--preview", "PR_123")
+	assert.NotNil(t, err)
+
+	_, runCmd, _ = initializeRunCmdOptionsWithOutput(t)
+	_, err = test.ExecuteCommand(runCmd, cmdRun, integrationSource, "-o", "yaml", "--preview", "pr-123", "--preview-ttl", "1 day")
+	assert.NotNil(t, err)
+}
+
+func TestPreviewHost(t *testing.T) {
+	assert.Equal(t, "hello-pr-1.example.com", previewHost("hello.example.com", "pr-1"))
+	assert.Equal(t, "hello-pr-1.example.com", previewHost("hello-pr-1.example.com", "pr-1"))
+	assert.Equal(t, "hello-pr-1", previewHost("hello", "pr-1"))
+}
+
 func TestMissingTrait(t *testing.T) {
 	var tmpFile *os.File
 	var err error
@@ -710,3 +858,54 @@ func TestResolveJsonPodTemplateWithSupplementalGroups(t *testing.T) {
 	assert.Equal(t, 1, len(integrationSpec.PodTemplate.Spec.SecurityContext.SupplementalGroups))
 	assert.Contains(t, integrationSpec.PodTemplate.Spec.SecurityContext.SupplementalGroups, int64(666))
 }
+
+func TestRunStdinSource(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	runCmd.SetIn(strings.NewReader(`- from:
+    uri: "timer:tick"
+    steps:
+    - to: "log:info"
+`))
+	output, err := test.ExecuteCommand(runCmd, cmdRun, "-", "--name", "foo", "-o", "yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "  name: foo\n")
+	assert.Contains(t, output, "uri: timer:tick")
+}
+
+func TestRunStdinSources(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	runCmd.SetIn(strings.NewReader(`from('timer:tick').log('groovy')
+---
+public class Routes extends RouteBuilder {
+  public void configure() {
+    from("timer:tick").log("java");
+  }
+}
+`))
+	output, err := test.ExecuteCommand(runCmd, cmdRun, "--name", "foo", "--source", "-", "--source", "-",
+		"--language", "groovy", "--language", "java", "-o", "yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "name: foo-1.groovy")
+	assert.Contains(t, output, "name: Routes.java")
+}
+
+func TestRunStdinSourceWithoutName(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	runCmd.SetIn(strings.NewReader("- from:\n    uri: \"timer:tick\"\n"))
+	_, err := test.ExecuteCommand(runCmd, cmdRun, "-", "-o", "yaml")
+	assert.NotNil(t, err)
+	assert.Equal(t, "the integration name must be set with --name when the sources are read from the standard input", err.Error())
+}
+
+func TestRunStdinSourceInvalidLanguage(t *testing.T) {
+	_, runCmd, _ := initializeRunCmdOptionsWithOutput(t)
+	_, err := test.ExecuteCommand(runCmd, cmdRun, "-", "--name", "foo", "--language", "cobol", "-o", "yaml")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `unsupported language "cobol"`)
+}
+
+func TestSplitStdin(t *testing.T) {
+	assert.Equal(t, []string{"a: 1\n---\nb: 2\n"}, splitStdin("a: 1\n---\nb: 2\n", 1))
+	assert.Equal(t, []string{"a: 1\n", "b: 2\n"}, splitStdin("---\na: 1\n---\nb: 2\n", 2))
+	assert.Equal(t, []string{"a: 1\n"}, splitStdin("a: 1\n", 2))
+}
@@ -0,0 +1,144 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/schema"
+)
+
+func newCmdSchema(rootCmdOptions *RootCmdOptions) (*cobra.Command, *schemaCmdOptions) {
+	options := schemaCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+
+	cmd := cobra.Command{
+		Use:   "schema [traits|yaml-dsl]",
+		Short: "Export the JSON schema of the traits configuration, or of the YAML DSL",
+		Long: `Export the JSON schema of the traits configuration, or of the YAML DSL, so that editors provide completion and
+validation. The schemas match the traits and the catalog bundled with the CLI, unless the --from-operator flag is set,
+in which case they are retrieved from the operator. They are also available in the camel-k-schemas ConfigMap, in the
+operator namespace.`,
+		Args:    options.validate,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+		Annotations: map[string]string{
+			offlineCommandLabel: "true",
+		},
+	}
+
+	cmd.Flags().Bool("from-operator", false, "Retrieve the schema from the operator")
+	cmd.Flags().String("operator-namespace", "", "The namespace of the operator, defaults to the current namespace")
+	cmd.Flags().Int32("operator-port", 8082, "The port of the operator schema endpoint")
+
+	return &cmd, &options
+}
+
+type schemaCmdOptions struct {
+	*RootCmdOptions
+	FromOperator      bool   `mapstructure:"from-operator"`
+	OperatorNamespace string `mapstructure:"operator-namespace"`
+	OperatorPort      int32  `mapstructure:"operator-port"`
+}
+
+func (o *schemaCmdOptions) validate(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("schema expects a schema name argument, either traits or yaml-dsl")
+	}
+	if !isSchemaName(args[0] + ".json") {
+		return fmt.Errorf("unknown schema %s, must be either traits or yaml-dsl", args[0])
+	}
+
+	return nil
+}
+
+func (o *schemaCmdOptions) run(cmd *cobra.Command, args []string) error {
+	name := args[0] + ".json"
+
+	var data []byte
+	var err error
+	if o.FromOperator {
+		data, err = o.fetch(name)
+	} else {
+		data, err = schema.Generate(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(string(data)))
+
+	return nil
+}
+
+// fetch retrieves the schema from the operator, through the API server Pod proxy.
+func (o *schemaCmdOptions) fetch(name string) ([]byte, error) {
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := o.OperatorNamespace
+	if namespace == "" {
+		namespace = o.Namespace
+	}
+	if namespace == "" {
+		// The current namespace is not resolved for offline commands
+		if namespace, err = c.GetCurrentNamespace(o.KubeConfig); err != nil {
+			return nil, err
+		}
+	}
+	return fetchSchema(o.Context, c, namespace, o.OperatorPort, name)
+}
+
+// fetchSchema retrieves the schema from the operator running in the given namespace, through the API server Pod proxy.
+func fetchSchema(ctx context.Context, c client.Client, namespace string, port int32, name string) ([]byte, error) {
+	pod, err := getRunningOperatorPod(ctx, c, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.CoreV1().RESTClient().Get().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", pod.Name, port)).
+		SubResource("proxy").
+		Suffix(schema.Path + name).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve schema %s from operator %s: %w", name, pod.Name, err)
+	}
+
+	return data, nil
+}
+
+func isSchemaName(name string) bool {
+	for _, n := range schema.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestSchemaNoName(t *testing.T) {
+	options, rootCmd := kamelTestPreAddCommandInit()
+	schemaCmd, _ := newCmdSchema(options)
+	rootCmd.AddCommand(schemaCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	_, err := test.ExecuteCommand(rootCmd, "schema")
+	assert.NotNil(t, err)
+
+	_, err = test.ExecuteCommand(rootCmd, "schema", "unknown")
+	assert.NotNil(t, err)
+}
+
+func TestSchemaTraits(t *testing.T) {
+	options, rootCmd := kamelTestPreAddCommandInit()
+	schemaCmd, _ := newCmdSchema(options)
+	rootCmd.AddCommand(schemaCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	output, err := test.ExecuteCommand(rootCmd, "schema", "traits")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "\"title\": \"Camel K traits\"")
+}
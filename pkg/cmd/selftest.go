@@ -0,0 +1,368 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+const selftestSource = `- from:
+    uri: "timer:selftest?period=1000"
+    steps:
+      - setBody:
+          constant: "Camel K self-test"
+      - to: "log:selftest"
+`
+
+func newCmdSelftest(rootCmdOptions *RootCmdOptions) (*cobra.Command, *selftestCmdOptions) {
+	options := selftestCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:   "selftest",
+		Short: "Verify the operator by running a canary integration",
+		Long: `Deploy a canary integration, and validate the complete pipeline, i.e., the build and publication of its image, ` +
+			`its deployment, health and metrics, and its cleanup, reporting the time taken by each stage. ` +
+			`The command fails if any stage fails, so that it can be used to verify an installation, or periodically check the cluster health.`,
+		Args:    cobra.NoArgs,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().String("name", "camel-k-selftest", "The name of the canary integration")
+	cmd.Flags().String("operator-id", "", "The id of the operator to test, defaults to the default operator")
+	cmd.Flags().String("timeout", "15m", "The maximum duration of the self-test")
+	cmd.Flags().Bool("keep", false, "Keep the canary integration and its kit once tested")
+
+	return &cmd, &options
+}
+
+type selftestCmdOptions struct {
+	*RootCmdOptions
+	Name       string `mapstructure:"name"`
+	OperatorID string `mapstructure:"operator-id"`
+	Timeout    string `mapstructure:"timeout"`
+	Keep       bool   `mapstructure:"keep"`
+}
+
+// selftestStage reports the outcome of a stage of the self-test.
+type selftestStage struct {
+	Name     string
+	Duration time.Duration
+	Details  string
+	Err      error
+	Skipped  bool
+}
+
+func (o *selftestCmdOptions) run(cmd *cobra.Command, _ []string) error {
+	timeout, err := time.ParseDuration(o.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid timeout %q", o.Timeout)
+	}
+
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	it, err := o.newCanary(c)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	stages := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{"create", func() (string, error) { return o.create(c, it) }},
+		{"build", func() (string, error) { return o.waitForKit(c, it, deadline) }},
+		{"deploy", func() (string, error) { return o.waitForPhase(c, it, v1.IntegrationPhaseRunning, deadline) }},
+		{"health", func() (string, error) { return o.waitForReadiness(c, it, deadline) }},
+		{"metrics", func() (string, error) { return o.scrapeMetrics(c, it, deadline) }},
+	}
+
+	report := make([]selftestStage, 0, len(stages)+1)
+	failed := false
+	for _, s := range stages {
+		if failed {
+			report = append(report, selftestStage{Name: s.name, Skipped: true})
+			continue
+		}
+		report = append(report, runSelftestStage(s.name, s.run))
+		failed = report[len(report)-1].Err != nil
+	}
+
+	if o.Keep {
+		report = append(report, selftestStage{Name: "cleanup", Skipped: true, Details: "kept integration " + it.Name})
+	} else {
+		// Give the cleanup its own deadline, so that it is attempted even when the previous stages timed out
+		report = append(report, runSelftestStage("cleanup", func() (string, error) {
+			return o.cleanup(c, it, time.Now().Add(5*time.Minute))
+		}))
+	}
+
+	printSelftestReport(cmd, report)
+
+	for _, s := range report {
+		if s.Err != nil {
+			return fmt.Errorf("self-test failed at stage %s", s.Name)
+		}
+	}
+	return nil
+}
+
+func runSelftestStage(name string, run func() (string, error)) selftestStage {
+	start := time.Now()
+	details, err := run()
+	return selftestStage{
+		Name:     name,
+		Duration: time.Since(start),
+		Details:  details,
+		Err:      err,
+	}
+}
+
+func printSelftestReport(cmd *cobra.Command, report []selftestStage) {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "STAGE\tSTATUS\tDURATION\tDETAILS")
+	for _, s := range report {
+		status, duration, details := "OK", s.Duration.Round(time.Millisecond).String(), s.Details
+		switch {
+		case s.Skipped:
+			status, duration = "SKIPPED", "-"
+		case s.Err != nil:
+			status, details = "FAILED", s.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, status, duration, details)
+	}
+	w.Flush()
+}
+
+// newCanary returns a timer-log integration, exposing health checks and metrics, that requires a kit of its own,
+// so that the build is part of the test.
+func (o *selftestCmdOptions) newCanary(c client.Client) (*v1.Integration, error) {
+	it := v1.NewIntegration(o.Namespace, o.Name)
+	it.Labels = map[string]string{
+		"camel.apache.org/selftest": "true",
+	}
+	if o.OperatorID != "" {
+		it.Annotations = map[string]string{
+			v1.OperatorIDAnnotation: o.OperatorID,
+		}
+	}
+	it.Spec.Sources = []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:    "selftest.yaml",
+				Content: selftestSource,
+			},
+			Language: v1.LanguageYaml,
+		},
+	}
+
+	traits, err := configureTraits([]string{
+		"health.enabled=true",
+		"prometheus.enabled=true",
+		"prometheus.pod-monitor=false",
+		// A unique build property forces a new kit to be built for each run
+		"builder.properties=camel.k.selftest=" + strconv.FormatInt(time.Now().UnixNano(), 10),
+	}, trait.NewCatalog(c))
+	if err != nil {
+		return nil, err
+	}
+	it.Spec.Traits = traits
+
+	return &it, nil
+}
+
+func (o *selftestCmdOptions) create(c client.Client, it *v1.Integration) (string, error) {
+	if err := c.Create(o.Context, it); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return "", fmt.Errorf("integration %s already exists, delete it or use another name", it.Name)
+		}
+		return "", err
+	}
+	return "created integration " + it.Name, nil
+}
+
+func (o *selftestCmdOptions) waitForKit(c client.Client, it *v1.Integration, deadline time.Time) (string, error) {
+	kit := v1.NewIntegrationKit("", "")
+	err := o.poll(deadline, func() (bool, error) {
+		if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(it), it); err != nil {
+			return false, err
+		}
+		if it.Status.Phase == v1.IntegrationPhaseError {
+			return false, fmt.Errorf("integration in error: %s", integrationErrorMessage(it))
+		}
+		if it.Status.IntegrationKit == nil {
+			return false, nil
+		}
+		key := k8sclient.ObjectKey{Namespace: it.Status.IntegrationKit.Namespace, Name: it.Status.IntegrationKit.Name}
+		if err := c.Get(o.Context, key, kit); err != nil {
+			return false, k8sclient.IgnoreNotFound(err)
+		}
+		switch kit.Status.Phase {
+		case v1.IntegrationKitPhaseError:
+			reason := "unknown reason"
+			if kit.Status.Failure != nil {
+				reason = kit.Status.Failure.Reason
+			}
+			return false, fmt.Errorf("kit %s failed to build: %s", kit.Name, reason)
+		case v1.IntegrationKitPhaseReady:
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "published image " + kit.Status.Image, nil
+}
+
+func (o *selftestCmdOptions) waitForPhase(c client.Client, it *v1.Integration, phase v1.IntegrationPhase, deadline time.Time) (string, error) {
+	err := o.poll(deadline, func() (bool, error) {
+		if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(it), it); err != nil {
+			return false, err
+		}
+		if it.Status.Phase == v1.IntegrationPhaseError {
+			return false, fmt.Errorf("integration in error: %s", integrationErrorMessage(it))
+		}
+		return it.Status.Phase == phase, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "integration in phase " + string(phase), nil
+}
+
+func (o *selftestCmdOptions) waitForReadiness(c client.Client, it *v1.Integration, deadline time.Time) (string, error) {
+	err := o.poll(deadline, func() (bool, error) {
+		if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(it), it); err != nil {
+			return false, err
+		}
+		ready := it.Status.GetCondition(v1.IntegrationConditionReady)
+		return ready != nil && ready.Status == corev1.ConditionTrue, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "readiness probes succeeded", nil
+}
+
+func (o *selftestCmdOptions) scrapeMetrics(c client.Client, it *v1.Integration, deadline time.Time) (string, error) {
+	var details string
+	err := o.poll(deadline, func() (bool, error) {
+		pods := corev1.PodList{}
+		if err := c.List(o.Context, &pods, k8sclient.InNamespace(it.Namespace), k8sclient.MatchingLabels{v1.IntegrationLabel: it.Name}); err != nil {
+			return false, err
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			// Scrape the metrics endpoint through the API server proxy
+			data, err := c.CoreV1().Pods(pod.Namespace).ProxyGet("http", pod.Name, metricsPort(pod), "/q/metrics", nil).DoRaw(o.Context)
+			if err != nil {
+				return false, nil
+			}
+			if strings.Contains(string(data), "camel_") {
+				details = "scraped metrics from pod " + pod.Name
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return details, err
+}
+
+func metricsPort(pod *corev1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == "http" {
+				return strconv.Itoa(int(port.ContainerPort))
+			}
+		}
+	}
+	return "8080"
+}
+
+func (o *selftestCmdOptions) cleanup(c client.Client, it *v1.Integration, deadline time.Time) (string, error) {
+	if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(it), it); err != nil {
+		return "", k8sclient.IgnoreNotFound(err)
+	}
+	if err := c.Delete(o.Context, it); err != nil && !k8serrors.IsNotFound(err) {
+		return "", err
+	}
+	if ref := it.Status.IntegrationKit; ref != nil {
+		kit := v1.NewIntegrationKit(ref.Namespace, ref.Name)
+		if err := c.Delete(o.Context, kit); err != nil && !k8serrors.IsNotFound(err) {
+			return "", err
+		}
+	}
+
+	err := o.poll(deadline, func() (bool, error) {
+		if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(it), it); err == nil || !k8serrors.IsNotFound(err) {
+			return false, k8sclient.IgnoreNotFound(err)
+		}
+		pods := corev1.PodList{}
+		if err := c.List(o.Context, &pods, k8sclient.InNamespace(it.Namespace), k8sclient.MatchingLabels{v1.IntegrationLabel: it.Name}); err != nil {
+			return false, err
+		}
+		return len(pods.Items) == 0, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "deleted integration and kit", nil
+}
+
+func (o *selftestCmdOptions) poll(deadline time.Time, condition wait.ConditionFunc) error {
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return wait.ErrWaitTimeout
+	}
+	return wait.PollImmediate(time.Second, timeout, condition)
+}
+
+func integrationErrorMessage(it *v1.Integration) string {
+	for _, condition := range it.Status.Conditions {
+		if condition.Status == corev1.ConditionFalse && condition.Message != "" {
+			return condition.Message
+		}
+	}
+	return "unknown reason"
+}
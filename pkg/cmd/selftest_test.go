@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdSelftest = "selftest"
+
+func initializeSelftestCmdOptions(t *testing.T, c client.Client) (*selftestCmdOptions, *cobra.Command) {
+	t.Helper()
+
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	selftestCmd, selftestOptions := newCmdSelftest(&options)
+	selftestCmd.Args = test.ArbitraryArgs
+	rootCmd.AddCommand(selftestCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return selftestOptions, rootCmd
+}
+
+func TestSelftestCanary(t *testing.T) {
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+	options, _ := initializeSelftestCmdOptions(t, c)
+	options.Namespace = "default"
+	options.Name = "canary"
+	options.OperatorID = "operator-a"
+
+	it, err := options.newCanary(c)
+	assert.Nil(t, err)
+	assert.Equal(t, "canary", it.Name)
+	assert.Equal(t, "operator-a", it.Annotations[v1.OperatorIDAnnotation])
+	assert.Len(t, it.Spec.Sources, 1)
+	assert.Contains(t, it.Spec.Traits, "health")
+	assert.Contains(t, it.Spec.Traits, "prometheus")
+	assert.Contains(t, string(it.Spec.Traits["builder"].Configuration.RawMessage), "camel.k.selftest=")
+}
+
+func TestSelftestTimeout(t *testing.T) {
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+	_, rootCmd := initializeSelftestCmdOptions(t, c)
+
+	// No operator reconciles the canary integration, so that the build stage times out
+	output, err := test.ExecuteCommand(rootCmd, cmdSelftest, "-n", "default", "--timeout", "1s")
+	assert.NotNil(t, err)
+	assert.Contains(t, output, "create\tOK")
+	assert.Contains(t, output, "build\tFAILED")
+	assert.Contains(t, output, "metrics\tSKIPPED")
+	assert.Contains(t, output, "cleanup\tOK")
+
+	it := v1.NewIntegration("default", "camel-k-selftest")
+	assert.NotNil(t, c.Get(context.TODO(), k8sclient.ObjectKeyFromObject(&it), &it))
+}
@@ -72,6 +72,9 @@ type traitDescription struct {
 	Name        trait.ID                   `json:"name" yaml:"name"`
 	Platform    bool                       `json:"platform" yaml:"platform"`
 	Profiles    []string                   `json:"profiles" yaml:"profiles"`
+	Order       int                        `json:"order" yaml:"order"`
+	Requires    []trait.ID                 `json:"requires,omitempty" yaml:"requires,omitempty"`
+	Influences  []trait.ID                 `json:"influences,omitempty" yaml:"influences,omitempty"`
 	Properties  []traitPropertyDescription `json:"properties" yaml:"properties"`
 	Description string                     `json:"description" yaml:"description"`
 }
@@ -101,6 +104,12 @@ func (command *traitHelpCommandOptions) run(cmd *cobra.Command, args []string) e
 	var traitDescriptions []*traitDescription
 	catalog := trait.NewCatalog(nil)
 
+	// the position of each trait in the execution sequence resolved by the catalog
+	positions := make(map[trait.ID]int)
+	for i, t := range catalog.AllTraits() {
+		positions[t.ID()] = i + 1
+	}
+
 	content, err := resources.Resource("/traits.yaml")
 	if err != nil {
 		return err
@@ -122,9 +131,12 @@ func (command *traitHelpCommandOptions) run(cmd *cobra.Command, args []string) e
 			td := findTraitDescription(t.ID(), traitDescriptions)
 			if td == nil {
 				td = &traitDescription{
-					Name:     t.ID(),
-					Platform: t.IsPlatformTrait(),
-					Profiles: make([]string, 0),
+					Name:       t.ID(),
+					Platform:   t.IsPlatformTrait(),
+					Profiles:   make([]string, 0),
+					Order:      positions[t.ID()],
+					Requires:   t.RequiresTraits(),
+					Influences: t.InfluencesTraits(),
 				}
 
 				var targetTrait *traitDescription
@@ -247,6 +259,13 @@ func outputTraits(descriptions []*traitDescription) (string, error) {
 			w.Writef(0, "Name:\t%s\n", td.Name)
 			w.Writef(0, "Profiles:\t%s\n", strings.Join(td.Profiles, ","))
 			w.Writef(0, "Platform:\t%t\n", td.Platform)
+			w.Writef(0, "Order:\t%d\n", td.Order)
+			if len(td.Requires) > 0 {
+				w.Writef(0, "Requires:\t%s\n", joinTraitIDs(td.Requires))
+			}
+			if len(td.Influences) > 0 {
+				w.Writef(0, "Influences:\t%s\n", joinTraitIDs(td.Influences))
+			}
 			w.Writef(0, "Properties:\n")
 			for _, p := range td.Properties {
 				w.Writef(1, "%s:\n", p.Name)
@@ -261,3 +280,11 @@ func outputTraits(descriptions []*traitDescription) (string, error) {
 		return nil
 	})
 }
+
+func joinTraitIDs(ids []trait.ID) string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, string(id))
+	}
+	return strings.Join(names, ",")
+}
@@ -23,6 +23,8 @@ package cmd
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/apache/camel-k/pkg/util/test"
 )
 
@@ -38,3 +40,16 @@ func TestHelpForNonExistentTrait(t *testing.T) {
 		t.Fatalf("Expected error result for invalid trait 'foobar'")
 	}
 }
+
+func TestHelpForTraitReportsOrdering(t *testing.T) {
+	options, rootCommand := kamelTestPreAddCommandInit()
+	traitHelpCommand, _ := newTraitHelpCmd(options)
+	rootCommand.AddCommand(traitHelpCommand)
+
+	kamelTestPostAddCommandInit(t, rootCommand)
+
+	output, err := test.ExecuteCommand(rootCommand, "trait", "mount", "-o", "yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, output, "order: ")
+	assert.Contains(t, output, "requires:\n  - container")
+}
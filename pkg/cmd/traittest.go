@@ -0,0 +1,162 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/apache/camel-k/pkg/trait/traittest"
+)
+
+func newCmdTraitTest(rootCmdOptions *RootCmdOptions) (*cobra.Command, *traitTestCmdOptions) {
+	options := traitTestCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+
+	cmd := cobra.Command{
+		Use:   "trait-test [file or directory...]",
+		Short: "Run declarative trait tests",
+		Long: `Run the TraitTest documents of the given YAML files, or of the YAML files of the given directories. A TraitTest
+declares an Integration, and JSONPath assertions on the resources the traits bundled with the CLI generate for it.
+The traits are applied against a fake cluster, so that no cluster is needed. The passed tests are printed with --verbose.`,
+		Example: `  kamel trait-test my-trait.traittest.yaml
+  kamel trait-test tests/ --run replicas`,
+		Args:    options.validate,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+		Annotations: map[string]string{
+			offlineCommandLabel: "true",
+		},
+	}
+
+	cmd.Flags().String("run", "", "Run only the tests whose name matches the regular expression")
+
+	return &cmd, &options
+}
+
+type traitTestCmdOptions struct {
+	*RootCmdOptions
+	Run string `mapstructure:"run"`
+}
+
+func (o *traitTestCmdOptions) validate(_ *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("trait-test expects at least one file or directory argument")
+	}
+	if _, err := regexp.Compile(o.Run); err != nil {
+		return fmt.Errorf("invalid --run expression %q: %w", o.Run, err)
+	}
+
+	return nil
+}
+
+func (o *traitTestCmdOptions) run(cmd *cobra.Command, args []string) error {
+	files, err := traitTestFiles(args)
+	if err != nil {
+		return err
+	}
+	filter := regexp.MustCompile(o.Run)
+
+	out := cmd.OutOrStdout()
+	passed, failed := 0, 0
+	for _, file := range files {
+		tests, err := loadTraitTests(file)
+		if err != nil {
+			return err
+		}
+		for _, t := range tests {
+			if !filter.MatchString(t.Name) {
+				continue
+			}
+			result, err := traittest.Run(o.Context, t)
+			if err != nil {
+				return fmt.Errorf("cannot run trait test %s of %s: %w", t.Name, file, err)
+			}
+			if result.Passed() {
+				passed++
+				if o.Verbose {
+					fmt.Fprintf(out, "PASS %s (%s)\n", t.Name, file)
+				}
+				continue
+			}
+			failed++
+			fmt.Fprintf(out, "FAIL %s (%s)\n", t.Name, file)
+			for _, f := range result.Failures {
+				fmt.Fprintf(out, "    %s\n", f)
+			}
+		}
+	}
+
+	fmt.Fprintf(out, "%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d trait tests failed", failed)
+	}
+
+	return nil
+}
+
+// traitTestFiles returns the given files, and the YAML files of the given directories, walked recursively.
+func traitTestFiles(args []string) ([]string, error) {
+	files := make([]string, 0, len(args))
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err = filepath.Walk(arg, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && (strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func loadTraitTests(file string) ([]traittest.TraitTest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tests, err := traittest.Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load trait tests from %s: %w", file, err)
+	}
+
+	return tests, nil
+}
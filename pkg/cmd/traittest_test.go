@@ -0,0 +1,92 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const traitTests = `
+apiVersion: camel.apache.org/v1alpha1
+kind: TraitTest
+metadata:
+  name: replicas
+spec:
+  integration:
+    metadata:
+      name: hello
+    spec:
+      replicas: 2
+      sources:
+      - name: hello.yaml
+        content: |
+          - from:
+              uri: "timer:tick"
+              steps:
+              - to: "log:info"
+  expect:
+  - kind: Deployment
+    assertions:
+    - path: "{.spec.replicas}"
+      value: %d
+`
+
+func TestTraitTestNoFile(t *testing.T) {
+	options, rootCmd := kamelTestPreAddCommandInit()
+	traitTestCmd, _ := newCmdTraitTest(options)
+	rootCmd.AddCommand(traitTestCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	_, err := test.ExecuteCommand(rootCmd, "trait-test")
+	assert.NotNil(t, err)
+}
+
+func TestTraitTestDirectory(t *testing.T) {
+	err := util.WithTempDir("camel-k-test-", func(dir string) error {
+		assert.Nil(t, ioutil.WriteFile(path.Join(dir, "passing.yaml"), []byte(fmt.Sprintf(traitTests, 2)), 0o400))
+		assert.Nil(t, ioutil.WriteFile(path.Join(dir, "failing.yaml"), []byte(fmt.Sprintf(traitTests, 3)), 0o400))
+		assert.Nil(t, ioutil.WriteFile(path.Join(dir, "README.md"), []byte("not a test"), 0o400))
+
+		options, rootCmd := kamelTestPreAddCommandInit()
+		traitTestCmd, _ := newCmdTraitTest(options)
+		rootCmd.AddCommand(traitTestCmd)
+		kamelTestPostAddCommandInit(t, rootCmd)
+
+		output, err := test.ExecuteCommand(rootCmd, "trait-test", dir)
+		assert.NotNil(t, err)
+		assert.Contains(t, output, "FAIL replicas ("+path.Join(dir, "failing.yaml")+")")
+		assert.Contains(t, output, "Deployment hello: {.spec.replicas} is 2, expected 3")
+		assert.Contains(t, output, "1 passed, 1 failed")
+
+		output, err = test.ExecuteCommand(rootCmd, "trait-test", path.Join(dir, "passing.yaml"), "--run", "^replicas$")
+		assert.Nil(t, err)
+		assert.Contains(t, output, "1 passed, 0 failed")
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+}
@@ -62,6 +62,7 @@ func newCmdUninstall(rootCmdOptions *RootCmdOptions) (*cobra.Command, *uninstall
 	cmd.Flags().Bool("skip-config-maps", false, "Do not uninstall the Camel K Config Maps in the current namespace")
 	cmd.Flags().Bool("skip-registry-secret", false, "Do not uninstall the Camel K Registry Secret in the current namespace")
 	cmd.Flags().Bool("skip-kamelets", false, "Do not uninstall the Kamelets in the current namespace")
+	cmd.Flags().Bool("skip-flow-schema", false, "Do not uninstall the Camel K Operator FlowSchema and PriorityLevelConfiguration")
 	cmd.Flags().Bool("global", false, "Indicates that a global installation is going to be uninstalled (affects OLM)")
 	cmd.Flags().Bool("olm", true, "Try to uninstall via OLM (Operator Lifecycle Manager) if available")
 	cmd.Flags().String("olm-operator-name", "", "Name of the Camel K operator in the OLM source or marketplace")
@@ -86,6 +87,7 @@ type uninstallCmdOptions struct {
 	SkipConfigMaps          bool `mapstructure:"skip-config-maps"`
 	SkipRegistrySecret      bool `mapstructure:"skip-registry-secret"`
 	SkipKamelets            bool `mapstructure:"skip-kamelets"`
+	SkipFlowSchema          bool `mapstructure:"skip-flow-schema"`
 	Global                  bool `mapstructure:"global"`
 	OlmEnabled              bool `mapstructure:"olm"`
 	UninstallAll            bool `mapstructure:"all"`
@@ -226,6 +228,19 @@ func (o *uninstallCmdOptions) uninstallClusterWideResources(ctx context.Context,
 		fmt.Fprintln(cmd.OutOrStdout(), "Camel K Cluster Roles removed from cluster")
 	}
 
+	if !o.SkipFlowSchema || o.UninstallAll {
+		if err := o.uninstallFlowControl(ctx, c, namespace); err != nil {
+			if k8serrors.IsForbidden(err) {
+				// Let's print a warning message and continue
+				fmt.Fprintln(cmd.ErrOrStderr(), "Current user is not authorized to remove the operator FlowSchema and PriorityLevelConfiguration")
+			} else {
+				return err
+			}
+		} else {
+			fmt.Fprintln(cmd.OutOrStdout(), "Camel K Operator FlowSchema removed from cluster")
+		}
+	}
+
 	return nil
 }
 
@@ -397,6 +412,49 @@ func (o *uninstallCmdOptions) uninstallClusterRoleBindings(ctx context.Context,
 	return nil
 }
 
+// uninstallFlowControl removes the FlowSchema and PriorityLevelConfiguration of the operator installed
+// in the given namespace. Both are cluster-scoped and named after the operator namespace, so the resources
+// of the operators installed in other namespaces are preserved.
+func (o *uninstallCmdOptions) uninstallFlowControl(ctx context.Context, c client.Client, namespace string) error {
+	api := c.FlowcontrolV1beta1()
+	name := fmt.Sprintf("camel-k-operator-%s", namespace)
+
+	flowSchemas, err := api.FlowSchemas().List(ctx, defaultListOptions)
+	if k8serrors.IsNotFound(err) {
+		// API Priority and Fairness is not enabled on the cluster
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, flowSchema := range flowSchemas.Items {
+		if flowSchema.Name != name {
+			continue
+		}
+		err := api.FlowSchemas().Delete(ctx, flowSchema.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	priorityLevels, err := api.PriorityLevelConfigurations().List(ctx, defaultListOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, priorityLevel := range priorityLevels.Items {
+		if priorityLevel.Name != name {
+			continue
+		}
+		err := api.PriorityLevelConfigurations().Delete(ctx, priorityLevel.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (o *uninstallCmdOptions) uninstallServiceAccounts(ctx context.Context, c client.Client) error {
 	api := c.CoreV1()
 
@@ -18,10 +18,15 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	flowcontrolv1beta1 "k8s.io/api/flowcontrol/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
 	"github.com/apache/camel-k/pkg/util/test"
 	"github.com/spf13/cobra"
 )
@@ -77,3 +82,61 @@ func TestUninstallAllFlag(t *testing.T) {
 	assert.True(t, uninstallCmdOptions.SkipClusterRoles)
 	assert.False(t, uninstallCmdOptions.SkipIntegrationPlatform)
 }
+
+func TestUninstallSkipFlowSchemaFlag(t *testing.T) {
+	options, cmd := kamelTestPreAddCommandInit()
+
+	uninstallCmdOptions := addTestUninstallCmd(options, cmd)
+
+	kamelTestPostAddCommandInit(t, cmd)
+
+	_, err := test.ExecuteCommand(cmd, "uninstall", "--skip-flow-schema")
+	assert.Nil(t, err)
+	assert.True(t, uninstallCmdOptions.SkipFlowSchema)
+}
+
+func TestUninstallFlowControl(t *testing.T) {
+	labels := map[string]string{"app": "camel-k"}
+	objects := []runtime.Object{
+		&flowcontrolv1beta1.FlowSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: "camel-k-operator-ns1", Labels: labels},
+		},
+		&flowcontrolv1beta1.FlowSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: "camel-k-operator-ns2", Labels: labels},
+		},
+		&flowcontrolv1beta1.FlowSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: "camel-k-operator-ns1-other"},
+		},
+		&flowcontrolv1beta1.PriorityLevelConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "camel-k-operator-ns1", Labels: labels},
+		},
+		&flowcontrolv1beta1.PriorityLevelConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "camel-k-operator-ns2", Labels: labels},
+		},
+	}
+
+	c, err := test.NewFakeClient(objects...)
+	assert.Nil(t, err)
+
+	options := uninstallCmdOptions{}
+	ctx := context.TODO()
+	err = options.uninstallFlowControl(ctx, c, "ns1")
+	assert.Nil(t, err)
+
+	flowSchemas, err := c.FlowcontrolV1beta1().FlowSchemas().List(ctx, metav1.ListOptions{})
+	assert.Nil(t, err)
+	names := make([]string, 0, len(flowSchemas.Items))
+	for _, flowSchema := range flowSchemas.Items {
+		names = append(names, flowSchema.Name)
+	}
+	assert.ElementsMatch(t, []string{"camel-k-operator-ns2", "camel-k-operator-ns1-other"}, names)
+
+	priorityLevels, err := c.FlowcontrolV1beta1().PriorityLevelConfigurations().List(ctx, metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, priorityLevels.Items, 1)
+	assert.Equal(t, "camel-k-operator-ns2", priorityLevels.Items[0].Name)
+
+	// uninstalling again is a no-op
+	err = options.uninstallFlowControl(ctx, c, "ns1")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+)
+
+// getRunningOperatorPod returns a running operator Pod in the given namespace, preferably the leader, that the other
+// replicas would forward the uploads to. Any running replica is returned when the leader cannot be determined,
+// e.g., when the user is not allowed to get the leader election Lease.
+func getRunningOperatorPod(ctx context.Context, c client.Client, namespace string) (*corev1.Pod, error) {
+	pods := corev1.PodList{}
+	if err := c.List(ctx, &pods,
+		k8sclient.InNamespace(namespace),
+		k8sclient.MatchingLabels{"camel.apache.org/component": "operator"},
+	); err != nil {
+		return nil, err
+	}
+	leader, _ := platform.GetOperatorLeaderPodName(ctx, c, k8sclient.ObjectKey{Namespace: namespace, Name: platform.OperatorLockName})
+
+	var running *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if pods.Items[i].Name == leader {
+			return &pods.Items[i], nil
+		}
+		if running == nil {
+			running = &pods.Items[i]
+		}
+	}
+	if running == nil {
+		return nil, fmt.Errorf("no running operator found in namespace %s", namespace)
+	}
+	return running, nil
+}
@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	coordination "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestGetRunningOperatorPodPrefersLeader(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      name,
+				Labels:    map[string]string{"camel.apache.org/component": "operator"},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	holder := "operator-b_0123-4567"
+	lease := &coordination.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: platform.OperatorLockName},
+		Spec:       coordination.LeaseSpec{HolderIdentity: &holder},
+	}
+
+	c, err := test.NewFakeClient(pod("operator-a", corev1.PodRunning), pod("operator-b", corev1.PodRunning), lease)
+	assert.Nil(t, err)
+	p, err := getRunningOperatorPod(context.Background(), c, "ns")
+	assert.Nil(t, err)
+	assert.Equal(t, "operator-b", p.Name)
+
+	// any running replica is returned when the leader is unknown
+	c, err = test.NewFakeClient(pod("operator-a", corev1.PodPending), pod("operator-b", corev1.PodRunning))
+	assert.Nil(t, err)
+	p, err = getRunningOperatorPod(context.Background(), c, "ns")
+	assert.Nil(t, err)
+	assert.Equal(t, "operator-b", p.Name)
+
+	c, err = test.NewFakeClient(pod("operator-a", corev1.PodPending))
+	assert.Nil(t, err)
+	_, err = getRunningOperatorPod(context.Background(), c, "ns")
+	assert.NotNil(t, err)
+}
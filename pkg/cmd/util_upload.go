@@ -0,0 +1,238 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/resource"
+	"github.com/apache/camel-k/pkg/util/upload"
+)
+
+// uploadChunkSize is the size of the chunks the files are uploaded in, below the limit of the operator.
+const uploadChunkSize = 4 * 1024 * 1024
+
+// uploader uploads local files to the operator, through the API server Pod proxy, authenticated with the token
+// of the user.
+type uploader struct {
+	client    client.Client
+	pod       *corev1.Pod
+	port      int32
+	token     string
+	namespace string
+}
+
+func newUploader(ctx context.Context, c client.Client, operatorNamespace string, port int32, namespace string) (*uploader, error) {
+	token, err := bearerToken(c.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	if operatorNamespace == "" {
+		operatorNamespace = namespace
+	}
+	pod, err := getRunningOperatorPod(ctx, c, operatorNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploader{
+		client:    c,
+		pod:       pod,
+		port:      port,
+		token:     token,
+		namespace: namespace,
+	}, nil
+}
+
+// bearerToken returns the token the user is authenticated with, that the operator reviews.
+func bearerToken(config *rest.Config) (string, error) {
+	if config.BearerToken != "" {
+		return config.BearerToken, nil
+	}
+	if config.BearerTokenFile != "" {
+		data, err := ioutil.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", errors.New("uploading files to the operator requires the current context to be authenticated with a bearer token")
+}
+
+// upload sends the file in chunks, and returns its digest once committed.
+func (u *uploader) upload(ctx context.Context, file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return u.send(ctx, f)
+}
+
+// send uploads the content in chunks, and returns its digest once committed.
+func (u *uploader) send(ctx context.Context, content interface {
+	io.Reader
+	io.ReaderAt
+}) (string, error) {
+	digest, err := upload.Digest(content)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := u.do(ctx, u.request(http.MethodPost, ""))
+	if err != nil {
+		return "", err
+	}
+	id := response.ID
+
+	buffer := make([]byte, uploadChunkSize)
+	for offset := int64(0); ; {
+		n, err := content.ReadAt(buffer, offset)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		if n == 0 {
+			break
+		}
+		response, err = u.do(ctx, u.request(http.MethodPut, id).
+			Param("offset", strconv.FormatInt(offset, 10)).
+			Body(buffer[:n]))
+		if err != nil && !k8serrors.IsConflict(err) {
+			return "", err
+		}
+		// on conflict, the upload resumes from the size received by the operator
+		offset = response.Size
+	}
+
+	if _, err := u.do(ctx, u.request(http.MethodPost, id).Param("digest", digest)); err != nil {
+		return "", err
+	}
+
+	return digest, nil
+}
+
+func (u *uploader) request(method string, id string) *rest.Request {
+	return u.client.CoreV1().RESTClient().Verb(method).
+		Namespace(u.pod.Namespace).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", u.pod.Name, u.port)).
+		SubResource("proxy").
+		Suffix(path.Join(upload.Path, u.namespace, id)).
+		SetHeader(upload.TokenHeader, u.token)
+}
+
+func (u *uploader) do(ctx context.Context, request *rest.Request) (upload.Response, error) {
+	response := upload.Response{}
+	data, err := request.DoRaw(ctx)
+	if len(data) > 0 && json.Unmarshal(data, &response) == nil {
+		return response, err
+	}
+	if err != nil {
+		return response, fmt.Errorf("cannot upload to operator %s: %w", u.pod.Name, err)
+	}
+	return response, nil
+}
+
+// uploadFiles uploads the local files, among the resources or configurations, to the operator, so that they are added
+// to the Integration image, instead of being stored in ConfigMaps. It returns the items that are not local files,
+// the dependencies adding the files to the image, and the image resources mounting them at their destination path.
+func (u *uploader) uploadFiles(ctx context.Context, items []string, parse func(string) (*resource.Config, error),
+	defaultDir string) ([]string, []string, []string, error) {
+	remaining := make([]string, 0, len(items))
+	dependencies := make([]string, 0)
+	imageResources := make([]string, 0)
+	for _, item := range items {
+		config, err := parse(item)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if config.StorageType() != resource.StorageTypeFile {
+			remaining = append(remaining, item)
+			continue
+		}
+		targetPath, imageResource, err := imageResourceFor(config, defaultDir)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		digest, err := u.upload(ctx, config.Name())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot upload %s: %w", config.Name(), err)
+		}
+		dependencies = append(dependencies, upload.Dependency(u.namespace, digest, targetPath))
+		imageResources = append(imageResources, imageResource)
+	}
+
+	return remaining, dependencies, imageResources, nil
+}
+
+// uploadSources uploads the content of the sources to the operator, so that they are added to the Integration image,
+// instead of being stored in the Integration. The sources reference the uploaded content, and the returned dependencies
+// add it to the image.
+func (u *uploader) uploadSources(ctx context.Context, sources []v1.SourceSpec) ([]string, error) {
+	dependencies := make([]string, 0, len(sources))
+	for i := range sources {
+		s := &sources[i]
+		if s.ContentRef != "" || s.Content == "" {
+			continue
+		}
+		digest, err := u.send(ctx, strings.NewReader(s.Content))
+		if err != nil {
+			return nil, fmt.Errorf("cannot upload source %s: %w", s.Name, err)
+		}
+		s.ContentRef = upload.Ref(u.namespace, digest)
+		s.Content = ""
+		dependencies = append(dependencies, upload.Dependency(u.namespace, digest, upload.SourcePath(s.Name)))
+	}
+
+	return dependencies, nil
+}
+
+// imageResourceFor returns the path of the local file resource in the Integration image, relative to the deployments
+// directory, and the image resource that copies it to the same destination path a ConfigMap would be mounted at.
+func imageResourceFor(config *resource.Config, defaultDir string) (string, string, error) {
+	filename := filepath.Base(config.Name())
+	destination := config.DestinationPath()
+	if destination == "" {
+		destination = path.Join(defaultDir, filename)
+	}
+	targetPath := path.Join("camel-k-resources", hashFrom([]byte(destination))[:8], filename)
+	imageResource := fmt.Sprintf("image:%s@%s", targetPath, destination)
+	if _, err := resource.ParseResource(imageResource); err != nil {
+		return "", "", fmt.Errorf("the name of %s cannot be stored in the Integration image", config.Name())
+	}
+
+	return targetPath, imageResource, nil
+}
@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+
+	"github.com/apache/camel-k/pkg/util/resource"
+)
+
+func TestImageResourceFor(t *testing.T) {
+	config, err := resource.ParseConfig("file:/tmp/app.properties")
+	assert.Nil(t, err)
+	targetPath, imageResource, err := imageResourceFor(config, "/etc/camel/conf.d/_resources")
+	assert.Nil(t, err)
+	assert.Regexp(t, `^camel-k-resources/[0-9a-f]{8}/app.properties$`, targetPath)
+
+	image, err := resource.ParseResource(imageResource)
+	assert.Nil(t, err)
+	assert.Equal(t, resource.StorageTypeImage, image.StorageType())
+	assert.Equal(t, targetPath, image.Name())
+	assert.Equal(t, "/etc/camel/conf.d/_resources/app.properties", image.DestinationPath())
+
+	config, err = resource.ParseResource("file:/tmp/data.bin@/var/data/data.bin")
+	assert.Nil(t, err)
+	_, imageResource, err = imageResourceFor(config, "/etc/camel/resources")
+	assert.Nil(t, err)
+	image, err = resource.ParseResource(imageResource)
+	assert.Nil(t, err)
+	assert.Equal(t, "/var/data/data.bin", image.DestinationPath())
+}
+
+func TestBearerToken(t *testing.T) {
+	token, err := bearerToken(&rest.Config{BearerToken: "token"})
+	assert.Nil(t, err)
+	assert.Equal(t, "token", token)
+
+	file := filepath.Join(t.TempDir(), "token")
+	assert.Nil(t, ioutil.WriteFile(file, []byte("file-token\n"), 0o600))
+	token, err = bearerToken(&rest.Config{BearerTokenFile: file})
+	assert.Nil(t, err)
+	assert.Equal(t, "file-token", token)
+
+	_, err = bearerToken(&rest.Config{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,268 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func newCmdVerifyBuild(rootCmdOptions *RootCmdOptions) (*cobra.Command, *verifyBuildCmdOptions) {
+	options := verifyBuildCmdOptions{
+		RootCmdOptions: rootCmdOptions,
+	}
+	cmd := cobra.Command{
+		Use:   "verify-build <kit>",
+		Short: "Verify the reproducibility of an integration kit build",
+		Long: `Rebuild the given integration kit from scratch, i.e., without reusing the images of other kits as base layers, ` +
+			`and compare the resulting image digest and artifacts with the ones recorded in the kit status, to report any reproducibility drift.`,
+		Args:    options.validateArgs,
+		PreRunE: decode(&options),
+		RunE:    options.run,
+	}
+
+	cmd.Flags().String("timeout", "30m", "The maximum duration to wait for the rebuild to complete")
+	cmd.Flags().Bool("keep", false, "Keep the rebuilt integration kit once verified")
+
+	return &cmd, &options
+}
+
+type verifyBuildCmdOptions struct {
+	*RootCmdOptions
+	Timeout string `mapstructure:"timeout"`
+	Keep    bool   `mapstructure:"keep"`
+}
+
+// artifactDrift is an artifact whose recorded and rebuilt versions differ, either of them being nil if missing.
+type artifactDrift struct {
+	Recorded *v1.Artifact
+	Rebuilt  *v1.Artifact
+}
+
+func (o *verifyBuildCmdOptions) validateArgs(_ *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("verify-build expects a single kit name argument")
+	}
+	if _, err := time.ParseDuration(o.Timeout); err != nil {
+		return errors.Wrapf(err, "invalid timeout %q", o.Timeout)
+	}
+	return nil
+}
+
+func (o *verifyBuildCmdOptions) run(cmd *cobra.Command, args []string) error {
+	c, err := o.GetCmdClient()
+	if err != nil {
+		return err
+	}
+
+	kit := v1.NewIntegrationKit(o.Namespace, args[0])
+	if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(kit), kit); err != nil {
+		return errors.Wrapf(err, "could not find integration kit %s in namespace %s", args[0], o.Namespace)
+	}
+	if kit.Labels[v1.IntegrationKitTypeLabel] == v1.IntegrationKitTypeExternal {
+		return fmt.Errorf("integration kit %s is not built by the operator", kit.Name)
+	}
+	if kit.Status.Phase != v1.IntegrationKitPhaseReady {
+		return fmt.Errorf("integration kit %s is not ready (phase %q)", kit.Name, kit.Status.Phase)
+	}
+
+	rebuilt, err := newVerificationKit(kit)
+	if err != nil {
+		return err
+	}
+	if err := c.Create(o.Context, rebuilt); err != nil {
+		return errors.Wrapf(err, "could not create the integration kit to verify %s", kit.Name)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Rebuilding integration kit %s as %s\n", kit.Name, rebuilt.Name)
+
+	if !o.Keep {
+		defer func() {
+			if err := c.Delete(o.Context, rebuilt); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "could not delete integration kit %s: %v\n", rebuilt.Name, err)
+			}
+		}()
+	}
+
+	timeout, _ := time.ParseDuration(o.Timeout)
+	err = wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		if err := c.Get(o.Context, k8sclient.ObjectKeyFromObject(rebuilt), rebuilt); err != nil {
+			return false, err
+		}
+		switch rebuilt.Status.Phase {
+		case v1.IntegrationKitPhaseReady:
+			return true, nil
+		case v1.IntegrationKitPhaseError:
+			reason := "unknown reason"
+			if rebuilt.Status.Failure != nil {
+				reason = rebuilt.Status.Failure.Reason
+			}
+			return false, fmt.Errorf("integration kit %s failed to rebuild: %s", rebuilt.Name, reason)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !printBuildVerification(cmd.OutOrStdout(), kit, rebuilt) {
+		return fmt.Errorf("the build of integration kit %s is not reproducible", kit.Name)
+	}
+	return nil
+}
+
+// newVerificationKit returns a user kit with the same specification as the given kit, built without reusing
+// the images of other kits as base layers, so that it is not picked by the Integrations nor by other builds.
+func newVerificationKit(kit *v1.IntegrationKit) (*v1.IntegrationKit, error) {
+	rebuilt := v1.NewIntegrationKit(kit.Namespace, "")
+	rebuilt.GenerateName = kit.Name + "-verify-"
+	rebuilt.Annotations = make(map[string]string)
+	for k, v := range kit.Annotations {
+		rebuilt.Annotations[k] = v
+	}
+	rebuilt.Labels = map[string]string{
+		v1.IntegrationKitTypeLabel: v1.IntegrationKitTypeUser,
+	}
+	for _, l := range []string{v1.IntegrationKitLayoutLabel, "camel.apache.org/runtime.version", "camel.apache.org/runtime.provider"} {
+		if v, ok := kit.Labels[l]; ok {
+			rebuilt.Labels[l] = v
+		}
+	}
+	kit.Spec.DeepCopyInto(&rebuilt.Spec)
+
+	builder := make(map[string]interface{})
+	if spec, ok := rebuilt.Spec.Traits["builder"]; ok && len(spec.Configuration.RawMessage) > 0 {
+		if err := json.Unmarshal(spec.Configuration.RawMessage, &builder); err != nil {
+			return nil, err
+		}
+	}
+	builder["incrementalImageBuild"] = false
+	data, err := json.Marshal(builder)
+	if err != nil {
+		return nil, err
+	}
+	if rebuilt.Spec.Traits == nil {
+		rebuilt.Spec.Traits = make(map[string]v1.TraitSpec)
+	}
+	rebuilt.Spec.Traits["builder"] = v1.TraitSpec{
+		Configuration: v1.TraitConfiguration{
+			RawMessage: data,
+		},
+	}
+
+	return rebuilt, nil
+}
+
+// compareArtifacts returns the artifacts that differ between the recorded and the rebuilt ones, sorted by target.
+func compareArtifacts(recorded []v1.Artifact, rebuilt []v1.Artifact) []artifactDrift {
+	index := func(artifacts []v1.Artifact) map[string]*v1.Artifact {
+		m := make(map[string]*v1.Artifact, len(artifacts))
+		for i := range artifacts {
+			m[artifacts[i].Target] = &artifacts[i]
+		}
+		return m
+	}
+	before, after := index(recorded), index(rebuilt)
+
+	drifts := make([]artifactDrift, 0)
+	for target, a := range before {
+		if b, ok := after[target]; !ok || a.Checksum != b.Checksum {
+			drifts = append(drifts, artifactDrift{Recorded: a, Rebuilt: b})
+		}
+	}
+	for target, b := range after {
+		if _, ok := before[target]; !ok {
+			drifts = append(drifts, artifactDrift{Rebuilt: b})
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].target() < drifts[j].target()
+	})
+	return drifts
+}
+
+func (d artifactDrift) target() string {
+	if d.Recorded != nil {
+		return d.Recorded.Target
+	}
+	return d.Rebuilt.Target
+}
+
+// imageDigest returns the digest the image is addressed by, or an empty string if it's addressed by tag.
+func imageDigest(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// printBuildVerification reports the differences between the recorded and the rebuilt kits,
+// and returns whether the build is reproducible, i.e., all the artifacts are identical.
+func printBuildVerification(out io.Writer, recorded *v1.IntegrationKit, rebuilt *v1.IntegrationKit) bool {
+	drifts := compareArtifacts(recorded.Status.Artifacts, rebuilt.Status.Artifacts)
+
+	fmt.Fprintf(out, "Recorded image:\t%s\n", recorded.Status.Image)
+	fmt.Fprintf(out, "Rebuilt image:\t%s\n", rebuilt.Status.Image)
+	if digest := imageDigest(recorded.Status.Image); digest != "" && digest == imageDigest(rebuilt.Status.Image) {
+		fmt.Fprintln(out, "Image digests are identical")
+	} else {
+		fmt.Fprintln(out, "Image digests differ")
+	}
+
+	fmt.Fprintf(out, "Artifacts:\t%d identical, %d differ\n", len(recorded.Status.Artifacts)-countRecorded(drifts), len(drifts))
+	for _, d := range drifts {
+		switch {
+		case d.Rebuilt == nil:
+			fmt.Fprintf(out, "  - %s\t%s\n", d.Recorded.Target, d.Recorded.Checksum)
+		case d.Recorded == nil:
+			fmt.Fprintf(out, "  + %s\t%s\n", d.Rebuilt.Target, d.Rebuilt.Checksum)
+		default:
+			fmt.Fprintf(out, "  ~ %s\t%s -> %s\n", d.Recorded.Target, d.Recorded.Checksum, d.Rebuilt.Checksum)
+		}
+	}
+
+	if len(drifts) > 0 {
+		fmt.Fprintln(out, "Result:\tdrift detected")
+		return false
+	}
+	fmt.Fprintln(out, "Result:\treproducible")
+	return true
+}
+
+func countRecorded(drifts []artifactDrift) int {
+	count := 0
+	for _, d := range drifts {
+		if d.Recorded != nil {
+			count++
+		}
+	}
+	return count
+}
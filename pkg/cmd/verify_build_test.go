@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const cmdVerifyBuild = "verify-build"
+
+func initializeVerifyBuildCmdOptions(t *testing.T, c client.Client) *cobra.Command {
+	t.Helper()
+
+	options := RootCmdOptions{
+		Context: context.Background(),
+		_client: c,
+	}
+	rootCmd := kamelPreAddCommandInit(&options)
+	rootCmd.Run = test.EmptyRun
+	verifyBuildCmd, _ := newCmdVerifyBuild(&options)
+	rootCmd.AddCommand(verifyBuildCmd)
+	kamelTestPostAddCommandInit(t, rootCmd)
+
+	return rootCmd
+}
+
+func TestVerifyBuildNotReadyKit(t *testing.T) {
+	kit := v1.NewIntegrationKit("default", "kit-1")
+	kit.Status.Phase = v1.IntegrationKitPhaseBuildRunning
+	c, err := test.NewFakeClient(kit)
+	assert.Nil(t, err)
+
+	rootCmd := initializeVerifyBuildCmdOptions(t, c)
+	_, err = test.ExecuteCommand(rootCmd, cmdVerifyBuild, "-n", "default", "kit-1")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is not ready")
+}
+
+func TestNewVerificationKit(t *testing.T) {
+	kit := v1.NewIntegrationKit("default", "kit-1")
+	kit.Labels = map[string]string{
+		v1.IntegrationKitTypeLabel:   v1.IntegrationKitTypePlatform,
+		v1.IntegrationKitLayoutLabel: v1.IntegrationKitLayoutFastJar,
+	}
+	kit.Spec.Dependencies = []string{"camel:timer"}
+	kit.Spec.Traits = map[string]v1.TraitSpec{
+		"builder": {
+			Configuration: v1.TraitConfiguration{
+				RawMessage: []byte(`{"verbose":true}`),
+			},
+		},
+	}
+
+	rebuilt, err := newVerificationKit(kit)
+	assert.Nil(t, err)
+	assert.Equal(t, "kit-1-verify-", rebuilt.GenerateName)
+	assert.Equal(t, v1.IntegrationKitTypeUser, rebuilt.Labels[v1.IntegrationKitTypeLabel])
+	assert.Equal(t, v1.IntegrationKitLayoutFastJar, rebuilt.Labels[v1.IntegrationKitLayoutLabel])
+	assert.Equal(t, []string{"camel:timer"}, rebuilt.Spec.Dependencies)
+	assert.JSONEq(t, `{"verbose":true,"incrementalImageBuild":false}`, string(rebuilt.Spec.Traits["builder"].Configuration.RawMessage))
+	// The original kit is left untouched
+	assert.JSONEq(t, `{"verbose":true}`, string(kit.Spec.Traits["builder"].Configuration.RawMessage))
+}
+
+func TestPrintBuildVerification(t *testing.T) {
+	recorded := v1.NewIntegrationKit("default", "kit-1")
+	recorded.Status.Digest = "vA1b2C3d4"
+	recorded.Status.Image = "registry:5000/default/camel-k-kit-1@sha256:1"
+	recorded.Status.Artifacts = []v1.Artifact{
+		{ID: "a.jar", Target: "dependencies/lib/main/a.jar", Checksum: "sha1:a"},
+		{ID: "b.jar", Target: "dependencies/lib/main/b.jar", Checksum: "sha1:b"},
+		{ID: "c.jar", Target: "dependencies/lib/main/c.jar", Checksum: "sha1:c"},
+	}
+
+	rebuilt := recorded.DeepCopy()
+	rebuilt.Status.Digest = "vE5f6G7h8"
+	rebuilt.Status.Image = "registry:5000/default/camel-k-kit-1-verify-x@sha256:1"
+	var out bytes.Buffer
+	assert.True(t, printBuildVerification(&out, recorded, rebuilt))
+	assert.Contains(t, out.String(), "Image digests are identical")
+	assert.Contains(t, out.String(), "3 identical, 0 differ")
+
+	rebuilt.Status.Image = "registry:5000/default/camel-k-kit-1-verify-x@sha256:2"
+	rebuilt.Status.Artifacts = []v1.Artifact{
+		{ID: "a.jar", Target: "dependencies/lib/main/a.jar", Checksum: "sha1:a"},
+		{ID: "b.jar", Target: "dependencies/lib/main/b.jar", Checksum: "sha1:x"},
+		{ID: "d.jar", Target: "dependencies/lib/main/d.jar", Checksum: "sha1:d"},
+	}
+	out.Reset()
+	assert.False(t, printBuildVerification(&out, recorded, rebuilt))
+	assert.Contains(t, out.String(), "Image digests differ")
+	assert.Contains(t, out.String(), "1 identical, 3 differ")
+	assert.Contains(t, out.String(), "~ dependencies/lib/main/b.jar\tsha1:b -> sha1:x")
+	assert.Contains(t, out.String(), "- dependencies/lib/main/c.jar")
+	assert.Contains(t, out.String(), "+ dependencies/lib/main/d.jar")
+}
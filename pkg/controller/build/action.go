@@ -19,6 +19,7 @@ package build
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/client-go/tools/record"
 
@@ -44,6 +45,13 @@ type Action interface {
 	Handle(ctx context.Context, build *v1.Build) (*v1.Build, error)
 }
 
+// requeuer is implemented by the actions that may require the build
+// to be reconciled again after a delay.
+type requeuer interface {
+	// returns the delay after which the build must be reconciled again, if any
+	RequeueAfter() time.Duration
+}
+
 type baseAction struct {
 	client   client.Client
 	L        log.Logger
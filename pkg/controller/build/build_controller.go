@@ -36,6 +36,7 @@ import (
 	"github.com/apache/camel-k/pkg/client"
 	camelevent "github.com/apache/camel-k/pkg/event"
 	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/buildcache"
 	"github.com/apache/camel-k/pkg/util/monitoring"
 )
 
@@ -46,16 +47,21 @@ func Add(mgr manager.Manager) error {
 	if err != nil {
 		return err
 	}
-	return add(mgr, newReconciler(mgr, c))
+	store, err := buildcache.NewStore(buildcache.Kind(), mgr.GetAPIReader(), c, platform.GetOperatorNamespace())
+	if err != nil {
+		return err
+	}
+	return add(mgr, newReconciler(mgr, c, store))
 }
 
-func newReconciler(mgr manager.Manager, c client.Client) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, c client.Client, store buildcache.Store) reconcile.Reconciler {
 	return monitoring.NewInstrumentedReconciler(
 		&reconcileBuild{
 			client:   c,
 			reader:   mgr.GetAPIReader(),
 			scheme:   mgr.GetScheme(),
 			recorder: mgr.GetEventRecorderFor("camel-k-build-controller"),
+			store:    store,
 		},
 		schema.GroupVersionKind{
 			Group:   v1.SchemeGroupVersion.Group,
@@ -102,6 +108,8 @@ type reconcileBuild struct {
 	reader   ctrl.Reader
 	scheme   *runtime.Scheme
 	recorder record.EventRecorder
+	// the store of the records of the build routines
+	store buildcache.Store
 }
 
 // Reconcile reads that state of the cluster for a Build object and makes changes based on the state read
@@ -128,8 +136,9 @@ func (r *reconcileBuild) Reconcile(ctx context.Context, request reconcile.Reques
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// The record of a routine build deleted while the operator was stopped is removed.
 			// Return and don't requeue
-			return reconcile.Result{}, nil
+			return reconcile.Result{}, r.store.Delete(ctx, buildcache.Key(request.Namespace, request.Name))
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
@@ -159,7 +168,7 @@ func (r *reconcileBuild) Reconcile(ctx context.Context, request reconcile.Reques
 		actions = []Action{
 			newInitializeRoutineAction(),
 			newScheduleAction(r.reader),
-			newMonitorRoutineAction(),
+			newMonitorRoutineAction(r.store),
 			newErrorRecoveryAction(),
 			newErrorAction(),
 		}
@@ -200,6 +209,9 @@ func (r *reconcileBuild) Reconcile(ctx context.Context, request reconcile.Reques
 			// is always at its latest state
 			camelevent.NotifyBuildUpdated(ctx, r.client, r.recorder, &instance, newTarget)
 
+			if rq, ok := a.(requeuer); ok && rq.RequeueAfter() > 0 {
+				return reconcile.Result{RequeueAfter: rq.RequeueAfter()}, nil
+			}
 			break
 		}
 	}
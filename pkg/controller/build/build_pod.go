@@ -20,7 +20,6 @@ package build
 import (
 	"context"
 	"fmt"
-	"os"
 	"path"
 	"strconv"
 	"strings"
@@ -37,11 +36,13 @@ import (
 	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 const (
 	builderDir    = "/builder"
 	builderVolume = "camel-k-builder"
+	exportVolume  = "camel-k-export"
 )
 
 type registryConfigMap struct {
@@ -130,6 +131,8 @@ func newBuildPod(ctx context.Context, c ctrl.Reader, build *v1.Build) (*corev1.P
 		Spec: corev1.PodSpec{
 			ServiceAccountName: platform.BuilderServiceAccount,
 			RestartPolicy:      corev1.RestartPolicyNever,
+			NodeSelector:       build.Spec.NodeSelector,
+			Tolerations:        build.Spec.Tolerations,
 		},
 	}
 
@@ -138,7 +141,7 @@ func newBuildPod(ctx context.Context, c ctrl.Reader, build *v1.Build) (*corev1.P
 	for _, task := range build.Spec.Tasks {
 		switch {
 		case task.Builder != nil:
-			addBuildTaskToPod(build, task.Builder.Name, pod)
+			addBuilderTaskToPod(build, task.Builder, pod)
 		case task.Buildah != nil:
 			err := addBuildahTaskToPod(ctx, c, build, task.Buildah, pod)
 			if err != nil {
@@ -153,6 +156,8 @@ func newBuildPod(ctx context.Context, c ctrl.Reader, build *v1.Build) (*corev1.P
 			addBuildTaskToPod(build, task.S2i.Name, pod)
 		case task.Spectrum != nil:
 			addBuildTaskToPod(build, task.Spectrum.Name, pod)
+		case task.Export != nil:
+			addExportTaskToPod(build, task.Export, pod)
 		}
 	}
 
@@ -201,6 +206,24 @@ func buildPodName(build *v1.Build) string {
 }
 
 func addBuildTaskToPod(build *v1.Build, taskName string, pod *corev1.Pod) {
+	addContainerToPod(build, newBuildTaskContainer(build, taskName, pod), pod)
+}
+
+func addBuilderTaskToPod(build *v1.Build, task *v1.BuilderTask, pod *corev1.Pod) {
+	container := newBuildTaskContainer(build, task.Name, pod)
+	container.Resources = task.ContainerResources
+	if platform.OperatorUploadURL != "" && upload.HasDependency(task.Dependencies) {
+		// The files uploaded to the operator are fetched from its API
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  upload.URLEnvVariable,
+			Value: platform.OperatorUploadURL,
+		})
+	}
+
+	addContainerToPod(build, container, pod)
+}
+
+func newBuildTaskContainer(build *v1.Build, taskName string, pod *corev1.Pod) corev1.Container {
 	if !hasBuilderVolume(pod) {
 		// Add the EmptyDir volume used to share the build state across tasks
 		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
@@ -231,9 +254,28 @@ func addBuildTaskToPod(build *v1.Build, taskName string, pod *corev1.Pod) {
 			taskName,
 		},
 		WorkingDir: path.Join(builderDir, build.Name),
-		Env:        proxyFromEnvironment(),
+		Env:        platform.GetProxyEnvVars(build.Spec.Proxy),
 	}
 
+	return container
+}
+
+func addExportTaskToPod(build *v1.Build, task *v1.ExportTask, pod *corev1.Pod) {
+	container := newBuildTaskContainer(build, task.Name, pod)
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: exportVolume,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: task.PersistentVolumeClaim,
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      exportVolume,
+		MountPath: builder.ImageExportDir,
+	})
+
 	addContainerToPod(build, container, pod)
 }
 
@@ -314,7 +356,7 @@ func addBuildahTaskToPod(ctx context.Context, c ctrl.Reader, build *v1.Build, ta
 		push = append(push[:2], append([]string{"--tls-verify=false"}, push[2:]...)...)
 	}
 
-	env = append(env, proxyFromEnvironment()...)
+	env = append(env, platform.GetProxyEnvVars(build.Spec.Proxy)...)
 
 	args := []string{
 		strings.Join(bud, " "),
@@ -378,7 +420,7 @@ func addKanikoTaskToPod(ctx context.Context, c ctrl.Reader, build *v1.Build, tas
 		args = append(args, "--insecure-pull")
 	}
 
-	env = append(env, proxyFromEnvironment()...)
+	env = append(env, platform.GetProxyEnvVars(build.Spec.Proxy)...)
 
 	if cache {
 		// Co-locate with the Kaniko warmer pod for sharing the host path volume as the current
@@ -557,30 +599,3 @@ func addRegistrySecret(name string, secret registrySecret, volumes *[]corev1.Vol
 		})
 	}
 }
-
-func proxyFromEnvironment() []corev1.EnvVar {
-	var envVars []corev1.EnvVar
-
-	if httpProxy, ok := os.LookupEnv("HTTP_PROXY"); ok {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "HTTP_PROXY",
-			Value: httpProxy,
-		})
-	}
-
-	if httpsProxy, ok := os.LookupEnv("HTTPS_PROXY"); ok {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "HTTPS_PROXY",
-			Value: httpsProxy,
-		})
-	}
-
-	if noProxy, ok := os.LookupEnv("NO_PROXY"); ok {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "NO_PROXY",
-			Value: noProxy,
-		})
-	}
-
-	return envVars
-}
@@ -19,8 +19,12 @@ package build
 
 import (
 	"context"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
 )
 
 func newErrorAction() Action {
@@ -29,6 +33,7 @@ func newErrorAction() Action {
 
 type errorAction struct {
 	baseAction
+	requeueAfter time.Duration
 }
 
 // Name returns a common name of the action.
@@ -41,7 +46,34 @@ func (action *errorAction) CanHandle(build *v1.Build) bool {
 	return build.Status.Phase == v1.BuildPhaseError
 }
 
-// Handle handles the builds.
+// Handle deletes the build once the failed build retention of the platform is elapsed.
 func (action *errorAction) Handle(ctx context.Context, build *v1.Build) (*v1.Build, error) {
+	pl, err := platform.GetForResource(ctx, action.client, build)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	if pl == nil || pl.Status.Cleanup.FailedBuildRetention == nil {
+		return nil, nil
+	}
+
+	expiration := build.CreationTimestamp.Add(pl.Status.Cleanup.FailedBuildRetention.Duration)
+	if expiresIn := time.Until(expiration); expiresIn > 0 {
+		action.requeueAfter = expiresIn
+		return nil, nil
+	}
+
+	action.L.Info("Deleting failed build once its retention elapsed")
+	if err := action.client.Delete(ctx, build); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	buildCleanup.Inc()
+
 	return nil, nil
 }
+
+func (action *errorAction) RequeueAfter() time.Duration {
+	return action.requeueAfter
+}
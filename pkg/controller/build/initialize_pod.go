@@ -19,12 +19,16 @@ package build
 
 import (
 	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/pkg/errors"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
 )
 
 func newInitializePodAction(reader ctrl.Reader) Action {
@@ -50,6 +54,18 @@ func (action *initializePodAction) CanHandle(build *v1.Build) bool {
 
 // Handle handles the builds.
 func (action *initializePodAction) Handle(ctx context.Context, build *v1.Build) (*v1.Build, error) {
+	// The builder Pods are only allowed into the build farm namespace, when the platform defines one
+	pl, err := platform.GetForResource(ctx, action.reader, build)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	if pl != nil && pl.Status.Build.BuildFarm != nil && pl.Status.Build.BuildFarm.Namespace != build.Namespace {
+		build.Status.Phase = v1.BuildPhaseError
+		build.Status.Error = fmt.Sprintf("the pod build strategy is only allowed into the build farm namespace %s",
+			pl.Status.Build.BuildFarm.Namespace)
+		return build, nil
+	}
+
 	if err := deleteBuilderPod(ctx, action.client, build); err != nil {
 		return nil, errors.Wrap(err, "cannot delete build pod")
 	}
@@ -82,11 +82,18 @@ var (
 			buildTypeLabel,
 		},
 	)
+
+	buildCleanup = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "camel_k_build_cleanup_total",
+			Help: "Camel K failed builds deleted once their retention elapsed",
+		},
+	)
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(buildDuration, buildRecovery, queueDuration)
+	metrics.Registry.MustRegister(buildDuration, buildRecovery, queueDuration, buildCleanup)
 }
 
 func observeBuildQueueDuration(build *v1.Build, creator *corev1.ObjectReference) {
@@ -35,6 +35,7 @@ import (
 	"github.com/pkg/errors"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/builder"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 )
 
@@ -91,6 +92,8 @@ func (action *monitorPodAction) Handle(ctx context.Context, build *v1.Build) (*v
 		}
 	}
 
+	build.Status.Pod = pod.Name
+
 	switch pod.Status.Phase {
 
 	case corev1.PodPending, corev1.PodRunning:
@@ -126,6 +129,10 @@ func (action *monitorPodAction) Handle(ctx context.Context, build *v1.Build) (*v
 		// Account for the Build metrics
 		observeBuildResult(build, build.Status.Phase, buildCreator, duration)
 
+		if err = action.recordStages(ctx, build, pod); err != nil {
+			return nil, err
+		}
+
 		for _, task := range build.Spec.Tasks {
 			if t := task.Buildah; t != nil {
 				build.Status.Image = t.Image
@@ -137,9 +144,12 @@ func (action *monitorPodAction) Handle(ctx context.Context, build *v1.Build) (*v
 				break
 			}
 		}
-		// Reconcile image digest from build container status if available
-		for _, container := range pod.Status.ContainerStatuses {
-			if container.Name == "buildah" {
+		// Reconcile image digest from build container status if available.
+		// The build container runs as an init container when followed by other tasks, e.g., the image export.
+		containers := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+		containers = append(containers, pod.Status.ContainerStatuses...)
+		for _, container := range containers {
+			if container.Name == "buildah" && container.State.Terminated != nil {
 				build.Status.Digest = container.State.Terminated.Message
 
 				break
@@ -171,11 +181,54 @@ func (action *monitorPodAction) Handle(ctx context.Context, build *v1.Build) (*v
 		buildCreator := kubernetes.GetCamelCreator(build)
 		// Account for the Build metrics
 		observeBuildResult(build, build.Status.Phase, buildCreator, duration)
+
+		if err = action.recordStages(ctx, build, pod); err != nil {
+			return nil, err
+		}
 	}
 
 	return build, nil
 }
 
+// recordStages completes the stages, recorded by the builder task, with the ones of the tasks executed
+// by the other containers of the pod. The builder task patches the Build status from within the pod,
+// so the Build is read from the API server, as the cached one may not reflect the patch yet.
+func (action *monitorPodAction) recordStages(ctx context.Context, build *v1.Build, pod *corev1.Pod) error {
+	latest := v1.Build{}
+	if err := action.reader.Get(ctx, ctrl.ObjectKeyFromObject(build), &latest); err != nil {
+		return err
+	}
+
+	containers := make(map[string]corev1.ContainerStatus)
+	for _, container := range pod.Status.InitContainerStatuses {
+		containers[container.Name] = container
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		containers[container.Name] = container
+	}
+
+	stages := make([]v1.BuildStage, 0, len(latest.Status.Stages)+len(build.Spec.Tasks))
+	for _, task := range build.Spec.Tasks {
+		name := builder.TaskName(task)
+		if task.Builder != nil {
+			for _, stage := range latest.Status.Stages {
+				if stage.Task == name {
+					stages = append(stages, stage)
+				}
+			}
+			continue
+		}
+		if t := containers[name].State.Terminated; t != nil {
+			if stage := builder.NewTaskStage(task, t.StartedAt, t.FinishedAt); stage != nil {
+				stages = append(stages, *stage)
+			}
+		}
+	}
+	build.Status.Stages = stages
+
+	return nil
+}
+
 func (action *monitorPodAction) isPodScheduled(pod *corev1.Pod) bool {
 	for _, condition := range pod.Status.Conditions {
 		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
@@ -34,18 +34,24 @@ import (
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/builder"
 	"github.com/apache/camel-k/pkg/event"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/buildcache"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/patch"
 )
 
 var routines sync.Map
 
-func newMonitorRoutineAction() Action {
-	return &monitorRoutineAction{}
+func newMonitorRoutineAction(store buildcache.Store) Action {
+	return &monitorRoutineAction{
+		store: store,
+	}
 }
 
 type monitorRoutineAction struct {
 	baseAction
+	// store persists the records of the build routines, so that the builds interrupted by an operator restart are resumed
+	store buildcache.Store
 }
 
 // Name returns a common name of the action.
@@ -70,6 +76,9 @@ func (action *monitorRoutineAction) Handle(ctx context.Context, build *v1.Build)
 			build.Status.Error = "Build routine exists"
 			return build, nil
 		}
+		if err := action.claim(ctx, build); err != nil {
+			return nil, err
+		}
 		status := v1.BuildStatus{Phase: v1.BuildPhaseRunning}
 		if err := action.updateBuildStatus(ctx, build, status); err != nil {
 			return nil, err
@@ -82,6 +91,24 @@ func (action *monitorRoutineAction) Handle(ctx context.Context, build *v1.Build)
 
 	case v1.BuildPhaseRunning:
 		if _, ok := routines.Load(build.Name); !ok {
+			record, err := action.store.Get(ctx, buildcache.Key(build.Namespace, build.Name))
+			if err != nil {
+				return nil, err
+			}
+			if record != nil && record.Holder != operatorHolder() {
+				// The build has been started by another operator Pod, that has stopped since,
+				// so that it's run again, within its initial timeout.
+				action.L.Infof("Resuming build interrupted by the restart of operator %s", record.Holder)
+				if err := action.claim(ctx, build); err != nil {
+					return nil, err
+				}
+				routines.Store(build.Name, true)
+
+				// nolint: contextcheck
+				go action.runBuild(build)
+
+				return nil, nil
+			}
 			// Recover the build if the routine missing. This can happen when the operator
 			// stops abruptly and restarts or the build status update fails.
 			build.Status.Phase = v1.BuildPhaseFailed
@@ -93,14 +120,38 @@ func (action *monitorRoutineAction) Handle(ctx context.Context, build *v1.Build)
 	return nil, nil
 }
 
+// claim records that the build routine is run by the current operator.
+func (action *monitorRoutineAction) claim(ctx context.Context, build *v1.Build) error {
+	return action.store.Put(ctx, buildcache.Key(build.Namespace, build.Name), buildcache.Record{
+		Holder:    operatorHolder(),
+		StartedAt: metav1.Now(),
+	})
+}
+
+// operatorHolder returns the identity of the current operator in the build records.
+func operatorHolder() string {
+	if name := platform.GetOperatorPodName(); name != "" {
+		return name
+	}
+	// The operator runs out of cluster
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
 func (action *monitorRoutineAction) runBuild(build *v1.Build) {
 	defer routines.Delete(build.Name)
+	defer func() {
+		if err := action.store.Delete(context.Background(), buildcache.Key(build.Namespace, build.Name)); err != nil {
+			action.L.Error(err, "Cannot delete the build record")
+		}
+	}()
 
 	ctx := context.Background()
 	ctxWithTimeout, cancel := context.WithDeadline(ctx, build.Status.StartedAt.Add(build.Spec.Timeout.Duration))
 	defer cancel()
 
 	status := v1.BuildStatus{}
+	stages := make([]v1.BuildStage, 0)
 	buildDir := ""
 	Builder := builder.New(action.client)
 
@@ -149,7 +200,13 @@ tasks:
 			}
 
 			// Execute the task
+			startedAt := metav1.Now()
 			status = Builder.Build(build).Task(task).Do(ctxWithTimeout)
+			stages = append(stages, status.Stages...)
+			if stage := builder.NewTaskStage(task, startedAt, metav1.Now()); stage != nil {
+				stages = append(stages, *stage)
+			}
+			status.Stages = stages
 
 			lastTask := i == len(build.Spec.Tasks)-1
 			taskFailed := status.Phase == v1.BuildPhaseFailed ||
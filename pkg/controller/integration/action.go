@@ -19,6 +19,7 @@ package integration
 
 import (
 	"context"
+	"time"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/client"
@@ -40,6 +41,13 @@ type Action interface {
 	Handle(ctx context.Context, integration *v1.Integration) (*v1.Integration, error)
 }
 
+// requeuer is implemented by the actions that may require the integration
+// to be reconciled again after a delay.
+type requeuer interface {
+	// returns the delay after which the integration must be reconciled again, if any
+	RequeueAfter() time.Duration
+}
+
 type baseAction struct {
 	client client.Client
 	L      log.Logger
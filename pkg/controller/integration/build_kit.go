@@ -22,6 +22,8 @@ import (
 
 	"github.com/pkg/errors"
 
+	corev1 "k8s.io/api/core/v1"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/trait"
 	"github.com/apache/camel-k/pkg/util/digest"
@@ -70,7 +72,8 @@ func (action *buildKitAction) Handle(ctx context.Context, integration *v1.Integr
 			return nil, errors.Wrapf(err, "unable to find integration kit %s/%s, %s", integration.Status.IntegrationKit.Namespace, integration.Status.IntegrationKit.Name, err)
 		}
 
-		if kit.Labels[v1.IntegrationKitTypeLabel] == v1.IntegrationKitTypePlatform {
+		// The kit the Integration is pinned to is validated once ready, rather than replaced
+		if kit.Labels[v1.IntegrationKitTypeLabel] == v1.IntegrationKitTypePlatform && !isIntegrationKitPinned(integration) {
 			match, err := integrationMatches(integration, kit)
 			if err != nil {
 				return nil, errors.Wrapf(err, "unable to match any integration kit with integration %s/%s", integration.Namespace, integration.Name)
@@ -95,6 +98,17 @@ func (action *buildKitAction) Handle(ctx context.Context, integration *v1.Integr
 		}
 
 		if kit.Status.Phase == v1.IntegrationKitPhaseReady {
+			if isIntegrationKitPinned(integration) {
+				if mismatch := pinnedKitMismatch(integration, kit); mismatch != "" {
+					// The Integration waits for being updated
+					integration.Status.Phase = v1.IntegrationPhaseError
+					integration.SetIntegrationKit(nil)
+					integration.Status.SetCondition(v1.IntegrationConditionKitAvailable, corev1.ConditionFalse,
+						v1.IntegrationConditionKitAvailableReason, mismatch)
+					setReadyConditionError(integration, mismatch)
+					return integration, nil
+				}
+			}
 			integration.Status.Phase = v1.IntegrationPhaseDeploying
 			integration.SetIntegrationKit(kit)
 			return integration, nil
@@ -19,13 +19,18 @@ package integration
 
 import (
 	"context"
+	"os"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/digest"
 	"github.com/apache/camel-k/pkg/util/log"
 	"github.com/apache/camel-k/pkg/util/test"
 )
@@ -234,6 +239,99 @@ func TestLookupKitForIntegration_DiscardKitsWithIncompatibleTraits(t *testing.T)
 	assert.Equal(t, "my-kit-3", kits[0].Name)
 }
 
+func TestLookupKitForIntegration_TenantKitsIsolation(t *testing.T) {
+	os.Setenv("NAMESPACE", "operator")
+	defer os.Unsetenv("NAMESPACE")
+
+	kit := func(namespace string, name string) *v1.IntegrationKit {
+		return &v1.IntegrationKit{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1.SchemeGroupVersion.String(),
+				Kind:       v1.IntegrationKitKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					v1.IntegrationKitTypeLabel: v1.IntegrationKitTypePlatform,
+				},
+			},
+			Spec: v1.IntegrationKitSpec{
+				Dependencies: []string{
+					"camel-core",
+				},
+			},
+			Status: v1.IntegrationKitStatus{
+				Phase: v1.IntegrationKitPhaseReady,
+			},
+		}
+	}
+	integration := &v1.Integration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.IntegrationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "tenant",
+			Name:      "my-integration",
+		},
+		Status: v1.IntegrationStatus{
+			Dependencies: []string{
+				"camel-core",
+			},
+		},
+	}
+
+	for _, sharing := range []bool{false, true} {
+		c, err := test.NewFakeClient(
+			&v1.IntegrationPlatform{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: v1.SchemeGroupVersion.String(),
+					Kind:       v1.IntegrationPlatformKind,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "operator",
+					Name:      "camel-k",
+				},
+				Status: v1.IntegrationPlatformStatus{
+					Phase: v1.IntegrationPlatformPhaseReady,
+				},
+			},
+			&corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "ConfigMap",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "tenant",
+					Name:      platform.TenantConfigMapName,
+				},
+				Data: map[string]string{
+					"registry.address": "registry.tenant.svc",
+					"kit.sharing":      strconv.FormatBool(sharing),
+				},
+			},
+			kit("operator", "shared-kit"),
+			kit("tenant", "tenant-kit"),
+			kit("other", "other-kit"),
+		)
+		assert.Nil(t, err)
+
+		kits, err := lookupKitsForIntegration(context.TODO(), c, integration)
+		assert.Nil(t, err)
+
+		names := make([]string, 0, len(kits))
+		for _, k := range kits {
+			names = append(names, k.Name)
+		}
+		if sharing {
+			assert.ElementsMatch(t, []string{"tenant-kit", "shared-kit"}, names)
+		} else {
+			assert.ElementsMatch(t, []string{"tenant-kit"}, names)
+		}
+	}
+}
+
 func TestHasMatchingTraits_KitNoTraitShouldNotBePicked(t *testing.T) {
 	integration := &v1.Integration{
 		TypeMeta: metav1.TypeMeta{
@@ -325,3 +423,155 @@ func TestHasMatchingTraits_KitSameTraitShouldBePicked(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, ok)
 }
+
+func TestIntegrationMatches_KitWithMavenCredentialsOfAnotherNamespace(t *testing.T) {
+	integration := &v1.Integration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "my-integration",
+		},
+		Status: v1.IntegrationStatus{
+			Dependencies: []string{
+				"camel-core",
+			},
+		},
+	}
+
+	kit := &v1.IntegrationKit{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "camel-k",
+			Name:      "my-kit",
+			Labels: map[string]string{
+				v1.IntegrationKitTypeLabel:                 v1.IntegrationKitTypePlatform,
+				v1.IntegrationKitCredentialsNamespaceLabel: "other",
+			},
+		},
+		Spec: v1.IntegrationKitSpec{
+			Dependencies: []string{
+				"camel-core",
+			},
+		},
+	}
+
+	ok, err := integrationMatches(integration, kit)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	kit.Labels[v1.IntegrationKitCredentialsNamespaceLabel] = "ns"
+	ok, err = integrationMatches(integration, kit)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestFindIntegrationKitByDigest(t *testing.T) {
+	newKit := func(name string, image string, phase v1.IntegrationKitPhase) *v1.IntegrationKit {
+		kit := v1.NewIntegrationKit("shared", name)
+		// The kit digest is the hash of its spec, not of its image
+		kit.Status.Digest = "vA1b2C3d4"
+		kit.Status.Image = image
+		kit.Status.Phase = phase
+		return kit
+	}
+	c, err := test.NewFakeClient(
+		newKit("my-kit-1", "registry:5000/shared/camel-k-kit-1@sha256:1", v1.IntegrationKitPhaseBuildRunning),
+		newKit("my-kit-2", "registry:5000/shared/camel-k-kit-1@sha256:1", v1.IntegrationKitPhaseReady),
+		newKit("my-kit-3", "registry:5000/shared/camel-k-kit-3@sha256:2", v1.IntegrationKitPhaseReady),
+		newKit("my-kit-4", "registry:5000/shared/camel-k-kit-4:sha256", v1.IntegrationKitPhaseReady),
+	)
+	assert.Nil(t, err)
+
+	kit, err := findIntegrationKitByDigest(context.TODO(), c, "shared", "sha256:1")
+	assert.Nil(t, err)
+	assert.NotNil(t, kit)
+	assert.Equal(t, "my-kit-2", kit.Name)
+
+	kit, err = findIntegrationKitByDigest(context.TODO(), c, "shared", "sha256:2")
+	assert.Nil(t, err)
+	assert.NotNil(t, kit)
+	assert.Equal(t, "my-kit-3", kit.Name)
+
+	// The spec digest and the image tag are not matched
+	kit, err = findIntegrationKitByDigest(context.TODO(), c, "shared", "vA1b2C3d4")
+	assert.Nil(t, err)
+	assert.Nil(t, kit)
+
+	kit, err = findIntegrationKitByDigest(context.TODO(), c, "shared", "sha256")
+	assert.Nil(t, err)
+	assert.Nil(t, kit)
+
+	kit, err = findIntegrationKitByDigest(context.TODO(), c, "ns", "sha256:1")
+	assert.Nil(t, err)
+	assert.Nil(t, kit)
+}
+
+func TestPinnedKitMismatch(t *testing.T) {
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Spec.IntegrationKitDigest = "sha256:1"
+	integration.Status.RuntimeVersion = "1.12.0"
+	integration.Status.Dependencies = []string{"camel:core", "camel:irc"}
+
+	kit := v1.NewIntegrationKit("shared", "my-kit")
+	kit.Labels = map[string]string{v1.IntegrationKitTypeLabel: v1.IntegrationKitTypePlatform}
+	kit.Spec.Dependencies = []string{"camel:core", "camel:irc", "camel:log"}
+	kit.Status.Digest = "vA1b2C3d4"
+	kit.Status.Image = "registry:5000/shared/camel-k-kit@sha256:1"
+	kit.Status.RuntimeVersion = "1.12.0"
+	assert.Equal(t, "", pinnedKitMismatch(&integration, kit))
+
+	kit.Status.Image = "registry:5000/shared/camel-k-kit@sha256:2"
+	assert.Equal(t, "integration kit shared/my-kit image registry:5000/shared/camel-k-kit@sha256:2 does not match digest sha256:1",
+		pinnedKitMismatch(&integration, kit))
+
+	kit.Status.Image = "registry:5000/shared/camel-k-kit:1"
+	assert.Equal(t, "integration kit shared/my-kit image registry:5000/shared/camel-k-kit:1 does not match digest sha256:1",
+		pinnedKitMismatch(&integration, kit))
+
+	kit.Status.Image = "registry:5000/shared/camel-k-kit@sha256:1"
+	kit.Status.RuntimeVersion = "1.11.0"
+	assert.Equal(t, "integration kit shared/my-kit runtime version 1.11.0 does not match 1.12.0", pinnedKitMismatch(&integration, kit))
+
+	kit.Status.RuntimeVersion = "1.12.0"
+	kit.Labels[v1.IntegrationKitCredentialsNamespaceLabel] = "other"
+	assert.Equal(t, "integration kit shared/my-kit has been built with the Maven credentials of namespace other", pinnedKitMismatch(&integration, kit))
+
+	delete(kit.Labels, v1.IntegrationKitCredentialsNamespaceLabel)
+	kit.Spec.Dependencies = []string{"camel:core"}
+	assert.Equal(t, "integration kit shared/my-kit misses the dependencies camel:irc", pinnedKitMismatch(&integration, kit))
+
+	// The dependencies of external kits are not validated
+	kit.Labels[v1.IntegrationKitTypeLabel] = v1.IntegrationKitTypeExternal
+	assert.Equal(t, "", pinnedKitMismatch(&integration, kit))
+}
+
+func TestBuildKitReportsPinnedKitMismatch(t *testing.T) {
+	kit := v1.NewIntegrationKit("shared", "my-kit")
+	kit.Labels = map[string]string{v1.IntegrationKitTypeLabel: v1.IntegrationKitTypePlatform}
+	kit.Spec.Dependencies = []string{"camel:core"}
+	kit.Status.Phase = v1.IntegrationKitPhaseReady
+	kit.Status.Digest = "vA1b2C3d4"
+	kit.Status.Image = "registry:5000/shared/camel-k-kit@sha256:1"
+
+	c, err := test.NewFakeClient(kit)
+	assert.Nil(t, err)
+
+	a := buildKitAction{}
+	a.InjectLogger(log.Log)
+	a.InjectClient(c)
+
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Spec.IntegrationKit = &corev1.ObjectReference{Namespace: "shared"}
+	integration.Spec.IntegrationKitDigest = "sha256:1"
+	integration.Status.Phase = v1.IntegrationPhaseBuildingKit
+	integration.Status.Dependencies = []string{"camel:core", "camel:irc"}
+	integration.Status.IntegrationKit = &corev1.ObjectReference{Namespace: "shared", Name: "my-kit"}
+	integration.Status.Digest, err = digest.ComputeForIntegration(&integration)
+	assert.Nil(t, err)
+
+	handled, err := a.Handle(context.TODO(), &integration)
+	assert.Nil(t, err)
+	assert.NotNil(t, handled)
+	assert.Equal(t, v1.IntegrationPhaseError, handled.Status.Phase)
+	assert.Nil(t, handled.Status.IntegrationKit)
+	assert.Equal(t, "integration kit shared/my-kit misses the dependencies camel:irc",
+		handled.Status.GetCondition(v1.IntegrationConditionKitAvailable).Message)
+}
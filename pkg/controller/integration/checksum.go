@@ -0,0 +1,136 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/resource"
+)
+
+const (
+	// mountedConfigMapsIndex indexes the Integrations by the names of the configmaps they mount.
+	mountedConfigMapsIndex = "spec.traits.mount.configmaps"
+	// mountedSecretsIndex indexes the Integrations by the names of the secrets they mount.
+	mountedSecretsIndex = "spec.traits.mount.secrets"
+)
+
+// indexMountedResources returns the function indexing the Integrations by the configmaps or secrets they mount,
+// so that the Integrations mounting a given resource can be retrieved from the cache without listing the namespace.
+func indexMountedResources(storageType resource.StorageType) ctrl.IndexerFunc {
+	return func(obj ctrl.Object) []string {
+		integration, ok := obj.(*v1.Integration)
+		if !ok {
+			return nil
+		}
+		return mountedResources(integration, storageType)
+	}
+}
+
+// integrationsMounting returns the requests for the Integrations mounting the given configmap or secret,
+// so that their Pods checksum is updated when its content changes.
+func integrationsMounting(c client.Client, storageType resource.StorageType, obj ctrl.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	index := mountedConfigMapsIndex
+	if storageType == resource.StorageTypeSecret {
+		index = mountedSecretsIndex
+	}
+
+	list := &v1.IntegrationList{}
+	if err := c.List(context.Background(), list, ctrl.InNamespace(obj.GetNamespace()), ctrl.MatchingFields{index: obj.GetName()}); err != nil {
+		log.Error(err, "Failed to list integrations")
+		return requests
+	}
+
+	for i := range list.Items {
+		integration := &list.Items[i]
+		if integration.Status.Phase != v1.IntegrationPhaseDeploying && integration.Status.Phase != v1.IntegrationPhaseRunning {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: integration.Namespace,
+				Name:      integration.Name,
+			},
+		})
+	}
+
+	return requests
+}
+
+// integrationIssuedCertificate returns the request for the Integration the given secret has been issued to by cert-manager,
+// so that its route is updated when the certificate is issued, or renewed.
+func integrationIssuedCertificate(obj ctrl.Object) []reconcile.Request {
+	name := obj.GetLabels()[v1.IntegrationLabel]
+	if name == "" {
+		return nil
+	}
+	if _, ok := obj.GetAnnotations()[trait.CertManagerCertificateNameAnnotation]; !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      name,
+			},
+		},
+	}
+}
+
+// mountedResources returns the names of the configmaps or secrets the Integration mounts, as configs or as resources.
+func mountedResources(integration *v1.Integration, storageType resource.StorageType) []string {
+	spec, ok := integration.Spec.Traits["mount"]
+	if !ok {
+		return nil
+	}
+
+	var mount struct {
+		Configs   []string `json:"configs"`
+		Resources []string `json:"resources"`
+	}
+	if err := json.Unmarshal(spec.Configuration.RawMessage, &mount); err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, c := range mount.Configs {
+		if conf, err := resource.ParseConfig(c); err == nil && conf.StorageType() == storageType {
+			names = append(names, conf.Name())
+		}
+	}
+	for _, r := range mount.Resources {
+		if conf, err := resource.ParseResource(r); err == nil && conf.StorageType() == storageType {
+			names = append(names, conf.Name())
+		}
+	}
+
+	return names
+}
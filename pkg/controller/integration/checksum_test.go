@@ -0,0 +1,128 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/resource"
+)
+
+func TestMountedResources(t *testing.T) {
+	it := v1.NewIntegration("ns", "it")
+	it.Spec.Traits = map[string]v1.TraitSpec{
+		"mount": {
+			Configuration: v1.TraitConfiguration{
+				RawMessage: []byte(`{"configs":["configmap:cm1","secret:sec1/key"],"resources":["configmap:cm2@/etc/cm2","secret:sec2"]}`),
+			},
+		},
+	}
+
+	assert.ElementsMatch(t, []string{"cm1", "cm2"}, mountedResources(&it, resource.StorageTypeConfigmap))
+	assert.ElementsMatch(t, []string{"sec1", "sec2"}, mountedResources(&it, resource.StorageTypeSecret))
+
+	assert.Empty(t, indexMountedResources(resource.StorageTypeSecret)(&corev1.Secret{}))
+	assert.Empty(t, mountedResources(&v1.Integration{}, resource.StorageTypeConfigmap))
+}
+
+func TestIntegrationIssuedCertificate(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "it-tls",
+			Labels: map[string]string{
+				v1.IntegrationLabel: "it",
+			},
+			Annotations: map[string]string{
+				trait.CertManagerCertificateNameAnnotation: "it-tls",
+			},
+		},
+	}
+
+	requests := integrationIssuedCertificate(secret)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "ns", requests[0].Namespace)
+	assert.Equal(t, "it", requests[0].Name)
+
+	secret.Annotations = nil
+	assert.Empty(t, integrationIssuedCertificate(secret))
+	assert.Empty(t, integrationIssuedCertificate(&corev1.Secret{}))
+}
+
+func TestNotFrequentlyUpdatedSecretPredicate(t *testing.T) {
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "default-token-x2x4k"},
+		Type:       corev1.SecretTypeServiceAccountToken,
+	}
+	release := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sh.helm.release.v1.camel-k.v1"},
+		Type:       helmReleaseSecretType,
+	}
+	user := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-secret"},
+		Type:       corev1.SecretTypeOpaque,
+	}
+
+	p := NotFrequentlyUpdatedSecretPredicate{}
+	assert.False(t, p.Create(event.CreateEvent{Object: token}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: release, ObjectNew: release}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: token}))
+	assert.True(t, p.Create(event.CreateEvent{Object: user}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: user, ObjectNew: user}))
+	assert.True(t, p.Create(event.CreateEvent{Object: &corev1.ConfigMap{}}))
+}
+
+func TestNotControlledByIntegrationPredicate(t *testing.T) {
+	controller := true
+	owned := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "it-application-properties",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: v1.SchemeGroupVersion.String(),
+					Kind:       v1.IntegrationKind,
+					Name:       "it",
+					Controller: &controller,
+				},
+			},
+		},
+	}
+	user := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "my-cm",
+		},
+	}
+
+	p := NotControlledByIntegrationPredicate{}
+	assert.False(t, p.Create(event.CreateEvent{Object: owned}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: owned, ObjectNew: owned}))
+	assert.False(t, p.Delete(event.DeleteEvent{Object: owned}))
+	assert.True(t, p.Create(event.CreateEvent{Object: user}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: user, ObjectNew: user}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: user}))
+}
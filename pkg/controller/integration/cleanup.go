@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+)
+
+const (
+	cleanupReasonPreview   = "preview"
+	cleanupReasonTemporary = "temporary"
+)
+
+// getExpiration returns the time after which the Integration is deleted, along with the reason, either set
+// for a preview, or computed from the temporary Integration TTL of the platform. It returns nil otherwise.
+func getExpiration(ctx context.Context, c client.Client, integration *v1.Integration) (*time.Time, string, error) {
+	expiration, err := integration.GetPreviewExpiration()
+	if err != nil || expiration != nil {
+		return expiration, cleanupReasonPreview, err
+	}
+
+	if integration.Labels[v1.IntegrationTemporaryLabel] != "true" {
+		return nil, "", nil
+	}
+
+	pl, err := platform.GetForResource(ctx, c, integration)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, "", err
+	}
+	if pl == nil || pl.Status.Cleanup.TemporaryIntegrationTTL == nil {
+		return nil, "", nil
+	}
+
+	t := integration.CreationTimestamp.Add(pl.Status.Cleanup.TemporaryIntegrationTTL.Duration)
+	return &t, cleanupReasonTemporary, nil
+}
@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestGetExpiration(t *testing.T) {
+	created := metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	c, err := test.NewFakeClient(&v1.IntegrationPlatform{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.IntegrationPlatformKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "camel-k",
+		},
+		Status: v1.IntegrationPlatformStatus{
+			IntegrationPlatformSpec: v1.IntegrationPlatformSpec{
+				Cleanup: v1.IntegrationPlatformCleanupSpec{
+					TemporaryIntegrationTTL: &metav1.Duration{Duration: time.Hour},
+				},
+			},
+			Phase: v1.IntegrationPlatformPhaseReady,
+		},
+	})
+	assert.Nil(t, err)
+
+	it := v1.NewIntegration("ns", "it")
+	it.CreationTimestamp = created
+
+	expiration, _, err := getExpiration(context.TODO(), c, &it)
+	assert.Nil(t, err)
+	assert.Nil(t, expiration)
+
+	it.Labels = map[string]string{
+		v1.IntegrationTemporaryLabel: "true",
+	}
+	expiration, reason, err := getExpiration(context.TODO(), c, &it)
+	assert.Nil(t, err)
+	assert.Equal(t, cleanupReasonTemporary, reason)
+	assert.Equal(t, created.Add(time.Hour), *expiration)
+
+	it.Annotations = map[string]string{
+		v1.IntegrationPreviewExpirationAnnotation: "2022-01-02T00:00:00Z",
+	}
+	expiration, reason, err = getExpiration(context.TODO(), c, &it)
+	assert.Nil(t, err)
+	assert.Equal(t, cleanupReasonPreview, reason)
+	assert.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), *expiration)
+}
@@ -19,6 +19,8 @@ package integration
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,14 +52,32 @@ func (action *initializeAction) CanHandle(integration *v1.Integration) bool {
 
 // Handle handles the integrations.
 func (action *initializeAction) Handle(ctx context.Context, integration *v1.Integration) (*v1.Integration, error) {
+	blocked, err := action.scanSecrets(ctx, integration)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return integration, nil
+	}
+
 	if _, err := trait.Apply(ctx, action.client, integration, nil); err != nil {
+		var cerr *trait.ConfigurationError
+		if errors.As(err, &cerr) {
+			integration.Status.Phase = v1.IntegrationPhaseError
+			setReadyConditionError(integration, err.Error())
+			return integration, nil
+		}
 		return nil, err
 	}
 
 	if integration.Status.IntegrationKit == nil {
-		if integration.Spec.IntegrationKit != nil && integration.Spec.IntegrationKit.Name != "" {
-			kitNamespace := integration.Spec.IntegrationKit.Namespace
-			kitName := integration.Spec.IntegrationKit.Name
+		if ref := integration.Spec.IntegrationKit; ref != nil && ref.Name != "" || integration.Spec.IntegrationKitDigest != "" {
+			kitNamespace := ""
+			kitName := ""
+			if ref != nil {
+				kitNamespace = ref.Namespace
+				kitName = ref.Name
+			}
 
 			if kitNamespace == "" {
 				pl, err := platform.GetForResource(ctx, action.client, integration)
@@ -68,6 +88,20 @@ func (action *initializeAction) Handle(ctx context.Context, integration *v1.Inte
 					kitNamespace = pl.Namespace
 				}
 			}
+			if kitName == "" {
+				// The kit is pinned by its image digest
+				kit, err := findIntegrationKitByDigest(ctx, action.client, kitNamespace, integration.Spec.IntegrationKitDigest)
+				if err != nil {
+					return nil, err
+				}
+				if kit == nil {
+					integration.Status.Phase = v1.IntegrationPhaseError
+					setReadyConditionError(integration, fmt.Sprintf("no IntegrationKit found with image digest %s in namespace %s",
+						integration.Spec.IntegrationKitDigest, kitNamespace))
+					return integration, nil
+				}
+				kitName = kit.Name
+			}
 			kit := v1.NewIntegrationKit(kitNamespace, kitName)
 			integration.SetIntegrationKit(kit)
 		} else {
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -43,13 +45,16 @@ import (
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	"github.com/apache/camel-k/pkg/client"
 	camelevent "github.com/apache/camel-k/pkg/event"
 	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/digest"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/log"
 	"github.com/apache/camel-k/pkg/util/monitoring"
+	"github.com/apache/camel-k/pkg/util/resource"
 )
 
 func Add(mgr manager.Manager) error {
@@ -76,11 +81,27 @@ func newReconciler(mgr manager.Manager, c client.Client) reconcile.Reconciler {
 }
 
 func add(mgr manager.Manager, c client.Client, r reconcile.Reconciler) error {
+	// Index the Integrations by the configmaps and secrets they mount, to look them up
+	// upon content change without listing all the Integrations in the namespace
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1.Integration{}, mountedConfigMapsIndex,
+		indexMountedResources(resource.StorageTypeConfigmap)); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1.Integration{}, mountedSecretsIndex,
+		indexMountedResources(resource.StorageTypeSecret)); err != nil {
+		return err
+	}
+	// Index the Integrations by the Kamelets they use, to roll out the Kamelet updates
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1.Integration{}, kameletsIndex, indexKamelets); err != nil {
+		return err
+	}
+
 	b := builder.ControllerManagedBy(mgr).
 		Named("integration-controller").
 		// Watch for changes to primary resource Integration
 		For(&v1.Integration{}, builder.WithPredicates(
 			platform.FilteringFuncs{
+				AllowUnassigned: true,
 				UpdateFunc: func(e event.UpdateEvent) bool {
 					old, ok := e.ObjectOld.(*v1.Integration)
 					if !ok {
@@ -102,9 +123,10 @@ func add(mgr manager.Manager, c client.Client, r reconcile.Reconciler) error {
 					}
 					// Ignore updates to the integration status in which case metadata.Generation does not change,
 					// or except when the integration phase changes as it's used to transition from one phase
-					// to another.
+					// to another, or when the reconciliation is paused or resumed.
 					return old.Generation != it.Generation ||
-						old.Status.Phase != it.Status.Phase
+						old.Status.Phase != it.Status.Phase ||
+						old.Annotations[v1.ReconcileAnnotation] != it.Annotations[v1.ReconcileAnnotation]
 				},
 				DeleteFunc: func(e event.DeleteEvent) bool {
 					// Evaluates to false if the object has been confirmed deleted
@@ -205,8 +227,12 @@ func add(mgr manager.Manager, c client.Client, r reconcile.Reconciler) error {
 			})).
 		// Watch for the owned Deployments
 		Owns(&appsv1.Deployment{}, builder.WithPredicates(StatusChangedPredicate{})).
+		// Watch for the owned StatefulSets
+		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(StatusChangedPredicate{})).
 		// Watch for the owned CronJobs
 		Owns(&batchv1beta1.CronJob{}, builder.WithPredicates(StatusChangedPredicate{})).
+		// Watch for the owned Jobs
+		Owns(&batchv1.Job{}, builder.WithPredicates(StatusChangedPredicate{})).
 		// Watch for the Integration Pods
 		Watches(&source.Kind{Type: &corev1.Pod{}},
 			handler.EnqueueRequestsFromMapFunc(func(a ctrl.Object) []reconcile.Request {
@@ -223,7 +249,25 @@ func add(mgr manager.Manager, c client.Client, r reconcile.Reconciler) error {
 						},
 					},
 				}
-			}))
+			})).
+		// Watch for the content of the mounted configmaps and secrets, that may be part of the Pods checksum.
+		// The resources generated by the traits, and the updates not changing the content, are filtered out.
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(func(a ctrl.Object) []reconcile.Request {
+				return integrationsMounting(c, resource.StorageTypeConfigmap, a)
+			}),
+			builder.WithPredicates(NotControlledByIntegrationPredicate{}, DataChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(func(a ctrl.Object) []reconcile.Request {
+				return append(integrationsMounting(c, resource.StorageTypeSecret, a), integrationIssuedCertificate(a)...)
+			}),
+			builder.WithPredicates(NotFrequentlyUpdatedSecretPredicate{}, NotControlledByIntegrationPredicate{}, DataChangedPredicate{})).
+		// Watch for the Kamelets spec changes, to update the Integrations using them
+		Watches(&source.Kind{Type: &v1alpha1.Kamelet{}},
+			handler.EnqueueRequestsFromMapFunc(func(a ctrl.Object) []reconcile.Request {
+				return integrationsUsingKamelet(c, a)
+			}),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
 
 	// Watch for the owned Knative Services conditionally
 	if ok, err := kubernetes.IsAPIResourceInstalled(c, servingv1.SchemeGroupVersion.String(), reflect.TypeOf(servingv1.Service{}).Name()); err != nil {
@@ -285,11 +329,50 @@ func (r *reconcileIntegration) Reconcile(ctx context.Context, request reconcile.
 	}
 
 	// Only process resources assigned to the operator
-	if !platform.IsOperatorHandler(&instance) {
+	if ok, err := platform.IsOperatorHandlerOrDefault(ctx, r.client, &instance); err != nil {
+		return reconcile.Result{}, err
+	} else if !ok {
 		rlog.Info("Ignoring request because resource is not assigned to current operator")
 		return reconcile.Result{}, nil
 	}
 
+	// Paused Integrations are neither claimed nor deleted, until their reconciliation is resumed
+	paused, err := isReconcilePaused(ctx, r.client, &instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Claim the resource when the current operator is elected as the default one
+	if operatorID := defaults.OperatorID(); operatorID != "" && instance.Annotations[v1.OperatorIDAnnotation] == "" && !paused {
+		rlog.Infof("Claiming resource for default operator %s", operatorID)
+		claimed := instance.DeepCopy()
+		if claimed.Annotations == nil {
+			claimed.Annotations = make(map[string]string)
+		}
+		claimed.Annotations[v1.OperatorIDAnnotation] = operatorID
+		return reconcile.Result{}, r.client.Patch(ctx, claimed, ctrl.MergeFrom(&instance))
+	}
+
+	result := reconcile.Result{}
+
+	// Delete the preview, or temporary, Integration once expired, or make sure it is reconciled again by then
+	if expiration, reason, err := getExpiration(ctx, r.client, &instance); err != nil {
+		rlog.Error(err, "Ignoring the Integration expiration")
+	} else if expiration != nil {
+		if expiresIn := time.Until(*expiration); expiresIn > 0 {
+			result.RequeueAfter = expiresIn
+		} else if paused {
+			rlog.Infof("Keeping expired %s Integration until its reconciliation is resumed", reason)
+		} else {
+			rlog.Infof("Deleting expired %s Integration", reason)
+			if err := r.client.Delete(ctx, &instance); err != nil {
+				return reconcile.Result{}, ctrl.IgnoreNotFound(err)
+			}
+			integrationCleanup.WithLabelValues(reason).Inc()
+			return reconcile.Result{}, nil
+		}
+	}
+
 	target := instance.DeepCopy()
 	targetLog := rlog.ForIntegration(target)
 
@@ -331,11 +414,16 @@ func (r *reconcileIntegration) Reconcile(ctx context.Context, request reconcile.
 			// handle one action at time so the resource
 			// is always at its latest state
 			camelevent.NotifyIntegrationUpdated(ctx, r.client, r.recorder, &instance, newTarget)
+
+			if rq, ok := a.(requeuer); ok && rq.RequeueAfter() > 0 &&
+				(result.RequeueAfter == 0 || rq.RequeueAfter() < result.RequeueAfter) {
+				result.RequeueAfter = rq.RequeueAfter()
+			}
 			break
 		}
 	}
 
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
 func (r *reconcileIntegration) update(ctx context.Context, base *v1.Integration, target *v1.Integration) (reconcile.Result, error) {
@@ -351,3 +439,12 @@ func (r *reconcileIntegration) update(ctx context.Context, base *v1.Integration,
 
 	return reconcile.Result{}, err
 }
+
+// isReconcilePaused returns whether the reconciliation of the Integration, or of its platform, is paused.
+func isReconcilePaused(ctx context.Context, c client.Client, integration *v1.Integration) (bool, error) {
+	pl, err := platform.GetForResource(ctx, c, integration)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+	return integration.IsReconcilePaused(pl), nil
+}
@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/log"
+)
+
+// kameletsIndex indexes the Integrations by the namespaced names of the Kamelets they use.
+const kameletsIndex = "status.kamelets"
+
+// indexKamelets indexes the Integrations by the Kamelets they have been initialized with, so that the Integrations
+// using a given Kamelet can be retrieved from the cache without listing all the Integrations.
+func indexKamelets(obj ctrl.Object) []string {
+	integration, ok := obj.(*v1.Integration)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(integration.Status.Kamelets))
+	for _, k := range integration.Status.Kamelets {
+		keys = append(keys, kameletKey(k.Namespace, k.Name))
+	}
+	return keys
+}
+
+func kameletKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
+
+// integrationsUsingKamelet returns the requests for the running Integrations initialized with an older generation of
+// the given Kamelet. The Kamelets of the operator namespace can be used by Integrations from any namespace.
+func integrationsUsingKamelet(c client.Client, obj ctrl.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	list := &v1.IntegrationList{}
+	if err := c.List(context.Background(), list, ctrl.MatchingFields{kameletsIndex: kameletKey(obj.GetNamespace(), obj.GetName())}); err != nil {
+		log.Error(err, "Failed to list integrations")
+		return requests
+	}
+
+	for i := range list.Items {
+		integration := &list.Items[i]
+		if integration.Status.Phase != v1.IntegrationPhaseDeploying &&
+			integration.Status.Phase != v1.IntegrationPhaseRunning &&
+			integration.Status.Phase != v1.IntegrationPhaseCompleted &&
+			integration.Status.Phase != v1.IntegrationPhaseError {
+			continue
+		}
+		for _, k := range integration.Status.Kamelets {
+			if k.Namespace == obj.GetNamespace() && k.Name == obj.GetName() && k.Generation != obj.GetGeneration() {
+				log.Infof("Kamelet %s updated, notify integration: %s", obj.GetName(), integration.Name)
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: integration.Namespace,
+						Name:      integration.Name,
+					},
+				})
+				break
+			}
+		}
+	}
+
+	return requests
+}
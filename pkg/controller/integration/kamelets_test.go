@@ -0,0 +1,40 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestIndexKamelets(t *testing.T) {
+	it := v1.NewIntegration("ns", "it")
+	it.Status.Kamelets = []v1.IntegrationKamelet{
+		{Namespace: "ns", Name: "timer-source", Generation: 1},
+		{Namespace: "operators", Name: "log-sink", Generation: 2},
+	}
+
+	assert.Equal(t, []string{"ns/timer-source", "operators/log-sink"}, indexKamelets(&it))
+	assert.Empty(t, indexKamelets(&v1.Integration{}))
+	assert.Empty(t, indexKamelets(&corev1.ConfigMap{}))
+}
@@ -20,7 +20,9 @@ package integration
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
@@ -50,8 +52,12 @@ func lookupKitsForIntegration(ctx context.Context, c ctrl.Reader, integration *v
 		return nil, err
 	}
 
+	tenant, err := platform.GetTenant(ctx, c, integration.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	listOptions := []ctrl.ListOption{
-		ctrl.InNamespace(integration.GetIntegrationKitNamespace(pl)),
 		ctrl.MatchingLabels{
 			"camel.apache.org/runtime.version":  integration.Status.RuntimeVersion,
 			"camel.apache.org/runtime.provider": string(integration.Status.RuntimeProvider),
@@ -62,24 +68,95 @@ func lookupKitsForIntegration(ctx context.Context, c ctrl.Reader, integration *v
 	}
 	listOptions = append(listOptions, options...)
 
+	kits := make([]v1.IntegrationKit, 0)
+	for _, ns := range tenant.IntegrationKitLookupNamespaces(integration, pl) {
+		list := v1.NewIntegrationKitList()
+		if err := c.List(ctx, &list, append(listOptions, ctrl.InNamespace(ns))...); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			kit := &list.Items[i]
+			match, err := integrationMatches(integration, kit)
+			if err != nil {
+				return nil, err
+			} else if !match {
+				continue
+			}
+			kits = append(kits, *kit)
+		}
+	}
+
+	return kits, nil
+}
+
+// isIntegrationKitPinned returns whether the Integration explicitly references its kit, by name or by image digest.
+func isIntegrationKitPinned(integration *v1.Integration) bool {
+	return integration.Spec.IntegrationKit != nil && integration.Spec.IntegrationKit.Name != "" ||
+		integration.Spec.IntegrationKitDigest != ""
+}
+
+// findIntegrationKitByDigest returns the kit of the given namespace whose image has the given digest, or nil if none exists.
+// The ready kits are preferred, as the same image may be shared by several kits.
+func findIntegrationKitByDigest(ctx context.Context, c ctrl.Reader, namespace string, digest string) (*v1.IntegrationKit, error) {
 	list := v1.NewIntegrationKitList()
-	if err := c.List(ctx, &list, listOptions...); err != nil {
+	if err := c.List(ctx, &list, ctrl.InNamespace(namespace)); err != nil {
 		return nil, err
 	}
 
-	kits := make([]v1.IntegrationKit, 0)
+	var kit *v1.IntegrationKit
 	for i := range list.Items {
-		kit := &list.Items[i]
-		match, err := integrationMatches(integration, kit)
-		if err != nil {
-			return nil, err
-		} else if !match {
+		k := &list.Items[i]
+		if kitImageDigest(k) != digest {
 			continue
 		}
-		kits = append(kits, *kit)
+		if kit == nil || kit.Status.Phase != v1.IntegrationKitPhaseReady && k.Status.Phase == v1.IntegrationKitPhaseReady {
+			kit = k
+		}
 	}
 
-	return kits, nil
+	return kit, nil
+}
+
+// kitImageDigest returns the digest of the kit image, e.g. `sha256:...`, or an empty string if the image is not
+// addressed by digest, which is the case of the kits whose build has not completed yet.
+func kitImageDigest(kit *v1.IntegrationKit) string {
+	if i := strings.LastIndex(kit.Status.Image, "@"); i >= 0 {
+		return kit.Status.Image[i+1:]
+	}
+	return ""
+}
+
+// pinnedKitMismatch returns the reason why the kit the Integration is pinned to cannot run it, or an empty string
+// if the kit is compatible. Contrary to the kits looked up by the operator, a pinned kit is never replaced, so that
+// the mismatch is reported instead.
+func pinnedKitMismatch(integration *v1.Integration, kit *v1.IntegrationKit) string {
+	if digest := integration.Spec.IntegrationKitDigest; digest != "" && kitImageDigest(kit) != digest {
+		return fmt.Sprintf("integration kit %s/%s image %s does not match digest %s", kit.Namespace, kit.Name, kit.Status.Image, digest)
+	}
+	if provider := integration.Status.RuntimeProvider; provider != "" && kit.Status.RuntimeProvider != "" && kit.Status.RuntimeProvider != provider {
+		return fmt.Sprintf("integration kit %s/%s runtime provider %s does not match %s", kit.Namespace, kit.Name, kit.Status.RuntimeProvider, provider)
+	}
+	if version := integration.Status.RuntimeVersion; version != "" && kit.Status.RuntimeVersion != "" && kit.Status.RuntimeVersion != version {
+		return fmt.Sprintf("integration kit %s/%s runtime version %s does not match %s", kit.Namespace, kit.Name, kit.Status.RuntimeVersion, version)
+	}
+	if ns, ok := kit.Labels[v1.IntegrationKitCredentialsNamespaceLabel]; ok && ns != integration.Namespace {
+		return fmt.Sprintf("integration kit %s/%s has been built with the Maven credentials of namespace %s", kit.Namespace, kit.Name, ns)
+	}
+	// The dependencies of external kits are unknown
+	if kit.Labels[v1.IntegrationKitTypeLabel] != v1.IntegrationKitTypeExternal {
+		var missing []string
+		for _, dependency := range integration.Status.Dependencies {
+			if !util.StringSliceExists(kit.Spec.Dependencies, dependency) {
+				missing = append(missing, dependency)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Sprintf("integration kit %s/%s misses the dependencies %s", kit.Namespace, kit.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	return ""
 }
 
 // integrationMatches returns whether the v1.IntegrationKit meets the requirements of the v1.Integration.
@@ -103,6 +180,10 @@ func integrationMatches(integration *v1.Integration, kit *v1.IntegrationKit) (bo
 		ilog.Debug("Integration and integration-kit runtime versions do not match", "integration", integration.Name, "integration-kit", kit.Name, "namespace", integration.Namespace)
 		return false, nil
 	}
+	if ns, ok := kit.Labels[v1.IntegrationKitCredentialsNamespaceLabel]; ok && ns != integration.Namespace {
+		ilog.Debug("Integration-kit has been built with the Maven credentials of another namespace", "integration", integration.Name, "integration-kit", kit.Name, "namespace", integration.Namespace)
+		return false, nil
+	}
 	if len(integration.Status.Dependencies) != len(kit.Spec.Dependencies) {
 		ilog.Debug("Integration and integration-kit have different number of dependencies", "integration", integration.Name, "integration-kit", kit.Name, "namespace", integration.Namespace)
 		return false, nil
@@ -140,6 +221,9 @@ func kitMatches(kit1 *v1.IntegrationKit, kit2 *v1.IntegrationKit) (bool, error)
 	if version != kit2.Status.Version {
 		return false, nil
 	}
+	if kit1.Labels[v1.IntegrationKitCredentialsNamespaceLabel] != kit2.Labels[v1.IntegrationKitCredentialsNamespaceLabel] {
+		return false, nil
+	}
 	if len(kit1.Spec.Dependencies) != len(kit2.Spec.Dependencies) {
 		return false, nil
 	}
@@ -39,7 +39,17 @@ var timeToFirstReadiness = prometheus.NewHistogram(
 	},
 )
 
+var integrationCleanup = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "camel_k_integration_cleanup_total",
+		Help: "Camel K integrations deleted once expired",
+	},
+	[]string{
+		"reason",
+	},
+)
+
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(timeToFirstReadiness)
+	metrics.Registry.MustRegister(timeToFirstReadiness, integrationCleanup)
 }
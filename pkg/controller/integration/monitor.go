@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -42,8 +44,12 @@ import (
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 )
 
-// The key used for propagating error details from Camel health to MicroProfile Health (See CAMEL-17138).
-const runtimeHealthCheckErrorMessage = "error.message"
+const (
+	// The key used for propagating error details from Camel health to MicroProfile Health (See CAMEL-17138).
+	runtimeHealthCheckErrorMessage = "error.message"
+	// The key used by the Camel route health checks for the id of the route.
+	runtimeHealthCheckRouteID = "route.id"
+)
 
 func NewMonitorAction() Action {
 	return &monitorAction{}
@@ -51,24 +57,25 @@ func NewMonitorAction() Action {
 
 type monitorAction struct {
 	baseAction
+	requeueAfter time.Duration
 }
 
 func (action *monitorAction) Name() string {
 	return "monitor"
 }
 
+func (action *monitorAction) RequeueAfter() time.Duration {
+	return action.requeueAfter
+}
+
 func (action *monitorAction) CanHandle(integration *v1.Integration) bool {
 	return integration.Status.Phase == v1.IntegrationPhaseDeploying ||
 		integration.Status.Phase == v1.IntegrationPhaseRunning ||
+		integration.Status.Phase == v1.IntegrationPhaseCompleted ||
 		integration.Status.Phase == v1.IntegrationPhaseError
 }
 
 func (action *monitorAction) Handle(ctx context.Context, integration *v1.Integration) (*v1.Integration, error) {
-	// At that staged the Integration must have a Kit
-	if integration.Status.IntegrationKit == nil {
-		return nil, fmt.Errorf("no kit set on integration %s", integration.Name)
-	}
-
 	// Check if the Integration requires a rebuild
 	hash, err := digest.ComputeForIntegration(integration)
 	if err != nil {
@@ -84,39 +91,77 @@ func (action *monitorAction) Handle(ctx context.Context, integration *v1.Integra
 		return integration, nil
 	}
 
+	// At that staged the Integration must have a Kit
+	if integration.Status.IntegrationKit == nil {
+		if integration.Status.Phase == v1.IntegrationPhaseError {
+			// The Integration failed to initialize, e.g. because of an invalid trait configuration,
+			// and waits for being updated
+			return nil, nil
+		}
+		return nil, fmt.Errorf("no kit set on integration %s", integration.Name)
+	}
+
 	kit, err := kubernetes.GetIntegrationKit(ctx, action.client, integration.Status.IntegrationKit.Name, integration.Status.IntegrationKit.Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("unable to find integration kit %s/%s: %w", integration.Status.IntegrationKit.Namespace, integration.Status.IntegrationKit.Name, err)
 	}
 
-	// Check if an IntegrationKit with higher priority is ready
-	priority, ok := kit.Labels[v1.IntegrationKitPriorityLabel]
-	if !ok {
-		priority = "0"
-	}
-	withHigherPriority, err := labels.NewRequirement(v1.IntegrationKitPriorityLabel, selection.GreaterThan, []string{priority})
-	if err != nil {
-		return nil, err
-	}
-	kits, err := lookupKitsForIntegration(ctx, action.client, integration, ctrl.MatchingLabelsSelector{
-		Selector: labels.NewSelector().Add(*withHigherPriority),
-	})
-	if err != nil {
-		return nil, err
-	}
-	priorityReadyKit, err := findHighestPriorityReadyKit(kits)
-	if err != nil {
-		return nil, err
-	}
-	if priorityReadyKit != nil {
-		integration.SetIntegrationKit(priorityReadyKit)
+	// Check if an IntegrationKit with higher priority is ready, unless the Integration is pinned to its kit
+	if !isIntegrationKitPinned(integration) {
+		priority, ok := kit.Labels[v1.IntegrationKitPriorityLabel]
+		if !ok {
+			priority = "0"
+		}
+		withHigherPriority, err := labels.NewRequirement(v1.IntegrationKitPriorityLabel, selection.GreaterThan, []string{priority})
+		if err != nil {
+			return nil, err
+		}
+		kits, err := lookupKitsForIntegration(ctx, action.client, integration, ctrl.MatchingLabelsSelector{
+			Selector: labels.NewSelector().Add(*withHigherPriority),
+		})
+		if err != nil {
+			return nil, err
+		}
+		priorityReadyKit, err := findHighestPriorityReadyKit(kits)
+		if err != nil {
+			return nil, err
+		}
+		if priorityReadyKit != nil {
+			integration.SetIntegrationKit(priorityReadyKit)
+		}
 	}
 
 	// Run traits that are enabled for the phase
 	environment, err := trait.Apply(ctx, action.client, integration, kit)
 	if err != nil {
+		var cerr *trait.ConfigurationError
+		if errors.As(err, &cerr) {
+			integration.Status.Phase = v1.IntegrationPhaseError
+			setReadyConditionError(integration, err.Error())
+			return integration, nil
+		}
 		return nil, err
 	}
+	action.requeueAfter = environment.RequeueAfter
+
+	if environment.IsReconcilePaused() {
+		integration.Status.SetCondition(v1.IntegrationConditionReconcilePaused, corev1.ConditionTrue,
+			v1.IntegrationConditionReconcilePausedReason, "the resources owned by the integration are not updated by the operator")
+	} else {
+		integration.Status.RemoveCondition(v1.IntegrationConditionReconcilePaused)
+	}
+
+	// Rebuild the Integration when the Kamelets it uses have been updated, unless the update is held or ignored,
+	// or the reconciliation is paused
+	if c := integration.Status.GetCondition(v1.IntegrationConditionKameletsUpToDate); c != nil && !environment.IsReconcilePaused() &&
+		c.Status == corev1.ConditionFalse && c.Reason == v1.IntegrationConditionKameletsUpdatedReason {
+		action.L.Info("Integration needs a rebuild", "reason", c.Message)
+
+		integration.Initialize()
+		integration.Status.Digest = hash
+
+		return integration, nil
+	}
 
 	// Enforce the scale sub-resource label selector.
 	// It is used by the HPA that queries the scale sub-resource endpoint,
@@ -140,6 +185,10 @@ func (action *monitorAction) Handle(ctx context.Context, integration *v1.Integra
 	if err != nil {
 		return nil, err
 	}
+	// The canary Pods are evaluated by the rollout
+	var canaryPendingPods, canaryRunningPods []corev1.Pod
+	pendingPods.Items, canaryPendingPods = splitCanaryPods(integration, pendingPods.Items)
+	runningPods.Items, canaryRunningPods = splitCanaryPods(integration, runningPods.Items)
 	nonTerminatingPods := 0
 	for _, pod := range runningPods.Items {
 		if pod.DeletionTimestamp != nil {
@@ -158,6 +207,21 @@ func (action *monitorAction) Handle(ctx context.Context, integration *v1.Integra
 	if err != nil {
 		return nil, err
 	}
+	checkDependencies(environment, integration, pendingPods.Items)
+	pods := make([]corev1.Pod, 0, len(pendingPods.Items)+len(runningPods.Items))
+	pods = append(pods, pendingPods.Items...)
+	pods = append(pods, runningPods.Items...)
+	err = action.checkRestartBudget(ctx, environment, integration, pods)
+	if err != nil {
+		return nil, err
+	}
+	err = action.checkSmokeTest(ctx, environment, integration, runningPods.Items)
+	if err != nil {
+		return nil, err
+	}
+	action.countCanaryExchanges(ctx, environment, integration, canaryRunningPods)
+	action.checkRollout(environment, integration, append(canaryPendingPods, canaryRunningPods...))
+	action.profileResources(ctx, environment, integration)
 
 	return integration, nil
 }
@@ -173,6 +237,14 @@ func (action *monitorAction) newController(ctx context.Context, env *trait.Envir
 	var obj ctrl.Object
 	switch {
 	case isConditionTrue(integration, v1.IntegrationConditionDeploymentAvailable):
+		// The Deployment is replaced by a StatefulSet when the replicas require their own storage
+		if obj = getUpdatedController(env, &appsv1.StatefulSet{}); obj != nil {
+			controller = &statefulSetController{
+				obj:         obj.(*appsv1.StatefulSet),
+				integration: integration,
+			}
+			break
+		}
 		obj = getUpdatedController(env, &appsv1.Deployment{})
 		controller = &deploymentController{
 			obj:         obj.(*appsv1.Deployment),
@@ -192,6 +264,12 @@ func (action *monitorAction) newController(ctx context.Context, env *trait.Envir
 			client:      action.client,
 			context:     ctx,
 		}
+	case isConditionTrue(integration, v1.IntegrationConditionJobAvailable):
+		obj = getUpdatedController(env, &batchv1.Job{})
+		controller = &jobController{
+			obj:         obj.(*batchv1.Job),
+			integration: integration,
+		}
 	default:
 		return nil, fmt.Errorf("unsupported controller for integration %s", integration.Name)
 	}
@@ -216,6 +294,10 @@ func (action *monitorAction) updateIntegrationPhaseAndReadyCondition(ctx context
 		return err
 	}
 
+	if deployment, ok := controller.(*deploymentController); ok {
+		deployment.updateProgressingCondition(pendingPods, runningPods)
+	}
+
 	if done, err := controller.checkReadyCondition(); done || err != nil {
 		return err
 	}
@@ -316,6 +398,7 @@ func filterPodsByReadyStatus(runningPods []corev1.Pod, podSpec corev1.PodSpec) (
 // probeReadiness calls the readiness probes of the non-ready Pods directly to retrieve insights from the Camel runtime.
 func (action *monitorAction) probeReadiness(ctx context.Context, environment *trait.Environment, integration *v1.Integration, unreadyPods []corev1.Pod) error {
 	var runtimeNotReadyMessages []string
+	routeNotReady := false
 	for i := range unreadyPods {
 		pod := &unreadyPods[i]
 		if ready := kubernetes.GetPodCondition(*pod, corev1.PodReady); ready.Reason != "ContainersNotReady" {
@@ -349,6 +432,11 @@ func (action *monitorAction) probeReadiness(ctx context.Context, environment *tr
 				if _, ok := check.Data[runtimeHealthCheckErrorMessage]; ok {
 					integration.Status.Phase = v1.IntegrationPhaseError
 				}
+				if routeID, ok := check.Data[runtimeHealthCheckRouteID]; ok {
+					routeNotReady = true
+					runtimeNotReadyMessages = append(runtimeNotReadyMessages, fmt.Sprintf("Pod %s route %v is not ready: %s", pod.Name, routeID, check.Data))
+					continue
+				}
 				runtimeNotReadyMessages = append(runtimeNotReadyMessages, fmt.Sprintf("Pod %s runtime is not ready: %s", pod.Name, check.Data))
 			}
 		}
@@ -357,6 +445,8 @@ func (action *monitorAction) probeReadiness(ctx context.Context, environment *tr
 		reason := v1.IntegrationConditionRuntimeNotReadyReason
 		if integration.Status.Phase == v1.IntegrationPhaseError {
 			reason = v1.IntegrationConditionErrorReason
+		} else if routeNotReady {
+			reason = v1.IntegrationConditionRouteNotReadyReason
 		}
 		setReadyCondition(integration, corev1.ConditionFalse, reason, fmt.Sprintf("%s", runtimeNotReadyMessages))
 	}
@@ -19,6 +19,7 @@ package integration
 
 import (
 	"fmt"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -75,3 +76,84 @@ func (c *deploymentController) updateReadyCondition(readyPods []corev1.Pod) bool
 
 	return false
 }
+
+// updateProgressingCondition reports the progress of the Deployment rollout, along with the reason why it's stalled,
+// as observed from the Deployment, its ReplicaSets and its Pods, so that it's not needed to inspect them.
+func (c *deploymentController) updateProgressingCondition(pendingPods []corev1.Pod, runningPods []corev1.Pod) {
+	replicas := int32(1)
+	if r := c.integration.Spec.Replicas; r != nil {
+		replicas = *r
+	}
+	status := c.obj.Status
+
+	// The ReplicaSet failures, e.g., when the Pods cannot be created, are reported by the Deployment
+	if failure := kubernetes.GetDeploymentCondition(*c.obj, appsv1.DeploymentReplicaFailure); failure != nil && failure.Status == corev1.ConditionTrue {
+		reason := v1.IntegrationConditionDeploymentReplicaFailureReason
+		if strings.Contains(failure.Message, "exceeded quota") {
+			reason = v1.IntegrationConditionDeploymentQuotaExceededReason
+		}
+		c.setProgressingCondition(corev1.ConditionFalse, reason, failure.Message)
+		return
+	}
+	if progressing := kubernetes.GetDeploymentCondition(*c.obj, appsv1.DeploymentProgressing); progressing != nil && progressing.Status == corev1.ConditionFalse && progressing.Reason == "ProgressDeadlineExceeded" {
+		c.setProgressingCondition(corev1.ConditionFalse, v1.IntegrationConditionDeploymentProgressDeadlineExceededReason, progressing.Message)
+		return
+	}
+	if reason, message := getPodFailure(pendingPods, runningPods); reason != "" {
+		c.setProgressingCondition(corev1.ConditionFalse, reason, message)
+		return
+	}
+
+	if status.ObservedGeneration >= c.obj.Generation && status.UpdatedReplicas >= replicas &&
+		status.AvailableReplicas >= replicas && status.Replicas == status.UpdatedReplicas {
+		c.setProgressingCondition(corev1.ConditionTrue, v1.IntegrationConditionDeploymentRolloutCompleteReason,
+			fmt.Sprintf("%d/%d updated replicas available", status.AvailableReplicas, replicas))
+		return
+	}
+	c.setProgressingCondition(corev1.ConditionTrue, v1.IntegrationConditionDeploymentRolloutProgressingReason,
+		fmt.Sprintf("%d/%d updated replicas, %d available, %d old replicas pending termination",
+			status.UpdatedReplicas, replicas, status.AvailableReplicas, status.Replicas-status.UpdatedReplicas))
+}
+
+func (c *deploymentController) setProgressingCondition(status corev1.ConditionStatus, reason string, message string) {
+	c.integration.Status.SetCondition(v1.IntegrationConditionDeploymentProgressing, status, reason, message)
+}
+
+// getPodFailure returns the reason why the Pods of the Integration cannot run, if any, and the message reported by the
+// first failing Pod.
+func getPodFailure(pendingPods []corev1.Pod, runningPods []corev1.Pod) (string, string) {
+	for _, pod := range pendingPods {
+		if scheduled := kubernetes.GetPodCondition(pod, corev1.PodScheduled); scheduled != nil && scheduled.Status == corev1.ConditionFalse && scheduled.Reason == "Unschedulable" {
+			return v1.IntegrationConditionDeploymentUnschedulableReason, fmt.Sprintf("Pod %s: %s", pod.Name, scheduled.Message)
+		}
+	}
+
+	pods := make([]corev1.Pod, 0, len(pendingPods)+len(runningPods))
+	pods = append(pods, pendingPods...)
+	pods = append(pods, runningPods...)
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		var containers []corev1.ContainerStatus
+		containers = append(containers, pod.Status.InitContainerStatuses...)
+		containers = append(containers, pod.Status.ContainerStatuses...)
+		for _, container := range containers {
+			waiting := container.State.Waiting
+			if waiting == nil {
+				continue
+			}
+			message := fmt.Sprintf("Pod %s, container %s: %s", pod.Name, container.Name, waiting.Message)
+			switch waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+				return v1.IntegrationConditionDeploymentImagePullFailedReason, message
+			case "CreateContainerConfigError":
+				return v1.IntegrationConditionDeploymentContainerConfigErrorReason, message
+			case "CrashLoopBackOff":
+				return v1.IntegrationConditionDeploymentCrashLoopingReason, message
+			}
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,153 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func newTestDeploymentController(replicas int32, status appsv1.DeploymentStatus) *deploymentController {
+	return &deploymentController{
+		obj: &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2},
+			Status:     status,
+		},
+		integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{Replicas: &replicas},
+		},
+	}
+}
+
+func TestProgressingConditionRolloutComplete(t *testing.T) {
+	c := newTestDeploymentController(2, appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           2,
+		UpdatedReplicas:    2,
+		AvailableReplicas:  2,
+	})
+
+	c.updateProgressingCondition(nil, nil)
+
+	condition := c.integration.Status.GetCondition(v1.IntegrationConditionDeploymentProgressing)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionDeploymentRolloutCompleteReason, condition.Reason)
+	assert.Equal(t, "2/2 updated replicas available", condition.Message)
+}
+
+func TestProgressingConditionRolloutProgressing(t *testing.T) {
+	c := newTestDeploymentController(2, appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    1,
+		AvailableReplicas:  2,
+	})
+
+	c.updateProgressingCondition(nil, nil)
+
+	condition := c.integration.Status.GetCondition(v1.IntegrationConditionDeploymentProgressing)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionDeploymentRolloutProgressingReason, condition.Reason)
+	assert.Equal(t, "1/2 updated replicas, 2 available, 2 old replicas pending termination", condition.Message)
+}
+
+func TestProgressingConditionQuotaExceeded(t *testing.T) {
+	c := newTestDeploymentController(1, appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Conditions: []appsv1.DeploymentCondition{
+			{
+				Type:    appsv1.DeploymentReplicaFailure,
+				Status:  corev1.ConditionTrue,
+				Reason:  "FailedCreate",
+				Message: `pods "test-1" is forbidden: exceeded quota: compute, requested: cpu=1, used: cpu=2, limited: cpu=2`,
+			},
+		},
+	})
+
+	c.updateProgressingCondition(nil, nil)
+
+	condition := c.integration.Status.GetCondition(v1.IntegrationConditionDeploymentProgressing)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionDeploymentQuotaExceededReason, condition.Reason)
+	assert.Contains(t, condition.Message, "exceeded quota: compute")
+}
+
+func TestProgressingConditionPodFailures(t *testing.T) {
+	waiting := func(reason string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-1"},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "integration",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: "failure"},
+						},
+					},
+				},
+			},
+		}
+	}
+	unschedulable := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-2"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: "0/3 nodes are available: 3 Insufficient memory.",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		pending []corev1.Pod
+		running []corev1.Pod
+		reason  string
+		message string
+	}{
+		{[]corev1.Pod{waiting("ImagePullBackOff")}, nil, v1.IntegrationConditionDeploymentImagePullFailedReason, "Pod test-1, container integration: failure"},
+		{[]corev1.Pod{waiting("CreateContainerConfigError")}, nil, v1.IntegrationConditionDeploymentContainerConfigErrorReason, "Pod test-1, container integration: failure"},
+		{nil, []corev1.Pod{waiting("CrashLoopBackOff")}, v1.IntegrationConditionDeploymentCrashLoopingReason, "Pod test-1, container integration: failure"},
+		{[]corev1.Pod{waiting("ErrImagePull"), unschedulable}, nil, v1.IntegrationConditionDeploymentUnschedulableReason, "Pod test-2: 0/3 nodes are available: 3 Insufficient memory."},
+	}
+
+	for _, test := range tests {
+		c := newTestDeploymentController(1, appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1})
+
+		c.updateProgressingCondition(test.pending, test.running)
+
+		condition := c.integration.Status.GetCondition(v1.IntegrationConditionDeploymentProgressing)
+		assert.NotNil(t, condition)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, test.reason, condition.Reason)
+		assert.Equal(t, test.message, condition.Message)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+type jobController struct {
+	obj         *batchv1.Job
+	integration *v1.Integration
+}
+
+var _ controller = &jobController{}
+
+func (c *jobController) checkReadyCondition() (bool, error) {
+	// Check the Job completion
+	if complete := kubernetes.GetJobCondition(*c.obj, batchv1.JobComplete); complete != nil && complete.Status == corev1.ConditionTrue {
+		setReadyCondition(c.integration, corev1.ConditionFalse, v1.IntegrationConditionJobCompletedReason, fmt.Sprintf("job %s completed successfully", c.obj.Name))
+		c.integration.Status.Phase = v1.IntegrationPhaseCompleted
+		return true, nil
+	}
+	if failed := kubernetes.GetJobCondition(*c.obj, batchv1.JobFailed); failed != nil && failed.Status == corev1.ConditionTrue {
+		setReadyCondition(c.integration, corev1.ConditionFalse, v1.IntegrationConditionJobFailedReason, fmt.Sprintf("job %s failed: %s", c.obj.Name, failed.Message))
+		c.integration.Status.Phase = v1.IntegrationPhaseError
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (c *jobController) getPodSpec() corev1.PodSpec {
+	return c.obj.Spec.Template.Spec
+}
+
+func (c *jobController) updateReadyCondition(readyPods []corev1.Pod) bool {
+	if c.obj.Status.Active > 0 {
+		setReadyCondition(c.integration, corev1.ConditionTrue, v1.IntegrationConditionJobActiveReason, "job active")
+		return true
+	}
+
+	setReadyCondition(c.integration, corev1.ConditionUnknown, "", "")
+	return false
+}
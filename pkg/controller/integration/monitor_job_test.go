@@ -0,0 +1,105 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func newTestJobController(status batchv1.JobStatus) *jobController {
+	return &jobController{
+		obj: &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Status:     status,
+		},
+		integration: &v1.Integration{
+			Status: v1.IntegrationStatus{Phase: v1.IntegrationPhaseRunning},
+		},
+	}
+}
+
+func TestJobActive(t *testing.T) {
+	c := newTestJobController(batchv1.JobStatus{Active: 1})
+
+	done, err := c.checkReadyCondition()
+	assert.Nil(t, err)
+	assert.False(t, done)
+	assert.True(t, c.updateReadyCondition(nil))
+
+	assert.Equal(t, v1.IntegrationPhaseRunning, c.integration.Status.Phase)
+	condition := c.integration.Status.GetCondition(v1.IntegrationConditionReady)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionJobActiveReason, condition.Reason)
+}
+
+func TestJobCompleted(t *testing.T) {
+	c := newTestJobController(batchv1.JobStatus{
+		Succeeded: 1,
+		Conditions: []batchv1.JobCondition{
+			{
+				Type:   batchv1.JobComplete,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	})
+
+	done, err := c.checkReadyCondition()
+	assert.Nil(t, err)
+	assert.True(t, done)
+
+	assert.Equal(t, v1.IntegrationPhaseCompleted, c.integration.Status.Phase)
+	condition := c.integration.Status.GetCondition(v1.IntegrationConditionReady)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionJobCompletedReason, condition.Reason)
+	assert.Equal(t, "job test completed successfully", condition.Message)
+}
+
+func TestJobFailed(t *testing.T) {
+	c := newTestJobController(batchv1.JobStatus{
+		Failed: 3,
+		Conditions: []batchv1.JobCondition{
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			},
+		},
+	})
+
+	done, err := c.checkReadyCondition()
+	assert.Nil(t, err)
+	assert.True(t, done)
+
+	assert.Equal(t, v1.IntegrationPhaseError, c.integration.Status.Phase)
+	condition := c.integration.Status.GetCondition(v1.IntegrationConditionReady)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionJobFailedReason, condition.Reason)
+	assert.Equal(t, "job test failed: Job has reached the specified backoff limit", condition.Message)
+}
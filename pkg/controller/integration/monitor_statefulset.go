@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+type statefulSetController struct {
+	obj         *appsv1.StatefulSet
+	integration *v1.Integration
+}
+
+var _ controller = &statefulSetController{}
+
+func (c *statefulSetController) checkReadyCondition() (bool, error) {
+	return false, nil
+}
+
+func (c *statefulSetController) getPodSpec() corev1.PodSpec {
+	return c.obj.Spec.Template.Spec
+}
+
+func (c *statefulSetController) updateReadyCondition(readyPods []corev1.Pod) bool {
+	replicas := int32(1)
+	if r := c.integration.Spec.Replicas; r != nil {
+		replicas = *r
+	}
+	readyReplicas := int32(len(readyPods))
+	switch {
+	case readyReplicas >= replicas:
+		setReadyCondition(c.integration, corev1.ConditionTrue, v1.IntegrationConditionStatefulSetReadyReason, fmt.Sprintf("%d/%d ready replicas", readyReplicas, replicas))
+		return true
+
+	case c.obj.Status.UpdatedReplicas < replicas:
+		setReadyCondition(c.integration, corev1.ConditionFalse, v1.IntegrationConditionStatefulSetProgressingReason, fmt.Sprintf("%d/%d updated replicas", c.obj.Status.UpdatedReplicas, replicas))
+
+	default:
+		setReadyCondition(c.integration, corev1.ConditionFalse, v1.IntegrationConditionStatefulSetProgressingReason, fmt.Sprintf("%d/%d ready replicas", readyReplicas, replicas))
+	}
+
+	return false
+}
@@ -19,10 +19,16 @@ package integration
 
 import (
 	"reflect"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 )
 
 // StatusChangedPredicate implements a generic update predicate function on status change.
@@ -55,3 +61,102 @@ func (StatusChangedPredicate) Update(e event.UpdateEvent) bool {
 
 	return !equality.Semantic.DeepDerivative(s1.Interface(), s2.Interface())
 }
+
+// DataChangedPredicate implements an update predicate function on the change of the content of configmaps and secrets,
+// or of their checksum annotation.
+type DataChangedPredicate struct {
+	predicate.Funcs
+}
+
+// Update implements UpdateEvent filter for validating content change.
+func (DataChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	if e.ObjectOld.GetAnnotations()[v1.ChecksumAnnotation] != e.ObjectNew.GetAnnotations()[v1.ChecksumAnnotation] {
+		return true
+	}
+
+	for _, field := range []string{"Data", "BinaryData"} {
+		d1 := reflect.ValueOf(e.ObjectOld).Elem().FieldByName(field)
+		d2 := reflect.ValueOf(e.ObjectNew).Elem().FieldByName(field)
+		if d1.IsValid() && d2.IsValid() && !equality.Semantic.DeepEqual(d1.Interface(), d2.Interface()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// helmReleaseSecretType is the type of the secrets storing the Helm releases.
+const helmReleaseSecretType = "helm.sh/release.v1"
+
+// NotFrequentlyUpdatedSecretPredicate filters out the events of the secrets that are frequently updated on busy clusters,
+// i.e., service account tokens and Helm releases. The secrets are filtered at the watch level, rather than excluded from
+// the cache, so that they can still be read by the operator, e.g., the service CA of the builder service account token.
+type NotFrequentlyUpdatedSecretPredicate struct {
+	predicate.Funcs
+}
+
+// Create implements CreateEvent filter.
+func (NotFrequentlyUpdatedSecretPredicate) Create(e event.CreateEvent) bool {
+	return !isFrequentlyUpdatedSecret(e.Object)
+}
+
+// Update implements UpdateEvent filter.
+func (NotFrequentlyUpdatedSecretPredicate) Update(e event.UpdateEvent) bool {
+	return !isFrequentlyUpdatedSecret(e.ObjectNew)
+}
+
+// Delete implements DeleteEvent filter.
+func (NotFrequentlyUpdatedSecretPredicate) Delete(e event.DeleteEvent) bool {
+	return !isFrequentlyUpdatedSecret(e.Object)
+}
+
+// Generic implements GenericEvent filter.
+func (NotFrequentlyUpdatedSecretPredicate) Generic(e event.GenericEvent) bool {
+	return !isFrequentlyUpdatedSecret(e.Object)
+}
+
+func isFrequentlyUpdatedSecret(obj ctrl.Object) bool {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	return secret.Type == corev1.SecretTypeServiceAccountToken || secret.Type == helmReleaseSecretType
+}
+
+// NotControlledByIntegrationPredicate filters out the events of the resources controlled by an Integration,
+// like the configmaps generated by the traits, that are already reconciled as part of their owner.
+type NotControlledByIntegrationPredicate struct {
+	predicate.Funcs
+}
+
+// Create implements CreateEvent filter.
+func (NotControlledByIntegrationPredicate) Create(e event.CreateEvent) bool {
+	return !isControlledByIntegration(e.Object)
+}
+
+// Update implements UpdateEvent filter.
+func (NotControlledByIntegrationPredicate) Update(e event.UpdateEvent) bool {
+	return !isControlledByIntegration(e.ObjectNew)
+}
+
+// Delete implements DeleteEvent filter.
+func (NotControlledByIntegrationPredicate) Delete(e event.DeleteEvent) bool {
+	return !isControlledByIntegration(e.Object)
+}
+
+// Generic implements GenericEvent filter.
+func (NotControlledByIntegrationPredicate) Generic(e event.GenericEvent) bool {
+	return !isControlledByIntegration(e.Object)
+}
+
+func isControlledByIntegration(obj ctrl.Object) bool {
+	if obj == nil {
+		return false
+	}
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && ref.Kind == v1.IntegrationKind && strings.HasPrefix(ref.APIVersion, v1.SchemeGroupVersion.Group+"/")
+}
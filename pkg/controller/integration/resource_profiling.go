@@ -0,0 +1,221 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+const mebibyte = 1024 * 1024
+
+// The subset of the metrics.k8s.io/v1beta1 PodMetricsList resource used to profile the Integrations.
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+type podMetrics struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Containers        []containerMetrics `json:"containers"`
+}
+
+type containerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// profileResources samples the resource usage of the running Integration Pods, and computes the recommended
+// resources once the observation window of the resource profiling is complete.
+func (action *monitorAction) profileResources(ctx context.Context, environment *trait.Environment, integration *v1.Integration) {
+	profiling := environment.GetResourceProfiling()
+	if profiling == nil {
+		integration.Status.ResourceProfile = nil
+		integration.Status.RemoveCondition(v1.IntegrationConditionResourceRecommendationAvailable)
+		return
+	}
+	if integration.Status.Phase != v1.IntegrationPhaseRunning || isConditionTrue(integration, v1.IntegrationConditionCronJobAvailable) ||
+		isConditionTrue(integration, v1.IntegrationConditionJobAvailable) {
+		return
+	}
+
+	now := time.Now()
+	profile := integration.Status.ResourceProfile
+	if profile != nil && now.Before(profile.LastSampleTime.Add(profiling.Interval)) {
+		return
+	}
+
+	usages, err := getContainerUsages(ctx, action.client, integration, environment.GetIntegrationContainerName())
+	if err != nil {
+		action.L.Info("Unable to sample the Integration resource usage", "error", err.Error())
+		if profile == nil || profile.Recommendation == nil {
+			integration.Status.SetCondition(v1.IntegrationConditionResourceRecommendationAvailable, corev1.ConditionFalse,
+				v1.IntegrationConditionResourceMetricsNotAvailableReason, fmt.Sprintf("cannot get the resource metrics: %s", err.Error()))
+		}
+		return
+	}
+	if len(usages) == 0 {
+		return
+	}
+
+	profile = sampleResourceProfile(profile, usages, now, profiling)
+	integration.Status.ResourceProfile = profile
+
+	if profile.Recommendation != nil {
+		integration.Status.SetCondition(v1.IntegrationConditionResourceRecommendationAvailable, corev1.ConditionTrue,
+			v1.IntegrationConditionResourceRecommendationAvailableReason, profile.RecommendationString())
+	} else {
+		integration.Status.SetCondition(v1.IntegrationConditionResourceRecommendationAvailable, corev1.ConditionFalse,
+			v1.IntegrationConditionResourceProfilingReason, fmt.Sprintf("%d sample(s) collected, the recommendation is available after %s",
+				profile.Samples, profile.WindowStart.Add(profiling.Window).Format(time.RFC3339)))
+	}
+}
+
+// getContainerUsages returns the resource usage of the Integration container, for each of the Integration Pods,
+// as reported by the resource metrics API.
+func getContainerUsages(ctx context.Context, c kubernetes.Interface, integration *v1.Integration, container string) ([]corev1.ResourceList, error) {
+	data, err := c.CoreV1().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1", "namespaces", integration.Namespace, "pods").
+		Param("labelSelector", v1.IntegrationLabel+"="+integration.Name).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := podMetricsList{}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return nil, err
+	}
+
+	usages := make([]corev1.ResourceList, 0, len(metrics.Items))
+	for _, pod := range metrics.Items {
+		for _, c := range pod.Containers {
+			if c.Name == container {
+				usages = append(usages, c.Usage)
+			}
+		}
+	}
+
+	return usages, nil
+}
+
+// sampleResourceProfile returns the profile updated with the given usages, and with the recommended resources computed
+// when its observation window is complete, in which case a new observation window is started.
+func sampleResourceProfile(profile *v1.ResourceProfile, usages []corev1.ResourceList, now time.Time, profiling *trait.ResourceProfiling) *v1.ResourceProfile {
+	p := profile.DeepCopy()
+	if p == nil {
+		p = &v1.ResourceProfile{
+			WindowStart: metav1.NewTime(now),
+		}
+	}
+	if p.Average == nil {
+		p.Average = make(corev1.ResourceList)
+	}
+	if p.Peak == nil {
+		p.Peak = make(corev1.ResourceList)
+	}
+
+	for _, usage := range usages {
+		p.Samples++
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			q, ok := usage[name]
+			if !ok {
+				continue
+			}
+			value := q.MilliValue()
+			average := milliValue(p.Average, name)
+			setMilliValue(p.Average, name, average+(value-average)/int64(p.Samples))
+			if value > milliValue(p.Peak, name) {
+				setMilliValue(p.Peak, name, value)
+			}
+		}
+	}
+	p.LastSampleTime = metav1.NewTime(now)
+
+	if now.Sub(p.WindowStart.Time) >= profiling.Window {
+		p.Recommendation = recommendResources(p, profiling.Headroom)
+		p.WindowStart = metav1.NewTime(now)
+		p.Samples = 0
+		p.Average = nil
+		p.Peak = nil
+	}
+
+	return p
+}
+
+// recommendResources returns the requests and limits recommended from the usage aggregated by the profile.
+// The CPU request is the average usage, and the CPU limit the peak usage, while the memory request and limit
+// are both the peak usage, as memory isn't a compressible resource.
+func recommendResources(profile *v1.ResourceProfile, headroom int) *corev1.ResourceRequirements {
+	withHeadroom := func(value int64) int64 {
+		return value * int64(100+headroom) / 100
+	}
+
+	requests := make(corev1.ResourceList)
+	limits := make(corev1.ResourceList)
+
+	if _, ok := profile.Peak[corev1.ResourceCPU]; ok {
+		average := withHeadroom(milliValue(profile.Average, corev1.ResourceCPU))
+		if average < 1 {
+			average = 1
+		}
+		requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(average, resource.DecimalSI)
+		peak := withHeadroom(milliValue(profile.Peak, corev1.ResourceCPU))
+		if peak < average {
+			peak = average
+		}
+		limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(peak, resource.DecimalSI)
+	}
+	if _, ok := profile.Peak[corev1.ResourceMemory]; ok {
+		// Round the memory up to the next mebibyte
+		peak := withHeadroom(milliValue(profile.Peak, corev1.ResourceMemory)) / 1000
+		peak = (peak + mebibyte - 1) / mebibyte * mebibyte
+		memory := *resource.NewQuantity(peak, resource.BinarySI)
+		requests[corev1.ResourceMemory] = memory
+		limits[corev1.ResourceMemory] = memory.DeepCopy()
+	}
+
+	return &corev1.ResourceRequirements{
+		Requests: requests,
+		Limits:   limits,
+	}
+}
+
+func milliValue(resources corev1.ResourceList, name corev1.ResourceName) int64 {
+	if q, ok := resources[name]; ok {
+		return q.MilliValue()
+	}
+	return 0
+}
+
+func setMilliValue(resources corev1.ResourceList, name corev1.ResourceName, value int64) {
+	if name == corev1.ResourceCPU {
+		resources[name] = *resource.NewMilliQuantity(value, resource.DecimalSI)
+	} else {
+		resources[name] = *resource.NewQuantity(value/1000, resource.BinarySI)
+	}
+}
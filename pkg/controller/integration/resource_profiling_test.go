@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+func TestSampleResourceProfile(t *testing.T) {
+	profiling := &trait.ResourceProfiling{
+		Window:   time.Hour,
+		Interval: 5 * time.Minute,
+		Headroom: 20,
+	}
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	profile := sampleResourceProfile(nil, []corev1.ResourceList{
+		{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("200Mi")},
+		{corev1.ResourceCPU: resource.MustParse("300m"), corev1.ResourceMemory: resource.MustParse("300Mi")},
+	}, start, profiling)
+
+	assert.Equal(t, 2, profile.Samples)
+	assert.Equal(t, start, profile.WindowStart.Time)
+	assert.Equal(t, int64(200), profile.Average.Cpu().MilliValue())
+	assert.Equal(t, int64(300), profile.Peak.Cpu().MilliValue())
+	assert.Equal(t, resource.MustParse("300Mi").Value(), profile.Peak.Memory().Value())
+	assert.Nil(t, profile.Recommendation)
+
+	profile = sampleResourceProfile(profile, []corev1.ResourceList{
+		{corev1.ResourceCPU: resource.MustParse("200m"), corev1.ResourceMemory: resource.MustParse("250Mi")},
+	}, start.Add(time.Hour), profiling)
+
+	assert.Equal(t, 0, profile.Samples)
+	assert.Equal(t, start.Add(time.Hour), profile.WindowStart.Time)
+	assert.Nil(t, profile.Average)
+	assert.Nil(t, profile.Peak)
+	assert.NotNil(t, profile.Recommendation)
+	assert.Equal(t, "240m", profile.Recommendation.Requests.Cpu().String())
+	assert.Equal(t, "360m", profile.Recommendation.Limits.Cpu().String())
+	assert.Equal(t, "360Mi", profile.Recommendation.Requests.Memory().String())
+	assert.Equal(t, "360Mi", profile.Recommendation.Limits.Memory().String())
+	assert.Equal(t, "requests: cpu=240m, memory=360Mi; limits: cpu=360m, memory=360Mi", profile.RecommendationString())
+}
@@ -0,0 +1,177 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+// The maximum number of Pod warning events reported in the restart budget diagnostics.
+const restartBudgetMaxEvents = 5
+
+// checkRestartBudget applies the restart budget remediation, once a container of the Integration Pods has been restarted
+// more times than the budget allows. The remediation holds until the Integration is updated.
+func (action *monitorAction) checkRestartBudget(ctx context.Context, environment *trait.Environment, integration *v1.Integration, pods []corev1.Pod) error {
+	budget := environment.GetRestartBudget()
+	if budget == nil || isConditionTrue(integration, v1.IntegrationConditionCronJobAvailable) ||
+		isConditionTrue(integration, v1.IntegrationConditionJobAvailable) {
+		return nil
+	}
+
+	return action.applyRestartBudget(ctx, environment, integration, pods, budget)
+}
+
+func (action *monitorAction) applyRestartBudget(ctx context.Context, environment *trait.Environment, integration *v1.Integration, pods []corev1.Pod, budget *trait.RestartBudget) error {
+	if condition := integration.Status.GetCondition(v1.IntegrationConditionRestartBudgetExceeded); condition != nil && condition.Status == corev1.ConditionTrue {
+		if budget.Action != trait.RestartBudgetActionNotify {
+			integration.Status.Phase = v1.IntegrationPhaseError
+			setReadyConditionError(integration, condition.Message)
+		}
+		return nil
+	}
+
+	pod, status := findRestartBudgetExceeded(pods, budget.MaxRestarts)
+	if pod == nil {
+		return nil
+	}
+
+	message := fmt.Sprintf("Pod %s, container %s restarted %d time(s)", pod.Name, status.Name, status.RestartCount)
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		message += fmt.Sprintf(", last terminated with reason %s and exit code %d", terminated.Reason, terminated.ExitCode)
+		if terminated.Message != "" {
+			message += ": " + terminated.Message
+		}
+	}
+	message += action.collectRestartDiagnostics(ctx, pod, status.Name, budget.LogLines)
+	action.L.Info("Restart budget exceeded", "pod", pod.Name, "container", status.Name, "restarts", status.RestartCount)
+
+	reason := v1.IntegrationConditionRestartBudgetExceededReason
+	switch budget.Action {
+	case trait.RestartBudgetActionNotify:
+		integration.Status.SetCondition(v1.IntegrationConditionRestartBudgetExceeded, corev1.ConditionTrue, reason, message)
+		return nil
+	case trait.RestartBudgetActionScaleToZero:
+		if !environment.IsReconcilePaused() && isConditionTrue(integration, v1.IntegrationConditionDeploymentAvailable) {
+			if err := scaleDeploymentToZero(ctx, action.client, integration); err != nil {
+				return err
+			}
+			reason = v1.IntegrationConditionRestartBudgetScaledToZeroReason
+		}
+	}
+
+	integration.Status.Phase = v1.IntegrationPhaseError
+	integration.Status.SetCondition(v1.IntegrationConditionRestartBudgetExceeded, corev1.ConditionTrue, reason, message)
+	setReadyConditionError(integration, message)
+
+	return nil
+}
+
+// findRestartBudgetExceeded returns the first container, along with its Pod, that has been restarted more than the given
+// number of times, or nil if none exceeds it.
+func findRestartBudgetExceeded(pods []corev1.Pod, maxRestarts int32) (*corev1.Pod, *corev1.ContainerStatus) {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		var containers []corev1.ContainerStatus
+		containers = append(containers, pod.Status.InitContainerStatuses...)
+		containers = append(containers, pod.Status.ContainerStatuses...)
+		for j := range containers {
+			if containers[j].RestartCount > maxRestarts {
+				return pod, &containers[j]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// collectRestartDiagnostics returns the warning events of the Pod, and the tail of the logs of the previous instance of
+// the container. The diagnostics are collected on a best-effort basis, so that they don't hold the remediation.
+func (action *monitorAction) collectRestartDiagnostics(ctx context.Context, pod *corev1.Pod, container string, logLines int64) string {
+	var diagnostics strings.Builder
+
+	events, err := action.client.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(fields.Set{"involvedObject.name": pod.Name, "type": corev1.EventTypeWarning}).String(),
+	})
+	if err != nil {
+		action.L.Error(err, "Cannot list the events of Pod", "pod", pod.Name)
+	} else {
+		var warnings []corev1.Event
+		for _, event := range events.Items {
+			if event.InvolvedObject.Name == pod.Name && event.Type == corev1.EventTypeWarning {
+				warnings = append(warnings, event)
+			}
+		}
+		if len(warnings) > restartBudgetMaxEvents {
+			warnings = warnings[len(warnings)-restartBudgetMaxEvents:]
+		}
+		if len(warnings) > 0 {
+			diagnostics.WriteString("\nWarning events:")
+			for _, event := range warnings {
+				fmt.Fprintf(&diagnostics, "\n  %s: %s", event.Reason, event.Message)
+			}
+		}
+	}
+
+	if logLines == 0 {
+		return diagnostics.String()
+	}
+	stream, err := action.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  true,
+		TailLines: &logLines,
+	}).Stream(ctx)
+	if err != nil {
+		action.L.Error(err, "Cannot retrieve the logs of Pod", "pod", pod.Name, "container", container)
+		return diagnostics.String()
+	}
+	defer stream.Close()
+	diagnostics.WriteString("\nLast logs:")
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintf(&diagnostics, "\n  %s", scanner.Text())
+	}
+
+	return diagnostics.String()
+}
+
+// scaleDeploymentToZero scales the Integration Deployment to zero replicas.
+func scaleDeploymentToZero(ctx context.Context, c ctrl.Client, integration *v1.Integration) error {
+	deployment := appsv1.Deployment{}
+	if err := c.Get(ctx, ctrl.ObjectKey{Namespace: integration.Namespace, Name: integration.Name}, &deployment); err != nil {
+		return err
+	}
+	target := deployment.DeepCopy()
+	target.Spec.Replicas = pointer.Int32(0)
+	return c.Patch(ctx, target, ctrl.MergeFrom(&deployment))
+}
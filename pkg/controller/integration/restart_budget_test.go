@@ -0,0 +1,167 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestCheckRestartBudgetWithinBudget(t *testing.T) {
+	action, environment, integration := createRestartBudgetTest(t)
+	budget := newRestartBudget(trait.RestartBudgetActionNotify)
+
+	err := action.applyRestartBudget(context.TODO(), environment, integration, []corev1.Pod{newRestartBudgetPod(5)}, budget)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.IntegrationPhaseRunning, integration.Status.Phase)
+	assert.Nil(t, integration.Status.GetCondition(v1.IntegrationConditionRestartBudgetExceeded))
+}
+
+func TestCheckRestartBudgetNotifies(t *testing.T) {
+	action, environment, integration := createRestartBudgetTest(t)
+	budget := newRestartBudget(trait.RestartBudgetActionNotify)
+
+	err := action.applyRestartBudget(context.TODO(), environment, integration, []corev1.Pod{newRestartBudgetPod(6)}, budget)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.IntegrationPhaseRunning, integration.Status.Phase)
+	condition := integration.Status.GetCondition(v1.IntegrationConditionRestartBudgetExceeded)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionRestartBudgetExceededReason, condition.Reason)
+	assert.Contains(t, condition.Message, "Pod my-integration-1, container integration restarted 6 time(s), last terminated with reason Error and exit code 1")
+	assert.Contains(t, condition.Message, "Warning events:\n  BackOff: Back-off restarting failed container")
+	assert.Contains(t, condition.Message, "Last logs:")
+}
+
+func TestCheckRestartBudgetMovesToError(t *testing.T) {
+	action, environment, integration := createRestartBudgetTest(t)
+	budget := newRestartBudget(trait.RestartBudgetActionError)
+
+	err := action.applyRestartBudget(context.TODO(), environment, integration, []corev1.Pod{newRestartBudgetPod(6)}, budget)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.IntegrationPhaseError, integration.Status.Phase)
+	assert.Equal(t, v1.IntegrationConditionErrorReason, integration.Status.GetCondition(v1.IntegrationConditionReady).Reason)
+
+	// The Integration is held in error, even when the Pods are restarted successfully
+	integration.Status.Phase = v1.IntegrationPhaseRunning
+	err = action.applyRestartBudget(context.TODO(), environment, integration, nil, budget)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.IntegrationPhaseError, integration.Status.Phase)
+}
+
+func TestCheckRestartBudgetScalesToZero(t *testing.T) {
+	action, environment, integration := createRestartBudgetTest(t)
+	budget := newRestartBudget(trait.RestartBudgetActionScaleToZero)
+	integration.Status.SetCondition(v1.IntegrationConditionDeploymentAvailable, corev1.ConditionTrue,
+		v1.IntegrationConditionDeploymentAvailableReason, "")
+
+	err := action.applyRestartBudget(context.TODO(), environment, integration, []corev1.Pod{newRestartBudgetPod(6)}, budget)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.IntegrationPhaseError, integration.Status.Phase)
+	assert.Equal(t, v1.IntegrationConditionRestartBudgetScaledToZeroReason,
+		integration.Status.GetCondition(v1.IntegrationConditionRestartBudgetExceeded).Reason)
+
+	deployment := appsv1.Deployment{}
+	err = action.client.Get(context.TODO(), ctrl.ObjectKey{Namespace: "ns", Name: "my-integration"}, &deployment)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+}
+
+func createRestartBudgetTest(t *testing.T) (*monitorAction, *trait.Environment, *v1.Integration) {
+	t.Helper()
+
+	c, err := test.NewFakeClient([]runtime.Object{
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "my-integration",
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32(1),
+			},
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "my-integration-1.backoff",
+			},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "ns", Name: "my-integration-1"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			Message:        "Back-off restarting failed container",
+		},
+	}...)
+	assert.Nil(t, err)
+
+	monitor := &monitorAction{}
+	monitor.InjectLogger(log.Log)
+	monitor.InjectClient(c)
+
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Status.Phase = v1.IntegrationPhaseRunning
+
+	environment := &trait.Environment{
+		Integration: &integration,
+	}
+
+	return monitor, environment, &integration
+}
+
+func newRestartBudget(action trait.RestartBudgetAction) *trait.RestartBudget {
+	return &trait.RestartBudget{
+		MaxRestarts: 5,
+		Action:      action,
+		LogLines:    20,
+	}
+}
+
+func newRestartBudgetPod(restarts int32) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "my-integration-1",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:         "integration",
+					RestartCount: restarts,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+const (
+	knativeRevisionLabel = "serving.knative.dev/revision"
+
+	// The metrics exposed by the Prometheus trait
+	metricsPath                = "/q/metrics"
+	metricsScrapeTimeout       = 5 * time.Second
+	routeExchangesMetric       = "application_camel_route_exchanges_total"
+	routeFailedExchangesMetric = "application_camel_route_exchanges_failed_total"
+)
+
+// splitCanaryPods separates the Pods of the canary revision of the Integration, being rolled out,
+// from the Pods of its stable revision.
+func splitCanaryPods(integration *v1.Integration, pods []corev1.Pod) ([]corev1.Pod, []corev1.Pod) {
+	rollout := integration.Status.Rollout
+	if rollout == nil {
+		return pods, nil
+	}
+
+	stable := make([]corev1.Pod, 0, len(pods))
+	var canary []corev1.Pod
+	for _, pod := range pods {
+		if pod.Labels[v1.IntegrationRolloutCanaryLabel] == trait.True ||
+			rollout.CanaryRevision != "" && pod.Labels[knativeRevisionLabel] == rollout.CanaryRevision {
+			canary = append(canary, pod)
+		} else {
+			stable = append(stable, pod)
+		}
+	}
+
+	return stable, canary
+}
+
+// checkRollout evaluates the health of the canary Pods of the Integration revision being rolled out,
+// and, at the end of each step, either moves the rollout to its next step, promotes the canary, or rolls it back.
+func (action *monitorAction) checkRollout(environment *trait.Environment, integration *v1.Integration, canaryPods []corev1.Pod) {
+	rollout := environment.GetRollout()
+	if rollout == nil {
+		integration.Status.Rollout = nil
+		return
+	}
+	status := integration.Status.Rollout
+	if status == nil || status.Phase != v1.RolloutPhaseProgressing {
+		return
+	}
+
+	pods, ready, restarts := canaryPodsHealth(canaryPods, environment.GetIntegrationContainerName())
+	status.CanaryReadyReplicas = ready
+	status.CanaryRestarts = restarts
+
+	// Neither roll back nor progress the rollout while the reconciliation is paused
+	if environment.IsReconcilePaused() {
+		return
+	}
+
+	if restarts > rollout.MaxRestarts {
+		action.rollBack(integration, fmt.Sprintf("the canary Integration containers restarted %d time(s), more than the %d allowed", restarts, rollout.MaxRestarts))
+		return
+	}
+	if rollout.MaxFailureRate != nil && status.CanaryFailedExchanges*100 > status.CanaryExchanges*int64(*rollout.MaxFailureRate) {
+		action.rollBack(integration, fmt.Sprintf("the canary routes failed %d/%d exchange(s), more than the %d%% allowed", status.CanaryFailedExchanges, status.CanaryExchanges, *rollout.MaxFailureRate))
+		return
+	}
+
+	now := time.Now()
+	if status.StepStartTime != nil && now.Before(status.StepStartTime.Add(rollout.Interval)) {
+		return
+	}
+	// The canary Knative revision may have been scaled down to zero
+	if ready < pods || (pods == 0 && isConditionTrue(integration, v1.IntegrationConditionDeploymentAvailable)) {
+		action.rollBack(integration, fmt.Sprintf("%d/%d canary Pod(s) ready after %s", ready, pods, rollout.Interval))
+		return
+	}
+
+	next := status.Step + 1
+	if int(next) >= len(rollout.Steps) {
+		action.L.Info("Canary promoted", "digest", status.CanaryDigest)
+		status.Phase = v1.RolloutPhasePromoted
+		status.StableDigest = status.CanaryDigest
+		if status.CanaryRevision != "" {
+			status.StableRevision = status.CanaryRevision
+		}
+		status.CanaryRevision = ""
+		status.Weight = 100
+		status.StepStartTime = nil
+		status.Message = fmt.Sprintf("the canary has been promoted after %d step(s)", len(rollout.Steps))
+		return
+	}
+
+	stepStartTime := metav1.NewTime(now)
+	status.Step = next
+	status.Weight = rollout.Steps[next]
+	status.StepStartTime = &stepStartTime
+}
+
+func (action *monitorAction) rollBack(integration *v1.Integration, message string) {
+	action.L.Info("Canary rolled back", "message", message)
+
+	status := integration.Status.Rollout
+	status.Phase = v1.RolloutPhaseRolledBack
+	status.Weight = 0
+	status.StepStartTime = nil
+	status.Message = message
+}
+
+// canaryPodsHealth returns the number of canary Pods, not being terminated, along with the number of those that are ready,
+// and the total number of restarts of their Integration container.
+func canaryPodsHealth(pods []corev1.Pod, containerName string) (int32, int32, int32) {
+	var count, ready, restarts int32
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		count++
+		if condition := kubernetes.GetPodCondition(*pod, corev1.PodReady); condition != nil && condition.Status == corev1.ConditionTrue {
+			ready++
+		}
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name == containerName {
+				restarts += container.RestartCount
+			}
+		}
+	}
+
+	return count, ready, restarts
+}
+
+// countCanaryExchanges reports, in the rollout status, the number of exchanges processed and failed by the routes
+// of the ready canary Pods, when the rollout has a failure rate threshold. The exchanges are counted from the metrics
+// exposed by the Prometheus trait, and the previous counts are kept when the metrics cannot be scraped.
+func (action *monitorAction) countCanaryExchanges(ctx context.Context, environment *trait.Environment, integration *v1.Integration, canaryPods []corev1.Pod) {
+	rollout := environment.GetRollout()
+	status := integration.Status.Rollout
+	if rollout == nil || rollout.MaxFailureRate == nil || status == nil || status.Phase != v1.RolloutPhaseProgressing {
+		return
+	}
+
+	var exchanges, failed int64
+	for i := range canaryPods {
+		pod := &canaryPods[i]
+		if condition := kubernetes.GetPodCondition(*pod, corev1.PodReady); pod.DeletionTimestamp != nil || condition == nil || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		body, err := action.scrapeMetrics(ctx, environment, pod)
+		if err != nil {
+			action.L.Info("Unable to scrape the canary metrics", "pod", pod.Name, "error", err.Error())
+			return
+		}
+		total, failures, err := countExchanges(body)
+		if err != nil {
+			action.L.Info("Unable to parse the canary metrics", "pod", pod.Name, "error", err.Error())
+			return
+		}
+		exchanges += total
+		failed += failures
+	}
+
+	status.CanaryExchanges = exchanges
+	status.CanaryFailedExchanges = failed
+}
+
+func (action *monitorAction) scrapeMetrics(ctx context.Context, environment *trait.Environment, pod *corev1.Pod) ([]byte, error) {
+	container := getIntegrationContainer(environment, pod)
+	if container == nil || len(container.Ports) == 0 {
+		return nil, fmt.Errorf("integration container port not found in Pod %s/%s", pod.Namespace, pod.Name)
+	}
+
+	scrapeCtx, cancel := context.WithTimeout(ctx, metricsScrapeTimeout)
+	defer cancel()
+	return action.client.CoreV1().Pods(pod.Namespace).
+		ProxyGet("http", pod.Name, strconv.Itoa(int(container.Ports[0].ContainerPort)), metricsPath, nil).
+		DoRaw(scrapeCtx)
+}
+
+// countExchanges returns the number of exchanges processed, and failed, by all the routes, from the Prometheus metrics.
+func countExchanges(metrics []byte) (int64, int64, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return sumMetric(families[routeExchangesMetric]), sumMetric(families[routeFailedExchangesMetric]), nil
+}
+
+func sumMetric(family *dto.MetricFamily) int64 {
+	var sum float64
+	for _, metric := range family.GetMetric() {
+		sum += metric.GetCounter().GetValue() + metric.GetUntyped().GetValue()
+	}
+	return int64(sum)
+}
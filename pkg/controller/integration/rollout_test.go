@@ -0,0 +1,217 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+   http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/log"
+)
+
+func TestSplitCanaryPods(t *testing.T) {
+	integration := v1.NewIntegration("ns", "my-integration")
+	pods := []corev1.Pod{
+		newRolloutPod("stable", nil, true, 0),
+		newRolloutPod("canary", map[string]string{v1.IntegrationRolloutCanaryLabel: "true"}, true, 0),
+		newRolloutPod("revision", map[string]string{knativeRevisionLabel: "my-integration-00002"}, true, 0),
+	}
+
+	stable, canary := splitCanaryPods(&integration, pods)
+	assert.Len(t, stable, 3)
+	assert.Empty(t, canary)
+
+	integration.Status.Rollout = &v1.RolloutStatus{
+		Phase:          v1.RolloutPhaseProgressing,
+		CanaryRevision: "my-integration-00002",
+	}
+	stable, canary = splitCanaryPods(&integration, pods)
+	assert.Len(t, stable, 1)
+	assert.Equal(t, "stable", stable[0].Name)
+	assert.Len(t, canary, 2)
+}
+
+func TestCheckRolloutMovesToNextStep(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 0, time.Minute+time.Second)
+
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, true, 0)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseProgressing, rollout.Phase)
+	assert.Equal(t, int32(1), rollout.Step)
+	assert.Equal(t, int32(25), rollout.Weight)
+	assert.Equal(t, int32(1), rollout.CanaryReadyReplicas)
+}
+
+func TestCheckRolloutWaitsForStepEnd(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 0, time.Second)
+
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, false, 0)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseProgressing, rollout.Phase)
+	assert.Equal(t, int32(0), rollout.Step)
+	assert.Equal(t, int32(0), rollout.CanaryReadyReplicas)
+}
+
+func TestCheckRolloutPromotesCanary(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 2, time.Minute+time.Second)
+
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, true, 0)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhasePromoted, rollout.Phase)
+	assert.Equal(t, "v2", rollout.StableDigest)
+	assert.Equal(t, "my-integration-00002", rollout.StableRevision)
+	assert.Empty(t, rollout.CanaryRevision)
+	assert.Equal(t, int32(100), rollout.Weight)
+	assert.Nil(t, rollout.StepStartTime)
+}
+
+func TestCheckRolloutRollsBackRestartingCanary(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 1, time.Second)
+
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, true, 1)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseRolledBack, rollout.Phase)
+	assert.Equal(t, "v1", rollout.StableDigest)
+	assert.Equal(t, int32(0), rollout.Weight)
+	assert.Equal(t, int32(1), rollout.CanaryRestarts)
+	assert.Contains(t, rollout.Message, "restarted 1 time(s)")
+}
+
+func TestCheckRolloutRollsBackUnreadyCanary(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 0, time.Minute+time.Second)
+
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, false, 0)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseRolledBack, rollout.Phase)
+	assert.Equal(t, "0/1 canary Pod(s) ready after 1m0s", rollout.Message)
+}
+
+func TestCheckRolloutRollsBackFailingCanary(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 0, time.Second)
+	assert.Nil(t, json.Unmarshal([]byte(`{"maxFailureRate": 10}`), environment.Catalog.GetTrait("rollout")))
+
+	integration.Status.Rollout.CanaryExchanges = 100
+	integration.Status.Rollout.CanaryFailedExchanges = 10
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, true, 0)})
+	assert.Equal(t, v1.RolloutPhaseProgressing, integration.Status.Rollout.Phase)
+
+	integration.Status.Rollout.CanaryFailedExchanges = 11
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, true, 0)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseRolledBack, rollout.Phase)
+	assert.Equal(t, "the canary routes failed 11/100 exchange(s), more than the 10% allowed", rollout.Message)
+}
+
+func TestCountExchanges(t *testing.T) {
+	metrics := `# HELP application_camel_route_exchanges_total The total number of exchanges for a route
+# TYPE application_camel_route_exchanges_total counter
+application_camel_route_exchanges_total{camelContext="camel-1",routeId="orders"} 40.0
+application_camel_route_exchanges_total{camelContext="camel-1",routeId="payments"} 10.0
+# HELP application_camel_route_exchanges_failed_total The total number of failed exchanges for a route
+# TYPE application_camel_route_exchanges_failed_total counter
+application_camel_route_exchanges_failed_total{camelContext="camel-1",routeId="orders"} 3.0
+application_camel_route_exchanges_failed_total{camelContext="camel-1",routeId="payments"} 2.0
+`
+
+	exchanges, failed, err := countExchanges([]byte(metrics))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), exchanges)
+	assert.Equal(t, int64(5), failed)
+
+	exchanges, failed, err = countExchanges([]byte("# TYPE base_thread_count gauge\nbase_thread_count 12.0\n"))
+	assert.Nil(t, err)
+	assert.Zero(t, exchanges)
+	assert.Zero(t, failed)
+}
+
+func TestCheckRolloutHoldsPausedIntegration(t *testing.T) {
+	action, environment, integration := createRolloutTest(t, 0, time.Minute+time.Second)
+	integration.Annotations = map[string]string{v1.ReconcileAnnotation: v1.ReconcilePaused}
+	environment.Integration = integration
+
+	action.checkRollout(environment, integration, []corev1.Pod{newRolloutPod("canary", nil, false, 2)})
+
+	rollout := integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseProgressing, rollout.Phase)
+	assert.Equal(t, int32(0), rollout.Step)
+	assert.Equal(t, int32(10), rollout.Weight)
+	assert.Equal(t, int32(2), rollout.CanaryRestarts)
+}
+
+func createRolloutTest(t *testing.T, step int32, elapsed time.Duration) (*monitorAction, *trait.Environment, *v1.Integration) {
+	t.Helper()
+
+	action := &monitorAction{}
+	action.InjectLogger(log.Log)
+
+	catalog := trait.NewCatalog(nil)
+	environment := &trait.Environment{
+		Catalog:        catalog,
+		ExecutedTraits: []trait.Trait{catalog.GetTrait("rollout")},
+	}
+
+	stepStartTime := metav1.NewTime(time.Now().Add(-elapsed))
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Status.Rollout = &v1.RolloutStatus{
+		Phase:          v1.RolloutPhaseProgressing,
+		StableDigest:   "v1",
+		StableRevision: "my-integration-00001",
+		CanaryDigest:   "v2",
+		CanaryRevision: "my-integration-00002",
+		Step:           step,
+		Weight:         []int32{10, 25, 50}[step],
+		StepStartTime:  &stepStartTime,
+	}
+
+	return action, environment, &integration
+}
+
+func newRolloutPod(name string, labels map[string]string, ready bool, restarts int32) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Labels:    labels,
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: status},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "integration", RestartCount: restarts},
+			},
+		},
+	}
+}
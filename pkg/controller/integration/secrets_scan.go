@@ -0,0 +1,104 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/credentials"
+	"github.com/apache/camel-k/pkg/util/dsl"
+	"github.com/apache/camel-k/pkg/util/gzip"
+)
+
+// scanSecrets detects the credentials hard-coded into the Integration sources, and reports them according to the
+// policy of the platform. It returns whether the Integration must be blocked.
+func (action *initializeAction) scanSecrets(ctx context.Context, integration *v1.Integration) (bool, error) {
+	policy := v1.SecretsScanPolicyWarn
+	pl, err := platform.GetForResource(ctx, action.client, integration)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+	if pl != nil && pl.Status.SecretsScan.Policy != "" {
+		policy = pl.Status.SecretsScan.Policy
+	}
+	if policy == v1.SecretsScanPolicyIgnore {
+		return false, nil
+	}
+
+	findings, err := findInlineCredentials(integration)
+	if err != nil {
+		return false, err
+	}
+	if len(findings) == 0 {
+		integration.Status.SetCondition(v1.IntegrationConditionSecretsScanPassed, corev1.ConditionTrue,
+			v1.IntegrationConditionSecretsScanPassedReason, "")
+		return false, nil
+	}
+
+	message := fmt.Sprintf("inline credentials found (%s), store them into Secrets and reference them with property placeholders instead",
+		strings.Join(findings, ", "))
+	integration.Status.SetCondition(v1.IntegrationConditionSecretsScanPassed, corev1.ConditionFalse,
+		v1.IntegrationConditionInlineCredentialsReason, message)
+	action.L.ForIntegration(integration).Info("Inline credentials found", "findings", findings, "policy", policy)
+
+	if policy == v1.SecretsScanPolicyBlock {
+		integration.Status.Phase = v1.IntegrationPhaseError
+		setReadyConditionError(integration, message)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// findInlineCredentials returns the likely inline credentials found in the sources and the flows of the Integration,
+// each prefixed with the name of the source it has been found in.
+func findInlineCredentials(integration *v1.Integration) ([]string, error) {
+	var findings []string
+
+	for _, source := range integration.Spec.Sources {
+		content := []byte(source.Content)
+		if source.Compression {
+			var err error
+			if content, err = gzip.UncompressBase64(content); err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range credentials.Scan(string(content)) {
+			findings = append(findings, fmt.Sprintf("%s: %s", source.Name, f))
+		}
+	}
+
+	if len(integration.Spec.Flows) > 0 {
+		flows, err := dsl.ToYamlDSL(integration.Spec.Flows)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range credentials.Scan(string(flows)) {
+			findings = append(findings, fmt.Sprintf("flows: %s", f))
+		}
+	}
+
+	return findings, nil
+}
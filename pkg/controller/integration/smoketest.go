@@ -0,0 +1,174 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// checkSmokeTest calls the smoke test probes on the ready Integration Pods, once the Integration is ready,
+// and holds the Integration in the deploying phase until they all succeed.
+func (action *monitorAction) checkSmokeTest(ctx context.Context, environment *trait.Environment, integration *v1.Integration, runningPods []corev1.Pod) error {
+	probes := environment.GetSmokeTestProbes()
+	if len(probes) == 0 || isConditionTrue(integration, v1.IntegrationConditionCronJobAvailable) ||
+		isConditionTrue(integration, v1.IntegrationConditionJobAvailable) {
+		return nil
+	}
+
+	if condition := integration.Status.GetCondition(v1.IntegrationConditionSmokeTestPassed); condition != nil {
+		switch condition.Status {
+		case corev1.ConditionTrue:
+			return nil
+		case corev1.ConditionFalse:
+			// The smoke test is only run again once the Integration is updated
+			integration.Status.Phase = v1.IntegrationPhaseError
+			setReadyConditionError(integration, condition.Message)
+			return nil
+		}
+	}
+
+	if integration.Status.Phase != v1.IntegrationPhaseRunning {
+		return nil
+	}
+	if !isConditionTrue(integration, v1.IntegrationConditionReady) {
+		integration.Status.Phase = v1.IntegrationPhaseDeploying
+		return nil
+	}
+
+	controller, err := action.newController(ctx, environment, integration)
+	if err != nil {
+		return err
+	}
+	readyPods, _ := filterPodsByReadyStatus(runningPods, controller.getPodSpec())
+	if len(readyPods) == 0 {
+		integration.Status.Phase = v1.IntegrationPhaseDeploying
+		return nil
+	}
+
+	for i := range readyPods {
+		pod := &readyPods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		container := getIntegrationContainer(environment, pod)
+		if container == nil {
+			return fmt.Errorf("integration container not found in Pod %s/%s", pod.Namespace, pod.Name)
+		}
+		for j := range probes {
+			probe := &probes[j]
+			if _, err := proxyGetHTTPProbe(ctx, action.client, probe, pod, container); err != nil {
+				message := fmt.Sprintf("smoke test probe %s failed for Pod %s/%s: %s", probe.HTTPGet.Path, pod.Namespace, pod.Name, err.Error())
+				if errors.Is(err, context.DeadlineExceeded) {
+					message = fmt.Sprintf("smoke test probe %s timed out for Pod %s/%s", probe.HTTPGet.Path, pod.Namespace, pod.Name)
+				}
+				return action.failSmokeTest(ctx, environment, integration, message)
+			}
+		}
+	}
+
+	integration.Status.SetCondition(v1.IntegrationConditionSmokeTestPassed, corev1.ConditionTrue,
+		v1.IntegrationConditionSmokeTestPassedReason, fmt.Sprintf("%d/%d probes succeeded for %d Pod(s)", len(probes), len(probes), len(readyPods)))
+
+	return nil
+}
+
+func (action *monitorAction) failSmokeTest(ctx context.Context, environment *trait.Environment, integration *v1.Integration, message string) error {
+	action.L.Info("Smoke test failed", "message", message)
+
+	reason := v1.IntegrationConditionSmokeTestFailedReason
+	if environment.IsSmokeTestRollbackEnabled() && !environment.IsReconcilePaused() &&
+		isConditionTrue(integration, v1.IntegrationConditionDeploymentAvailable) {
+		revision, err := rollbackDeployment(ctx, action.client, integration)
+		if err != nil {
+			return err
+		}
+		if revision > 0 {
+			reason = v1.IntegrationConditionSmokeTestRolledBackReason
+			message = fmt.Sprintf("%s, rolled back to revision %d", message, revision)
+		}
+	}
+
+	integration.Status.Phase = v1.IntegrationPhaseError
+	integration.Status.SetCondition(v1.IntegrationConditionSmokeTestPassed, corev1.ConditionFalse, reason, message)
+	setReadyConditionError(integration, message)
+
+	return nil
+}
+
+// rollbackDeployment restores the Pod template of the previous revision of the Integration Deployment.
+// It returns the restored revision, or 0 if no previous revision exists.
+func rollbackDeployment(ctx context.Context, c client.Client, integration *v1.Integration) (int64, error) {
+	deployment := appsv1.Deployment{}
+	if err := c.Get(ctx, ctrl.ObjectKey{Namespace: integration.Namespace, Name: integration.Name}, &deployment); err != nil {
+		return 0, err
+	}
+	current, err := strconv.ParseInt(deployment.Annotations[deploymentRevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0, nil
+	}
+
+	replicaSets := appsv1.ReplicaSetList{}
+	err = c.List(ctx, &replicaSets,
+		ctrl.InNamespace(integration.Namespace),
+		ctrl.MatchingLabels{v1.IntegrationLabel: integration.Name})
+	if err != nil {
+		return 0, err
+	}
+
+	var previous *appsv1.ReplicaSet
+	var revision int64
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if owner := metav1.GetControllerOf(rs); owner == nil || owner.UID != deployment.UID {
+			continue
+		}
+		r, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+		if err != nil || r >= current || r <= revision {
+			continue
+		}
+		previous = rs
+		revision = r
+	}
+	if previous == nil {
+		return 0, nil
+	}
+
+	target := deployment.DeepCopy()
+	target.Spec.Template = *previous.Spec.Template.DeepCopy()
+	delete(target.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	if err := c.Patch(ctx, target, ctrl.MergeFrom(&deployment)); err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+// checkDependencies reports the Integration Pods that wait for the dependencies declared with the wait-for trait,
+// and moves the Integration into the error phase when the dependencies are still unreachable after the timeout.
+func checkDependencies(environment *trait.Environment, integration *v1.Integration, pendingPods []corev1.Pod) {
+	name := environment.GetWaitForContainerName()
+	if name == "" {
+		integration.Status.RemoveCondition(v1.IntegrationConditionDependenciesReachable)
+		return
+	}
+
+	var waiting []string
+	for _, pod := range pendingPods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != name || status.Ready {
+				continue
+			}
+			if message := unreachableDependencies(status); message != "" {
+				message = fmt.Sprintf("%s, in Pod %s", message, pod.Name)
+				integration.Status.Phase = v1.IntegrationPhaseError
+				integration.Status.SetCondition(v1.IntegrationConditionDependenciesReachable, corev1.ConditionFalse,
+					v1.IntegrationConditionDependenciesUnreachableReason, message)
+				setReadyConditionError(integration, message)
+				return
+			}
+			waiting = append(waiting, pod.Name)
+		}
+	}
+
+	if len(waiting) > 0 {
+		integration.Status.SetCondition(v1.IntegrationConditionDependenciesReachable, corev1.ConditionFalse,
+			v1.IntegrationConditionDependenciesWaitingReason,
+			fmt.Sprintf("%d Pod(s) waiting for the dependencies to be reachable: %s", len(waiting), strings.Join(waiting, ",")))
+		return
+	}
+
+	integration.Status.SetCondition(v1.IntegrationConditionDependenciesReachable, corev1.ConditionTrue,
+		v1.IntegrationConditionDependenciesReachableReason, "")
+}
+
+// unreachableDependencies returns the termination message of the wait-for container if it timed out,
+// either in its current or in its last state, when it's restarted by the kubelet.
+func unreachableDependencies(status corev1.ContainerStatus) string {
+	terminated := status.State.Terminated
+	if terminated == nil && status.State.Waiting != nil {
+		terminated = status.LastTerminationState.Terminated
+	}
+	if terminated == nil || terminated.ExitCode == 0 {
+		return ""
+	}
+	if message := strings.TrimSpace(terminated.Message); message != "" {
+		return message
+	}
+	return fmt.Sprintf("dependencies unreachable (exit code %d)", terminated.ExitCode)
+}
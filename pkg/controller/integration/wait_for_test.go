@@ -0,0 +1,98 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/trait"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	environment := &trait.Environment{
+		ExecutedTraits: []trait.Trait{trait.NewCatalog(nil).GetTrait("wait-for")},
+	}
+	integration := &v1.Integration{
+		Status: v1.IntegrationStatus{
+			Phase: v1.IntegrationPhaseRunning,
+		},
+	}
+
+	waiting := waitForPod("pod-1", corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, corev1.ContainerState{})
+	checkDependencies(environment, integration, []corev1.Pod{waiting})
+
+	condition := integration.Status.GetCondition(v1.IntegrationConditionDependenciesReachable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionDependenciesWaitingReason, condition.Reason)
+	assert.Equal(t, "1 Pod(s) waiting for the dependencies to be reachable: pod-1", condition.Message)
+	assert.Equal(t, v1.IntegrationPhaseRunning, integration.Status.Phase)
+
+	timedOut := waitForPod("pod-2",
+		corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "my-broker:61616 is unreachable after 300s\n"}})
+	checkDependencies(environment, integration, []corev1.Pod{waiting, timedOut})
+
+	condition = integration.Status.GetCondition(v1.IntegrationConditionDependenciesReachable)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionDependenciesUnreachableReason, condition.Reason)
+	assert.Equal(t, "my-broker:61616 is unreachable after 300s, in Pod pod-2", condition.Message)
+	assert.Equal(t, v1.IntegrationPhaseError, integration.Status.Phase)
+	assert.Equal(t, condition.Message, integration.Status.GetCondition(v1.IntegrationConditionReady).Message)
+
+	checkDependencies(environment, integration, nil)
+
+	condition = integration.Status.GetCondition(v1.IntegrationConditionDependenciesReachable)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1.IntegrationConditionDependenciesReachableReason, condition.Reason)
+}
+
+func TestCheckDependenciesDisabled(t *testing.T) {
+	integration := &v1.Integration{}
+	integration.Status.SetCondition(v1.IntegrationConditionDependenciesReachable, corev1.ConditionTrue,
+		v1.IntegrationConditionDependenciesReachableReason, "")
+
+	checkDependencies(&trait.Environment{}, integration, nil)
+
+	assert.Nil(t, integration.Status.GetCondition(v1.IntegrationConditionDependenciesReachable))
+}
+
+func waitForPod(name string, state corev1.ContainerState, lastState corev1.ContainerState) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:                 "wait-for",
+					State:                state,
+					LastTerminationState: lastState,
+				},
+			},
+		},
+	}
+}
@@ -19,6 +19,8 @@ package integrationkit
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -29,9 +31,11 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/trait"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 )
@@ -65,7 +69,12 @@ func (action *buildAction) Handle(ctx context.Context, kit *v1.IntegrationKit) (
 }
 
 func (action *buildAction) handleBuildSubmitted(ctx context.Context, kit *v1.IntegrationKit) (*v1.IntegrationKit, error) {
-	build, err := kubernetes.GetBuild(ctx, action.client, kit.Name, kit.Namespace)
+	key, err := action.buildKey(ctx, kit)
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := kubernetes.GetBuild(ctx, action.client, key.Name, key.Namespace)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return nil, err
 	}
@@ -84,8 +93,21 @@ func (action *buildAction) handleBuildSubmitted(ctx context.Context, kit *v1.Int
 			return nil, errors.New("undefined camel catalog")
 		}
 
+		farm := env.Platform.Status.Build.BuildFarm
+
+		// The kits of a tenant are built in its namespace, where the builder Pod needs its service account
+		if env.Tenant != nil && farm == nil && env.Platform.Status.Build.BuildStrategy == v1.BuildStrategyPod {
+			if err := env.Tenant.CreateBuilderServiceAccount(ctx, action.client, env.Platform); err != nil {
+				return nil, errors.Wrap(err, "cannot ensure the builder service account of the tenant")
+			}
+		}
+
 		labels := kubernetes.FilterCamelCreatorLabels(kit.Labels)
 		labels[v1.IntegrationKitLayoutLabel] = kit.Labels[v1.IntegrationKitLayoutLabel]
+		if farm != nil {
+			labels[v1.IntegrationKitNameLabel] = kit.Name
+			labels[v1.IntegrationKitNamespaceLabel] = kit.Namespace
+		}
 
 		annotations := make(map[string]string)
 		if v, ok := kit.Annotations[v1.PlatformSelectorAnnotation]; ok {
@@ -109,8 +131,8 @@ func (action *buildAction) handleBuildSubmitted(ctx context.Context, kit *v1.Int
 				Kind:       v1.BuildKind,
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace:   kit.Namespace,
-				Name:        kit.Name,
+				Namespace:   key.Namespace,
+				Name:        key.Name,
 				Labels:      labels,
 				Annotations: annotations,
 			},
@@ -118,12 +140,21 @@ func (action *buildAction) handleBuildSubmitted(ctx context.Context, kit *v1.Int
 				Strategy: env.Platform.Status.Build.BuildStrategy,
 				Tasks:    env.BuildTasks,
 				Timeout:  timeout,
+				Proxy:    env.Platform.Status.Proxy,
 			},
 		}
 
-		// Set the integration kit instance as the owner and controller
-		if err := controllerutil.SetControllerReference(kit, build, action.client.GetScheme()); err != nil {
-			return nil, err
+		if farm != nil {
+			build.Spec.NodeSelector = farm.NodeSelector
+			build.Spec.Tolerations = farm.Tolerations
+		}
+
+		// Set the integration kit instance as the owner and controller, which cannot cross namespaces,
+		// so the Builds run into the farm are deleted once completed instead
+		if build.Namespace == kit.Namespace {
+			if err := controllerutil.SetControllerReference(kit, build, action.client.GetScheme()); err != nil {
+				return nil, err
+			}
 		}
 
 		err = action.client.Delete(ctx, build)
@@ -146,7 +177,12 @@ func (action *buildAction) handleBuildSubmitted(ctx context.Context, kit *v1.Int
 }
 
 func (action *buildAction) handleBuildRunning(ctx context.Context, kit *v1.IntegrationKit) (*v1.IntegrationKit, error) {
-	build, err := kubernetes.GetBuild(ctx, action.client, kit.Name, kit.Namespace)
+	key, err := action.buildKey(ctx, kit)
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := kubernetes.GetBuild(ctx, action.client, key.Name, key.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +229,12 @@ func (action *buildAction) handleBuildRunning(ctx context.Context, kit *v1.Integ
 				Checksum: a.Checksum,
 			})
 		}
+		kit.Status.Vulnerabilities = build.Status.Vulnerabilities
+		kit.Status.Export = build.Status.Export
+
+		if err := action.deleteFarmBuild(ctx, kit, build); err != nil {
+			return nil, err
+		}
 
 		return kit, err
 	case v1.BuildPhaseError, v1.BuildPhaseInterrupted:
@@ -209,9 +251,96 @@ func (action *buildAction) handleBuildRunning(ctx context.Context, kit *v1.Integ
 		// Let's copy the build failure to the integration kit status
 		kit.Status.Failure = build.Status.Failure
 		kit.Status.Phase = v1.IntegrationKitPhaseError
+		// as well as the vulnerabilities, in case they have failed the build
+		kit.Status.Vulnerabilities = build.Status.Vulnerabilities
+
+		if err := action.deleteFarmBuild(ctx, kit, build); err != nil {
+			return nil, err
+		}
 
 		return kit, nil
 	}
 
 	return nil, nil
 }
+
+// buildKey returns the key of the Build of the kit, which is run into the build farm namespace
+// when the platform defines one, with a name that's unique across the kit namespaces.
+func (action *buildAction) buildKey(ctx context.Context, kit *v1.IntegrationKit) (ctrl.ObjectKey, error) {
+	key := ctrl.ObjectKey{
+		Namespace: kit.Namespace,
+		Name:      kit.Name,
+	}
+
+	pl, err := platform.GetForResource(ctx, action.client, kit)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return key, err
+	}
+	if pl != nil && pl.Status.Build.BuildFarm != nil && pl.Status.Build.BuildFarm.Namespace != kit.Namespace {
+		key.Namespace = pl.Status.Build.BuildFarm.Namespace
+		key.Name = farmBuildName(kit)
+	}
+
+	return key, nil
+}
+
+// farmBuildNameMaxLength keeps the name of the builder Pod, i.e., camel-k-<build>-builder, within 63 characters.
+const farmBuildNameMaxLength = 47
+
+// farmBuildName returns the name of the Build of the kit in the build farm namespace. The name is suffixed with
+// a hash of the kit namespace and name, as joining them may collide, e.g., a-b/c and a/b-c. The Build is
+// identified by the kit labels rather than by its name.
+func farmBuildName(kit *v1.IntegrationKit) string {
+	sum := sha256.Sum256([]byte(kit.Namespace + "/" + kit.Name))
+	hash := hex.EncodeToString(sum[:])[:10]
+
+	name := kit.Name
+	if limit := farmBuildNameMaxLength - len(hash) - 1; len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-.")
+	}
+
+	return name + "-" + hash
+}
+
+// deleteFarmBuild deletes the Build run into the build farm, once its outcome is reported to the kit,
+// as it is not garbage collected along with the kit.
+func (action *buildAction) deleteFarmBuild(ctx context.Context, kit *v1.IntegrationKit, build *v1.Build) error {
+	if build.Namespace == kit.Namespace {
+		return nil
+	}
+
+	err := action.client.Delete(ctx, build)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "cannot delete build")
+	}
+
+	return nil
+}
+
+// deleteOrphanFarmBuilds deletes the Builds run into the build farm for the deleted kit of the given key. They
+// cannot be owned by the kit from another namespace, so they are found by the kit labels, as they would otherwise
+// be orphaned when the kit is deleted before the outcome of its Build is reported.
+func deleteOrphanFarmBuilds(ctx context.Context, c ctrl.Client, key ctrl.ObjectKey) error {
+	builds := v1.NewBuildList()
+	err := c.List(ctx, &builds, ctrl.MatchingLabels{
+		v1.IntegrationKitNameLabel:      key.Name,
+		v1.IntegrationKitNamespaceLabel: key.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range builds.Items {
+		build := &builds.Items[i]
+		// The Builds in the kit namespace are garbage collected along with their owner kit
+		if build.Namespace == key.Namespace {
+			continue
+		}
+		err := c.Delete(ctx, build)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot delete build")
+		}
+	}
+
+	return nil
+}
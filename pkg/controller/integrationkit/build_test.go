@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrationkit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestFarmBuildName(t *testing.T) {
+	name := farmBuildName(v1.NewIntegrationKit("ns", "kit-c9f2o7k0m1s2bqrh8v40"))
+	assert.True(t, strings.HasPrefix(name, "kit-c9f2o7k0m1s2bqrh8v40-"))
+	assert.Equal(t, name, farmBuildName(v1.NewIntegrationKit("ns", "kit-c9f2o7k0m1s2bqrh8v40")))
+
+	// Joining the namespace and the name would collide
+	assert.NotEqual(t, farmBuildName(v1.NewIntegrationKit("a-b", "c")), farmBuildName(v1.NewIntegrationKit("a", "b-c")))
+
+	long := farmBuildName(v1.NewIntegrationKit("ns", strings.Repeat("my-very-long-kit-name", 5)))
+	assert.LessOrEqual(t, len("camel-k-"+long+"-builder"), 63)
+	assert.NotEqual(t, long, farmBuildName(v1.NewIntegrationKit("other-ns", strings.Repeat("my-very-long-kit-name", 5))))
+}
+
+func TestDeleteOrphanFarmBuilds(t *testing.T) {
+	farmBuild := func(namespace string, name string, kitNamespace string, kitName string) *v1.Build {
+		return &v1.Build{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1.SchemeGroupVersion.String(),
+				Kind:       v1.BuildKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					v1.IntegrationKitNameLabel:      kitName,
+					v1.IntegrationKitNamespaceLabel: kitNamespace,
+				},
+			},
+		}
+	}
+
+	orphan := farmBuild("farm", "kit-1-a1b2c3d4e5", "ns", "kit-1")
+	other := farmBuild("farm", "kit-2-f6a7b8c9d0", "ns", "kit-2")
+	local := farmBuild("ns", "kit-1", "ns", "kit-1")
+
+	c, err := test.NewFakeClient(orphan, other, local)
+	assert.Nil(t, err)
+
+	ctx := context.TODO()
+	err = deleteOrphanFarmBuilds(ctx, c, ctrl.ObjectKey{Namespace: "ns", Name: "kit-1"})
+	assert.Nil(t, err)
+
+	err = c.Get(ctx, ctrl.ObjectKeyFromObject(orphan), &v1.Build{})
+	assert.True(t, k8serrors.IsNotFound(err))
+	// The Build of another kit is preserved
+	assert.Nil(t, c.Get(ctx, ctrl.ObjectKeyFromObject(other), &v1.Build{}))
+	// The Build in the kit namespace is left to the garbage collector
+	assert.Nil(t, c.Get(ctx, ctrl.ObjectKeyFromObject(local), &v1.Build{}))
+
+	// Sweeping again is a no-op
+	err = deleteOrphanFarmBuilds(ctx, c, ctrl.ObjectKey{Namespace: "ns", Name: "kit-1"})
+	assert.Nil(t, err)
+}
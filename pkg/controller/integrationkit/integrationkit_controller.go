@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -134,6 +135,80 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to the Builds run into the build farm, that cannot be owned by
+	// the IntegrationKit from another namespace, and requeue the labelled IntegrationKit
+	err = c.Watch(&source.Kind{Type: &v1.Build{}},
+		handler.EnqueueRequestsFromMapFunc(func(a ctrl.Object) []reconcile.Request {
+			name, ok := a.GetLabels()[v1.IntegrationKitNameLabel]
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Namespace: a.GetLabels()[v1.IntegrationKitNamespaceLabel],
+						Name:      name,
+					},
+				},
+			}
+		}),
+		platform.FilteringFuncs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldBuild, ok := e.ObjectOld.(*v1.Build)
+				if !ok {
+					return false
+				}
+				newBuild, ok := e.ObjectNew.(*v1.Build)
+				if !ok {
+					return false
+				}
+				return oldBuild.Status.Phase != newBuild.Status.Phase
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for the Integrations changing of kit, or being deleted, and requeue the kit they used,
+	// so that the image pre-pull of the kits no longer used is removed
+	err = c.Watch(&source.Kind{Type: &v1.Integration{}},
+		handler.EnqueueRequestsFromMapFunc(func(a ctrl.Object) []reconcile.Request {
+			it, ok := a.(*v1.Integration)
+			if !ok || it.Status.IntegrationKit == nil {
+				return nil
+			}
+			return []reconcile.Request{
+				{
+					NamespacedName: types.NamespacedName{
+						Namespace: it.Status.IntegrationKit.Namespace,
+						Name:      it.Status.IntegrationKit.Name,
+					},
+				},
+			}
+		}),
+		platform.FilteringFuncs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return false
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldIntegration, ok := e.ObjectOld.(*v1.Integration)
+				if !ok {
+					return false
+				}
+				newIntegration, ok := e.ObjectNew.(*v1.Integration)
+				if !ok {
+					return false
+				}
+				// Both the previous and the new kits are requeued
+				return !equality.Semantic.DeepEqual(oldIntegration.Status.IntegrationKit, newIntegration.Status.IntegrationKit)
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
 	// Watch for IntegrationPlatform phase transitioning to ready and enqueue
 	// requests for any integration kits that are in phase waiting for platform
 	err = c.Watch(&source.Kind{Type: &v1.IntegrationPlatform{}},
@@ -211,8 +286,10 @@ func (r *reconcileIntegrationKit) Reconcile(ctx context.Context, request reconci
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// The Builds run into the build farm cannot be owned by the kit, and are deleted instead, including
+			// those requeued by the farm Builds watch on start-up, for a kit deleted while the operator was stopped.
 			// Return and don't requeue
-			return reconcile.Result{}, nil
+			return reconcile.Result{}, deleteOrphanFarmBuilds(ctx, r.client, request.NamespacedName)
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
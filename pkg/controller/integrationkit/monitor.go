@@ -20,7 +20,10 @@ package integrationkit
 import (
 	"context"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/util/digest"
 )
 
@@ -60,5 +63,19 @@ func (action *monitorAction) Handle(ctx context.Context, kit *v1.IntegrationKit)
 		return kit, nil
 	}
 
+	pl, err := platform.GetForResource(ctx, action.client, kit)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	if pl != nil {
+		tenant, err := platform.GetTenant(ctx, action.client, kit.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if err := reconcileImagePrePull(ctx, action.client, tenant.ApplyTo(pl), kit); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
@@ -0,0 +1,216 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrationkit
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+const (
+	// The image providing the statically linked busybox binary, that is run from the pre-pulled kit image to keep it
+	// in use on the node, so that it's not reclaimed by the kubelet image garbage collection, without requiring
+	// the kit image to provide a shell.
+	defaultPrePullHelperImage = "docker.io/library/busybox:1.35.0-musl"
+
+	prePullBinDir = "/prepull"
+)
+
+func prePullDaemonSetName(kit *v1.IntegrationKit) string {
+	return kit.Name + "-prepull"
+}
+
+// reconcileImagePrePull creates the DaemonSet that pre-pulls the kit image onto the nodes selected in the platform
+// configuration, as long as the kit is used by an Integration, or deletes it otherwise.
+func reconcileImagePrePull(ctx context.Context, c client.Client, platform *v1.IntegrationPlatform, kit *v1.IntegrationKit) error {
+	spec := platform.Status.Build.ImagePrePull
+
+	// Only the images built by the platform are pre-pulled
+	enabled := spec != nil && pointer.BoolDeref(spec.Enabled, false) && kit.Spec.Image == "" && kit.Status.Image != ""
+	if enabled {
+		used, err := isKitUsed(ctx, c, kit)
+		if err != nil {
+			return err
+		}
+		enabled = used
+	}
+	if !enabled {
+		return deleteImagePrePull(ctx, c, kit)
+	}
+
+	ds, err := newImagePrePullDaemonSet(ctx, c, platform, kit)
+	if err != nil {
+		return err
+	}
+
+	// Set the integration kit instance as the owner and controller
+	if err := controllerutil.SetControllerReference(kit, ds, c.GetScheme()); err != nil {
+		return err
+	}
+
+	if err := kubernetes.ReplaceResource(ctx, c, ds); err != nil {
+		return errors.Wrap(err, "cannot create image pre-pull daemon set")
+	}
+
+	return nil
+}
+
+// deleteImagePrePull deletes the DaemonSet pre-pulling the kit image, if any. It's looked up from the cache,
+// so that the API server is only called when the DaemonSet exists.
+func deleteImagePrePull(ctx context.Context, c ctrl.Client, kit *v1.IntegrationKit) error {
+	ds := appsv1.DaemonSet{}
+	if err := c.Get(ctx, ctrl.ObjectKey{Namespace: kit.Namespace, Name: prePullDaemonSetName(kit)}, &ds); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := c.Delete(ctx, &ds); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "cannot delete image pre-pull daemon set")
+	}
+
+	return nil
+}
+
+// isKitUsed returns whether the kit is used by any Integration.
+func isKitUsed(ctx context.Context, c ctrl.Reader, kit *v1.IntegrationKit) (bool, error) {
+	list := v1.NewIntegrationList()
+	if err := c.List(ctx, &list); err != nil {
+		return false, err
+	}
+	for _, it := range list.Items {
+		if ref := it.Status.IntegrationKit; ref != nil && ref.Namespace == kit.Namespace && ref.Name == kit.Name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func newImagePrePullDaemonSet(ctx context.Context, c ctrl.Reader, platform *v1.IntegrationPlatform, kit *v1.IntegrationKit) (*appsv1.DaemonSet, error) {
+	spec := platform.Status.Build.ImagePrePull
+	helperImage := spec.HelperImage
+	if helperImage == "" {
+		helperImage = defaultPrePullHelperImage
+	}
+
+	labels := map[string]string{
+		"camel.apache.org/kit":       kit.Name,
+		"camel.apache.org/component": "image-prepuller",
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "prepull",
+			MountPath: prePullBinDir,
+		},
+	}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("8Mi"),
+		},
+	}
+
+	ds := appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: kit.Namespace,
+			Name:      prePullDaemonSetName(kit),
+			Labels: kubernetes.MergeCamelCreatorLabels(kit.Labels, map[string]string{
+				"camel.apache.org/kit":        kit.Name,
+				v1.IntegrationKitPrePullLabel: kit.Name,
+			}),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector: spec.NodeSelector,
+					// Copy the busybox binary, so that it can be run from the kit image
+					InitContainers: []corev1.Container{
+						{
+							Name:         "helper",
+							Image:        helperImage,
+							Command:      []string{"/bin/busybox", "cp", "/bin/busybox", prePullBinDir + "/busybox"},
+							Resources:    resources,
+							VolumeMounts: volumeMounts,
+						},
+					},
+					// Pull the kit image, and keep it in use on the node
+					Containers: []corev1.Container{
+						{
+							Name:            "prepull",
+							Image:           kit.Status.Image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         []string{prePullBinDir + "/busybox", "sleep", "2147483647"},
+							Resources:       resources,
+							VolumeMounts:    volumeMounts,
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "prepull",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Reuse the registry secret, if it can be used to pull images
+	if secret := platform.Status.Build.Registry.Secret; secret != "" {
+		obj := corev1.Secret{}
+		if err := c.Get(ctx, ctrl.ObjectKey{Namespace: kit.Namespace, Name: secret}, &obj); err != nil && !k8serrors.IsNotFound(err) {
+			return nil, err
+		} else if err == nil && obj.Type == corev1.SecretTypeDockerConfigJson {
+			ds.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{
+				{
+					Name: secret,
+				},
+			}
+		}
+	}
+
+	return &ds, nil
+}
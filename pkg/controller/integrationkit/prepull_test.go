@@ -0,0 +1,126 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrationkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestImagePrePullOfUsedKit(t *testing.T) {
+	platform, kit := createImagePrePullTest(true)
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Status.IntegrationKit = &corev1.ObjectReference{Namespace: "ns", Name: "my-kit"}
+	c := newDeleteCountingClient(t, kit, &integration)
+
+	assert.Nil(t, reconcileImagePrePull(context.TODO(), c, platform, kit))
+
+	ds := appsv1.DaemonSet{}
+	assert.Nil(t, c.Get(context.TODO(), ctrl.ObjectKey{Namespace: "ns", Name: "my-kit-prepull"}, &ds))
+	assert.Equal(t, "my-kit", ds.Labels[v1.IntegrationKitPrePullLabel])
+	spec := ds.Spec.Template.Spec
+	assert.Equal(t, map[string]string{"node-pool": "integrations"}, spec.NodeSelector)
+	assert.Equal(t, defaultPrePullHelperImage, spec.InitContainers[0].Image)
+	assert.Equal(t, "registry/my-kit:1", spec.Containers[0].Image)
+	assert.Equal(t, []string{"/prepull/busybox", "sleep", "2147483647"}, spec.Containers[0].Command)
+
+	platform.Status.Build.ImagePrePull.HelperImage = "registry/busybox:1.35.0-musl"
+	assert.Nil(t, reconcileImagePrePull(context.TODO(), c, platform, kit))
+	assert.Nil(t, c.Get(context.TODO(), ctrl.ObjectKey{Namespace: "ns", Name: "my-kit-prepull"}, &ds))
+	assert.Equal(t, "registry/busybox:1.35.0-musl", ds.Spec.Template.Spec.InitContainers[0].Image)
+}
+
+func TestImagePrePullOfUnusedKit(t *testing.T) {
+	platform, kit := createImagePrePullTest(true)
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "my-kit-prepull",
+		},
+	}
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Status.IntegrationKit = &corev1.ObjectReference{Namespace: "ns", Name: "other-kit"}
+	c := newDeleteCountingClient(t, kit, ds, &integration)
+
+	assert.Nil(t, reconcileImagePrePull(context.TODO(), c, platform, kit))
+
+	err := c.Get(context.TODO(), ctrl.ObjectKey{Namespace: "ns", Name: "my-kit-prepull"}, &appsv1.DaemonSet{})
+	assert.True(t, k8serrors.IsNotFound(err))
+	assert.Equal(t, 1, c.deletes)
+}
+
+func TestImagePrePullDisabled(t *testing.T) {
+	platform, kit := createImagePrePullTest(false)
+	integration := v1.NewIntegration("ns", "my-integration")
+	integration.Status.IntegrationKit = &corev1.ObjectReference{Namespace: "ns", Name: "my-kit"}
+	c := newDeleteCountingClient(t, kit, &integration)
+
+	assert.Nil(t, reconcileImagePrePull(context.TODO(), c, platform, kit))
+
+	err := c.Get(context.TODO(), ctrl.ObjectKey{Namespace: "ns", Name: "my-kit-prepull"}, &appsv1.DaemonSet{})
+	assert.True(t, k8serrors.IsNotFound(err))
+	assert.Equal(t, 0, c.deletes)
+}
+
+func createImagePrePullTest(enabled bool) (*v1.IntegrationPlatform, *v1.IntegrationKit) {
+	platform := v1.NewIntegrationPlatform("ns", "camel-k")
+	platform.Status.Build.ImagePrePull = &v1.IntegrationPlatformImagePrePullSpec{
+		Enabled:      pointer.Bool(enabled),
+		NodeSelector: map[string]string{"node-pool": "integrations"},
+	}
+
+	kit := v1.NewIntegrationKit("ns", "my-kit")
+	kit.Status.Phase = v1.IntegrationKitPhaseReady
+	kit.Status.Image = "registry/my-kit:1"
+
+	return &platform, kit
+}
+
+// deleteCountingClient counts the delete calls made to the API server.
+type deleteCountingClient struct {
+	client.Client
+	deletes int
+}
+
+func newDeleteCountingClient(t *testing.T, objects ...runtime.Object) *deleteCountingClient {
+	t.Helper()
+
+	c, err := test.NewFakeClient(objects...)
+	assert.Nil(t, err)
+	return &deleteCountingClient{Client: c}
+}
+
+func (c *deleteCountingClient) Delete(ctx context.Context, obj ctrl.Object, opts ...ctrl.DeleteOption) error {
+	c.deletes++
+	return c.Client.Delete(ctx, obj, opts...)
+}
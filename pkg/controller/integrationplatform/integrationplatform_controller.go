@@ -215,6 +215,12 @@ func (r *reconcileIntegrationPlatform) Reconcile(ctx context.Context, request re
 	}
 
 	if targetPhase == v1.IntegrationPlatformPhaseReady {
+		if pool := target.Status.Build.KitPool; pool != nil && len(pool.Kits) > 0 {
+			// Periodically check the kits of the warm pool are still available
+			return reconcile.Result{
+				RequeueAfter: pool.GetInterval().Duration,
+			}, nil
+		}
 		return reconcile.Result{}, nil
 	}
 
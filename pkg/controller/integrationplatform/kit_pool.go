@@ -0,0 +1,145 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrationplatform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rs/xid"
+	"github.com/scylladb/go-set/strset"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/defaults"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/log"
+)
+
+// reconcileKitPool makes sure a kit is available, or being built, for each entry of the platform kit pool.
+// Kits that failed are deleted and built again. Kits built for a different runtime or operator version
+// are left untouched, as they may still be used by existing Integrations, and a new kit is created instead.
+func reconcileKitPool(ctx context.Context, c client.Client, l log.Logger, platform *v1.IntegrationPlatform) error {
+	pool := platform.Status.Build.KitPool
+	if pool == nil || len(pool.Kits) == 0 {
+		return nil
+	}
+
+	runtime := v1.RuntimeSpec{
+		Version:  platform.Status.Build.RuntimeVersion,
+		Provider: v1.RuntimeProviderQuarkus,
+	}
+	catalog, err := camel.LoadCatalog(ctx, c, platform.Namespace, runtime)
+	if err != nil {
+		return err
+	}
+	if catalog == nil {
+		l.Infof("Camel catalog for runtime version %s not available yet, skipping kit pool", runtime.Version)
+		return nil
+	}
+
+	for _, pooled := range pool.Kits {
+		dependencies := strset.New(pooled.Dependencies...)
+		for _, d := range catalog.Runtime.Dependencies {
+			dependencies.Add(d.GetDependencyID())
+		}
+
+		list := v1.NewIntegrationKitList()
+		err := c.List(ctx, &list,
+			ctrl.InNamespace(platform.Namespace),
+			ctrl.MatchingLabels{
+				v1.IntegrationKitPoolLabel:       pooled.Name,
+				kubernetes.CamelCreatorLabelKind: v1.IntegrationPlatformKind,
+				kubernetes.CamelCreatorLabelName: platform.Name,
+			})
+		if err != nil {
+			return err
+		}
+
+		available := false
+		for i := range list.Items {
+			kit := &list.Items[i]
+
+			if kit.Status.Phase == v1.IntegrationKitPhaseError {
+				l.Infof("Deleting failed kit %s from kit pool entry %s", kit.Name, pooled.Name)
+				if err := c.Delete(ctx, kit); err != nil && !k8serrors.IsNotFound(err) {
+					return err
+				}
+				continue
+			}
+			if kit.Status.Version != "" && kit.Status.Version != defaults.Version {
+				continue
+			}
+			if kit.Labels["camel.apache.org/runtime.version"] != catalog.Runtime.Version {
+				continue
+			}
+			if dependencies.IsEqual(strset.New(kit.Spec.Dependencies...)) {
+				available = true
+			}
+		}
+
+		if available {
+			continue
+		}
+
+		deps := dependencies.List()
+		sort.Strings(deps)
+
+		kit := newPooledIntegrationKit(platform, pooled.Name, catalog.Runtime, deps)
+		l.Infof("Creating kit %s for kit pool entry %s", kit.Name, pooled.Name)
+		if err := c.Create(ctx, kit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newPooledIntegrationKit(platform *v1.IntegrationPlatform, name string, runtime v1.RuntimeSpec, dependencies []string) *v1.IntegrationKit {
+	kit := v1.NewIntegrationKit(platform.Namespace, fmt.Sprintf("kit-%s", xid.New()))
+
+	kit.Labels = map[string]string{
+		v1.IntegrationKitTypeLabel:            v1.IntegrationKitTypePlatform,
+		v1.IntegrationKitPoolLabel:            name,
+		"camel.apache.org/runtime.version":    runtime.Version,
+		"camel.apache.org/runtime.provider":   string(runtime.Provider),
+		v1.IntegrationKitLayoutLabel:          v1.IntegrationKitLayoutFastJar,
+		kubernetes.CamelCreatorLabelKind:      v1.IntegrationPlatformKind,
+		kubernetes.CamelCreatorLabelName:      platform.Name,
+		kubernetes.CamelCreatorLabelNamespace: platform.Namespace,
+	}
+
+	kit.Annotations = map[string]string{
+		v1.PlatformSelectorAnnotation: platform.Name,
+	}
+	if operatorID := defaults.OperatorID(); operatorID != "" {
+		kit.Annotations[v1.OperatorIDAnnotation] = operatorID
+	}
+
+	kit.Spec = v1.IntegrationKitSpec{
+		Dependencies: dependencies,
+	}
+
+	return kit
+}
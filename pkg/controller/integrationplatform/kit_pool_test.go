@@ -0,0 +1,129 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrationplatform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestKitPool_CreatesMissingKits(t *testing.T) {
+	ip, c := newKitPoolPlatform(t)
+
+	h := NewMonitorAction()
+	h.InjectLogger(log.Log)
+	h.InjectClient(c)
+
+	_, err := h.Handle(context.TODO(), ip)
+	assert.Nil(t, err)
+
+	kits := listPooledKits(t, c, ip)
+	assert.Len(t, kits, 1)
+	assert.Equal(t, v1.IntegrationKitTypePlatform, kits[0].Labels[v1.IntegrationKitTypeLabel])
+	assert.Equal(t, "timer-log", kits[0].Labels[v1.IntegrationKitPoolLabel])
+	assert.Equal(t, ip.Status.Build.RuntimeVersion, kits[0].Labels["camel.apache.org/runtime.version"])
+	assert.Contains(t, kits[0].Spec.Dependencies, "camel:timer")
+	assert.Contains(t, kits[0].Spec.Dependencies, "camel:log")
+
+	// the kit is available so no further kit is created
+	_, err = h.Handle(context.TODO(), ip)
+	assert.Nil(t, err)
+	assert.Len(t, listPooledKits(t, c, ip), 1)
+}
+
+func TestKitPool_ReplacesFailedKits(t *testing.T) {
+	ip, c := newKitPoolPlatform(t)
+
+	h := NewMonitorAction()
+	h.InjectLogger(log.Log)
+	h.InjectClient(c)
+
+	_, err := h.Handle(context.TODO(), ip)
+	assert.Nil(t, err)
+
+	kits := listPooledKits(t, c, ip)
+	assert.Len(t, kits, 1)
+
+	failed := kits[0]
+	failed.Status.Phase = v1.IntegrationKitPhaseError
+	assert.Nil(t, c.Status().Update(context.TODO(), &failed))
+
+	_, err = h.Handle(context.TODO(), ip)
+	assert.Nil(t, err)
+
+	kits = listPooledKits(t, c, ip)
+	assert.Len(t, kits, 1)
+	assert.NotEqual(t, failed.Name, kits[0].Name)
+}
+
+func newKitPoolPlatform(t *testing.T) (*v1.IntegrationPlatform, client.Client) {
+	t.Helper()
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	ip := v1.IntegrationPlatform{}
+	ip.Namespace = "ns"
+	ip.Name = xid.New().String()
+	ip.Spec.Cluster = v1.IntegrationPlatformClusterOpenShift
+	ip.Spec.Profile = v1.TraitProfileOpenShift
+	ip.Spec.Build.RuntimeVersion = catalog.Runtime.Version
+	ip.Spec.Build.KitPool = &v1.IntegrationPlatformKitPoolSpec{
+		Kits: []v1.IntegrationPlatformPooledKitSpec{
+			{
+				Name:         "timer-log",
+				Dependencies: []string{"camel:timer", "camel:log"},
+			},
+		},
+	}
+	ip.Status.Phase = v1.IntegrationPlatformPhaseReady
+
+	cc := v1.NewCamelCatalogWithSpecs(ip.Namespace, "camel-catalog", catalog.CamelCatalogSpec)
+
+	c, err := test.NewFakeClient(&ip, &cc)
+	assert.Nil(t, err)
+
+	assert.Nil(t, platform.ConfigureDefaults(context.TODO(), c, &ip, false))
+
+	return &ip, c
+}
+
+func listPooledKits(t *testing.T, c client.Client, ip *v1.IntegrationPlatform) []v1.IntegrationKit {
+	t.Helper()
+
+	list := v1.NewIntegrationKitList()
+	err := c.List(context.TODO(), &list,
+		k8sclient.InNamespace(ip.Namespace),
+		k8sclient.HasLabels{v1.IntegrationKitPoolLabel},
+	)
+	assert.Nil(t, err)
+
+	return list.Items
+}
@@ -54,5 +54,10 @@ func (action *monitorAction) Handle(ctx context.Context, platform *v1.Integratio
 		return nil, err
 	}
 
+	// Make sure the kits of the warm pool are available
+	if err := reconcileKitPool(ctx, action.client, action.L, platform); err != nil {
+		return nil, err
+	}
+
 	return platform, nil
 }
@@ -0,0 +1,135 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kameletbinding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/client"
+)
+
+// endpointsReadyResyncPeriod is the period the source and the sink of a ready KameletBinding are probed at,
+// as the resources they refer to are not watched.
+const endpointsReadyResyncPeriod = 30 * time.Second
+
+// endpointsReadyRequeueAfter returns the delay after which the KameletBinding is reconciled again, so that its
+// SourceReady and SinkReady conditions do not go stale, or zero if none of its endpoints refers to a resource.
+func endpointsReadyRequeueAfter(kb *v1alpha1.KameletBinding) time.Duration {
+	if kb.Spec.Source.Ref == nil && kb.Spec.Sink.Ref == nil {
+		return 0
+	}
+	return endpointsReadyResyncPeriod
+}
+
+// setEndpointsReadyConditions probes the source and the sink, when they refer to a resource reporting its readiness,
+// e.g., a Kamelet or a Knative Broker, and reflects the outcome into the KameletBinding Ready condition, so that it
+// accounts for the health of the whole binding, and not only for the one of the underlying Integration.
+func setEndpointsReadyConditions(ctx context.Context, c client.Client, kb *v1alpha1.KameletBinding) {
+	endpoints := []struct {
+		condition v1alpha1.KameletBindingConditionType
+		endpoint  v1alpha1.Endpoint
+	}{
+		{v1alpha1.KameletBindingConditionSourceReady, kb.Spec.Source},
+		{v1alpha1.KameletBindingConditionSinkReady, kb.Spec.Sink},
+	}
+
+	for _, e := range endpoints {
+		condition := probeEndpoint(ctx, c, kb.Namespace, e.endpoint)
+		if condition == nil {
+			kb.Status.RemoveCondition(e.condition)
+			continue
+		}
+		condition.Type = e.condition
+		kb.Status.SetConditions(*condition)
+
+		if ready := kb.Status.GetCondition(v1alpha1.KameletBindingConditionReady); condition.Status == corev1.ConditionFalse &&
+			ready != nil && ready.Status == corev1.ConditionTrue {
+			kb.Status.SetCondition(v1alpha1.KameletBindingConditionReady, corev1.ConditionFalse, condition.Reason, condition.Message)
+		}
+	}
+}
+
+// probeEndpoint returns the readiness of the resource the endpoint refers to, or nil if it cannot be determined.
+func probeEndpoint(ctx context.Context, c client.Client, namespace string, endpoint v1alpha1.Endpoint) *v1alpha1.KameletBindingCondition {
+	ref := endpoint.Ref
+	if ref == nil {
+		return nil
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil
+	}
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	resource := unstructured.Unstructured{}
+	resource.SetGroupVersionKind(gv.WithKind(ref.Kind))
+	err = c.Get(ctx, ctrl.ObjectKey{Namespace: namespace, Name: ref.Name}, &resource)
+	switch {
+	case err != nil && k8serrors.IsNotFound(err):
+		if ref.Kind == v1alpha1.KameletKind && ref.Namespace == "" {
+			// The Kamelet may be provided by a repository
+			return nil
+		}
+		return &v1alpha1.KameletBindingCondition{
+			Status:  corev1.ConditionFalse,
+			Reason:  v1alpha1.KameletBindingConditionEndpointNotFoundReason,
+			Message: fmt.Sprintf("%s %q not found", ref.Kind, ref.Name),
+		}
+	case err != nil:
+		// The operator may not be granted the permission to read the resource
+		return nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(resource.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]interface{})
+		if !ok || fields["type"] != "Ready" {
+			continue
+		}
+		status, _ := fields["status"].(string)
+		message, _ := fields["message"].(string)
+		result := v1alpha1.KameletBindingCondition{
+			Status:  corev1.ConditionStatus(status),
+			Reason:  v1alpha1.KameletBindingConditionEndpointReadyReason,
+			Message: fmt.Sprintf("%s %q is ready", ref.Kind, ref.Name),
+		}
+		if result.Status != corev1.ConditionTrue {
+			result.Reason = v1alpha1.KameletBindingConditionEndpointNotReadyReason
+			result.Message = fmt.Sprintf("%s %q is not ready", ref.Kind, ref.Name)
+			if message != "" {
+				result.Message += ": " + message
+			}
+		}
+		return &result
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kameletbinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestEndpointsReadyConditions(t *testing.T) {
+	kamelet := v1alpha1.Kamelet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.KameletKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "timer-source",
+		},
+		Status: v1alpha1.KameletStatus{
+			Phase: v1alpha1.KameletPhaseError,
+			Conditions: []v1alpha1.KameletCondition{
+				{
+					Type:    v1alpha1.KameletConditionReady,
+					Status:  corev1.ConditionFalse,
+					Reason:  v1alpha1.KameletConditionReasonInvalidProperty,
+					Message: "invalid property",
+				},
+			},
+		},
+	}
+
+	c, err := test.NewFakeClient(&kamelet)
+	assert.Nil(t, err)
+
+	kb := v1alpha1.NewKameletBinding("ns", "binding")
+	kb.Spec.Source = v1alpha1.Endpoint{
+		Ref: &corev1.ObjectReference{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.KameletKind,
+			Name:       "timer-source",
+		},
+	}
+	kb.Spec.Sink = v1alpha1.Endpoint{
+		Ref: &corev1.ObjectReference{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       v1alpha1.KameletKind,
+			Namespace:  "ns",
+			Name:       "log-sink",
+		},
+	}
+	kb.Status.SetCondition(v1alpha1.KameletBindingConditionReady, corev1.ConditionTrue, "DeploymentReady", "")
+
+	setEndpointsReadyConditions(context.TODO(), c, &kb)
+
+	source := kb.Status.GetCondition(v1alpha1.KameletBindingConditionSourceReady)
+	assert.NotNil(t, source)
+	assert.Equal(t, corev1.ConditionFalse, source.Status)
+	assert.Equal(t, v1alpha1.KameletBindingConditionEndpointNotReadyReason, source.Reason)
+	assert.Equal(t, `Kamelet "timer-source" is not ready: invalid property`, source.Message)
+
+	sink := kb.Status.GetCondition(v1alpha1.KameletBindingConditionSinkReady)
+	assert.NotNil(t, sink)
+	assert.Equal(t, corev1.ConditionFalse, sink.Status)
+	assert.Equal(t, v1alpha1.KameletBindingConditionEndpointNotFoundReason, sink.Reason)
+
+	ready := kb.Status.GetCondition(v1alpha1.KameletBindingConditionReady)
+	assert.NotNil(t, ready)
+	assert.Equal(t, corev1.ConditionFalse, ready.Status)
+	assert.Equal(t, v1alpha1.KameletBindingConditionEndpointNotReadyReason, ready.Reason)
+}
+
+func TestEndpointsReadyConditionsWithURI(t *testing.T) {
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+
+	uri := "timer:tick"
+	kb := v1alpha1.NewKameletBinding("ns", "binding")
+	kb.Spec.Source = v1alpha1.Endpoint{URI: &uri}
+	kb.Spec.Sink = v1alpha1.Endpoint{URI: &uri}
+	kb.Status.SetCondition(v1alpha1.KameletBindingConditionSourceReady, corev1.ConditionFalse, "", "")
+	kb.Status.SetCondition(v1alpha1.KameletBindingConditionReady, corev1.ConditionTrue, "DeploymentReady", "")
+
+	setEndpointsReadyConditions(context.TODO(), c, &kb)
+
+	assert.Nil(t, kb.Status.GetCondition(v1alpha1.KameletBindingConditionSourceReady))
+	assert.Nil(t, kb.Status.GetCondition(v1alpha1.KameletBindingConditionSinkReady))
+	assert.Equal(t, corev1.ConditionTrue, kb.Status.GetCondition(v1alpha1.KameletBindingConditionReady).Status)
+}
+
+func TestEndpointsReadyRequeueAfter(t *testing.T) {
+	uri := "timer:tick"
+	kb := v1alpha1.NewKameletBinding("ns", "binding")
+	kb.Spec.Source = v1alpha1.Endpoint{URI: &uri}
+	kb.Spec.Sink = v1alpha1.Endpoint{URI: &uri}
+	assert.Zero(t, endpointsReadyRequeueAfter(&kb))
+
+	kb.Spec.Sink = v1alpha1.Endpoint{
+		Ref: &corev1.ObjectReference{
+			APIVersion: "eventing.knative.dev/v1",
+			Kind:       "Broker",
+			Name:       "default",
+		},
+	}
+	assert.Equal(t, endpointsReadyResyncPeriod, endpointsReadyRequeueAfter(&kb))
+}
@@ -218,7 +218,10 @@ func (r *ReconcileKameletBinding) Reconcile(ctx context.Context, request reconci
 	}
 
 	if targetPhase == v1alpha1.KameletBindingPhaseReady {
-		return reconcile.Result{}, nil
+		// Requeue to refresh the readiness of the source and the sink
+		return reconcile.Result{
+			RequeueAfter: endpointsReadyRequeueAfter(target),
+		}, nil
 	}
 
 	// Requeue
@@ -98,7 +98,7 @@ func (action *monitorAction) Handle(ctx context.Context, kameletbinding *v1alpha
 
 	switch it.Status.Phase {
 
-	case v1.IntegrationPhaseRunning:
+	case v1.IntegrationPhaseRunning, v1.IntegrationPhaseCompleted:
 		target.Status.Phase = v1alpha1.KameletBindingPhaseReady
 		setKameletBindingReadyCondition(target, &it)
 
@@ -116,6 +116,8 @@ func (action *monitorAction) Handle(ctx context.Context, kameletbinding *v1alpha
 		)
 	}
 
+	setEndpointsReadyConditions(ctx, action.client, target)
+
 	// Mirror status replicas and selector
 	target.Status.Replicas = it.Status.Replicas
 	target.Status.Selector = it.Status.Selector
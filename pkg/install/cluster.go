@@ -183,6 +183,18 @@ func SetupClusterWideResourcesOrCollect(ctx context.Context, clientProvider clie
 		installResource(ctx, c, collection, "/rbac/operator-cluster-role-local-registry.yaml")
 	}
 
+	ok, err = isClusterRoleInstalled(ctx, c, "camel-k-operator-upload")
+	if err == nil && !ok {
+		// nolint: errcheck
+		installResource(ctx, c, collection, "/rbac/operator-cluster-role-upload.yaml")
+	}
+
+	ok, err = isClusterRoleInstalled(ctx, c, "camel-k-operator-namespaces")
+	if err == nil && !ok {
+		// nolint: errcheck
+		installResource(ctx, c, collection, "/rbac/operator-cluster-role-namespaces.yaml")
+	}
+
 	isOpenShift, err := isOpenShift(c, clusterType)
 	if err != nil {
 		return err
@@ -27,9 +27,11 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	flowcontrolv1beta1 "k8s.io/api/flowcontrol/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -40,12 +42,15 @@ import (
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/client"
 	"github.com/apache/camel-k/pkg/resources"
+	"github.com/apache/camel-k/pkg/util/buildcache"
+	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/envvar"
 	"github.com/apache/camel-k/pkg/util/knative"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/minikube"
 	"github.com/apache/camel-k/pkg/util/patch"
 	image "github.com/apache/camel-k/pkg/util/registry"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 type OperatorConfiguration struct {
@@ -56,6 +61,10 @@ type OperatorConfiguration struct {
 	ClusterType           string
 	Health                OperatorHealthConfiguration
 	Monitoring            OperatorMonitoringConfiguration
+	APIClient             OperatorAPIClientConfiguration
+	FlowControl           OperatorFlowControlConfiguration
+	Upload                OperatorStorageConfiguration
+	BuildCache            OperatorBuildCacheConfiguration
 	Tolerations           []string
 	NodeSelectors         []string
 	ResourcesRequirements []string
@@ -71,6 +80,44 @@ type OperatorMonitoringConfiguration struct {
 	Port    int32
 }
 
+// OperatorAPIClientConfiguration tunes the client-side throttling of the requests the operator makes to the API server.
+// Zero values retain the operator defaults.
+type OperatorAPIClientConfiguration struct {
+	QPS   float32
+	Burst int
+}
+
+// OperatorFlowControlConfiguration configures the API Priority and Fairness resources dedicated to the operator.
+type OperatorFlowControlConfiguration struct {
+	Enabled           bool
+	ConcurrencyShares int32
+}
+
+// OperatorStorageConfiguration configures a persistent volume of the operator, e.g., the one of the store of
+// the files uploaded to the operator. The volume is not created when no storage size is set.
+type OperatorStorageConfiguration struct {
+	StorageSize  string
+	StorageClass string
+	AccessMode   string
+}
+
+// OperatorBuildCacheConfiguration configures the persistence of the build cache of the operator.
+type OperatorBuildCacheConfiguration struct {
+	// the backend of the records of the running builds, i.e., memory or configmap
+	Store string
+	// the persistent volume of the Maven local repository
+	Storage OperatorStorageConfiguration
+}
+
+const (
+	uploadStorageName      = "camel-k-operator-uploads"
+	uploadStorageMountPath = "/var/lib/camel-k/uploads"
+	// buildCacheStorageName is the name of the volume of the Maven local repository, mounted on its default path
+	buildCacheStorageName = "camel-k-operator-build-cache"
+	// operatorStorageFSGroup is the group of the operator image user, so that it owns the volumes
+	operatorStorageFSGroup = int64(1000)
+)
+
 // OperatorOrCollect installs the operator resources or adds them to the collector if present.
 func OperatorOrCollect(ctx context.Context, cmd *cobra.Command, c client.Client, cfg OperatorConfiguration, collection *kubernetes.Collection, force bool) error {
 	isOpenShift, err := isOpenShift(c, cfg.ClusterType)
@@ -157,6 +204,37 @@ func OperatorOrCollect(ctx context.Context, cmd *cobra.Command, c client.Client,
 				d.Spec.Template.Spec.Containers[0].Args = append(d.Spec.Template.Spec.Containers[0].Args,
 					fmt.Sprintf("--health-port=%d", cfg.Health.Port))
 				d.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Port = intstr.FromInt(int(cfg.Health.Port))
+				// API client throttling
+				if cfg.APIClient.QPS > 0 {
+					d.Spec.Template.Spec.Containers[0].Args = append(d.Spec.Template.Spec.Containers[0].Args,
+						fmt.Sprintf("--api-qps=%v", cfg.APIClient.QPS))
+				}
+				if cfg.APIClient.Burst > 0 {
+					d.Spec.Template.Spec.Containers[0].Args = append(d.Spec.Template.Spec.Containers[0].Args,
+						fmt.Sprintf("--api-burst=%d", cfg.APIClient.Burst))
+				}
+			}
+		}
+
+		if cfg.Upload.StorageSize != "" {
+			if d, ok := o.(*appsv1.Deployment); ok {
+				if d.Labels["camel.apache.org/component"] == "operator" {
+					// Mount the persistent volume of the upload store
+					mountOperatorStorage(d, uploadStorageName, uploadStorageMountPath, isOpenShift)
+					envvar.SetVal(&d.Spec.Template.Spec.Containers[0].Env, upload.DirEnvVariable, uploadStorageMountPath)
+				}
+			}
+		}
+
+		if d, ok := o.(*appsv1.Deployment); ok {
+			if d.Labels["camel.apache.org/component"] == "operator" {
+				if cfg.BuildCache.Store != "" {
+					envvar.SetVal(&d.Spec.Template.Spec.Containers[0].Env, buildcache.StoreEnvVariable, cfg.BuildCache.Store)
+				}
+				if cfg.BuildCache.Storage.StorageSize != "" {
+					// Mount the persistent volume of the Maven local repository of the routine builds
+					mountOperatorStorage(d, buildCacheStorageName, defaults.LocalRepository, isOpenShift)
+				}
 			}
 		}
 
@@ -235,6 +313,18 @@ func OperatorOrCollect(ctx context.Context, cmd *cobra.Command, c client.Client,
 		}
 	}
 
+	// Create the persistent volumes before the operator mounts them
+	if cfg.Upload.StorageSize != "" {
+		if err := installOperatorStorage(ctx, c, cfg.Namespace, uploadStorageName, cfg.Upload, collection); err != nil {
+			return err
+		}
+	}
+	if cfg.BuildCache.Storage.StorageSize != "" {
+		if err := installOperatorStorage(ctx, c, cfg.Namespace, buildCacheStorageName, cfg.BuildCache.Storage, collection); err != nil {
+			return err
+		}
+	}
+
 	// Deploy the operator
 	if err := installOperator(ctx, c, cfg.Namespace, customizer, collection, force); err != nil {
 		return err
@@ -265,6 +355,13 @@ func OperatorOrCollect(ctx context.Context, cmd *cobra.Command, c client.Client,
 		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to publish Kubernetes events. Try installing as cluster-admin to allow it to generate events.")
 	}
 
+	if err = installIstioBindings(ctx, c, cfg.Namespace, customizer, collection, force); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to create Istio resources. Try installing as cluster-admin.")
+	}
+
 	if err = installKedaBindings(ctx, c, cfg.Namespace, customizer, collection, force); err != nil {
 		if k8serrors.IsAlreadyExists(err) {
 			return err
@@ -272,6 +369,13 @@ func OperatorOrCollect(ctx context.Context, cmd *cobra.Command, c client.Client,
 		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to create KEDA resources. Try installing as cluster-admin.")
 	}
 
+	if err = installVpaBindings(ctx, c, cfg.Namespace, customizer, collection, force); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to create Vertical Pod Autoscaler resources. Try installing as cluster-admin.")
+	}
+
 	if err = installPodMonitors(ctx, c, cfg.Namespace, customizer, collection, force); err != nil {
 		if k8serrors.IsAlreadyExists(err) {
 			return err
@@ -297,10 +401,31 @@ func OperatorOrCollect(ctx context.Context, cmd *cobra.Command, c client.Client,
 		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to get CustomResourceDefinitions resources and the service-binding trait will fail if used. Try installing the operator as cluster-admin.")
 	}
 
+	if err = installClusterRoleBinding(ctx, c, collection, cfg.Namespace, "camel-k-operator-upload", "/rbac/operator-cluster-role-binding-upload.yaml"); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to authenticate the files uploaded with kamel run --upload. Try installing the operator as cluster-admin.")
+	}
+
+	if err = installClusterRoleBinding(ctx, c, collection, cfg.Namespace, "camel-k-operator-namespaces", "/rbac/operator-cluster-role-binding-namespaces.yaml"); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator will not be able to detect the Istio ambient mode of the namespaces. Try installing the operator as cluster-admin.")
+	}
+
 	if err = installNamespacedRoleBinding(ctx, c, collection, cfg.Namespace, "/rbac/operator-role-binding-local-registry.yaml"); err != nil {
 		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the operator won't be able to detect a local image registry via KEP-1755")
 	}
 
+	if cfg.FlowControl.Enabled {
+		if err := installFlowControl(ctx, c, cfg, collection, force); err != nil {
+			switch {
+			case k8serrors.IsForbidden(err):
+				fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the creation of the operator FlowSchema is not allowed. Try installing as cluster-admin to allow the creation of API Priority and Fairness resources.")
+			case meta.IsNoMatchError(errors.Cause(err)):
+				fmt.Fprintln(cmd.ErrOrStderr(), "Warning: the creation of the operator FlowSchema failed, API Priority and Fairness may not be enabled on the cluster: ", err)
+			default:
+				return err
+			}
+		}
+	}
+
 	if cfg.Monitoring.Enabled {
 		if err := installMonitoringResources(ctx, c, cfg.Namespace, customizer, collection, force); err != nil {
 			switch {
@@ -459,6 +584,13 @@ func installOperator(ctx context.Context, c client.Client, namespace string, cus
 	)
 }
 
+func installIstioBindings(ctx context.Context, c client.Client, namespace string, customizer ResourceCustomizer, collection *kubernetes.Collection, force bool) error {
+	return ResourcesOrCollect(ctx, c, namespace, collection, force, customizer,
+		"/rbac/operator-role-istio.yaml",
+		"/rbac/operator-role-binding-istio.yaml",
+	)
+}
+
 func installKedaBindings(ctx context.Context, c client.Client, namespace string, customizer ResourceCustomizer, collection *kubernetes.Collection, force bool) error {
 	return ResourcesOrCollect(ctx, c, namespace, collection, force, customizer,
 		"/rbac/operator-role-keda.yaml",
@@ -466,6 +598,13 @@ func installKedaBindings(ctx context.Context, c client.Client, namespace string,
 	)
 }
 
+func installVpaBindings(ctx context.Context, c client.Client, namespace string, customizer ResourceCustomizer, collection *kubernetes.Collection, force bool) error {
+	return ResourcesOrCollect(ctx, c, namespace, collection, force, customizer,
+		"/rbac/operator-role-vpa.yaml",
+		"/rbac/operator-role-binding-vpa.yaml",
+	)
+}
+
 func installKnative(ctx context.Context, c client.Client, namespace string, customizer ResourceCustomizer, collection *kubernetes.Collection, force bool) error {
 	return ResourcesOrCollect(ctx, c, namespace, collection, force, customizer,
 		"/rbac/operator-role-knative.yaml",
@@ -501,6 +640,106 @@ func installMonitoringResources(ctx context.Context, c client.Client, namespace
 	)
 }
 
+// installFlowControl creates a PriorityLevelConfiguration, and a FlowSchema that assigns the requests
+// of the operator service account to it. Both resources are cluster-scoped, so their names are suffixed
+// with the operator namespace.
+func installFlowControl(ctx context.Context, c client.Client, cfg OperatorConfiguration, collection *kubernetes.Collection, force bool) error {
+	customizer := func(o ctrl.Object) ctrl.Object {
+		switch r := o.(type) {
+		case *flowcontrolv1beta1.PriorityLevelConfiguration:
+			r.Name = fmt.Sprintf("%s-%s", r.Name, cfg.Namespace)
+			if r.Spec.Limited != nil && cfg.FlowControl.ConcurrencyShares > 0 {
+				r.Spec.Limited.AssuredConcurrencyShares = cfg.FlowControl.ConcurrencyShares
+			}
+		case *flowcontrolv1beta1.FlowSchema:
+			r.Name = fmt.Sprintf("%s-%s", r.Name, cfg.Namespace)
+			r.Spec.PriorityLevelConfiguration.Name = fmt.Sprintf("%s-%s", r.Spec.PriorityLevelConfiguration.Name, cfg.Namespace)
+			for i := range r.Spec.Rules {
+				for j := range r.Spec.Rules[i].Subjects {
+					if sa := r.Spec.Rules[i].Subjects[j].ServiceAccount; sa != nil && sa.Name == serviceAccountName {
+						sa.Namespace = cfg.Namespace
+					}
+				}
+			}
+		}
+		return o
+	}
+
+	return ResourcesOrCollect(ctx, c, cfg.Namespace, collection, force, customizer,
+		"/manager/operator-priority-level-configuration.yaml",
+		"/manager/operator-flow-schema.yaml",
+	)
+}
+
+// mountOperatorStorage mounts the PersistentVolumeClaim of the given name into the operator container.
+func mountOperatorStorage(d *appsv1.Deployment, name string, mountPath string, isOpenShift bool) {
+	d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: name,
+			},
+		},
+	})
+	d.Spec.Template.Spec.Containers[0].VolumeMounts = append(d.Spec.Template.Spec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{
+			Name:      name,
+			MountPath: mountPath,
+		})
+	if !isOpenShift {
+		// OpenShift sets the group of the volume with the one of the namespace
+		if d.Spec.Template.Spec.SecurityContext == nil {
+			d.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		if d.Spec.Template.Spec.SecurityContext.FSGroup == nil {
+			fsGroup := operatorStorageFSGroup
+			d.Spec.Template.Spec.SecurityContext.FSGroup = &fsGroup
+		}
+	}
+}
+
+// installOperatorStorage creates the PersistentVolumeClaim of a persistent volume of the operator. An existing claim
+// is kept, along with its content, e.g., the uploaded files, even when the installation is forced.
+func installOperatorStorage(ctx context.Context, c client.Client, namespace string, name string, storage OperatorStorageConfiguration, collection *kubernetes.Collection) error {
+	size, err := resource.ParseQuantity(storage.StorageSize)
+	if err != nil {
+		return errors.Wrapf(err, "invalid size of the %s storage", name)
+	}
+	accessMode := corev1.ReadWriteOnce
+	if storage.AccessMode != "" {
+		accessMode = corev1.PersistentVolumeAccessMode(storage.AccessMode)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "PersistentVolumeClaim",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				"app": "camel-k",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{accessMode},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if storage.StorageClass != "" {
+		pvc.Spec.StorageClassName = &storage.StorageClass
+	}
+
+	if err := ObjectOrCollect(ctx, c, namespace, collection, false, pvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
 func installLeaseBindings(ctx context.Context, c client.Client, namespace string, customizer ResourceCustomizer, collection *kubernetes.Collection, force bool) error {
 	return ResourcesOrCollect(ctx, c, namespace, collection, force, customizer,
 		"/rbac/operator-role-leases.yaml",
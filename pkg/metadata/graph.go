@@ -0,0 +1,142 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+	src "github.com/apache/camel-k/pkg/util/source"
+)
+
+// GraphNode is either a source of the Integration or an endpoint its routes consume from or produce to.
+type GraphNode struct {
+	ID     string
+	Label  string
+	Source bool
+}
+
+// GraphEdge is a message flow between two nodes.
+type GraphEdge struct {
+	From string
+	To   string
+}
+
+// RouteGraph is the topology of the routes of an Integration, as statically extracted from its sources.
+// Each source consumes from its starting endpoints and produces to its end endpoints, so that the endpoints
+// shared by several sources, e.g. direct or seda ones, connect them together.
+type RouteGraph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// Graph returns the route graph of the sources. Endpoints are identified by their URI, without query parameters.
+func Graph(catalog *camel.RuntimeCatalog, sources []v1.SourceSpec) (RouteGraph, error) {
+	graph := RouteGraph{}
+	endpoints := make(map[string]string)
+	edges := make(map[GraphEdge]bool)
+
+	endpoint := func(uri string) string {
+		if idx := strings.Index(uri, "?"); idx >= 0 {
+			uri = uri[:idx]
+		}
+		if id, ok := endpoints[uri]; ok {
+			return id
+		}
+		id := fmt.Sprintf("e%d", len(endpoints))
+		endpoints[uri] = id
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: id, Label: uri})
+		return id
+	}
+	edge := func(from, to string) {
+		e := GraphEdge{From: from, To: to}
+		if !edges[e] {
+			edges[e] = true
+			graph.Edges = append(graph.Edges, e)
+		}
+	}
+
+	for i, source := range sources {
+		if source.ContentRef != "" || source.Compression {
+			return RouteGraph{}, fmt.Errorf("source %s must be provided inline and uncompressed", source.Name)
+		}
+		language := source.InferLanguage()
+		if language == "" {
+			return RouteGraph{}, fmt.Errorf("unable to infer the language of source %s", source.Name)
+		}
+
+		meta := src.NewMetadata()
+		if err := src.InspectorForLanguage(catalog, language).Extract(source, &meta); err != nil {
+			return RouteGraph{}, fmt.Errorf("unable to inspect source %s: %w", source.Name, err)
+		}
+
+		id := fmt.Sprintf("s%d", i)
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: id, Label: source.Name, Source: true})
+		for _, uri := range meta.FromURIs {
+			edge(endpoint(uri), id)
+		}
+		for _, uri := range meta.ToURIs {
+			edge(id, endpoint(uri))
+		}
+	}
+
+	return graph, nil
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+func (g RouteGraph) DOT(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(name))
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		shape := "ellipse"
+		if n.Source {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", n.ID, dotQuote(n.Label), shape)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g RouteGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := strings.ReplaceAll(n.Label, `"`, "#quot;")
+		if n.Source {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", n.ID, label)
+		} else {
+			fmt.Fprintf(&b, "  %s([\"%s\"])\n", n.ID, label)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", e.From, e.To)
+	}
+	return b.String()
+}
+
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,100 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+)
+
+func TestGraph(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	graph, err := Graph(catalog, []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name: "producer.yaml",
+				Content: `
+- from:
+    uri: "timer:tick"
+    steps:
+      - to: "direct:process"
+`,
+			},
+		},
+		{
+			DataSpec: v1.DataSpec{
+				Name: "consumer.yaml",
+				Content: `
+- from:
+    uri: "direct:process"
+    steps:
+      - to: "log:info?showAll=true"
+`,
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, `digraph "my-integration" {
+  rankdir=LR;
+  s0 [label="producer.yaml", shape=box];
+  e0 [label="timer:tick", shape=ellipse];
+  e1 [label="direct:process", shape=ellipse];
+  s1 [label="consumer.yaml", shape=box];
+  e2 [label="log:info", shape=ellipse];
+  e0 -> s0;
+  s0 -> e1;
+  e1 -> s1;
+  s1 -> e2;
+}
+`, graph.DOT("my-integration"))
+
+	assert.Equal(t, `flowchart LR
+  s0["producer.yaml"]
+  e0(["timer:tick"])
+  e1(["direct:process"])
+  s1["consumer.yaml"]
+  e2(["log:info"])
+  e0 --> s0
+  s0 --> e1
+  e1 --> s1
+  s1 --> e2
+`, graph.Mermaid())
+}
+
+func TestGraphCompressedSource(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	_, err = Graph(catalog, []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:        "routes.yaml",
+				Content:     "H4sIAAAAAAAA",
+				Compression: true,
+			},
+		},
+	})
+	assert.EqualError(t, err, "source routes.yaml must be provided inline and uncompressed")
+}
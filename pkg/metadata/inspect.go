@@ -0,0 +1,109 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scylladb/go-set/strset"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+	src "github.com/apache/camel-k/pkg/util/source"
+)
+
+// InspectPath is the path of the operator endpoint inspecting the sources of an Integration.
+const InspectPath = "/inspect"
+
+// InspectRequest contains the sources to inspect.
+type InspectRequest struct {
+	Sources []v1.SourceSpec `json:"sources"`
+}
+
+// Inspection contains the metadata extracted from the inspected sources.
+type Inspection struct {
+	// The Camel components used by the routes
+	Components []string `json:"components,omitempty"`
+	// The starting URIs of the routes
+	FromURIs []string `json:"fromURIs,omitempty"`
+	// The end URIs of the routes
+	ToURIs []string `json:"toURIs,omitempty"`
+	// The Kamelets referenced by the routes
+	Kamelets []string `json:"kamelets,omitempty"`
+	// The dependencies required to run the routes
+	Dependencies []string `json:"dependencies,omitempty"`
+	// The capabilities required to run the routes
+	RequiredCapabilities []string `json:"requiredCapabilities,omitempty"`
+	// Whether a route is exposed through HTTP
+	ExposesHTTPServices bool `json:"exposesHTTPServices"`
+	// Whether the routes only contain passive endpoints, activated from external calls
+	PassiveEndpoints bool `json:"passiveEndpoints"`
+}
+
+// Inspect returns the metadata extracted from the sources. Contrary to ExtractAll, it fails when a source
+// cannot be inspected, so that it can be used to validate the sources before deploying them.
+func Inspect(catalog *camel.RuntimeCatalog, sources []v1.SourceSpec) (Inspection, error) {
+	meta := src.NewMetadata()
+	meta.PassiveEndpoints = true
+	meta.ExposesHTTPServices = false
+
+	for _, source := range sources {
+		if source.ContentRef != "" || source.Compression {
+			return Inspection{}, fmt.Errorf("source %s must be provided inline and uncompressed", source.Name)
+		}
+		language := source.InferLanguage()
+		if language == "" {
+			return Inspection{}, fmt.Errorf("unable to infer the language of source %s", source.Name)
+		}
+
+		m := src.NewMetadata()
+		m.PassiveEndpoints = true
+		if err := src.InspectorForLanguage(catalog, language).Extract(source, &m); err != nil {
+			return Inspection{}, fmt.Errorf("unable to inspect source %s: %w", source.Name, err)
+		}
+		meta = merge(meta, m)
+		meta.Kamelets = append(meta.Kamelets, m.Kamelets...)
+	}
+
+	components := strset.New()
+	for _, uris := range [][]string{meta.FromURIs, meta.ToURIs} {
+		for _, uri := range uris {
+			if _, scheme := catalog.DecodeComponent(uri); scheme != nil {
+				components.Add(scheme.ID)
+			}
+		}
+	}
+
+	return Inspection{
+		Components:           sortedList(components),
+		FromURIs:             meta.FromURIs,
+		ToURIs:               meta.ToURIs,
+		Kamelets:             sortedList(strset.New(meta.Kamelets...)),
+		Dependencies:         sortedList(meta.Dependencies),
+		RequiredCapabilities: sortedList(meta.RequiredCapabilities),
+		ExposesHTTPServices:  meta.ExposesHTTPServices,
+		PassiveEndpoints:     meta.PassiveEndpoints,
+	}, nil
+}
+
+func sortedList(set *strset.Set) []string {
+	list := set.List()
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,106 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+)
+
+const yamlRoute = `
+- from:
+    uri: "platform-http:/hello"
+    steps:
+      - to:
+          uri: "kamelet:my-sink"
+`
+
+func TestInspect(t *testing.T) {
+	sources := []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name: "routes.java",
+				Content: `
+				from("timer:tick").to("log:info");
+				`,
+			},
+		},
+		{
+			DataSpec: v1.DataSpec{
+				Name:    "routes.yaml",
+				Content: yamlRoute,
+			},
+		},
+	}
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	inspection, err := Inspect(catalog, sources)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"kamelet", "log", "platform-http", "timer"}, inspection.Components)
+	assert.ElementsMatch(t, []string{"timer:tick", "platform-http:/hello"}, inspection.FromURIs)
+	assert.ElementsMatch(t, []string{"log:info", "kamelet:my-sink"}, inspection.ToURIs)
+	assert.Equal(t, []string{"my-sink"}, inspection.Kamelets)
+	assert.Contains(t, inspection.Dependencies, "camel:timer")
+	assert.Contains(t, inspection.Dependencies, "camel:log")
+	assert.Contains(t, inspection.Dependencies, "camel:platform-http")
+	assert.Contains(t, inspection.RequiredCapabilities, v1.CapabilityPlatformHTTP)
+	assert.True(t, inspection.ExposesHTTPServices)
+	assert.False(t, inspection.PassiveEndpoints)
+}
+
+func TestInspectUnknownLanguage(t *testing.T) {
+	sources := []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:    "routes.txt",
+				Content: `from("timer:tick").to("log:info")`,
+			},
+		},
+	}
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	_, err = Inspect(catalog, sources)
+	assert.NotNil(t, err)
+}
+
+func TestInspectCompressedSource(t *testing.T) {
+	sources := []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:        "routes.java",
+				Content:     "H4sIAAAAAAAA",
+				Compression: true,
+			},
+		},
+	}
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	_, err = Inspect(catalog, sources)
+	assert.NotNil(t, err)
+}
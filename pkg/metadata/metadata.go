@@ -49,9 +49,14 @@ func merge(m1 src.Metadata, m2 src.Metadata) src.Metadata {
 	t = append(t, m1.ToURIs...)
 	t = append(t, m2.ToURIs...)
 
+	r := make([]string, 0, len(m1.RouteIDs)+len(m2.RouteIDs))
+	r = append(r, m1.RouteIDs...)
+	r = append(r, m2.RouteIDs...)
+
 	return src.Metadata{
 		FromURIs:             f,
 		ToURIs:               t,
+		RouteIDs:             r,
 		Dependencies:         strset.Union(m1.Dependencies, m2.Dependencies),
 		RequiredCapabilities: strset.Union(m1.RequiredCapabilities, m2.RequiredCapabilities),
 		ExposesHTTPServices:  m1.ExposesHTTPServices || m2.ExposesHTTPServices,
@@ -75,9 +75,33 @@ func ConfigureDefaults(ctx context.Context, c client.Client, p *v1.IntegrationPl
 		}
 	}
 
+	if farm := p.Status.Build.BuildFarm; farm != nil {
+		if farm.Namespace == "" {
+			return errors.New("the build farm namespace must be set")
+		}
+		if p.Status.Build.PublishStrategy == v1.IntegrationPlatformBuildPublishStrategyS2I {
+			return errors.New("the build farm is not supported with the S2I publish strategy")
+		}
+	}
+
+	if export := p.Status.Build.ImageExport; export != nil {
+		if export.PersistentVolumeClaim == "" {
+			return errors.New("the image export persistent volume claim must be set")
+		}
+		if p.Status.Build.BuildStrategy == v1.BuildStrategyRoutine {
+			return errors.New("the image export is not supported with the routine build strategy")
+		}
+	}
+
 	if p.Status.Build.BuildStrategy == "" {
 		// Use the fastest strategy that they support (routine when possible)
-		if p.Status.Build.PublishStrategy == v1.IntegrationPlatformBuildPublishStrategyS2I ||
+		if p.Status.Build.BuildFarm != nil {
+			// The farm nodes only host the builder Pods
+			p.Status.Build.BuildStrategy = v1.BuildStrategyPod
+		} else if p.Status.Build.ImageExport != nil {
+			// The export volume is mounted into the builder Pod
+			p.Status.Build.BuildStrategy = v1.BuildStrategyPod
+		} else if p.Status.Build.PublishStrategy == v1.IntegrationPlatformBuildPublishStrategyS2I ||
 			p.Status.Build.PublishStrategy == v1.IntegrationPlatformBuildPublishStrategySpectrum {
 			p.Status.Build.BuildStrategy = v1.BuildStrategyRoutine
 		} else {
@@ -92,7 +116,12 @@ func ConfigureDefaults(ctx context.Context, c client.Client, p *v1.IntegrationPl
 	}
 
 	if p.Status.Build.BuildStrategy == v1.BuildStrategyPod {
-		if err := CreateBuilderServiceAccount(ctx, c, p); err != nil {
+		if farm := p.Status.Build.BuildFarm; farm != nil {
+			err = createBuilderServiceAccount(ctx, c, farm.Namespace, p.Status.Cluster)
+		} else {
+			err = CreateBuilderServiceAccount(ctx, c, p)
+		}
+		if err != nil {
 			return errors.Wrap(err, "cannot ensure service account is present")
 		}
 	}
@@ -114,15 +143,19 @@ func ConfigureDefaults(ctx context.Context, c client.Client, p *v1.IntegrationPl
 }
 
 func CreateBuilderServiceAccount(ctx context.Context, client client.Client, p *v1.IntegrationPlatform) error {
+	return createBuilderServiceAccount(ctx, client, p.Namespace, p.Status.Cluster)
+}
+
+func createBuilderServiceAccount(ctx context.Context, client client.Client, namespace string, cluster v1.IntegrationPlatformCluster) error {
 	sa := corev1.ServiceAccount{}
 	key := ctrl.ObjectKey{
 		Name:      BuilderServiceAccount,
-		Namespace: p.Namespace,
+		Namespace: namespace,
 	}
 
 	err := client.Get(ctx, key, &sa)
 	if err != nil && k8serrors.IsNotFound(err) {
-		return install.BuilderServiceAccountRoles(ctx, client, p.Namespace, p.Status.Cluster)
+		return install.BuilderServiceAccountRoles(ctx, client, namespace, cluster)
 	}
 
 	return err
@@ -229,6 +262,12 @@ func setPlatformDefaults(p *v1.IntegrationPlatform, verbose bool) error {
 		}
 	}
 
+	if p.Status.Cleanup.TemporaryIntegrationTTL == nil {
+		p.Status.Cleanup.TemporaryIntegrationTTL = &metav1.Duration{
+			Duration: 24 * time.Hour,
+		}
+	}
+
 	if len(p.Status.Kamelet.Repositories) == 0 {
 		p.Status.Kamelet.Repositories = append(p.Status.Kamelet.Repositories, v1.IntegrationPlatformKameletRepositorySpec{
 			URI: repository.DefaultRemoteRepository,
@@ -19,11 +19,15 @@ package platform
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/defaults"
+	"github.com/apache/camel-k/pkg/util/log"
 	coordination "k8s.io/api/coordination/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -42,6 +46,9 @@ const OperatorLockName = "camel-k-lock"
 
 var OperatorImage string
 
+// OperatorUploadURL is the URL of the operator API the builder Pods fetch the uploaded files from, if any.
+var OperatorUploadURL string
+
 // IsCurrentOperatorGlobal returns true if the operator is configured to watch all namespaces.
 func IsCurrentOperatorGlobal() bool {
 	if watchNamespace, envSet := os.LookupEnv(OperatorWatchNamespaceEnvVariable); !envSet || strings.TrimSpace(watchNamespace) == "" {
@@ -89,6 +96,23 @@ func IsNamespaceLocked(ctx context.Context, c ctrl.Reader, namespace string) (bo
 	return true, nil
 }
 
+// GetOperatorLeaderPodName returns the name of the operator Pod holding the leader election Lease, whose holder
+// identity is the Pod hostname followed by a unique identifier.
+func GetOperatorLeaderPodName(ctx context.Context, c ctrl.Reader, key ctrl.ObjectKey) (string, error) {
+	lease := coordination.Lease{}
+	if err := c.Get(ctx, key, &lease); err != nil {
+		return "", err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return "", fmt.Errorf("the lease %s has no holder", key.String())
+	}
+	holder := *lease.Spec.HolderIdentity
+	if i := strings.LastIndex(holder, "_"); i > 0 {
+		holder = holder[:i]
+	}
+	return holder, nil
+}
+
 // IsOperatorAllowedOnNamespace returns true if the current operator is allowed to react on changes in the given namespace.
 func IsOperatorAllowedOnNamespace(ctx context.Context, c ctrl.Reader, namespace string) (bool, error) {
 	if !IsCurrentOperatorGlobal() {
@@ -115,10 +139,93 @@ func IsOperatorHandler(object ctrl.Object) bool {
 	return resourceID == operatorID
 }
 
+// IsOperatorHandlerOrDefault returns true if the resource is assigned to the current operator, or if the resource
+// is not assigned to any operator and the current operator is the default one.
+func IsOperatorHandlerOrDefault(ctx context.Context, c ctrl.Reader, object ctrl.Object) (bool, error) {
+	if object.GetAnnotations()[camelv1.OperatorIDAnnotation] != "" {
+		return IsOperatorHandler(object), nil
+	}
+	defaultID, found, err := GetDefaultOperatorID(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		// No election: the resources are handled by the operator without id
+		return IsOperatorHandler(object), nil
+	}
+	return defaultID == defaults.OperatorID(), nil
+}
+
+// GetDefaultOperatorID returns the id of the operator claiming the resources that are not assigned to any operator.
+// It is the operator of the platform with the highest default priority, the lowest operator id winning ties.
+// It returns false if no platform is annotated with a default priority.
+func GetDefaultOperatorID(ctx context.Context, c ctrl.Reader) (string, bool, error) {
+	lst := camelv1.NewIntegrationPlatformList()
+	if err := c.List(ctx, &lst); err != nil {
+		return "", false, err
+	}
+
+	type candidate struct {
+		id       string
+		priority int
+	}
+	candidates := make([]candidate, 0)
+	for _, p := range lst.Items {
+		value, ok := p.Annotations[camelv1.OperatorDefaultPriorityAnnotation]
+		if !ok {
+			continue
+		}
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			log.Log.Infof("Ignoring invalid default priority %q of platform %s/%s", value, p.Namespace, p.Name)
+			continue
+		}
+		candidates = append(candidates, candidate{
+			id:       p.Annotations[camelv1.OperatorIDAnnotation],
+			priority: priority,
+		})
+	}
+	if len(candidates) == 0 {
+		return "", false, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority > candidates[j].priority
+		}
+		return candidates[i].id < candidates[j].id
+	})
+	return candidates[0].id, true, nil
+}
+
+// GetOperatorIDs returns the ids of the operators known from the installed platforms.
+func GetOperatorIDs(ctx context.Context, c ctrl.Reader) (map[string]bool, error) {
+	lst := camelv1.NewIntegrationPlatformList()
+	if err := c.List(ctx, &lst); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+	for _, p := range lst.Items {
+		ids[p.Annotations[camelv1.OperatorIDAnnotation]] = true
+	}
+	return ids, nil
+}
+
+// IsOrphan returns true if the resource is assigned to an operator that is not known from any platform.
+func IsOrphan(object ctrl.Object, operatorIDs map[string]bool) bool {
+	id := object.GetAnnotations()[camelv1.OperatorIDAnnotation]
+	return id != "" && !operatorIDs[id]
+}
+
 // FilteringFuncs do preliminary checks to determine if certain events should be handled by the controller
 // based on labels on the resources (e.g. camel.apache.org/operator.id) and the operator configuration,
 // before handing the computation over to the user code.
 type FilteringFuncs struct {
+	// AllowUnassigned lets the events of resources not assigned to any operator through, so that the
+	// default operator can claim them
+	AllowUnassigned bool
+
 	// Create returns true if the Create event should be processed
 	CreateFunc func(event.CreateEvent) bool
 
@@ -133,7 +240,7 @@ type FilteringFuncs struct {
 }
 
 func (f FilteringFuncs) Create(e event.CreateEvent) bool {
-	if !IsOperatorHandler(e.Object) {
+	if !f.isOperatorHandler(e.Object) {
 		return false
 	}
 	if f.CreateFunc != nil {
@@ -143,7 +250,7 @@ func (f FilteringFuncs) Create(e event.CreateEvent) bool {
 }
 
 func (f FilteringFuncs) Delete(e event.DeleteEvent) bool {
-	if !IsOperatorHandler(e.Object) {
+	if !f.isOperatorHandler(e.Object) {
 		return false
 	}
 	if f.DeleteFunc != nil {
@@ -153,7 +260,7 @@ func (f FilteringFuncs) Delete(e event.DeleteEvent) bool {
 }
 
 func (f FilteringFuncs) Update(e event.UpdateEvent) bool {
-	if !IsOperatorHandler(e.ObjectNew) {
+	if !f.isOperatorHandler(e.ObjectNew) {
 		return false
 	}
 	if e.ObjectOld != nil && e.ObjectNew != nil &&
@@ -168,7 +275,7 @@ func (f FilteringFuncs) Update(e event.UpdateEvent) bool {
 }
 
 func (f FilteringFuncs) Generic(e event.GenericEvent) bool {
-	if !IsOperatorHandler(e.Object) {
+	if !f.isOperatorHandler(e.Object) {
 		return false
 	}
 	if f.GenericFunc != nil {
@@ -177,4 +284,11 @@ func (f FilteringFuncs) Generic(e event.GenericEvent) bool {
 	return true
 }
 
+func (f FilteringFuncs) isOperatorHandler(object ctrl.Object) bool {
+	if f.AllowUnassigned && object != nil && object.GetAnnotations()[camelv1.OperatorIDAnnotation] == "" {
+		return true
+	}
+	return IsOperatorHandler(object)
+}
+
 var _ predicate.Predicate = FilteringFuncs{}
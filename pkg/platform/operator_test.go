@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func newPlatform(namespace string, operatorID string, priority string) *v1.IntegrationPlatform {
+	p := v1.NewIntegrationPlatform(namespace, DefaultPlatformName)
+	p.Annotations = map[string]string{
+		v1.OperatorIDAnnotation: operatorID,
+	}
+	if priority != "" {
+		p.Annotations[v1.OperatorDefaultPriorityAnnotation] = priority
+	}
+	return &p
+}
+
+func TestGetDefaultOperatorID(t *testing.T) {
+	c, err := test.NewFakeClient(
+		newPlatform("ns1", "operator-c", "10"),
+		newPlatform("ns2", "operator-b", "20"),
+		newPlatform("ns3", "operator-a", "20"),
+		newPlatform("ns4", "operator-z", "invalid"),
+		newPlatform("ns5", "operator-y", ""),
+	)
+	assert.Nil(t, err)
+
+	id, found, err := GetDefaultOperatorID(context.TODO(), c)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "operator-a", id)
+}
+
+func TestGetDefaultOperatorIDWithoutElection(t *testing.T) {
+	c, err := test.NewFakeClient(
+		newPlatform("ns1", "operator-a", ""),
+	)
+	assert.Nil(t, err)
+
+	_, found, err := GetDefaultOperatorID(context.TODO(), c)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	it := v1.NewIntegration("ns1", "it")
+	ok, err := IsOperatorHandlerOrDefault(context.TODO(), c, &it)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestIsOrphan(t *testing.T) {
+	ids := map[string]bool{"operator-a": true}
+
+	it := v1.NewIntegration("ns", "it")
+	assert.False(t, IsOrphan(&it, ids))
+
+	it.Annotations = map[string]string{v1.OperatorIDAnnotation: "operator-a"}
+	assert.False(t, IsOrphan(&it, ids))
+
+	it.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{v1.OperatorIDAnnotation: "operator-b"}}
+	assert.True(t, IsOrphan(&it, ids))
+}
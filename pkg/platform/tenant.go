@@ -0,0 +1,214 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TenantConfigMapName is the name of the ConfigMap holding the tenant configuration of a namespace.
+const TenantConfigMapName = "camel-k-tenant"
+
+const (
+	tenantRegistryAddress      = "registry.address"
+	tenantRegistryOrganization = "registry.organization"
+	tenantRegistrySecret       = "registry.secret"
+	tenantRegistryCA           = "registry.ca"
+	tenantRegistryInsecure     = "registry.insecure"
+	tenantKitSharing           = "kit.sharing"
+	tenantEnvironmentProfile   = "environment.profile"
+)
+
+// Tenant is the configuration that a namespace layers on top of the IntegrationPlatform it uses.
+type Tenant struct {
+	// Namespace is the namespace of the tenant.
+	Namespace string
+	// Registry overrides the non-empty fields of the platform registry.
+	Registry v1.RegistrySpec
+	// KitSharing allows the integrations of the tenant to reuse the kits built in the platform namespace.
+	KitSharing bool
+	// EnvironmentProfile overrides the default environment profile of the platform.
+	EnvironmentProfile string
+}
+
+// GetTenant returns the tenant configuration of the given namespace, or nil if the namespace has none.
+func GetTenant(ctx context.Context, c k8sclient.Reader, namespace string) (*Tenant, error) {
+	cm := corev1.ConfigMap{}
+	key := k8sclient.ObjectKey{Namespace: namespace, Name: TenantConfigMapName}
+	if err := c.Get(ctx, key, &cm); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return newTenant(namespace, cm.Data)
+}
+
+func newTenant(namespace string, data map[string]string) (*Tenant, error) {
+	t := Tenant{
+		Namespace: namespace,
+		Registry: v1.RegistrySpec{
+			Address:      data[tenantRegistryAddress],
+			Organization: data[tenantRegistryOrganization],
+			Secret:       data[tenantRegistrySecret],
+			CA:           data[tenantRegistryCA],
+		},
+		EnvironmentProfile: data[tenantEnvironmentProfile],
+	}
+
+	var err error
+	if t.Registry.Insecure, err = parseTenantBool(data, tenantRegistryInsecure); err != nil {
+		return nil, err
+	}
+	if t.KitSharing, err = parseTenantBool(data, tenantKitSharing); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func parseTenantBool(data map[string]string, key string) (bool, error) {
+	value, ok := data[key]
+	if !ok || value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for key %q of ConfigMap %s: %w", value, key, TenantConfigMapName, err)
+	}
+	return b, nil
+}
+
+// ApplyTo returns a copy of the given platform, with the registry overridden by the tenant one.
+// A tenant that sets the registry address, secret or CA provides all of them, so that the
+// credentials of the platform registry are never used to push or pull the tenant images.
+func (t *Tenant) ApplyTo(p *v1.IntegrationPlatform) *v1.IntegrationPlatform {
+	if t == nil || p == nil {
+		return p
+	}
+
+	pl := p.DeepCopy()
+	registry := &pl.Status.Build.Registry
+	if t.hasRegistry() {
+		if t.Registry.Address != "" {
+			registry.Address = t.Registry.Address
+		}
+		registry.Secret = t.Registry.Secret
+		registry.CA = t.Registry.CA
+		registry.Insecure = t.Registry.Insecure
+		if t.Registry.Organization == "" {
+			registry.Organization = t.Namespace
+		}
+	}
+	if t.Registry.Organization != "" {
+		registry.Organization = t.Registry.Organization
+	}
+	if t.EnvironmentProfile != "" {
+		pl.Status.EnvironmentProfile = t.EnvironmentProfile
+	}
+
+	return pl
+}
+
+// ConfigMap returns the ConfigMap holding the tenant configuration, that only sets the non-empty fields.
+func (t *Tenant) ConfigMap() *corev1.ConfigMap {
+	data := make(map[string]string)
+	set := func(key string, value string) {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	set(tenantRegistryAddress, t.Registry.Address)
+	set(tenantRegistryOrganization, t.Registry.Organization)
+	set(tenantRegistrySecret, t.Registry.Secret)
+	set(tenantRegistryCA, t.Registry.CA)
+	if t.Registry.Insecure {
+		data[tenantRegistryInsecure] = "true"
+	}
+	if t.KitSharing {
+		data[tenantKitSharing] = "true"
+	}
+	set(tenantEnvironmentProfile, t.EnvironmentProfile)
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TenantConfigMapName,
+			Namespace: t.Namespace,
+			Labels: map[string]string{
+				"app": "camel-k",
+			},
+		},
+		Data: data,
+	}
+}
+
+func (t *Tenant) hasRegistry() bool {
+	return t.Registry.Address != "" || t.Registry.Secret != "" || t.Registry.CA != ""
+}
+
+// RegistryNamespace returns the namespace holding the secret and the CA of the registry.
+// As the kits of a tenant are built in its namespace, they are looked up there, even when
+// the tenant reuses the platform registry.
+func (t *Tenant) RegistryNamespace(p *v1.IntegrationPlatform) string {
+	if t != nil {
+		return t.Namespace
+	}
+	return p.Namespace
+}
+
+// IntegrationKitNamespace returns the namespace where the kit of the given Integration lives.
+// The kits of a tenant are kept in its own namespace, unless the Integration refers to another one.
+func (t *Tenant) IntegrationKitNamespace(it *v1.Integration, p *v1.IntegrationPlatform) string {
+	if t == nil ||
+		it.Status.IntegrationKit != nil && it.Status.IntegrationKit.Namespace != "" ||
+		it.Spec.IntegrationKit != nil && it.Spec.IntegrationKit.Namespace != "" {
+		return it.GetIntegrationKitNamespace(p)
+	}
+	return t.Namespace
+}
+
+// IntegrationKitLookupNamespaces returns the namespaces where the kits matching the given Integration are looked up.
+func (t *Tenant) IntegrationKitLookupNamespaces(it *v1.Integration, p *v1.IntegrationPlatform) []string {
+	ns := t.IntegrationKitNamespace(it, p)
+	if t != nil && t.KitSharing {
+		if shared := it.GetIntegrationKitNamespace(p); shared != ns {
+			return []string{ns, shared}
+		}
+	}
+	return []string{ns}
+}
+
+// CreateBuilderServiceAccount creates the service account of the builder Pods in the tenant namespace,
+// unless it already exists.
+func (t *Tenant) CreateBuilderServiceAccount(ctx context.Context, c client.Client, p *v1.IntegrationPlatform) error {
+	return createBuilderServiceAccount(ctx, c, t.Namespace, p.Status.Cluster)
+}
@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestTenantConfigMapRoundTrip(t *testing.T) {
+	tenant := Tenant{
+		Namespace: "team-a",
+		Registry: v1.RegistrySpec{
+			Address:  "registry.team-a.example.com",
+			Secret:   "team-a-registry",
+			Insecure: true,
+		},
+		KitSharing:         true,
+		EnvironmentProfile: "dev",
+	}
+
+	cm := tenant.ConfigMap()
+	assert.Equal(t, TenantConfigMapName, cm.Name)
+	assert.Equal(t, "team-a", cm.Namespace)
+	assert.Equal(t, map[string]string{
+		"registry.address":    "registry.team-a.example.com",
+		"registry.secret":     "team-a-registry",
+		"registry.insecure":   "true",
+		"kit.sharing":         "true",
+		"environment.profile": "dev",
+	}, cm.Data)
+
+	parsed, err := newTenant("team-a", cm.Data)
+	assert.Nil(t, err)
+	assert.Equal(t, tenant, *parsed)
+}
+
+func TestTenantEnvironmentProfile(t *testing.T) {
+	pl := v1.NewIntegrationPlatform("camel-k", DefaultPlatformName)
+	pl.Status.EnvironmentProfile = "prod"
+
+	tenant, err := newTenant("team-a", map[string]string{"environment.profile": "dev"})
+	assert.Nil(t, err)
+	assert.Equal(t, "dev", tenant.ApplyTo(&pl).Status.EnvironmentProfile)
+	assert.Equal(t, "prod", pl.Status.EnvironmentProfile)
+
+	tenant, err = newTenant("team-a", map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, "prod", tenant.ApplyTo(&pl).Status.EnvironmentProfile)
+}
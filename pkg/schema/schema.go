@@ -0,0 +1,215 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/apache/camel-k/pkg/resources"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/defaults"
+)
+
+const (
+	// Path is the path prefix of the operator endpoint serving the JSON schemas.
+	Path = "/schemas/"
+	// TraitsSchema is the name of the JSON schema of the traits configuration.
+	TraitsSchema = "traits.json"
+	// YAMLDSLSchema is the name of the JSON schema of the YAML DSL.
+	YAMLDSLSchema = "yaml-dsl.json"
+
+	draft07 = "http://json-schema.org/draft-07/schema#"
+
+	// The location of the YAML DSL schema, generated for each Camel release.
+	yamlDSLSchemaURL = "https://raw.githubusercontent.com/apache/camel/camel-%s/dsl/camel-yaml-dsl/camel-yaml-dsl/src/generated/resources/schema/camel-yaml-dsl.json"
+)
+
+// Names returns the names of the published JSON schemas.
+func Names() []string {
+	return []string{TraitsSchema, YAMLDSLSchema}
+}
+
+// Generate returns the JSON schema with the given name, for the traits and the catalog bundled with the operator.
+func Generate(name string) ([]byte, error) {
+	var schema map[string]interface{}
+	switch name {
+	case TraitsSchema:
+		s, err := Traits()
+		if err != nil {
+			return nil, err
+		}
+		schema = s
+	case YAMLDSLSchema:
+		catalog, err := camel.DefaultCatalog()
+		if err != nil {
+			return nil, err
+		}
+		schema = YAMLDSL(catalog)
+	default:
+		return nil, fmt.Errorf("unknown schema %s, must be one of %s", name, strings.Join(Names(), ", "))
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+type traitMetaData struct {
+	Traits []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+		Properties  []struct {
+			Name        string `yaml:"name"`
+			Description string `yaml:"description"`
+		} `yaml:"properties"`
+	} `yaml:"traits"`
+}
+
+// Traits returns the JSON schema of the traits configuration, i.e., of the `traits` field of the Integration
+// and IntegrationKit specs.
+func Traits() (map[string]interface{}, error) {
+	content, err := resources.Resource("/traits.yaml")
+	if err != nil {
+		return nil, err
+	}
+	meta := traitMetaData{}
+	if err := yaml.Unmarshal(content, &meta); err != nil {
+		return nil, err
+	}
+	descriptions := make(map[string]string)
+	for _, t := range meta.Traits {
+		descriptions[t.Name] = t.Description
+		for _, p := range t.Properties {
+			descriptions[t.Name+"."+p.Name] = p.Description
+		}
+	}
+
+	properties := make(map[string]interface{})
+	for _, t := range trait.NewCatalog(nil).AllTraits() {
+		id := string(t.ID())
+		configuration := objectSchema(reflect.TypeOf(t), func(property string) string {
+			return descriptions[id+"."+property]
+		})
+		configuration["additionalProperties"] = false
+		traitSchema := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"configuration": configuration,
+			},
+		}
+		if d := descriptions[id]; d != "" {
+			traitSchema["description"] = d
+		}
+		properties[id] = traitSchema
+	}
+
+	return map[string]interface{}{
+		"$schema":     draft07,
+		"$id":         fmt.Sprintf("https://camel.apache.org/schemas/camel-k/%s/%s", defaults.Version, TraitsSchema),
+		"title":       "Camel K traits",
+		"description": fmt.Sprintf("The traits configuration of Camel K %s", defaults.Version),
+		"type":        "object",
+		"properties":  properties,
+	}, nil
+}
+
+// YAMLDSL returns the JSON schema of the YAML DSL supported by the given catalog. It refers to the schema
+// published with the Camel release of the catalog.
+func YAMLDSL(catalog *camel.RuntimeCatalog) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":     draft07,
+		"$id":         fmt.Sprintf("https://camel.apache.org/schemas/camel-k/%s/%s", defaults.Version, YAMLDSLSchema),
+		"title":       "Camel YAML DSL",
+		"description": fmt.Sprintf("The YAML DSL of Camel %s, supported by the Camel K runtime %s", catalog.GetCamelVersion(), catalog.Runtime.Version),
+		"$ref":        fmt.Sprintf(yamlDSLSchemaURL, catalog.GetCamelVersion()),
+	}
+}
+
+// objectSchema returns the schema of the JSON serialized fields of the given struct type. The describe function
+// returns the description of a field from its property name.
+func objectSchema(t reflect.Type, describe func(property string) string) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	properties := make(map[string]interface{})
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				visit(field.Type)
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if field.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			s := typeSchema(field.Type)
+			if describe != nil {
+				if d := describe(strings.Split(field.Tag.Get("property"), ",")[0]); d != "" {
+					s["description"] = d
+				}
+			}
+			properties[name] = s
+		}
+	}
+	visit(t)
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Implements(jsonMarshaler) || reflect.PtrTo(t).Implements(jsonMarshaler) {
+		// The JSON representation is not derived from the Go type
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return objectSchema(t, nil)
+	default:
+		// Any JSON value
+		return map[string]interface{}{}
+	}
+}
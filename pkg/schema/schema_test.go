@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/camel-k/pkg/util/camel"
+)
+
+func TestTraitsSchema(t *testing.T) {
+	s, err := Traits()
+	assert.Nil(t, err)
+
+	assert.Equal(t, draft07, s["$schema"])
+	properties, ok := s["properties"].(map[string]interface{})
+	assert.True(t, ok)
+
+	dns, ok := properties["dns"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.NotEmpty(t, dns["description"])
+
+	configuration := dns["properties"].(map[string]interface{})["configuration"].(map[string]interface{})
+	assert.Equal(t, false, configuration["additionalProperties"])
+	fields := configuration["properties"].(map[string]interface{})
+
+	enabled := fields["enabled"].(map[string]interface{})
+	assert.Equal(t, "boolean", enabled["type"])
+	assert.NotEmpty(t, enabled["description"])
+
+	policy := fields["policy"].(map[string]interface{})
+	assert.Equal(t, "string", policy["type"])
+
+	// The JSON name of the field is used, rather than the property name
+	hostAliases := fields["hostAliases"].(map[string]interface{})
+	assert.Equal(t, "array", hostAliases["type"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, hostAliases["items"])
+	assert.NotEmpty(t, hostAliases["description"])
+	assert.NotContains(t, fields, "host-aliases")
+
+	// Internal fields are not part of the schema
+	assert.NotContains(t, fields, "Client")
+}
+
+func TestYAMLDSLSchema(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	s := YAMLDSL(catalog)
+
+	assert.Equal(t, draft07, s["$schema"])
+	assert.Contains(t, s["$ref"], "/camel-"+catalog.GetCamelVersion()+"/")
+	assert.Contains(t, s["description"], catalog.Runtime.Version)
+}
+
+func TestGenerate(t *testing.T) {
+	for _, name := range Names() {
+		data, err := Generate(name)
+		assert.Nil(t, err)
+
+		s := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal(data, &s))
+		assert.Equal(t, draft07, s["$schema"])
+	}
+
+	_, err := Generate("unknown.json")
+	assert.NotNil(t, err)
+}
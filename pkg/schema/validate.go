@@ -0,0 +1,101 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Validate checks the value, as decoded from JSON, against the subset of JSON schema the traits schema is made of,
+// i.e., the type, properties, additionalProperties and items keywords. It returns the violations, each prefixed
+// with the path of the offending value, relative to the given path.
+func Validate(schema map[string]interface{}, value interface{}, path string) []string {
+	violations := make([]string, 0)
+	validate(schema, value, path, &violations)
+	return violations
+}
+
+func validate(schema map[string]interface{}, value interface{}, path string, violations *[]string) {
+	if value == nil {
+		return
+	}
+	if t, ok := schema["type"].(string); ok && !hasType(value, t) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, found %s", path, t, typeOf(value)))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := path + "." + k
+			if s, ok := properties[k].(map[string]interface{}); ok {
+				validate(s, v[k], p, violations)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*violations = append(*violations, fmt.Sprintf("%s: unknown property", p))
+				}
+			case map[string]interface{}:
+				validate(additional, v[k], p, violations)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validate(items, item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	}
+}
+
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	default:
+		return typeOf(value) == t
+	}
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTraits(t *testing.T) {
+	data, err := Generate(TraitsSchema)
+	assert.Nil(t, err)
+	s := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(data, &s))
+
+	var valid interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"container": {"configuration": {"port": 8088, "name": "integration"}},
+		"builder": {"configuration": {"properties": ["a=b"]}}
+	}`), &valid))
+	assert.Empty(t, Validate(s, valid, "traits"))
+
+	var invalid interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"container": {"configuration": {"port": "8088", "unknown": true}},
+		"builder": {"configuration": {"properties": [1]}},
+		"deployment": {"configuration": {"progressDeadlineSeconds": 1.5}}
+	}`), &invalid))
+	assert.Equal(t, []string{
+		"traits.builder.configuration.properties[0]: expected string, found number",
+		"traits.container.configuration.port: expected integer, found string",
+		"traits.container.configuration.unknown: unknown property",
+		"traits.deployment.configuration.progressDeadlineSeconds: expected integer, found number",
+	}, Validate(s, invalid, "traits"))
+}
@@ -0,0 +1,141 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+const (
+	envVarQuarkusHTTPAccessLogEnabled        = "QUARKUS_HTTP_ACCESS_LOG_ENABLED"
+	envVarQuarkusHTTPAccessLogPattern        = "QUARKUS_HTTP_ACCESS_LOG_PATTERN"
+	envVarQuarkusHTTPAccessLogExcludePattern = "QUARKUS_HTTP_ACCESS_LOG_EXCLUDE_PATTERN"
+	envVarQuarkusHTTPRecordRequestStartTime  = "QUARKUS_HTTP_RECORD_REQUEST_START_TIME"
+	defaultAccessLogExcludePattern           = "/q/.*"
+)
+
+// The Access Log trait enables the logging of the HTTP requests served by the Integration, e.g., by the
+// `platform-http` component or the REST DSL, as structured JSON lines, to meet audit requirements without any
+// change to the routes.
+//
+// The request headers to log must be listed explicitly. The headers holding credentials, like `Authorization`, are
+// redacted, i.e., never logged, even when listed. The query string, that may also hold credentials, is not logged by
+// default. The request and response bodies are never logged.
+//
+// For example:
+//
+// `kamel run -t access-log.enabled=true -t access-log.headers=User-Agent,X-Request-ID`
+//
+// The access log is provided by Quarkus, whose configuration is documented at https://quarkus.io/guides/http-reference.
+//
+// It's disabled by default.
+//
+// +camel-k:trait=access-log.
+type accessLogTrait struct {
+	BaseTrait `property:",squash"`
+	// The request headers to log, e.g., `User-Agent`.
+	Headers []string `property:"headers" json:"headers,omitempty"`
+	// The request headers that are never logged (default `Authorization`, `Proxy-Authorization` and `Cookie`).
+	RedactedHeaders []string `property:"redacted-headers" json:"redactedHeaders,omitempty"`
+	// Whether the query string of the requests is logged (default `false`).
+	QueryString *bool `property:"query-string" json:"queryString,omitempty"`
+	// A regular expression matching the paths of the requests that are not logged (default `/q/.*`, that matches
+	// the health and metrics endpoints).
+	ExcludePattern string `property:"exclude-pattern" json:"excludePattern,omitempty"`
+}
+
+func newAccessLogTrait() Trait {
+	return &accessLogTrait{
+		BaseTrait:       NewBaseTrait("access-log", 810),
+		RedactedHeaders: []string{"Authorization", "Proxy-Authorization", "Cookie"},
+		ExcludePattern:  defaultAccessLogExcludePattern,
+	}
+}
+
+func (t *accessLogTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	for _, h := range t.Headers {
+		if h == "" || strings.ContainsAny(h, `"%{},`) {
+			return false, fmt.Errorf("invalid access log header %q", h)
+		}
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *accessLogTrait) Apply(e *Environment) error {
+	envvar.SetVal(&e.EnvVars, envVarQuarkusHTTPAccessLogEnabled, True)
+	// Required to log the duration of the requests
+	envvar.SetVal(&e.EnvVars, envVarQuarkusHTTPRecordRequestStartTime, True)
+	envvar.SetVal(&e.EnvVars, envVarQuarkusHTTPAccessLogPattern, t.pattern())
+	if t.ExcludePattern != "" {
+		envvar.SetVal(&e.EnvVars, envVarQuarkusHTTPAccessLogExcludePattern, t.ExcludePattern)
+	}
+
+	return nil
+}
+
+// pattern returns the access log pattern, that formats each request as a JSON object.
+func (t *accessLogTrait) pattern() string {
+	fields := []string{
+		`"time":"%t"`,
+		`"remoteHost":"%h"`,
+		`"method":"%m"`,
+		`"path":"%U"`,
+	}
+	if pointer.BoolDeref(t.QueryString, false) {
+		fields = append(fields, `"query":"%q"`)
+	}
+	fields = append(fields,
+		`"protocol":"%H"`,
+		`"status":%s`,
+		`"bytes":"%b"`,
+		`"duration":%D`,
+	)
+
+	headers := make([]string, 0, len(t.Headers))
+	for _, h := range t.Headers {
+		if t.isRedacted(h) {
+			continue
+		}
+		headers = append(headers, fmt.Sprintf(`"%s":"%%{i,%s}"`, h, h))
+	}
+	if len(headers) > 0 {
+		fields = append(fields, `"headers":{`+strings.Join(headers, ",")+`}`)
+	}
+
+	return "{" + strings.Join(fields, ",") + "}"
+}
+
+func (t *accessLogTrait) isRedacted(header string) bool {
+	for _, r := range t.RedactedHeaders {
+		// HTTP header names are case-insensitive
+		if strings.EqualFold(r, header) {
+			return true
+		}
+	}
+	return false
+}
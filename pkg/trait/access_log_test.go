@@ -0,0 +1,108 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+func createNominalAccessLogTest() (*accessLogTrait, *Environment) {
+	trait, _ := newAccessLogTrait().(*accessLogTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	environment := &Environment{
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "integration-name",
+				Namespace: "namespace",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		EnvVars: make([]corev1.EnvVar, 0),
+	}
+
+	return trait, environment
+}
+
+func TestConfigureAccessLogTraitDoesSucceed(t *testing.T) {
+	trait, environment := createNominalAccessLogTest()
+
+	configured, err := trait.Configure(environment)
+
+	assert.Nil(t, err)
+	assert.True(t, configured)
+}
+
+func TestConfigureDisabledAccessLogTraitDoesNotSucceed(t *testing.T) {
+	trait, environment := createNominalAccessLogTest()
+	trait.Enabled = nil
+
+	configured, err := trait.Configure(environment)
+
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureAccessLogTraitWithInvalidHeaderDoesNotSucceed(t *testing.T) {
+	trait, environment := createNominalAccessLogTest()
+	trait.Headers = []string{`X-"Header"`}
+
+	configured, err := trait.Configure(environment)
+
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestApplyAccessLogTrait(t *testing.T) {
+	trait, environment := createNominalAccessLogTest()
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "true", envvar.Get(environment.EnvVars, envVarQuarkusHTTPAccessLogEnabled).Value)
+	assert.Equal(t, "true", envvar.Get(environment.EnvVars, envVarQuarkusHTTPRecordRequestStartTime).Value)
+	assert.Equal(t, "/q/.*", envvar.Get(environment.EnvVars, envVarQuarkusHTTPAccessLogExcludePattern).Value)
+	assert.Equal(t,
+		`{"time":"%t","remoteHost":"%h","method":"%m","path":"%U","protocol":"%H","status":%s,"bytes":"%b","duration":%D}`,
+		envvar.Get(environment.EnvVars, envVarQuarkusHTTPAccessLogPattern).Value)
+}
+
+func TestApplyAccessLogTraitRedactsHeaders(t *testing.T) {
+	trait, environment := createNominalAccessLogTest()
+	trait.Headers = []string{"User-Agent", "authorization", "X-Api-Key"}
+	trait.RedactedHeaders = append(trait.RedactedHeaders, "X-Api-Key")
+	trait.QueryString = pointer.Bool(true)
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Equal(t,
+		`{"time":"%t","remoteHost":"%h","method":"%m","path":"%U","query":"%q","protocol":"%H","status":%s,"bytes":"%b","duration":%D,"headers":{"User-Agent":"%{i,User-Agent}"}}`,
+		envvar.Get(environment.EnvVars, envVarQuarkusHTTPAccessLogPattern).Value)
+}
@@ -19,13 +19,16 @@ package trait
 
 import (
 	"fmt"
+	"path"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/pointer"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/builder"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
 	mvn "github.com/apache/camel-k/pkg/util/maven"
 	"github.com/apache/camel-k/pkg/util/property"
 )
@@ -40,6 +43,15 @@ type builderTrait struct {
 	Verbose *bool `property:"verbose" json:"verbose,omitempty"`
 	// A list of properties to be provided to the build task
 	Properties []string `property:"properties" json:"properties,omitempty"`
+	// Use the images of the existing kits as base layers, when they share dependencies with the kit (default `true`).
+	// Disabling it builds the kit from the platform base image only, e.g., to verify its reproducibility.
+	IncrementalImageBuild *bool `property:"incremental-image-build" json:"incrementalImageBuild,omitempty"`
+	// A list of Maven repository credentials, formatted as `<repository-id>:<secret-name>`, that are only used
+	// by the builds of the integration. The Secret, in the integration namespace, must contain the `username`
+	// and `password` keys, e.g., a `kubernetes.io/basic-auth` Secret. The credentials are added to the Maven
+	// settings as a server, whose id must match the id of the repository declared for the integration.
+	// The kits built with credentials are only reused by the integrations of the same namespace.
+	MavenCredentials []string `property:"maven-credentials" json:"mavenCredentials,omitempty"`
 }
 
 func newBuilderTrait() Trait {
@@ -78,6 +90,12 @@ func (t *builderTrait) Apply(e *Environment) error {
 		return nil
 	}
 
+	servers, err := t.mavenServers(e)
+	if err != nil {
+		return err
+	}
+	builderTask.Maven.Servers = append(builderTask.Maven.Servers, servers...)
+
 	e.BuildTasks = append(e.BuildTasks, v1.Task{Builder: builderTask})
 
 	switch e.Platform.Status.Build.PublishStrategy {
@@ -151,6 +169,22 @@ func (t *builderTrait) Apply(e *Environment) error {
 		}})
 	}
 
+	if export := e.Platform.Status.Build.ImageExport; export != nil {
+		task := &v1.ExportTask{
+			BaseTask: v1.BaseTask{
+				Name: "export",
+			},
+			Registry:              e.Platform.Status.Build.Registry,
+			PersistentVolumeClaim: export.PersistentVolumeClaim,
+			Path:                  path.Join(export.Path, "camel-k-"+e.IntegrationKit.Name+"-"+e.IntegrationKit.ResourceVersion+".tar"),
+		}
+		// The S2I task reports the image it has pushed into the internal registry
+		if e.Platform.Status.Build.PublishStrategy != v1.IntegrationPlatformBuildPublishStrategyS2I {
+			task.Image = getImageName(e)
+		}
+		e.BuildTasks = append(e.BuildTasks, v1.Task{Export: task})
+	}
+
 	return nil
 }
 
@@ -171,6 +205,8 @@ func (t *builderTrait) builderTask(e *Environment) (*v1.BuilderTask, error) {
 		Runtime:      e.CamelCatalog.Runtime,
 		Dependencies: e.IntegrationKit.Spec.Dependencies,
 		Maven:        maven,
+		// Dependencies are scanned once computed, when the platform configures a vulnerability database
+		VulnerabilityScan: e.Platform.Status.Build.VulnerabilityScan.DeepCopy(),
 	}
 
 	if task.Maven.Properties == nil {
@@ -201,10 +237,61 @@ func (t *builderTrait) builderTask(e *Environment) (*v1.BuilderTask, error) {
 	return task, nil
 }
 
+// mavenServers resolves the Maven repository credentials from the Secrets in the namespace the kit has been created for.
+func (t *builderTrait) mavenServers(e *Environment) ([]v1.Server, error) {
+	if len(t.MavenCredentials) == 0 {
+		return nil, nil
+	}
+
+	namespace := e.IntegrationKit.Labels[v1.IntegrationKitCredentialsNamespaceLabel]
+	if namespace == "" {
+		namespace = e.IntegrationKit.Namespace
+	}
+
+	servers := make([]v1.Server, 0, len(t.MavenCredentials))
+	for _, c := range t.MavenCredentials {
+		id, name, err := parseMavenCredentials(c)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := kubernetes.GetSecret(e.Ctx, e.Client, name, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get Secret %s/%s for Maven repository %s: %w", namespace, name, id, err)
+		}
+		username, ok := secret.Data[corev1.BasicAuthUsernameKey]
+		if !ok {
+			return nil, fmt.Errorf("missing key %s in Secret %s/%s", corev1.BasicAuthUsernameKey, namespace, name)
+		}
+		password, ok := secret.Data[corev1.BasicAuthPasswordKey]
+		if !ok {
+			return nil, fmt.Errorf("missing key %s in Secret %s/%s", corev1.BasicAuthPasswordKey, namespace, name)
+		}
+		servers = append(servers, v1.Server{
+			ID:       id,
+			Username: string(username),
+			Password: string(password),
+		})
+	}
+
+	return servers, nil
+}
+
+func parseMavenCredentials(credentials string) (string, string, error) {
+	parts := strings.SplitN(credentials, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("maven credentials must have <repository-id>:<secret-name> format, it was %v", credentials)
+	}
+	return parts[0], parts[1], nil
+}
+
 func getImageName(e *Environment) string {
 	organization := e.Platform.Status.Build.Registry.Organization
 	if organization == "" {
 		organization = e.Platform.Namespace
+		if e.Platform.Status.Build.BuildFarm != nil {
+			// The images built into the farm are published back for the namespace of the kit
+			organization = e.IntegrationKit.Namespace
+		}
 	}
 	return e.Platform.Status.Build.Registry.Address + "/" + organization + "/camel-k-" + e.IntegrationKit.Name + ":" + e.IntegrationKit.ResourceVersion
 }
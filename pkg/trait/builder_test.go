@@ -31,6 +31,7 @@ import (
 	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
 )
 
 func TestBuilderTraitNotAppliedBecauseOfNilKit(t *testing.T) {
@@ -101,6 +102,42 @@ func TestKanikoBuilderTrait(t *testing.T) {
 	assert.NotNil(t, env.BuildTasks[1].Kaniko)
 }
 
+func TestKanikoBuilderTraitInBuildFarm(t *testing.T) {
+	env := createBuilderTestEnv(v1.IntegrationPlatformClusterKubernetes, v1.IntegrationPlatformBuildPublishStrategyKaniko)
+	env.Platform.Namespace = "camel-k"
+	env.Platform.Status.Build.BuildFarm = &v1.IntegrationPlatformBuildFarmSpec{Namespace: "build-farm"}
+	env.IntegrationKit.Name = "kit"
+	env.IntegrationKit.Namespace = "ns"
+	env.IntegrationKit.ResourceVersion = "1"
+	err := NewBuilderTestCatalog().apply(env)
+
+	assert.Nil(t, err)
+	assert.Len(t, env.BuildTasks, 2)
+	assert.NotNil(t, env.BuildTasks[1].Kaniko)
+	assert.Equal(t, "registry/ns/camel-k-kit:1", env.BuildTasks[1].Kaniko.Image)
+}
+
+func TestBuilderTraitWithImageExport(t *testing.T) {
+	env := createBuilderTestEnv(v1.IntegrationPlatformClusterKubernetes, v1.IntegrationPlatformBuildPublishStrategyKaniko)
+	env.Platform.Namespace = "ns"
+	env.Platform.Status.Build.ImageExport = &v1.IntegrationPlatformImageExportSpec{
+		PersistentVolumeClaim: "images",
+		Path:                  "camel-k",
+	}
+	env.IntegrationKit.Name = "kit"
+	env.IntegrationKit.ResourceVersion = "1"
+	err := NewBuilderTestCatalog().apply(env)
+
+	assert.Nil(t, err)
+	assert.Len(t, env.BuildTasks, 3)
+	assert.NotNil(t, env.BuildTasks[1].Kaniko)
+	assert.NotNil(t, env.BuildTasks[2].Export)
+	assert.Equal(t, "export", env.BuildTasks[2].Export.Name)
+	assert.Equal(t, "registry/ns/camel-k-kit:1", env.BuildTasks[2].Export.Image)
+	assert.Equal(t, "images", env.BuildTasks[2].Export.PersistentVolumeClaim)
+	assert.Equal(t, "camel-k/camel-k-kit-1.tar", env.BuildTasks[2].Export.Path)
+}
+
 func createBuilderTestEnv(cluster v1.IntegrationPlatformCluster, strategy v1.IntegrationPlatformBuildPublishStrategy) *Environment {
 	c, err := camel.DefaultCatalog()
 	if err != nil {
@@ -164,6 +201,52 @@ func TestMavenPropertyBuilderTrait(t *testing.T) {
 	assert.Equal(t, "build-time-value1", env.BuildTasks[0].Builder.Maven.Properties["build-time-prop1"])
 }
 
+func TestMavenCredentialsBuilderTrait(t *testing.T) {
+	env := createBuilderTestEnv(v1.IntegrationPlatformClusterKubernetes, v1.IntegrationPlatformBuildPublishStrategyKaniko)
+	env.IntegrationKit.Namespace = "camel-k"
+	env.IntegrationKit.Labels = map[string]string{
+		v1.IntegrationKitCredentialsNamespaceLabel: "ns",
+	}
+	client, _ := test.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "nexus",
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("user"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		},
+	})
+	env.Client = client
+	builderTrait := createNominalBuilderTraitTest()
+	builderTrait.MavenCredentials = []string{"private:nexus"}
+
+	err := builderTrait.Apply(env)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []v1.Server{
+		{
+			ID:       "private",
+			Username: "user",
+			Password: "secret",
+		},
+	}, env.BuildTasks[0].Builder.Maven.Servers)
+}
+
+func TestInvalidMavenCredentialsBuilderTrait(t *testing.T) {
+	env := createBuilderTestEnv(v1.IntegrationPlatformClusterKubernetes, v1.IntegrationPlatformBuildPublishStrategyKaniko)
+	client, _ := test.NewFakeClient()
+	env.Client = client
+	builderTrait := createNominalBuilderTraitTest()
+	builderTrait.MavenCredentials = []string{"nexus"}
+
+	err := builderTrait.Apply(env)
+
+	assert.NotNil(t, err)
+	assert.Empty(t, env.BuildTasks)
+}
+
 func createNominalBuilderTraitTest() *builderTrait {
 	builderTrait, _ := newBuilderTrait().(*builderTrait)
 	builderTrait.Enabled = pointer.Bool(true)
@@ -20,6 +20,7 @@ package trait
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -31,6 +32,7 @@ import (
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/envvar"
 	"github.com/apache/camel-k/pkg/util/maven"
 	"github.com/apache/camel-k/pkg/util/property"
 )
@@ -44,8 +46,15 @@ type camelTrait struct {
 	RuntimeVersion string `property:"runtime-version" json:"runtimeVersion,omitempty"`
 	// A list of properties to be provided to the Integration runtime
 	Properties []string `property:"properties" json:"properties,omitempty"`
+	// The configuration profile activated at runtime, e.g., `dev`, `prod` or a custom profile like `staging`
+	// (default `prod`). The properties prefixed with the profile, e.g., `%staging.my.key`, override the unprefixed ones,
+	// whether they are provided by the user or generated by the operator.
+	Profile string `property:"profile" json:"profile,omitempty"`
 }
 
+// configProfileRegexp matches the name of a Quarkus configuration profile.
+var configProfileRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
 func newCamelTrait() Trait {
 	return &camelTrait{
 		BaseTrait: NewBaseTrait("camel", 200),
@@ -57,6 +66,10 @@ func (t *camelTrait) Configure(e *Environment) (bool, error) {
 		return false, errors.New("trait camel cannot be disabled")
 	}
 
+	if t.Profile != "" && !configProfileRegexp.MatchString(t.Profile) {
+		return false, &ConfigurationError{Trait: t.ID(), Err: fmt.Errorf("invalid configuration profile %q", t.Profile)}
+	}
+
 	return true, nil
 }
 
@@ -80,15 +93,27 @@ func (t *camelTrait) Apply(e *Environment) error {
 		e.IntegrationKit.Status.RuntimeVersion = e.CamelCatalog.Runtime.Version
 		e.IntegrationKit.Status.RuntimeProvider = e.CamelCatalog.Runtime.Provider
 	}
+	if e.IntegrationKitInPhase(v1.IntegrationKitPhaseInitialization) {
+		// Record the dependencies the capabilities provided by the kit have been resolved to
+		e.IntegrationKit.Status.Capabilities = e.CamelCatalog.Runtime.ResolveCapabilities(e.IntegrationKit.Spec.Dependencies)
+	}
 
 	if e.IntegrationKitInPhase(v1.IntegrationKitPhaseReady) && e.IntegrationInRunningPhases() {
 		// Get all resources
-		maps := t.computeConfigMaps(e)
+		maps, err := t.computeConfigMaps(e)
+		if err != nil {
+			return err
+		}
 		if t.Properties != nil {
 			// Only user.properties
 			maps = append(maps, t.computeUserProperties(e)...)
 		}
 		e.Resources.AddAll(maps)
+
+		if t.Profile != "" {
+			// Camel Quarkus configures the Camel context from the Quarkus configuration, so that the profile applies to both
+			envvar.SetVal(&e.EnvVars, "QUARKUS_PROFILE", t.Profile)
+		}
 	}
 
 	return nil
@@ -171,7 +196,7 @@ func (t *camelTrait) IsPlatformTrait() bool {
 	return true
 }
 
-func (t *camelTrait) computeConfigMaps(e *Environment) []ctrl.Object {
+func (t *camelTrait) computeConfigMaps(e *Environment) ([]ctrl.Object, error) {
 	sources := e.Integration.Sources()
 	maps := make([]ctrl.Object, 0, len(sources)+1)
 
@@ -180,8 +205,12 @@ func (t *camelTrait) computeConfigMaps(e *Environment) []ctrl.Object {
 	userProperties := ""
 
 	for _, prop := range e.collectConfigurationPairs("property") {
+		value, err := e.interpolate(prop.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot resolve property %s", prop.Name)
+		}
 		// properties in resource configuration are expected to be pre-encoded using properties format
-		userProperties += fmt.Sprintf("%s=%s\n", prop.Name, prop.Value)
+		userProperties += fmt.Sprintf("%s=%s\n", prop.Name, value)
 	}
 
 	if userProperties != "" {
@@ -286,7 +315,7 @@ func (t *camelTrait) computeConfigMaps(e *Environment) []ctrl.Object {
 		maps = append(maps, &cm)
 	}
 
-	return maps
+	return maps, nil
 }
 
 func (t *camelTrait) computeUserProperties(e *Environment) []ctrl.Object {
@@ -27,6 +27,7 @@ import (
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/envvar"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/test"
 
@@ -132,3 +133,70 @@ func TestApplyCamelTraitWithProperties(t *testing.T) {
 		"application.properties": "a=b\nc=d\n",
 	}, userPropertiesCm.Data)
 }
+
+func TestApplyCamelTraitRecordsKitCapabilities(t *testing.T) {
+	trait, environment := createNominalCamelTest()
+	environment.Integration = nil
+	environment.CamelCatalog.Runtime.Capabilities = map[string]v1.Capability{
+		"health": {
+			Dependencies: []v1.MavenArtifact{
+				{GroupID: "org.apache.camel.quarkus", ArtifactID: "camel-quarkus-microprofile-health"},
+			},
+		},
+		"platform-http": {
+			Dependencies: []v1.MavenArtifact{
+				{GroupID: "org.apache.camel.quarkus", ArtifactID: "camel-quarkus-platform-http"},
+			},
+		},
+		"rest": {
+			Dependencies: []v1.MavenArtifact{
+				{GroupID: "org.apache.camel.quarkus", ArtifactID: "camel-quarkus-platform-http"},
+				{GroupID: "org.apache.camel.quarkus", ArtifactID: "camel-quarkus-rest"},
+			},
+		},
+	}
+	environment.IntegrationKit.Spec.Dependencies = []string{
+		"camel:log",
+		"mvn:org.apache.camel.quarkus:camel-quarkus-platform-http",
+		"mvn:org.apache.camel.quarkus:camel-quarkus-rest",
+	}
+	environment.IntegrationKit.Status.Phase = v1.IntegrationKitPhaseInitialization
+
+	err := trait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Equal(t, []v1.CapabilityMapping{
+		{
+			Name:         "platform-http",
+			Dependencies: []string{"mvn:org.apache.camel.quarkus:camel-quarkus-platform-http"},
+		},
+		{
+			Name: "rest",
+			Dependencies: []string{
+				"mvn:org.apache.camel.quarkus:camel-quarkus-platform-http",
+				"mvn:org.apache.camel.quarkus:camel-quarkus-rest",
+			},
+		},
+	}, environment.IntegrationKit.Status.Capabilities)
+}
+
+func TestApplyCamelTraitWithProfile(t *testing.T) {
+	camelTrait, environment := createNominalCamelTest()
+	camelTrait.Profile = "staging"
+
+	configured, err := camelTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	err = camelTrait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", envvar.Get(environment.EnvVars, "QUARKUS_PROFILE").Value)
+}
+
+func TestConfigureCamelTraitWithInvalidProfile(t *testing.T) {
+	camelTrait, environment := createNominalCamelTest()
+	camelTrait.Profile = "%staging"
+
+	configured, err := camelTrait.Configure(environment)
+	assert.EqualError(t, err, `invalid camel trait configuration: invalid configuration profile "%staging"`)
+	assert.False(t, configured)
+}
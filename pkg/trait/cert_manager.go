@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const (
+	certManagerAPIVersion = "cert-manager.io/v1"
+	certManagerGroup      = "cert-manager.io"
+
+	// CertManagerCertificateNameAnnotation is set by cert-manager on the secrets of the certificates it issues
+	CertManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+
+	certManagerIssuerKind        = "Issuer"
+	certManagerClusterIssuerKind = "ClusterIssuer"
+)
+
+// validateCertManagerIssuerKind checks the kind of the cert-manager issuer, that defaults to Issuer.
+func validateCertManagerIssuerKind(trait string, kind string) error {
+	switch kind {
+	case "", certManagerIssuerKind, certManagerClusterIssuerKind:
+		return nil
+	}
+	return fmt.Errorf("cannot apply %s trait: unsupported cert-manager issuer kind %q, it must be either %s or %s",
+		trait, kind, certManagerIssuerKind, certManagerClusterIssuerKind)
+}
+
+// certificateSecretName returns the name of the secret cert-manager stores the certificate of the Integration into.
+func certificateSecretName(it *v1.Integration) string {
+	return it.Name + "-tls"
+}
+
+// newCertificate returns the cert-manager Certificate for the given host, issued by the given issuer.
+// The issued secret is labelled with the Integration, so that the Integration is reconciled when
+// the certificate is issued, or renewed.
+func newCertificate(it *v1.Integration, namespace string, host string, issuer string, issuerKind string) *unstructured.Unstructured {
+	if issuerKind == "" {
+		issuerKind = certManagerIssuerKind
+	}
+
+	certificate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"secretName": certificateSecretName(it),
+				"dnsNames":   []interface{}{host},
+				"issuerRef": map[string]interface{}{
+					"name":  issuer,
+					"kind":  issuerKind,
+					"group": certManagerGroup,
+				},
+				"secretTemplate": map[string]interface{}{
+					"labels": map[string]interface{}{
+						v1.IntegrationLabel: it.Name,
+					},
+				},
+			},
+		},
+	}
+	certificate.SetAPIVersion(certManagerAPIVersion)
+	certificate.SetKind("Certificate")
+	certificate.SetName(certificateSecretName(it))
+	certificate.SetNamespace(namespace)
+
+	return certificate
+}
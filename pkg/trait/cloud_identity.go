@@ -0,0 +1,212 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+const (
+	cloudIdentityTraitID = "cloud-identity"
+
+	cloudIdentityProviderAWS   = "aws"
+	cloudIdentityProviderGCP   = "gcp"
+	cloudIdentityProviderAzure = "azure"
+
+	awsRoleARNAnnotation          = "eks.amazonaws.com/role-arn"
+	gcpServiceAccountAnnotation   = "iam.gke.io/gcp-service-account"
+	azureClientIDAnnotation       = "azure.workload.identity/client-id"
+	azureTenantIDAnnotation       = "azure.workload.identity/tenant-id"
+	azureWorkloadIdentityUseLabel = "azure.workload.identity/use"
+)
+
+// The Azure components that can authenticate with the Azure Identity library.
+var azureIdentityComponents = []string{
+	"azure-eventhubs",
+	"azure-servicebus",
+	"azure-storage-blob",
+	"azure-storage-datalake",
+	"azure-storage-queue",
+}
+
+// The Cloud Identity trait binds the Integration to a cloud provider identity, so that the cloud components
+// authenticate with short-lived credentials, rather than with access keys stored in the Integration configuration.
+//
+// The trait creates a ServiceAccount dedicated to the Integration, that runs the Integration Pods, and that is annotated
+// for the workload identity mechanism of the provider:
+//
+// * `aws`: IAM Roles for Service Accounts (IRSA), on EKS, with the IAM role to assume
+// * `gcp`: Workload Identity, on GKE, with the IAM service account to impersonate
+// * `azure`: Azure AD Workload Identity, on AKS, with the client ID of the managed identity, or of the application
+//
+// The identity webhook of the provider must be running in the cluster, to inject the projected token into the Pods.
+// The `aws2-*` components, and the Azure Storage, Service Bus and Event Hubs components, used by the Integration are
+// configured to use the default credentials chain of their SDK, while the Google components use the Application Default
+// Credentials when no service account key is configured.
+//
+// The trait can't be used with Integrations that set a service account, which it doesn't manage.
+//
+// +camel-k:trait=cloud-identity.
+type cloudIdentityTrait struct {
+	BaseTrait `property:",squash"`
+	// The cloud provider, one of `aws`, `gcp` or `azure`.
+	Provider string `property:"provider" json:"provider,omitempty"`
+	// The ARN of the AWS IAM role assumed by the Integration, e.g., `arn:aws:iam::123456789012:role/orders`.
+	RoleARN string `property:"role-arn" json:"roleArn,omitempty"`
+	// The AWS region of the services, e.g., `eu-west-1`.
+	Region string `property:"region" json:"region,omitempty"`
+	// The email of the Google Cloud IAM service account impersonated by the Integration,
+	// e.g., `orders@my-project.iam.gserviceaccount.com`.
+	GoogleServiceAccount string `property:"google-service-account" json:"googleServiceAccount,omitempty"`
+	// The Google Cloud project of the services.
+	Project string `property:"project" json:"project,omitempty"`
+	// The client ID of the Azure AD application, or of the user-assigned managed identity, used by the Integration.
+	ClientID string `property:"client-id" json:"clientId,omitempty"`
+	// The Azure AD tenant ID, if it differs from the one the cluster is configured with.
+	TenantID string `property:"tenant-id" json:"tenantId,omitempty"`
+}
+
+func newCloudIdentityTrait() Trait {
+	return &cloudIdentityTrait{
+		// Must run before the container trait, that sets the environment variables and the application properties
+		BaseTrait: NewBaseTrait(cloudIdentityTraitID, 1190),
+	}
+}
+
+func (t *cloudIdentityTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	switch t.Provider {
+	case cloudIdentityProviderAWS:
+		if t.RoleARN == "" {
+			return false, fmt.Errorf("the role ARN must be set for the %s cloud identity provider", t.Provider)
+		}
+	case cloudIdentityProviderGCP:
+		if t.GoogleServiceAccount == "" {
+			return false, fmt.Errorf("the Google service account must be set for the %s cloud identity provider", t.Provider)
+		}
+	case cloudIdentityProviderAzure:
+		if t.ClientID == "" {
+			return false, fmt.Errorf("the client ID must be set for the %s cloud identity provider", t.Provider)
+		}
+	default:
+		return false, fmt.Errorf("unsupported cloud identity provider %q, must be one of %s, %s or %s",
+			t.Provider, cloudIdentityProviderAWS, cloudIdentityProviderGCP, cloudIdentityProviderAzure)
+	}
+
+	if sa := e.Integration.Spec.ServiceAccountName; sa != "" {
+		return false, fmt.Errorf("the cloud-identity trait cannot be used with the %s service account, "+
+			"as it runs the Integration with its own service account", sa)
+	}
+
+	return true, nil
+}
+
+func (t *cloudIdentityTrait) Apply(e *Environment) error {
+	serviceAccount := corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      e.Integration.Name,
+			Namespace: e.Integration.Namespace,
+			Labels: map[string]string{
+				v1.IntegrationLabel: e.Integration.Name,
+			},
+			Annotations: make(map[string]string),
+		},
+	}
+
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+
+	switch t.Provider {
+	case cloudIdentityProviderAWS:
+		serviceAccount.Annotations[awsRoleARNAnnotation] = t.RoleARN
+		if t.Region != "" {
+			envvar.SetVal(&e.EnvVars, "AWS_REGION", t.Region)
+		}
+		for _, component := range t.components(e, func(c string) bool { return strings.HasPrefix(c, "aws2-") }) {
+			e.ApplicationProperties[fmt.Sprintf("camel.component.%s.use-default-credentials-provider", component)] = True
+		}
+	case cloudIdentityProviderGCP:
+		serviceAccount.Annotations[gcpServiceAccountAnnotation] = t.GoogleServiceAccount
+		if t.Project != "" {
+			envvar.SetVal(&e.EnvVars, "GOOGLE_CLOUD_PROJECT", t.Project)
+		}
+	case cloudIdentityProviderAzure:
+		serviceAccount.Annotations[azureClientIDAnnotation] = t.ClientID
+		if t.TenantID != "" {
+			serviceAccount.Annotations[azureTenantIDAnnotation] = t.TenantID
+		}
+		for _, component := range t.components(e, func(c string) bool { return util.StringSliceExists(azureIdentityComponents, c) }) {
+			e.ApplicationProperties[fmt.Sprintf("camel.component.%s.credential-type", component)] = "AZURE_IDENTITY"
+		}
+	}
+
+	e.Resources.Add(&serviceAccount)
+
+	// The Integration Pods are configured once the controller is generated
+	e.PostProcessors = append(e.PostProcessors, func(e *Environment) error {
+		e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+			spec.ServiceAccountName = serviceAccount.Name
+		})
+		if t.Provider == cloudIdentityProviderAzure {
+			e.Resources.VisitPodTemplateMeta(func(meta *metav1.ObjectMeta) {
+				if meta.Labels == nil {
+					meta.Labels = make(map[string]string)
+				}
+				meta.Labels[azureWorkloadIdentityUseLabel] = True
+			})
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// components returns the names of the Camel components the Integration depends on, that match the filter.
+func (t *cloudIdentityTrait) components(e *Environment, filter func(string) bool) []string {
+	components := make([]string, 0)
+	for _, d := range e.Integration.Status.Dependencies {
+		if !strings.HasPrefix(d, "camel:") {
+			continue
+		}
+		if c := strings.TrimPrefix(d, "camel:"); filter(c) {
+			components = append(components, c)
+		}
+	}
+	return components
+}
@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+func TestConfigureCloudIdentityTraitWithInvalidOptions(t *testing.T) {
+	cloudIdentityTrait, environment := createCloudIdentityTest()
+
+	cloudIdentityTrait.Provider = "openstack"
+	configured, err := cloudIdentityTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	cloudIdentityTrait.Provider = cloudIdentityProviderAWS
+	configured, err = cloudIdentityTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	cloudIdentityTrait.RoleARN = "arn:aws:iam::123456789012:role/orders"
+	environment.Integration.Spec.ServiceAccountName = "custom"
+	configured, err = cloudIdentityTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestCloudIdentityAWS(t *testing.T) {
+	cloudIdentityTrait, environment := createCloudIdentityTest()
+	cloudIdentityTrait.Provider = cloudIdentityProviderAWS
+	cloudIdentityTrait.RoleARN = "arn:aws:iam::123456789012:role/orders"
+	cloudIdentityTrait.Region = "eu-west-1"
+	environment.Integration.Status.Dependencies = []string{"camel:aws2-sqs", "camel:log", "mvn:org.acme:aws2-client"}
+
+	configured, err := cloudIdentityTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, cloudIdentityTrait.Apply(environment))
+	assert.Nil(t, runPostProcessors(environment))
+
+	serviceAccount := getCloudIdentityServiceAccount(environment)
+	assert.NotNil(t, serviceAccount)
+	assert.Equal(t, "integration-name", serviceAccount.Name)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/orders", serviceAccount.Annotations[awsRoleARNAnnotation])
+	assert.Equal(t, map[string]string{
+		"camel.component.aws2-sqs.use-default-credentials-provider": "true",
+	}, environment.ApplicationProperties)
+	assert.Equal(t, "eu-west-1", envvar.Get(environment.EnvVars, "AWS_REGION").Value)
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, deployment)
+	assert.Equal(t, "integration-name", deployment.Spec.Template.Spec.ServiceAccountName)
+}
+
+func TestCloudIdentityAzure(t *testing.T) {
+	cloudIdentityTrait, environment := createCloudIdentityTest()
+	cloudIdentityTrait.Provider = cloudIdentityProviderAzure
+	cloudIdentityTrait.ClientID = "00000000-0000-0000-0000-000000000000"
+	environment.Integration.Status.Dependencies = []string{"camel:azure-storage-blob"}
+
+	configured, err := cloudIdentityTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, cloudIdentityTrait.Apply(environment))
+	assert.Nil(t, runPostProcessors(environment))
+
+	serviceAccount := getCloudIdentityServiceAccount(environment)
+	assert.NotNil(t, serviceAccount)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", serviceAccount.Annotations[azureClientIDAnnotation])
+	assert.Equal(t, "AZURE_IDENTITY", environment.ApplicationProperties["camel.component.azure-storage-blob.credential-type"])
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, deployment)
+	assert.Equal(t, "integration-name", deployment.Spec.Template.Spec.ServiceAccountName)
+	assert.Equal(t, "true", deployment.Spec.Template.Labels[azureWorkloadIdentityUseLabel])
+}
+
+func getCloudIdentityServiceAccount(e *Environment) *corev1.ServiceAccount {
+	var serviceAccount *corev1.ServiceAccount
+	e.Resources.VisitMetaObject(func(o metav1.Object) {
+		if sa, ok := o.(*corev1.ServiceAccount); ok {
+			serviceAccount = sa
+		}
+	})
+	return serviceAccount
+}
+
+func createCloudIdentityTest() (*cloudIdentityTrait, *Environment) {
+	_, environment := createStorageTest(1)
+
+	trait, _ := newCloudIdentityTrait().(*cloudIdentityTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	return trait, environment
+}
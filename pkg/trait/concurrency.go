@@ -0,0 +1,208 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util"
+)
+
+const concurrencyTraitID = "concurrency"
+
+var (
+	// The options supported by the Camel thread pool profiles.
+	concurrencyProfileOptions = []string{"pool-size", "max-pool-size", "max-queue-size", "keep-alive-time", "rejected-policy"}
+	// The policies applied to the tasks that a thread pool can't queue.
+	concurrencyRejectedPolicies = []string{"Abort", "CallerRuns", "DiscardOldest", "Discard"}
+	// The option of the components controlling their number of concurrent consumers.
+	concurrencyConsumersOptions = map[string]string{
+		"activemq": "concurrent-consumers",
+		"amqp":     "concurrent-consumers",
+		"jms":      "concurrent-consumers",
+		"kafka":    "consumers-count",
+		"seda":     "concurrent-consumers",
+	}
+)
+
+// The Concurrency trait tunes the thread pools of the Camel context, and the number of concurrent consumers
+// of the messaging components, so that the throughput of an Integration can be adjusted without changing its sources.
+//
+// The default thread pool profile applies to all the thread pools created by Camel, e.g., for the `threads` EIP,
+// or the parallel processing of the `split` EIP. Routes may reference custom thread pool profiles by name instead,
+// e.g., with `executorServiceRef("slow")`, that are configured with the `profiles` parameter.
+//
+// The concurrent consumers are configured at the component level, hence for all the endpoints of the components.
+// They are supported for the `activemq`, `amqp`, `jms`, `kafka` and `seda` components.
+//
+// +camel-k:trait=concurrency.
+type concurrencyTrait struct {
+	BaseTrait `property:",squash"`
+	// The core number of threads of the default thread pool profile.
+	PoolSize *int `property:"pool-size" json:"poolSize,omitempty"`
+	// The maximum number of threads of the default thread pool profile.
+	MaxPoolSize *int `property:"max-pool-size" json:"maxPoolSize,omitempty"`
+	// The maximum number of tasks queued by the default thread pool profile, `-1` for an unbounded queue.
+	MaxQueueSize *int `property:"max-queue-size" json:"maxQueueSize,omitempty"`
+	// How long, in seconds, the threads exceeding the core number are kept idle by the default thread pool profile.
+	KeepAliveTime *int `property:"keep-alive-time" json:"keepAliveTime,omitempty"`
+	// What the default thread pool profile does with the tasks it can't queue, one of `Abort`, `CallerRuns`,
+	// `DiscardOldest` or `Discard`.
+	RejectedPolicy string `property:"rejected-policy" json:"rejectedPolicy,omitempty"`
+	// The options of the custom thread pool profiles, in the `<profile>.<option>=<value>` format,
+	// e.g., `slow.max-pool-size=2`. The supported options are `pool-size`, `max-pool-size`, `max-queue-size`,
+	// `keep-alive-time` and `rejected-policy`.
+	Profiles []string `property:"profiles" json:"profiles,omitempty"`
+	// The number of concurrent consumers of the components, in the `<component>=<count>` format,
+	// e.g., `jms=5` or `kafka=3`.
+	Consumers []string `property:"consumers" json:"consumers,omitempty"`
+}
+
+func newConcurrencyTrait() Trait {
+	return &concurrencyTrait{
+		// Must run before the container trait, that computes the application properties
+		BaseTrait: NewBaseTrait(concurrencyTraitID, 1180),
+	}
+}
+
+func (t *concurrencyTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if _, err := t.properties(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (t *concurrencyTrait) Apply(e *Environment) error {
+	properties, err := t.properties()
+	if err != nil {
+		return err
+	}
+
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+	for k, v := range properties {
+		e.ApplicationProperties[k] = v
+	}
+
+	return nil
+}
+
+// properties returns the application properties mapped from the trait configuration.
+func (t *concurrencyTrait) properties() (map[string]string, error) {
+	properties := make(map[string]string)
+
+	defaults := map[string]*int{
+		"pool-size":       t.PoolSize,
+		"max-pool-size":   t.MaxPoolSize,
+		"max-queue-size":  t.MaxQueueSize,
+		"keep-alive-time": t.KeepAliveTime,
+	}
+	for option, value := range defaults {
+		if value == nil {
+			continue
+		}
+		v := strconv.Itoa(*value)
+		if err := validateConcurrencyProfileOption(option, v); err != nil {
+			return nil, err
+		}
+		properties["camel.threadpool."+option] = v
+	}
+	if t.RejectedPolicy != "" {
+		if err := validateConcurrencyProfileOption("rejected-policy", t.RejectedPolicy); err != nil {
+			return nil, err
+		}
+		properties["camel.threadpool.rejected-policy"] = t.RejectedPolicy
+	}
+
+	for _, p := range t.Profiles {
+		key, value, err := splitConcurrencyEntry(p)
+		if err != nil {
+			return nil, err
+		}
+		i := strings.LastIndex(key, ".")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid thread pool profile option %s, expected format is <profile>.<option>=<value>", p)
+		}
+		profile, option := key[:i], key[i+1:]
+		if err := validateConcurrencyProfileOption(option, value); err != nil {
+			return nil, err
+		}
+		properties[fmt.Sprintf("camel.threadpool.config[%s].%s", profile, option)] = value
+	}
+
+	for _, c := range t.Consumers {
+		component, value, err := splitConcurrencyEntry(c)
+		if err != nil {
+			return nil, err
+		}
+		option, ok := concurrencyConsumersOptions[component]
+		if !ok {
+			return nil, fmt.Errorf("concurrent consumers not supported for component %s", component)
+		}
+		if count, err := strconv.Atoi(value); err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid number of concurrent consumers %s for component %s", value, component)
+		}
+		properties[fmt.Sprintf("camel.component.%s.%s", component, option)] = value
+	}
+
+	return properties, nil
+}
+
+func splitConcurrencyEntry(entry string) (string, string, error) {
+	kv := strings.SplitN(entry, "=", 2)
+	if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+		return "", "", fmt.Errorf("invalid entry %s, expected format is <key>=<value>", entry)
+	}
+	return kv[0], kv[1], nil
+}
+
+func validateConcurrencyProfileOption(option string, value string) error {
+	switch {
+	case !util.StringSliceExists(concurrencyProfileOptions, option):
+		return fmt.Errorf("unsupported thread pool profile option %s", option)
+	case option == "rejected-policy":
+		if !util.StringSliceExists(concurrencyRejectedPolicies, value) {
+			return fmt.Errorf("invalid thread pool rejected policy %s, must be one of %s",
+				value, strings.Join(concurrencyRejectedPolicies, ", "))
+		}
+	default:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid thread pool %s %s: %w", option, value, err)
+		}
+		// The maximum queue size is the only option accepting -1, for unbounded queues
+		if n < 0 && !(option == "max-queue-size" && n == -1) {
+			return fmt.Errorf("invalid thread pool %s %s, must not be negative", option, value)
+		}
+	}
+	return nil
+}
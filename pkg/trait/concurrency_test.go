@@ -0,0 +1,109 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestConfigureConcurrencyTraitIsDisabledByDefault(t *testing.T) {
+	concurrencyTrait, environment := createConcurrencyTest()
+	concurrencyTrait.Enabled = nil
+
+	configured, err := concurrencyTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureConcurrencyTraitWithInvalidOptions(t *testing.T) {
+	concurrencyTrait, environment := createConcurrencyTest()
+
+	concurrencyTrait.RejectedPolicy = "Retry"
+	configured, err := concurrencyTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	concurrencyTrait.RejectedPolicy = ""
+	concurrencyTrait.Profiles = []string{"slow.core-size=2"}
+	configured, err = concurrencyTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	concurrencyTrait.Profiles = nil
+	concurrencyTrait.Consumers = []string{"timer=2"}
+	configured, err = concurrencyTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	concurrencyTrait.Consumers = []string{"jms=0"}
+	configured, err = concurrencyTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConcurrencyApplicationProperties(t *testing.T) {
+	concurrencyTrait, environment := createConcurrencyTest()
+	concurrencyTrait.PoolSize = pointer.Int(5)
+	concurrencyTrait.MaxPoolSize = pointer.Int(20)
+	concurrencyTrait.MaxQueueSize = pointer.Int(-1)
+	concurrencyTrait.RejectedPolicy = "CallerRuns"
+	concurrencyTrait.Profiles = []string{"slow.max-pool-size=2", "slow.rejected-policy=Abort"}
+	concurrencyTrait.Consumers = []string{"jms=5", "kafka=3"}
+
+	configured, err := concurrencyTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, concurrencyTrait.Apply(environment))
+	assert.Equal(t, map[string]string{
+		"camel.threadpool.pool-size":                    "5",
+		"camel.threadpool.max-pool-size":                "20",
+		"camel.threadpool.max-queue-size":               "-1",
+		"camel.threadpool.rejected-policy":              "CallerRuns",
+		"camel.threadpool.config[slow].max-pool-size":   "2",
+		"camel.threadpool.config[slow].rejected-policy": "Abort",
+		"camel.component.jms.concurrent-consumers":      "5",
+		"camel.component.kafka.consumers-count":         "3",
+	}, environment.ApplicationProperties)
+}
+
+func createConcurrencyTest() (*concurrencyTrait, *Environment) {
+	trait, _ := newConcurrencyTrait().(*concurrencyTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	environment := &Environment{
+		Catalog: NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "integration-name",
+				Namespace: "namespace",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+	}
+
+	return trait, environment
+}
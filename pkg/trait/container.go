@@ -20,30 +20,43 @@ package trait
 import (
 	"fmt"
 	"path"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util"
 	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/envvar"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/property"
+	"github.com/apache/camel-k/pkg/util/registry"
 )
 
+// resolveDigest is the function resolving the digest of the integration image, that can be replaced in tests.
+var resolveDigest = registry.ResolveDigest
+
 const (
 	defaultContainerName     = "integration"
 	defaultContainerPort     = 8080
 	defaultContainerPortName = "http"
 	defaultServicePort       = 80
 	containerTraitID         = "container"
+	containerTmpVolumeName   = "tmp"
 )
 
 // The Container trait can be used to configure properties of the container where the integration will run.
@@ -76,12 +89,51 @@ type containerTrait struct {
 	ServicePort int `property:"service-port" json:"servicePort,omitempty"`
 	// To configure under which service port name the container port is to be exposed (default `http`).
 	ServicePortName string `property:"service-port-name" json:"servicePortName,omitempty"`
+	// The ports exposed by the container, with the `name:containerPort[:servicePort]` format, e.g., `grpc:9090:9090`.
+	// The ports are also added to the Service, when the container is exposed. The service port defaults to the container port.
+	// A port named after the `port-name` option, i.e., `http` by default, configures the main port.
+	// Knative Services only support a single port, so the other ports are ignored.
+	Ports []string `property:"ports" json:"ports,omitempty"`
 	// The main container name. It's named `integration` by default.
 	Name string `property:"name" json:"name,omitempty"`
 	// The main container image
 	Image string `property:"image" json:"image,omitempty"`
 	// The pull policy: Always|Never|IfNotPresent
 	ImagePullPolicy corev1.PullPolicy `property:"image-pull-policy" json:"imagePullPolicy,omitempty"`
+	// Resolves the tag of the integration image to the digest of the image in the registry, when it is deployed,
+	// and pins the container to that digest, so that the rollouts are immutable, and the `IfNotPresent` pull policy
+	// is safe with mutable tags. The digest is recorded into the Integration status, and is only resolved again
+	// when the Integration image changes.
+	ResolveDigest *bool `property:"resolve-digest" json:"resolveDigest,omitempty"`
+	// Additional containers to run alongside the integration container, e.g. `sidecars[0].name=proxy`, `sidecars[0].image=my/proxy:1.0`.
+	// Each sidecar supports the `name`, `image`, `image-pull-policy`, `command`, `args`, `env` (`NAME=value`),
+	// `mounts` (`volume:/path`), `request-cpu`, `request-memory`, `limit-cpu` and `limit-memory` properties.
+	// A mounted volume that is not declared by the Pod is created as an `emptyDir` volume, so that it can be shared among sidecars.
+	Sidecars []containerSidecar `property:"sidecars" json:"sidecars,omitempty"`
+	// The UID to run the integration container as.
+	RunAsUser *int64 `property:"run-as-user" json:"runAsUser,omitempty"`
+	// Requires the integration container to run as a non-root user.
+	RunAsNonRoot *bool `property:"run-as-non-root" json:"runAsNonRoot,omitempty"`
+	// Mounts the root filesystem of the integration container as read-only. A writable `emptyDir` volume is then mounted on `/tmp`.
+	ReadOnlyRootFilesystem *bool `property:"read-only-root-filesystem" json:"readOnlyRootFilesystem,omitempty"`
+	// Controls whether the integration process can gain more privileges than its parent process.
+	AllowPrivilegeEscalation *bool `property:"allow-privilege-escalation" json:"allowPrivilegeEscalation,omitempty"`
+	// The Linux capabilities to drop from the integration container, e.g., `ALL`.
+	DropCapabilities []string `property:"drop-capabilities" json:"dropCapabilities,omitempty"`
+	// The seccomp profile of the integration container: RuntimeDefault|Unconfined|Localhost
+	SeccompProfileType corev1.SeccompProfileType `property:"seccomp-profile-type" json:"seccompProfileType,omitempty"`
+	// The seccomp profile file, relative to the kubelet seccomp profiles directory, when the `Localhost` type is set.
+	SeccompLocalhostProfile string `property:"seccomp-localhost-profile" json:"seccompLocalhostProfile,omitempty"`
+	// Fails the Integration when a resource quantity cannot be parsed (default `true`).
+	// When disabled, the invalid quantities are only logged and ignored.
+	ValidateResources *bool `property:"validate-resources" json:"validateResources,omitempty"`
+	// The labels added to the Integration Pods, e.g., `pod-labels.team=payments`. They are merged with the default Pod labels
+	// of the IntegrationPlatform, and override them. An empty value removes a default label.
+	PodLabels map[string]string `property:"pod-labels" json:"podLabels,omitempty"`
+	// The annotations added to the Integration Pods. They are merged with the default Pod annotations of the IntegrationPlatform,
+	// and override them. An empty value removes a default annotation. The keys containing dots or slashes, like most annotations,
+	// must be set in the Integration resource, as they cannot be expressed with the CLI.
+	PodAnnotations map[string]string `property:"pod-annotations" json:"podAnnotations,omitempty"`
 
 	// DeprecatedProbesEnabled enable/disable probes on the container (default `false`)
 	// Deprecated: replaced by the health trait.
@@ -128,6 +180,32 @@ type containerTrait struct {
 	DeprecatedReadinessFailureThreshold int32 `property:"readiness-failure-threshold" json:"readinessFailureThreshold,omitempty"`
 }
 
+// containerSidecar describes a container running in the integration Pod, next to the integration container.
+type containerSidecar struct {
+	// The sidecar container name.
+	Name string `property:"name" json:"name,omitempty"`
+	// The sidecar container image.
+	Image string `property:"image" json:"image,omitempty"`
+	// The pull policy: Always|Never|IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `property:"image-pull-policy" json:"imagePullPolicy,omitempty"`
+	// The entrypoint, replacing the one of the image.
+	Command []string `property:"command" json:"command,omitempty"`
+	// The arguments passed to the entrypoint.
+	Args []string `property:"args" json:"args,omitempty"`
+	// The environment variables, with the `NAME=value` format.
+	Env []string `property:"env" json:"env,omitempty"`
+	// The volume mounts, with the `volume:/path` format.
+	Mounts []string `property:"mounts" json:"mounts,omitempty"`
+	// The minimum amount of CPU required.
+	RequestCPU string `property:"request-cpu" json:"requestCPU,omitempty"`
+	// The minimum amount of memory required.
+	RequestMemory string `property:"request-memory" json:"requestMemory,omitempty"`
+	// The maximum amount of CPU required.
+	LimitCPU string `property:"limit-cpu" json:"limitCPU,omitempty"`
+	// The maximum amount of memory required.
+	LimitMemory string `property:"limit-memory" json:"limitMemory,omitempty"`
+}
+
 func newContainerTrait() Trait {
 	return &containerTrait{
 		BaseTrait:                 NewBaseTrait(containerTraitID, 1600),
@@ -161,9 +239,147 @@ func (t *containerTrait) Configure(e *Environment) (bool, error) {
 		return false, fmt.Errorf("unsupported pull policy %s", t.ImagePullPolicy)
 	}
 
+	if err := t.validateSeccompProfile(); err != nil {
+		return false, &ConfigurationError{Trait: t.ID(), Err: err}
+	}
+
+	if pointer.BoolDeref(t.ValidateResources, true) {
+		if err := t.validateResources(); err != nil {
+			return false, &ConfigurationError{Trait: t.ID(), Err: err}
+		}
+	}
+
+	mappings, err := t.portMappings()
+	if err != nil {
+		return false, &ConfigurationError{Trait: t.ID(), Err: err}
+	}
+	for _, mapping := range mappings {
+		if mapping.name == t.primaryPortName() {
+			t.Port = int(mapping.containerPort)
+			t.ServicePort = int(mapping.servicePort)
+		}
+	}
+
+	if err = t.validateSidecars(); err != nil {
+		return false, &ConfigurationError{Trait: t.ID(), Err: err}
+	}
+
+	if err = t.validatePodMetadata(); err != nil {
+		return false, &ConfigurationError{Trait: t.ID(), Err: err}
+	}
+
 	return true, nil
 }
 
+func (t *containerTrait) validateSeccompProfile() error {
+	switch t.SeccompProfileType {
+	case "", corev1.SeccompProfileTypeRuntimeDefault, corev1.SeccompProfileTypeUnconfined:
+		if t.SeccompLocalhostProfile != "" {
+			return fmt.Errorf("the seccomp localhost profile requires the %s seccomp profile type", corev1.SeccompProfileTypeLocalhost)
+		}
+	case corev1.SeccompProfileTypeLocalhost:
+		if t.SeccompLocalhostProfile == "" {
+			return fmt.Errorf("the %s seccomp profile type requires a localhost profile", corev1.SeccompProfileTypeLocalhost)
+		}
+	default:
+		return fmt.Errorf("unsupported seccomp profile type %s", t.SeccompProfileType)
+	}
+	return nil
+}
+
+func (t *containerTrait) validateResources() error {
+	quantities := []struct {
+		name  string
+		value string
+	}{
+		{"request-cpu", t.RequestCPU},
+		{"request-memory", t.RequestMemory},
+		{"limit-cpu", t.LimitCPU},
+		{"limit-memory", t.LimitMemory},
+	}
+	for _, q := range quantities {
+		if q.value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q.value); err != nil {
+			return fmt.Errorf("invalid %s quantity %q: %w", q.name, q.value, err)
+		}
+	}
+	return nil
+}
+
+func (t *containerTrait) validateSidecars() error {
+	names := map[string]bool{t.Name: true}
+	for i, sidecar := range t.Sidecars {
+		if err := validateSidecar("sidecar", i, sidecar, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSidecar checks the sidecar configuration, and records its name in the names already used by the Pod containers.
+func validateSidecar(kind string, index int, sidecar containerSidecar, names map[string]bool) error {
+	if sidecar.Name == "" {
+		return fmt.Errorf("%s %d: a name is required", kind, index)
+	}
+	if sidecar.Image == "" {
+		return fmt.Errorf("%s %s: an image is required", kind, sidecar.Name)
+	}
+	if names[sidecar.Name] {
+		return fmt.Errorf("%s %s: the name is already used by another container", kind, sidecar.Name)
+	}
+	names[sidecar.Name] = true
+	if !isValidPullPolicy(sidecar.ImagePullPolicy) {
+		return fmt.Errorf("%s %s: unsupported pull policy %s", kind, sidecar.Name, sidecar.ImagePullPolicy)
+	}
+	for _, env := range sidecar.Env {
+		if k, _ := property.SplitPropertyFileEntry(env); k == "" || !strings.Contains(env, "=") {
+			return fmt.Errorf("%s %s: invalid environment variable %q, expected NAME=value", kind, sidecar.Name, env)
+		}
+	}
+	for _, mount := range sidecar.Mounts {
+		if _, _, err := parseSidecarMount(mount); err != nil {
+			return fmt.Errorf("%s %s: %w", kind, sidecar.Name, err)
+		}
+	}
+	for _, quantity := range []string{sidecar.RequestCPU, sidecar.RequestMemory, sidecar.LimitCPU, sidecar.LimitMemory} {
+		if quantity == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(quantity); err != nil {
+			return fmt.Errorf("%s %s: invalid resource quantity %q: %w", kind, sidecar.Name, quantity, err)
+		}
+	}
+	return nil
+}
+
+func (t *containerTrait) validatePodMetadata() error {
+	for k, v := range t.PodLabels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid pod label %q: %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q of pod label %q: %s", v, k, strings.Join(errs, ", "))
+		}
+	}
+	for k := range t.PodAnnotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid pod annotation %q: %s", k, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// parseSidecarMount parses a mount with the `volume:/path` format.
+func parseSidecarMount(mount string) (string, string, error) {
+	parts := strings.SplitN(mount, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || !strings.HasPrefix(parts[1], "/") {
+		return "", "", fmt.Errorf("invalid mount %q, expected volume:/path", mount)
+	}
+	return parts[0], parts[1], nil
+}
+
 func isValidPullPolicy(policy corev1.PullPolicy) bool {
 	return policy == "" || policy == corev1.PullAlways || policy == corev1.PullIfNotPresent || policy == corev1.PullNever
 }
@@ -172,7 +388,20 @@ func (t *containerTrait) Apply(e *Environment) error {
 	if err := t.configureImageIntegrationKit(e); err != nil {
 		return err
 	}
-	return t.configureContainer(e)
+	if pointer.BoolDeref(t.ResolveDigest, false) && e.IntegrationInRunningPhases() {
+		if err := t.resolveImageDigest(e); err != nil {
+			return err
+		}
+	}
+	if err := t.configureContainer(e); err != nil {
+		return err
+	}
+	t.configurePodMetadata(e)
+	if len(t.Sidecars) > 0 {
+		// The sidecars are added once the volumes of the Pod are declared by the other traits
+		e.PostProcessors = append(e.PostProcessors, t.configureSidecars)
+	}
+	return nil
 }
 
 // IsPlatformTrait overrides base class method.
@@ -180,6 +409,11 @@ func (t *containerTrait) IsPlatformTrait() bool {
 	return true
 }
 
+// RequiresTraits makes sure the controller resources and the Service exist before the container is configured.
+func (t *containerTrait) RequiresTraits() []ID {
+	return []ID{environmentTraitID, "deployment", knativeServiceTraitID, "cron", "job", serviceTraitID}
+}
+
 func (t *containerTrait) configureImageIntegrationKit(e *Environment) error {
 	if t.Image != "" {
 		if e.Integration.Spec.IntegrationKit != nil {
@@ -187,32 +421,13 @@ func (t *containerTrait) configureImageIntegrationKit(e *Environment) error {
 				"unsupported configuration: a container image has been set in conjunction with an IntegrationKit %v",
 				e.Integration.Spec.IntegrationKit)
 		}
-
-		kitName := fmt.Sprintf("kit-%s", e.Integration.Name)
-		kit := v1.NewIntegrationKit(e.Integration.Namespace, kitName)
-		kit.Spec.Image = t.Image
-
-		// Add some information for post-processing, this may need to be refactored
-		// to a proper data structure
-		kit.Labels = map[string]string{
-			v1.IntegrationKitTypeLabel:            v1.IntegrationKitTypeExternal,
-			kubernetes.CamelCreatorLabelKind:      v1.IntegrationKind,
-			kubernetes.CamelCreatorLabelName:      e.Integration.Name,
-			kubernetes.CamelCreatorLabelNamespace: e.Integration.Namespace,
-			kubernetes.CamelCreatorLabelVersion:   e.Integration.ResourceVersion,
-		}
-
-		if kit.Annotations == nil {
-			kit.Annotations = make(map[string]string)
-		}
-		if v, ok := e.Integration.Annotations[v1.PlatformSelectorAnnotation]; ok {
-			kit.Annotations[v1.PlatformSelectorAnnotation] = v
-		}
-		operatorID := defaults.OperatorID()
-		if operatorID != "" {
-			kit.Annotations[v1.OperatorIDAnnotation] = operatorID
+		if e.Integration.Spec.IntegrationKitDigest != "" {
+			return fmt.Errorf(
+				"unsupported configuration: a container image has been set in conjunction with the IntegrationKit image digest %s",
+				e.Integration.Spec.IntegrationKitDigest)
 		}
 
+		kit := newExternalIntegrationKit(e, fmt.Sprintf("kit-%s", e.Integration.Name), t.Image)
 		t.L.Infof("image %s", kit.Spec.Image)
 		e.Resources.Add(kit)
 		e.Integration.SetIntegrationKit(kit)
@@ -220,6 +435,69 @@ func (t *containerTrait) configureImageIntegrationKit(e *Environment) error {
 	return nil
 }
 
+// newExternalIntegrationKit returns a kit for an image built outside of the platform.
+func newExternalIntegrationKit(e *Environment, name string, image string) *v1.IntegrationKit {
+	kit := v1.NewIntegrationKit(e.Integration.Namespace, name)
+	kit.Spec.Image = image
+
+	// Add some information for post-processing, this may need to be refactored
+	// to a proper data structure
+	kit.Labels = map[string]string{
+		v1.IntegrationKitTypeLabel:            v1.IntegrationKitTypeExternal,
+		kubernetes.CamelCreatorLabelKind:      v1.IntegrationKind,
+		kubernetes.CamelCreatorLabelName:      e.Integration.Name,
+		kubernetes.CamelCreatorLabelNamespace: e.Integration.Namespace,
+		kubernetes.CamelCreatorLabelVersion:   e.Integration.ResourceVersion,
+	}
+
+	if kit.Annotations == nil {
+		kit.Annotations = make(map[string]string)
+	}
+	if v, ok := e.Integration.Annotations[v1.PlatformSelectorAnnotation]; ok {
+		kit.Annotations[v1.PlatformSelectorAnnotation] = v
+	}
+	operatorID := defaults.OperatorID()
+	if operatorID != "" {
+		kit.Annotations[v1.OperatorIDAnnotation] = operatorID
+	}
+
+	return kit
+}
+
+// resolveImageDigest pins the Integration image to its digest, using the credentials of the platform registry if any.
+func (t *containerTrait) resolveImageDigest(e *Environment) error {
+	image := e.Integration.Status.Image
+	if image == "" || strings.Contains(image, "@") {
+		return nil
+	}
+
+	insecure := false
+	var dockerConfig []byte
+	if e.Platform != nil {
+		reg := e.Platform.Status.Build.Registry
+		insecure = reg.Insecure && reg.Address != "" && strings.HasPrefix(image, reg.Address)
+		if reg.Secret != "" {
+			key := ctrl.ObjectKey{Namespace: e.Tenant.RegistryNamespace(e.Platform), Name: reg.Secret}
+			secret := corev1.Secret{}
+			if err := t.Client.Get(e.Ctx, key, &secret); err != nil {
+				return err
+			}
+			if secret.Type == corev1.SecretTypeDockerConfigJson {
+				dockerConfig = secret.Data[corev1.DockerConfigJsonKey]
+			}
+		}
+	}
+
+	pinned, err := resolveDigest(e.Ctx, image, insecure, dockerConfig)
+	if err != nil {
+		return fmt.Errorf("cannot resolve the digest of image %s: %w", image, err)
+	}
+	t.L.ForIntegration(e.Integration).Infof("Image %s pinned to %s", image, pinned)
+	e.Integration.Status.Image = pinned
+
+	return nil
+}
+
 func (t *containerTrait) configureContainer(e *Environment) error {
 	if e.ApplicationProperties == nil {
 		e.ApplicationProperties = make(map[string]string)
@@ -235,10 +513,16 @@ func (t *containerTrait) configureContainer(e *Environment) error {
 		container.ImagePullPolicy = t.ImagePullPolicy
 	}
 
-	// combine Environment of integration with platform, kit, integration
-	for _, env := range e.collectConfigurationPairs("env") {
+	// Environment variables precedence, from the lowest to the highest, is:
+	// traits < platform configuration < kit configuration < integration configuration < user (environment trait)
+	envVars := e.collectEnvVars()
+	for _, env := range envVars {
 		envvar.SetVal(&container.Env, env.Name, env.Value)
 	}
+	override := false
+	if et, ok := e.GetTrait(environmentTraitID).(*environmentTrait); ok {
+		override = pointer.BoolDeref(et.TraitVarsOverride, false)
+	}
 
 	envvar.SetVal(&container.Env, "CAMEL_K_DIGEST", e.Integration.Status.Digest)
 	envvar.SetVal(&container.Env, "CAMEL_K_CONF", path.Join(camel.BasePath, "application.properties"))
@@ -252,6 +536,7 @@ func (t *containerTrait) configureContainer(e *Environment) error {
 	}
 
 	t.configureResources(e, &container)
+	t.configureSecurityContext(&container)
 	if pointer.BoolDeref(t.Expose, false) {
 		t.configureService(e, &container)
 	}
@@ -259,11 +544,12 @@ func (t *containerTrait) configureContainer(e *Environment) error {
 
 	var containers *[]corev1.Container
 	visited := false
+	knativeService := false
 
 	// Deployment
 	if err := e.Resources.VisitDeploymentE(func(deployment *appsv1.Deployment) error {
 		for _, envVar := range e.EnvVars {
-			envvar.SetVar(&container.Env, envVar)
+			setTraitEnvVar(&container.Env, envVar, override)
 		}
 
 		containers = &deployment.Spec.Template.Spec.Containers
@@ -278,20 +564,21 @@ func (t *containerTrait) configureContainer(e *Environment) error {
 		for _, env := range e.EnvVars {
 			switch {
 			case env.ValueFrom == nil:
-				envvar.SetVar(&container.Env, env)
+				setTraitEnvVar(&container.Env, env, override)
 			case env.ValueFrom.FieldRef != nil && env.ValueFrom.FieldRef.FieldPath == "metadata.namespace":
-				envvar.SetVar(&container.Env, corev1.EnvVar{Name: env.Name, Value: e.Integration.Namespace})
+				setTraitEnvVar(&container.Env, corev1.EnvVar{Name: env.Name, Value: e.Integration.Namespace}, override)
 			case env.ValueFrom.FieldRef != nil:
 				t.L.Infof("Skipping environment variable %s (fieldRef)", env.Name)
 			case env.ValueFrom.ResourceFieldRef != nil:
 				t.L.Infof("Skipping environment variable %s (resourceFieldRef)", env.Name)
 			default:
-				envvar.SetVar(&container.Env, env)
+				setTraitEnvVar(&container.Env, env, override)
 			}
 		}
 
 		containers = &service.Spec.ConfigurationSpec.Template.Spec.Containers
 		visited = true
+		knativeService = true
 		return nil
 	}); err != nil {
 		return err
@@ -300,7 +587,7 @@ func (t *containerTrait) configureContainer(e *Environment) error {
 	// CronJob
 	if err := e.Resources.VisitCronJobE(func(cron *v1beta1.CronJob) error {
 		for _, envVar := range e.EnvVars {
-			envvar.SetVar(&container.Env, envVar)
+			setTraitEnvVar(&container.Env, envVar, override)
 		}
 
 		containers = &cron.Spec.JobTemplate.Spec.Template.Spec.Containers
@@ -310,13 +597,63 @@ func (t *containerTrait) configureContainer(e *Environment) error {
 		return err
 	}
 
+	// Job
+	if err := e.Resources.VisitJobE(func(job *batchv1.Job) error {
+		for _, envVar := range e.EnvVars {
+			setTraitEnvVar(&container.Env, envVar, override)
+		}
+
+		containers = &job.Spec.Template.Spec.Containers
+		visited = true
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if override {
+		// The user environment variables still take precedence over the ones injected by the traits
+		for _, env := range envVars {
+			if env.Source == ConfigurationSourceUser {
+				envvar.SetVal(&container.Env, env.Name, env.Value)
+			}
+		}
+	}
+
+	if len(t.Ports) > 0 {
+		if knativeService {
+			t.L.Info("Knative Services only support a single port, ignoring the other ports", "ports", t.Ports)
+		} else if err := t.configureAdditionalPorts(e, &container); err != nil {
+			return err
+		}
+	}
+
 	if visited {
 		*containers = append(*containers, container)
 	}
 
+	if pointer.BoolDeref(t.ReadOnlyRootFilesystem, false) {
+		e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+			spec.Volumes = append(spec.Volumes, corev1.Volume{
+				Name: containerTmpVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
+		})
+	}
+
 	return nil
 }
 
+// setTraitEnvVar sets an environment variable injected by the traits, unless it's already defined with a higher
+// precedence, or override is true.
+func setTraitEnvVar(vars *[]corev1.EnvVar, envVar corev1.EnvVar, override bool) {
+	if !override && envvar.Get(*vars, envVar.Name) != nil {
+		return
+	}
+	envvar.SetVar(vars, envVar)
+}
+
 func (t *containerTrait) configureService(e *Environment, container *corev1.Container) {
 	service := e.Resources.GetServiceForIntegration(e.Integration)
 	if service == nil {
@@ -359,6 +696,87 @@ func (t *containerTrait) configureService(e *Environment, container *corev1.Cont
 	service.Labels["camel.apache.org/service.type"] = v1.ServiceTypeUser
 }
 
+// containerPortMapping maps a container port to the Service port it's exposed with.
+type containerPortMapping struct {
+	name          string
+	containerPort int32
+	servicePort   int32
+}
+
+func (t *containerTrait) primaryPortName() string {
+	if t.PortName == "" {
+		return defaultContainerPortName
+	}
+	return t.PortName
+}
+
+// portMappings parses the ports, with the `name:containerPort[:servicePort]` format.
+func (t *containerTrait) portMappings() ([]containerPortMapping, error) {
+	names := make(map[string]bool)
+
+	mappings := make([]containerPortMapping, 0, len(t.Ports))
+	for _, p := range t.Ports {
+		parts := strings.Split(p, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid port %q, expected name:containerPort[:servicePort]", p)
+		}
+		if names[parts[0]] {
+			return nil, fmt.Errorf("invalid port %q, the name %s is already used", p, parts[0])
+		}
+		names[parts[0]] = true
+
+		mapping := containerPortMapping{name: parts[0]}
+		numbers := []*int32{&mapping.containerPort, &mapping.servicePort}
+		for i, n := range parts[1:] {
+			v, err := strconv.ParseInt(n, 10, 32)
+			if err != nil || v < 1 || v > 65535 {
+				return nil, fmt.Errorf("invalid port %q, %s is not a valid port number", p, n)
+			}
+			*numbers[i] = int32(v)
+		}
+		if mapping.servicePort == 0 {
+			mapping.servicePort = mapping.containerPort
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+func (t *containerTrait) configureAdditionalPorts(e *Environment, container *corev1.Container) error {
+	mappings, err := t.portMappings()
+	if err != nil {
+		return err
+	}
+
+	var service *corev1.Service
+	if pointer.BoolDeref(t.Expose, false) {
+		service = e.Resources.GetServiceForIntegration(e.Integration)
+	}
+
+	for _, mapping := range mappings {
+		if mapping.name == t.primaryPortName() {
+			// The main port is configured with the Service
+			continue
+		}
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          mapping.name,
+			ContainerPort: mapping.containerPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+		if service != nil {
+			service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+				Name:       mapping.name,
+				Port:       mapping.servicePort,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromString(mapping.name),
+			})
+		}
+	}
+
+	return nil
+}
+
 func (t *containerTrait) configureResources(_ *Environment, container *corev1.Container) {
 	// Requests
 	if container.Resources.Requests == nil {
@@ -405,6 +823,166 @@ func (t *containerTrait) configureResources(_ *Environment, container *corev1.Co
 	}
 }
 
+func (t *containerTrait) configureSecurityContext(container *corev1.Container) {
+	if t.RunAsUser == nil && t.RunAsNonRoot == nil && t.ReadOnlyRootFilesystem == nil && t.AllowPrivilegeEscalation == nil &&
+		len(t.DropCapabilities) == 0 && t.SeccompProfileType == "" {
+		return
+	}
+
+	sc := corev1.SecurityContext{
+		RunAsUser:                t.RunAsUser,
+		RunAsNonRoot:             t.RunAsNonRoot,
+		ReadOnlyRootFilesystem:   t.ReadOnlyRootFilesystem,
+		AllowPrivilegeEscalation: t.AllowPrivilegeEscalation,
+	}
+	if len(t.DropCapabilities) > 0 {
+		sc.Capabilities = &corev1.Capabilities{}
+		for _, c := range t.DropCapabilities {
+			sc.Capabilities.Drop = append(sc.Capabilities.Drop, corev1.Capability(c))
+		}
+	}
+	if t.SeccompProfileType != "" {
+		sc.SeccompProfile = &corev1.SeccompProfile{
+			Type: t.SeccompProfileType,
+		}
+		if t.SeccompLocalhostProfile != "" {
+			sc.SeccompProfile.LocalhostProfile = pointer.String(t.SeccompLocalhostProfile)
+		}
+	}
+	container.SecurityContext = &sc
+
+	// The JVM, and the libraries it runs, need a writable temporary directory
+	if pointer.BoolDeref(t.ReadOnlyRootFilesystem, false) {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      containerTmpVolumeName,
+			MountPath: "/tmp",
+		})
+	}
+}
+
+// configurePodMetadata adds the default Pod labels and annotations of the platform, overridden by the ones of the trait,
+// to the Pod template. The labels and annotations already set by the other traits, like the Integration label, take precedence.
+func (t *containerTrait) configurePodMetadata(e *Environment) {
+	var labels, annotations map[string]string
+	if e.Platform != nil {
+		labels = e.Platform.Status.PodLabels
+		annotations = e.Platform.Status.PodAnnotations
+	}
+	labels = mergePodMetadata(labels, t.PodLabels)
+	annotations = mergePodMetadata(annotations, t.PodAnnotations)
+	if len(labels) == 0 && len(annotations) == 0 {
+		return
+	}
+
+	e.Resources.VisitPodTemplateMeta(func(meta *metav1.ObjectMeta) {
+		meta.Labels = withDefaults(meta.Labels, labels)
+		meta.Annotations = withDefaults(meta.Annotations, annotations)
+	})
+}
+
+// mergePodMetadata overrides the platform defaults with the values of the trait, where an empty value removes the default.
+func mergePodMetadata(platform map[string]string, overrides map[string]string) map[string]string {
+	res := make(map[string]string, len(platform)+len(overrides))
+	for k, v := range platform {
+		res[k] = v
+	}
+	for k, v := range overrides {
+		if v == "" {
+			delete(res, k)
+		} else {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// withDefaults adds the default entries that are missing from the given map.
+func withDefaults(values map[string]string, entries map[string]string) map[string]string {
+	if len(entries) == 0 {
+		return values
+	}
+	if values == nil {
+		values = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		if _, ok := values[k]; !ok {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+func (t *containerTrait) configureSidecars(e *Environment) error {
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		for _, sidecar := range t.Sidecars {
+			spec.Containers = append(spec.Containers, newSidecarContainer(spec, sidecar))
+		}
+	})
+
+	return nil
+}
+
+// newSidecarContainer creates the container described by the sidecar, and declares the Pod volumes it mounts
+// that are missing as `emptyDir` volumes.
+func newSidecarContainer(spec *corev1.PodSpec, sidecar containerSidecar) corev1.Container {
+	container := corev1.Container{
+		Name:            sidecar.Name,
+		Image:           sidecar.Image,
+		ImagePullPolicy: sidecar.ImagePullPolicy,
+		Command:         sidecar.Command,
+		Args:            sidecar.Args,
+		Resources:       sidecarResources(sidecar),
+	}
+	for _, env := range sidecar.Env {
+		k, v := property.SplitPropertyFileEntry(env)
+		envvar.SetVal(&container.Env, k, v)
+	}
+	for _, mount := range sidecar.Mounts {
+		// The format has been validated when configuring the trait
+		volume, mountPath, _ := parseSidecarMount(mount)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      volume,
+			MountPath: mountPath,
+		})
+		if !hasVolume(spec, volume) {
+			spec.Volumes = append(spec.Volumes, corev1.Volume{
+				Name: volume,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
+		}
+	}
+	return container
+}
+
+func sidecarResources(sidecar containerSidecar) corev1.ResourceRequirements {
+	resources := corev1.ResourceRequirements{}
+	set := func(list *corev1.ResourceList, name corev1.ResourceName, quantity string) {
+		if quantity == "" {
+			return
+		}
+		if *list == nil {
+			*list = make(corev1.ResourceList)
+		}
+		(*list)[name] = resource.MustParse(quantity)
+	}
+	set(&resources.Requests, corev1.ResourceCPU, sidecar.RequestCPU)
+	set(&resources.Requests, corev1.ResourceMemory, sidecar.RequestMemory)
+	set(&resources.Limits, corev1.ResourceCPU, sidecar.LimitCPU)
+	set(&resources.Limits, corev1.ResourceMemory, sidecar.LimitMemory)
+	return resources
+}
+
+func hasVolume(spec *corev1.PodSpec, name string) bool {
+	for _, volume := range spec.Volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *containerTrait) configureCapabilities(e *Environment) {
 	if util.StringSliceExists(e.Integration.Status.Capabilities, v1.CapabilityRest) {
 		e.ApplicationProperties["camel.context.rest-configuration.component"] = "platform-http"
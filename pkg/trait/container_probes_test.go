@@ -168,3 +168,130 @@ func TestProbesOnKnativeService(t *testing.T) {
 	assert.Equal(t, defaultReadinessProbePath, container.ReadinessProbe.HTTPGet.Path)
 	assert.Equal(t, int32(1234), container.LivenessProbe.TimeoutSeconds)
 }
+
+func TestProbesRouteSupervision(t *testing.T) {
+	integration := &v1.Integration{
+		Spec: v1.IntegrationSpec{
+			Traits: map[string]v1.TraitSpec{
+				"health": test.TraitSpecFromMap(t, map[string]interface{}{
+					"enabled":                 true,
+					"routeSupervision":        true,
+					"routeBackOffDelay":       5000,
+					"routeBackOffMaxAttempts": 10,
+				}),
+			},
+			Configuration: []v1.ConfigurationSpec{
+				{Type: "property", Value: "camel.main.route-controller-back-off-delay=1000"},
+			},
+		},
+	}
+
+	env := newTestProbesEnv(t, integration)
+	env.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+
+	err := env.Catalog.apply(&env)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []v1.ConfigurationSpec{
+		{Type: "property", Value: "camel.main.route-controller-back-off-delay=5000"},
+		{Type: "property", Value: "camel.main.route-controller-back-off-max-attempts=10"},
+		{Type: "property", Value: "camel.main.route-controller-supervise-enabled=true"},
+		{Type: "property", Value: "camel.main.route-controller-unhealthy-on-exhausted=true"},
+	}, env.Integration.Status.Configuration)
+
+	// the user properties take precedence
+	assert.Contains(t, env.collectConfigurationPairs("property"), Variable{
+		Name:   "camel.main.route-controller-back-off-delay",
+		Value:  "1000",
+		Source: ConfigurationSourceIntegration,
+	})
+}
+
+func TestStartupProbeOnDeployment(t *testing.T) {
+	integration := &v1.Integration{
+		Spec: v1.IntegrationSpec{
+			Traits: map[string]v1.TraitSpec{
+				"health": test.TraitSpecFromMap(t, map[string]interface{}{
+					"enabled":                 true,
+					"livenessProbeEnabled":    true,
+					"startupProbeEnabled":     true,
+					"startupPath":             "/q/health/started",
+					"startupPeriod":           10,
+					"startupFailureThreshold": 30,
+				}),
+			},
+		},
+	}
+
+	env := newTestProbesEnv(t, integration)
+	env.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+
+	err := env.Catalog.apply(&env)
+	assert.Nil(t, err)
+
+	container := env.GetIntegrationContainer()
+
+	assert.NotNil(t, container.LivenessProbe)
+	assert.NotNil(t, container.ReadinessProbe)
+	assert.NotNil(t, container.StartupProbe)
+	assert.Equal(t, "/q/health/started", container.StartupProbe.HTTPGet.Path)
+	assert.Equal(t, corev1.URISchemeHTTP, container.StartupProbe.HTTPGet.Scheme)
+	assert.Equal(t, int32(defaultContainerPort), container.StartupProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, int32(10), container.StartupProbe.PeriodSeconds)
+	assert.Equal(t, int32(30), container.StartupProbe.FailureThreshold)
+}
+
+func TestStartupProbeDefaultPath(t *testing.T) {
+	integration := &v1.Integration{
+		Spec: v1.IntegrationSpec{
+			Traits: map[string]v1.TraitSpec{
+				"health": test.TraitSpecFromMap(t, map[string]interface{}{
+					"enabled":               true,
+					"readinessProbeEnabled": false,
+					"startupProbeEnabled":   true,
+				}),
+			},
+		},
+	}
+
+	env := newTestProbesEnv(t, integration)
+	env.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+
+	err := env.Catalog.apply(&env)
+	assert.Nil(t, err)
+
+	container := env.GetIntegrationContainer()
+
+	assert.Nil(t, container.LivenessProbe)
+	assert.Nil(t, container.ReadinessProbe)
+	assert.NotNil(t, container.StartupProbe)
+	assert.Equal(t, defaultLivenessProbePath, container.StartupProbe.HTTPGet.Path)
+}
+
+func TestStartupProbeOnKnativeService(t *testing.T) {
+	integration := &v1.Integration{
+		Spec: v1.IntegrationSpec{
+			Profile: v1.TraitProfileKnative,
+			Traits: map[string]v1.TraitSpec{
+				"knative-service": test.TraitSpecFromMap(t, map[string]interface{}{
+					"enabled": true,
+				}),
+				"health": test.TraitSpecFromMap(t, map[string]interface{}{
+					"enabled":             true,
+					"startupProbeEnabled": true,
+				}),
+			},
+		},
+	}
+
+	env := newTestProbesEnv(t, integration)
+	env.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+
+	err := env.Catalog.apply(&env)
+	assert.Nil(t, err)
+
+	container := env.GetIntegrationContainer()
+
+	assert.NotNil(t, container.ReadinessProbe)
+	assert.Nil(t, container.StartupProbe)
+}
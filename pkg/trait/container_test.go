@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -313,3 +314,467 @@ func TestContainerWithImagePullPolicy(t *testing.T) {
 
 	assert.Equal(t, corev1.PullAlways, container.ImagePullPolicy)
 }
+
+func TestContainerWithSidecars(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	client, _ := test.NewFakeClient()
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		Ctx:          context.TODO(),
+		Client:       client,
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"container": test.TraitSpecFromMap(t, map[string]interface{}{
+						"sidecars": []map[string]interface{}{
+							{
+								"name":          "shipper",
+								"image":         "log/shipper:1.0",
+								"env":           []string{"TARGET=http://collector:9000"},
+								"mounts":        []string{"logs:/var/log/integration"},
+								"requestMemory": "64Mi",
+								"limitCPU":      "200m",
+							},
+							{
+								"name":   "proxy",
+								"image":  "local/proxy:1.0",
+								"args":   []string{"--listen", ":15000"},
+								"mounts": []string{"logs:/logs"},
+							},
+						},
+					}),
+				},
+			},
+		},
+		Platform:  &v1.IntegrationPlatform{},
+		Resources: kubernetes.NewCollection(),
+	}
+	environment.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+	environment.Platform.ResyncStatusFullConfig()
+
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+
+	d := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, d)
+
+	containers := d.Spec.Template.Spec.Containers
+	assert.Len(t, containers, 3)
+	assert.Equal(t, defaultContainerName, containers[0].Name)
+
+	shipper := containers[1]
+	assert.Equal(t, "shipper", shipper.Name)
+	assert.Equal(t, "log/shipper:1.0", shipper.Image)
+	assert.Equal(t, []corev1.EnvVar{{Name: "TARGET", Value: "http://collector:9000"}}, shipper.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "logs", MountPath: "/var/log/integration"}}, shipper.VolumeMounts)
+	assert.Equal(t, "64Mi", shipper.Resources.Requests.Memory().String())
+	assert.Equal(t, "200m", shipper.Resources.Limits.Cpu().String())
+
+	proxy := containers[2]
+	assert.Equal(t, "proxy", proxy.Name)
+	assert.Equal(t, []string{"--listen", ":15000"}, proxy.Args)
+	assert.Nil(t, proxy.Resources.Requests)
+
+	// The shared volume is declared once
+	volumes := 0
+	for _, volume := range d.Spec.Template.Spec.Volumes {
+		if volume.Name == "logs" {
+			assert.NotNil(t, volume.EmptyDir)
+			volumes++
+		}
+	}
+	assert.Equal(t, 1, volumes)
+}
+
+func TestContainerWithInvalidSidecars(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sidecars []containerSidecar
+		err      string
+	}{
+		{
+			name:     "missing image",
+			sidecars: []containerSidecar{{Name: "proxy"}},
+			err:      "invalid container trait configuration: sidecar proxy: an image is required",
+		},
+		{
+			name:     "integration container name",
+			sidecars: []containerSidecar{{Name: defaultContainerName, Image: "local/proxy"}},
+			err:      "invalid container trait configuration: sidecar integration: the name is already used by another container",
+		},
+		{
+			name:     "invalid mount",
+			sidecars: []containerSidecar{{Name: "proxy", Image: "local/proxy", Mounts: []string{"/logs"}}},
+			err:      `invalid container trait configuration: sidecar proxy: invalid mount "/logs", expected volume:/path`,
+		},
+		{
+			name:     "invalid env",
+			sidecars: []containerSidecar{{Name: "proxy", Image: "local/proxy", Env: []string{"TARGET"}}},
+			err:      `invalid container trait configuration: sidecar proxy: invalid environment variable "TARGET", expected NAME=value`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			trait, _ := newContainerTrait().(*containerTrait)
+			trait.Sidecars = tc.sidecars
+
+			environment := &Environment{
+				Integration: &v1.Integration{
+					Status: v1.IntegrationStatus{
+						Phase: v1.IntegrationPhaseDeploying,
+					},
+				},
+				Resources: kubernetes.NewCollection(),
+			}
+
+			configured, err := trait.Configure(environment)
+			assert.False(t, configured)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestContainerWithInvalidResources(t *testing.T) {
+	trait, _ := newContainerTrait().(*containerTrait)
+	trait.LimitMemory = "512mi"
+
+	environment := &Environment{
+		Integration: &v1.Integration{
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(),
+	}
+
+	configured, err := trait.Configure(environment)
+	assert.False(t, configured)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid limit-memory quantity "512mi"`)
+
+	var cerr *ConfigurationError
+	assert.ErrorAs(t, err, &cerr)
+	assert.Equal(t, ID(containerTraitID), cerr.Trait)
+}
+
+func TestContainerWithInvalidResourcesNotValidated(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	client, _ := test.NewFakeClient()
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		Ctx:          context.TODO(),
+		Client:       client,
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"container": test.TraitSpecFromMap(t, map[string]interface{}{
+						"validateResources": false,
+						"requestMemory":     "256Mi",
+						"limitMemory":       "512mi",
+					}),
+				},
+			},
+		},
+		Platform:  &v1.IntegrationPlatform{},
+		Resources: kubernetes.NewCollection(),
+	}
+	environment.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+	environment.Platform.ResyncStatusFullConfig()
+
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+
+	container := environment.GetIntegrationContainer()
+	assert.Equal(t, "256Mi", container.Resources.Requests.Memory().String())
+	assert.NotContains(t, container.Resources.Limits, corev1.ResourceMemory)
+}
+
+func TestContainerWithSecurityContext(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	client, _ := test.NewFakeClient()
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		Ctx:          context.TODO(),
+		Client:       client,
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"container": test.TraitSpecFromMap(t, map[string]interface{}{
+						"runAsUser":                1001,
+						"runAsNonRoot":             true,
+						"readOnlyRootFilesystem":   true,
+						"allowPrivilegeEscalation": false,
+						"dropCapabilities":         []string{"ALL"},
+						"seccompProfileType":       "RuntimeDefault",
+					}),
+				},
+			},
+		},
+		Platform:  &v1.IntegrationPlatform{},
+		Resources: kubernetes.NewCollection(),
+	}
+	environment.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+	environment.Platform.ResyncStatusFullConfig()
+
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+
+	container := environment.GetIntegrationContainer()
+	assert.Equal(t, &corev1.SecurityContext{
+		RunAsUser:                pointer.Int64(1001),
+		RunAsNonRoot:             pointer.Bool(true),
+		ReadOnlyRootFilesystem:   pointer.Bool(true),
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}, container.SecurityContext)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: containerTmpVolumeName, MountPath: "/tmp"})
+
+	d := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Contains(t, d.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         containerTmpVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+}
+
+func TestContainerWithInvalidSeccompProfile(t *testing.T) {
+	trait, _ := newContainerTrait().(*containerTrait)
+	environment := &Environment{
+		Integration: &v1.Integration{
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(),
+	}
+
+	trait.SeccompProfileType = corev1.SeccompProfileTypeLocalhost
+	configured, err := trait.Configure(environment)
+	assert.False(t, configured)
+	assert.EqualError(t, err, "invalid container trait configuration: the Localhost seccomp profile type requires a localhost profile")
+
+	trait.SeccompLocalhostProfile = "profiles/integration.json"
+	configured, err = trait.Configure(environment)
+	assert.True(t, configured)
+	assert.Nil(t, err)
+}
+
+func TestContainerWithoutSecurityContext(t *testing.T) {
+	trait, _ := newContainerTrait().(*containerTrait)
+	container := corev1.Container{}
+
+	trait.configureSecurityContext(&container)
+	assert.Nil(t, container.SecurityContext)
+}
+
+func TestContainerWithInvalidPorts(t *testing.T) {
+	testCases := []struct {
+		ports []string
+		err   string
+	}{
+		{ports: []string{"grpc"}, err: `invalid port "grpc", expected name:containerPort[:servicePort]`},
+		{ports: []string{"grpc:9090", "grpc:9091"}, err: `invalid port "grpc:9091", the name grpc is already used`},
+		{ports: []string{"grpc:9090:70000"}, err: `invalid port "grpc:9090:70000", 70000 is not a valid port number`},
+	}
+
+	for _, tc := range testCases {
+		trait, _ := newContainerTrait().(*containerTrait)
+		trait.Ports = tc.ports
+
+		_, err := trait.portMappings()
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+func TestContainerWithPodMetadata(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	client, _ := test.NewFakeClient()
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		Ctx:          context.TODO(),
+		Client:       client,
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ServiceTestName,
+				Namespace: "ns",
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"container": test.TraitSpecFromMap(t, map[string]interface{}{
+						"podLabels": map[string]string{
+							"team":              "payments",
+							"policy/exempt":     "",
+							v1.IntegrationLabel: "other",
+						},
+						"podAnnotations": map[string]string{
+							"container.apparmor.security.beta.kubernetes.io/integration": "localhost/integration",
+						},
+					}),
+				},
+			},
+		},
+		Platform: &v1.IntegrationPlatform{
+			Spec: v1.IntegrationPlatformSpec{
+				PodLabels: map[string]string{
+					"team":          "platform",
+					"policy/exempt": "true",
+					"environment":   "production",
+				},
+				PodAnnotations: map[string]string{
+					"container.apparmor.security.beta.kubernetes.io/integration": "runtime/default",
+					"policy.example.com/audit":                                   "true",
+				},
+			},
+		},
+		Resources: kubernetes.NewCollection(),
+	}
+	environment.Integration.Status.Phase = v1.IntegrationPhaseDeploying
+	environment.Platform.ResyncStatusFullConfig()
+
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+
+	d := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, d)
+	assert.Equal(t, ServiceTestName, d.Spec.Template.Labels[v1.IntegrationLabel])
+	assert.Equal(t, "payments", d.Spec.Template.Labels["team"])
+	assert.Equal(t, "production", d.Spec.Template.Labels["environment"])
+	assert.NotContains(t, d.Spec.Template.Labels, "policy/exempt")
+	assert.Equal(t, "localhost/integration", d.Spec.Template.Annotations["container.apparmor.security.beta.kubernetes.io/integration"])
+	assert.Equal(t, "true", d.Spec.Template.Annotations["policy.example.com/audit"])
+}
+
+func TestContainerWithInvalidPodLabels(t *testing.T) {
+	trait, _ := newContainerTrait().(*containerTrait)
+	environment := &Environment{
+		Integration: &v1.Integration{
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(),
+	}
+
+	trait.PodLabels = map[string]string{"team": "payments and billing"}
+	configured, err := trait.Configure(environment)
+	assert.False(t, configured)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid value "payments and billing" of pod label "team"`)
+}
+
+func TestContainerWithResolvedDigest(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "registry-secret",
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry":{"auth":"bmljOnBhc3M="}}}`),
+		},
+	}
+	client, _ := test.NewFakeClient(secret)
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		Ctx:          context.TODO(),
+		Client:       client,
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ServiceTestName,
+				Namespace: "ns",
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"container": test.TraitSpecFromMap(t, map[string]interface{}{
+						"resolveDigest":   true,
+						"imagePullPolicy": "IfNotPresent",
+					}),
+				},
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+				Image: "registry/camel-k/integration:latest",
+			},
+		},
+		Platform: &v1.IntegrationPlatform{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+			},
+			Spec: v1.IntegrationPlatformSpec{
+				Build: v1.IntegrationPlatformBuildSpec{
+					Registry: v1.RegistrySpec{
+						Address:  "registry",
+						Insecure: true,
+						Secret:   "registry-secret",
+					},
+				},
+			},
+		},
+		Resources: kubernetes.NewCollection(),
+	}
+	environment.Platform.ResyncStatusFullConfig()
+
+	calls := 0
+	defer func(resolve func(context.Context, string, bool, []byte) (string, error)) {
+		resolveDigest = resolve
+	}(resolveDigest)
+	resolveDigest = func(ctx context.Context, image string, insecure bool, dockerConfig []byte) (string, error) {
+		calls++
+		assert.Equal(t, "registry/camel-k/integration:latest", image)
+		assert.True(t, insecure)
+		assert.Equal(t, secret.Data[corev1.DockerConfigJsonKey], dockerConfig)
+		return "registry/camel-k/integration@sha256:0123456789abcdef", nil
+	}
+
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "registry/camel-k/integration@sha256:0123456789abcdef", environment.Integration.Status.Image)
+
+	container := environment.GetIntegrationContainer()
+	assert.Equal(t, "registry/camel-k/integration@sha256:0123456789abcdef", container.Image)
+	assert.Equal(t, corev1.PullIfNotPresent, container.ImagePullPolicy)
+
+	// The pinned image is not resolved again
+	environment.Resources = kubernetes.NewCollection()
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,197 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util"
+)
+
+const (
+	correlationTraitID           = "correlation"
+	correlationSourceName        = "camel-k-embedded-correlation.yaml"
+	correlationTraceIDProperty   = "CamelKCorrelationTraceId"
+	correlationTraceParentHeader = "traceparent"
+	defaultCorrelationHeader     = "X-Request-ID"
+)
+
+var (
+	correlationHeaderRegexp    = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	correlationComponentRegexp = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// The Correlation trait injects the standard correlation and trace headers into the requests sent by the HTTP
+// producers of the Integration, so that the requests can be correlated across the services, without any change
+// to the routes.
+//
+// The correlation ID header, `X-Request-ID` by default, is propagated when the exchange already holds it, e.g.,
+// when it's been received by the `platform-http` consumer, or set to the exchange ID otherwise. Likewise, the
+// W3C Trace Context `traceparent` header is propagated, or a new trace is started.
+//
+// The headers are set by a Camel route configuration, that intercepts the endpoints of the HTTP producer components,
+// and applies to all the routes of the Integration.
+//
+// For example:
+//
+// `kamel run -t correlation.enabled=true -t correlation.components=http,https,knative`
+//
+// NOTE: When the Integration is instrumented with a tracer, e.g., with the `opentelemetry` trait, the trace context
+// is managed by the tracer, and the `trace-context` option should be disabled.
+//
+// It's disabled by default.
+//
+// +camel-k:trait=correlation.
+type correlationTrait struct {
+	BaseTrait `property:",squash"`
+	// The name of the correlation ID header (default `X-Request-ID`).
+	Header string `property:"header" json:"header,omitempty"`
+	// Whether the W3C Trace Context `traceparent` header is propagated, or started when missing (default `true`).
+	TraceContext *bool `property:"trace-context" json:"traceContext,omitempty"`
+	// The HTTP producer components the headers are injected into (default `http`, `https`, `vertx-http` and `netty-http`).
+	Components []string `property:"components" json:"components,omitempty"`
+}
+
+func newCorrelationTrait() Trait {
+	return &correlationTrait{
+		// NOTE: Must run before dependency trait
+		BaseTrait:  NewBaseTrait(correlationTraitID, 480),
+		Header:     defaultCorrelationHeader,
+		Components: []string{"http", "https", "vertx-http", "netty-http"},
+	}
+}
+
+func (t *correlationTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !correlationHeaderRegexp.MatchString(t.Header) {
+		return false, fmt.Errorf("invalid correlation header %q", t.Header)
+	}
+	if len(t.Components) == 0 {
+		return false, fmt.Errorf("cannot apply %s trait: no component configured", correlationTraitID)
+	}
+	for _, c := range t.Components {
+		if !correlationComponentRegexp.MatchString(c) {
+			return false, fmt.Errorf("invalid correlation component %q", c)
+		}
+	}
+
+	return e.IntegrationInPhase(v1.IntegrationPhaseInitialization), nil
+}
+
+func (t *correlationTrait) Apply(e *Environment) error {
+	content, err := yaml.Marshal(t.routeConfiguration())
+	if err != nil {
+		return err
+	}
+
+	e.Integration.Status.AddOrReplaceGeneratedSources(v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    correlationSourceName,
+			Content: string(content),
+		},
+		Language: v1.LanguageYaml,
+	})
+
+	if pointer.BoolDeref(t.TraceContext, true) {
+		// The trace ID is generated with the method language
+		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, "camel:bean")
+	}
+
+	return nil
+}
+
+// routeConfiguration returns the route configuration intercepting the HTTP producer endpoints. As it has no ID,
+// it applies to all the routes.
+func (t *correlationTrait) routeConfiguration() []map[string]interface{} {
+	steps := []interface{}{
+		setHeaderIfMissing(t.Header, map[string]interface{}{
+			"set-header": map[string]interface{}{
+				"name":   t.Header,
+				"simple": "${exchangeId}",
+			},
+		}),
+	}
+
+	if pointer.BoolDeref(t.TraceContext, true) {
+		// The trace and parent IDs are derived from a random UUID, formatted as
+		// 00-<trace-id: 32 hex digits>-<parent-id: 16 hex digits>-<flags: sampled>
+		traceID := fmt.Sprintf("${exchangeProperty.%s.toString().replace('-', '')}", correlationTraceIDProperty)
+		parentID := fmt.Sprintf("${exchangeProperty.%s.toString().replace('-', '').substring(16)}", correlationTraceIDProperty)
+		steps = append(steps, setHeaderIfMissing(correlationTraceParentHeader,
+			map[string]interface{}{
+				"set-property": map[string]interface{}{
+					"name": correlationTraceIDProperty,
+					"method": map[string]interface{}{
+						"bean-type": "java.util.UUID",
+						"method":    "randomUUID",
+					},
+				},
+			},
+			map[string]interface{}{
+				"set-header": map[string]interface{}{
+					"name":   correlationTraceParentHeader,
+					"simple": fmt.Sprintf("00-%s-%s-01", traceID, parentID),
+				},
+			},
+			map[string]interface{}{
+				"remove-property": map[string]interface{}{
+					"name": correlationTraceIDProperty,
+				},
+			},
+		))
+	}
+
+	return []map[string]interface{}{
+		{
+			"route-configuration": map[string]interface{}{
+				"intercept-send-to-endpoint": []interface{}{
+					map[string]interface{}{
+						"intercept-send-to-endpoint": map[string]interface{}{
+							"uri":   fmt.Sprintf("(%s):.*", strings.Join(t.Components, "|")),
+							"steps": steps,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// setHeaderIfMissing returns the choice running the given steps when the exchange doesn't hold the header.
+func setHeaderIfMissing(header string, steps ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"choice": map[string]interface{}{
+			"when": []interface{}{
+				map[string]interface{}{
+					"simple": fmt.Sprintf("${header[%s]} == null", header),
+					"steps":  steps,
+				},
+			},
+		},
+	}
+}
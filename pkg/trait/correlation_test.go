@@ -0,0 +1,136 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestCorrelationConfigure(t *testing.T) {
+	e := &Environment{
+		Catalog:     NewEnvironmentTestCatalog(),
+		Integration: &v1.Integration{},
+	}
+	e.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+
+	trait, _ := newCorrelationTrait().(*correlationTrait)
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	trait.Enabled = pointer.Bool(true)
+	enabled, err = trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	e.Integration.Status.Phase = v1.IntegrationPhaseRunning
+	enabled, err = trait.Configure(e)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+}
+
+func TestCorrelationConfigureInvalid(t *testing.T) {
+	e := &Environment{
+		Catalog:     NewEnvironmentTestCatalog(),
+		Integration: &v1.Integration{},
+	}
+	e.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+
+	trait, _ := newCorrelationTrait().(*correlationTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	trait.Header = "X-Request-ID}"
+	_, err := trait.Configure(e)
+	assert.NotNil(t, err)
+
+	trait.Header = defaultCorrelationHeader
+	trait.Components = []string{"http:*"}
+	_, err = trait.Configure(e)
+	assert.NotNil(t, err)
+
+	trait.Components = nil
+	_, err = trait.Configure(e)
+	assert.NotNil(t, err)
+}
+
+func TestCorrelationApplySource(t *testing.T) {
+	e := &Environment{
+		Catalog:     NewEnvironmentTestCatalog(),
+		Integration: &v1.Integration{},
+	}
+	e.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+
+	trait, _ := newCorrelationTrait().(*correlationTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.Header = "X-Correlation-ID"
+	trait.Components = []string{"http", "knative"}
+
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+
+	assert.Len(t, e.Integration.Status.GeneratedSources, 1)
+	source := e.Integration.Status.GeneratedSources[0]
+	assert.Equal(t, correlationSourceName, source.Name)
+	assert.Equal(t, v1.LanguageYaml, source.Language)
+	assert.Contains(t, e.Integration.Status.Dependencies, "camel:bean")
+
+	var flows []map[string]map[string][]map[string]map[string]interface{}
+	assert.Nil(t, yaml.Unmarshal([]byte(source.Content), &flows))
+	assert.Len(t, flows, 1)
+	intercepts := flows[0]["route-configuration"]["intercept-send-to-endpoint"]
+	assert.Len(t, intercepts, 1)
+	intercept := intercepts[0]["intercept-send-to-endpoint"]
+	assert.Equal(t, "(http|knative):.*", intercept["uri"])
+	assert.Len(t, intercept["steps"], 2)
+
+	assert.Contains(t, source.Content, "${header[X-Correlation-ID]} == null")
+	assert.Contains(t, source.Content, "name: X-Correlation-ID")
+	assert.Contains(t, source.Content, "${header[traceparent]} == null")
+	assert.Contains(t, source.Content, "bean-type: java.util.UUID")
+}
+
+func TestCorrelationApplySourceWithoutTraceContext(t *testing.T) {
+	e := &Environment{
+		Catalog:     NewEnvironmentTestCatalog(),
+		Integration: &v1.Integration{},
+	}
+	e.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+
+	trait, _ := newCorrelationTrait().(*correlationTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.TraceContext = pointer.Bool(false)
+
+	err := trait.Apply(e)
+	assert.Nil(t, err)
+
+	source := e.Integration.Status.GeneratedSources[0]
+	assert.Contains(t, source.Content, "uri: (http|https|vertx-http|netty-http):.*")
+	assert.Contains(t, source.Content, "${header[X-Request-ID]} == null")
+	assert.NotContains(t, source.Content, "traceparent")
+	assert.Empty(t, e.Integration.Status.Dependencies)
+}
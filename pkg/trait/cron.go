@@ -44,12 +44,18 @@ import (
 // For such tasks, the cron trait can materialize the integration as a Kubernetes CronJob instead of a standard deployment,
 // in order to save resources when the integration does not need to be executed.
 //
-// Integrations that start from the following components are evaluated by the cron trait: `timer`, `cron`, `quartz`.
+// Integrations that start from the following components are evaluated by the cron trait: `timer`, `cron`, `quartz`,
+// and the scheduled polling consumer of `aws2-s3`.
 //
 // The rules for using a Kubernetes CronJob are the following:
 // - `timer`: when periods can be written as cron expressions. E.g. `timer:tick?period=60000`.
 // - `cron`, `quartz`: when the cron expression does not contain seconds (or the "seconds" part is set to 0). E.g.
 //   `cron:tab?schedule=0/2${plus}*{plus}*{plus}*{plus}?` or `quartz:trigger?cron=0{plus}0/2{plus}*{plus}*{plus}*{plus}?`.
+// - `aws2-s3`: when the consumer is scheduled with a cron expression (`scheduler=quartz` or `scheduler=spring`
+//   with a `scheduler.cron` parameter), or when its polling `delay` can be written as a cron expression.
+//
+// When only some of the routes can be scheduled by a CronJob, the integration falls back to the default controller
+// (e.g. a Deployment) and the reason is reported in the `CronJobAvailable` condition.
 //
 // +camel-k:trait=cron.
 type cronTrait struct {
@@ -61,7 +67,7 @@ type cronTrait struct {
 	// A specific customizer is activated for each specified component. E.g. for the `timer` component, the `cron-timer` customizer is
 	// activated (it's present in the `org.apache.camel.k:camel-k-cron` library).
 	//
-	// Supported components are currently: `cron`, `timer` and `quartz`.
+	// Supported components are currently: `cron`, `timer`, `quartz` and `aws2-s3`.
 	Components string `property:"components" json:"components,omitempty"`
 	// Use the default Camel implementation of the `cron` endpoint (`quartz`) instead of trying to materialize the integration
 	// as Kubernetes CronJob.
@@ -73,7 +79,8 @@ type cronTrait struct {
 	// - "Replace": cancels currently running job and replaces it with a new one
 	ConcurrencyPolicy string `property:"concurrency-policy" json:"concurrencyPolicy,omitempty"`
 	// Automatically deploy the integration as CronJob when all routes are
-	// either starting from a periodic consumer (only `cron`, `timer` and `quartz` are supported) or a passive consumer (e.g. `direct` is a passive consumer).
+	// either starting from a periodic consumer (`cron`, `timer`, `quartz` and the `aws2-s3` scheduled polling consumer are supported)
+	// or a passive consumer (e.g. `direct` is a passive consumer).
 	//
 	// It's required that all periodic consumers have the same period and it can be expressed as cron schedule (e.g. `1m` can be expressed as `0/1 * * * *`,
 	// while `35m` or `50s` cannot).
@@ -88,6 +95,9 @@ type cronTrait struct {
 	// Specifies the number of retries before marking the job failed.
 	// It defaults to 2.
 	BackoffLimit *int32 `property:"backoff-limit" json:"backoffLimit,omitempty"`
+
+	// the reason why the integration cannot be materialized as CronJob, if any
+	fallbackReason string
 }
 
 var _ ControllerStrategySelector = &cronTrait{}
@@ -110,9 +120,10 @@ var (
 	camelTimerPeriodMillis = regexp.MustCompile(`^[0-9]+$`)
 
 	supportedCamelComponents = map[string]cronExtractor{
-		"timer":  timerToCronInfo,
-		"quartz": quartzToCronInfo,
-		"cron":   cronToCronInfo,
+		"timer":   timerToCronInfo,
+		"quartz":  quartzToCronInfo,
+		"cron":    cronToCronInfo,
+		"aws2-s3": pollingConsumerToCronInfo,
 	}
 )
 
@@ -216,11 +227,15 @@ func (t *cronTrait) Configure(e *Environment) (bool, error) {
 	}
 	if strategy != ControllerStrategyCronJob {
 		if e.IntegrationInPhase(v1.IntegrationPhaseDeploying) {
+			message := fmt.Sprintf("different controller strategy used (%s)", string(strategy))
+			if t.fallbackReason != "" {
+				message = fmt.Sprintf("%s: %s", message, t.fallbackReason)
+			}
 			e.Integration.Status.SetCondition(
 				v1.IntegrationConditionCronJobAvailable,
 				corev1.ConditionFalse,
 				v1.IntegrationConditionCronJobNotAvailableReason,
-				fmt.Sprintf("different controller strategy used (%s)", string(strategy)),
+				message,
 			)
 		}
 		return false, nil
@@ -380,16 +395,31 @@ func (t *cronTrait) getGlobalCron(e *Environment) (*cronInfo, error) {
 	})
 
 	var cron []string
+	var incompatible []string
 	for _, from := range fromURIs {
 		comp := uri.GetComponent(from)
-		if supportedCamelComponents[comp] != nil {
+		switch {
+		case supportedCamelComponents[comp] != nil && getCronForURI(from) != nil:
 			cron = append(cron, from)
-		} else if !passiveComponents[comp] {
-			return nil, nil
+		case !passiveComponents[comp]:
+			incompatible = append(incompatible, from)
 		}
 	}
 
+	t.fallbackReason = ""
+	if len(cron) == 0 {
+		return nil, nil
+	}
+	if len(incompatible) > 0 {
+		t.fallbackReason = fmt.Sprintf("only some routes can be scheduled as CronJob, not compatible: %s",
+			strings.Join(incompatible, ", "))
+		return nil, nil
+	}
+
 	globalCron := getCronForURIs(cron)
+	if globalCron == nil {
+		t.fallbackReason = "routes declare different schedules"
+	}
 	return globalCron, nil
 }
 
@@ -495,6 +525,37 @@ func cronToCronInfo(camelURI string) *cronInfo {
 	return nil
 }
 
+// pollingConsumerToCronInfo converts a scheduled polling consumer endpoint (e.g. `aws2-s3`) to a Kubernetes cron schedule.
+func pollingConsumerToCronInfo(camelURI string) *cronInfo {
+	if uri.GetQueryParameter(camelURI, "initialDelay") != "" ||
+		uri.GetQueryParameter(camelURI, "repeatCount") != "" {
+		return nil
+	}
+
+	component := uri.GetComponent(camelURI)
+	switch uri.GetQueryParameter(camelURI, "scheduler") {
+	case "quartz", "spring":
+		normalized := toKubernetesCronSchedule(uri.GetQueryParameter(camelURI, "scheduler.cron"))
+		if normalized != "" {
+			return newCronInfo().withComponents(component).withSchedule(normalized)
+		}
+		return nil
+	case "":
+		// The polling delay has the same semantic as the timer period
+		delay := uri.GetQueryParameter(camelURI, "delay")
+		if delay == "" {
+			return nil
+		}
+		info := timerToCronInfo(fmt.Sprintf("timer:poll?period=%s", delay))
+		if info == nil {
+			return nil
+		}
+		return newCronInfo().withComponents(component).withSchedule(info.schedule)
+	default:
+		return nil
+	}
+}
+
 // Utility
 
 func cronEquivalent(cron1, cron2 string) bool {
@@ -165,6 +165,27 @@ func TestCronFromURI(t *testing.T) {
 			uri: "cron:tab?schedule=1+0+0/4+*+*+?", // invalid
 		},
 
+		// Scheduled polling consumers
+		{
+			uri:        "aws2-s3:bucket?scheduler=quartz&scheduler.cron=0+0/5+*+*+*+?",
+			cron:       "0/5 * * * ?",
+			components: "aws2-s3",
+		},
+		{
+			uri:        "aws2-s3:bucket?delay=3600000",
+			cron:       "0 0/1 * * ?",
+			components: "aws2-s3",
+		},
+		{
+			uri: "aws2-s3:bucket?delay=500", // invalid
+		},
+		{
+			uri: "aws2-s3:bucket", // invalid
+		},
+		{
+			uri: "jms:queue:orders?scheduler=spring&scheduler.cron=0+0/5+*+*+*+?", // event-driven consumer
+		},
+
 		// Mixed scenarios
 		{
 			uri:        "cron:tab?schedule=0/2 * * * ?",
@@ -191,6 +212,12 @@ func TestCronFromURI(t *testing.T) {
 			uri3: "quartz:trigger?cron=0 0 0/2 * * ? ?",
 			// invalid
 		},
+		{
+			uri:        "timer:tick?period=300000",
+			uri2:       "aws2-s3:bucket?scheduler=quartz&scheduler.cron=0+0/5+*+*+*+?",
+			cron:       "0/5 * * * ?",
+			components: "timer,aws2-s3",
+		},
 	}
 
 	for _, test := range tests {
@@ -510,3 +537,133 @@ func TestCronWithBackoffLimit(t *testing.T) {
 	assert.NotNil(t, cronJob.Spec.JobTemplate.Spec.BackoffLimit)
 	assert.EqualValues(t, *cronJob.Spec.JobTemplate.Spec.BackoffLimit, 5)
 }
+
+func TestCronPartialRoutesFallback(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name: "routes.java",
+							Content: `from("timer:tick?period=60000").to("log:test");
+								from("kafka:topic").to("log:test")`,
+						},
+						Language: v1.LanguageJavaSource,
+					},
+				},
+				Resources: []v1.ResourceSpec{},
+				Traits:    map[string]v1.TraitSpec{},
+			},
+		},
+		IntegrationKit: &v1.IntegrationKit{
+			Status: v1.IntegrationKitStatus{
+				Phase: v1.IntegrationKitPhaseReady,
+			},
+		},
+		Platform:       &v1.IntegrationPlatform{},
+		EnvVars:        make([]corev1.EnvVar, 0),
+		ExecutedTraits: make([]Trait, 0),
+		Resources:      kubernetes.NewCollection(),
+	}
+	environment.Platform.ResyncStatusFullConfig()
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	tc := NewCatalog(c)
+
+	err = tc.apply(&environment)
+
+	assert.Nil(t, err)
+	assert.Nil(t, environment.GetTrait("cron"))
+	assert.NotContains(t, environment.Interceptors, "cron")
+	assert.Nil(t, environment.Resources.GetCronJob(func(job *batchv1beta1.CronJob) bool { return true }))
+
+	condition := environment.Integration.Status.GetCondition(v1.IntegrationConditionCronJobAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Contains(t, condition.Message, "different controller strategy used (deployment)")
+	assert.Contains(t, condition.Message, "kafka:topic")
+}
+
+func TestCronWithPollingConsumer(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	traitCatalog := NewCatalog(nil)
+
+	environment := Environment{
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name:    "routes.java",
+							Content: `from("aws2-s3:bucket?scheduler=quartz&scheduler.cron=0+0/5+*+*+*+?").to("log:test")`,
+						},
+						Language: v1.LanguageJavaSource,
+					},
+				},
+				Resources: []v1.ResourceSpec{},
+				Traits:    map[string]v1.TraitSpec{},
+			},
+		},
+		IntegrationKit: &v1.IntegrationKit{
+			Status: v1.IntegrationKitStatus{
+				Phase: v1.IntegrationKitPhaseReady,
+			},
+		},
+		Platform:       &v1.IntegrationPlatform{},
+		EnvVars:        make([]corev1.EnvVar, 0),
+		ExecutedTraits: make([]Trait, 0),
+		Resources:      kubernetes.NewCollection(),
+	}
+	environment.Platform.ResyncStatusFullConfig()
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	tc := NewCatalog(c)
+
+	err = tc.apply(&environment)
+
+	assert.Nil(t, err)
+
+	ct, _ := environment.GetTrait("cron").(*cronTrait)
+	assert.NotNil(t, ct)
+	assert.Equal(t, "0/5 * * * ?", ct.Schedule)
+	assert.Equal(t, "aws2-s3", ct.Components)
+	assert.Contains(t, environment.Interceptors, "cron")
+	assert.Equal(t, "5", environment.ApplicationProperties["camel.main.duration-max-idle-seconds"])
+	assert.Equal(t, "aws2-s3", environment.ApplicationProperties["loader.interceptor.cron.overridable-components"])
+
+	cronJob := environment.Resources.GetCronJob(func(job *batchv1beta1.CronJob) bool { return true })
+	assert.NotNil(t, cronJob)
+	assert.Equal(t, "0/5 * * * ?", cronJob.Spec.Schedule)
+}
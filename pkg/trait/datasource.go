@@ -0,0 +1,299 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	utilResource "github.com/apache/camel-k/pkg/util/resource"
+)
+
+const (
+	datasourceTraitID = "datasource"
+
+	datasourceEnvVarPrefix = "CAMEL_K_DATASOURCE_"
+
+	crunchyClusterLabel = "postgres-operator.crunchydata.com/cluster"
+	cnpgClusterLabel    = "cnpg.io/cluster"
+)
+
+type databaseKind struct {
+	// The Quarkus JDBC driver extension
+	extension   string
+	urlTemplate string
+	defaultPort string
+}
+
+var databaseKinds = map[string]databaseKind{
+	"postgresql": {extension: "quarkus-jdbc-postgresql", urlTemplate: "jdbc:postgresql://%s:%s/%s", defaultPort: "5432"},
+	"mysql":      {extension: "quarkus-jdbc-mysql", urlTemplate: "jdbc:mysql://%s:%s/%s", defaultPort: "3306"},
+	"mariadb":    {extension: "quarkus-jdbc-mariadb", urlTemplate: "jdbc:mariadb://%s:%s/%s", defaultPort: "3306"},
+	"mssql":      {extension: "quarkus-jdbc-mssql", urlTemplate: "jdbc:sqlserver://%s:%s;databaseName=%s", defaultPort: "1433"},
+	"oracle":     {extension: "quarkus-jdbc-oracle", urlTemplate: "jdbc:oracle:thin:@//%s:%s/%s", defaultPort: "1521"},
+	"db2":        {extension: "quarkus-jdbc-db2", urlTemplate: "jdbc:db2://%s:%s/%s", defaultPort: "50000"},
+}
+
+var databaseKindAliases = map[string]string{
+	"postgres":  "postgresql",
+	"sqlserver": "mssql",
+}
+
+// The Datasource trait configures the default Quarkus datasource of the Integration, and adds the matching
+// JDBC driver, from a Secret containing the database connection details.
+//
+// The following Secret formats are detected:
+//
+// * Service Binding secrets, e.g., projected by the Service Binding Operator, with the `type`, `host`, `port`,
+// `database`, `username` and `password` keys
+// * Crunchy Postgres for Kubernetes user secrets, e.g., `hippo-pguser-hippo`
+// * CloudNativePG application secrets, e.g., `cluster-example-app`
+//
+// The Secret can either be set explicitly, or is looked up among the Secrets mounted with the `mount.configs` option,
+// e.g., `kamel run --config secret:hippo-pguser-hippo`.
+//
+// The connection details are never copied into the Integration configuration: they are injected as environment
+// variables, from the Secret, that the datasource properties refer to.
+//
+// +camel-k:trait=datasource.
+type datasourceTrait struct {
+	BaseTrait `property:",squash"`
+	// The name of the Secret containing the database connection details.
+	Secret string `property:"secret" json:"secret,omitempty"`
+	// Looks up the Secret among the Secrets mounted in the Integration, when no Secret is set (default `true`).
+	Auto *bool `property:"auto" json:"auto,omitempty"`
+
+	binding *databaseBinding
+}
+
+// databaseBinding maps the connection details of a database to the keys of the Secret providing them.
+type databaseBinding struct {
+	secret string
+	kind   string
+	// The connection fields, i.e., host, port, database, username, password and url, mapped to the Secret keys
+	keys map[string]string
+	// The connection fields that are not provided by the Secret, but derived from its metadata
+	defaults map[string]string
+}
+
+func newDatasourceTrait() Trait {
+	return &datasourceTrait{
+		// Must run before the container trait, that computes the application properties
+		BaseTrait: NewBaseTrait(datasourceTraitID, 1170),
+	}
+}
+
+func (t *datasourceTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, true) {
+		return false, nil
+	}
+
+	if !e.IntegrationInPhase(v1.IntegrationPhaseInitialization) && !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if t.Secret != "" {
+		secret, err := kubernetes.GetSecret(e.Ctx, e.Client, t.Secret, e.Integration.Namespace)
+		if err != nil {
+			return false, fmt.Errorf("unable to get datasource secret %s: %w", t.Secret, err)
+		}
+		if t.binding = detectDatabaseBinding(secret); t.binding == nil {
+			return false, fmt.Errorf("unable to detect the database connection details from secret %s", t.Secret)
+		}
+		return true, nil
+	}
+
+	if !pointer.BoolDeref(t.Auto, true) {
+		return false, nil
+	}
+
+	for _, name := range t.getMountedSecrets(e) {
+		secret, err := kubernetes.GetSecret(e.Ctx, e.Client, name, e.Integration.Namespace)
+		if err != nil {
+			// The mount trait reports the missing secrets
+			continue
+		}
+		if t.binding = detectDatabaseBinding(secret); t.binding != nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (t *datasourceTrait) Apply(e *Environment) error {
+	kind := databaseKinds[t.binding.kind]
+
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, "mvn:io.quarkus:quarkus-agroal")
+		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, "mvn:io.quarkus:"+kind.extension)
+		return nil
+	}
+
+	for _, field := range []string{"host", "port", "database", "username", "password", "url"} {
+		if key, ok := t.binding.keys[field]; ok {
+			envvar.SetVar(&e.EnvVars, corev1.EnvVar{
+				Name: datasourceEnvVar(field),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: t.binding.secret,
+						},
+						Key: key,
+					},
+				},
+			})
+		}
+	}
+
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+	if _, ok := t.binding.keys["url"]; ok {
+		e.ApplicationProperties["quarkus.datasource.jdbc.url"] = t.expression("url", "")
+	} else {
+		e.ApplicationProperties["quarkus.datasource.jdbc.url"] = fmt.Sprintf(kind.urlTemplate,
+			t.expression("host", ""), t.expression("port", kind.defaultPort), t.expression("database", ""))
+	}
+	if _, ok := t.binding.keys["username"]; ok {
+		e.ApplicationProperties["quarkus.datasource.username"] = t.expression("username", "")
+	}
+	if _, ok := t.binding.keys["password"]; ok {
+		e.ApplicationProperties["quarkus.datasource.password"] = t.expression("password", "")
+	}
+
+	return nil
+}
+
+// expression returns the property expression resolving the given connection field.
+func (t *datasourceTrait) expression(field string, defaultValue string) string {
+	if _, ok := t.binding.keys[field]; !ok {
+		if value, ok := t.binding.defaults[field]; ok {
+			return value
+		}
+		return defaultValue
+	}
+	if defaultValue != "" {
+		return fmt.Sprintf("${%s:%s}", datasourceEnvVar(field), defaultValue)
+	}
+	return fmt.Sprintf("${%s}", datasourceEnvVar(field))
+}
+
+func (t *datasourceTrait) getMountedSecrets(e *Environment) []string {
+	if e.Catalog == nil {
+		return nil
+	}
+	var secrets []string
+	if m, ok := e.Catalog.GetTrait("mount").(*mountTrait); ok {
+		for _, c := range m.Configs {
+			if conf, err := utilResource.ParseConfig(c); err == nil && conf.StorageType() == utilResource.StorageTypeSecret {
+				secrets = append(secrets, conf.Name())
+			}
+		}
+	}
+	return secrets
+}
+
+func datasourceEnvVar(field string) string {
+	return datasourceEnvVarPrefix + strings.ToUpper(field)
+}
+
+// detectDatabaseBinding returns the connection details provided by the given Secret, or nil if it does not
+// match any of the supported formats.
+func detectDatabaseBinding(secret *corev1.Secret) *databaseBinding {
+	binding := databaseBinding{
+		secret:   secret.Name,
+		keys:     make(map[string]string),
+		defaults: make(map[string]string),
+	}
+	mapKeys := func(fields map[string]string) {
+		for field, key := range fields {
+			if _, ok := secret.Data[key]; ok {
+				binding.keys[field] = key
+			}
+		}
+	}
+
+	switch {
+	case len(secret.Data["type"]) > 0:
+		// Service Binding specification
+		kind := strings.ToLower(strings.TrimSpace(string(secret.Data["type"])))
+		if alias, ok := databaseKindAliases[kind]; ok {
+			kind = alias
+		}
+		if _, ok := databaseKinds[kind]; !ok {
+			return nil
+		}
+		binding.kind = kind
+		mapKeys(map[string]string{
+			"host":     "host",
+			"port":     "port",
+			"database": "database",
+			"username": "username",
+			"password": "password",
+			"url":      "jdbc-url",
+		})
+	case secret.Labels[crunchyClusterLabel] != "":
+		binding.kind = "postgresql"
+		mapKeys(map[string]string{
+			"host":     "host",
+			"port":     "port",
+			"database": "dbname",
+			"username": "user",
+			"password": "password",
+			"url":      "jdbc-uri",
+		})
+	case secret.Labels[cnpgClusterLabel] != "" || len(secret.Data["pgpass"]) > 0:
+		binding.kind = "postgresql"
+		mapKeys(map[string]string{
+			"host":     "host",
+			"port":     "port",
+			"database": "dbname",
+			"username": "username",
+			"password": "password",
+			"url":      "jdbc-uri",
+		})
+		// Earlier CloudNativePG versions only provide the credentials
+		if cluster := secret.Labels[cnpgClusterLabel]; cluster != "" {
+			binding.defaults["host"] = cluster + "-rw"
+		}
+		binding.defaults["database"] = "app"
+	default:
+		return nil
+	}
+
+	if _, ok := binding.keys["url"]; ok {
+		return &binding
+	}
+	for _, field := range []string{"host", "database"} {
+		_, key := binding.keys[field]
+		_, value := binding.defaults[field]
+		if !key && !value {
+			return nil
+		}
+	}
+
+	return &binding
+}
@@ -0,0 +1,153 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestDetectDatabaseBinding(t *testing.T) {
+	binding := detectDatabaseBinding(newDatabaseSecret("db", nil, "type", "host", "port", "database", "username", "password"))
+	assert.NotNil(t, binding)
+	assert.Equal(t, "mysql", binding.kind)
+	assert.Equal(t, "database", binding.keys["database"])
+
+	binding = detectDatabaseBinding(newDatabaseSecret("hippo-pguser-hippo", map[string]string{crunchyClusterLabel: "hippo"},
+		"host", "port", "dbname", "user", "password", "jdbc-uri"))
+	assert.NotNil(t, binding)
+	assert.Equal(t, "postgresql", binding.kind)
+	assert.Equal(t, "jdbc-uri", binding.keys["url"])
+	assert.Equal(t, "user", binding.keys["username"])
+
+	binding = detectDatabaseBinding(newDatabaseSecret("cluster-example-app", map[string]string{cnpgClusterLabel: "cluster-example"},
+		"username", "password", "pgpass"))
+	assert.NotNil(t, binding)
+	assert.Equal(t, "cluster-example-rw", binding.defaults["host"])
+	assert.Equal(t, "app", binding.defaults["database"])
+
+	assert.Nil(t, detectDatabaseBinding(newDatabaseSecret("credentials", nil, "username", "password")))
+}
+
+func TestDatasourceDependencies(t *testing.T) {
+	datasourceTrait, environment := createDatasourceTest(t,
+		newDatabaseSecret("db", nil, "type", "host", "port", "database", "username", "password"))
+	environment.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+	datasourceTrait.Secret = "db"
+
+	configured, err := datasourceTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, datasourceTrait.Apply(environment))
+	assert.Equal(t, []string{"mvn:io.quarkus:quarkus-agroal", "mvn:io.quarkus:quarkus-jdbc-mysql"}, environment.Integration.Status.Dependencies)
+}
+
+func TestDatasourceFromServiceBindingSecret(t *testing.T) {
+	datasourceTrait, environment := createDatasourceTest(t,
+		newDatabaseSecret("db", nil, "type", "host", "port", "database", "username", "password"))
+	datasourceTrait.Secret = "db"
+
+	configured, err := datasourceTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, datasourceTrait.Apply(environment))
+
+	assert.Equal(t, "jdbc:mysql://${CAMEL_K_DATASOURCE_HOST}:${CAMEL_K_DATASOURCE_PORT:3306}/${CAMEL_K_DATASOURCE_DATABASE}",
+		environment.ApplicationProperties["quarkus.datasource.jdbc.url"])
+	assert.Equal(t, "${CAMEL_K_DATASOURCE_USERNAME}", environment.ApplicationProperties["quarkus.datasource.username"])
+	assert.Equal(t, "${CAMEL_K_DATASOURCE_PASSWORD}", environment.ApplicationProperties["quarkus.datasource.password"])
+	assert.Equal(t, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+		*envvar.Get(environment.EnvVars, "CAMEL_K_DATASOURCE_PASSWORD").ValueFrom.SecretKeyRef)
+	assert.Nil(t, envvar.Get(environment.EnvVars, "CAMEL_K_DATASOURCE_URL"))
+}
+
+func TestDatasourceFromMountedSecret(t *testing.T) {
+	datasourceTrait, environment := createDatasourceTest(t,
+		newDatabaseSecret("credentials", nil, "username", "password"),
+		newDatabaseSecret("cluster-example-app", map[string]string{cnpgClusterLabel: "cluster-example"}, "username", "password", "pgpass"))
+	mount, _ := environment.Catalog.GetTrait("mount").(*mountTrait)
+	mount.Configs = []string{"configmap:settings", "secret:credentials", "secret:cluster-example-app"}
+
+	configured, err := datasourceTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, datasourceTrait.Apply(environment))
+
+	assert.Equal(t, "jdbc:postgresql://cluster-example-rw:5432/app", environment.ApplicationProperties["quarkus.datasource.jdbc.url"])
+	assert.Equal(t, "cluster-example-app", envvar.Get(environment.EnvVars, "CAMEL_K_DATASOURCE_USERNAME").ValueFrom.SecretKeyRef.Name)
+}
+
+func TestDatasourceWithoutDatabaseSecret(t *testing.T) {
+	datasourceTrait, environment := createDatasourceTest(t, newDatabaseSecret("credentials", nil, "username", "password"))
+
+	configured, err := datasourceTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+
+	datasourceTrait.Secret = "credentials"
+	configured, err = datasourceTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func newDatabaseSecret(name string, labels map[string]string, keys ...string) *corev1.Secret {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{},
+	}
+	for _, key := range keys {
+		secret.Data[key] = []byte("value")
+	}
+	if _, ok := secret.Data["type"]; ok {
+		secret.Data["type"] = []byte("MySQL")
+	}
+	return secret
+}
+
+func createDatasourceTest(t *testing.T, secrets ...*corev1.Secret) (*datasourceTrait, *Environment) {
+	t.Helper()
+
+	_, environment := createStorageTest(1)
+
+	client, err := test.NewFakeClient()
+	assert.Nil(t, err)
+	for _, secret := range secrets {
+		assert.Nil(t, client.Create(environment.Ctx, secret))
+	}
+	environment.Client = client
+
+	trait, _ := newDatasourceTrait().(*datasourceTrait)
+
+	return trait, environment
+}
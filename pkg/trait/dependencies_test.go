@@ -331,3 +331,91 @@ func TestRestDepsQuarkus(t *testing.T) {
 		},
 	)
 }
+
+func TestRestDepsConflictingVersion(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	e := &Environment{
+		Catalog:      NewEnvironmentTestCatalog(),
+		CamelCatalog: catalog,
+		Integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name:    "flow.java",
+							Content: `rest().to("log:bar");`,
+						},
+						Language: v1.LanguageJavaSource,
+					},
+				},
+				Dependencies: []string{
+					"mvn:org.apache.camel.quarkus:camel-quarkus-rest:1.0.0",
+				},
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseInitialization,
+			},
+		},
+	}
+
+	for _, trait := range []Trait{NewInitTrait(), newDependenciesTrait()} {
+		enabled, err := trait.Configure(e)
+		assert.Nil(t, err)
+		assert.True(t, enabled)
+		assert.Nil(t, trait.Apply(e))
+	}
+
+	var errs []error
+	for _, processor := range e.PostStepProcessors {
+		if err := processor(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	assert.Len(t, errs, 1)
+	var cerr *ConfigurationError
+	assert.ErrorAs(t, errs[0], &cerr)
+	assert.Contains(t, errs[0].Error(), "conflicts with the rest capability")
+}
+
+func TestRestDepsWithoutVersion(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	e := &Environment{
+		Catalog:      NewEnvironmentTestCatalog(),
+		CamelCatalog: catalog,
+		Integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name:    "flow.java",
+							Content: `rest().to("log:bar");`,
+						},
+						Language: v1.LanguageJavaSource,
+					},
+				},
+				Dependencies: []string{
+					"mvn:org.apache.camel.quarkus:camel-quarkus-rest",
+				},
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseInitialization,
+			},
+		},
+	}
+
+	for _, trait := range []Trait{NewInitTrait(), newDependenciesTrait()} {
+		enabled, err := trait.Configure(e)
+		assert.Nil(t, err)
+		assert.True(t, enabled)
+		assert.Nil(t, trait.Apply(e))
+	}
+
+	for _, processor := range e.PostStepProcessors {
+		assert.Nil(t, processor(e))
+	}
+	assert.Contains(t, e.Integration.Status.Dependencies, "mvn:org.apache.camel.quarkus:camel-quarkus-rest")
+}
@@ -40,7 +40,7 @@ import (
 // +camel-k:trait=deployer.
 type deployerTrait struct {
 	BaseTrait `property:",squash"`
-	// Allows to explicitly select the desired deployment kind between `deployment`, `cron-job` or `knative-service` when creating the resources for running the integration.
+	// Allows to explicitly select the desired deployment kind between `deployment`, `cron-job`, `job` or `knative-service` when creating the resources for running the integration.
 	Kind string `property:"kind" json:"kind,omitempty"`
 	// Use server-side apply to update the owned resources (default `true`).
 	// Note that it automatically falls back to client-side patching, if SSA is not available, e.g., on old Kubernetes clusters.
@@ -64,6 +64,17 @@ func (t *deployerTrait) Configure(e *Environment) (bool, error) {
 func (t *deployerTrait) Apply(e *Environment) error {
 	// Register a post action that patches the resources generated by the traits
 	e.PostActions = append(e.PostActions, func(env *Environment) error {
+		if env.IsReconcilePaused() {
+			// Leave the resources as they are, so that they can be tuned by hand,
+			// but retrieve them so that the Integration status reflects their actual state
+			t.L.ForIntegration(env.Integration).Info("Reconciliation paused, the owned resources are not updated")
+			for _, resource := range env.Resources.Items() {
+				if err := t.getFromCluster(env, resource); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
 		for _, resource := range env.Resources.Items() {
 			// We assume that server-side apply is enabled by default.
 			// It is currently convoluted to check proactively whether server-side apply
@@ -137,6 +148,20 @@ func (t *deployerTrait) clientSideApply(env *Environment, resource ctrl.Object)
 	return nil
 }
 
+func (t *deployerTrait) getFromCluster(env *Environment, resource ctrl.Object) error {
+	object := &unstructured.Unstructured{}
+	object.SetNamespace(resource.GetNamespace())
+	object.SetName(resource.GetName())
+	object.SetGroupVersionKind(resource.GetObjectKind().GroupVersionKind())
+	err := env.Client.Get(env.Ctx, ctrl.ObjectKeyFromObject(object), object)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return t.unstructuredToRuntimeObject(object, resource)
+}
+
 func (t *deployerTrait) unstructuredToRuntimeObject(u *unstructured.Unstructured, obj ctrl.Object) error {
 	data, err := json.Marshal(u)
 	if err != nil {
@@ -18,12 +18,18 @@ limitations under the License.
 package trait
 
 import (
+	"context"
 	"testing"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
 	"github.com/stretchr/testify/assert"
 
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestConfigureDeployerTraitDoesSucceed(t *testing.T) {
@@ -64,6 +70,57 @@ func TestApplyDeployerTraitInInitializationPhaseDoesSucceed(t *testing.T) {
 	assert.Len(t, environment.PostActions, 1)
 }
 
+func TestDeployerTraitWithReconcilePausedLeavesResources(t *testing.T) {
+	deployerTrait, environment := createNominalDeployerTest()
+	environment.Platform = &v1.IntegrationPlatform{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "camel-k",
+			Annotations: map[string]string{
+				v1.ReconcileAnnotation: v1.ReconcilePaused,
+			},
+		},
+	}
+
+	live := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(5),
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: 5,
+		},
+	}
+	c, err := test.NewFakeClient(live)
+	assert.Nil(t, err)
+	environment.Client = c
+	environment.Ctx = context.TODO()
+
+	desired := live.DeepCopy()
+	desired.Spec.Replicas = pointer.Int32(1)
+	desired.Status = appsv1.DeploymentStatus{}
+	environment.Resources = kubernetes.NewCollection(desired)
+
+	assert.Nil(t, deployerTrait.Apply(environment))
+	for _, postAction := range environment.PostActions {
+		assert.Nil(t, postAction(environment))
+	}
+
+	deployment := &appsv1.Deployment{}
+	assert.Nil(t, c.Get(context.TODO(), ctrl.ObjectKeyFromObject(live), deployment))
+	assert.Equal(t, int32(5), *deployment.Spec.Replicas)
+	// the resource is updated from the cluster, so that the Integration status reflects it
+	assert.Equal(t, int32(5), *desired.Spec.Replicas)
+	assert.Equal(t, int32(5), desired.Status.ReadyReplicas)
+}
+
 func createNominalDeployerTest() (*deployerTrait, *Environment) {
 	trait, _ := newDeployerTrait().(*deployerTrait)
 
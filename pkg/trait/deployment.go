@@ -19,16 +19,24 @@ package trait
 
 import (
 	"fmt"
+	"sort"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/digest"
 	"github.com/apache/camel-k/pkg/util/label"
 )
 
+// configChecksumAnnotation annotates the Integration Pods with a checksum of the ConfigMaps and Secrets generated
+// for the Integration, so that configuration changes roll out a new revision.
+const configChecksumAnnotation = "camel.apache.org/config.checksum"
+
 // The Deployment trait is responsible for generating the Kubernetes deployment that will make sure
 // the integration will run in the cluster.
 //
@@ -38,6 +46,10 @@ type deploymentTrait struct {
 	// The maximum time in seconds for the deployment to make progress before it
 	// is considered to be failed. It defaults to 60s.
 	ProgressDeadlineSeconds *int32 `property:"progress-deadline-seconds" json:"progressDeadlineSeconds,omitempty"`
+	// Rolls out the configuration changes, that do not change the container image, as a new Deployment revision,
+	// and surges a complete set of new Pods before the previous ones are terminated, so that the available capacity
+	// never drops during the rollout.
+	ZeroDowntime *bool `property:"zero-downtime" json:"zeroDowntime,omitempty"`
 }
 
 var _ ControllerStrategySelector = &deploymentTrait{}
@@ -106,6 +118,10 @@ func (t *deploymentTrait) Apply(e *Environment) error {
 	deployment := t.getDeploymentFor(e)
 	e.Resources.Add(deployment)
 
+	if pointer.BoolDeref(t.ZeroDowntime, false) {
+		t.configureZeroDowntime(e, deployment)
+	}
+
 	e.Integration.Status.SetCondition(
 		v1.IntegrationConditionDeploymentAvailable,
 		corev1.ConditionTrue,
@@ -181,3 +197,62 @@ func (t *deploymentTrait) getDeploymentFor(e *Environment) *appsv1.Deployment {
 
 	return &deployment
 }
+
+// configureZeroDowntime surges the new Pods without making the previous ones unavailable, and rolls out the
+// configuration changes, once all the ConfigMaps and Secrets of the Integration are generated.
+func (t *deploymentTrait) configureZeroDowntime(e *Environment, deployment *appsv1.Deployment) {
+	maxSurge := intstr.FromString("100%")
+	maxUnavailable := intstr.FromInt(0)
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+
+	e.PostProcessors = append(e.PostProcessors, func(env *Environment) error {
+		checksum, err := computeConfigChecksum(env)
+		if err != nil || checksum == "" {
+			return err
+		}
+		deployment.Spec.Template.Annotations = withAnnotation(deployment.Spec.Template.Annotations, configChecksumAnnotation, checksum)
+		return nil
+	})
+}
+
+// computeConfigChecksum returns the checksum of the content of the ConfigMaps and Secrets generated for the Integration,
+// or an empty string if there is none.
+func computeConfigChecksum(e *Environment) (string, error) {
+	var configmaps []corev1.ConfigMap
+	var secrets []corev1.Secret
+	e.Resources.VisitConfigMap(func(cm *corev1.ConfigMap) {
+		if cm.Labels[v1.IntegrationLabel] == e.Integration.Name {
+			configmaps = append(configmaps, *cm)
+		}
+	})
+	e.Resources.Visit(func(object runtime.Object) {
+		if secret, ok := object.(*corev1.Secret); ok && secret.Labels[v1.IntegrationLabel] == e.Integration.Name {
+			secrets = append(secrets, *secret)
+		}
+	})
+	if len(configmaps) == 0 && len(secrets) == 0 {
+		return "", nil
+	}
+
+	sort.Slice(configmaps, func(i, j int) bool { return configmaps[i].Name < configmaps[j].Name })
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	return digest.ComputeForConfigMapsAndSecrets(configmaps, secrets)
+}
+
+// withAnnotation returns a copy of the given annotations, with the given annotation added, so that the maps shared
+// between several objects are left untouched.
+func withAnnotation(annotations map[string]string, key string, value string) map[string]string {
+	res := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		res[k] = v
+	}
+	res[key] = value
+	return res
+}
@@ -147,6 +147,43 @@ func TestApplyDeploymentTraitWithProgressDeadline(t *testing.T) {
 	assert.Equal(t, int32(120), *deployment.Spec.ProgressDeadlineSeconds)
 }
 
+func TestApplyDeploymentTraitWithZeroDowntime(t *testing.T) {
+	deploymentTrait, environment := createNominalDeploymentTest()
+	deploymentTrait.ZeroDowntime = pointer.Bool(true)
+	environment.Integration.Annotations = map[string]string{"foo": "bar"}
+	environment.Resources.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "integration-name-application-properties",
+			Labels: map[string]string{v1.IntegrationLabel: "integration-name"},
+		},
+		Data: map[string]string{"application.properties": "foo=bar"},
+	})
+
+	assert.Nil(t, deploymentTrait.Apply(environment))
+	for _, processor := range environment.PostProcessors {
+		assert.Nil(t, processor(environment))
+	}
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, deployment)
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, "100%", deployment.Spec.Strategy.RollingUpdate.MaxSurge.String())
+	assert.Equal(t, 0, deployment.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue())
+	checksum := deployment.Spec.Template.Annotations[configChecksumAnnotation]
+	assert.NotEmpty(t, checksum)
+	assert.Equal(t, "bar", deployment.Spec.Template.Annotations["foo"])
+	assert.NotContains(t, deployment.Annotations, configChecksumAnnotation)
+
+	// A configuration change rolls out a new revision
+	environment.Resources.VisitConfigMap(func(cm *corev1.ConfigMap) {
+		cm.Data["application.properties"] = "foo=baz"
+	})
+	for _, processor := range environment.PostProcessors {
+		assert.Nil(t, processor(environment))
+	}
+	assert.NotEqual(t, checksum, deployment.Spec.Template.Annotations[configChecksumAnnotation])
+}
+
 func createNominalDeploymentTest() (*deploymentTrait, *Environment) {
 	trait, _ := newDeploymentTrait().(*deploymentTrait)
 	trait.Enabled = pointer.Bool(true)
@@ -0,0 +1,140 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// The DNS trait configures the DNS resolution of the Integration pods, e.g., to resolve on-premise hostnames
+// that are not served by the cluster DNS.
+// See https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-dns-config for more details.
+//
+// For examples:
+//
+// - `kamel run -t dns.nameservers=10.0.0.53 -t dns.searches=corp.example.com -t dns.options=ndots:2`
+// - `kamel run -t dns.host-aliases=10.0.0.10=legacy.corp.example.com,legacy`
+//
+// It's disabled by default.
+//
+// +camel-k:trait=dns.
+type dnsTrait struct {
+	BaseTrait `property:",squash"`
+	// The DNS policy of the pods, either `ClusterFirst`, `ClusterFirstWithHostNet`, `Default` or `None`.
+	// With the `None` policy, the DNS configuration is entirely provided by the other options of this trait.
+	Policy string `property:"policy" json:"policy,omitempty"`
+	// The IP addresses of the DNS servers, added to the ones derived from the DNS policy.
+	Nameservers []string `property:"nameservers" json:"nameservers,omitempty"`
+	// The DNS search domains for host-name lookup, added to the ones derived from the DNS policy.
+	Searches []string `property:"searches" json:"searches,omitempty"`
+	// The DNS resolver options, in the form `name[:value]`, e.g., `ndots:2`, merged with the ones derived from the DNS policy.
+	Options []string `property:"options" json:"options,omitempty"`
+	// The entries added to the pods hosts file, in the form `ip=hostname[,hostname]`, e.g., `10.0.0.10=legacy.corp.example.com,legacy`.
+	HostAliases []string `property:"host-aliases" json:"hostAliases,omitempty"`
+}
+
+func newDNSTrait() Trait {
+	return &dnsTrait{
+		BaseTrait: NewBaseTrait("dns", 1200),
+	}
+}
+
+func (t *dnsTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	switch corev1.DNSPolicy(t.Policy) {
+	case "", corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault:
+	case corev1.DNSNone:
+		if len(t.Nameservers) == 0 {
+			return false, fmt.Errorf("at least one nameserver is required with the %s DNS policy", corev1.DNSNone)
+		}
+	default:
+		return false, fmt.Errorf("unsupported DNS policy %s", t.Policy)
+	}
+
+	for _, ns := range t.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return false, fmt.Errorf("invalid nameserver %s, it must be an IP address", ns)
+		}
+	}
+
+	if _, err := t.getHostAliases(); err != nil {
+		return false, err
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *dnsTrait) Apply(e *Environment) error {
+	hostAliases, err := t.getHostAliases()
+	if err != nil {
+		return err
+	}
+
+	podSpec := e.GetIntegrationPodSpec()
+	if podSpec == nil {
+		return fmt.Errorf("could not find any integration deployment for %v", e.Integration.Name)
+	}
+
+	if t.Policy != "" {
+		podSpec.DNSPolicy = corev1.DNSPolicy(t.Policy)
+	}
+
+	if len(t.Nameservers)+len(t.Searches)+len(t.Options) > 0 {
+		if podSpec.DNSConfig == nil {
+			podSpec.DNSConfig = &corev1.PodDNSConfig{}
+		}
+		podSpec.DNSConfig.Nameservers = append(podSpec.DNSConfig.Nameservers, t.Nameservers...)
+		podSpec.DNSConfig.Searches = append(podSpec.DNSConfig.Searches, t.Searches...)
+		for _, o := range t.Options {
+			option := corev1.PodDNSConfigOption{}
+			parts := strings.SplitN(o, ":", 2)
+			option.Name = parts[0]
+			if len(parts) == 2 {
+				option.Value = pointer.String(parts[1])
+			}
+			podSpec.DNSConfig.Options = append(podSpec.DNSConfig.Options, option)
+		}
+	}
+
+	podSpec.HostAliases = append(podSpec.HostAliases, hostAliases...)
+
+	return nil
+}
+
+func (t *dnsTrait) getHostAliases() ([]corev1.HostAlias, error) {
+	hostAliases := make([]corev1.HostAlias, 0, len(t.HostAliases))
+	for _, h := range t.HostAliases {
+		parts := strings.SplitN(h, "=", 2)
+		if len(parts) != 2 || parts[1] == "" || net.ParseIP(parts[0]) == nil {
+			return nil, fmt.Errorf("invalid host alias %s, it must be in the form ip=hostname[,hostname]", h)
+		}
+		hostAliases = append(hostAliases, corev1.HostAlias{
+			IP:        parts[0],
+			Hostnames: strings.Split(parts[1], ","),
+		})
+	}
+	return hostAliases, nil
+}
@@ -0,0 +1,132 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestConfigureDNSTraitInvalidPolicy(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.Policy = "Unknown"
+
+	success, err := dnsTrait.Configure(environment)
+
+	assert.False(t, success)
+	assert.NotNil(t, err)
+}
+
+func TestConfigureDNSTraitNonePolicyWithoutNameservers(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.Policy = string(corev1.DNSNone)
+
+	success, err := dnsTrait.Configure(environment)
+
+	assert.False(t, success)
+	assert.NotNil(t, err)
+}
+
+func TestConfigureDNSTraitInvalidNameserver(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.Nameservers = []string{"dns.corp.example.com"}
+
+	success, err := dnsTrait.Configure(environment)
+
+	assert.False(t, success)
+	assert.NotNil(t, err)
+}
+
+func TestConfigureDNSTraitMalformedHostAlias(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.HostAliases = []string{"legacy.corp.example.com"}
+
+	success, err := dnsTrait.Configure(environment)
+
+	assert.False(t, success)
+	assert.NotNil(t, err)
+}
+
+func TestApplyDNSTraitMissingDeployment(t *testing.T) {
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.Policy = string(corev1.DNSDefault)
+
+	environment := createNominalMissingDeploymentTraitTest()
+	err := dnsTrait.Apply(environment)
+
+	assert.NotNil(t, err)
+}
+
+func TestApplyDNSTrait(t *testing.T) {
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.Policy = string(corev1.DNSNone)
+	dnsTrait.Nameservers = []string{"10.0.0.53"}
+	dnsTrait.Searches = []string{"corp.example.com"}
+	dnsTrait.Options = []string{"ndots:2", "edns0"}
+	dnsTrait.HostAliases = []string{"10.0.0.10=legacy.corp.example.com,legacy"}
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	success, err := dnsTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, success)
+
+	err = dnsTrait.Apply(environment)
+	assert.Nil(t, err)
+
+	spec := deployment.Spec.Template.Spec
+	assert.Equal(t, corev1.DNSNone, spec.DNSPolicy)
+	assert.Equal(t, &corev1.PodDNSConfig{
+		Nameservers: []string{"10.0.0.53"},
+		Searches:    []string{"corp.example.com"},
+		Options: []corev1.PodDNSConfigOption{
+			{Name: "ndots", Value: pointer.String("2")},
+			{Name: "edns0"},
+		},
+	}, spec.DNSConfig)
+	assert.Equal(t, []corev1.HostAlias{
+		{IP: "10.0.0.10", Hostnames: []string{"legacy.corp.example.com", "legacy"}},
+	}, spec.HostAliases)
+}
+
+func TestApplyDNSTraitHostAliasesOnly(t *testing.T) {
+	dnsTrait := createNominalDNSTrait()
+	dnsTrait.HostAliases = []string{"10.0.0.10=legacy.corp.example.com"}
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	err := dnsTrait.Apply(environment)
+	assert.Nil(t, err)
+
+	spec := deployment.Spec.Template.Spec
+	assert.Empty(t, spec.DNSPolicy)
+	assert.Nil(t, spec.DNSConfig)
+	assert.Len(t, spec.HostAliases, 1)
+}
+
+func createNominalDNSTrait() *dnsTrait {
+	dnsTrait, _ := newDNSTrait().(*dnsTrait)
+	dnsTrait.Enabled = pointer.Bool(true)
+	return dnsTrait
+}
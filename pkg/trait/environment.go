@@ -18,10 +18,10 @@ limitations under the License.
 package trait
 
 import (
-	"os"
-
 	"k8s.io/utils/pointer"
 
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/envvar"
@@ -36,15 +36,22 @@ type environmentTrait struct {
 	BaseTrait `property:",squash"`
 	// Enables injection of `NAMESPACE` and `POD_NAME` environment variables (default `true`)
 	ContainerMeta *bool `property:"container-meta" json:"containerMeta,omitempty"`
-	// Propagates the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables (default `true`)
+	// Propagates the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables, either from the
+	// proxy configured in the IntegrationPlatform, or from the operator environment (default `true`)
 	HTTPProxy *bool `property:"http-proxy" json:"httpProxy,omitempty"`
 	// A list of environment variables to be added to the integration container.
 	// The syntax is KEY=VALUE, e.g., `MY_VAR="my value"`.
-	// These take precedence over the previously defined environment variables.
+	// These take precedence over the environment variables injected by the traits, and the ones
+	// defined in the platform and integration configuration.
 	Vars []string `property:"vars" json:"vars,omitempty"`
+	// Lets the environment variables injected by the traits override the ones defined in the platform
+	// and integration configuration, as with previous versions (default `false`)
+	TraitVarsOverride *bool `property:"trait-vars-override" json:"traitVarsOverride,omitempty"`
 }
 
 const (
+	environmentTraitID = "environment"
+
 	envVarNamespace            = "NAMESPACE"
 	envVarPodName              = "POD_NAME"
 	envVarCamelKVersion        = "CAMEL_K_VERSION"
@@ -63,7 +70,7 @@ const (
 
 func newEnvironmentTrait() Trait {
 	return &environmentTrait{
-		BaseTrait:     NewBaseTrait("environment", 800),
+		BaseTrait:     NewBaseTrait(environmentTraitID, 800),
 		ContainerMeta: pointer.Bool(true),
 	}
 }
@@ -91,25 +98,31 @@ func (t *environmentTrait) Apply(e *Environment) error {
 	}
 
 	if pointer.BoolDeref(t.HTTPProxy, true) {
-		if HTTPProxy, ok := os.LookupEnv("HTTP_PROXY"); ok {
-			envvar.SetVal(&e.EnvVars, "HTTP_PROXY", HTTPProxy)
+		// The proxy configured in the platform takes precedence over the one of the operator
+		var proxy *v1.ProxySpec
+		if e.Platform != nil {
+			proxy = e.Platform.Status.Proxy
 		}
-		if HTTPSProxy, ok := os.LookupEnv("HTTPS_PROXY"); ok {
-			envvar.SetVal(&e.EnvVars, "HTTPS_PROXY", HTTPSProxy)
-		}
-		if noProxy, ok := os.LookupEnv("NO_PROXY"); ok {
-			envvar.SetVal(&e.EnvVars, "NO_PROXY", noProxy)
+		for _, v := range platform.GetProxyEnvVars(proxy) {
+			envvar.SetVar(&e.EnvVars, v)
 		}
 	}
 
-	if t.Vars != nil {
-		for _, env := range t.Vars {
-			k, v := property.SplitPropertyFileEntry(env)
-			envvar.SetVal(&e.EnvVars, k, v)
+	return nil
+}
+
+// userEnvVars returns the environment variables set by the user, that are added to the integration container
+// with the highest precedence.
+func (t *environmentTrait) userEnvVars() []Variable {
+	vars := make([]Variable, 0, len(t.Vars))
+	for _, env := range t.Vars {
+		k, v := property.SplitPropertyFileEntry(env)
+		if k == "" {
+			continue
 		}
+		vars = setVariable(vars, Variable{Name: k, Value: v, Source: ConfigurationSourceUser})
 	}
-
-	return nil
+	return vars
 }
 
 // IsPlatformTrait overrides base class method.
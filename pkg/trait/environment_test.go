@@ -28,6 +28,8 @@ import (
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/defaults"
+	"github.com/apache/camel-k/pkg/util/envvar"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/test"
 )
@@ -189,6 +191,123 @@ func TestCustomEnvVars(t *testing.T) {
 	assert.True(t, userK2)
 }
 
+func TestEnvVarsPrecedence(t *testing.T) {
+	c, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	env := mockEnvironment(c)
+	env.Platform.Spec.Configuration = []v1.ConfigurationSpec{
+		{Type: "env", Value: "MY_VAR=platform"},
+		{Type: "env", Value: "MY_PLATFORM_VAR=platform"},
+	}
+	env.Integration.Spec.Configuration = []v1.ConfigurationSpec{
+		{Type: "env", Value: "MY_VAR=integration"},
+		{Type: "env", Value: "MY_USER_VAR=integration"},
+		{Type: "env", Value: envVarCamelKVersion + "=integration"},
+	}
+	env.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"environment": test.TraitSpecFromMap(t, map[string]interface{}{
+			"vars": []string{"MY_USER_VAR=user"},
+		}),
+	}
+	env.Platform.ResyncStatusFullConfig()
+
+	err = NewEnvironmentTestCatalog().apply(&env)
+	assert.Nil(t, err)
+
+	vars := findIntegrationContainerEnv(env)
+	assert.Equal(t, "platform", envvar.Get(vars, "MY_PLATFORM_VAR").Value)
+	assert.Equal(t, "integration", envvar.Get(vars, "MY_VAR").Value)
+	assert.Equal(t, "user", envvar.Get(vars, "MY_USER_VAR").Value)
+	assert.Equal(t, "integration", envvar.Get(vars, envVarCamelKVersion).Value)
+}
+
+func TestEnvVarsTraitVarsOverride(t *testing.T) {
+	c, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	env := mockEnvironment(c)
+	env.Integration.Spec.Configuration = []v1.ConfigurationSpec{
+		{Type: "env", Value: envVarCamelKVersion + "=integration"},
+	}
+	env.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"environment": test.TraitSpecFromMap(t, map[string]interface{}{
+			"vars":              []string{envVarCamelKIntegration + "=user"},
+			"traitVarsOverride": true,
+		}),
+	}
+	env.Platform.ResyncStatusFullConfig()
+
+	err = NewEnvironmentTestCatalog().apply(&env)
+	assert.Nil(t, err)
+
+	vars := findIntegrationContainerEnv(env)
+	assert.Equal(t, defaults.Version, envvar.Get(vars, envVarCamelKVersion).Value)
+	assert.Equal(t, "user", envvar.Get(vars, envVarCamelKIntegration).Value)
+}
+
+func TestPlatformProxyEnvVars(t *testing.T) {
+	c, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	env := mockEnvironment(c)
+	env.Platform.Spec.Proxy = &v1.ProxySpec{
+		HTTPProxy:  "http://platform-proxy:3128",
+		HTTPSProxy: "http://platform-proxy:3128",
+	}
+	env.Platform.ResyncStatusFullConfig()
+
+	err = NewEnvironmentTestCatalog().apply(&env)
+	assert.Nil(t, err)
+
+	vars := findIntegrationContainerEnv(env)
+	assert.Equal(t, "http://platform-proxy:3128", envvar.Get(vars, "HTTP_PROXY").Value)
+	assert.Equal(t, "http://platform-proxy:3128", envvar.Get(vars, "HTTPS_PROXY").Value)
+	assert.Nil(t, envvar.Get(vars, "NO_PROXY"))
+}
+
+func TestEffectiveConfiguration(t *testing.T) {
+	platform := &v1.IntegrationPlatform{
+		Spec: v1.IntegrationPlatformSpec{
+			Configuration: []v1.ConfigurationSpec{
+				{Type: "env", Value: "MY_VAR=platform"},
+				{Type: "property", Value: "my.property=platform"},
+			},
+		},
+	}
+	platform.ResyncStatusFullConfig()
+	integration := &v1.Integration{
+		Spec: v1.IntegrationSpec{
+			Configuration: []v1.ConfigurationSpec{
+				{Type: "env", Value: "MY_VAR=integration"},
+			},
+			Traits: map[string]v1.TraitSpec{
+				"environment": test.TraitSpecFromMap(t, map[string]interface{}{
+					"vars": []string{"MY_USER_VAR=user"},
+				}),
+			},
+		},
+	}
+
+	env, properties, err := EffectiveConfiguration(platform, nil, integration)
+	assert.Nil(t, err)
+	assert.Equal(t, []Variable{
+		{Name: "MY_VAR", Value: "integration", Source: ConfigurationSourceIntegration},
+		{Name: "MY_USER_VAR", Value: "user", Source: ConfigurationSourceUser},
+	}, env)
+	assert.Equal(t, []Variable{
+		{Name: "my.property", Value: "platform", Source: ConfigurationSourcePlatform},
+	}, properties)
+}
+
+func findIntegrationContainerEnv(env Environment) []corev1.EnvVar {
+	var vars []corev1.EnvVar
+	env.Resources.VisitDeployment(func(deployment *appsv1.Deployment) {
+		vars = deployment.Spec.Template.Spec.Containers[0].Env
+	})
+	return vars
+}
+
 func NewEnvironmentTestCatalog() *Catalog {
 	return NewCatalog(nil)
 }
@@ -0,0 +1,377 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/util/log"
+)
+
+const (
+	// ExternalTraitLabel marks the ConfigMaps, in the operator namespace, that define an external trait.
+	ExternalTraitLabel = "camel.apache.org/trait.plugin"
+	// ExternalTraitDefinitionKey is the ConfigMap key holding the external trait definition.
+	ExternalTraitDefinitionKey = "trait.yaml"
+	// ExternalTraitTransportHTTP calls the plugin server with a JSON payload posted over HTTP.
+	ExternalTraitTransportHTTP = "http"
+	// ExternalTraitTransportGRPC calls the plugin server with the ExternalTraitGRPCMethod unary gRPC method.
+	ExternalTraitTransportGRPC = "grpc"
+
+	defaultExternalTraitTimeout = 10 * time.Second
+)
+
+// ExternalTraitDefinition declares a trait that is provided by a third party, either as a list of patches
+// applied to the Integration resources, or by a plugin server that computes these patches.
+type ExternalTraitDefinition struct {
+	// The trait ID, that must not conflict with any of the built-in traits
+	ID ID `json:"id"`
+	// The order of execution of the trait, that defaults to the post-processing of the resources
+	Order *int `json:"order,omitempty"`
+	// Whether the trait is enabled when it's not configured explicitly (defaults to true)
+	Enabled *bool `json:"enabled,omitempty"`
+	// The traits that must be executed before this trait
+	Requires []ID `json:"requires,omitempty"`
+	// The traits that must be executed after this trait
+	Influences []ID `json:"influences,omitempty"`
+	// The profiles the trait is allowed in (defaults to all profiles)
+	Profiles []v1.TraitProfile `json:"profiles,omitempty"`
+	// The URL of the plugin server, e.g., a sidecar of the operator Pod, or its gRPC target with the grpc transport
+	Endpoint string `json:"endpoint,omitempty"`
+	// The transport used to call the plugin server, either http or grpc (defaults to http)
+	Transport string `json:"transport,omitempty"`
+	// The timeout of the requests to the plugin server (defaults to 10s)
+	Timeout string `json:"timeout,omitempty"`
+	// The patches applied to the Integration resources
+	Patches []ExternalTraitPatch `json:"patches,omitempty"`
+}
+
+// ExternalTraitPatch is a patch applied to the Integration resources of the given kind, and optionally the given name.
+// Typed resources are patched with a strategic merge patch, other resources with a JSON merge patch.
+type ExternalTraitPatch struct {
+	Kind  string          `json:"kind"`
+	Name  string          `json:"name,omitempty"`
+	Patch json.RawMessage `json:"patch"`
+}
+
+// ExternalTraitRequest is the payload sent to the plugin server.
+type ExternalTraitRequest struct {
+	Trait         ID                `json:"trait"`
+	Configuration map[string]string `json:"configuration,omitempty"`
+	Integration   *v1.Integration   `json:"integration"`
+	Resources     []ctrl.Object     `json:"resources"`
+}
+
+// ExternalTraitResponse is the payload returned by the plugin server.
+type ExternalTraitResponse struct {
+	Patches []ExternalTraitPatch `json:"patches,omitempty"`
+}
+
+// externalTrait applies the customizations of a trait defined outside of the operator.
+type externalTrait struct {
+	BaseTrait `property:",squash"`
+	// The configuration passed to the plugin server
+	Configuration map[string]string `property:"configuration" json:"configuration,omitempty"`
+
+	definition ExternalTraitDefinition
+	timeout    time.Duration
+	plugin     externalTraitPlugin
+}
+
+// externalTraitPlugin calls the plugin server of an external trait over a given transport.
+type externalTraitPlugin interface {
+	Call(ctx context.Context, request *ExternalTraitRequest) (*ExternalTraitResponse, error)
+}
+
+func newExternalTraitFactory(definition ExternalTraitDefinition) (Factory, error) {
+	if definition.ID == "" {
+		return nil, errors.New("missing trait id")
+	}
+	if definition.Endpoint == "" && len(definition.Patches) == 0 {
+		return nil, fmt.Errorf("trait %s defines neither an endpoint nor patches", definition.ID)
+	}
+	for _, p := range definition.Patches {
+		if p.Kind == "" || len(p.Patch) == 0 {
+			return nil, fmt.Errorf("trait %s defines a patch without kind or content", definition.ID)
+		}
+	}
+	timeout := defaultExternalTraitTimeout
+	if definition.Timeout != "" {
+		d, err := time.ParseDuration(definition.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout for trait %s", definition.ID)
+		}
+		timeout = d
+	}
+	order := TraitOrderPostProcessResources
+	if definition.Order != nil {
+		order = *definition.Order
+	}
+
+	// The plugin is shared by the instances of the trait
+	var plugin externalTraitPlugin
+	if definition.Endpoint != "" {
+		switch definition.Transport {
+		case "", ExternalTraitTransportHTTP:
+			// The client bounds the duration of the requests, including the reading of the response body
+			plugin = &httpTraitPlugin{
+				endpoint: definition.Endpoint,
+				client:   &http.Client{Timeout: timeout},
+			}
+		case ExternalTraitTransportGRPC:
+			plugin = &grpcTraitPlugin{
+				target: definition.Endpoint,
+			}
+		default:
+			return nil, fmt.Errorf("unsupported transport %s for trait %s", definition.Transport, definition.ID)
+		}
+	}
+
+	return func() Trait {
+		return &externalTrait{
+			BaseTrait:  NewBaseTrait(string(definition.ID), order),
+			definition: definition,
+			timeout:    timeout,
+			plugin:     plugin,
+		}
+	}, nil
+}
+
+// LoadExternalTraits registers the external traits defined by the labelled ConfigMaps of the given namespace.
+// Invalid definitions, definitions conflicting with an already registered trait, or declaring dependencies
+// that cannot be satisfied, are skipped.
+func LoadExternalTraits(ctx context.Context, c client.Client, namespace string) error {
+	configMaps := corev1.ConfigMapList{}
+	if err := c.List(ctx, &configMaps, ctrl.InNamespace(namespace), ctrl.HasLabels{ExternalTraitLabel}); err != nil {
+		return err
+	}
+
+	registered := make(map[ID]bool)
+	traits := make([]Trait, 0, len(FactoryList))
+	for _, factory := range FactoryList {
+		t := factory()
+		registered[t.ID()] = true
+		traits = append(traits, t)
+	}
+
+	logger := log.Log.WithName("traits")
+	for _, cm := range configMaps.Items {
+		definition, err := parseExternalTraitDefinition(cm.Data[ExternalTraitDefinitionKey])
+		if err == nil && registered[definition.ID] {
+			err = fmt.Errorf("trait %s is already registered", definition.ID)
+		}
+		var factory Factory
+		if err == nil {
+			factory, err = newExternalTraitFactory(definition)
+		}
+		var trait Trait
+		if err == nil {
+			// The catalog cannot be created if the dependencies between the traits are cyclic
+			trait = factory()
+			if _, err = sortTraits(append(traits, trait)); err != nil {
+				err = errors.Wrapf(err, "invalid dependencies of trait %s", definition.ID)
+			}
+		}
+		if err != nil {
+			logger.Errorf(err, "Skipping the external trait defined in ConfigMap %s/%s", cm.Namespace, cm.Name)
+			continue
+		}
+
+		AddToTraits(factory)
+		traits = append(traits, trait)
+		registered[definition.ID] = true
+		logger.Infof("Registered the external trait %s from ConfigMap %s/%s", definition.ID, cm.Namespace, cm.Name)
+	}
+
+	return nil
+}
+
+func parseExternalTraitDefinition(data string) (ExternalTraitDefinition, error) {
+	definition := ExternalTraitDefinition{}
+	if data == "" {
+		return definition, fmt.Errorf("missing %s key", ExternalTraitDefinitionKey)
+	}
+	content, err := yaml.ToJSON([]byte(data))
+	if err != nil {
+		return definition, err
+	}
+	err = json.Unmarshal(content, &definition)
+	return definition, err
+}
+
+func (t *externalTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, pointer.BoolDeref(t.definition.Enabled, true)) {
+		return false, nil
+	}
+
+	if e.Integration == nil {
+		return false, nil
+	}
+
+	return e.IntegrationInPhase(v1.IntegrationPhaseInitialization) || e.IntegrationInRunningPhases(), nil
+}
+
+func (t *externalTrait) Apply(e *Environment) error {
+	patches := t.definition.Patches
+	if t.plugin != nil {
+		response, err := t.callEndpoint(e)
+		if err != nil {
+			return errors.Wrapf(err, "cannot call the endpoint of trait %s", t.ID())
+		}
+		patches = append(append([]ExternalTraitPatch(nil), patches...), response.Patches...)
+	}
+
+	for _, p := range patches {
+		for _, resource := range e.Resources.Items() {
+			if resourceKind(resource) != p.Kind || (p.Name != "" && resource.GetName() != p.Name) {
+				continue
+			}
+			if err := patchResource(resource, p.Patch); err != nil {
+				return errors.Wrapf(err, "cannot apply the patches of trait %s to %s %s", t.ID(), p.Kind, resource.GetName())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *externalTrait) callEndpoint(e *Environment) (*ExternalTraitResponse, error) {
+	ctx := e.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.plugin.Call(ctx, &ExternalTraitRequest{
+		Trait:         t.ID(),
+		Configuration: t.Configuration,
+		Integration:   e.Integration,
+		Resources:     e.Resources.Items(),
+	})
+}
+
+// httpTraitPlugin posts the request to the plugin server as JSON.
+type httpTraitPlugin struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (p *httpTraitPlugin) Call(ctx context.Context, request *ExternalTraitRequest) (*ExternalTraitResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	content, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s: %s", res.Status, string(content))
+	}
+
+	response := ExternalTraitResponse{}
+	if err := json.Unmarshal(content, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RequiresTraits returns the traits the definition declares to be executed before.
+func (t *externalTrait) RequiresTraits() []ID {
+	return t.definition.Requires
+}
+
+// InfluencesTraits returns the traits the definition declares to be executed after.
+func (t *externalTrait) InfluencesTraits() []ID {
+	return t.definition.Influences
+}
+
+// IsAllowedInProfile restricts the trait to the profiles of the definition, if any.
+func (t *externalTrait) IsAllowedInProfile(profile v1.TraitProfile) bool {
+	if len(t.definition.Profiles) == 0 {
+		return true
+	}
+	for _, p := range t.definition.Profiles {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceKind(resource ctrl.Object) string {
+	if kind := resource.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.Indirect(reflect.ValueOf(resource)).Type().Name()
+}
+
+func patchResource(resource ctrl.Object, patch []byte) error {
+	source, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	if _, ok := resource.(runtime.Unstructured); ok {
+		patched, err = jsonpatch.MergePatch(source, patch)
+	} else {
+		patched, err = strategicpatch.StrategicMergePatch(source, patch, resource)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reset the resource so that the fields removed by the patch are actually removed
+	reflect.Indirect(reflect.ValueOf(resource)).Set(reflect.Zero(reflect.Indirect(reflect.ValueOf(resource)).Type()))
+	return json.Unmarshal(patched, resource)
+}
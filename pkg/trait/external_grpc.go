@@ -0,0 +1,76 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ExternalTraitGRPCMethod is the unary gRPC method implemented by the plugin servers using the grpc transport.
+// The request and response messages are the ExternalTraitRequest and ExternalTraitResponse JSON payloads,
+// exchanged with the application/grpc+json content type.
+const ExternalTraitGRPCMethod = "/org.apache.camel.k.trait.v1.TraitPlugin/Apply"
+
+// externalTraitCodec encodes the gRPC messages as JSON, so that the plugin servers share the payloads
+// of the HTTP transport, and do not depend on generated Protocol Buffers stubs.
+type externalTraitCodec struct{}
+
+func (externalTraitCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (externalTraitCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (externalTraitCodec) Name() string {
+	return "json"
+}
+
+// grpcTraitPlugin calls the plugin server over gRPC. The connection is established on the first call,
+// and shared by the following calls.
+type grpcTraitPlugin struct {
+	target string
+
+	once sync.Once
+	conn *grpc.ClientConn
+	err  error
+}
+
+func (p *grpcTraitPlugin) Call(ctx context.Context, request *ExternalTraitRequest) (*ExternalTraitResponse, error) {
+	p.once.Do(func() {
+		p.conn, p.err = grpc.Dial(p.target,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.ForceCodec(externalTraitCodec{})),
+		)
+	})
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	response := ExternalTraitResponse{}
+	if err := p.conn.Invoke(ctx, ExternalTraitGRPCMethod, request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
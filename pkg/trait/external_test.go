@@ -0,0 +1,269 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const costCenterTraitDefinition = `
+id: cost-center
+profiles:
+- Kubernetes
+patches:
+- kind: Deployment
+  patch:
+    metadata:
+      annotations:
+        example.com/cost-center: "1234"
+    spec:
+      template:
+        spec:
+          containers:
+          - name: audit
+            image: example.com/audit:1.0
+`
+
+func TestParseExternalTraitDefinition(t *testing.T) {
+	definition, err := parseExternalTraitDefinition(costCenterTraitDefinition)
+	assert.Nil(t, err)
+	assert.Equal(t, ID("cost-center"), definition.ID)
+	assert.Equal(t, []v1.TraitProfile{v1.TraitProfileKubernetes}, definition.Profiles)
+	assert.Len(t, definition.Patches, 1)
+
+	factory, err := newExternalTraitFactory(definition)
+	assert.Nil(t, err)
+	trait := factory()
+	assert.Equal(t, ID("cost-center"), trait.ID())
+	assert.Equal(t, TraitOrderPostProcessResources, trait.Order())
+	assert.True(t, trait.IsAllowedInProfile(v1.TraitProfileKubernetes))
+	assert.False(t, trait.IsAllowedInProfile(v1.TraitProfileKnative))
+}
+
+func TestInvalidExternalTraitDefinition(t *testing.T) {
+	_, err := newExternalTraitFactory(ExternalTraitDefinition{ID: "empty"})
+	assert.NotNil(t, err)
+
+	_, err = newExternalTraitFactory(ExternalTraitDefinition{ID: "timeout", Endpoint: "http://localhost:9090", Timeout: "soon"})
+	assert.NotNil(t, err)
+
+	_, err = newExternalTraitFactory(ExternalTraitDefinition{ID: "transport", Endpoint: "localhost:9090", Transport: "wasm"})
+	assert.NotNil(t, err)
+}
+
+func TestExternalTraitPatches(t *testing.T) {
+	definition, err := parseExternalTraitDefinition(costCenterTraitDefinition)
+	assert.Nil(t, err)
+	factory, err := newExternalTraitFactory(definition)
+	assert.Nil(t, err)
+	trait := factory()
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "integration", Image: "my-image"}}
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(environment))
+
+	assert.Equal(t, "1234", deployment.Annotations["example.com/cost-center"])
+	assert.Len(t, deployment.Spec.Template.Spec.Containers, 2)
+	assert.Equal(t, "integration", deployment.Spec.Template.Spec.Containers[0].Name)
+	assert.Equal(t, "audit", deployment.Spec.Template.Spec.Containers[1].Name)
+}
+
+func TestExternalTraitEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := ExternalTraitRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		patch := `{"metadata":{"labels":{"example.com/team":"` + request.Configuration["team"] + `"}}}`
+		_ = json.NewEncoder(w).Encode(ExternalTraitResponse{
+			Patches: []ExternalTraitPatch{{Kind: "Deployment", Name: request.Integration.Name, Patch: json.RawMessage(patch)}},
+		})
+	}))
+	defer server.Close()
+
+	factory, err := newExternalTraitFactory(ExternalTraitDefinition{ID: "team", Endpoint: server.URL})
+	assert.Nil(t, err)
+	trait := factory()
+	trait.(*externalTrait).Configuration = map[string]string{"team": "integration"}
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(environment))
+
+	assert.Equal(t, "integration", deployment.Labels["example.com/team"])
+}
+
+func TestExternalTraitEndpointFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	factory, err := newExternalTraitFactory(ExternalTraitDefinition{ID: "failing", Endpoint: server.URL})
+	assert.Nil(t, err)
+	trait := factory()
+
+	environment, _ := createNominalDeploymentTraitTest()
+	assert.NotNil(t, trait.Apply(environment))
+}
+
+// pluginRequest decodes the requests received by the test plugin servers, that are not concerned by the resources.
+type pluginRequest struct {
+	Trait         ID                `json:"trait"`
+	Configuration map[string]string `json:"configuration,omitempty"`
+	Integration   *v1.Integration   `json:"integration"`
+}
+
+func startGRPCTraitPlugin(t *testing.T, apply func(request *pluginRequest) (*ExternalTraitResponse, error)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	server := grpc.NewServer(grpc.ForceServerCodec(externalTraitCodec{}))
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "org.apache.camel.k.trait.v1.TraitPlugin",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Apply",
+				Handler: func(_ interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					request := pluginRequest{}
+					if err := dec(&request); err != nil {
+						return nil, err
+					}
+					return apply(&request)
+				},
+			},
+		},
+	}, struct{}{})
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestExternalTraitGRPCEndpoint(t *testing.T) {
+	target := startGRPCTraitPlugin(t, func(request *pluginRequest) (*ExternalTraitResponse, error) {
+		patch := `{"metadata":{"labels":{"example.com/team":"` + request.Configuration["team"] + `"}}}`
+		return &ExternalTraitResponse{
+			Patches: []ExternalTraitPatch{{Kind: "Deployment", Name: request.Integration.Name, Patch: json.RawMessage(patch)}},
+		}, nil
+	})
+
+	factory, err := newExternalTraitFactory(ExternalTraitDefinition{ID: "team", Endpoint: target, Transport: ExternalTraitTransportGRPC})
+	assert.Nil(t, err)
+	trait := factory()
+	trait.(*externalTrait).Configuration = map[string]string{"team": "integration"}
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(environment))
+
+	assert.Equal(t, "integration", deployment.Labels["example.com/team"])
+}
+
+func TestExternalTraitGRPCEndpointFailure(t *testing.T) {
+	target := startGRPCTraitPlugin(t, func(_ *pluginRequest) (*ExternalTraitResponse, error) {
+		return nil, status.Error(codes.InvalidArgument, "missing team")
+	})
+
+	factory, err := newExternalTraitFactory(ExternalTraitDefinition{ID: "failing", Endpoint: target, Transport: ExternalTraitTransportGRPC})
+	assert.Nil(t, err)
+	trait := factory()
+
+	environment, _ := createNominalDeploymentTraitTest()
+	err = trait.Apply(environment)
+	assert.NotNil(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(errors.Cause(err)))
+}
+
+func TestLoadExternalTraitsSkipsCyclicDependencies(t *testing.T) {
+	factories := FactoryList
+	defer func() {
+		FactoryList = factories
+	}()
+
+	newConfigMap := func(name string, definition string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "operator",
+				Name:      name,
+				Labels:    map[string]string{ExternalTraitLabel: "true"},
+			},
+			Data: map[string]string{ExternalTraitDefinitionKey: definition},
+		}
+	}
+	c, err := test.NewFakeClient(
+		newConfigMap("cost-center", costCenterTraitDefinition),
+		// The container trait requires the deployment trait
+		newConfigMap("cyclic", `
+id: cyclic
+requires:
+- container
+influences:
+- deployment
+patches:
+- kind: Deployment
+  patch:
+    metadata:
+      labels:
+        example.com/cyclic: "true"
+`),
+	)
+	assert.Nil(t, err)
+
+	assert.Nil(t, LoadExternalTraits(context.TODO(), c, "operator"))
+	assert.Len(t, FactoryList, len(factories)+1)
+
+	assert.NotPanics(t, func() {
+		catalog := NewCatalog(nil)
+		assert.NotNil(t, catalog.GetTrait("cost-center"))
+		assert.Nil(t, catalog.GetTrait("cyclic"))
+	})
+}
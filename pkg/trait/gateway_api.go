@@ -0,0 +1,240 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const (
+	httpRouteAPIVersion = "gateway.networking.k8s.io/v1beta1"
+	grpcRouteAPIVersion = "gateway.networking.k8s.io/v1alpha2"
+)
+
+// The Gateway API trait can be used to expose the service associated with the integration
+// with Kubernetes Gateway API routes, attached to an existing Gateway, as an alternative to the `ingress` and `route` traits.
+//
+// It creates an `HTTPRoute` that forwards the traffic matching the configured hostnames and path to the `http` port of the service,
+// and optionally a `GRPCRoute` that forwards the gRPC traffic to its `grpc` port, when the container exposes one.
+//
+// The hostnames default to the one templated by the IntegrationPlatform `exposure` configuration, if any.
+// When none is set, the routes match all the hostnames of the Gateway listener.
+//
+// It's disabled by default, and requires the Gateway API CRDs to be installed on the cluster.
+//
+// +camel-k:trait=gateway-api.
+type gatewayAPITrait struct {
+	BaseTrait `property:",squash"`
+	// **Required**. The name of the Gateway the routes are attached to.
+	Gateway string `property:"gateway" json:"gateway,omitempty"`
+	// The namespace of the Gateway, defaults to the integration namespace.
+	GatewayNamespace string `property:"gateway-namespace" json:"gatewayNamespace,omitempty"`
+	// The name of the Gateway listener the routes are attached to, all the listeners of the Gateway by default.
+	SectionName string `property:"section-name" json:"sectionName,omitempty"`
+	// The hostnames matched by the routes.
+	Hostnames []string `property:"hostnames" json:"hostnames,omitempty"`
+	// The path prefix matched by the HTTPRoute (default `/`).
+	Path string `property:"path" json:"path,omitempty"`
+	// To create a GRPCRoute, in addition to the HTTPRoute, forwarding the gRPC traffic to the `grpc` port of the service.
+	GRPC *bool `property:"grpc" json:"grpc,omitempty"`
+}
+
+func newGatewayAPITrait() Trait {
+	return &gatewayAPITrait{
+		BaseTrait: NewBaseTrait("gateway-api", 2410),
+	}
+}
+
+// IsAllowedInProfile overrides default.
+func (t *gatewayAPITrait) IsAllowedInProfile(profile v1.TraitProfile) bool {
+	return profile == v1.TraitProfileKubernetes
+}
+
+func (t *gatewayAPITrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if t.Gateway == "" {
+		e.Integration.Status.SetCondition(
+			v1.IntegrationConditionExposureAvailable,
+			corev1.ConditionFalse,
+			v1.IntegrationConditionGatewayAPINotAvailableReason,
+			"no gateway defined",
+		)
+
+		return false, errors.New("cannot apply gateway-api trait: no gateway defined")
+	}
+
+	if e.Resources.GetUserServiceForIntegration(e.Integration) == nil {
+		e.Integration.Status.SetCondition(
+			v1.IntegrationConditionExposureAvailable,
+			corev1.ConditionFalse,
+			v1.IntegrationConditionGatewayAPINotAvailableReason,
+			"no service defined",
+		)
+
+		return false, nil
+	}
+
+	if len(t.Hostnames) == 0 && e.Platform != nil {
+		if host := e.Platform.Status.Exposure.GetHost(e.Integration.Name, e.Integration.Namespace); host != "" {
+			t.Hostnames = []string{host}
+		}
+	}
+
+	return true, nil
+}
+
+func (t *gatewayAPITrait) Apply(e *Environment) error {
+	service := e.Resources.GetUserServiceForIntegration(e.Integration)
+	if service == nil {
+		return errors.New("cannot apply gateway-api trait: no target service")
+	}
+
+	servicePortName := defaultContainerPortName
+	if dt := e.Catalog.GetTrait(containerTraitID); dt != nil {
+		servicePortName = dt.(*containerTrait).ServicePortName
+	}
+	httpPort := findServicePort(service, servicePortName)
+	if httpPort == nil {
+		return fmt.Errorf("cannot apply gateway-api trait: no %s port defined on service %s", servicePortName, service.Name)
+	}
+
+	path := t.Path
+	if path == "" {
+		path = "/"
+	}
+
+	httpRoute := t.newRoute(service, httpRouteAPIVersion, "HTTPRoute")
+	httpRoute.Object["spec"].(map[string]interface{})["rules"] = []interface{}{
+		map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":  "PathPrefix",
+						"value": path,
+					},
+				},
+			},
+			"backendRefs": gatewayBackendRefs(service, httpPort),
+		},
+	}
+	e.Resources.Add(httpRoute)
+
+	routes := []string{fmt.Sprintf("%s(%s%s) -> %s(%s)", httpRoute.GetName(), t.hosts(), path, service.Name, httpPort.Name)}
+
+	if pointer.BoolDeref(t.GRPC, false) {
+		grpcPort := findServicePort(service, "grpc")
+		if grpcPort == nil {
+			return fmt.Errorf("cannot apply gateway-api trait: no grpc port defined on service %s, it can be exposed with the container trait ports", service.Name)
+		}
+
+		grpcRoute := t.newRoute(service, grpcRouteAPIVersion, "GRPCRoute")
+		grpcRoute.Object["spec"].(map[string]interface{})["rules"] = []interface{}{
+			map[string]interface{}{
+				"backendRefs": gatewayBackendRefs(service, grpcPort),
+			},
+		}
+		e.Resources.Add(grpcRoute)
+
+		routes = append(routes, fmt.Sprintf("%s(%s) -> %s(%s)", grpcRoute.GetName(), t.hosts(), service.Name, grpcPort.Name))
+	}
+
+	e.Integration.Status.SetCondition(
+		v1.IntegrationConditionExposureAvailable,
+		corev1.ConditionTrue,
+		v1.IntegrationConditionGatewayAPIAvailableReason,
+		strings.Join(routes, ", "),
+	)
+
+	return nil
+}
+
+// newRoute returns a route of the given kind, attached to the Gateway, and matching the hostnames.
+func (t *gatewayAPITrait) newRoute(service *corev1.Service, apiVersion string, kind string) *unstructured.Unstructured {
+	parentRef := map[string]interface{}{
+		"name": t.Gateway,
+	}
+	if t.GatewayNamespace != "" {
+		parentRef["namespace"] = t.GatewayNamespace
+	}
+	if t.SectionName != "" {
+		parentRef["sectionName"] = t.SectionName
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+	}
+	if len(t.Hostnames) > 0 {
+		hostnames := make([]interface{}, 0, len(t.Hostnames))
+		for _, hostname := range t.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+
+	route := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	route.SetAPIVersion(apiVersion)
+	route.SetKind(kind)
+	route.SetName(service.Name)
+	route.SetNamespace(service.Namespace)
+
+	return route
+}
+
+func (t *gatewayAPITrait) hosts() string {
+	if len(t.Hostnames) == 0 {
+		return "*"
+	}
+	return strings.Join(t.Hostnames, ",")
+}
+
+func findServicePort(service *corev1.Service, name string) *corev1.ServicePort {
+	for i := range service.Spec.Ports {
+		if service.Spec.Ports[i].Name == name {
+			return &service.Spec.Ports[i]
+		}
+	}
+	return nil
+}
+
+func gatewayBackendRefs(service *corev1.Service, port *corev1.ServicePort) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"name": service.Name,
+			"port": int64(port.Port),
+		},
+	}
+}
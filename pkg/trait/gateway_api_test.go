@@ -0,0 +1,277 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+func TestConfigureGatewayAPITraitDoesSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	configured, err := gatewayAPITrait.Configure(environment)
+
+	assert.True(t, configured)
+	assert.Nil(t, err)
+	assert.Len(t, environment.Integration.Status.Conditions, 0)
+}
+
+func TestConfigureGatewayAPITraitIsDisabledByDefault(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	gatewayAPITrait.Enabled = nil
+
+	configured, err := gatewayAPITrait.Configure(environment)
+
+	assert.False(t, configured)
+	assert.Nil(t, err)
+}
+
+func TestConfigureGatewayAPITraitWithoutGatewayDoesNotSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	gatewayAPITrait.Gateway = ""
+
+	configured, err := gatewayAPITrait.Configure(environment)
+
+	assert.False(t, configured)
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot apply gateway-api trait: no gateway defined", err.Error())
+	conditions := environment.Integration.Status.Conditions
+	assert.Len(t, conditions, 1)
+	assert.Equal(t, v1.IntegrationConditionGatewayAPINotAvailableReason, conditions[0].Reason)
+}
+
+func TestConfigureGatewayAPITraitWithoutUserServiceDoesNotSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	environment.Resources = kubernetes.NewCollection()
+
+	configured, err := gatewayAPITrait.Configure(environment)
+
+	assert.False(t, configured)
+	assert.Nil(t, err)
+	conditions := environment.Integration.Status.Conditions
+	assert.Len(t, conditions, 1)
+	assert.Equal(t, "no service defined", conditions[0].Message)
+}
+
+func TestApplyGatewayAPITraitDoesSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	gatewayAPITrait.GatewayNamespace = "gateways"
+	gatewayAPITrait.SectionName = "http"
+	gatewayAPITrait.Hostnames = []string{"hello.example.com"}
+
+	configured, err := gatewayAPITrait.Configure(environment)
+	assert.True(t, configured)
+	assert.Nil(t, err)
+
+	err = gatewayAPITrait.Apply(environment)
+	assert.Nil(t, err)
+
+	routes := gatewayAPIRoutes(environment)
+	assert.Len(t, routes, 1)
+	route := routes[0]
+	assert.Equal(t, httpRouteAPIVersion, route.GetAPIVersion())
+	assert.Equal(t, "HTTPRoute", route.GetKind())
+	assert.Equal(t, "service-name", route.GetName())
+	assert.Equal(t, "namespace", route.GetNamespace())
+	assert.Equal(t, map[string]interface{}{
+		"parentRefs": []interface{}{
+			map[string]interface{}{
+				"name":        "gateway",
+				"namespace":   "gateways",
+				"sectionName": "http",
+			},
+		},
+		"hostnames": []interface{}{"hello.example.com"},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": "/",
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": "service-name",
+						"port": int64(80),
+					},
+				},
+			},
+		},
+	}, route.Object["spec"])
+
+	conditions := environment.Integration.Status.Conditions
+	assert.Len(t, conditions, 1)
+	assert.Equal(t, corev1.ConditionTrue, conditions[0].Status)
+	assert.Equal(t, "service-name(hello.example.com/) -> service-name(http)", conditions[0].Message)
+}
+
+func TestApplyGatewayAPITraitWithHostTemplateDoesSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	environment.Integration.Namespace = "namespace"
+	environment.Platform = &v1.IntegrationPlatform{
+		Status: v1.IntegrationPlatformStatus{
+			IntegrationPlatformSpec: v1.IntegrationPlatformSpec{
+				Exposure: v1.IntegrationPlatformExposureSpec{
+					HostTemplate: "{name}.{namespace}.apps.example.com",
+				},
+			},
+		},
+	}
+
+	configured, err := gatewayAPITrait.Configure(environment)
+	assert.True(t, configured)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"integration-name.namespace.apps.example.com"}, gatewayAPITrait.Hostnames)
+
+	err = gatewayAPITrait.Apply(environment)
+	assert.Nil(t, err)
+
+	routes := gatewayAPIRoutes(environment)
+	assert.Len(t, routes, 1)
+	hostnames, _, _ := unstructured.NestedStringSlice(routes[0].Object, "spec", "hostnames")
+	assert.Equal(t, []string{"integration-name.namespace.apps.example.com"}, hostnames)
+}
+
+func TestApplyGatewayAPITraitWithGRPCDoesSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	gatewayAPITrait.GRPC = pointer.Bool(true)
+	gatewayAPITrait.Path = "/api"
+	service := environment.Resources.GetUserServiceForIntegration(environment.Integration)
+	service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+		Name: "grpc",
+		Port: 9090,
+	})
+
+	err := gatewayAPITrait.Apply(environment)
+	assert.Nil(t, err)
+
+	routes := gatewayAPIRoutes(environment)
+	assert.Len(t, routes, 2)
+	for _, route := range routes {
+		switch route.GetKind() {
+		case "HTTPRoute":
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			assert.Len(t, rules, 1)
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":  "PathPrefix",
+						"value": "/api",
+					},
+				},
+			}, rules[0].(map[string]interface{})["matches"])
+		case "GRPCRoute":
+			assert.Equal(t, grpcRouteAPIVersion, route.GetAPIVersion())
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{
+							"name": "service-name",
+							"port": int64(9090),
+						},
+					},
+				},
+			}, route.Object["spec"].(map[string]interface{})["rules"])
+		default:
+			t.Errorf("unexpected route kind %s", route.GetKind())
+		}
+	}
+
+	conditions := environment.Integration.Status.Conditions
+	assert.Len(t, conditions, 1)
+	assert.Equal(t, "service-name(*/api) -> service-name(http), service-name(*) -> service-name(grpc)", conditions[0].Message)
+}
+
+func TestApplyGatewayAPITraitWithGRPCWithoutPortDoesNotSucceed(t *testing.T) {
+	gatewayAPITrait, environment := createNominalGatewayAPITest()
+	gatewayAPITrait.GRPC = pointer.Bool(true)
+
+	err := gatewayAPITrait.Apply(environment)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "cannot apply gateway-api trait: no grpc port defined on service service-name, it can be exposed with the container trait ports", err.Error())
+}
+
+func gatewayAPIRoutes(e *Environment) []*unstructured.Unstructured {
+	routes := make([]*unstructured.Unstructured, 0)
+	for _, o := range e.Resources.Items() {
+		if u, ok := o.(*unstructured.Unstructured); ok {
+			routes = append(routes, u)
+		}
+	}
+	return routes
+}
+
+func createNominalGatewayAPITest() (*gatewayAPITrait, *Environment) {
+	trait, _ := newGatewayAPITrait().(*gatewayAPITrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.Gateway = "gateway"
+
+	environment := &Environment{
+		Catalog: NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "integration-name",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(
+			&corev1.Service{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Service",
+					APIVersion: "v1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service-name",
+					Namespace: "namespace",
+					Labels: map[string]string{
+						v1.IntegrationLabel:             "integration-name",
+						"camel.apache.org/service.type": v1.ServiceTypeUser,
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{
+							Name: defaultContainerPortName,
+							Port: defaultServicePort,
+						},
+					},
+					Selector: map[string]string{
+						v1.IntegrationLabel: "integration-name",
+					},
+				},
+			},
+		),
+	}
+
+	return trait, environment
+}
@@ -94,7 +94,7 @@ func (t *garbageCollectorTrait) Configure(e *Environment) (bool, error) {
 }
 
 func (t *garbageCollectorTrait) Apply(e *Environment) error {
-	if e.IntegrationInRunningPhases() && e.Integration.GetGeneration() > 1 {
+	if e.IntegrationInRunningPhases() && e.Integration.GetGeneration() > 1 && !e.IsReconcilePaused() {
 		// Register a post action that deletes the existing resources that are labelled
 		// with the previous integration generation(s).
 		// We make the assumption generation is a monotonically increasing strictly positive integer,
@@ -77,6 +77,20 @@ func TestApplyGarbageCollectorTraitDuringInitializationPhaseSkipPostActions(t *t
 	assert.Len(t, environment.PostActions, 0)
 }
 
+func TestApplyGarbageCollectorTraitWithReconcilePausedSkipPostActions(t *testing.T) {
+	gcTrait, environment := createNominalGarbageCollectorTest()
+	environment.Integration.Generation = 2
+	environment.Integration.Annotations = map[string]string{
+		v1.ReconcileAnnotation: v1.ReconcilePaused,
+	}
+
+	err := gcTrait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Len(t, environment.PostProcessors, 1)
+	assert.Len(t, environment.PostActions, 0)
+}
+
 func createNominalGarbageCollectorTest() (*garbageCollectorTrait, *Environment) {
 	trait, _ := newGarbageCollectorTrait().(*garbageCollectorTrait)
 	trait.Enabled = pointer.Bool(true)
@@ -20,6 +20,7 @@ package trait
 import (
 	"encoding/json"
 	"sort"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -36,6 +37,10 @@ const (
 
 // The health trait is responsible for configuring the health probes on the integration container.
 //
+// It can also enable the Camel supervising route controller, that restarts the routes whose consumer fails to start,
+// e.g., because a remote system is not available yet. Once the restart attempts of a route are exhausted, the route
+// is reported as unhealthy, so that the readiness probe fails, and the Integration Ready condition reports the route.
+//
 // It's disabled by default.
 //
 // +camel-k:trait=health.
@@ -71,6 +76,36 @@ type healthTrait struct {
 	ReadinessSuccessThreshold int32 `property:"readiness-success-threshold" json:"readinessSuccessThreshold,omitempty"`
 	// Minimum consecutive failures for the readiness probe to be considered failed after having succeeded.
 	ReadinessFailureThreshold int32 `property:"readiness-failure-threshold" json:"readinessFailureThreshold,omitempty"`
+
+	// Configures the startup probe for the integration container (default `false`).
+	// The liveness and readiness probes are disabled until the startup probe succeeds, so that slow starting
+	// integrations are not restarted by the liveness probe.
+	StartupProbeEnabled *bool `property:"startup-probe-enabled" json:"startupProbeEnabled,omitempty"`
+	// Scheme to use when connecting to the startup probe (default `HTTP`).
+	StartupScheme string `property:"startup-scheme" json:"startupScheme,omitempty"`
+	// The path of the startup probe (default `/q/health/live`).
+	StartupPath string `property:"startup-path" json:"startupPath,omitempty"`
+	// Number of seconds after the container has started before the startup probe is initiated.
+	StartupInitialDelay int32 `property:"startup-initial-delay" json:"startupInitialDelay,omitempty"`
+	// Number of seconds after which the startup probe times out.
+	StartupTimeout int32 `property:"startup-timeout" json:"startupTimeout,omitempty"`
+	// How often to perform the startup probe.
+	StartupPeriod int32 `property:"startup-period" json:"startupPeriod,omitempty"`
+	// Minimum consecutive failures for the startup probe to be considered failed, and the container restarted.
+	// The integration is given up to the failure threshold times the period to start.
+	StartupFailureThreshold int32 `property:"startup-failure-threshold" json:"startupFailureThreshold,omitempty"`
+
+	// Whether the routes that fail to start are restarted by the Camel supervising route controller (default `false`).
+	RouteSupervision *bool `property:"route-supervision" json:"routeSupervision,omitempty"`
+	// The delay, in milliseconds, before the routes are started by the supervising route controller.
+	RouteInitialDelay int64 `property:"route-initial-delay" json:"routeInitialDelay,omitempty"`
+	// The delay, in milliseconds, between the attempts to restart a route that fails to start.
+	RouteBackOffDelay int64 `property:"route-back-off-delay" json:"routeBackOffDelay,omitempty"`
+	// The maximum number of attempts to restart a route that fails to start, before giving up (default unlimited).
+	RouteBackOffMaxAttempts int64 `property:"route-back-off-max-attempts" json:"routeBackOffMaxAttempts,omitempty"`
+	// Whether a route is reported as unhealthy, and the readiness probe fails, once its restart attempts are
+	// exhausted (default `true`).
+	RouteUnhealthyOnExhausted *bool `property:"route-unhealthy-on-exhausted" json:"routeUnhealthyOnExhausted,omitempty"`
 }
 
 func newHealthTrait() Trait {
@@ -78,6 +113,8 @@ func newHealthTrait() Trait {
 		BaseTrait:       NewBaseTrait("health", 1700),
 		LivenessScheme:  string(corev1.URISchemeHTTP),
 		ReadinessScheme: string(corev1.URISchemeHTTP),
+		StartupScheme:   string(corev1.URISchemeHTTP),
+		StartupPath:     defaultLivenessProbePath,
 	}
 }
 
@@ -112,6 +149,11 @@ func (t *healthTrait) Configure(e *Environment) (bool, error) {
 	return true, nil
 }
 
+// RequiresTraits makes sure the integration container is configured before the probes are set on it.
+func (t *healthTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
 func (t *healthTrait) Apply(e *Environment) error {
 	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
 		if capability, ok := e.CamelCatalog.Runtime.Capabilities[v1.CapabilityHealth]; ok {
@@ -121,10 +163,14 @@ func (t *healthTrait) Apply(e *Environment) error {
 			// sort the dependencies to get always the same list if they don't change
 			sort.Strings(e.Integration.Status.Dependencies)
 		}
+		if pointer.BoolDeref(t.RouteSupervision, false) {
+			t.addRouteControllerProperties(e)
+		}
 		return nil
 	}
 
-	if !pointer.BoolDeref(t.LivenessProbeEnabled, false) && !pointer.BoolDeref(t.ReadinessProbeEnabled, true) {
+	if !pointer.BoolDeref(t.LivenessProbeEnabled, false) && !pointer.BoolDeref(t.ReadinessProbeEnabled, true) &&
+		!pointer.BoolDeref(t.StartupProbeEnabled, false) {
 		return nil
 	}
 
@@ -147,10 +193,43 @@ func (t *healthTrait) Apply(e *Environment) error {
 	if pointer.BoolDeref(t.ReadinessProbeEnabled, true) {
 		container.ReadinessProbe = t.newReadinessProbe(port, defaultReadinessProbePath)
 	}
+	if pointer.BoolDeref(t.StartupProbeEnabled, false) {
+		if e.GetTrait(knativeServiceTraitID) != nil {
+			// The Knative Serving webhook rejects the startup probes
+			t.L.ForIntegration(e.Integration).Info("The startup probe is not supported by Knative services")
+		} else {
+			container.StartupProbe = t.newStartupProbe(port, t.StartupPath)
+		}
+	}
 
 	return nil
 }
 
+// addRouteControllerProperties configures the Camel supervising route controller. The properties are added to the
+// Integration configuration, with a lower precedence than the user properties.
+func (t *healthTrait) addRouteControllerProperties(e *Environment) {
+	properties := map[string]string{
+		"camel.main.route-controller-supervise-enabled":      "true",
+		"camel.main.route-controller-unhealthy-on-exhausted": strconv.FormatBool(pointer.BoolDeref(t.RouteUnhealthyOnExhausted, true)),
+	}
+	if t.RouteInitialDelay > 0 {
+		properties["camel.main.route-controller-initial-delay"] = strconv.FormatInt(t.RouteInitialDelay, 10)
+	}
+	if t.RouteBackOffDelay > 0 {
+		properties["camel.main.route-controller-back-off-delay"] = strconv.FormatInt(t.RouteBackOffDelay, 10)
+	}
+	if t.RouteBackOffMaxAttempts > 0 {
+		properties["camel.main.route-controller-back-off-max-attempts"] = strconv.FormatInt(t.RouteBackOffMaxAttempts, 10)
+	}
+
+	for _, k := range util.SortedStringMapKeys(properties) {
+		e.Integration.Status.AddConfigurationsIfMissing(v1.ConfigurationSpec{
+			Type:  "property",
+			Value: k + "=" + properties[k],
+		})
+	}
+}
+
 func (t *healthTrait) newLivenessProbe(port *intstr.IntOrString, path string) *corev1.Probe {
 	p := corev1.Probe{
 		Handler: corev1.Handler{
@@ -173,6 +252,27 @@ func (t *healthTrait) newLivenessProbe(port *intstr.IntOrString, path string) *c
 	return &p
 }
 
+func (t *healthTrait) newStartupProbe(port *intstr.IntOrString, path string) *corev1.Probe {
+	p := corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   path,
+				Scheme: corev1.URIScheme(t.StartupScheme),
+			},
+		},
+		InitialDelaySeconds: t.StartupInitialDelay,
+		TimeoutSeconds:      t.StartupTimeout,
+		PeriodSeconds:       t.StartupPeriod,
+		FailureThreshold:    t.StartupFailureThreshold,
+	}
+
+	if port != nil {
+		p.Handler.HTTPGet.Port = *port
+	}
+
+	return &p
+}
+
 func (t *healthTrait) newReadinessProbe(port *intstr.IntOrString, path string) *corev1.Probe {
 	p := corev1.Probe{
 		Handler: corev1.Handler{
@@ -34,6 +34,12 @@ import (
 //
 // It's enabled by default whenever a Service is added to the integration (through the `service` trait).
 //
+// The host defaults to the one templated by the IntegrationPlatform `exposure` configuration, if any, in which case
+// the Ingress also reuses the wildcard certificate configured on the platform.
+//
+// When a cert-manager issuer is set, a certificate is requested for the host, and the Ingress is secured with it.
+// The certificate is renewed by cert-manager, without any change to the Ingress.
+//
 // +camel-k:trait=ingress.
 type ingressTrait struct {
 	BaseTrait `property:",squash"`
@@ -41,6 +47,12 @@ type ingressTrait struct {
 	Host string `property:"host" json:"host,omitempty"`
 	// To automatically add an ingress whenever the integration uses a HTTP endpoint consumer.
 	Auto *bool `property:"auto" json:"auto,omitempty"`
+	// The name of the cert-manager issuer the certificate of the host is requested to.
+	CertManagerIssuer string `property:"cert-manager-issuer" json:"certManagerIssuer,omitempty"`
+	// The kind of the cert-manager issuer, either `Issuer` or `ClusterIssuer` (default `Issuer`).
+	CertManagerIssuerKind string `property:"cert-manager-issuer-kind" json:"certManagerIssuerKind,omitempty"`
+
+	tls *networking.IngressTLS
 }
 
 func newIngressTrait() Trait {
@@ -70,6 +82,17 @@ func (t *ingressTrait) Configure(e *Environment) (bool, error) {
 		return false, nil
 	}
 
+	if t.Host == "" && e.Platform != nil {
+		exposure := e.Platform.Status.Exposure
+		t.Host = exposure.GetHost(e.Integration.Name, e.Integration.Namespace)
+		if t.Host != "" && exposure.TLS {
+			t.tls = &networking.IngressTLS{
+				Hosts:      []string{t.Host},
+				SecretName: exposure.TLSSecret,
+			}
+		}
+	}
+
 	if pointer.BoolDeref(t.Auto, true) {
 		hasService := e.Resources.GetUserServiceForIntegration(e.Integration) != nil
 		hasHost := t.Host != ""
@@ -98,6 +121,16 @@ func (t *ingressTrait) Configure(e *Environment) (bool, error) {
 		return false, errors.New("cannot Apply ingress trait: no host defined")
 	}
 
+	if t.CertManagerIssuer != "" {
+		if err := validateCertManagerIssuerKind("ingress", t.CertManagerIssuerKind); err != nil {
+			return false, err
+		}
+		t.tls = &networking.IngressTLS{
+			Hosts:      []string{t.Host},
+			SecretName: certificateSecretName(e.Integration),
+		}
+	}
+
 	return true, nil
 }
 
@@ -133,8 +166,16 @@ func (t *ingressTrait) Apply(e *Environment) error {
 		},
 	}
 
+	if t.tls != nil {
+		ingress.Spec.TLS = []networking.IngressTLS{*t.tls}
+	}
+
 	e.Resources.Add(&ingress)
 
+	if t.CertManagerIssuer != "" {
+		e.Resources.Add(newCertificate(e.Integration, service.Namespace, t.Host, t.CertManagerIssuer, t.CertManagerIssuerKind))
+	}
+
 	message := fmt.Sprintf("%s(%s) -> %s(%s)",
 		ingress.Name,
 		t.Host,
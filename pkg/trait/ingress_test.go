@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
@@ -153,6 +154,87 @@ func TestApplyIngressTraitDoesSucceed(t *testing.T) {
 	assert.Equal(t, "service-name(hostname) -> service-name(http)", conditions[0].Message)
 }
 
+func TestApplyIngressTraitWithHostTemplateDoesSucceed(t *testing.T) {
+	ingressTrait, environment := createNominalIngressTest()
+	ingressTrait.Auto = nil
+	ingressTrait.Host = ""
+	environment.Integration.Namespace = "namespace"
+	environment.Platform = &v1.IntegrationPlatform{
+		Status: v1.IntegrationPlatformStatus{
+			IntegrationPlatformSpec: v1.IntegrationPlatformSpec{
+				Exposure: v1.IntegrationPlatformExposureSpec{
+					HostTemplate: "{name}.{namespace}.apps.example.com",
+					TLS:          true,
+					TLSSecret:    "wildcard-tls",
+				},
+			},
+		},
+	}
+
+	configured, err := ingressTrait.Configure(environment)
+
+	assert.True(t, configured)
+	assert.Nil(t, err)
+	assert.Equal(t, "integration-name.namespace.apps.example.com", ingressTrait.Host)
+
+	err = ingressTrait.Apply(environment)
+
+	assert.Nil(t, err)
+	environment.Resources.Visit(func(resource runtime.Object) {
+		if ingress, ok := resource.(*networking.Ingress); ok {
+			assert.Len(t, ingress.Spec.Rules, 1)
+			assert.Equal(t, "integration-name.namespace.apps.example.com", ingress.Spec.Rules[0].Host)
+			assert.Len(t, ingress.Spec.TLS, 1)
+			assert.Equal(t, []string{"integration-name.namespace.apps.example.com"}, ingress.Spec.TLS[0].Hosts)
+			assert.Equal(t, "wildcard-tls", ingress.Spec.TLS[0].SecretName)
+		}
+	})
+}
+
+func TestApplyIngressTraitWithCertManagerDoesSucceed(t *testing.T) {
+	ingressTrait, environment := createNominalIngressTest()
+	ingressTrait.CertManagerIssuer = "letsencrypt"
+	ingressTrait.CertManagerIssuerKind = "ClusterIssuer"
+
+	configured, err := ingressTrait.Configure(environment)
+	assert.True(t, configured)
+	assert.Nil(t, err)
+
+	err = ingressTrait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Len(t, environment.Resources.Items(), 3)
+	environment.Resources.Visit(func(resource runtime.Object) {
+		switch r := resource.(type) {
+		case *networking.Ingress:
+			assert.Len(t, r.Spec.TLS, 1)
+			assert.Equal(t, []string{"hostname"}, r.Spec.TLS[0].Hosts)
+			assert.Equal(t, "integration-name-tls", r.Spec.TLS[0].SecretName)
+		case *unstructured.Unstructured:
+			assert.Equal(t, "Certificate", r.GetKind())
+			assert.Equal(t, "integration-name-tls", r.GetName())
+			assert.Equal(t, "namespace", r.GetNamespace())
+			secretName, _, _ := unstructured.NestedString(r.Object, "spec", "secretName")
+			assert.Equal(t, "integration-name-tls", secretName)
+			dnsNames, _, _ := unstructured.NestedStringSlice(r.Object, "spec", "dnsNames")
+			assert.Equal(t, []string{"hostname"}, dnsNames)
+			issuer, _, _ := unstructured.NestedStringMap(r.Object, "spec", "issuerRef")
+			assert.Equal(t, map[string]string{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuer)
+		}
+	})
+}
+
+func TestConfigureIngressTraitWithWrongCertManagerIssuerKindDoesNotSucceed(t *testing.T) {
+	ingressTrait, environment := createNominalIngressTest()
+	ingressTrait.CertManagerIssuer = "letsencrypt"
+	ingressTrait.CertManagerIssuerKind = "Vault"
+
+	configured, err := ingressTrait.Configure(environment)
+
+	assert.False(t, configured)
+	assert.NotNil(t, err)
+}
+
 func createNominalIngressTest() (*ingressTrait, *Environment) {
 	trait, _ := newIngressTrait().(*ingressTrait)
 	trait.Enabled = pointer.Bool(true)
@@ -18,7 +18,9 @@ limitations under the License.
 package trait
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -27,6 +29,7 @@ import (
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util"
 	"github.com/apache/camel-k/pkg/util/dsl"
+	"github.com/apache/camel-k/pkg/util/maven"
 )
 
 const flowsInternalSourceName = "camel-k-embedded-flow.yaml"
@@ -75,6 +78,9 @@ func (t *initTrait) Apply(e *Environment) error {
 			// add runtime specific dependencies
 			for _, capability := range e.Integration.Status.Capabilities {
 				for _, dependency := range e.CamelCatalog.Runtime.CapabilityDependencies(capability) {
+					if err := checkCapabilityDependency(e.Integration, capability, dependency); err != nil {
+						return &ConfigurationError{Trait: "dependencies", Err: err}
+					}
 					util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, dependency.GetDependencyID())
 				}
 			}
@@ -90,3 +96,24 @@ func (t *initTrait) Apply(e *Environment) error {
 
 	return nil
 }
+
+// checkCapabilityDependency returns an error if a dependency provided by the user pins a version of an artifact
+// that a required capability has been resolved to, as it would conflict with the version managed by the runtime.
+func checkCapabilityDependency(it *v1.Integration, capability string, artifact v1.MavenArtifact) error {
+	for _, d := range it.Spec.Dependencies {
+		if !strings.HasPrefix(d, "mvn:") {
+			continue
+		}
+		dependency, err := maven.ParseGAV(strings.TrimPrefix(d, "mvn:"))
+		if err != nil {
+			// Invalid dependencies are reported when building the kit
+			continue
+		}
+		if dependency.GroupID == artifact.GroupID && dependency.ArtifactID == artifact.ArtifactID &&
+			dependency.Version != "" && dependency.Version != artifact.Version {
+			return fmt.Errorf("dependency %s conflicts with the %s capability, which is resolved to %s",
+				d, capability, artifact.GetDependencyID())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+const initContainersTraitID = "init-containers"
+
+// The Init Containers trait adds init containers to the Integration Pod, that run to completion before the integration
+// container starts, e.g., to wait for a database to accept connections, or to fetch certificates into a shared volume.
+//
+// Each init container supports the `name`, `image`, `image-pull-policy`, `command`, `args`, `env` (`NAME=value`),
+// `mounts` (`volume:/path`), `request-cpu`, `request-memory`, `limit-cpu` and `limit-memory` properties, for example:
+//
+// - `init-containers.containers[0].name=fetch-certs`
+// - `init-containers.containers[0].image=my/fetcher:1.0`
+// - `init-containers.containers[0].mounts=certs:/etc/certs`
+//
+// A mounted volume that is not declared by the Pod is created as an `emptyDir` volume, so that it can be shared
+// with the other init containers, and with the sidecars configured by the `container` trait. The init containers run in the declared order, after
+// the ones added by the `mount` and `wait-for` traits, and before the database migrations of the `migration` trait.
+//
+// NOTE: With Knative, the `kubernetes.podspec-init-containers` feature flag must be enabled in the
+// `config-features` ConfigMap of Knative Serving.
+//
+// It's disabled by default.
+//
+// +camel-k:trait=init-containers.
+type initContainersTrait struct {
+	BaseTrait `property:",squash"`
+	// The init containers, e.g. `containers[0].name=wait-for-db`, `containers[0].image=busybox`,
+	// `containers[0].command=sh`, `containers[0].args=-c`, `containers[0].args=until nc -z postgres 5432; do sleep 2; done`.
+	Containers []containerSidecar `property:"containers" json:"containers,omitempty"`
+}
+
+func newInitContainersTrait() Trait {
+	return &initContainersTrait{
+		BaseTrait: NewBaseTrait(initContainersTraitID, 1618),
+	}
+}
+
+func (t *initContainersTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if len(t.Containers) == 0 {
+		return false, &ConfigurationError{Trait: t.ID(), Err: errors.New("at least one init container is required")}
+	}
+	names := make(map[string]bool)
+	for i, container := range t.Containers {
+		if err := validateSidecar("init container", i, container, names); err != nil {
+			return false, &ConfigurationError{Trait: t.ID(), Err: err}
+		}
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+// RequiresTraits makes sure the Pod template is created before the init containers are added to it.
+func (t *initContainersTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
+func (t *initContainersTrait) Apply(e *Environment) error {
+	var err error
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		if err != nil {
+			return
+		}
+		for _, container := range t.Containers {
+			if hasContainer(spec, container.Name) {
+				err = fmt.Errorf("init container %s: the name is already used by another container", container.Name)
+				return
+			}
+			spec.InitContainers = append(spec.InitContainers, newSidecarContainer(spec, container))
+		}
+	})
+
+	return err
+}
+
+// hasContainer returns whether the Pod declares a container, or an init container, with the given name.
+func hasContainer(spec *corev1.PodSpec, name string) bool {
+	for _, containers := range [][]corev1.Container{spec.Containers, spec.InitContainers} {
+		for _, container := range containers {
+			if container.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+func TestConfigureInitContainersTraitDisabled(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	initContainersTrait := createNominalInitContainersTrait()
+	initContainersTrait.Enabled = nil
+
+	configured, err := initContainersTrait.Configure(environment)
+
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureInitContainersTraitWithInvalidContainers(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+
+	tests := []struct {
+		name       string
+		containers []containerSidecar
+		err        string
+	}{
+		{
+			name: "missing containers",
+			err:  "invalid init-containers trait configuration: at least one init container is required",
+		},
+		{
+			name:       "missing image",
+			containers: []containerSidecar{{Name: "fetch-certs"}},
+			err:        "invalid init-containers trait configuration: init container fetch-certs: an image is required",
+		},
+		{
+			name:       "duplicate name",
+			containers: []containerSidecar{{Name: "fetch-certs", Image: "my/fetcher"}, {Name: "fetch-certs", Image: "my/fetcher"}},
+			err:        "invalid init-containers trait configuration: init container fetch-certs: the name is already used by another container",
+		},
+		{
+			name:       "invalid mount",
+			containers: []containerSidecar{{Name: "fetch-certs", Image: "my/fetcher", Mounts: []string{"/etc/certs"}}},
+			err:        `invalid init-containers trait configuration: init container fetch-certs: invalid mount "/etc/certs", expected volume:/path`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			initContainersTrait := createNominalInitContainersTrait()
+			initContainersTrait.Containers = test.containers
+
+			configured, err := initContainersTrait.Configure(environment)
+
+			assert.EqualError(t, err, test.err)
+			assert.False(t, configured)
+		})
+	}
+}
+
+func TestApplyInitContainersTrait(t *testing.T) {
+	initContainersTrait := createNominalInitContainersTrait()
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	testApplyInitContainersTrait(t, initContainersTrait, environment, &deployment.Spec.Template.Spec)
+
+	environment, knativeService := createNominalKnativeServiceTraitTest()
+	testApplyInitContainersTrait(t, initContainersTrait, environment, &knativeService.Spec.Template.Spec.PodSpec)
+
+	environment, cronJob := createNominalCronJobTraitTest()
+	testApplyInitContainersTrait(t, initContainersTrait, environment, &cronJob.Spec.JobTemplate.Spec.Template.Spec)
+}
+
+func testApplyInitContainersTrait(t *testing.T, trait *initContainersTrait, environment *Environment, spec *corev1.PodSpec) {
+	t.Helper()
+
+	spec.InitContainers = []corev1.Container{{Name: waitForContainerName}}
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(environment))
+
+	assert.Len(t, spec.InitContainers, 3)
+	assert.Equal(t, waitForContainerName, spec.InitContainers[0].Name)
+
+	container := spec.InitContainers[1]
+	assert.Equal(t, "wait-for-db", container.Name)
+	assert.Equal(t, "busybox", container.Image)
+	assert.Equal(t, []string{"sh", "-c"}, container.Command)
+	assert.Equal(t, []string{"until nc -z postgres 5432; do sleep 2; done"}, container.Args)
+
+	container = spec.InitContainers[2]
+	assert.Equal(t, "fetch-certs", container.Name)
+	assert.Equal(t, corev1.PullAlways, container.ImagePullPolicy)
+	assert.Equal(t, "vault:8200", envvar.Get(container.Env, "VAULT_ADDR").Value)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "certs", MountPath: "/etc/certs"}}, container.VolumeMounts)
+	assert.Equal(t, resource.MustParse("64Mi"), container.Resources.Limits[corev1.ResourceMemory])
+
+	assert.Len(t, spec.Volumes, 1)
+	assert.Equal(t, "certs", spec.Volumes[0].Name)
+	assert.NotNil(t, spec.Volumes[0].EmptyDir)
+}
+
+func TestApplyInitContainersTraitWithConflictingName(t *testing.T) {
+	environment, deployment := createNominalDeploymentTraitTest()
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "wait-for-db"}}
+	initContainersTrait := createNominalInitContainersTrait()
+
+	err := initContainersTrait.Apply(environment)
+
+	assert.EqualError(t, err, "init container wait-for-db: the name is already used by another container")
+	assert.Empty(t, deployment.Spec.Template.Spec.InitContainers)
+}
+
+func createNominalInitContainersTrait() *initContainersTrait {
+	initContainersTrait, _ := newInitContainersTrait().(*initContainersTrait)
+	initContainersTrait.Enabled = pointer.Bool(true)
+	initContainersTrait.Containers = []containerSidecar{
+		{
+			Name:    "wait-for-db",
+			Image:   "busybox",
+			Command: []string{"sh", "-c"},
+			Args:    []string{"until nc -z postgres 5432; do sleep 2; done"},
+		},
+		{
+			Name:            "fetch-certs",
+			Image:           "my/fetcher:1.0",
+			ImagePullPolicy: corev1.PullAlways,
+			Env:             []string{"VAULT_ADDR=vault:8200"},
+			Mounts:          []string{"certs:/etc/certs"},
+			LimitMemory:     "64Mi",
+		},
+	}
+
+	return initContainersTrait
+}
@@ -0,0 +1,173 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+// The cluster facts that trait configuration and property values can refer to, with the `{{camel-k:<fact>}}` syntax.
+const (
+	factNamespace           = "namespace"
+	factClusterDomain       = "cluster.domain"
+	factRegistryAddress     = "registry.address"
+	factOpenShiftAppsDomain = "openshift.apps-domain"
+)
+
+const factPrefix = "{{camel-k:"
+
+var factRegexp = regexp.MustCompile(`\{\{camel-k:([a-zA-Z0-9.-]+)\}\}`)
+
+// interpolate replaces the references to cluster facts in the given value. It fails if a fact is unknown,
+// or cannot be resolved, rather than letting the Integration start with an unresolved value.
+func (e *Environment) interpolate(value string) (string, error) {
+	if !strings.Contains(value, factPrefix) {
+		return value, nil
+	}
+
+	var err error
+	result := factRegexp.ReplaceAllStringFunc(value, func(match string) string {
+		if err != nil {
+			return match
+		}
+		var fact string
+		fact, err = e.resolveFact(factRegexp.FindStringSubmatch(match)[1])
+		return fact
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// interpolateTraitSpec replaces the references to cluster facts in the given trait configuration.
+func (e *Environment) interpolateTraitSpec(in *v1.TraitSpec) error {
+	if !strings.Contains(string(in.Configuration.RawMessage), factPrefix) {
+		return nil
+	}
+
+	var config interface{}
+	if err := json.Unmarshal(in.Configuration.RawMessage, &config); err != nil {
+		return err
+	}
+	config, err := e.interpolateAll(config)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	in.Configuration.RawMessage = data
+
+	return nil
+}
+
+// interpolateAll replaces the references to cluster facts in the strings contained in the given value, as
+// decoded from JSON.
+func (e *Environment) interpolateAll(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return e.interpolate(v)
+	case []interface{}:
+		for i := range v {
+			item, err := e.interpolateAll(v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = item
+		}
+	case map[string]interface{}:
+		for k := range v {
+			item, err := e.interpolateAll(v[k])
+			if err != nil {
+				return nil, err
+			}
+			v[k] = item
+		}
+	}
+	return value, nil
+}
+
+func (e *Environment) resolveFact(name string) (string, error) {
+	if value, ok := e.facts[name]; ok {
+		return value, nil
+	}
+
+	var value string
+	switch name {
+	case factNamespace:
+		if e.Integration != nil {
+			value = e.Integration.Namespace
+		} else if e.IntegrationKit != nil {
+			value = e.IntegrationKit.Namespace
+		}
+	case factClusterDomain:
+		value = kubernetes.GetClusterDomain()
+	case factRegistryAddress:
+		if e.Platform != nil {
+			value = e.Platform.Status.Build.Registry.Address
+		}
+	case factOpenShiftAppsDomain:
+		domain, err := e.getOpenShiftAppsDomain()
+		if err != nil {
+			return "", err
+		}
+		value = domain
+	default:
+		return "", fmt.Errorf("unknown cluster fact %q, must be one of %s, %s, %s or %s", name,
+			factNamespace, factClusterDomain, factRegistryAddress, factOpenShiftAppsDomain)
+	}
+	if value == "" {
+		return "", fmt.Errorf("cannot resolve cluster fact %q", name)
+	}
+
+	if e.facts == nil {
+		e.facts = make(map[string]string)
+	}
+	e.facts[name] = value
+
+	return value, nil
+}
+
+// getOpenShiftAppsDomain returns the default domain of the OpenShift Routes, from the cluster Ingress configuration.
+func (e *Environment) getOpenShiftAppsDomain() (string, error) {
+	if e.Platform == nil || e.Platform.Status.Cluster != v1.IntegrationPlatformClusterOpenShift {
+		return "", fmt.Errorf("cannot resolve cluster fact %q: the cluster is not OpenShift", factOpenShiftAppsDomain)
+	}
+	if e.Client == nil {
+		return "", fmt.Errorf("cannot resolve cluster fact %q: no client available", factOpenShiftAppsDomain)
+	}
+
+	ingress := configv1.Ingress{}
+	if err := e.Client.Get(e.Ctx, ctrl.ObjectKey{Name: "cluster"}, &ingress); err != nil {
+		return "", fmt.Errorf("cannot resolve cluster fact %q: %w", factOpenShiftAppsDomain, err)
+	}
+
+	return ingress.Spec.Domain, nil
+}
@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func createInterpolationTestEnvironment() *Environment {
+	return &Environment{
+		Catalog: NewCatalog(nil),
+		Platform: &v1.IntegrationPlatform{
+			Status: v1.IntegrationPlatformStatus{
+				IntegrationPlatformSpec: v1.IntegrationPlatformSpec{
+					Cluster: v1.IntegrationPlatformClusterKubernetes,
+					Build: v1.IntegrationPlatformBuildSpec{
+						Registry: v1.RegistrySpec{
+							Address: "registry.example.com:5000",
+						},
+					},
+				},
+			},
+		},
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-it",
+				Namespace: "ns",
+			},
+		},
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	e := createInterpolationTestEnvironment()
+
+	value, err := e.interpolate("http://my-svc.{{camel-k:namespace}}.svc.{{camel-k:cluster.domain}}:8080")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://my-svc.ns.svc."+kubernetes.GetClusterDomain()+":8080", value)
+
+	value, err = e.interpolate("{{camel-k:registry.address}}/ns/my-image")
+	assert.Nil(t, err)
+	assert.Equal(t, "registry.example.com:5000/ns/my-image", value)
+
+	// Camel property placeholders are left untouched
+	value, err = e.interpolate("{{my.property}}")
+	assert.Nil(t, err)
+	assert.Equal(t, "{{my.property}}", value)
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	e := createInterpolationTestEnvironment()
+
+	_, err := e.interpolate("{{camel-k:unknown}}")
+	assert.NotNil(t, err)
+
+	// Not an OpenShift cluster
+	_, err = e.interpolate("{{camel-k:openshift.apps-domain}}")
+	assert.NotNil(t, err)
+
+	e.Platform.Status.Build.Registry.Address = ""
+	_, err = e.interpolate("{{camel-k:registry.address}}/my-image")
+	assert.NotNil(t, err)
+}
+
+func TestConfigureTraitsWithClusterFacts(t *testing.T) {
+	e := createInterpolationTestEnvironment()
+	e.Integration.Annotations = map[string]string{
+		v1.TraitAnnotationPrefix + "route.host": "my-it-{{camel-k:namespace}}.apps.example.com",
+	}
+	e.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"container": test.TraitSpecFromMap(t, map[string]interface{}{
+			"image": "{{camel-k:registry.address}}/{{camel-k:namespace}}/my-image",
+		}),
+	}
+
+	err := e.Catalog.configure(e)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "my-it-ns.apps.example.com", e.Catalog.GetTrait("route").(*routeTrait).Host)
+	assert.Equal(t, "registry.example.com:5000/ns/my-image", e.Catalog.GetTrait("container").(*containerTrait).Image)
+	// The Integration is not changed
+	assert.Contains(t, string(e.Integration.Spec.Traits["container"].Configuration.RawMessage), "{{camel-k:registry.address}}")
+}
+
+func TestConfigureTraitsWithUnknownClusterFact(t *testing.T) {
+	e := createInterpolationTestEnvironment()
+	e.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"container": test.TraitSpecFromMap(t, map[string]interface{}{
+			"image": "{{camel-k:registry}}/my-image",
+		}),
+	}
+
+	err := e.Catalog.configure(e)
+	assert.NotNil(t, err)
+}
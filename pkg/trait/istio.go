@@ -18,17 +18,33 @@ limitations under the License.
 package trait
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/utils/pointer"
 
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/istio"
 )
 
 // The Istio trait allows configuring properties related to the Istio service mesh,
 // such as sidecar injection and outbound IP ranges.
 //
+// It can also generate the VirtualService, DestinationRule and PeerAuthentication resources for the Integration.
+// The VirtualService and the DestinationRule apply to the Service exposing the Integration, when it is deployed as a Deployment.
+// The VirtualService can shift a share of the traffic to the Services of other Integrations, e.g., to progressively roll out
+// a new version of the Integration deployed alongside, under another name.
+//
+// When the namespace of the Integration is enrolled into the Istio ambient mode, i.e., it is labelled with
+// `istio.io/dataplane-mode=ambient`, the trait configures the Integration Pods for the sidecar-less data plane.
+//
 // +camel-k:trait=istio.
 type istioTrait struct {
 	BaseTrait `property:",squash"`
@@ -36,11 +52,40 @@ type istioTrait struct {
 	Allow string `property:"allow" json:"allow,omitempty"`
 	// Forces the value for labels `sidecar.istio.io/inject`. By default the label is set to `true` on deployment and not set on Knative Service.
 	Inject *bool `property:"inject" json:"inject,omitempty"`
+	// Generates a VirtualService routing the traffic addressed to the Integration Service.
+	VirtualService *bool `property:"virtual-service" json:"virtualService,omitempty"`
+	// Generates a DestinationRule for the traffic addressed to the Integration Service.
+	DestinationRule *bool `property:"destination-rule" json:"destinationRule,omitempty"`
+	// The load balancing algorithm set in the DestinationRule, one of `ROUND_ROBIN`, `LEAST_REQUEST`, `RANDOM` or `PASSTHROUGH`.
+	LoadBalancer string `property:"load-balancer" json:"loadBalancer,omitempty"`
+	// Generates a PeerAuthentication with the given mutual TLS mode for the Integration Pods, one of `STRICT`, `PERMISSIVE` or `DISABLE`.
+	PeerAuthentication string `property:"peer-authentication" json:"peerAuthentication,omitempty"`
+	// Shifts a share of the traffic addressed to the Integration Service to the Services of other Integrations,
+	// in the form of `<integration>:<weight>` entries, e.g., `orders-v2:10` routes 10% of the traffic to the `orders-v2` Integration,
+	// and the remaining 90% to the Integration. The weights must not sum up to more than 100.
+	// It implies the generation of the VirtualService.
+	TrafficShift []string `property:"traffic-shift" json:"trafficShift,omitempty"`
+	// The data plane mode of the mesh, one of `sidecar` or `ambient`.
+	// It is detected from the `istio.io/dataplane-mode` label of the Integration namespace by default.
+	Mode string `property:"mode" json:"mode,omitempty"`
+	// The waypoint proxy the Integration Service uses in ambient mode, required by the VirtualService and the traffic shifting.
+	Waypoint string `property:"waypoint" json:"waypoint,omitempty"`
+}
+
+type istioTrafficShift struct {
+	integration string
+	weight      int32
 }
 
 const (
 	istioSidecarInjectAnnotation    = "sidecar.istio.io/inject"
 	istioOutboundIPRangesAnnotation = "traffic.sidecar.istio.io/includeOutboundIPRanges"
+	istioRewriteProbesAnnotation    = "sidecar.istio.io/rewriteAppHTTPProbers"
+	istioDataplaneModeLabel         = "istio.io/dataplane-mode"
+	istioUseWaypointLabel           = "istio.io/use-waypoint"
+
+	istioModeSidecar = "sidecar"
+	istioModeAmbient = "ambient"
 )
 
 func newIstioTrait() Trait {
@@ -51,15 +96,80 @@ func newIstioTrait() Trait {
 }
 
 func (t *istioTrait) Configure(e *Environment) (bool, error) {
-	if pointer.BoolDeref(t.Enabled, false) {
-		return e.IntegrationInRunningPhases(), nil
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	switch t.LoadBalancer {
+	case "", "ROUND_ROBIN", "LEAST_REQUEST", "RANDOM", "PASSTHROUGH":
+	default:
+		return false, fmt.Errorf("unsupported load balancer %q, must be one of ROUND_ROBIN, LEAST_REQUEST, RANDOM or PASSTHROUGH", t.LoadBalancer)
+	}
+
+	switch t.PeerAuthentication {
+	case "", "STRICT", "PERMISSIVE", "DISABLE":
+	default:
+		return false, fmt.Errorf("unsupported mutual TLS mode %q, must be one of STRICT, PERMISSIVE or DISABLE", t.PeerAuthentication)
+	}
+
+	if _, err := t.parseTrafficShift(); err != nil {
+		return false, err
+	}
+
+	if !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	switch t.Mode {
+	case "":
+		mode, err := t.detectMode(e)
+		if err != nil {
+			return false, err
+		}
+		t.Mode = mode
+	case istioModeSidecar, istioModeAmbient:
+	default:
+		return false, fmt.Errorf("unsupported data plane mode %q, must be one of sidecar or ambient", t.Mode)
 	}
 
-	return false, nil
+	if t.Mode == istioModeAmbient && pointer.BoolDeref(t.Inject, false) {
+		return false, fmt.Errorf("the sidecar cannot be injected in ambient mode")
+	}
+
+	return true, nil
+}
+
+// detectMode returns the data plane mode the Integration namespace is enrolled into,
+// falling back to the sidecar mode when the namespace cannot be read.
+func (t *istioTrait) detectMode(e *Environment) (string, error) {
+	if e.Client == nil {
+		return istioModeSidecar, nil
+	}
+
+	ns := corev1.Namespace{}
+	if err := e.Client.Get(e.Ctx, ctrl.ObjectKey{Name: e.Integration.Namespace}, &ns); err != nil {
+		if k8serrors.IsForbidden(err) || k8serrors.IsNotFound(err) {
+			t.L.ForIntegration(e.Integration).Debugf("Cannot read namespace %s, assuming the sidecar data plane mode: %v", e.Integration.Namespace, err)
+			return istioModeSidecar, nil
+		}
+		return "", err
+	}
+	if ns.Labels[istioDataplaneModeLabel] == istioModeAmbient {
+		return istioModeAmbient, nil
+	}
+
+	return istioModeSidecar, nil
 }
 
 func (t *istioTrait) Apply(e *Environment) error {
-	if t.Allow != "" {
+	if t.Mode == istioModeAmbient {
+		e.Resources.VisitDeployment(func(d *appsv1.Deployment) {
+			d.Spec.Template.Labels, d.Spec.Template.Annotations = t.enrollAmbient(d.Spec.Template.Labels, d.Spec.Template.Annotations)
+		})
+		e.Resources.VisitKnativeConfigurationSpec(func(cs *servingv1.ConfigurationSpec) {
+			cs.Template.Labels, cs.Template.Annotations = t.enrollAmbient(cs.Template.Labels, cs.Template.Annotations)
+		})
+	} else if t.Allow != "" {
 		e.Resources.VisitDeployment(func(d *appsv1.Deployment) {
 			d.Spec.Template.Annotations = t.injectIstioAnnotation(d.Spec.Template.Annotations, true)
 		})
@@ -67,9 +177,142 @@ func (t *istioTrait) Apply(e *Environment) error {
 			cs.Template.Annotations = t.injectIstioAnnotation(cs.Template.Annotations, false)
 		})
 	}
+
+	if t.PeerAuthentication != "" {
+		pa := istio.NewPeerAuthentication(e.Integration.Namespace, e.Integration.Name)
+		pa.Labels = map[string]string{
+			v1.IntegrationLabel: e.Integration.Name,
+		}
+		pa.Spec = istio.PeerAuthenticationSpec{
+			Selector: &istio.WorkloadSelector{
+				MatchLabels: map[string]string{
+					v1.IntegrationLabel: e.Integration.Name,
+				},
+			},
+			MTLS: &istio.PeerAuthenticationMutualTLS{
+				Mode: t.PeerAuthentication,
+			},
+		}
+		e.Resources.Add(&pa)
+	}
+
+	shifts, err := t.parseTrafficShift()
+	if err != nil {
+		return err
+	}
+	virtualService := pointer.BoolDeref(t.VirtualService, false) || len(shifts) > 0
+	destinationRule := pointer.BoolDeref(t.DestinationRule, false)
+	if !virtualService && !destinationRule {
+		return nil
+	}
+
+	service := e.Resources.GetServiceForIntegration(e.Integration)
+	if service == nil {
+		t.L.ForIntegration(e.Integration).Info("No Service exposes the Integration, the VirtualService and DestinationRule are not generated")
+		return nil
+	}
+
+	if t.Mode == istioModeAmbient {
+		if t.Waypoint != "" {
+			if service.Labels == nil {
+				service.Labels = make(map[string]string)
+			}
+			service.Labels[istioUseWaypointLabel] = t.Waypoint
+		} else if virtualService {
+			t.L.ForIntegration(e.Integration).Info("No waypoint is configured, the VirtualService is not enforced in ambient mode")
+		}
+	}
+
+	if virtualService {
+		vs := istio.NewVirtualService(e.Integration.Namespace, e.Integration.Name)
+		vs.Labels = map[string]string{
+			v1.IntegrationLabel: e.Integration.Name,
+		}
+		vs.Spec.Hosts = []string{service.Name}
+		vs.Spec.HTTP = []istio.HTTPRoute{
+			{
+				Route: t.routeDestinations(service.Name, shifts),
+			},
+		}
+		e.Resources.Add(&vs)
+	}
+
+	if destinationRule {
+		dr := istio.NewDestinationRule(e.Integration.Namespace, e.Integration.Name)
+		dr.Labels = map[string]string{
+			v1.IntegrationLabel: e.Integration.Name,
+		}
+		dr.Spec.Host = service.Name
+		// In ambient mode, mutual TLS is transparently originated by the node proxies
+		mutualTLS := t.PeerAuthentication == "STRICT" && t.Mode != istioModeAmbient
+		if t.LoadBalancer != "" || mutualTLS {
+			dr.Spec.TrafficPolicy = &istio.TrafficPolicy{}
+			if t.LoadBalancer != "" {
+				dr.Spec.TrafficPolicy.LoadBalancer = &istio.LoadBalancerSettings{Simple: t.LoadBalancer}
+			}
+			if mutualTLS {
+				// Make sure the clients originate mutual TLS connections to the Integration
+				dr.Spec.TrafficPolicy.TLS = &istio.ClientTLSSettings{Mode: "ISTIO_MUTUAL"}
+			}
+		}
+		e.Resources.Add(&dr)
+	}
+
 	return nil
 }
 
+func (t *istioTrait) routeDestinations(host string, shifts []istioTrafficShift) []istio.HTTPRouteDestination {
+	if len(shifts) == 0 {
+		return []istio.HTTPRouteDestination{
+			{
+				Destination: istio.Destination{Host: host},
+			},
+		}
+	}
+
+	remaining := int32(100)
+	destinations := make([]istio.HTTPRouteDestination, 0, len(shifts)+1)
+	for _, shift := range shifts {
+		remaining -= shift.weight
+		destinations = append(destinations, istio.HTTPRouteDestination{
+			Destination: istio.Destination{Host: shift.integration},
+			Weight:      shift.weight,
+		})
+	}
+
+	return append([]istio.HTTPRouteDestination{
+		{
+			Destination: istio.Destination{Host: host},
+			Weight:      remaining,
+		},
+	}, destinations...)
+}
+
+func (t *istioTrait) parseTrafficShift() ([]istioTrafficShift, error) {
+	shifts := make([]istioTrafficShift, 0, len(t.TrafficShift))
+	total := 0
+	for _, s := range t.TrafficShift {
+		parts := strings.Split(s, ":")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid traffic shift %q, must be in the form <integration>:<weight>", s)
+		}
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight < 0 || weight > 100 {
+			return nil, fmt.Errorf("invalid traffic shift %q, the weight must be a number between 0 and 100", s)
+		}
+		total += weight
+		shifts = append(shifts, istioTrafficShift{
+			integration: parts[0],
+			weight:      int32(weight),
+		})
+	}
+	if total > 100 {
+		return nil, fmt.Errorf("invalid traffic shift, the weights sum up to %d, which is more than 100", total)
+	}
+
+	return shifts, nil
+}
+
 func (t *istioTrait) injectIstioAnnotation(annotations map[string]string, includeInject bool) map[string]string {
 	if annotations == nil {
 		annotations = make(map[string]string)
@@ -81,5 +324,24 @@ func (t *istioTrait) injectIstioAnnotation(annotations map[string]string, includ
 	if t.Inject != nil {
 		annotations[istioSidecarInjectAnnotation] = strconv.FormatBool(*t.Inject)
 	}
+	if t.PeerAuthentication == "STRICT" {
+		// The kubelet probes are plain text, so they have to go through the sidecar
+		annotations[istioRewriteProbesAnnotation] = True
+	}
 	return annotations
 }
+
+func (t *istioTrait) enrollAmbient(labels map[string]string, annotations map[string]string) (map[string]string, map[string]string) {
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[istioDataplaneModeLabel] = istioModeAmbient
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	// The Pods must not get a sidecar if the namespace is also enabled for injection,
+	// while the probes reach the application directly, as ztunnel does not intercept them
+	annotations[istioSidecarInjectAnnotation] = False
+	delete(annotations, istioRewriteProbesAnnotation)
+	return labels, annotations
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package trait
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,11 +26,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/istio"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/test"
 )
@@ -170,3 +173,225 @@ func TestIstioDisabled(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotContains(t, env.ExecutedTraits, "istio")
 }
+
+func TestIstioServiceMeshResources(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.VirtualService = pointer.Bool(true)
+	trait.DestinationRule = pointer.Bool(true)
+	trait.LoadBalancer = "LEAST_REQUEST"
+	trait.PeerAuthentication = "STRICT"
+
+	configured, err := trait.Configure(env)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(env))
+
+	vs := findVirtualService(env.Resources)
+	assert.NotNil(t, vs)
+	assert.Equal(t, []string{"orders"}, vs.Spec.Hosts)
+	assert.Equal(t, []istio.HTTPRoute{
+		{Route: []istio.HTTPRouteDestination{{Destination: istio.Destination{Host: "orders"}}}},
+	}, vs.Spec.HTTP)
+
+	dr := findDestinationRule(env.Resources)
+	assert.NotNil(t, dr)
+	assert.Equal(t, "orders", dr.Spec.Host)
+	assert.Equal(t, "LEAST_REQUEST", dr.Spec.TrafficPolicy.LoadBalancer.Simple)
+	assert.Equal(t, "ISTIO_MUTUAL", dr.Spec.TrafficPolicy.TLS.Mode)
+
+	pa := findPeerAuthentication(env.Resources)
+	assert.NotNil(t, pa)
+	assert.Equal(t, "STRICT", pa.Spec.MTLS.Mode)
+	assert.Equal(t, map[string]string{v1.IntegrationLabel: "orders"}, pa.Spec.Selector.MatchLabels)
+}
+
+func TestIstioTrafficShift(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.TrafficShift = []string{"orders-v2:10", "orders-v3:5"}
+
+	configured, err := trait.Configure(env)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(env))
+
+	vs := findVirtualService(env.Resources)
+	assert.NotNil(t, vs)
+	assert.Len(t, vs.Spec.HTTP, 1)
+	assert.Equal(t, []istio.HTTPRouteDestination{
+		{Destination: istio.Destination{Host: "orders"}, Weight: 85},
+		{Destination: istio.Destination{Host: "orders-v2"}, Weight: 10},
+		{Destination: istio.Destination{Host: "orders-v3"}, Weight: 5},
+	}, vs.Spec.HTTP[0].Route)
+	assert.Nil(t, findDestinationRule(env.Resources))
+	assert.Nil(t, findPeerAuthentication(env.Resources))
+}
+
+func TestIstioInvalidTrafficShift(t *testing.T) {
+	for _, shift := range [][]string{{"orders-v2"}, {"orders-v2:ten"}, {"orders-v2:60", "orders-v3:50"}} {
+		trait, env := createIstioServiceMeshTest()
+		trait.TrafficShift = shift
+
+		configured, err := trait.Configure(env)
+		assert.NotNil(t, err)
+		assert.False(t, configured)
+	}
+}
+
+func TestIstioInvalidPeerAuthentication(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.PeerAuthentication = "MUTUAL"
+
+	configured, err := trait.Configure(env)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestIstioVirtualServiceWithoutService(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.VirtualService = pointer.Bool(true)
+	env.Resources = kubernetes.NewCollection()
+
+	configured, err := trait.Configure(env)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, trait.Apply(env))
+	assert.Nil(t, findVirtualService(env.Resources))
+}
+
+func TestIstioAmbientModeDetected(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.DestinationRule = pointer.Bool(true)
+	trait.PeerAuthentication = "STRICT"
+	trait.Waypoint = "waypoint"
+	client, _ := test.NewFakeClient(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ns",
+			Labels: map[string]string{
+				istioDataplaneModeLabel: istioModeAmbient,
+			},
+		},
+	})
+	env.Client = client
+	env.Ctx = context.TODO()
+	d := appsv1.Deployment{}
+	env.Resources.Add(&d)
+
+	configured, err := trait.Configure(env)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Equal(t, istioModeAmbient, trait.Mode)
+	assert.Nil(t, trait.Apply(env))
+
+	assert.Equal(t, istioModeAmbient, d.Spec.Template.Labels[istioDataplaneModeLabel])
+	assert.Equal(t, "false", d.Spec.Template.Annotations[istioSidecarInjectAnnotation])
+	assert.NotContains(t, d.Spec.Template.Annotations, istioOutboundIPRangesAnnotation)
+	assert.NotContains(t, d.Spec.Template.Annotations, istioRewriteProbesAnnotation)
+
+	service := env.Resources.GetServiceForIntegration(env.Integration)
+	assert.Equal(t, "waypoint", service.Labels[istioUseWaypointLabel])
+
+	dr := findDestinationRule(env.Resources)
+	assert.NotNil(t, dr)
+	assert.Nil(t, dr.Spec.TrafficPolicy)
+}
+
+func TestIstioSidecarModeDetected(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.PeerAuthentication = "STRICT"
+	client, _ := test.NewFakeClient(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ns",
+		},
+	})
+	env.Client = client
+	env.Ctx = context.TODO()
+	d := appsv1.Deployment{}
+	env.Resources.Add(&d)
+
+	configured, err := trait.Configure(env)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Equal(t, istioModeSidecar, trait.Mode)
+	assert.Nil(t, trait.Apply(env))
+
+	assert.NotContains(t, d.Spec.Template.Labels, istioDataplaneModeLabel)
+	assert.Equal(t, "true", d.Spec.Template.Annotations[istioSidecarInjectAnnotation])
+	assert.Equal(t, "true", d.Spec.Template.Annotations[istioRewriteProbesAnnotation])
+}
+
+func TestIstioAmbientModeWithInject(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.Mode = istioModeAmbient
+	trait.Inject = pointer.Bool(true)
+
+	configured, err := trait.Configure(env)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestIstioInvalidMode(t *testing.T) {
+	trait, env := createIstioServiceMeshTest()
+	trait.Mode = "proxyless"
+
+	configured, err := trait.Configure(env)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func findVirtualService(resources *kubernetes.Collection) *istio.VirtualService {
+	for _, r := range resources.Items() {
+		if vs, ok := r.(*istio.VirtualService); ok {
+			return vs
+		}
+	}
+	return nil
+}
+
+func findDestinationRule(resources *kubernetes.Collection) *istio.DestinationRule {
+	for _, r := range resources.Items() {
+		if dr, ok := r.(*istio.DestinationRule); ok {
+			return dr
+		}
+	}
+	return nil
+}
+
+func findPeerAuthentication(resources *kubernetes.Collection) *istio.PeerAuthentication {
+	for _, r := range resources.Items() {
+		if pa, ok := r.(*istio.PeerAuthentication); ok {
+			return pa
+		}
+	}
+	return nil
+}
+
+func createIstioServiceMeshTest() (*istioTrait, *Environment) {
+	trait, _ := newIstioTrait().(*istioTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	env := &Environment{
+		Catalog: NewEnvironmentTestCatalog(),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "orders",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Name:      "orders",
+					Labels: map[string]string{
+						v1.IntegrationLabel: "orders",
+					},
+				},
+			},
+		),
+	}
+
+	return trait, env
+}
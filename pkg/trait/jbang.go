@@ -0,0 +1,200 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/upload"
+)
+
+const (
+	jbangTraitID = "jbang"
+
+	jbangDefaultImage  = "docker.io/apache/camel-jbang"
+	jbangSourcesVolume = "i-sources"
+)
+
+// The JBang trait runs the Integration in a prebuilt, generic, Camel JBang runner image, in which the sources are
+// mounted, instead of an image built from the Integration. It skips the build entirely, so that the Integration
+// starts as soon as it's created, and the runner reloads the routes when the sources change, without restarting the
+// Pods. It's enabled by `kamel run --dev`, unless `--dev-runner=false` is set.
+//
+// The runner resolves the Camel components, and the Maven dependencies of the Integration, when it starts, so it must
+// have access to the Maven repositories. It's meant for development iterations only: the Integrations deployed to
+// production should not enable it, so that they are built and run with the regular pipeline.
+//
+// The trait can't be used in conjunction with an IntegrationKit, or with a container image.
+//
+// +camel-k:trait=jbang.
+type jbangTrait struct {
+	BaseTrait `property:",squash"`
+	// The runner image, that defaults to `docker.io/apache/camel-jbang`, tagged with the Camel version of the catalog.
+	Image string `property:"image" json:"image,omitempty"`
+	// Whether the runner reloads the routes when the sources change (default `true`).
+	// The sources are then mounted from projected volumes, that are updated in place.
+	HotReload *bool `property:"hot-reload" json:"hotReload,omitempty"`
+}
+
+func newJBangTrait() Trait {
+	return &jbangTrait{
+		BaseTrait: NewBaseTrait(jbangTraitID, 1990),
+	}
+}
+
+func (t *jbangTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if e.Integration.Spec.IntegrationKit != nil {
+		return false, fmt.Errorf("unsupported configuration: the jbang trait is enabled in conjunction with an IntegrationKit %v",
+			e.Integration.Spec.IntegrationKit)
+	}
+	if e.Integration.Spec.IntegrationKitDigest != "" {
+		return false, fmt.Errorf("unsupported configuration: the jbang trait is enabled in conjunction with the IntegrationKit image digest %s",
+			e.Integration.Spec.IntegrationKitDigest)
+	}
+	if ct, ok := e.Catalog.GetTrait(containerTraitID).(*containerTrait); ok && ct.Image != "" {
+		return false, fmt.Errorf("unsupported configuration: the jbang trait is enabled in conjunction with the container image %s", ct.Image)
+	}
+	if upload.HasDependency(e.Integration.Spec.Dependencies) {
+		return false, fmt.Errorf("unsupported configuration: the jbang trait is enabled in conjunction with uploaded files, that require a build")
+	}
+
+	if e.IntegrationInRunningPhases() {
+		// the runner image comes with its own launcher
+		if jt, ok := e.Catalog.GetTrait("jvm").(*jvmTrait); ok {
+			jt.Enabled = pointer.Bool(false)
+		}
+		return true, nil
+	}
+
+	return e.IntegrationInPhase(v1.IntegrationPhaseInitialization), nil
+}
+
+func (t *jbangTrait) Apply(e *Environment) error {
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		image := t.Image
+		if image == "" {
+			if e.CamelCatalog == nil {
+				return fmt.Errorf("unable to determine the Camel version of the jbang runner image")
+			}
+			image = fmt.Sprintf("%s:%s", jbangDefaultImage, e.CamelCatalog.GetCamelVersion())
+		}
+
+		kit := newExternalIntegrationKit(e, fmt.Sprintf("kit-%s-jbang", e.Integration.Name), image)
+		t.L.Infof("jbang runner image %s", kit.Spec.Image)
+		e.Resources.Add(kit)
+		e.Integration.SetIntegrationKit(kit)
+
+		return nil
+	}
+
+	container := e.GetIntegrationContainer()
+	if container == nil {
+		return fmt.Errorf("unable to find integration container")
+	}
+
+	hotReload := pointer.BoolDeref(t.HotReload, true)
+
+	args := []string{"run"}
+	if hotReload {
+		args = append(args, "--dev")
+	}
+	for _, d := range e.Integration.Spec.Dependencies {
+		switch {
+		case strings.HasPrefix(d, "mvn:"):
+			args = append(args, "--dep="+strings.TrimPrefix(d, "mvn:"))
+		case strings.HasPrefix(d, "camel:"):
+			args = append(args, "--dep="+d)
+		}
+	}
+	for _, s := range e.Integration.Sources() {
+		args = append(args, path.Join(camel.SourcesMountPath, strings.TrimPrefix(s.Name, "/")))
+	}
+	args = append(args, path.Join(camel.BasePath, "application.properties"))
+
+	// use the entrypoint of the runner image
+	container.Command = nil
+	container.Args = args
+
+	if hotReload {
+		// the digest changes with the sources, so it would roll the Pods out at each change
+		envvar.Remove(&container.Env, "CAMEL_K_DIGEST")
+		e.Resources.VisitPodSpec(projectSourceVolumes)
+	}
+
+	return nil
+}
+
+// projectSourceVolumes replaces the source volumes, that are mounted as single files, with a projected volume mounted
+// as a directory, so that the sources are updated in place when their ConfigMaps change.
+func projectSourceVolumes(spec *corev1.PodSpec) {
+	projected := corev1.ProjectedVolumeSource{}
+	volumes := make([]corev1.Volume, 0, len(spec.Volumes))
+	sourceVolumes := make(map[string]bool)
+	for _, v := range spec.Volumes {
+		if strings.HasPrefix(v.Name, "i-source-") && v.ConfigMap != nil {
+			projected.Sources = append(projected.Sources, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: v.ConfigMap.LocalObjectReference,
+					Items:                v.ConfigMap.Items,
+				},
+			})
+			sourceVolumes[v.Name] = true
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	if len(sourceVolumes) == 0 {
+		return
+	}
+	spec.Volumes = append(volumes, corev1.Volume{
+		Name: jbangSourcesVolume,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &projected,
+		},
+	})
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		mounts := make([]corev1.VolumeMount, 0, len(container.VolumeMounts))
+		for _, m := range container.VolumeMounts {
+			if !sourceVolumes[m.Name] {
+				mounts = append(mounts, m)
+			}
+		}
+		if len(mounts) < len(container.VolumeMounts) {
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      jbangSourcesVolume,
+				MountPath: camel.SourcesMountPath,
+				ReadOnly:  true,
+			})
+		}
+		container.VolumeMounts = mounts
+	}
+}
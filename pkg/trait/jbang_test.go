@@ -0,0 +1,190 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+func TestConfigureJBangTraitIsDisabledByDefault(t *testing.T) {
+	trait, environment := createJBangTest(t, v1.IntegrationPhaseInitialization)
+	trait.Enabled = nil
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureJBangTraitWithContainerImage(t *testing.T) {
+	trait, environment := createJBangTest(t, v1.IntegrationPhaseInitialization)
+	ct, _ := environment.Catalog.GetTrait(containerTraitID).(*containerTrait)
+	ct.Image = "quay.io/acme/routes:1.0"
+
+	configured, err := trait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestApplyJBangTraitCreatesExternalKit(t *testing.T) {
+	trait, environment := createJBangTest(t, v1.IntegrationPhaseInitialization)
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	err = trait.Apply(environment)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, environment.Resources.Size())
+	kit, ok := environment.Resources.Items()[1].(*v1.IntegrationKit)
+	assert.True(t, ok)
+	assert.Equal(t, "kit-my-it-jbang", kit.Name)
+	assert.Equal(t, "docker.io/apache/camel-jbang:"+environment.CamelCatalog.GetCamelVersion(), kit.Spec.Image)
+	assert.Equal(t, v1.IntegrationKitTypeExternal, kit.Labels[v1.IntegrationKitTypeLabel])
+	assert.Equal(t, "kit-my-it-jbang", environment.Integration.Status.IntegrationKit.Name)
+}
+
+func TestApplyJBangTraitRunsSources(t *testing.T) {
+	trait, environment := createJBangTest(t, v1.IntegrationPhaseDeploying)
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	jt, _ := environment.Catalog.GetTrait("jvm").(*jvmTrait)
+	assert.False(t, pointer.BoolDeref(jt.Enabled, true))
+
+	err = trait.Apply(environment)
+	assert.Nil(t, err)
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Nil(t, container.Command)
+	assert.Equal(t, []string{
+		"run", "--dev", "--dep=org.acme:lib:1.0", "--dep=camel:kafka",
+		"/etc/camel/sources/routes.yaml", "/etc/camel/application.properties",
+	}, container.Args)
+	assert.Equal(t, []corev1.EnvVar{{Name: "CAMEL_K_CONF", Value: "/etc/camel/application.properties"}}, container.Env)
+
+	volumes := deployment.Spec.Template.Spec.Volumes
+	assert.Len(t, volumes, 2)
+	assert.Equal(t, "i-properties", volumes[0].Name)
+	assert.Equal(t, jbangSourcesVolume, volumes[1].Name)
+	assert.Equal(t, "my-it-source-000", volumes[1].Projected.Sources[0].ConfigMap.Name)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "i-properties", MountPath: "/etc/camel/application.properties", SubPath: "application.properties", ReadOnly: true},
+		{Name: jbangSourcesVolume, MountPath: camel.SourcesMountPath, ReadOnly: true},
+	}, container.VolumeMounts)
+}
+
+func TestApplyJBangTraitWithoutHotReload(t *testing.T) {
+	trait, environment := createJBangTest(t, v1.IntegrationPhaseDeploying)
+	trait.HotReload = pointer.Bool(false)
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	err = trait.Apply(environment)
+	assert.Nil(t, err)
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "run", container.Args[0])
+	assert.NotContains(t, container.Args, "--dev")
+	assert.Len(t, container.Env, 2)
+	assert.Equal(t, "i-source-000", deployment.Spec.Template.Spec.Volumes[1].Name)
+}
+
+func createJBangTest(t *testing.T, phase v1.IntegrationPhase) (*jbangTrait, *Environment) {
+	t.Helper()
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	trait, _ := newJBangTrait().(*jbangTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	integration := &v1.Integration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-it",
+			Namespace: "ns",
+		},
+		Spec: v1.IntegrationSpec{
+			Sources: []v1.SourceSpec{
+				{DataSpec: v1.DataSpec{Name: "routes.yaml", Content: "- from:\n    uri: timer:tick"}},
+			},
+			Dependencies: []string{"mvn:org.acme:lib:1.0", "camel:kafka", "camel-k:runtime"},
+		},
+		Status: v1.IntegrationStatus{
+			Phase: phase,
+		},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-it",
+			Namespace: "ns",
+			Labels: map[string]string{
+				v1.IntegrationLabel: "my-it",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    defaultContainerName,
+							Command: []string{"java"},
+							Env: []corev1.EnvVar{
+								{Name: "CAMEL_K_DIGEST", Value: "digest"},
+								{Name: "CAMEL_K_CONF", Value: "/etc/camel/application.properties"},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "i-properties", MountPath: "/etc/camel/application.properties", SubPath: "application.properties", ReadOnly: true},
+								{Name: "i-source-000", MountPath: "/etc/camel/sources/routes.yaml", SubPath: "routes.yaml", ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						*getVolume("i-properties", "configmap", "my-it-application-properties", "application.properties", "application.properties"),
+						*getVolume("i-source-000", "configmap", "my-it-source-000", "content", "routes.yaml"),
+					},
+				},
+			},
+		},
+	}
+
+	environment := &Environment{
+		CamelCatalog: catalog,
+		Catalog:      NewCatalog(nil),
+		Integration:  integration,
+		Resources:    kubernetes.NewCollection(deployment),
+	}
+
+	return trait, environment
+}
@@ -0,0 +1,202 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/label"
+)
+
+// The Job trait can be used to run integrations that perform a finite amount of work, such as batch imports or
+// one-shot migrations, as a Kubernetes Job that runs to completion, instead of a Deployment whose pods are restarted
+// whenever they terminate.
+//
+// Unlike the cron trait, the Job is executed once, as soon as the integration is deployed. The integration reaches the
+// `Completed` phase when the Job succeeds, or the `Error` phase when the Job fails, e.g., once its pods have failed
+// more times than the backoff limit allows, or when it runs beyond its active deadline.
+//
+// The Job is recreated, and thus executed again, when the integration changes. The Camel application stops once
+// it has been idle for the configured amount of time, so that the Job can complete.
+//
+// +camel-k:trait=job.
+type jobTrait struct {
+	BaseTrait `property:",squash"`
+	// Specifies the number of retries before marking the job failed.
+	// It defaults to the Kubernetes default value, i.e., 6.
+	BackoffLimit *int32 `property:"backoff-limit" json:"backoffLimit,omitempty"`
+	// Specifies the duration in seconds, relative to the start time, that the job
+	// may be continuously active before it is considered to be failed.
+	// The job is not limited in time by default.
+	ActiveDeadlineSeconds *int64 `property:"active-deadline-seconds" json:"activeDeadlineSeconds,omitempty"`
+	// The number of seconds the Camel application can stay idle, i.e., without processing any message,
+	// before it stops and the job completes. It defaults to 5s.
+	MaxIdleSeconds *int32 `property:"max-idle-seconds" json:"maxIdleSeconds,omitempty"`
+}
+
+var _ ControllerStrategySelector = &jobTrait{}
+
+func newJobTrait() Trait {
+	return &jobTrait{
+		// The Job is checked against the live one before the deployer trait applies it
+		BaseTrait: NewBaseTrait("job", 850),
+	}
+}
+
+func (t *jobTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, true) {
+		return false, nil
+	}
+
+	if !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if t.MaxIdleSeconds != nil && *t.MaxIdleSeconds <= 0 {
+		return false, fmt.Errorf("job max idle seconds must be strictly positive: %d", *t.MaxIdleSeconds)
+	}
+
+	strategy, err := e.DetermineControllerStrategy()
+	if err != nil {
+		e.Integration.Status.SetErrorCondition(
+			v1.IntegrationConditionJobAvailable,
+			v1.IntegrationConditionJobNotAvailableReason,
+			err,
+		)
+		return false, err
+	}
+
+	return strategy == ControllerStrategyJob, nil
+}
+
+func (t *jobTrait) Apply(e *Environment) error {
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+	e.ApplicationProperties["camel.main.duration-max-idle-seconds"] = strconv.Itoa(int(pointer.Int32Deref(t.MaxIdleSeconds, 5)))
+
+	job := t.getJobFor(e)
+	e.Resources.Add(job)
+
+	e.Integration.Status.SetCondition(
+		v1.IntegrationConditionJobAvailable,
+		corev1.ConditionTrue,
+		v1.IntegrationConditionJobAvailableReason,
+		fmt.Sprintf("job name is %s", job.Name),
+	)
+
+	// The Job pod template is immutable, so that the Job must be recreated for the changes to be run
+	e.PostActions = append(e.PostActions, func(env *Environment) error {
+		return t.deleteOutdatedJob(env, job)
+	})
+
+	return nil
+}
+
+func (t *jobTrait) getJobFor(e *Environment) *batchv1.Job {
+	annotations := make(map[string]string)
+	if e.Integration.Annotations != nil {
+		for k, v := range filterTransferableAnnotations(e.Integration.Annotations) {
+			annotations[k] = v
+		}
+	}
+
+	job := batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      e.Integration.Name,
+			Namespace: e.Integration.Namespace,
+			Labels: map[string]string{
+				v1.IntegrationLabel: e.Integration.Name,
+			},
+			Annotations: annotations,
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds: t.ActiveDeadlineSeconds,
+			BackoffLimit:          t.BackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      label.AddLabels(e.Integration.Name),
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: e.Integration.Spec.ServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+
+	return &job
+}
+
+// deleteOutdatedJob deletes the existing Job, when its pod template differs from the generated one,
+// so that the deployer trait creates it again.
+func (t *jobTrait) deleteOutdatedJob(e *Environment, job *batchv1.Job) error {
+	if e.IsReconcilePaused() {
+		return nil
+	}
+
+	existing := &batchv1.Job{}
+	err := e.Client.Get(e.Ctx, ctrl.ObjectKeyFromObject(job), existing)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// The Job controller adds its own labels to the pod template, and the API server defaults the unset fields
+	if equality.Semantic.DeepDerivative(job.Spec.Template, existing.Spec.Template) {
+		return nil
+	}
+
+	t.L.ForIntegration(e.Integration).Infof("Job %s pod template has changed, recreating it", existing.Name)
+	err = e.Client.Delete(e.Ctx, existing, ctrl.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// SelectControllerStrategy selects the Job controller strategy, when the trait is explicitly enabled.
+func (t *jobTrait) SelectControllerStrategy(e *Environment) (*ControllerStrategy, error) {
+	if pointer.BoolDeref(t.Enabled, false) {
+		jobStrategy := ControllerStrategyJob
+		return &jobStrategy, nil
+	}
+	return nil, nil
+}
+
+func (t *jobTrait) ControllerStrategySelectorOrder() int {
+	return 50
+}
@@ -0,0 +1,217 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestJobTrait(t *testing.T) {
+	environment := createNominalJobTraitTest(t, map[string]v1.TraitSpec{
+		"job": test.TraitSpecFromMap(t, map[string]interface{}{
+			"enabled":               true,
+			"backoffLimit":          3,
+			"activeDeadlineSeconds": 600,
+		}),
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(environment)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, environment.GetTrait("job"))
+	assert.Nil(t, environment.GetTrait("deployment"))
+	assert.Nil(t, environment.Resources.GetDeployment(func(*appsv1.Deployment) bool { return true }))
+
+	job := environment.Resources.GetJob(func(*batchv1.Job) bool { return true })
+	assert.NotNil(t, job)
+	assert.Equal(t, "test", job.Name)
+	assert.Equal(t, "test", job.Labels[v1.IntegrationLabel])
+	assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	assert.Len(t, job.Spec.Template.Spec.Containers, 1)
+	assert.EqualValues(t, 3, *job.Spec.BackoffLimit)
+	assert.EqualValues(t, 600, *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, "5", environment.ApplicationProperties["camel.main.duration-max-idle-seconds"])
+
+	condition := environment.Integration.Status.GetCondition(v1.IntegrationConditionJobAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	condition = environment.Integration.Status.GetCondition(v1.IntegrationConditionDeploymentAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+}
+
+func TestJobTraitFromDeployerKind(t *testing.T) {
+	environment := createNominalJobTraitTest(t, map[string]v1.TraitSpec{
+		"deployer": test.TraitSpecFromMap(t, map[string]interface{}{
+			"kind": "job",
+		}),
+		"job": test.TraitSpecFromMap(t, map[string]interface{}{
+			"maxIdleSeconds": 30,
+		}),
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(environment)
+	assert.Nil(t, err)
+
+	job := environment.Resources.GetJob(func(*batchv1.Job) bool { return true })
+	assert.NotNil(t, job)
+	assert.Nil(t, job.Spec.BackoffLimit)
+	assert.Nil(t, job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, "30", environment.ApplicationProperties["camel.main.duration-max-idle-seconds"])
+}
+
+func TestJobTraitDisabledByDefault(t *testing.T) {
+	environment := createNominalJobTraitTest(t, map[string]v1.TraitSpec{})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(environment)
+	assert.Nil(t, err)
+
+	assert.Nil(t, environment.GetTrait("job"))
+	assert.Nil(t, environment.Resources.GetJob(func(*batchv1.Job) bool { return true }))
+	assert.NotNil(t, environment.Resources.GetDeployment(func(*appsv1.Deployment) bool { return true }))
+}
+
+func TestJobTraitDeletesOutdatedJob(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:  "integration",
+					Image: "my-image:1",
+				},
+			},
+		},
+	}
+	existing := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "ns",
+		},
+		Spec: batchv1.JobSpec{
+			Template: *template.DeepCopy(),
+		},
+	}
+	// The Job controller labels the pod template
+	existing.Spec.Template.Labels = map[string]string{"job-name": "test"}
+
+	c, err := test.NewFakeClient(existing)
+	assert.Nil(t, err)
+
+	environment := &Environment{
+		Ctx:    context.TODO(),
+		Client: c,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+		},
+	}
+	trait, _ := newJobTrait().(*jobTrait)
+	job := existing.DeepCopy()
+	job.Spec.Template = *template.DeepCopy()
+
+	// Unchanged pod template
+	err = trait.deleteOutdatedJob(environment, job)
+	assert.Nil(t, err)
+	err = c.Get(context.TODO(), ctrl.ObjectKeyFromObject(existing), &batchv1.Job{})
+	assert.Nil(t, err)
+
+	// Changed pod template
+	job.Spec.Template.Spec.Containers[0].Image = "my-image:2"
+	err = trait.deleteOutdatedJob(environment, job)
+	assert.Nil(t, err)
+	err = c.Get(context.TODO(), ctrl.ObjectKeyFromObject(existing), &batchv1.Job{})
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func createNominalJobTraitTest(t *testing.T, traits map[string]v1.TraitSpec) *Environment {
+	t.Helper()
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	environment := &Environment{
+		CamelCatalog: catalog,
+		Catalog:      NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "ns",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name:    "routes.java",
+							Content: `from("file:/data/inbox?noop=true").to("log:test")`,
+						},
+						Language: v1.LanguageJavaSource,
+					},
+				},
+				Traits: traits,
+			},
+		},
+		IntegrationKit: &v1.IntegrationKit{
+			Status: v1.IntegrationKitStatus{
+				Phase: v1.IntegrationKitPhaseReady,
+			},
+		},
+		Platform:       &v1.IntegrationPlatform{},
+		EnvVars:        make([]corev1.EnvVar, 0),
+		ExecutedTraits: make([]Trait, 0),
+		Resources:      kubernetes.NewCollection(),
+	}
+	environment.Platform.ResyncStatusFullConfig()
+
+	return environment
+}
@@ -84,6 +84,11 @@ func (t *jolokiaTrait) Configure(e *Environment) (bool, error) {
 	return e.IntegrationInPhase(v1.IntegrationPhaseInitialization) || e.IntegrationInRunningPhases(), nil
 }
 
+// RequiresTraits makes sure the integration container is configured before the Jolokia port is added to it.
+func (t *jolokiaTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
 func (t *jolokiaTrait) Apply(e *Environment) (err error) {
 	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
 		// Add the Camel management and Jolokia agent dependencies
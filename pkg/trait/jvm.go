@@ -89,12 +89,17 @@ func (t *jvmTrait) Configure(e *Environment) (bool, error) {
 	return true, nil
 }
 
+// RequiresTraits makes sure the Quarkus packaging is known and the integration container is configured before the JVM arguments are computed.
+func (t *jvmTrait) RequiresTraits() []ID {
+	return []ID{quarkusTraitID, containerTraitID}
+}
+
 func (t *jvmTrait) Apply(e *Environment) error {
 	kit := e.IntegrationKit
 
 	if kit == nil && e.Integration.Status.IntegrationKit != nil {
 		name := e.Integration.Status.IntegrationKit.Name
-		ns := e.Integration.GetIntegrationKitNamespace(e.Platform)
+		ns := e.Tenant.IntegrationKitNamespace(e.Integration, e.Platform)
 		k := v1.NewIntegrationKit(ns, name)
 		if err := t.Client.Get(e.Ctx, ctrl.ObjectKeyFromObject(k), k); err != nil {
 			return errors.Wrapf(err, "unable to find integration kit %s/%s, %s", ns, name, err)
@@ -104,7 +109,7 @@ func (t *jvmTrait) Apply(e *Environment) error {
 
 	if kit == nil {
 		if e.Integration.Status.IntegrationKit != nil {
-			return fmt.Errorf("unable to find integration kit %s/%s", e.Integration.GetIntegrationKitNamespace(e.Platform), e.Integration.Status.IntegrationKit.Name)
+			return fmt.Errorf("unable to find integration kit %s/%s", e.Tenant.IntegrationKitNamespace(e.Integration, e.Platform), e.Integration.Status.IntegrationKit.Name)
 		}
 		return fmt.Errorf("unable to find integration kit for integration %s", e.Integration.Name)
 	}
@@ -244,9 +249,21 @@ func (t *jvmTrait) Apply(e *Environment) error {
 	for _, m := range container.VolumeMounts {
 		classpath.Add(m.MountPath)
 	}
-	items := classpath.List()
+
+	var items []string
+	if kit.Labels[v1.IntegrationKitAppCDSLabel] == "true" {
+		// The class path must start with the one the AppCDS archive has been dumped with for it to be used
+		items = builder.AppCDSClasspath(kit.Status.Artifacts)
+		classpath.Remove(items...)
+		if !util.StringSliceContainsAnyOf(t.Options, "-XX:SharedArchiveFile", "-Xshare") {
+			// The archive is ignored, rather than failing the JVM, if it cannot be mapped
+			args = append(args, "-XX:SharedArchiveFile="+path.Join(builder.DeploymentDir, builder.AppCDSArchive), "-Xshare:auto")
+		}
+	}
+	others := classpath.List()
 	// Keep class path sorted so that it's consistent over reconciliation cycles
-	sort.Strings(items)
+	sort.Strings(others)
+	items = append(items, others...)
 	args = append(args, "-cp", strings.Join(items, ":"))
 
 	args = append(args, e.CamelCatalog.Runtime.ApplicationClass)
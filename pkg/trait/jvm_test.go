@@ -250,6 +250,41 @@ func TestApplyJvmTraitWithClasspath(t *testing.T) {
 	}, d.Spec.Template.Spec.Containers[0].Args)
 }
 
+func TestApplyJvmTraitWithAppCDSKit(t *testing.T) {
+	trait, environment := createNominalJvmTest(v1.IntegrationKitTypePlatform)
+	environment.IntegrationKit.Labels[v1.IntegrationKitAppCDSLabel] = "true"
+	environment.IntegrationKit.Status.Artifacts = []v1.Artifact{
+		{ID: "quarkus-run.jar", Target: "dependencies/quarkus-run.jar"},
+		{ID: "quarkus-application.dat", Target: "dependencies/quarkus/quarkus-application.dat"},
+		{ID: "camel-core.jar", Target: "dependencies/lib/main/camel-core.jar"},
+	}
+	d := appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: defaultContainerName,
+						},
+					},
+				},
+			},
+		},
+	}
+	environment.Resources.Add(&d)
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"-XX:SharedArchiveFile=/deployments/app-cds.jsa",
+		"-Xshare:auto",
+		"-cp",
+		fmt.Sprintf("dependencies/lib/main/camel-core.jar:dependencies/quarkus-run.jar:./resources:%s:%s:dependencies/quarkus/quarkus-application.dat",
+			camel.ConfigResourcesMountPath, camel.ResourcesDefaultMountPath),
+		"io.quarkus.bootstrap.runner.QuarkusEntryPoint",
+	}, d.Spec.Template.Spec.Containers[0].Args)
+}
+
 func createNominalJvmTest(kitType string) (*jvmTrait, *Environment) {
 	catalog, _ := camel.DefaultCatalog()
 
@@ -48,6 +48,10 @@ type kameletsTrait struct {
 	Auto *bool `property:"auto"`
 	// Comma separated list of Kamelet names to load into the current integration
 	List string `property:"list"`
+	// How the integration is updated when the Kamelets it uses are updated: `auto` rebuilds and rolls out the integration,
+	// `manual` reports the update with the `KameletsUpToDate` condition until the integration is rebuilt, e.g., with
+	// `kamel rebuild`, and `ignore` keeps running the Kamelets the integration has been built with (`auto` by default)
+	UpdatePolicy string `property:"update-policy"`
 }
 
 type configurationKey struct {
@@ -67,6 +71,10 @@ const (
 
 	kameletLabel              = "camel.apache.org/kamelet"
 	kameletConfigurationLabel = "camel.apache.org/kamelet.configuration"
+
+	kameletsUpdatePolicyAuto   = "auto"
+	kameletsUpdatePolicyManual = "manual"
+	kameletsUpdatePolicyIgnore = "ignore"
 )
 
 func newKameletsTrait() Trait {
@@ -89,6 +97,13 @@ func (t *kameletsTrait) Configure(e *Environment) (bool, error) {
 		return false, nil
 	}
 
+	switch t.UpdatePolicy {
+	case "", kameletsUpdatePolicyAuto, kameletsUpdatePolicyManual, kameletsUpdatePolicyIgnore:
+	default:
+		return false, fmt.Errorf("unsupported kamelets update policy %q, must be one of %s, %s or %s",
+			t.UpdatePolicy, kameletsUpdatePolicyAuto, kameletsUpdatePolicyManual, kameletsUpdatePolicyIgnore)
+	}
+
 	if pointer.BoolDeref(t.Auto, true) {
 		kamelets, err := kamelets.ExtractKameletFromSources(e.Ctx, e.Client, e.CamelCatalog, e.Resources, e.Integration)
 		if err != nil {
@@ -184,6 +199,14 @@ func (t *kameletsTrait) addKamelets(e *Environment) error {
 			return err
 		}
 
+		if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+			e.Integration.Status.Kamelets = t.kameletReferences(kamelets)
+		} else if updated := updatedKamelets(e.Integration, kamelets); len(updated) > 0 {
+			// Keep the sources of the Kamelets the integration has been built with, until it's rebuilt
+			t.reportUpdatedKamelets(e, updated)
+			return nil
+		}
+
 		for _, key := range t.getKameletKeys() {
 			kamelet := kamelets[key]
 
@@ -204,6 +227,63 @@ func (t *kameletsTrait) addKamelets(e *Environment) error {
 	return nil
 }
 
+// kameletReferences returns the references to the Kamelets, at their current generation, so that the integration
+// can be updated when they change. The Kamelets from remote repositories cannot be watched, and are not referenced.
+func (t *kameletsTrait) kameletReferences(kamelets map[string]*v1alpha1.Kamelet) []v1.IntegrationKamelet {
+	references := make([]v1.IntegrationKamelet, 0, len(kamelets))
+	for _, key := range t.getKameletKeys() {
+		kamelet := kamelets[key]
+		if kamelet.Namespace == "" {
+			continue
+		}
+		references = append(references, v1.IntegrationKamelet{
+			Namespace:  kamelet.Namespace,
+			Name:       kamelet.Name,
+			Generation: kamelet.Generation,
+		})
+	}
+	return references
+}
+
+// updatedKamelets returns the names of the Kamelets that have changed since the integration has been initialized,
+// either updated, or shadowed by a Kamelet from another namespace.
+func updatedKamelets(integration *v1.Integration, kamelets map[string]*v1alpha1.Kamelet) []string {
+	updated := make([]string, 0)
+	for _, reference := range integration.Status.Kamelets {
+		kamelet, ok := kamelets[reference.Name]
+		if !ok {
+			continue
+		}
+		if kamelet.Namespace != reference.Namespace || kamelet.Generation != reference.Generation {
+			updated = append(updated, reference.Name)
+		}
+	}
+	sort.Strings(updated)
+	return updated
+}
+
+func (t *kameletsTrait) reportUpdatedKamelets(e *Environment, updated []string) {
+	switch t.UpdatePolicy {
+	case kameletsUpdatePolicyIgnore:
+		return
+	case kameletsUpdatePolicyManual:
+		e.Integration.Status.SetCondition(
+			v1.IntegrationConditionKameletsUpToDate,
+			corev1.ConditionFalse,
+			v1.IntegrationConditionKameletsUpdatePendingReason,
+			fmt.Sprintf("kamelets %s updated, rebuild the integration to roll out the update", strings.Join(updated, ",")),
+		)
+	default:
+		// The integration controller rebuilds the integration upon this condition
+		e.Integration.Status.SetCondition(
+			v1.IntegrationConditionKameletsUpToDate,
+			corev1.ConditionFalse,
+			v1.IntegrationConditionKameletsUpdatedReason,
+			fmt.Sprintf("kamelets %s updated", strings.Join(updated, ",")),
+		)
+	}
+}
+
 func (t *kameletsTrait) configureApplicationProperties(e *Environment) error {
 	if len(t.getKameletKeys()) > 0 {
 		kamelets, err := t.collectKamelets(e)
@@ -211,6 +291,10 @@ func (t *kameletsTrait) configureApplicationProperties(e *Environment) error {
 			return err
 		}
 
+		userProps := make([]v1.ConfigurationSpec, 0, len(e.Integration.Spec.Configuration))
+		userProps = append(userProps, e.Integration.Spec.Configuration...)
+		userProps = append(userProps, e.environmentProfile.Configurations()...)
+
 		for _, key := range t.getKameletKeys() {
 			kamelet := kamelets[key]
 			// Configuring defaults from Kamelet
@@ -220,7 +304,7 @@ func (t *kameletsTrait) configureApplicationProperties(e *Environment) error {
 					userDefined := false
 					propName := fmt.Sprintf("camel.kamelet.%s.%s", kamelet.Name, prop.Name)
 					propPrefix := propName + "="
-					for _, userProp := range e.Integration.Spec.Configuration {
+					for _, userProp := range userProps {
 						if strings.HasPrefix(userProp.Value, propPrefix) {
 							userDefined = true
 							break
@@ -534,6 +534,118 @@ func TestKameletConditionTrue(t *testing.T) {
 	assert.Contains(t, cond.Message, "none,timer found")
 }
 
+func TestKameletReferences(t *testing.T) {
+	trait, environment := createKameletsTestEnvironment(`
+- from:
+    uri: kamelet:timer
+    steps:
+    - to: log:info
+`, timerKamelet(3))
+
+	enabled, err := trait.Configure(environment)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(environment)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1.IntegrationKamelet{{Namespace: "test", Name: "timer", Generation: 3}}, environment.Integration.Status.Kamelets)
+}
+
+func TestKameletUpdated(t *testing.T) {
+	tests := []struct {
+		policy string
+		reason string
+	}{
+		{policy: "", reason: v1.IntegrationConditionKameletsUpdatedReason},
+		{policy: kameletsUpdatePolicyAuto, reason: v1.IntegrationConditionKameletsUpdatedReason},
+		{policy: kameletsUpdatePolicyManual, reason: v1.IntegrationConditionKameletsUpdatePendingReason},
+		{policy: kameletsUpdatePolicyIgnore},
+	}
+
+	for _, tc := range tests {
+		trait, environment := createKameletsTestEnvironment(`
+- from:
+    uri: kamelet:timer
+    steps:
+    - to: log:info
+`, timerKamelet(3))
+		trait.UpdatePolicy = tc.policy
+		environment.Integration.Status.Phase = v1.IntegrationPhaseRunning
+		environment.Integration.Status.Kamelets = []v1.IntegrationKamelet{{Namespace: "test", Name: "timer", Generation: 2}}
+
+		enabled, err := trait.Configure(environment)
+		assert.NoError(t, err)
+		assert.True(t, enabled)
+
+		err = trait.Apply(environment)
+		assert.NoError(t, err)
+		// The sources of the Kamelet the integration has been built with are kept
+		assert.Empty(t, environment.Integration.Status.GeneratedSources)
+		assert.Nil(t, environment.Resources.GetConfigMap(func(_ *corev1.ConfigMap) bool { return true }))
+
+		cond := environment.Integration.Status.GetCondition(v1.IntegrationConditionKameletsUpToDate)
+		if tc.reason == "" {
+			assert.Nil(t, cond)
+			continue
+		}
+		assert.NotNil(t, cond)
+		assert.Equal(t, corev1.ConditionFalse, cond.Status)
+		assert.Equal(t, tc.reason, cond.Reason)
+		assert.Contains(t, cond.Message, "kamelets timer updated")
+	}
+}
+
+func TestKameletUpToDate(t *testing.T) {
+	trait, environment := createKameletsTestEnvironment(`
+- from:
+    uri: kamelet:timer
+    steps:
+    - to: log:info
+`, timerKamelet(3))
+	environment.Integration.Status.Phase = v1.IntegrationPhaseRunning
+	environment.Integration.Status.Kamelets = []v1.IntegrationKamelet{{Namespace: "test", Name: "timer", Generation: 3}}
+
+	enabled, err := trait.Configure(environment)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(environment)
+	assert.NoError(t, err)
+	assert.Len(t, environment.Integration.Status.GeneratedSources, 1)
+	assert.Nil(t, environment.Integration.Status.GetCondition(v1.IntegrationConditionKameletsUpToDate))
+}
+
+func TestKameletInvalidUpdatePolicy(t *testing.T) {
+	trait, environment := createKameletsTestEnvironment(`
+- from:
+    uri: kamelet:timer
+    steps:
+    - to: log:info
+`, timerKamelet(1))
+	trait.UpdatePolicy = "never"
+
+	_, err := trait.Configure(environment)
+	assert.Error(t, err)
+}
+
+func timerKamelet(generation int64) *v1alpha1.Kamelet {
+	return &v1alpha1.Kamelet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "test",
+			Name:       "timer",
+			Generation: generation,
+		},
+		Spec: v1alpha1.KameletSpec{
+			Template: templateOrFail(map[string]interface{}{
+				"from": map[string]interface{}{
+					"uri": "timer:tick",
+				},
+			}),
+		},
+		Status: v1alpha1.KameletStatus{Phase: v1alpha1.KameletPhaseReady},
+	}
+}
+
 func createKameletsTestEnvironment(flow string, objects ...runtime.Object) (*kameletsTrait, *Environment) {
 	catalog, _ := camel.DefaultCatalog()
 
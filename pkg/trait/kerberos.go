@@ -0,0 +1,244 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/camel"
+	utilResource "github.com/apache/camel-k/pkg/util/resource"
+)
+
+const (
+	kerberosTraitID = "kerberos"
+
+	kerberosConfigVolumeName = "kerberos-config"
+	kerberosKrb5VolumeName   = "kerberos-krb5"
+	kerberosKeytabVolumeName = "kerberos-keytab"
+
+	krb5ConfFile   = "krb5.conf"
+	jaasConfFile   = "jaas.conf"
+	krb5KeytabFile = "krb5.keytab"
+)
+
+var (
+	kerberosMountPath = path.Join(camel.BasePath, "kerberos")
+
+	// The login contexts of the Kafka, ZooKeeper, HDFS and SQL Server clients.
+	defaultLoginContexts = []string{"KafkaClient", "Client", "com.sun.security.jgss.initiate", "SQLJDBCDriver"}
+)
+
+// The Kerberos trait configures the Integration JVM to authenticate with Kerberos, e.g., to Kerberized Kafka
+// clusters, HDFS, or SQL Server databases.
+//
+// The keytab of the principal is read from a Secret, and the `krb5.conf` Kerberos configuration is either read from
+// a ConfigMap or a Secret, or generated from the `realm` and `kdc` options. A JAAS configuration, declaring the login
+// contexts of the principal, is generated, and the `java.security.krb5.conf` and `java.security.auth.login.config`
+// system properties are set on the Integration JVM.
+//
+// The clients still need to be configured to use Kerberos, e.g., with the `camel.component.kafka.security-protocol=SASL_SSL`,
+// `camel.component.kafka.sasl-mechanism=GSSAPI` and `camel.component.kafka.sasl-kerberos-service-name=kafka` properties.
+//
+// +camel-k:trait=kerberos.
+type kerberosTrait struct {
+	BaseTrait `property:",squash"`
+	// The Kerberos principal of the Integration, e.g., `camel@EXAMPLE.COM`.
+	Principal string `property:"principal" json:"principal,omitempty"`
+	// The keytab of the principal. Syntax: secret:name[/key], the key defaults to `krb5.keytab`.
+	Keytab string `property:"keytab" json:"keytab,omitempty"`
+	// The Kerberos configuration file. Syntax: [configmap|secret]:name[/key], the key defaults to `krb5.conf`.
+	Krb5Conf string `property:"krb5-conf" json:"krb5Conf,omitempty"`
+	// The default realm, used to generate the Kerberos configuration when no configuration file is set.
+	Realm string `property:"realm" json:"realm,omitempty"`
+	// The KDC hosts of the default realm, used to generate the Kerberos configuration when no configuration file is set.
+	KDC []string `property:"kdc" json:"kdc,omitempty"`
+	// The JAAS login contexts to declare for the principal (default `KafkaClient`, `Client`, `com.sun.security.jgss.initiate`, `SQLJDBCDriver`).
+	LoginContexts []string `property:"login-contexts" json:"loginContexts,omitempty"`
+	// Enables the Kerberos debug logs.
+	Debug *bool `property:"debug" json:"debug,omitempty"`
+
+	keytab   *utilResource.Config
+	krb5Conf *utilResource.Config
+}
+
+func newKerberosTrait() Trait {
+	return &kerberosTrait{
+		// Must run after the container trait, and before the JVM trait, that builds the container command
+		BaseTrait: NewBaseTrait(kerberosTraitID, 1640),
+	}
+}
+
+func (t *kerberosTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if t.Principal == "" {
+		return false, fmt.Errorf("the kerberos principal is required")
+	}
+
+	if t.Keytab == "" {
+		return false, fmt.Errorf("the kerberos keytab is required")
+	}
+	keytab, err := utilResource.ParseConfig(t.Keytab)
+	if err != nil {
+		return false, err
+	}
+	if keytab.StorageType() != utilResource.StorageTypeSecret {
+		return false, fmt.Errorf("unsupported kerberos keytab %s, it must be a secret", t.Keytab)
+	}
+	t.keytab = keytab
+
+	switch {
+	case t.Krb5Conf != "":
+		conf, err := utilResource.ParseConfig(t.Krb5Conf)
+		if err != nil {
+			return false, err
+		}
+		if conf.StorageType() != utilResource.StorageTypeConfigmap && conf.StorageType() != utilResource.StorageTypeSecret {
+			return false, fmt.Errorf("unsupported kerberos configuration %s, it must be a configmap or a secret", t.Krb5Conf)
+		}
+		t.krb5Conf = conf
+	case t.Realm == "" || len(t.KDC) == 0:
+		return false, fmt.Errorf("either the krb5-conf option, or the realm and kdc options, are required")
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+// RequiresTraits makes sure the integration container is configured before the Kerberos configuration is mounted into it.
+func (t *kerberosTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
+func (t *kerberosTrait) Apply(e *Environment) error {
+	container := e.GetIntegrationContainer()
+	if container == nil {
+		return fmt.Errorf("unable to find integration container: %s", e.Integration.Name)
+	}
+
+	configMap := t.getConfigMapFor(e)
+	e.Resources.Add(configMap)
+
+	volumes := []corev1.Volume{
+		*getVolume(kerberosConfigVolumeName, "configmap", configMap.Name, "", ""),
+		*getVolume(kerberosKeytabVolumeName, "secret", t.keytab.Name(), "", ""),
+	}
+	keytabKey := t.keytab.Key()
+	if keytabKey == "" {
+		keytabKey = krb5KeytabFile
+	}
+	mounts := []corev1.VolumeMount{
+		*getMount(kerberosConfigVolumeName, path.Join(kerberosMountPath, jaasConfFile), jaasConfFile, true),
+		*getMount(kerberosKeytabVolumeName, path.Join(kerberosMountPath, krb5KeytabFile), keytabKey, true),
+	}
+	if t.krb5Conf != nil {
+		key := t.krb5Conf.Key()
+		if key == "" {
+			key = krb5ConfFile
+		}
+		volumes = append(volumes, *getVolume(kerberosKrb5VolumeName, string(t.krb5Conf.StorageType()), t.krb5Conf.Name(), "", ""))
+		mounts = append(mounts, *getMount(kerberosKrb5VolumeName, path.Join(kerberosMountPath, krb5ConfFile), key, true))
+	} else {
+		mounts = append(mounts, *getMount(kerberosConfigVolumeName, path.Join(kerberosMountPath, krb5ConfFile), krb5ConfFile, true))
+	}
+
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		spec.Volumes = append(spec.Volumes, volumes...)
+	})
+	container.VolumeMounts = append(container.VolumeMounts, mounts...)
+
+	// The JVM trait appends its own options to the arguments contributed by the other traits
+	container.Args = append(container.Args,
+		"-Djava.security.krb5.conf="+path.Join(kerberosMountPath, krb5ConfFile),
+		"-Djava.security.auth.login.config="+path.Join(kerberosMountPath, jaasConfFile),
+		// Lets the GSS-API mechanism use the JAAS login contexts, e.g., for the SPNEGO authentication to HDFS
+		"-Djavax.security.auth.useSubjectCredsOnly=false",
+	)
+	if pointer.BoolDeref(t.Debug, false) {
+		container.Args = append(container.Args, "-Dsun.security.krb5.debug=true")
+	}
+
+	return nil
+}
+
+func (t *kerberosTrait) getConfigMapFor(e *Environment) *corev1.ConfigMap {
+	data := map[string]string{
+		jaasConfFile: t.jaasConf(),
+	}
+	if t.krb5Conf == nil {
+		data[krb5ConfFile] = t.generateKrb5Conf()
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-kerberos", e.Integration.Name),
+			Namespace: e.Integration.Namespace,
+			Labels: map[string]string{
+				v1.IntegrationLabel: e.Integration.Name,
+			},
+		},
+		Data: data,
+	}
+}
+
+func (t *kerberosTrait) jaasConf() string {
+	contexts := t.LoginContexts
+	if len(contexts) == 0 {
+		contexts = defaultLoginContexts
+	}
+
+	var sb strings.Builder
+	for _, c := range contexts {
+		fmt.Fprintf(&sb, "%s {\n", c)
+		sb.WriteString("  com.sun.security.auth.module.Krb5LoginModule required\n")
+		sb.WriteString("  useKeyTab=true\n")
+		sb.WriteString("  storeKey=true\n")
+		sb.WriteString("  doNotPrompt=true\n")
+		fmt.Fprintf(&sb, "  keyTab=\"%s\"\n", path.Join(kerberosMountPath, krb5KeytabFile))
+		fmt.Fprintf(&sb, "  principal=\"%s\";\n", t.Principal)
+		sb.WriteString("};\n")
+	}
+	return sb.String()
+}
+
+func (t *kerberosTrait) generateKrb5Conf() string {
+	var sb strings.Builder
+	sb.WriteString("[libdefaults]\n")
+	fmt.Fprintf(&sb, "  default_realm = %s\n", t.Realm)
+	sb.WriteString("  dns_lookup_kdc = false\n")
+	sb.WriteString("  rdns = false\n")
+	sb.WriteString("\n[realms]\n")
+	fmt.Fprintf(&sb, "  %s = {\n", t.Realm)
+	for _, kdc := range t.KDC {
+		fmt.Fprintf(&sb, "    kdc = %s\n", kdc)
+	}
+	sb.WriteString("  }\n")
+	return sb.String()
+}
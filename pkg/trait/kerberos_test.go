@@ -0,0 +1,105 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestConfigureKerberosTraitWithInvalidOptions(t *testing.T) {
+	kerberosTrait, environment := createKerberosTest()
+
+	kerberosTrait.Keytab = "configmap:keytab"
+	configured, err := kerberosTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	kerberosTrait.Keytab = "secret:keytab"
+	kerberosTrait.KDC = nil
+	configured, err = kerberosTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestKerberosGeneratedConfiguration(t *testing.T) {
+	kerberosTrait, environment := createKerberosTest()
+	kerberosTrait.Debug = pointer.Bool(true)
+
+	configured, err := kerberosTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, kerberosTrait.Apply(environment))
+
+	configMap := environment.Resources.GetConfigMap(func(cm *corev1.ConfigMap) bool {
+		return cm.Name == "integration-name-kerberos"
+	})
+	assert.NotNil(t, configMap)
+	assert.Contains(t, configMap.Data[krb5ConfFile], "default_realm = EXAMPLE.COM")
+	assert.Contains(t, configMap.Data[krb5ConfFile], "kdc = kdc.example.com:88")
+	assert.Contains(t, configMap.Data[jaasConfFile], "KafkaClient {")
+	assert.Contains(t, configMap.Data[jaasConfFile], `principal="camel@EXAMPLE.COM";`)
+
+	spec := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 2)
+	assert.Equal(t, "keytab", spec.Volumes[1].Secret.SecretName)
+	container := spec.Containers[0]
+	assert.Len(t, container.VolumeMounts, 3)
+	assert.Equal(t, corev1.VolumeMount{Name: kerberosKeytabVolumeName, MountPath: "/etc/camel/kerberos/krb5.keytab", SubPath: krb5KeytabFile, ReadOnly: true},
+		container.VolumeMounts[1])
+	assert.Contains(t, container.Args, "-Djava.security.krb5.conf=/etc/camel/kerberos/krb5.conf")
+	assert.Contains(t, container.Args, "-Djava.security.auth.login.config=/etc/camel/kerberos/jaas.conf")
+	assert.Contains(t, container.Args, "-Dsun.security.krb5.debug=true")
+}
+
+func TestKerberosMountedConfiguration(t *testing.T) {
+	kerberosTrait, environment := createKerberosTest()
+	kerberosTrait.Krb5Conf = "configmap:kerberos/corporate.conf"
+	kerberosTrait.LoginContexts = []string{"KafkaClient"}
+
+	configured, err := kerberosTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, kerberosTrait.Apply(environment))
+
+	configMap := environment.Resources.GetConfigMap(func(cm *corev1.ConfigMap) bool { return true })
+	assert.NotContains(t, configMap.Data, krb5ConfFile)
+	assert.NotContains(t, configMap.Data[jaasConfFile], "SQLJDBCDriver")
+
+	spec := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 3)
+	assert.Equal(t, "kerberos", spec.Volumes[2].ConfigMap.Name)
+	assert.Equal(t, "corporate.conf", spec.Containers[0].VolumeMounts[2].SubPath)
+}
+
+func createKerberosTest() (*kerberosTrait, *Environment) {
+	_, environment := createStorageTest(1)
+
+	trait, _ := newKerberosTrait().(*kerberosTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.Principal = "camel@EXAMPLE.COM"
+	trait.Keytab = "secret:keytab"
+	trait.Realm = "EXAMPLE.COM"
+	trait.KDC = []string{"kdc.example.com:88"}
+
+	return trait, environment
+}
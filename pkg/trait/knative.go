@@ -29,11 +29,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/pointer"
 
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
-	eventing "knative.dev/eventing/pkg/apis/eventing/v1"
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
@@ -53,6 +53,15 @@ import (
 //
 // The trait is enabled by default when the Knative profile is active.
 //
+// The visibility of the channels and endpoints the integration consumes from can be set individually. As they are all
+// served by the integration Knative Service, the Service is only made cluster-local when all of them are `cluster-local`.
+// Knative DomainMappings can also be requested, to expose the Service on custom domains.
+//
+// The Triggers subscribing the integration to the events of a Broker filter them on the event type, and on the
+// CloudEvents attributes set by the `filter.<attribute>` parameters of the event URI, e.g.,
+// `knative:event/order.created?filter.source=orders`, so that the events are filtered by the Broker rather than by
+// the integration.
+//
 // +camel-k:trait=knative.
 type knativeTrait struct {
 	BaseTrait `property:",squash"`
@@ -85,10 +94,26 @@ type knativeTrait struct {
 	SinkBinding *bool `property:"sink-binding" json:"sinkBinding,omitempty"`
 	// Enable automatic discovery of all trait properties.
 	Auto *bool `property:"auto" json:"auto,omitempty"`
+	// The visibility of the channels and endpoints the integration consumes from, in the `<name>=<visibility>` format,
+	// where the visibility is either `cluster-local` or `external` (default), e.g., `orders=cluster-local`.
+	// It requires the integration to be deployed as a Knative Service.
+	Visibility []string `property:"visibility" json:"visibility,omitempty"`
+	// The custom domains the integration Knative Service is exposed on, with a Knative DomainMapping created for each of them,
+	// e.g., `orders.example.com`. The domains are mapped even if the Service is cluster-local.
+	// It requires the integration to be deployed as a Knative Service.
+	DomainMappings []string `property:"domain-mappings" json:"domainMappings,omitempty"`
+	// The CloudEvents SQL expressions filtering the events the integration consumes from a Broker, in the
+	// `<event type>=<expression>` format, e.g., `order.created=amount > 100`, where the event type is `default` for
+	// the events of any type. They are set on the Triggers of the event type, in addition to the attribute filters.
+	// It requires the `new-trigger-filters` experimental feature of Knative Eventing.
+	SQLFilters []string `property:"sql-filters" json:"sqlFilters,omitempty"`
 }
 
 const (
 	knativeHistoryHeader = "ce-knativehistory"
+
+	knativeVisibilityClusterLocal = "cluster-local"
+	knativeVisibilityExternal     = "external"
 )
 
 func newKnativeTrait() Trait {
@@ -202,6 +227,18 @@ func (t *knativeTrait) Configure(e *Environment) (bool, error) {
 		}
 	}
 
+	if _, err := t.isClusterLocal(); err != nil {
+		return false, err
+	}
+	if _, err := t.sqlFilters(); err != nil {
+		return false, err
+	}
+	for _, domain := range t.DomainMappings {
+		if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+			return false, fmt.Errorf("invalid knative domain mapping %s: %s", domain, strings.Join(errs, ", "))
+		}
+	}
+
 	return true, nil
 }
 
@@ -244,6 +281,10 @@ func (t *knativeTrait) Apply(e *Environment) error {
 		}
 
 		envvar.SetVal(&e.EnvVars, "CAMEL_KNATIVE_CONFIGURATION", conf)
+
+		if err := t.configureExposure(e); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -369,8 +410,13 @@ func (t *knativeTrait) configureEndpoints(e *Environment, env *knativeapi.CamelE
 }
 
 func (t *knativeTrait) configureEvents(e *Environment, env *knativeapi.CamelEnvironment) error {
+	sqlFilters, err := t.sqlFilters()
+	if err != nil {
+		return err
+	}
+
 	// Sources
-	err := t.withServiceDo(false, e, env, t.EventSources, knativeapi.CamelServiceTypeEvent, knativeapi.CamelEndpointKindSource,
+	err = t.withServiceDo(false, e, env, t.EventSources, knativeapi.CamelServiceTypeEvent, knativeapi.CamelEndpointKindSource,
 		func(ref *corev1.ObjectReference, serviceURI string, _ func() (*url.URL, error)) error {
 			// Iterate over all, without skipping duplicates
 			eventType := knativeutil.ExtractEventType(serviceURI)
@@ -379,7 +425,10 @@ func (t *knativeTrait) configureEvents(e *Environment, env *knativeapi.CamelEnvi
 				serviceName = "default"
 			}
 			servicePath := fmt.Sprintf("/events/%s", eventType)
-			t.createTrigger(e, ref, eventType, servicePath)
+			filters := knativeutil.ExtractEventFilters(serviceURI)
+			if err := t.createTrigger(e, ref, eventType, filters, sqlFilters[serviceName], servicePath); err != nil {
+				return err
+			}
 
 			if !env.ContainsService(serviceName, knativeapi.CamelEndpointKindSource, knativeapi.CamelServiceTypeEvent, ref.APIVersion, ref.Kind) {
 				svc := knativeapi.CamelServiceDefinition{
@@ -426,6 +475,90 @@ func (t *knativeTrait) configureEvents(e *Environment, env *knativeapi.CamelEnvi
 	return nil
 }
 
+func (t *knativeTrait) configureExposure(e *Environment) error {
+	if len(t.Visibility) == 0 && len(t.DomainMappings) == 0 {
+		return nil
+	}
+
+	strategy, err := e.DetermineControllerStrategy()
+	if err != nil {
+		return err
+	}
+	if strategy != ControllerStrategyKnativeService {
+		return fmt.Errorf("knative visibility and domain mappings require the %s controller strategy, not %s",
+			ControllerStrategyKnativeService, strategy)
+	}
+
+	for _, domain := range t.DomainMappings {
+		e.Resources.Add(knativeutil.CreateDomainMapping(e.Integration.Namespace, domain, e.Integration.Name))
+	}
+
+	clusterLocal, err := t.isClusterLocal()
+	if err != nil {
+		return err
+	}
+	if clusterLocal {
+		// The Knative Service is generated afterwards by the knative-service trait
+		e.PostProcessors = append(e.PostProcessors, func(env *Environment) error {
+			env.Resources.VisitKnativeService(func(s *serving.Service) {
+				if s.Name != env.Integration.Name {
+					return
+				}
+				if s.Labels == nil {
+					s.Labels = make(map[string]string)
+				}
+				s.Labels[knativeutil.VisibilityLabel] = knativeVisibilityClusterLocal
+			})
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// isClusterLocal returns whether all the channels and endpoints the integration consumes from are cluster-local.
+func (t *knativeTrait) isClusterLocal() (bool, error) {
+	if len(t.Visibility) == 0 {
+		return false, nil
+	}
+
+	names := make([]string, 0)
+	services := t.extractServices(t.ChannelSources, knativeapi.CamelServiceTypeChannel)
+	services = append(services, t.extractServices(t.EndpointSources, knativeapi.CamelServiceTypeEndpoint)...)
+	for _, service := range services {
+		ref, err := knativeutil.ExtractObjectReference(service)
+		if err != nil {
+			return false, err
+		}
+		util.StringSliceUniqueAdd(&names, ref.Name)
+	}
+
+	visibilities := make(map[string]string, len(t.Visibility))
+	for _, entry := range t.Visibility {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return false, fmt.Errorf("invalid knative visibility %s, expected <name>=<visibility>", entry)
+		}
+		name, visibility := parts[0], parts[1]
+		if visibility != knativeVisibilityClusterLocal && visibility != knativeVisibilityExternal {
+			return false, fmt.Errorf("invalid knative visibility %s for %s, expected %s or %s",
+				visibility, name, knativeVisibilityClusterLocal, knativeVisibilityExternal)
+		}
+		if !util.StringSliceExists(names, name) {
+			return false, fmt.Errorf("invalid knative visibility for %s: the integration consumes from no channel or endpoint with that name", name)
+		}
+		visibilities[name] = visibility
+	}
+
+	for _, name := range names {
+		if visibilities[name] != knativeVisibilityClusterLocal {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (t *knativeTrait) isSinkBindingAllowed(e *Environment) bool {
 	services := t.extractServices(t.ChannelSinks, knativeapi.CamelServiceTypeChannel)
 	services = append(services, t.extractServices(t.EndpointSinks, knativeapi.CamelServiceTypeEndpoint)...)
@@ -527,20 +660,41 @@ func (t *knativeTrait) configureSinkBinding(e *Environment, env *knativeapi.Came
 	return err
 }
 
-func (t *knativeTrait) createTrigger(e *Environment, ref *corev1.ObjectReference, eventType string, path string) {
-	// TODO extend to additional filters too, to filter them at source and not at destination
-	found := e.Resources.HasKnativeTrigger(func(trigger *eventing.Trigger) bool {
-		return trigger.Spec.Broker == ref.Name &&
-			trigger.Spec.Filter != nil &&
-			trigger.Spec.Filter.Attributes["type"] == eventType // can be also missing
-	})
-	if !found {
-		if ref.Namespace == "" {
-			ref.Namespace = e.Integration.Namespace
+func (t *knativeTrait) createTrigger(e *Environment, ref *corev1.ObjectReference, eventType string, filters map[string]string, expression string, path string) error {
+	if ref.Namespace == "" {
+		ref.Namespace = e.Integration.Namespace
+	}
+	trigger := knativeutil.CreateTrigger(*ref, e.Integration.Name, eventType, filters, path)
+	// The Triggers are named after the Broker, the event type and the attribute filters,
+	// and may be unstructured, when they filter on a SQL expression
+	for _, o := range e.Resources.Items() {
+		if o.GetObjectKind().GroupVersionKind().Kind == "Trigger" && o.GetNamespace() == trigger.Namespace && o.GetName() == trigger.Name {
+			return nil
 		}
-		trigger := knativeutil.CreateTrigger(*ref, e.Integration.Name, eventType, path)
+	}
+	if expression == "" {
 		e.Resources.Add(trigger)
+		return nil
+	}
+	u, err := knativeutil.WithSQLFilter(trigger, expression)
+	if err != nil {
+		return err
+	}
+	e.Resources.Add(u)
+	return nil
+}
+
+// sqlFilters returns the CloudEvents SQL expressions by event type.
+func (t *knativeTrait) sqlFilters() (map[string]string, error) {
+	filters := make(map[string]string, len(t.SQLFilters))
+	for _, f := range t.SQLFilters {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" || strings.TrimSpace(kv[1]) == "" {
+			return nil, fmt.Errorf("invalid knative SQL filter %q, it must be in the <event type>=<expression> format", f)
+		}
+		filters[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
 	}
+	return filters, nil
 }
 
 func (t *knativeTrait) ifServiceMissingDo(
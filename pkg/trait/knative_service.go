@@ -20,13 +20,18 @@ package trait
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/metadata"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
@@ -37,13 +42,19 @@ const (
 	knativeServiceTraitID = "knative-service"
 
 	// Auto-scaling annotations.
-	knativeServingClassAnnotation    = "autoscaling.knative.dev/class"
-	knativeServingMetricAnnotation   = "autoscaling.knative.dev/metric"
-	knativeServingTargetAnnotation   = "autoscaling.knative.dev/target"
-	knativeServingMinScaleAnnotation = "autoscaling.knative.dev/minScale"
-	knativeServingMaxScaleAnnotation = "autoscaling.knative.dev/maxScale"
+	knativeServingClassAnnotation        = "autoscaling.knative.dev/class"
+	knativeServingMetricAnnotation       = "autoscaling.knative.dev/metric"
+	knativeServingTargetAnnotation       = "autoscaling.knative.dev/target"
+	knativeServingMinScaleAnnotation     = "autoscaling.knative.dev/minScale"
+	knativeServingMaxScaleAnnotation     = "autoscaling.knative.dev/maxScale"
+	knativeServingInitialScaleAnnotation = "autoscaling.knative.dev/initial-scale"
 	// Rollout annotation.
 	knativeServingRolloutDurationAnnotation = "serving.knative.dev/rolloutDuration"
+
+	// The tag of the latest Revision, while the traffic is pinned to the previous one
+	knativeLatestRevisionTag = "latest"
+	// The delay after which the traffic pinned to the previous Revision is checked again
+	knativePinnedTrafficRecheckDelay = 10 * time.Second
 )
 
 // The Knative Service trait allows configuring options when running the Integration as a Knative service, instead of
@@ -81,6 +92,20 @@ type knativeServiceTrait struct {
 	// It's disabled by default and must be expressed as a Golang `time.Duration` string representation,
 	// rounded to a second precision.
 	RolloutDuration string `property:"rollout-duration" json:"rolloutDuration,omitempty"`
+	// A list of time windows during which the operator keeps a minimum number of warm Pods running for the integration,
+	// so that latency-sensitive requests don't wait for the integration to scale from zero, e.g., `Mon-Fri 08:00-18:00`.
+	//
+	// Each window is expressed as an optional day, or range of days, of the week (`Mon`, `Tue`, `Wed`, `Thu`, `Fri`, `Sat`, `Sun`),
+	// followed by a range of hours in the `HH:MM-HH:MM` format. A range of hours ending before it starts spans midnight.
+	WarmWindows []string `property:"warm-windows" json:"warmWindows,omitempty"`
+	// The minimum number of Pods kept running during the warm windows. It's **one** by default.
+	WarmMinScale *int `property:"warm-min-scale" json:"warmMinScale,omitempty"`
+	// The time zone the warm windows are expressed in, as an IANA Time Zone database name (e.g. `Europe/Rome`). It's `UTC` by default.
+	WarmTimeZone string `property:"warm-time-zone" json:"warmTimeZone,omitempty"`
+	// Rolls out the configuration changes, that do not change the container image, as a new Revision, and keeps
+	// routing the traffic to the previous Revision until the new one is ready, with as many Pods as the previous one
+	// is scaled to at minimum.
+	ZeroDowntime *bool `property:"zero-downtime" json:"zeroDowntime,omitempty"`
 	// Automatically deploy the integration as Knative service when all conditions hold:
 	//
 	// * Integration is using the Knative profile
@@ -172,6 +197,18 @@ func (t *knativeServiceTrait) Configure(e *Environment) (bool, error) {
 		}
 	}
 
+	if len(t.WarmWindows) > 0 {
+		if err := t.configureWarmWindows(e, time.Now()); err != nil {
+			e.Integration.Status.SetErrorCondition(
+				v1.IntegrationConditionKnativeServiceAvailable,
+				v1.IntegrationConditionKnativeServiceNotAvailableReason,
+				err,
+			)
+
+			return false, err
+		}
+	}
+
 	if e.IntegrationInPhase(v1.IntegrationPhaseRunning, v1.IntegrationPhaseError) {
 		condition := e.Integration.Status.GetCondition(v1.IntegrationConditionKnativeServiceAvailable)
 		return condition != nil && condition.Status == corev1.ConditionTrue, nil
@@ -187,6 +224,12 @@ func (t *knativeServiceTrait) Apply(e *Environment) error {
 	}
 	e.Resources.Add(ksvc)
 
+	if pointer.BoolDeref(t.ZeroDowntime, false) {
+		e.PostProcessors = append(e.PostProcessors, func(env *Environment) error {
+			return t.configureZeroDowntime(env, ksvc)
+		})
+	}
+
 	e.Integration.Status.SetCondition(
 		v1.IntegrationConditionKnativeServiceAvailable,
 		corev1.ConditionTrue,
@@ -338,3 +381,211 @@ func (t *knativeServiceTrait) getServiceFor(e *Environment) (*serving.Service, e
 
 	return &svc, nil
 }
+
+// configureZeroDowntime creates a new Revision when the ConfigMaps and Secrets of the Integration change, and pins
+// the traffic to the latest ready Revision, until the new one becomes ready.
+func (t *knativeServiceTrait) configureZeroDowntime(e *Environment, ksvc *serving.Service) error {
+	template := &ksvc.Spec.Template
+	checksum, err := computeConfigChecksum(e)
+	if err != nil {
+		return err
+	}
+	if checksum != "" {
+		template.Annotations = withAnnotation(template.Annotations, configChecksumAnnotation, checksum)
+	}
+	if scale := t.minimumScale(e); scale > 1 {
+		// The new Revision only becomes ready once it's scaled as the previous one
+		template.Annotations = withAnnotation(template.Annotations, knativeServingInitialScaleAnnotation, strconv.Itoa(scale))
+	}
+
+	// Let the traffic be managed by the rollout trait, when it's enabled
+	if ksvc.Spec.Traffic != nil {
+		return nil
+	}
+
+	live := serving.Service{}
+	if err := t.Client.Get(e.Ctx, ctrl.ObjectKeyFromObject(ksvc), &live); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	ready := live.Status.LatestReadyRevisionName
+	if ready == "" {
+		return nil
+	}
+	pending := live.Status.ObservedGeneration < live.Generation ||
+		live.Status.LatestCreatedRevisionName != ready ||
+		live.Spec.Template.Annotations[configChecksumAnnotation] != checksum
+	if pending {
+		ksvc.Spec.Traffic = []serving.TrafficTarget{
+			{
+				RevisionName: ready,
+				Percent:      pointer.Int64(100),
+			},
+			{
+				LatestRevision: pointer.Bool(true),
+				Percent:        pointer.Int64(0),
+				Tag:            knativeLatestRevisionTag,
+			},
+		}
+		e.RequeueNoLaterThan(knativePinnedTrafficRecheckDelay)
+	}
+
+	return nil
+}
+
+// minimumScale returns the minimum number of Pods the Revisions of the Integration are scaled to.
+func (t *knativeServiceTrait) minimumScale(e *Environment) int {
+	if replicas := e.Integration.Spec.Replicas; replicas != nil {
+		return int(*replicas)
+	}
+	return pointer.IntDeref(t.MinScale, 0)
+}
+
+// configureWarmWindows raises the minimum scale when the current time falls within one of the warm windows,
+// and makes sure the integration is reconciled again when the next window starts or ends.
+func (t *knativeServiceTrait) configureWarmWindows(e *Environment, now time.Time) error {
+	location := time.UTC
+	if t.WarmTimeZone != "" {
+		l, err := time.LoadLocation(t.WarmTimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid warm time zone %q: %w", t.WarmTimeZone, err)
+		}
+		location = l
+	}
+
+	windows := make([]warmWindow, 0, len(t.WarmWindows))
+	for _, w := range t.WarmWindows {
+		window, err := parseWarmWindow(w)
+		if err != nil {
+			return err
+		}
+		windows = append(windows, window)
+	}
+
+	now = now.In(location)
+	warm := inWarmWindows(windows, now)
+	if warm {
+		minScale := pointer.IntDeref(t.WarmMinScale, 1)
+		if t.MinScale == nil || *t.MinScale < minScale {
+			t.MinScale = &minScale
+		}
+	}
+
+	// Look up the next time the integration enters, or leaves, a warm window
+	next := now.Truncate(time.Minute).Add(time.Minute)
+	for limit := next.Add(7 * 24 * time.Hour); next.Before(limit); next = next.Add(time.Minute) {
+		if inWarmWindows(windows, next) != warm {
+			e.RequeueNoLaterThan(next.Sub(now))
+			break
+		}
+	}
+
+	return nil
+}
+
+var weekDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+type warmWindow struct {
+	// the days of the week the window starts on
+	days [7]bool
+	// the start and end of the window, in minutes since midnight
+	start int
+	end   int
+}
+
+func parseWarmWindow(w string) (warmWindow, error) {
+	window := warmWindow{}
+
+	fields := strings.Fields(w)
+	var days, hours string
+	switch len(fields) {
+	case 1:
+		days, hours = "Mon-Sun", fields[0]
+	case 2:
+		days, hours = fields[0], fields[1]
+	default:
+		return window, fmt.Errorf("invalid warm window %q, expected format is [days] HH:MM-HH:MM", w)
+	}
+
+	from, to, err := splitRange(days)
+	if err != nil {
+		return window, fmt.Errorf("invalid days in warm window %q: %w", w, err)
+	}
+	first, ok := weekDays[strings.ToLower(from)]
+	if !ok {
+		return window, fmt.Errorf("invalid day %q in warm window %q", from, w)
+	}
+	last, ok := weekDays[strings.ToLower(to)]
+	if !ok {
+		return window, fmt.Errorf("invalid day %q in warm window %q", to, w)
+	}
+	for d := first; ; d = (d + 1) % 7 {
+		window.days[d] = true
+		if d == last {
+			break
+		}
+	}
+
+	from, to, err = splitRange(hours)
+	if err != nil || !strings.Contains(hours, "-") {
+		return window, fmt.Errorf("invalid hours in warm window %q, expected format is HH:MM-HH:MM", w)
+	}
+	if window.start, err = parseTimeOfDay(from); err != nil {
+		return window, fmt.Errorf("invalid hours in warm window %q: %w", w, err)
+	}
+	if window.end, err = parseTimeOfDay(to); err != nil {
+		return window, fmt.Errorf("invalid hours in warm window %q: %w", w, err)
+	}
+
+	return window, nil
+}
+
+// splitRange splits a range in the `from-to` format, a single value being a range on its own.
+func splitRange(r string) (string, string, error) {
+	parts := strings.Split(r, "-")
+	switch len(parts) {
+	case 1:
+		return parts[0], parts[0], nil
+	case 2:
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("invalid range %q", r)
+	}
+}
+
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (w warmWindow) contains(t time.Time) bool {
+	minutes := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return w.days[t.Weekday()] && minutes >= w.start && minutes < w.end
+	}
+	// The window spans midnight
+	yesterday := (t.Weekday() + 6) % 7
+	return w.days[t.Weekday()] && minutes >= w.start || w.days[yesterday] && minutes < w.end
+}
+
+func inWarmWindows(windows []warmWindow, t time.Time) bool {
+	for _, w := range windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -18,7 +18,9 @@ limitations under the License.
 package trait
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -395,3 +397,146 @@ func TestKnativeServiceWithRollout(t *testing.T) {
 
 	assert.Equal(t, ksvc.Annotations[knativeServingRolloutDurationAnnotation], "60s")
 }
+
+func TestKnativeServiceZeroDowntime(t *testing.T) {
+	configmap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   KnativeServiceTestName + "-application-properties",
+			Labels: map[string]string{v1.IntegrationLabel: KnativeServiceTestName},
+		},
+		Data: map[string]string{"application.properties": "foo=bar"},
+	}
+	newEnvironment := func() *Environment {
+		replicas := int32(3)
+		e := &Environment{
+			Ctx: context.TODO(),
+			Integration: &v1.Integration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      KnativeServiceTestName,
+					Namespace: KnativeServiceTestNamespace,
+				},
+				Spec: v1.IntegrationSpec{
+					Replicas: &replicas,
+				},
+			},
+			Resources: kubernetes.NewCollection(),
+		}
+		e.Resources.Add(configmap.DeepCopy())
+		return e
+	}
+	newService := func() *serving.Service {
+		return &serving.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: serving.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      KnativeServiceTestName,
+				Namespace: KnativeServiceTestNamespace,
+			},
+		}
+	}
+
+	// The first Revision is deployed as is
+	trait, _ := newKnativeServiceTrait().(*knativeServiceTrait)
+	trait.Client, _ = test.NewFakeClient()
+	environment := newEnvironment()
+	ksvc := newService()
+
+	assert.Nil(t, trait.configureZeroDowntime(environment, ksvc))
+	checksum := ksvc.Spec.Template.Annotations[configChecksumAnnotation]
+	assert.NotEmpty(t, checksum)
+	assert.Equal(t, "3", ksvc.Spec.Template.Annotations[knativeServingInitialScaleAnnotation])
+	assert.Nil(t, ksvc.Spec.Traffic)
+
+	// The traffic is pinned to the ready Revision when the configuration changes
+	live := newService()
+	live.Spec.Template.Annotations = map[string]string{configChecksumAnnotation: checksum}
+	live.Status.LatestReadyRevisionName = KnativeServiceTestName + "-00001"
+	live.Status.LatestCreatedRevisionName = KnativeServiceTestName + "-00001"
+	trait.Client, _ = test.NewFakeClient(live)
+	environment = newEnvironment()
+	environment.Resources.VisitConfigMap(func(cm *corev1.ConfigMap) {
+		cm.Data["application.properties"] = "foo=baz"
+	})
+	ksvc = newService()
+
+	assert.Nil(t, trait.configureZeroDowntime(environment, ksvc))
+	assert.NotEqual(t, checksum, ksvc.Spec.Template.Annotations[configChecksumAnnotation])
+	assert.Len(t, ksvc.Spec.Traffic, 2)
+	assert.Equal(t, KnativeServiceTestName+"-00001", ksvc.Spec.Traffic[0].RevisionName)
+	assert.Equal(t, int64(100), *ksvc.Spec.Traffic[0].Percent)
+	assert.True(t, *ksvc.Spec.Traffic[1].LatestRevision)
+	assert.Equal(t, int64(0), *ksvc.Spec.Traffic[1].Percent)
+	assert.Equal(t, knativePinnedTrafficRecheckDelay, environment.RequeueAfter)
+
+	// The traffic is routed to the latest Revision once it's ready
+	live.Spec.Template.Annotations[configChecksumAnnotation] = ksvc.Spec.Template.Annotations[configChecksumAnnotation]
+	live.Status.LatestReadyRevisionName = KnativeServiceTestName + "-00002"
+	live.Status.LatestCreatedRevisionName = KnativeServiceTestName + "-00002"
+	trait.Client, _ = test.NewFakeClient(live)
+	environment = newEnvironment()
+	environment.Resources.VisitConfigMap(func(cm *corev1.ConfigMap) {
+		cm.Data["application.properties"] = "foo=baz"
+	})
+	ksvc = newService()
+
+	assert.Nil(t, trait.configureZeroDowntime(environment, ksvc))
+	assert.Nil(t, ksvc.Spec.Traffic)
+}
+
+func TestKnativeServiceWarmWindows(t *testing.T) {
+	// Wednesday
+	now := time.Date(2022, time.June, 1, 10, 30, 0, 0, time.UTC)
+
+	trait, _ := newKnativeServiceTrait().(*knativeServiceTrait)
+	trait.WarmWindows = []string{"Mon-Fri 08:00-18:00"}
+	environment := Environment{}
+
+	assert.Nil(t, trait.configureWarmWindows(&environment, now))
+	assert.NotNil(t, trait.MinScale)
+	assert.Equal(t, 1, *trait.MinScale)
+	assert.Equal(t, 7*time.Hour+30*time.Minute, environment.RequeueAfter)
+
+	// Saturday
+	now = time.Date(2022, time.June, 4, 10, 0, 0, 0, time.UTC)
+
+	trait, _ = newKnativeServiceTrait().(*knativeServiceTrait)
+	trait.WarmWindows = []string{"Mon-Fri 08:00-18:00"}
+	environment = Environment{}
+
+	assert.Nil(t, trait.configureWarmWindows(&environment, now))
+	assert.Nil(t, trait.MinScale)
+	assert.Equal(t, 46*time.Hour, environment.RequeueAfter)
+}
+
+func TestKnativeServiceWarmWindowsOverMidnight(t *testing.T) {
+	// Saturday
+	now := time.Date(2022, time.June, 4, 1, 0, 0, 0, time.UTC)
+
+	trait, _ := newKnativeServiceTrait().(*knativeServiceTrait)
+	trait.WarmWindows = []string{"Fri 22:00-02:00"}
+	minScale := 2
+	trait.WarmMinScale = &minScale
+	environment := Environment{}
+
+	assert.Nil(t, trait.configureWarmWindows(&environment, now))
+	assert.NotNil(t, trait.MinScale)
+	assert.Equal(t, 2, *trait.MinScale)
+	assert.Equal(t, time.Hour, environment.RequeueAfter)
+}
+
+func TestKnativeServiceWarmWindowsInvalid(t *testing.T) {
+	for _, w := range []string{"Mon-Fri 8-18", "Mon-Foo 08:00-18:00", "Mon Tue 08:00-18:00", "08:00"} {
+		trait, _ := newKnativeServiceTrait().(*knativeServiceTrait)
+		trait.WarmWindows = []string{w}
+
+		assert.NotNil(t, trait.configureWarmWindows(&Environment{}, time.Now()), w)
+	}
+
+	trait, _ := newKnativeServiceTrait().(*knativeServiceTrait)
+	trait.WarmWindows = []string{"08:00-18:00"}
+	trait.WarmTimeZone = "Mars/Olympus_Mons"
+
+	assert.NotNil(t, trait.configureWarmWindows(&Environment{}, time.Now()))
+}
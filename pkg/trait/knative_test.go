@@ -24,6 +24,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventing "knative.dev/eventing/pkg/apis/eventing/v1"
@@ -31,12 +33,14 @@ import (
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	serving "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	knativeapi "github.com/apache/camel-k/pkg/apis/camel/v1/knative"
 	"github.com/apache/camel-k/pkg/client"
 	"github.com/apache/camel-k/pkg/util/camel"
 	"github.com/apache/camel-k/pkg/util/envvar"
+	knativeutil "github.com/apache/camel-k/pkg/util/knative"
 	k8sutils "github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/test"
 )
@@ -542,3 +546,215 @@ func NewFakeClient(namespace string) (client.Client, error) {
 		},
 	)
 }
+
+func TestKnativeVisibility(t *testing.T) {
+	source := v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name: "source.groovy",
+			Content: `
+				from('knative:endpoint/ep').log('${body}')
+				from('knative:channel/channel-source-1').log('${body}')
+			`,
+		},
+		Language: v1.LanguageGroovy,
+	}
+
+	tests := []struct {
+		name         string
+		visibility   []string
+		clusterLocal bool
+		err          string
+	}{
+		{
+			name:         "all cluster-local",
+			visibility:   []string{"ep=cluster-local", "channel-source-1=cluster-local"},
+			clusterLocal: true,
+		},
+		{
+			name:       "one external",
+			visibility: []string{"ep=external", "channel-source-1=cluster-local"},
+		},
+		{
+			name:       "one unset",
+			visibility: []string{"channel-source-1=cluster-local"},
+		},
+		{
+			name:       "unknown name",
+			visibility: []string{"unknown=cluster-local"},
+			err:        "invalid knative visibility for unknown: the integration consumes from no channel or endpoint with that name",
+		},
+		{
+			name:       "invalid visibility",
+			visibility: []string{"ep=public"},
+			err:        "invalid knative visibility public for ep, expected cluster-local or external",
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			environment := NewFakeEnvironment(t, source)
+			environment.Integration.Spec.Traits["knative"] = test.TraitSpecFromMap(t, map[string]interface{}{
+				"enabled":    true,
+				"visibility": tt.visibility,
+			})
+
+			c, err := NewFakeClient("ns")
+			assert.Nil(t, err)
+
+			err = NewCatalog(c).apply(&environment)
+			if tt.err != "" {
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			assert.Nil(t, err)
+
+			ksvc := environment.Resources.GetKnativeService(func(s *serving.Service) bool { return s.Name == "test" })
+			assert.NotNil(t, ksvc)
+			if tt.clusterLocal {
+				assert.Equal(t, "cluster-local", ksvc.Labels[knativeutil.VisibilityLabel])
+			} else {
+				assert.NotContains(t, ksvc.Labels, knativeutil.VisibilityLabel)
+			}
+		})
+	}
+}
+
+func TestKnativeDomainMappings(t *testing.T) {
+	environment := NewFakeEnvironment(t, v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    "source.groovy",
+			Content: `from('knative:endpoint/ep').log('${body}')`,
+		},
+		Language: v1.LanguageGroovy,
+	})
+	environment.Integration.Spec.Traits["knative"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"enabled":        true,
+		"domainMappings": []string{"orders.example.com", "orders.example.org"},
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(&environment)
+	assert.Nil(t, err)
+
+	domains := make([]string, 0)
+	environment.Resources.Visit(func(object runtime.Object) {
+		if mapping, ok := object.(*servingv1beta1.DomainMapping); ok {
+			assert.Equal(t, "ns", mapping.Namespace)
+			assert.Equal(t, "Service", mapping.Spec.Ref.Kind)
+			assert.Equal(t, "test", mapping.Spec.Ref.Name)
+			domains = append(domains, mapping.Name)
+		}
+	})
+	assert.ElementsMatch(t, []string{"orders.example.com", "orders.example.org"}, domains)
+}
+
+func TestKnativeInvalidDomainMapping(t *testing.T) {
+	environment := NewFakeEnvironment(t, v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    "source.groovy",
+			Content: `from('knative:endpoint/ep').log('${body}')`,
+		},
+		Language: v1.LanguageGroovy,
+	})
+	environment.Integration.Spec.Traits["knative"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"enabled":        true,
+		"domainMappings": []string{"Orders_Example"},
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(&environment)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "invalid knative domain mapping Orders_Example")
+}
+
+func TestKnativeEventTriggerFilters(t *testing.T) {
+	environment := NewFakeEnvironment(t, v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name: "source.groovy",
+			Content: `
+				from('knative:event/order.created?filter.source=orders&filter.ce-region=eu').log('${body}')
+				from('knative:event/order.created').log('${body}')
+			`,
+		},
+		Language: v1.LanguageGroovy,
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(&environment)
+	assert.Nil(t, err)
+
+	filters := make([]map[string]string, 0)
+	environment.Resources.VisitKnativeTrigger(func(trigger *eventing.Trigger) {
+		assert.Equal(t, "default", trigger.Spec.Broker)
+		assert.Equal(t, "/events/order.created", trigger.Spec.Subscriber.URI.Path)
+		filters = append(filters, trigger.Spec.Filter.Attributes)
+	})
+	assert.ElementsMatch(t, []map[string]string{
+		{"type": "order.created", "source": "orders", "region": "eu"},
+		{"type": "order.created"},
+	}, filters)
+}
+
+func TestKnativeEventTriggerSQLFilter(t *testing.T) {
+	environment := NewFakeEnvironment(t, v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    "source.groovy",
+			Content: `from('knative:event/order.created?filter.source=orders').log('${body}')`,
+		},
+		Language: v1.LanguageGroovy,
+	})
+	environment.Integration.Spec.Traits["knative"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"enabled":    true,
+		"sqlFilters": []string{"order.created=amount > 100"},
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(&environment)
+	assert.Nil(t, err)
+
+	var trigger *unstructured.Unstructured
+	for _, o := range environment.Resources.Items() {
+		if u, ok := o.(*unstructured.Unstructured); ok && u.GetKind() == "Trigger" {
+			trigger = u
+		}
+	}
+	assert.NotNil(t, trigger)
+	filters, found, err := unstructured.NestedSlice(trigger.Object, "spec", "filters")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"exact": map[string]interface{}{"type": "order.created", "source": "orders"}},
+		map[string]interface{}{"cesql": "amount > 100"},
+	}, filters)
+}
+
+func TestKnativeInvalidSQLFilter(t *testing.T) {
+	environment := NewFakeEnvironment(t, v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    "source.groovy",
+			Content: `from('knative:event/order.created').log('${body}')`,
+		},
+		Language: v1.LanguageGroovy,
+	})
+	environment.Integration.Spec.Traits["knative"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"enabled":    true,
+		"sqlFilters": []string{"amount > 100"},
+	})
+
+	c, err := NewFakeClient("ns")
+	assert.Nil(t, err)
+
+	err = NewCatalog(c).apply(&environment)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `invalid knative SQL filter "amount > 100"`)
+}
@@ -18,8 +18,12 @@ limitations under the License.
 package trait
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/utils/pointer"
 
+	"github.com/apache/camel-k/pkg/util"
 	"github.com/apache/camel-k/pkg/util/envvar"
 )
 
@@ -30,6 +34,21 @@ const (
 	envVarQuarkusLogConsoleJSON            = "QUARKUS_LOG_CONSOLE_JSON"
 	envVarQuarkusLogConsoleJSONPrettyPrint = "QUARKUS_LOG_CONSOLE_JSON_PRETTY_PRINT"
 	defaultLogLevel                        = "INFO"
+
+	logJSONFormatDefault = "default"
+	logJSONFormatECS     = "ecs"
+	logJSONFormatGCP     = "gcp"
+)
+
+var (
+	logLevels = []string{"OFF", "FATAL", "ERROR", "WARN", "INFO", "DEBUG", "TRACE", "ALL"}
+
+	// The names of the integration, kit and namespace fields, for each JSON format
+	logJSONContextFields = map[string][3]string{
+		logJSONFormatDefault: {"integration", "kit", "namespace"},
+		logJSONFormatECS:     {"labels.integration", "labels.kit", "orchestrator.namespace"},
+		logJSONFormatGCP:     {"integration", "kit", "namespace"},
+	}
 )
 
 // The Logging trait is used to configure Integration runtime logging options (such as color and format).
@@ -48,6 +67,15 @@ type loggingTrait struct {
 	JSON *bool `property:"json" json:"json,omitempty"`
 	// Enable "pretty printing" of the JSON logs
 	JSONPrettyPrint *bool `property:"json-pretty-print" json:"jsonPrettyPrint,omitempty"`
+	// The layout of the JSON logs, either `default`, `ecs` for the Elastic Common Schema, or `gcp` for Google Cloud Logging.
+	// It enables the JSON logs when it's set.
+	JSONFormat string `property:"json-format" json:"jsonFormat,omitempty"`
+	// Add the `integration`, `kit` and `namespace` fields to the JSON logs (default `true`).
+	JSONContextFields *bool `property:"json-context-fields" json:"jsonContextFields,omitempty"`
+	// A list of fields added to the JSON logs, in the form `name=value`
+	JSONFields []string `property:"json-fields" json:"jsonFields,omitempty"`
+	// A list of logging levels set for particular categories, in the form `category=level`, e.g., `org.apache.kafka=WARN`
+	Loggers []string `property:"loggers" json:"loggers,omitempty"`
 }
 
 func newLoggingTraitTrait() Trait {
@@ -62,6 +90,16 @@ func (l loggingTrait) Configure(environment *Environment) (bool, error) {
 		return false, nil
 	}
 
+	if l.JSONFormat != "" {
+		if _, ok := logJSONContextFields[l.JSONFormat]; !ok {
+			return false, fmt.Errorf("unsupported JSON log format %s, must be one of %s, %s or %s",
+				l.JSONFormat, logJSONFormatDefault, logJSONFormatECS, logJSONFormatGCP)
+		}
+	}
+	if _, err := l.getLoggingProperties(environment); err != nil {
+		return false, err
+	}
+
 	return environment.IntegrationInRunningPhases(), nil
 }
 
@@ -72,7 +110,7 @@ func (l loggingTrait) Apply(environment *Environment) error {
 		envvar.SetVal(&environment.EnvVars, envVarQuarkusLogConsoleFormat, l.Format)
 	}
 
-	if pointer.BoolDeref(l.JSON, false) {
+	if l.isJSON() {
 		envvar.SetVal(&environment.EnvVars, envVarQuarkusLogConsoleJSON, True)
 		if pointer.BoolDeref(l.JSONPrettyPrint, false) {
 			envvar.SetVal(&environment.EnvVars, envVarQuarkusLogConsoleJSONPrettyPrint, True)
@@ -86,5 +124,70 @@ func (l loggingTrait) Apply(environment *Environment) error {
 		}
 	}
 
+	properties, err := l.getLoggingProperties(environment)
+	if err != nil {
+		return err
+	}
+	if environment.ApplicationProperties == nil {
+		environment.ApplicationProperties = make(map[string]string)
+	}
+	for k, v := range properties {
+		environment.ApplicationProperties[k] = v
+	}
+
 	return nil
 }
+
+func (l loggingTrait) isJSON() bool {
+	return pointer.BoolDeref(l.JSON, l.JSONFormat != "")
+}
+
+// getLoggingProperties returns the application properties configuring the JSON log fields and the categories levels.
+func (l loggingTrait) getLoggingProperties(e *Environment) (map[string]string, error) {
+	properties := make(map[string]string)
+
+	for _, logger := range l.Loggers {
+		parts := strings.SplitN(logger, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || !util.StringSliceExists(logLevels, strings.ToUpper(parts[1])) {
+			return nil, fmt.Errorf("invalid logger %s, it must be in the form category=level, with level one of %s",
+				logger, strings.Join(logLevels, ", "))
+		}
+		properties[fmt.Sprintf("quarkus.log.category.\"%s\".level", parts[0])] = strings.ToUpper(parts[1])
+	}
+
+	if !l.isJSON() {
+		return properties, nil
+	}
+
+	format := l.JSONFormat
+	if format == "" {
+		format = logJSONFormatDefault
+	}
+	if format != logJSONFormatDefault {
+		properties["quarkus.log.console.json.log-format"] = format
+	}
+
+	fields := make(map[string]string)
+	if pointer.BoolDeref(l.JSONContextFields, true) && e.Integration != nil {
+		names := logJSONContextFields[format]
+		fields[names[0]] = e.Integration.Name
+		if e.Integration.Status.IntegrationKit != nil {
+			fields[names[1]] = e.Integration.Status.IntegrationKit.Name
+		}
+		fields[names[2]] = e.Integration.Namespace
+	}
+	for _, field := range l.JSONFields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid JSON log field %s, it must be in the form name=value", field)
+		}
+		fields[parts[0]] = parts[1]
+	}
+	for name, value := range fields {
+		if value != "" {
+			properties[fmt.Sprintf("quarkus.log.console.json.additional-field.\"%s\".value", name)] = value
+		}
+	}
+
+	return properties, nil
+}
@@ -19,6 +19,7 @@ package trait
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,7 @@ import (
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/envvar"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/test"
 )
@@ -200,3 +202,54 @@ func TestJsonLoggingTrait(t *testing.T) {
 	assert.True(t, logFormatIsNotDefault)
 	assert.NotEmpty(t, env.ExecutedTraits)
 }
+
+func TestStructuredJsonLoggingTrait(t *testing.T) {
+	env := createDefaultLoggingTestEnv(t)
+	env.Integration.Status.IntegrationKit = &corev1.ObjectReference{Name: "kit-123"}
+	env.Integration.Spec.Traits["logging"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"jsonFormat": "ecs",
+		"jsonFields": []string{"team=payments"},
+		"loggers":    []string{"org.apache.kafka=warn"},
+	})
+
+	err := NewLoggingTestCatalog().apply(env)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "true", envvar.Get(env.EnvVars, envVarQuarkusLogConsoleJSON).Value)
+	assert.Nil(t, envvar.Get(env.EnvVars, envVarQuarkusLogConsoleColor))
+	assert.Equal(t, "ecs", env.ApplicationProperties["quarkus.log.console.json.log-format"])
+	assert.Equal(t, "test", env.ApplicationProperties[`quarkus.log.console.json.additional-field."labels.integration".value`])
+	assert.Equal(t, "kit-123", env.ApplicationProperties[`quarkus.log.console.json.additional-field."labels.kit".value`])
+	assert.Equal(t, "ns", env.ApplicationProperties[`quarkus.log.console.json.additional-field."orchestrator.namespace".value`])
+	assert.Equal(t, "payments", env.ApplicationProperties[`quarkus.log.console.json.additional-field."team".value`])
+	assert.Equal(t, "WARN", env.ApplicationProperties[`quarkus.log.category."org.apache.kafka".level`])
+}
+
+func TestLoggersWithoutJsonLoggingTrait(t *testing.T) {
+	env := createDefaultLoggingTestEnv(t)
+	env.Integration.Spec.Traits["logging"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"loggers": []string{"org.apache.camel=DEBUG"},
+	})
+
+	err := NewLoggingTestCatalog().apply(env)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "false", envvar.Get(env.EnvVars, envVarQuarkusLogConsoleJSON).Value)
+	assert.Equal(t, "DEBUG", env.ApplicationProperties[`quarkus.log.category."org.apache.camel".level`])
+	for k := range env.ApplicationProperties {
+		assert.False(t, strings.HasPrefix(k, "quarkus.log.console.json"))
+	}
+}
+
+func TestInvalidLoggingTrait(t *testing.T) {
+	for _, config := range []map[string]interface{}{
+		{"jsonFormat": "logstash"},
+		{"loggers": []string{"org.apache.camel=VERBOSE"}},
+		{"json": true, "jsonFields": []string{"team"}},
+	} {
+		env := createDefaultLoggingTestEnv(t)
+		env.Integration.Spec.Traits["logging"] = test.TraitSpecFromMap(t, config)
+
+		assert.NotNil(t, NewLoggingTestCatalog().apply(env))
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	traitIDLabel   = "trait"
+	traitStepLabel = "step"
+
+	traitStepConfigure = "configure"
+	traitStepApply     = "apply"
+
+	// The duration above which a trait step is logged, as contributing to the reconcile latency
+	slowTraitThreshold = 500 * time.Millisecond
+)
+
+var traitDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: "camel_k_trait_duration_seconds",
+		Help: "Camel K trait configure and apply duration",
+		Buckets: []float64{
+			1 * time.Millisecond.Seconds(),
+			10 * time.Millisecond.Seconds(),
+			50 * time.Millisecond.Seconds(),
+			100 * time.Millisecond.Seconds(),
+			500 * time.Millisecond.Seconds(),
+			1 * time.Second.Seconds(),
+		},
+	},
+	[]string{
+		traitIDLabel,
+		traitStepLabel,
+	},
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(traitDuration)
+}
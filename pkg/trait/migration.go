@@ -0,0 +1,199 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	migrationTraitID       = "migration"
+	migrationContainerName = "migration"
+	migrationVolumeName    = "migration-scripts"
+
+	flywayTool    = "flyway"
+	liquibaseTool = "liquibase"
+
+	defaultFlywayImage    = "docker.io/flyway/flyway:8.5.13"
+	defaultLiquibaseImage = "docker.io/liquibase/liquibase:4.9.1"
+
+	flywayScriptsPath    = "/flyway/sql"
+	liquibaseScriptsPath = "/liquibase/changelog"
+
+	defaultLiquibaseChangelog = "changelog.xml"
+)
+
+// The Migration trait runs the database schema migrations, managed with Flyway or Liquibase, before the Integration starts.
+//
+// The migration scripts, or the Liquibase changelog files, are read from one or more ConfigMaps, e.g., created with
+// `kubectl create configmap my-migrations --from-file=sql/`. They are applied by an init container, that runs the
+// migration tool image against the configured database, so that the Integration container is only started once the
+// migrations succeed. A failing migration leaves the Pod in the `Init:Error` status, with the migration tool logs
+// available in the `migration` container.
+//
+// The migration tools hold a lock in the database while migrating, so that the replicas of the Integration
+// do not apply the same migrations concurrently.
+//
+// +camel-k:trait=migration.
+type migrationTrait struct {
+	BaseTrait `property:",squash"`
+	// The migration tool, either `flyway` or `liquibase` (default `flyway`).
+	Tool string `property:"tool" json:"tool,omitempty"`
+	// The image of the migration tool. Defaults to the official Flyway or Liquibase image.
+	Image string `property:"image" json:"image,omitempty"`
+	// The JDBC URL of the database to migrate, e.g., `jdbc:postgresql://postgres:5432/orders`.
+	URL string `property:"url" json:"url,omitempty"`
+	// The name of the Secret, containing the `username` and `password` keys, used to connect to the database.
+	Secret string `property:"secret" json:"secret,omitempty"`
+	// The names of the ConfigMaps containing the migration scripts, or the Liquibase changelog files.
+	ConfigMaps []string `property:"configmaps" json:"configmaps,omitempty"`
+	// The Liquibase changelog file, relative to the ConfigMaps content (default `changelog.xml`).
+	Changelog string `property:"changelog" json:"changelog,omitempty"`
+}
+
+func newMigrationTrait() Trait {
+	return &migrationTrait{
+		// Must run after the container trait, that creates the Integration Pod template
+		BaseTrait: NewBaseTrait(migrationTraitID, 1620),
+	}
+}
+
+func (t *migrationTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	switch t.tool() {
+	case flywayTool, liquibaseTool:
+	default:
+		return false, fmt.Errorf("unsupported migration tool %s, must be %s or %s", t.Tool, flywayTool, liquibaseTool)
+	}
+	if t.URL == "" {
+		return false, fmt.Errorf("the database url is required to run the migrations")
+	}
+	if len(t.ConfigMaps) == 0 {
+		return false, fmt.Errorf("at least one ConfigMap containing the migration scripts is required")
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *migrationTrait) Apply(e *Environment) error {
+	sources := make([]corev1.VolumeProjection, 0, len(t.ConfigMaps))
+	for _, cm := range t.ConfigMaps {
+		sources = append(sources, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: cm,
+				},
+			},
+		})
+	}
+
+	container := t.getContainer()
+
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: migrationVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: sources,
+				},
+			},
+		})
+		spec.InitContainers = append(spec.InitContainers, *container.DeepCopy())
+	})
+
+	return nil
+}
+
+func (t *migrationTrait) getContainer() corev1.Container {
+	container := corev1.Container{
+		Name:            migrationContainerName,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+	}
+
+	var usernameVar, passwordVar string
+	switch t.tool() {
+	case flywayTool:
+		container.Image = defaultFlywayImage
+		container.Args = []string{"migrate"}
+		container.Env = []corev1.EnvVar{
+			{Name: "FLYWAY_URL", Value: t.URL},
+			{Name: "FLYWAY_LOCATIONS", Value: "filesystem:" + flywayScriptsPath},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{
+			{Name: migrationVolumeName, MountPath: flywayScriptsPath, ReadOnly: true},
+		}
+		usernameVar, passwordVar = "FLYWAY_USER", "FLYWAY_PASSWORD"
+	case liquibaseTool:
+		changelog := t.Changelog
+		if changelog == "" {
+			changelog = defaultLiquibaseChangelog
+		}
+		container.Image = defaultLiquibaseImage
+		container.Args = []string{"update"}
+		container.Env = []corev1.EnvVar{
+			{Name: "LIQUIBASE_COMMAND_URL", Value: t.URL},
+			{Name: "LIQUIBASE_COMMAND_CHANGELOG_FILE", Value: changelog},
+			{Name: "LIQUIBASE_SEARCH_PATH", Value: liquibaseScriptsPath},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{
+			{Name: migrationVolumeName, MountPath: liquibaseScriptsPath, ReadOnly: true},
+		}
+		usernameVar, passwordVar = "LIQUIBASE_COMMAND_USERNAME", "LIQUIBASE_COMMAND_PASSWORD"
+	}
+
+	if t.Image != "" {
+		container.Image = t.Image
+	}
+
+	if t.Secret != "" {
+		container.Env = append(container.Env,
+			t.getSecretEnvVar(usernameVar, "username"),
+			t.getSecretEnvVar(passwordVar, "password"),
+		)
+	}
+
+	return container
+}
+
+func (t *migrationTrait) getSecretEnvVar(name string, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: t.Secret,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
+func (t *migrationTrait) tool() string {
+	if t.Tool == "" {
+		return flywayTool
+	}
+	return strings.ToLower(t.Tool)
+}
@@ -0,0 +1,101 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+func TestConfigureMigrationTraitWithInvalidOptions(t *testing.T) {
+	migrationTrait, environment := createMigrationTest()
+
+	migrationTrait.Tool = "alembic"
+	configured, err := migrationTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	migrationTrait.Tool = ""
+	migrationTrait.URL = ""
+	configured, err = migrationTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestMigrationFlyway(t *testing.T) {
+	migrationTrait, environment := createMigrationTest()
+	migrationTrait.ConfigMaps = []string{"schema", "data"}
+
+	configured, err := migrationTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, migrationTrait.Apply(environment))
+
+	spec := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 1)
+	assert.Equal(t, migrationVolumeName, spec.Volumes[0].Name)
+	assert.Len(t, spec.Volumes[0].Projected.Sources, 2)
+	assert.Equal(t, "data", spec.Volumes[0].Projected.Sources[1].ConfigMap.Name)
+
+	assert.Len(t, spec.InitContainers, 1)
+	container := spec.InitContainers[0]
+	assert.Equal(t, defaultFlywayImage, container.Image)
+	assert.Equal(t, []string{"migrate"}, container.Args)
+	assert.Equal(t, "jdbc:postgresql://postgres:5432/orders", envvar.Get(container.Env, "FLYWAY_URL").Value)
+	assert.Equal(t, "filesystem:/flyway/sql", envvar.Get(container.Env, "FLYWAY_LOCATIONS").Value)
+	assert.Equal(t, "postgres-credentials", envvar.Get(container.Env, "FLYWAY_PASSWORD").ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, corev1.VolumeMount{Name: migrationVolumeName, MountPath: flywayScriptsPath, ReadOnly: true}, container.VolumeMounts[0])
+}
+
+func TestMigrationLiquibase(t *testing.T) {
+	migrationTrait, environment := createMigrationTest()
+	migrationTrait.Tool = liquibaseTool
+	migrationTrait.Image = "quay.io/acme/liquibase:latest"
+	migrationTrait.Changelog = "db.changelog-master.yaml"
+	migrationTrait.Secret = ""
+
+	configured, err := migrationTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, migrationTrait.Apply(environment))
+
+	container := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec.InitContainers[0]
+	assert.Equal(t, "quay.io/acme/liquibase:latest", container.Image)
+	assert.Equal(t, []string{"update"}, container.Args)
+	assert.Equal(t, "db.changelog-master.yaml", envvar.Get(container.Env, "LIQUIBASE_COMMAND_CHANGELOG_FILE").Value)
+	assert.Equal(t, liquibaseScriptsPath, envvar.Get(container.Env, "LIQUIBASE_SEARCH_PATH").Value)
+	assert.Nil(t, envvar.Get(container.Env, "LIQUIBASE_COMMAND_USERNAME"))
+}
+
+func createMigrationTest() (*migrationTrait, *Environment) {
+	_, environment := createStorageTest(1)
+
+	trait, _ := newMigrationTrait().(*migrationTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.URL = "jdbc:postgresql://postgres:5432/orders"
+	trait.Secret = "postgres-credentials"
+	trait.ConfigMaps = []string{"migrations"}
+
+	return trait, environment
+}
@@ -19,22 +19,33 @@ package trait
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
 
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/builder"
+	"github.com/apache/camel-k/pkg/util/digest"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	utilResource "github.com/apache/camel-k/pkg/util/resource"
 )
 
+const (
+	imageResourceVolumePrefix = "image-resource"
+	imageResourceCopyPath     = "/tmp/camel-k-resource"
+	mountChecksumAnnotation   = "camel.apache.org/mount.checksum"
+)
+
 // The Mount trait can be used to configure volumes mounted on the Integration Pods.
 //
 // +camel-k:trait=mount
@@ -49,10 +60,16 @@ type mountTrait struct {
 	// A list of resources (text or binary content) pointing to configmap/secret.
 	// The resources are expected to be any resource type (text or binary content).
 	// The destination path can be either a default location or any path specified by the user.
-	// Syntax: [configmap|secret]:name[/key][@path], where name represents the resource name, key optionally represents the resource key to be filtered and path represents the destination path
+	// Syntax: [configmap|secret]:name[/key][@path], where name represents the resource name, key optionally represents the resource key to be filtered and path represents the destination path.
+	// Resources that are too large to be stored in a configmap can be stored in the Integration image, and copied to their destination path when the Pod starts.
+	// Syntax: image:file@path, where file represents the path of the resource in the Integration image, relative to the `/deployments` directory
 	Resources []string `property:"resources" json:"resources,omitempty"`
 	// A list of Persistent Volume Claims to be mounted. Syntax: [pvcname:/container/path]
 	Volumes []string `property:"volumes" json:"volumes,omitempty"`
+	// Annotate the Integration Pods with a checksum of the content of the mounted configmaps and secrets,
+	// so that any change to their content rolls out new Pods.
+	// A configmap or secret can be individually included, or excluded, with the `camel.apache.org/checksum=true|false` annotation.
+	Checksum *bool `property:"checksum" json:"checksum,omitempty"`
 }
 
 func newMountTrait() Trait {
@@ -79,14 +96,19 @@ func (t *mountTrait) Configure(e *Environment) (bool, error) {
 		}
 	}
 	for _, r := range t.Resources {
-		if !strings.HasPrefix(r, "configmap:") && !strings.HasPrefix(r, "secret:") {
-			return false, fmt.Errorf("unsupported resource %s, must be a configmap or secret resource", r)
+		if !strings.HasPrefix(r, "configmap:") && !strings.HasPrefix(r, "secret:") && !strings.HasPrefix(r, "image:") {
+			return false, fmt.Errorf("unsupported resource %s, must be a configmap, secret or image resource", r)
 		}
 	}
 
 	return true, nil
 }
 
+// RequiresTraits makes sure the integration container is configured before the volumes are mounted into it.
+func (t *mountTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
 func (t *mountTrait) Apply(e *Environment) error {
 	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
 		return nil
@@ -98,11 +120,15 @@ func (t *mountTrait) Apply(e *Environment) error {
 	}
 
 	var volumes *[]corev1.Volume
+	var initContainers *[]corev1.Container
+	var template *metav1.ObjectMeta
 	visited := false
 
 	// Deployment
 	if err := e.Resources.VisitDeploymentE(func(deployment *appsv1.Deployment) error {
 		volumes = &deployment.Spec.Template.Spec.Volumes
+		initContainers = &deployment.Spec.Template.Spec.InitContainers
+		template = &deployment.Spec.Template.ObjectMeta
 		visited = true
 		return nil
 	}); err != nil {
@@ -112,6 +138,8 @@ func (t *mountTrait) Apply(e *Environment) error {
 	// Knative Service
 	if err := e.Resources.VisitKnativeServiceE(func(service *serving.Service) error {
 		volumes = &service.Spec.ConfigurationSpec.Template.Spec.Volumes
+		initContainers = &service.Spec.ConfigurationSpec.Template.Spec.InitContainers
+		template = &service.Spec.ConfigurationSpec.Template.ObjectMeta
 		visited = true
 		return nil
 	}); err != nil {
@@ -121,6 +149,19 @@ func (t *mountTrait) Apply(e *Environment) error {
 	// CronJob
 	if err := e.Resources.VisitCronJobE(func(cron *v1beta1.CronJob) error {
 		volumes = &cron.Spec.JobTemplate.Spec.Template.Spec.Volumes
+		initContainers = &cron.Spec.JobTemplate.Spec.Template.Spec.InitContainers
+		template = &cron.Spec.JobTemplate.Spec.Template.ObjectMeta
+		visited = true
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Job
+	if err := e.Resources.VisitJobE(func(job *batchv1.Job) error {
+		volumes = &job.Spec.Template.Spec.Volumes
+		initContainers = &job.Spec.Template.Spec.InitContainers
+		template = &job.Spec.Template.ObjectMeta
 		visited = true
 		return nil
 	}); err != nil {
@@ -131,16 +172,75 @@ func (t *mountTrait) Apply(e *Environment) error {
 		// Volumes declared in the Integration resources
 		e.configureVolumesAndMounts(volumes, &container.VolumeMounts)
 		// Volumes declared in the trait config/resource options
-		err := t.configureVolumesAndMounts(e, volumes, &container.VolumeMounts)
+		err := t.configureVolumesAndMounts(e, volumes, &container.VolumeMounts, initContainers)
 		if err != nil {
 			return err
 		}
+		// Roll out new Pods whenever the content of the mounted configmaps and secrets changes
+		checksum, err := t.computeChecksum(e)
+		if err != nil {
+			return err
+		}
+		if checksum != "" {
+			if template.Annotations == nil {
+				template.Annotations = make(map[string]string)
+			}
+			template.Annotations[mountChecksumAnnotation] = checksum
+		}
 	}
 
 	return nil
 }
 
-func (t *mountTrait) configureVolumesAndMounts(e *Environment, vols *[]corev1.Volume, mnts *[]corev1.VolumeMount) error {
+// computeChecksum returns the checksum of the content of the mounted configmaps and secrets, that are either
+// selected by the checksum option, or annotated to be included. It returns an empty string if there is none.
+func (t *mountTrait) computeChecksum(e *Environment) (string, error) {
+	enabled := pointer.BoolDeref(t.Checksum, false)
+	included := func(annotations map[string]string) bool {
+		if v, ok := annotations[v1.ChecksumAnnotation]; ok {
+			return v == "true"
+		}
+		return enabled
+	}
+
+	configmaps := make([]corev1.ConfigMap, 0)
+	secrets := make([]corev1.Secret, 0)
+	confs := make([]*utilResource.Config, 0, len(t.Configs)+len(t.Resources))
+	for _, c := range t.Configs {
+		conf, err := utilResource.ParseConfig(c)
+		if err != nil {
+			return "", err
+		}
+		confs = append(confs, conf)
+	}
+	for _, r := range t.Resources {
+		conf, err := utilResource.ParseResource(r)
+		if err != nil {
+			return "", err
+		}
+		confs = append(confs, conf)
+	}
+
+	for _, conf := range confs {
+		switch conf.StorageType() {
+		case utilResource.StorageTypeConfigmap:
+			if cm := kubernetes.LookupConfigmap(e.Ctx, e.Client, e.Integration.Namespace, conf.Name()); cm != nil && included(cm.Annotations) {
+				configmaps = append(configmaps, *cm)
+			}
+		case utilResource.StorageTypeSecret:
+			if secret := kubernetes.LookupSecret(e.Ctx, e.Client, e.Integration.Namespace, conf.Name()); secret != nil && included(secret.Annotations) {
+				secrets = append(secrets, *secret)
+			}
+		}
+	}
+
+	if len(configmaps) == 0 && len(secrets) == 0 {
+		return "", nil
+	}
+	return digest.ComputeForConfigMapsAndSecrets(configmaps, secrets)
+}
+
+func (t *mountTrait) configureVolumesAndMounts(e *Environment, vols *[]corev1.Volume, mnts *[]corev1.VolumeMount, initContainers *[]corev1.Container) error {
 	for _, c := range t.Configs {
 		if conf, parseErr := utilResource.ParseConfig(c); parseErr == nil {
 			t.attachResource(e, conf)
@@ -151,6 +251,10 @@ func (t *mountTrait) configureVolumesAndMounts(e *Environment, vols *[]corev1.Vo
 	}
 	for _, r := range t.Resources {
 		if res, parseErr := utilResource.ParseResource(r); parseErr == nil {
+			if res.StorageType() == utilResource.StorageTypeImage {
+				t.mountImageResource(e, vols, mnts, initContainers, res)
+				continue
+			}
 			t.attachResource(e, res)
 			t.mountResource(vols, mnts, res)
 		} else {
@@ -205,6 +309,33 @@ func (t *mountTrait) mountResource(vols *[]corev1.Volume, mnts *[]corev1.VolumeM
 	*mnts = append(*mnts, *mnt)
 }
 
+// mountImageResource copies the resource from the Integration image into an emptyDir volume, with an init container,
+// so that it is mounted at the same destination path as a configmap resource would be.
+func (t *mountTrait) mountImageResource(e *Environment, vols *[]corev1.Volume, mnts *[]corev1.VolumeMount,
+	initContainers *[]corev1.Container, conf *utilResource.Config) {
+	refName := kubernetes.SanitizeLabel(fmt.Sprintf("%s-%s", imageResourceVolumePrefix, conf.Name()))
+	if len(refName) > 63 {
+		refName = strings.TrimRight(refName[:63], "-")
+	}
+	fileName := filepath.Base(conf.DestinationPath())
+
+	*vols = append(*vols, corev1.Volume{
+		Name: refName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	*mnts = append(*mnts, *getMount(refName, conf.DestinationPath(), fileName, true))
+	*initContainers = append(*initContainers, corev1.Container{
+		Name:    refName,
+		Image:   e.Integration.Status.Image,
+		Command: []string{"cp", path.Join(builder.DeploymentDir, conf.Name()), path.Join(imageResourceCopyPath, fileName)},
+		VolumeMounts: []corev1.VolumeMount{
+			*getMount(refName, imageResourceCopyPath, "", false),
+		},
+	})
+}
+
 // IsPlatformTrait overrides base class method.
 func (t *mountTrait) IsPlatformTrait() bool {
 	return true
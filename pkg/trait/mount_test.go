@@ -123,6 +123,83 @@ func TestMountVolumesIntegrationPhaseInitialization(t *testing.T) {
 	assert.Nil(t, s)
 }
 
+func TestMountImageResource(t *testing.T) {
+	traitCatalog := NewCatalog(nil)
+
+	environment := getNominalEnv(t, traitCatalog)
+	environment.Platform.ResyncStatusFullConfig()
+	environment.Integration.Status.Image = "integration-image"
+	environment.Integration.Spec.Traits["mount"] = test.TraitSpecFromMap(t, map[string]interface{}{
+		"resources": []string{"image:resources/4d7d9f12/data.bin@/etc/camel/resources/data.bin"},
+	})
+
+	err := traitCatalog.apply(environment)
+	assert.Nil(t, err)
+
+	s := environment.Resources.GetDeployment(func(service *appsv1.Deployment) bool {
+		return service.Name == "hello"
+	})
+	assert.NotNil(t, s)
+	spec := s.Spec.Template.Spec
+
+	volumeName := "image-resourceresources4d7d9f12databin"
+	assert.Contains(t, spec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	assert.Contains(t, spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      volumeName,
+		MountPath: "/etc/camel/resources/data.bin",
+		SubPath:   "data.bin",
+		ReadOnly:  true,
+	})
+	assert.Len(t, spec.InitContainers, 1)
+	assert.Equal(t, "integration-image", spec.InitContainers[0].Image)
+	assert.Equal(t, []string{"cp", "/deployments/resources/4d7d9f12/data.bin", "/tmp/camel-k-resource/data.bin"}, spec.InitContainers[0].Command)
+	assert.Equal(t, volumeName, spec.InitContainers[0].VolumeMounts[0].Name)
+}
+
+func TestMountChecksum(t *testing.T) {
+	checksum := func(data string, secretAnnotations map[string]string) string {
+		t.Helper()
+		traitCatalog := NewCatalog(nil)
+		environment := getNominalEnv(t, traitCatalog)
+		environment.Platform.ResyncStatusFullConfig()
+		environment.Integration.Spec.Traits["mount"] = test.TraitSpecFromMap(t, map[string]interface{}{
+			"configs":   []string{"configmap:my-cm"},
+			"resources": []string{"secret:my-secret"},
+			"checksum":  true,
+		})
+		c, err := test.NewFakeClient(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-cm"},
+				Data:       map[string]string{"application.properties": data},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-secret", Annotations: secretAnnotations},
+				Data:       map[string][]byte{"password": []byte(data)},
+			},
+		)
+		assert.Nil(t, err)
+		environment.Client = c
+
+		assert.Nil(t, traitCatalog.apply(environment))
+		d := environment.Resources.GetDeployment(func(deployment *appsv1.Deployment) bool {
+			return deployment.Name == "hello"
+		})
+		assert.NotNil(t, d)
+		return d.Spec.Template.Annotations[mountChecksumAnnotation]
+	}
+
+	c1 := checksum("v1", nil)
+	assert.NotEmpty(t, c1)
+	assert.Equal(t, c1, checksum("v1", nil))
+	assert.NotEqual(t, c1, checksum("v2", nil))
+	assert.NotEqual(t, c1, checksum("v1", map[string]string{v1.ChecksumAnnotation: "false"}))
+}
+
 func getNominalEnv(t *testing.T, traitCatalog *Catalog) *Environment {
 	t.Helper()
 	fakeClient, _ := test.NewFakeClient()
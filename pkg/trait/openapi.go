@@ -38,6 +38,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/metadata"
 	"github.com/apache/camel-k/pkg/util"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	"github.com/apache/camel-k/pkg/util/digest"
@@ -45,15 +46,26 @@ import (
 	"github.com/apache/camel-k/pkg/util/jvm"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/maven"
+	"github.com/apache/camel-k/pkg/util/openapi"
 )
 
 // The OpenAPI DSL trait is internally used to allow creating integrations from a OpenAPI specs.
 //
+// In mock mode, the trait also generates the routes implementing the operations of the OpenAPI specs, that reply
+// with the response examples, or with values generated from the response schemas. An Integration created from
+// OpenAPI specs only can then be deployed as a mock service, e.g.:
+//
+// `kamel run --name pets --open-api file:pets.yaml -t openapi.mock=true`
+//
+// The operations whose `direct:<operationId>` endpoint is implemented by the Integration sources are not mocked.
+//
 // +camel-k:trait=openapi.
 type openAPITrait struct {
 	BaseTrait `property:",squash"`
 	// The configmaps holding the spec of the OpenAPI
 	Configmaps []string `property:"configmaps" json:"configmaps,omitempty"`
+	// Generates the routes implementing the operations of the OpenAPI specs, with mocked responses (default `false`).
+	Mock *bool `property:"mock" json:"mock,omitempty"`
 }
 
 func newOpenAPITrait() Trait {
@@ -187,9 +199,62 @@ func (t *openAPITrait) generateFromDataSpecs(e *Environment, tmpDir string, spec
 		})
 	}
 
+	if pointer.BoolDeref(t.Mock, false) && len(specs) > 0 {
+		mocks, err := t.generateMocks(e, specs)
+		if err != nil {
+			return nil, err
+		}
+		generatedSources = append(generatedSources, mocks...)
+	}
+
 	return generatedSources, nil
 }
 
+// generateMocks generates a source per OpenAPI spec, with the routes replying to the operations that are not
+// implemented by the Integration sources.
+func (t *openAPITrait) generateMocks(e *Environment, specs []v1.DataSpec) ([]v1.SourceSpec, error) {
+	implemented := metadata.ExtractAll(e.CamelCatalog, e.Integration.Spec.Sources).FromURIs
+
+	mocks := make([]v1.SourceSpec, 0, len(specs))
+	for _, resource := range specs {
+		content := []byte(resource.Content)
+		if resource.Compression {
+			var err error
+			if content, err = gzip.UncompressBase64(content); err != nil {
+				return nil, err
+			}
+		}
+
+		operations, err := openapi.Operations(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot mock openapi resource %s", resource.Name)
+		}
+		mocked := make([]openapi.Operation, 0, len(operations))
+		for _, operation := range operations {
+			if !util.StringSliceExists(implemented, "direct:"+operation.ID) {
+				mocked = append(mocked, operation)
+			}
+		}
+		if len(mocked) == 0 {
+			continue
+		}
+
+		routes, err := openapi.MockRoutes(mocked)
+		if err != nil {
+			return nil, err
+		}
+		mocks = append(mocks, v1.SourceSpec{
+			DataSpec: v1.DataSpec{
+				Name:    strings.TrimSuffix(resource.Name, filepath.Ext(resource.Name)) + "-mock.yaml",
+				Content: string(routes),
+			},
+			Language: v1.LanguageYaml,
+		})
+	}
+
+	return mocks, nil
+}
+
 func (t *openAPITrait) generateOpenAPIConfigMap(e *Environment, resource v1.DataSpec, tmpDir, generatedContentName string) error {
 	cm := corev1.ConfigMap{}
 	key := ctrl.ObjectKey{
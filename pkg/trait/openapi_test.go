@@ -62,3 +62,54 @@ func TestRestDslTraitApplicability(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, enabled)
 }
+
+func TestOpenAPITraitGenerateMocks(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	e := &Environment{
+		CamelCatalog: catalog,
+		Integration: &v1.Integration{
+			Spec: v1.IntegrationSpec{
+				Sources: []v1.SourceSpec{
+					v1.NewSourceSpec("routes.yaml", `
+- from:
+    uri: direct:listPets
+    steps:
+    - setBody:
+        constant: "[]"
+`, v1.LanguageYaml),
+				},
+			},
+		},
+	}
+
+	trait, _ := newOpenAPITrait().(*openAPITrait)
+	mocks, err := trait.generateMocks(e, []v1.DataSpec{
+		{
+			Name: "pets.yaml",
+			Content: `
+openapi: 3.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: The pets
+    post:
+      operationId: createPet
+      responses:
+        "201":
+          description: Created
+`,
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, mocks, 1)
+	assert.Equal(t, "pets-mock.yaml", mocks[0].Name)
+	assert.Equal(t, v1.LanguageYaml, mocks[0].Language)
+	assert.Contains(t, mocks[0].Content, "uri: direct:createPet")
+	assert.NotContains(t, mocks[0].Content, "uri: direct:listPets")
+}
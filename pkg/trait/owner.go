@@ -57,6 +57,11 @@ func (t *ownerTrait) Configure(e *Environment) (bool, error) {
 	return e.IntegrationInPhase(v1.IntegrationPhaseInitialization) || e.IntegrationInRunningPhases(), nil
 }
 
+// RequiresTraits makes sure the controller resources exist before the owner metadata is propagated to them.
+func (t *ownerTrait) RequiresTraits() []ID {
+	return []ID{"deployment", knativeServiceTraitID, "cron", "job"}
+}
+
 func (t *ownerTrait) Apply(e *Environment) error {
 	controller := true
 	blockOwnerDeletion := true
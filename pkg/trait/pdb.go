@@ -20,16 +20,25 @@ package trait
 import (
 	"fmt"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	"k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 )
 
 // The PDB trait allows to configure the PodDisruptionBudget resource for the Integration pods.
 //
+// In `auto` mode, the budget is computed from the number of replicas of the Integration, so that its Pods are evicted
+// one at a time. When the Integration is autoscaled, by Knative or by a HorizontalPodAutoscaler (e.g., created by KEDA),
+// the current number of Pods is used, bounded by the minimum and maximum number of replicas of the autoscaler.
+// The budget is updated as the Integration scales.
+//
 // +camel-k:trait=pdb.
 type pdbTrait struct {
 	BaseTrait `property:",squash"`
@@ -41,6 +50,9 @@ type pdbTrait struct {
 	// It can be either an absolute number or a percentage (default `1` if `min-available` is also not set).
 	// Only one of `max-unavailable` and `min-available` can be specified.
 	MaxUnavailable string `property:"max-unavailable" json:"maxUnavailable,omitempty"`
+	// Computes `min-available` from the number of replicas of the Integration, and its autoscaling bounds.
+	// It cannot be used along with `min-available` or `max-unavailable`.
+	Auto *bool `property:"auto" json:"auto,omitempty"`
 }
 
 func newPdbTrait() Trait {
@@ -63,14 +75,41 @@ func (t *pdbTrait) Configure(e *Environment) (bool, error) {
 		return false, fmt.Errorf("poddisruptionbudget isn't supported with cron-job controller strategy")
 	}
 
+	if strategy == ControllerStrategyJob {
+		return false, fmt.Errorf("poddisruptionbudget isn't supported with job controller strategy")
+	}
+
 	if t.MaxUnavailable != "" && t.MinAvailable != "" {
 		return false, fmt.Errorf("both minAvailable and maxUnavailable can't be set simultaneously")
 	}
 
+	if pointer.BoolDeref(t.Auto, false) && (t.MaxUnavailable != "" || t.MinAvailable != "") {
+		return false, fmt.Errorf("minAvailable and maxUnavailable can't be set in auto mode")
+	}
+
 	return e.IntegrationInRunningPhases(), nil
 }
 
 func (t *pdbTrait) Apply(e *Environment) error {
+	if pointer.BoolDeref(t.Auto, false) {
+		pdb := t.podDisruptionBudgetFor(e.Integration)
+		e.Resources.Add(pdb)
+
+		// The budget is computed once all the traits are applied,
+		// as the autoscaling bounds can be set by traits executed later on
+		e.PostProcessors = append(e.PostProcessors, func(env *Environment) error {
+			minAvailable, err := t.autoMinAvailable(env)
+			if err != nil {
+				return err
+			}
+			min := intstr.FromInt(int(minAvailable))
+			pdb.Spec.MinAvailable = &min
+			return nil
+		})
+
+		return nil
+	}
+
 	if t.MaxUnavailable == "" && t.MinAvailable == "" {
 		t.MaxUnavailable = "1"
 	}
@@ -104,10 +143,84 @@ func (t *pdbTrait) podDisruptionBudgetFor(integration *v1.Integration) *v1beta1.
 	if t.MaxUnavailable != "" {
 		max := intstr.Parse(t.MaxUnavailable)
 		pdb.Spec.MaxUnavailable = &max
-	} else {
+	} else if t.MinAvailable != "" {
 		min := intstr.Parse(t.MinAvailable)
 		pdb.Spec.MinAvailable = &min
 	}
 
 	return pdb
 }
+
+// autoMinAvailable returns the number of replicas of the Integration minus one, so that its Pods can only be evicted
+// one at a time. The number of replicas of an autoscaled Integration is bounded by the autoscaling min and max replicas.
+func (t *pdbTrait) autoMinAvailable(e *Environment) (int32, error) {
+	replicas := int32(1)
+	if e.Integration.Spec.Replicas != nil {
+		replicas = *e.Integration.Spec.Replicas
+	}
+
+	min, max, autoscaled, err := t.autoscalingBounds(e)
+	if err != nil {
+		return 0, err
+	}
+	if autoscaled {
+		// The number of replicas is driven by the autoscaler
+		if e.Integration.Status.Replicas != nil {
+			replicas = *e.Integration.Status.Replicas
+		}
+		if replicas < min {
+			replicas = min
+		}
+		if max > 0 && replicas > max {
+			replicas = max
+		}
+	}
+
+	if replicas <= 1 {
+		return 0, nil
+	}
+	return replicas - 1, nil
+}
+
+// autoscalingBounds returns the min and max replicas of the autoscaler of the Integration, if any.
+// A max replicas of zero means the number of replicas isn't bounded.
+func (t *pdbTrait) autoscalingBounds(e *Environment) (int32, int32, bool, error) {
+	strategy, err := e.DetermineControllerStrategy()
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	if strategy == ControllerStrategyKnativeService {
+		var min, max int32
+		if ks, ok := e.Catalog.GetTrait(knativeServiceTraitID).(*knativeServiceTrait); ok {
+			if ks.MinScale != nil {
+				min = int32(*ks.MinScale)
+			}
+			if ks.MaxScale != nil {
+				max = int32(*ks.MaxScale)
+			}
+		}
+		return min, max, true, nil
+	}
+
+	hpas := autoscalingv1.HorizontalPodAutoscalerList{}
+	if err := e.Client.List(e.Ctx, &hpas, ctrl.InNamespace(e.Integration.Namespace)); err != nil {
+		return 0, 0, false, err
+	}
+	for _, hpa := range hpas.Items {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Name != e.Integration.Name {
+			continue
+		}
+		// The autoscaler may target the Integration, its KameletBinding, or its Deployment
+		if ref.Kind == v1.IntegrationKind || ref.Kind == v1alpha1.KameletBindingKind || ref.Kind == "Deployment" {
+			min := int32(1)
+			if hpa.Spec.MinReplicas != nil {
+				min = *hpa.Spec.MinReplicas
+			}
+			return min, hpa.Spec.MaxReplicas, true, nil
+		}
+	}
+
+	return 0, 0, false, nil
+}
@@ -18,11 +18,13 @@ limitations under the License.
 package trait
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +32,7 @@ import (
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
 )
 
 func TestConfigurePdbTraitDoesSucceed(t *testing.T) {
@@ -73,6 +76,92 @@ func TestPdbIsCreatedWithMinAvailable(t *testing.T) {
 	assert.Equal(t, int32(2), pdb.Spec.MinAvailable.IntVal)
 }
 
+func TestConfigurePdbTraitAutoWithMinAvailable(t *testing.T) {
+	pdbTrait, environment, _ := createPdbTest()
+	pdbTrait.Auto = pointer.Bool(true)
+	pdbTrait.MinAvailable = "2"
+
+	configured, err := pdbTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestPdbIsCreatedWithAutoFromReplicas(t *testing.T) {
+	pdbTrait, environment, _ := createPdbTest()
+	pdbTrait.Auto = pointer.Bool(true)
+	environment.Integration.Spec.Replicas = pointer.Int32(3)
+
+	pdb := pdbAutoCreatedCheck(t, pdbTrait, environment)
+	assert.Nil(t, pdb.Spec.MaxUnavailable)
+	assert.Equal(t, int32(2), pdb.Spec.MinAvailable.IntVal)
+}
+
+func TestPdbIsCreatedWithAutoForSingleReplica(t *testing.T) {
+	pdbTrait, environment, _ := createPdbTest()
+	pdbTrait.Auto = pointer.Bool(true)
+
+	pdb := pdbAutoCreatedCheck(t, pdbTrait, environment)
+	assert.Equal(t, int32(0), pdb.Spec.MinAvailable.IntVal)
+}
+
+func TestPdbIsCreatedWithAutoFromHorizontalPodAutoscaler(t *testing.T) {
+	pdbTrait, environment, _ := createPdbTest()
+	pdbTrait.Auto = pointer.Bool(true)
+	environment.Integration.Spec.Replicas = pointer.Int32(1)
+	environment.Integration.Status.Replicas = pointer.Int32(8)
+	environment.Client, _ = test.NewFakeClient(&autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "keda-hpa-integration-name",
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: v1.SchemeGroupVersion.String(),
+				Kind:       v1.IntegrationKind,
+				Name:       "integration-name",
+			},
+			MinReplicas: pointer.Int32(2),
+			MaxReplicas: 5,
+		},
+	})
+
+	pdb := pdbAutoCreatedCheck(t, pdbTrait, environment)
+	assert.Equal(t, int32(4), pdb.Spec.MinAvailable.IntVal)
+
+	// Scale down below the autoscaler min replicas
+	environment.Integration.Status.Replicas = pointer.Int32(0)
+	environment.Resources = kubernetes.NewCollection()
+	environment.PostProcessors = nil
+
+	pdb = pdbAutoCreatedCheck(t, pdbTrait, environment)
+	assert.Equal(t, int32(1), pdb.Spec.MinAvailable.IntVal)
+}
+
+func TestPdbIsCreatedWithAutoFromKnativeScale(t *testing.T) {
+	pdbTrait, environment, _ := createPdbTest()
+	pdbTrait.Auto = pointer.Bool(true)
+	environment.Integration.Status.Replicas = pointer.Int32(1)
+
+	ks, _ := environment.Catalog.GetTrait(knativeServiceTraitID).(*knativeServiceTrait)
+	ks.Enabled = pointer.Bool(true)
+	ks.MinScale = pointer.Int(3)
+	environment.ConfiguredTraits = []Trait{ks}
+
+	pdb := pdbAutoCreatedCheck(t, pdbTrait, environment)
+	assert.Equal(t, int32(2), pdb.Spec.MinAvailable.IntVal)
+}
+
+func pdbAutoCreatedCheck(t *testing.T, pdbTrait *pdbTrait, environment *Environment) *v1beta1.PodDisruptionBudget {
+	t.Helper()
+
+	pdb := pdbCreatedCheck(t, pdbTrait, environment)
+	for _, processor := range environment.PostProcessors {
+		assert.Nil(t, processor(environment))
+	}
+	assert.NotNil(t, pdb.Spec.MinAvailable)
+	return pdb
+}
+
 func pdbCreatedCheck(t *testing.T, pdbTrait *pdbTrait, environment *Environment) *v1beta1.PodDisruptionBudget {
 	t.Helper()
 
@@ -110,10 +199,16 @@ func createPdbTest() (*pdbTrait, *Environment, *appsv1.Deployment) {
 		},
 	}
 
+	client, _ := test.NewFakeClient()
+
 	environment := &Environment{
+		Catalog: NewEnvironmentTestCatalog(),
+		Client:  client,
+		Ctx:     context.TODO(),
 		Integration: &v1.Integration{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "integration-name",
+				Name:      "integration-name",
+				Namespace: "ns",
 			},
 			Status: v1.IntegrationStatus{
 				Phase: v1.IntegrationPhaseDeploying,
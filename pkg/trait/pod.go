@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -79,6 +80,15 @@ func (t *podTrait) Apply(e *Environment) error {
 			}
 		})
 
+	case ControllerStrategyJob:
+		e.Resources.VisitJob(func(j *batchv1.Job) {
+			if j.Name == e.Integration.Name {
+				if patchedPodSpec, err = t.applyChangesTo(&j.Spec.Template.Spec, changes); err == nil {
+					j.Spec.Template.Spec = *patchedPodSpec
+				}
+			}
+		})
+
 	case ControllerStrategyDeployment:
 		e.Resources.VisitDeployment(func(d *appsv1.Deployment) {
 			if d.Name == e.Integration.Name {
@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/utils/pointer"
+)
+
+const prebuiltRoutesTraitID = "prebuilt-routes"
+
+var javaQualifiedNameRegexp = regexp.MustCompile(`^[a-zA-Z_$][\w$]*(\.[a-zA-Z_$][\w$]*)*$`)
+
+// The Prebuilt Routes trait loads the routes from classes that are compiled outside of the platform, e.g., by
+// a CI pipeline, and packaged in jar artifacts.
+//
+// The artifacts must be added to the Integration dependencies, either as Maven artifacts,
+// e.g., `kamel run mvn:org.acme:routes:1.0`, or as local jar files uploaded by the CLI, e.g., `kamel run routes.jar`.
+// They are then added to the Integration kit classpath.
+//
+// +camel-k:trait=prebuilt-routes.
+type prebuiltRoutesTrait struct {
+	BaseTrait `property:",squash"`
+	// The fully qualified names of the `RouteBuilder` classes to load the routes from, e.g., `org.acme.MyRoutes`.
+	Classes []string `property:"classes" json:"classes,omitempty"`
+	// The Java packages that are scanned for `RouteBuilder` classes, including sub-packages, e.g., `org.acme.routes`.
+	Packages []string `property:"packages" json:"packages,omitempty"`
+}
+
+func newPrebuiltRoutesTrait() Trait {
+	return &prebuiltRoutesTrait{
+		BaseTrait: NewBaseTrait(prebuiltRoutesTraitID, 1050),
+	}
+}
+
+func (t *prebuiltRoutesTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, true) || len(t.Classes)+len(t.Packages) == 0 {
+		return false, nil
+	}
+
+	for _, names := range [][]string{t.Classes, t.Packages} {
+		for _, name := range names {
+			if !javaQualifiedNameRegexp.MatchString(name) {
+				return false, fmt.Errorf("invalid Java class or package name: %s", name)
+			}
+		}
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *prebuiltRoutesTrait) Apply(e *Environment) error {
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+
+	if len(t.Classes) > 0 {
+		e.ApplicationProperties["camel.main.routes-builder-classes"] = strings.Join(t.Classes, ",")
+	}
+
+	if len(t.Packages) > 0 {
+		patterns := make([]string, 0, len(t.Packages))
+		for _, p := range t.Packages {
+			patterns = append(patterns, strings.ReplaceAll(p, ".", "/")+"/**")
+		}
+		e.ApplicationProperties["camel.main.java-routes-include-pattern"] = strings.Join(patterns, ",")
+	}
+
+	return nil
+}
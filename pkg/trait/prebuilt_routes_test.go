@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestConfigurePrebuiltRoutesTraitDoesNotSucceedWithoutRoutes(t *testing.T) {
+	trait, environment := createPrebuiltRoutesTest()
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigurePrebuiltRoutesTraitWithInvalidName(t *testing.T) {
+	trait, environment := createPrebuiltRoutesTest()
+	trait.Classes = []string{"org.acme.My-Routes"}
+
+	configured, err := trait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestApplyPrebuiltRoutesTrait(t *testing.T) {
+	trait, environment := createPrebuiltRoutesTest()
+	trait.Classes = []string{"org.acme.MyRoutes", "org.acme.OtherRoutes"}
+	trait.Packages = []string{"org.acme.routes", "com.acme"}
+
+	configured, err := trait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	err = trait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Equal(t, "org.acme.MyRoutes,org.acme.OtherRoutes", environment.ApplicationProperties["camel.main.routes-builder-classes"])
+	assert.Equal(t, "org/acme/routes/**,com/acme/**", environment.ApplicationProperties["camel.main.java-routes-include-pattern"])
+}
+
+func createPrebuiltRoutesTest() (*prebuiltRoutesTrait, *Environment) {
+	trait, _ := newPrebuiltRoutesTrait().(*prebuiltRoutesTrait)
+
+	environment := &Environment{
+		Integration: &v1.Integration{
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+	}
+
+	return trait, environment
+}
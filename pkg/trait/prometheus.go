@@ -18,26 +18,54 @@ limitations under the License.
 package trait
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/metadata"
 	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/dsl"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+// The MicroProfile Config environment variable holding the tags added to all the metrics.
+const metricsTagsEnvVar = "MP_METRICS_TAGS"
+
+var metricsTagNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+const (
+	defaultFailureRateThreshold = 10
+	// The label the Grafana sidecar watches, to discover the ConfigMaps holding dashboards.
+	grafanaDashboardLabel = "grafana_dashboard"
+	grafanaDashboardKey   = "dashboard.json"
 )
 
 // The Prometheus trait configures a Prometheus-compatible endpoint. It also creates a `PodMonitor` resource,
 // so that the endpoint can be scraped automatically, when using the Prometheus operator.
 //
-// The metrics are exposed using MicroProfile Metrics.
+// The metrics are exposed using MicroProfile Metrics. They are tagged with the name of the Integration, of its
+// IntegrationKit, and with its namespace, so that they can be aggregated across the Integration pods.
+// The routes declared in the Integration flows, that have no id, are given one derived from the Integration name,
+// so that the route metrics keep the same name from one deployment to the other.
+//
+// The trait can also create a `PrometheusRule` resource, alerting on the exchange failure rate and on the stopped routes,
+// and a ConfigMap holding a Grafana dashboard, with a panel per route declared in the Integration sources.
+// The ConfigMap is labelled with `grafana_dashboard: "1"`, so that it is picked up by the Grafana sidecar.
 //
 // WARNING: The creation of the `PodMonitor` resource requires the https://github.com/coreos/prometheus-operator[Prometheus Operator]
 // custom resource definition to be installed.
 // You can set `pod-monitor` to `false` for the Prometheus trait to work without the Prometheus Operator.
+// The same applies to the `PrometheusRule` resource.
 //
 // The Prometheus trait is disabled by default.
 //
@@ -48,12 +76,25 @@ type prometheusTrait struct {
 	PodMonitor *bool `property:"pod-monitor" json:"podMonitor,omitempty"`
 	// The `PodMonitor` resource labels, applicable when `pod-monitor` is `true`.
 	PodMonitorLabels []string `property:"pod-monitor-labels" json:"podMonitorLabels,omitempty"`
+	// Additional tags added to all the metrics, in the form `name=value`.
+	Tags []string `property:"tags" json:"tags,omitempty"`
+	// Whether the routes declared in the Integration flows, that have no id, are given a stable id (default `true`).
+	RouteIDs *bool `property:"route-ids" json:"routeIDs,omitempty"`
+	// Whether a `PrometheusRule` resource, with the default alerts, is created (default `false`).
+	PrometheusRule *bool `property:"prometheus-rule" json:"prometheusRule,omitempty"`
+	// The `PrometheusRule` resource labels, applicable when `prometheus-rule` is `true`.
+	PrometheusRuleLabels []string `property:"prometheus-rule-labels" json:"prometheusRuleLabels,omitempty"`
+	// The percentage of failed exchanges, over 5 minutes, above which a route fires the failure rate alert (default `10`).
+	FailureRateThreshold *int `property:"failure-rate-threshold" json:"failureRateThreshold,omitempty"`
+	// Whether a ConfigMap holding a Grafana dashboard is created (default `false`).
+	GrafanaDashboard *bool `property:"grafana-dashboard" json:"grafanaDashboard,omitempty"`
 }
 
 func newPrometheusTrait() Trait {
 	return &prometheusTrait{
 		BaseTrait:  NewBaseTrait("prometheus", 1900),
 		PodMonitor: pointer.Bool(true),
+		RouteIDs:   pointer.Bool(true),
 	}
 }
 
@@ -62,13 +103,28 @@ func (t *prometheusTrait) Configure(e *Environment) (bool, error) {
 		return false, nil
 	}
 
+	if _, err := t.getTags(e); err != nil {
+		return false, err
+	}
+	if threshold := t.getFailureRateThreshold(); threshold <= 0 || threshold > 100 {
+		return false, fmt.Errorf("invalid failure rate threshold %d, it must be a percentage between 1 and 100", threshold)
+	}
+
 	return e.IntegrationInPhase(v1.IntegrationPhaseInitialization) || e.IntegrationInRunningPhases(), nil
 }
 
+// RequiresTraits makes sure the integration container is configured before the metrics port is added to it.
+func (t *prometheusTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
 func (t *prometheusTrait) Apply(e *Environment) (err error) {
 	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
 		// Add the Camel Quarkus MP Metrics extension
 		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, "mvn:org.apache.camel.quarkus:camel-quarkus-microprofile-metrics")
+		if pointer.BoolDeref(t.RouteIDs, true) && len(e.Integration.Spec.Flows) > 0 {
+			return t.setRouteIDs(e)
+		}
 		return nil
 	}
 
@@ -102,6 +158,12 @@ func (t *prometheusTrait) Apply(e *Environment) (err error) {
 
 	condition.Message = fmt.Sprintf("%s(%d)", container.Name, containerPort.ContainerPort)
 
+	tags, err := t.getTags(e)
+	if err != nil {
+		return err
+	}
+	envvar.SetVal(&container.Env, metricsTagsEnvVar, tags)
+
 	// Add the PodMonitor resource
 	if pointer.BoolDeref(t.PodMonitor, false) {
 		portName := containerPort.Name
@@ -123,6 +185,27 @@ func (t *prometheusTrait) Apply(e *Environment) (err error) {
 		condition.Message = "ContainerPort " + condition.Message
 	}
 
+	if pointer.BoolDeref(t.PrometheusRule, false) || pointer.BoolDeref(t.GrafanaDashboard, false) {
+		routeIDs, err := t.getRouteIDs(e)
+		if err != nil {
+			return err
+		}
+		if pointer.BoolDeref(t.PrometheusRule, false) {
+			rule, err := t.getPrometheusRuleFor(e, routeIDs)
+			if err != nil {
+				return err
+			}
+			e.Resources.Add(rule)
+		}
+		if pointer.BoolDeref(t.GrafanaDashboard, false) {
+			dashboard, err := t.getGrafanaDashboardFor(e, routeIDs)
+			if err != nil {
+				return err
+			}
+			e.Resources.Add(dashboard)
+		}
+	}
+
 	e.Integration.Status.SetConditions(condition)
 
 	return nil
@@ -183,3 +266,243 @@ func (t *prometheusTrait) getPodMonitorFor(e *Environment, portName string) (*mo
 
 	return &podMonitor, nil
 }
+
+// getRouteIDs returns the ids of the routes declared in the Integration sources, in order to scope the alerts
+// and the dashboard panels. An empty result means the routes are not identified, and all of them are considered.
+func (t *prometheusTrait) getRouteIDs(e *Environment) ([]string, error) {
+	sources, err := kubernetes.ResolveIntegrationSources(e.Ctx, t.Client, e.Integration, e.Resources)
+	if err != nil {
+		return nil, err
+	}
+	meta := metadata.ExtractAll(e.CamelCatalog, sources)
+
+	ids := make([]string, 0, len(meta.RouteIDs))
+	for _, id := range meta.RouteIDs {
+		util.StringSliceUniqueAdd(&ids, id)
+	}
+	return ids, nil
+}
+
+func (t *prometheusTrait) getFailureRateThreshold() int {
+	if t.FailureRateThreshold == nil {
+		return defaultFailureRateThreshold
+	}
+	return *t.FailureRateThreshold
+}
+
+func (t *prometheusTrait) getPrometheusRuleFor(e *Environment, routeIDs []string) (*monitoringv1.PrometheusRule, error) {
+	labels, err := keyValuePairArrayAsStringMap(t.PrometheusRuleLabels)
+	if err != nil {
+		return nil, err
+	}
+	labels[v1.IntegrationLabel] = e.Integration.Name
+
+	selector := metricsSelector(e, routeIDs)
+	contextSelector := metricsSelector(e, nil)
+	alertLabels := map[string]string{
+		"integration": e.Integration.Name,
+		"namespace":   e.Integration.Namespace,
+		"severity":    "warning",
+	}
+	threshold := t.getFailureRateThreshold()
+
+	rule := monitoringv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       monitoringv1.PrometheusRuleKind,
+			APIVersion: monitoringv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      e.Integration.Name,
+			Namespace: e.Integration.Namespace,
+			Labels:    labels,
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name: e.Integration.Name,
+					Rules: []monitoringv1.Rule{
+						{
+							Alert: "CamelKRouteExchangesFailureRate",
+							Expr: intstr.FromString(fmt.Sprintf(
+								"sum by (routeId) (rate(application_camel_route_exchanges_failed_total%s[5m])) / sum by (routeId) (rate(application_camel_route_exchanges_total%s[5m])) * 100 > %d",
+								selector, selector, threshold)),
+							For:    "5m",
+							Labels: alertLabels,
+							Annotations: map[string]string{
+								"summary": fmt.Sprintf("Route {{ $labels.routeId }} of Integration %s fails more than %d%% of its exchanges", e.Integration.Name, threshold),
+								"description": fmt.Sprintf("Route {{ $labels.routeId }} of Integration %s/%s has failed {{ $value | humanize }}%% of its exchanges over the last 5 minutes.",
+									e.Integration.Namespace, e.Integration.Name),
+							},
+						},
+						{
+							Alert: "CamelKRoutesStopped",
+							Expr: intstr.FromString(fmt.Sprintf(
+								"min(application_camel_route_running_count%s) < max(application_camel_route_count%s)",
+								contextSelector, contextSelector)),
+							For:    "5m",
+							Labels: alertLabels,
+							Annotations: map[string]string{
+								"summary": fmt.Sprintf("Routes of Integration %s are stopped", e.Integration.Name),
+								"description": fmt.Sprintf("Some of the routes of Integration %s/%s have not been running for 5 minutes.",
+									e.Integration.Namespace, e.Integration.Name),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return &rule, nil
+}
+
+func (t *prometheusTrait) getGrafanaDashboardFor(e *Environment, routeIDs []string) (*corev1.ConfigMap, error) {
+	contextSelector := metricsSelector(e, nil)
+
+	panels := []map[string]interface{}{
+		grafanaStatPanel(1, "Running routes", fmt.Sprintf("min(application_camel_route_running_count%s)", contextSelector), 0),
+		grafanaStatPanel(2, "Exchanges in flight", fmt.Sprintf("sum(application_camel_route_exchanges_inflight%s)", contextSelector), 12),
+	}
+	if len(routeIDs) == 0 {
+		panels = append(panels, grafanaRoutePanel(len(panels)+1, "Routes", contextSelector, 0))
+	}
+	for i, id := range routeIDs {
+		panels = append(panels, grafanaRoutePanel(len(panels)+1, "Route "+id, metricsSelector(e, []string{id}), i))
+	}
+
+	dashboard := map[string]interface{}{
+		"title":         fmt.Sprintf("Camel K / %s / %s", e.Integration.Namespace, e.Integration.Name),
+		"tags":          []string{"camel-k"},
+		"editable":      true,
+		"schemaVersion": 30,
+		"refresh":       "30s",
+		"time": map[string]string{
+			"from": "now-1h",
+			"to":   "now",
+		},
+		"panels": panels,
+	}
+	content, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      e.Integration.Name + "-grafana-dashboard",
+			Namespace: e.Integration.Namespace,
+			Labels: map[string]string{
+				v1.IntegrationLabel:   e.Integration.Name,
+				grafanaDashboardLabel: "1",
+			},
+		},
+		Data: map[string]string{
+			grafanaDashboardKey: string(content),
+		},
+	}, nil
+}
+
+// metricsSelector returns the PromQL selector matching the metrics of the Integration, optionally
+// restricted to the given routes.
+func metricsSelector(e *Environment, routeIDs []string) string {
+	matchers := []string{
+		fmt.Sprintf("integration=%q", e.Integration.Name),
+		fmt.Sprintf("namespace=%q", e.Integration.Namespace),
+	}
+	if len(routeIDs) > 0 {
+		patterns := make([]string, 0, len(routeIDs))
+		for _, id := range routeIDs {
+			patterns = append(patterns, regexp.QuoteMeta(id))
+		}
+		matchers = append(matchers, fmt.Sprintf("routeId=~%q", strings.Join(patterns, "|")))
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+func grafanaStatPanel(id int, title string, expr string, x int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":    id,
+		"type":  "stat",
+		"title": title,
+		"gridPos": map[string]int{
+			"h": 4, "w": 12, "x": x, "y": 0,
+		},
+		"targets": []map[string]interface{}{
+			{"refId": "A", "expr": expr},
+		},
+	}
+}
+
+// grafanaRoutePanel returns a panel graphing the exchanges rates of the routes matched by the selector,
+// laid out on two columns below the stat panels.
+func grafanaRoutePanel(id int, title string, selector string, position int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":    id,
+		"type":  "timeseries",
+		"title": title,
+		"gridPos": map[string]int{
+			"h": 8, "w": 12, "x": 12 * (position % 2), "y": 4 + 8*(position/2),
+		},
+		"targets": []map[string]interface{}{
+			{
+				"refId":        "A",
+				"expr":         fmt.Sprintf("sum by (routeId) (rate(application_camel_route_exchanges_total%s[5m]))", selector),
+				"legendFormat": "{{routeId}} exchanges/s",
+			},
+			{
+				"refId":        "B",
+				"expr":         fmt.Sprintf("sum by (routeId) (rate(application_camel_route_exchanges_failed_total%s[5m]))", selector),
+				"legendFormat": "{{routeId}} failures/s",
+			},
+		},
+	}
+}
+
+// setRouteIDs regenerates the source of the Integration flows, with the routes that have no id given one derived
+// from the Integration name.
+func (t *prometheusTrait) setRouteIDs(e *Environment) error {
+	flows, err := dsl.WithRouteIDs(e.Integration.Spec.Flows, e.Integration.Name)
+	if err != nil {
+		return err
+	}
+	content, err := dsl.ToYamlDSL(flows)
+	if err != nil {
+		return err
+	}
+	e.Integration.Status.AddOrReplaceGeneratedSources(v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    flowsInternalSourceName,
+			Content: string(content),
+		},
+	})
+	return nil
+}
+
+// getTags returns the tags added to all the metrics, in the MicroProfile Metrics `mp.metrics.tags` format.
+func (t *prometheusTrait) getTags(e *Environment) (string, error) {
+	tags := map[string]string{
+		"integration": e.Integration.Name,
+		"namespace":   e.Integration.Namespace,
+	}
+	if e.Integration.Status.IntegrationKit != nil {
+		tags["kit"] = e.Integration.Status.IntegrationKit.Name
+	}
+	for _, tag := range t.Tags {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) != 2 || !metricsTagNameRegexp.MatchString(parts[0]) {
+			return "", fmt.Errorf("invalid metrics tag %s, it must be in the form name=value, with name matching %s", tag, metricsTagNameRegexp)
+		}
+		tags[parts[0]] = parts[1]
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`)
+	pairs := make([]string, 0, len(tags))
+	for _, name := range util.SortedStringMapKeys(tags) {
+		pairs = append(pairs, name+"="+escaper.Replace(tags[name]))
+	}
+	return strings.Join(pairs, ","), nil
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package trait
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,6 +26,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
@@ -153,3 +155,129 @@ func createNominalPrometheusTest() (*prometheusTrait, *Environment) {
 
 	return trait, environment
 }
+
+func TestApplyPrometheusTraitSetsMetricsTags(t *testing.T) {
+	trait, environment := createNominalPrometheusTest()
+	trait.Tags = []string{"team=orders", "region=eu,west"}
+	environment.Integration.Status.IntegrationKit = &corev1.ObjectReference{Name: "kit-123"}
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+
+	container := environment.Resources.GetContainerByName(defaultContainerName)
+	assert.NotNil(t, container)
+	assert.Contains(t, container.Env, corev1.EnvVar{
+		Name:  "MP_METRICS_TAGS",
+		Value: `integration=integration-name,kit=kit-123,namespace=integration-namespace,region=eu\,west,team=orders`,
+	})
+}
+
+func TestConfigurePrometheusTraitWithInvalidTagDoesNotSucceed(t *testing.T) {
+	trait, environment := createNominalPrometheusTest()
+	trait.Tags = []string{"my-tag=value"}
+
+	configured, err := trait.Configure(environment)
+
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestApplyPrometheusTraitSetsRouteIDs(t *testing.T) {
+	trait, environment := createNominalPrometheusTest()
+	environment.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+	environment.Integration.Spec.Flows = []v1.Flow{
+		{RawMessage: []byte(`{"from":{"uri":"timer:tick","steps":[{"to":"log:info"}]}}`)},
+	}
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Contains(t, environment.Integration.Status.Dependencies, "mvn:org.apache.camel.quarkus:camel-quarkus-microprofile-metrics")
+	assert.Len(t, environment.Integration.Status.GeneratedSources, 1)
+	assert.Equal(t, flowsInternalSourceName, environment.Integration.Status.GeneratedSources[0].Name)
+	assert.Contains(t, environment.Integration.Status.GeneratedSources[0].Content, "id: integration-name-1")
+}
+
+func TestApplyPrometheusTraitCreatesPrometheusRule(t *testing.T) {
+	trait, environment := createNominalPrometheusTest()
+	trait.PrometheusRule = pointer.Bool(true)
+	trait.PrometheusRuleLabels = []string{"role=alert-rules"}
+	environment.Integration.Spec.Sources = []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:    "routes.yaml",
+				Content: "- route:\n    id: orders\n    from:\n      uri: timer:tick\n      steps:\n        - to: log:info\n",
+			},
+			Language: v1.LanguageYaml,
+		},
+	}
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+
+	rule := environment.Resources.GetPrometheusRule(func(r *monitoringv1.PrometheusRule) bool {
+		return r.Name == "integration-name"
+	})
+	assert.NotNil(t, rule)
+	assert.Equal(t, "alert-rules", rule.Labels["role"])
+	assert.Equal(t, "integration-name", rule.Labels[v1.IntegrationLabel])
+	assert.Len(t, rule.Spec.Groups, 1)
+	assert.Len(t, rule.Spec.Groups[0].Rules, 2)
+
+	failureRate := rule.Spec.Groups[0].Rules[0]
+	assert.Equal(t, "CamelKRouteExchangesFailureRate", failureRate.Alert)
+	assert.Contains(t, failureRate.Expr.String(), `routeId=~"orders"`)
+	assert.Contains(t, failureRate.Expr.String(), "> 10")
+
+	stopped := rule.Spec.Groups[0].Rules[1]
+	assert.Equal(t, "CamelKRoutesStopped", stopped.Alert)
+	assert.Contains(t, stopped.Expr.String(), `integration="integration-name",namespace="integration-namespace"`)
+}
+
+func TestConfigurePrometheusTraitWithInvalidFailureRateThresholdDoesNotSucceed(t *testing.T) {
+	trait, environment := createNominalPrometheusTest()
+	trait.FailureRateThreshold = pointer.Int(0)
+
+	configured, err := trait.Configure(environment)
+
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestApplyPrometheusTraitCreatesGrafanaDashboard(t *testing.T) {
+	trait, environment := createNominalPrometheusTest()
+	trait.GrafanaDashboard = pointer.Bool(true)
+	environment.Integration.Spec.Sources = []v1.SourceSpec{
+		{
+			DataSpec: v1.DataSpec{
+				Name:    "Routes.java",
+				Content: `from("timer:tick").routeId("ticker").to("log:info"); from("direct:orders").routeId("orders.v2").to("log:info");`,
+			},
+			Language: v1.LanguageJavaSource,
+		},
+	}
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+
+	dashboard := environment.Resources.GetConfigMap(func(cm *corev1.ConfigMap) bool {
+		return cm.Name == "integration-name-grafana-dashboard"
+	})
+	assert.NotNil(t, dashboard)
+	assert.Equal(t, "1", dashboard.Labels["grafana_dashboard"])
+	assert.Equal(t, "integration-name", dashboard.Labels[v1.IntegrationLabel])
+
+	var content map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(dashboard.Data["dashboard.json"]), &content))
+	panels, ok := content["panels"].([]interface{})
+	assert.True(t, ok)
+	assert.Len(t, panels, 4)
+	assert.Equal(t, "Route ticker", panels[2].(map[string]interface{})["title"])
+	assert.Equal(t, "Route orders.v2", panels[3].(map[string]interface{})["title"])
+	assert.Contains(t, dashboard.Data["dashboard.json"], `routeId=~\"orders\\\\.v2\"`)
+
+	assert.Nil(t, environment.Resources.GetPrometheusRule(func(r *monitoringv1.PrometheusRule) bool { return true }))
+}
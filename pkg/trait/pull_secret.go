@@ -19,19 +19,25 @@ package trait
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/openshift"
+	"github.com/apache/camel-k/pkg/util/registry"
 )
 
 // The Pull Secret trait sets a pull secret on the pod,
@@ -45,6 +51,14 @@ import (
 //
 // If your registry does not need authentication for pulling images, you can disable this trait.
 //
+// When the platform registry secret lives in another namespace than the Integration, e.g., with a global operator,
+// it can be copied into the namespace of the Integration, with the `sync` option, and the copy is refreshed periodically.
+// The copy is deleted once all the Integrations using it are deleted.
+//
+// The short-lived tokens of the cloud registries, i.e., Amazon ECR, Google Container Registry or Artifact Registry,
+// and Azure Container Registry, can be obtained by the operator, from the workload identity of its service account,
+// with the `provider` option. They are stored into a pull secret of the Integration namespace, and renewed periodically.
+//
 // +camel-k:trait=pull-secret.
 type pullSecretTrait struct {
 	BaseTrait `property:",squash"`
@@ -54,8 +68,39 @@ type pullSecretTrait struct {
 	ImagePullerDelegation *bool `property:"image-puller-delegation" json:"imagePullerDelegation,omitempty"`
 	// Automatically configures the platform registry secret on the pod if it is of type `kubernetes.io/dockerconfigjson`.
 	Auto *bool `property:"auto" json:"auto,omitempty"`
+	// Copies the platform registry secret into the namespace of the Integration, when it lives in another namespace,
+	// so that it can be referenced by the Integration Pods (default `false`).
+	Sync *bool `property:"sync" json:"sync,omitempty"`
+	// How often the copy of the platform registry secret, or the registry token, is refreshed, e.g., `30m` (default `1h`).
+	// The registry tokens are also renewed before they expire.
+	RefreshInterval string `property:"refresh-interval" json:"refreshInterval,omitempty"`
+	// The provider of the tokens of the platform registry, i.e., `ecr`, `gcr` or `acr`. The operator exchanges the
+	// workload identity credentials of its service account for a token pulling the images of the platform registry,
+	// that is stored into the pull secret of the Integration namespace, instead of the platform registry secret.
+	Provider string `property:"provider" json:"provider,omitempty"`
+	// Adds the pull secret to the image pull secrets of the service account running the Integration, rather than to the
+	// Integration Pods, so that it's also used by the other workloads running with the same service account.
+	// The `default` service account of the namespace is never modified, and the pull secret is set on the Integration Pods
+	// when the Integration runs with it.
+	LinkServiceAccount *bool `property:"link-service-account" json:"linkServiceAccount,omitempty"`
+
+	// source is the platform registry secret to copy into the namespace of the Integration
+	source *corev1.Secret
+	// tokenProvider obtains the tokens of the platform registry
+	tokenProvider registry.TokenProvider
 }
 
+const (
+	// pullSecretSourceAnnotation records the platform registry secret a pull secret has been copied from
+	pullSecretSourceAnnotation = "camel.apache.org/pull-secret.source"
+	// pullSecretCopyLabel marks the pull secrets copied from the platform registry secret, or holding a registry token
+	pullSecretCopyLabel = "camel.apache.org/pull-secret.copy"
+	// pullSecretTokenName is the default name of the pull secrets holding a registry token
+	pullSecretTokenName = "camel-k-registry-token"
+
+	defaultPullSecretRefreshInterval = time.Hour
+)
+
 func newPullSecretTrait() Trait {
 	return &pullSecretTrait{
 		BaseTrait: NewBaseTrait("pull-secret", 1700),
@@ -71,17 +116,40 @@ func (t *pullSecretTrait) Configure(e *Environment) (bool, error) {
 		return false, nil
 	}
 
+	if _, err := t.refreshInterval(); err != nil {
+		return false, &ConfigurationError{Trait: t.ID(), Err: err}
+	}
+
+	if t.Provider != "" {
+		if t.tokenProvider == nil {
+			provider, err := registry.GetTokenProvider(t.Provider)
+			if err != nil {
+				return false, &ConfigurationError{Trait: t.ID(), Err: err}
+			}
+			t.tokenProvider = provider
+		}
+		if e.Platform.Status.Build.Registry.Address == "" {
+			return false, &ConfigurationError{Trait: t.ID(), Err: errors.New("the platform registry address is required to obtain its tokens")}
+		}
+		if t.SecretName == "" {
+			t.SecretName = pullSecretTokenName
+		}
+	}
+
 	if pointer.BoolDeref(t.Auto, true) {
 		if t.SecretName == "" {
 			secret := e.Platform.Status.Build.Registry.Secret
 			if secret != "" {
-				key := ctrl.ObjectKey{Namespace: e.Platform.Namespace, Name: secret}
+				key := ctrl.ObjectKey{Namespace: e.Tenant.RegistryNamespace(e.Platform), Name: secret}
 				obj := corev1.Secret{}
 				if err := t.Client.Get(e.Ctx, key, &obj); err != nil {
 					return false, err
 				}
 				if obj.Type == corev1.SecretTypeDockerConfigJson {
 					t.SecretName = secret
+					if key.Namespace != e.Integration.Namespace && pointer.BoolDeref(t.Sync, false) {
+						t.source = &obj
+					}
 				}
 			}
 		}
@@ -95,7 +163,7 @@ func (t *pullSecretTrait) Configure(e *Environment) (bool, error) {
 				}
 			}
 			isOperatorGlobal := platform.IsCurrentOperatorGlobal()
-			isKitExternal := e.Integration.GetIntegrationKitNamespace(e.Platform) != e.Integration.Namespace
+			isKitExternal := e.Tenant.IntegrationKitNamespace(e.Integration, e.Platform) != e.Integration.Namespace
 			needsDelegation := isOpenshift && isOperatorGlobal && isKitExternal
 			t.ImagePullerDelegation = &needsDelegation
 		}
@@ -105,12 +173,31 @@ func (t *pullSecretTrait) Configure(e *Environment) (bool, error) {
 }
 
 func (t *pullSecretTrait) Apply(e *Environment) error {
+	if t.tokenProvider != nil {
+		if err := t.renewToken(e); err != nil {
+			return err
+		}
+	}
+	if t.source != nil {
+		if err := t.syncSecret(e); err != nil {
+			return err
+		}
+	}
 	if t.SecretName != "" {
-		e.Resources.VisitPodSpec(func(p *corev1.PodSpec) {
-			p.ImagePullSecrets = append(p.ImagePullSecrets, corev1.LocalObjectReference{
-				Name: t.SecretName,
+		linked := false
+		if pointer.BoolDeref(t.LinkServiceAccount, false) {
+			var err error
+			if linked, err = t.linkServiceAccount(e); err != nil {
+				return err
+			}
+		}
+		if !linked {
+			e.Resources.VisitPodSpec(func(p *corev1.PodSpec) {
+				p.ImagePullSecrets = append(p.ImagePullSecrets, corev1.LocalObjectReference{
+					Name: t.SecretName,
+				})
 			})
-		})
+		}
 	}
 	if pointer.BoolDeref(t.ImagePullerDelegation, false) {
 		if err := t.delegateImagePuller(e); err != nil {
@@ -121,6 +208,196 @@ func (t *pullSecretTrait) Apply(e *Environment) error {
 	return nil
 }
 
+func (t *pullSecretTrait) refreshInterval() (time.Duration, error) {
+	if t.RefreshInterval == "" {
+		return defaultPullSecretRefreshInterval, nil
+	}
+	interval, err := time.ParseDuration(t.RefreshInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid refresh interval %q: %w", t.RefreshInterval, err)
+	}
+	if interval < time.Minute {
+		return 0, fmt.Errorf("invalid refresh interval %q: must be at least 1m", t.RefreshInterval)
+	}
+	return interval, nil
+}
+
+// syncSecret copies the platform registry secret into the namespace of the Integration, and schedules its refresh.
+func (t *pullSecretTrait) syncSecret(e *Environment) error {
+	source := fmt.Sprintf("%s/%s", t.source.Namespace, t.source.Name)
+	description := fmt.Sprintf("copy the registry secret %s", source)
+	if err := t.applyPullSecret(e, t.source.Name, source, description, t.source.Type, t.source.Data); err != nil {
+		return err
+	}
+
+	interval, err := t.refreshInterval()
+	if err != nil {
+		return err
+	}
+	e.RequeueNoLaterThan(interval)
+
+	return nil
+}
+
+// renewToken stores a token of the platform registry, obtained from the provider, into the pull secret of the
+// namespace of the Integration, and schedules its renewal, no later than the refresh interval, or the token expiration.
+func (t *pullSecretTrait) renewToken(e *Environment) error {
+	host := registryHost(e.Platform.Status.Build.Registry.Address)
+	token, err := t.tokenProvider.Token(e.Ctx, host)
+	if err != nil {
+		return errors.Wrapf(err, "cannot obtain a token of registry %s from provider %s", host, t.Provider)
+	}
+	config, err := registry.Auth{Server: host, Username: token.Username, Password: token.Password}.GenerateDockerConfig()
+	if err != nil {
+		return err
+	}
+
+	source := fmt.Sprintf("%s:%s", t.Provider, host)
+	description := fmt.Sprintf("store the token of registry %s", host)
+	data := map[string][]byte{corev1.DockerConfigJsonKey: config}
+	if err := t.applyPullSecret(e, t.SecretName, source, description, corev1.SecretTypeDockerConfigJson, data); err != nil {
+		return err
+	}
+
+	interval, err := t.refreshInterval()
+	if err != nil {
+		return err
+	}
+	if renewAfter := token.RenewAfter(time.Now()); renewAfter < interval {
+		interval = renewAfter
+		if interval < time.Minute {
+			interval = time.Minute
+		}
+	}
+	e.RequeueNoLaterThan(interval)
+
+	return nil
+}
+
+// applyPullSecret creates or updates the pull secret of the given name in the namespace of the Integration, unless
+// a secret from another source exists with the same name. The pull secret is shared by the Integrations of the namespace,
+// so that each of them is added to its owners, and the secret is garbage collected once they are all deleted.
+// nolint: lll
+func (t *pullSecretTrait) applyPullSecret(e *Environment, name string, source string, description string, secretType corev1.SecretType, data map[string][]byte) error {
+	existing := corev1.Secret{}
+	err := e.Client.Get(e.Ctx, ctrl.ObjectKey{Namespace: e.Integration.Namespace, Name: name}, &existing)
+	switch {
+	case err == nil && existing.Annotations[pullSecretSourceAnnotation] != source:
+		return fmt.Errorf("cannot %s into namespace %s, as another secret named %s exists",
+			description, e.Integration.Namespace, name)
+	case err != nil && !k8serrors.IsNotFound(err):
+		return err
+	}
+
+	owners := existing.OwnerReferences
+	if !hasOwner(owners, e.Integration) {
+		owners = append(owners, metav1.OwnerReference{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.IntegrationKind,
+			Name:       e.Integration.Name,
+			UID:        e.Integration.UID,
+		})
+	}
+
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: e.Integration.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				kubernetes.CamelCreatorLabelKind:      v1.IntegrationPlatformKind,
+				kubernetes.CamelCreatorLabelName:      e.Platform.Name,
+				kubernetes.CamelCreatorLabelNamespace: e.Platform.Namespace,
+				pullSecretCopyLabel:                   True,
+			},
+			Annotations: map[string]string{
+				pullSecretSourceAnnotation: source,
+			},
+			OwnerReferences: owners,
+		},
+		Type: secretType,
+		Data: data,
+	}
+
+	if err := kubernetes.ReplaceResource(e.Ctx, e.Client, secret); err != nil {
+		return errors.Wrapf(err, "cannot %s", description)
+	}
+	return nil
+}
+
+// registryHost returns the host of the registry address, that may include a scheme and an organization.
+func registryHost(address string) string {
+	host := address
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	return host
+}
+
+// linkServiceAccount adds the pull secret to the service account running the Integration, either the one
+// generated by the other traits, or the one set on the Integration. It returns false when the Integration runs with
+// the default service account of the namespace, which is shared by all its workloads, and isn't modified.
+func (t *pullSecretTrait) linkServiceAccount(e *Environment) (bool, error) {
+	reference := corev1.LocalObjectReference{Name: t.SecretName}
+
+	name := e.Integration.Spec.ServiceAccountName
+	generated := false
+	e.Resources.Visit(func(o runtime.Object) {
+		if sa, ok := o.(*corev1.ServiceAccount); ok && sa.Namespace == e.Integration.Namespace {
+			name = sa.Name
+			generated = true
+			if !hasPullSecret(sa, reference) {
+				sa.ImagePullSecrets = append(sa.ImagePullSecrets, reference)
+			}
+		}
+	})
+	if generated {
+		return true, nil
+	}
+	if name == "" || name == "default" {
+		return false, nil
+	}
+
+	sa := corev1.ServiceAccount{}
+	if err := e.Client.Get(e.Ctx, ctrl.ObjectKey{Namespace: e.Integration.Namespace, Name: name}, &sa); err != nil {
+		return false, errors.Wrapf(err, "cannot link the pull secret to the %s service account", name)
+	}
+	if hasPullSecret(&sa, reference) {
+		return true, nil
+	}
+	patch := ctrl.MergeFrom(sa.DeepCopy())
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, reference)
+	if err := e.Client.Patch(e.Ctx, &sa, patch); err != nil {
+		return false, errors.Wrapf(err, "cannot link the pull secret to the %s service account", name)
+	}
+
+	return true, nil
+}
+
+func hasOwner(references []metav1.OwnerReference, integration *v1.Integration) bool {
+	for _, reference := range references {
+		if reference.Kind == v1.IntegrationKind && reference.UID == integration.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPullSecret(sa *corev1.ServiceAccount, reference corev1.LocalObjectReference) bool {
+	for _, secret := range sa.ImagePullSecrets {
+		if secret.Name == reference.Name {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *pullSecretTrait) delegateImagePuller(e *Environment) error {
 	// Applying the RoleBinding directly because it's a resource in the operator namespace
 	// (different from the integration namespace when delegation is enabled).
@@ -132,7 +409,7 @@ func (t *pullSecretTrait) delegateImagePuller(e *Environment) error {
 }
 
 func (t *pullSecretTrait) newImagePullerRoleBinding(e *Environment) *rbacv1.RoleBinding {
-	targetNamespace := e.Integration.GetIntegrationKitNamespace(e.Platform)
+	targetNamespace := e.Tenant.IntegrationKitNamespace(e.Integration, e.Platform)
 	var references []metav1.OwnerReference
 	if e.Platform != nil && e.Platform.Namespace == targetNamespace {
 		controller := true
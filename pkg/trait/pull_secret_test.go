@@ -19,15 +19,20 @@ package trait
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/registry"
 	"github.com/apache/camel-k/pkg/util/test"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -93,6 +98,346 @@ func TestPullSecretImagePullerDelegation(t *testing.T) {
 	assert.Len(t, roleBinding.Subjects, 1)
 }
 
+func TestPullSecretAutoFromTenantRegistry(t *testing.T) {
+	e, deployment := getEnvironmentAndDeployment(t)
+
+	pl := &v1.IntegrationPlatform{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "camel-k",
+		},
+	}
+	pl.Status.Build.Registry.Address = "registry.platform.svc"
+	pl.Status.Build.Registry.Secret = "platform-registry"
+	e.Tenant = &platform.Tenant{
+		Namespace: "test",
+		Registry: v1.RegistrySpec{
+			Address: "registry.tenant.svc",
+			Secret:  "tenant-registry",
+		},
+	}
+	e.Platform = e.Tenant.ApplyTo(pl)
+	assert.Equal(t, "registry.tenant.svc", e.Platform.Status.Build.Registry.Address)
+	assert.Equal(t, "test", e.Platform.Status.Build.Registry.Organization)
+	assert.Equal(t, "platform-registry", pl.Status.Build.Registry.Secret)
+
+	err := e.Client.Create(e.Ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "tenant-registry",
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+	})
+	assert.NoError(t, err)
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "tenant-registry"}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+}
+
+func TestPullSecretSyncFromPlatformNamespace(t *testing.T) {
+	e, deployment := getEnvironmentAndDeployment(t)
+	e.Platform = &v1.IntegrationPlatform{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "camel-k",
+		},
+	}
+	e.Platform.Status.Build.Registry.Secret = "registry"
+
+	data := map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)}
+	err := e.Client.Create(e.Ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "registry",
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: data,
+	})
+	assert.NoError(t, err)
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	trait.Sync = pointer.Bool(true)
+	trait.RefreshInterval = "30m"
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+	assert.Equal(t, 30*time.Minute, e.RequeueAfter)
+
+	var secret corev1.Secret
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: "registry"}, &secret)
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeDockerConfigJson, secret.Type)
+	assert.Equal(t, data, secret.Data)
+	assert.Equal(t, "operator/registry", secret.Annotations[pullSecretSourceAnnotation])
+	assert.Equal(t, True, secret.Labels[pullSecretCopyLabel])
+	assert.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "myit", secret.OwnerReferences[0].Name)
+
+	// The copy is refreshed when the Integration is reconciled again
+	var source corev1.Secret
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "operator", Name: "registry"}, &source)
+	assert.NoError(t, err)
+	data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{"registry":{"auth":"bmljOnBhc3M="}}}`)
+	source.Data = data
+	err = e.Client.Update(e.Ctx, &source)
+	assert.NoError(t, err)
+
+	trait, _ = newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	trait.Sync = pointer.Bool(true)
+	enabled, err = trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: "registry"}, &secret)
+	assert.NoError(t, err)
+	assert.Equal(t, data, secret.Data)
+	assert.Len(t, secret.OwnerReferences, 1)
+}
+
+func TestPullSecretDoesNotSyncByDefault(t *testing.T) {
+	e, deployment := getEnvironmentAndDeployment(t)
+	e.Platform = &v1.IntegrationPlatform{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "camel-k",
+		},
+	}
+	e.Platform.Status.Build.Registry.Secret = "registry"
+
+	err := e.Client.Create(e.Ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "registry",
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+	})
+	assert.NoError(t, err)
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "registry"}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	var secret corev1.Secret
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: "registry"}, &secret)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestPullSecretSyncDoesNotOverrideExistingSecret(t *testing.T) {
+	e, _ := getEnvironmentAndDeployment(t)
+	e.Platform = &v1.IntegrationPlatform{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "camel-k",
+		},
+	}
+	e.Platform.Status.Build.Registry.Secret = "registry"
+
+	for _, namespace := range []string{"operator", "test"} {
+		err := e.Client.Create(e.Ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "registry",
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+		})
+		assert.NoError(t, err)
+	}
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	trait.Sync = pointer.Bool(true)
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.EqualError(t, err, "cannot copy the registry secret operator/registry into namespace test, as another secret named registry exists")
+}
+
+func TestPullSecretLinkServiceAccount(t *testing.T) {
+	e, deployment := getEnvironmentAndDeployment(t)
+	e.Integration.Spec.ServiceAccountName = "integration"
+	err := e.Client.Create(e.Ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "integration",
+		},
+	})
+	assert.NoError(t, err)
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.SecretName = "xxxy"
+	trait.LinkServiceAccount = pointer.Bool(true)
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.Empty(t, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	var sa corev1.ServiceAccount
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: "integration"}, &sa)
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "xxxy"}}, sa.ImagePullSecrets)
+
+	// The secret is only linked once
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: "integration"}, &sa)
+	assert.NoError(t, err)
+	assert.Len(t, sa.ImagePullSecrets, 1)
+}
+
+func TestPullSecretDoesNotLinkDefaultServiceAccount(t *testing.T) {
+	e, deployment := getEnvironmentAndDeployment(t)
+	err := e.Client.Create(e.Ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "default",
+		},
+	})
+	assert.NoError(t, err)
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.SecretName = "xxxy"
+	trait.LinkServiceAccount = pointer.Bool(true)
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "xxxy"}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	var sa corev1.ServiceAccount
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: "default"}, &sa)
+	assert.NoError(t, err)
+	assert.Empty(t, sa.ImagePullSecrets)
+}
+
+type fakeRegistryTokenProvider struct {
+	registries []string
+	ttl        time.Duration
+}
+
+func (p *fakeRegistryTokenProvider) Token(_ context.Context, host string) (registry.Token, error) {
+	p.registries = append(p.registries, host)
+	return registry.Token{
+		Username:  "AWS",
+		Password:  fmt.Sprintf("token-%d", len(p.registries)),
+		ExpiresAt: time.Now().Add(p.ttl),
+	}, nil
+}
+
+func TestPullSecretRenewsRegistryToken(t *testing.T) {
+	e, deployment := getEnvironmentAndDeployment(t)
+	e.Platform = &v1.IntegrationPlatform{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "camel-k",
+		},
+	}
+	e.Platform.Status.Build.Registry.Address = "123456789012.dkr.ecr.eu-west-1.amazonaws.com/camel-k"
+	provider := &fakeRegistryTokenProvider{ttl: 12 * time.Hour}
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	trait.Provider = registry.ProviderECR
+	trait.tokenProvider = provider
+	enabled, err := trait.Configure(e)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"123456789012.dkr.ecr.eu-west-1.amazonaws.com"}, provider.registries)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: pullSecretTokenName}}, deployment.Spec.Template.Spec.ImagePullSecrets)
+	assert.Equal(t, time.Hour, e.RequeueAfter)
+
+	var secret corev1.Secret
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: pullSecretTokenName}, &secret)
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeDockerConfigJson, secret.Type)
+	assert.JSONEq(t, `{"auths":{"123456789012.dkr.ecr.eu-west-1.amazonaws.com":{"auth":"QVdTOnRva2VuLTE="}}}`,
+		string(secret.Data[corev1.DockerConfigJsonKey]))
+	assert.Equal(t, "ecr:123456789012.dkr.ecr.eu-west-1.amazonaws.com", secret.Annotations[pullSecretSourceAnnotation])
+	assert.Equal(t, True, secret.Labels[pullSecretCopyLabel])
+	assert.Len(t, secret.OwnerReferences, 1)
+
+	// The token is renewed before it expires, when it expires before the refresh interval
+	provider.ttl = 30 * time.Minute
+	e.RequeueAfter = 0
+	trait, _ = newPullSecretTrait().(*pullSecretTrait)
+	trait.Client = e.Client
+	trait.ImagePullerDelegation = pointer.Bool(false)
+	trait.Provider = registry.ProviderECR
+	trait.tokenProvider = provider
+	_, err = trait.Configure(e)
+	assert.Nil(t, err)
+	err = trait.Apply(e)
+	assert.Nil(t, err)
+	assert.True(t, e.RequeueAfter > 24*time.Minute && e.RequeueAfter <= 25*time.Minute)
+	err = e.Client.Get(e.Ctx, client.ObjectKey{Namespace: "test", Name: pullSecretTokenName}, &secret)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"auths":{"123456789012.dkr.ecr.eu-west-1.amazonaws.com":{"auth":"QVdTOnRva2VuLTI="}}}`,
+		string(secret.Data[corev1.DockerConfigJsonKey]))
+}
+
+func TestPullSecretWithInvalidProvider(t *testing.T) {
+	e, _ := getEnvironmentAndDeployment(t)
+	e.Platform = &v1.IntegrationPlatform{}
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.Provider = "quay"
+	enabled, err := trait.Configure(e)
+	assert.EqualError(t, err, "invalid pull-secret trait configuration: unsupported registry token provider quay, expected ecr, gcr or acr")
+	assert.False(t, enabled)
+
+	trait, _ = newPullSecretTrait().(*pullSecretTrait)
+	trait.Provider = registry.ProviderGCR
+	enabled, err = trait.Configure(e)
+	assert.EqualError(t, err, "invalid pull-secret trait configuration: the platform registry address is required to obtain its tokens")
+	assert.False(t, enabled)
+}
+
+func TestPullSecretWithInvalidRefreshInterval(t *testing.T) {
+	e, _ := getEnvironmentAndDeployment(t)
+
+	trait, _ := newPullSecretTrait().(*pullSecretTrait)
+	trait.SecretName = "xxxy"
+	trait.RefreshInterval = "10s"
+	enabled, err := trait.Configure(e)
+	assert.EqualError(t, err, `invalid pull-secret trait configuration: invalid refresh interval "10s": must be at least 1m`)
+	assert.False(t, enabled)
+}
+
 func getEnvironmentAndDeployment(t *testing.T) (*Environment, *appsv1.Deployment) {
 	t.Helper()
 
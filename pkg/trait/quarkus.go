@@ -21,10 +21,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/rs/xid"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
@@ -52,10 +54,10 @@ var kitPriority = map[quarkusPackageType]string{
 // It's enabled by default.
 //
 // NOTE: Compiling to a native executable, i.e. when using `package-type=native`, is only supported
-// for kamelets, as well as YAML and XML integrations.
+// for kamelets, as well as YAML and XML integrations, that do not depend on third-party Maven artifacts.
 // It also requires at least 4GiB of memory, so the Pod running the native build, that is either
 // the operator Pod, or the build Pod (depending on the build strategy configured for the platform),
-// must have enough memory available.
+// must have enough memory available. The resources of the build Pod can be set with the `native-build-*` options.
 //
 // +camel-k:trait=quarkus.
 type quarkusTrait struct {
@@ -67,6 +69,21 @@ type quarkusTrait struct {
 	// The kit corresponding to the first package type will be assigned to the
 	// integration in case no existing kit that matches the integration exists.
 	PackageTypes []quarkusPackageType `property:"package-type" json:"packageTypes,omitempty"`
+	// Bakes an AppCDS archive into the `fast-jar` kit image, to reduce the JVM startup time, e.g., of the
+	// Knative services scaling from zero. The archive is dumped from a training run of the kit runtime at image build,
+	// so that it is not supported by the `Spectrum` publish strategy.
+	AppCDS *bool `property:"app-cds" json:"appCDS,omitempty"`
+	// Builds the `fast-jar` kit in place of the `native` one, when the integration has sources, or dependencies,
+	// that cannot be compiled to a native executable, instead of failing the integration.
+	NativeFallback *bool `property:"native-fallback" json:"nativeFallback,omitempty"`
+	// The minimum amount of CPU required by the native build, when using the `pod` build strategy.
+	NativeBuildRequestCPU string `property:"native-build-request-cpu" json:"nativeBuildRequestCPU,omitempty"`
+	// The minimum amount of memory required by the native build, when using the `pod` build strategy.
+	NativeBuildRequestMemory string `property:"native-build-request-memory" json:"nativeBuildRequestMemory,omitempty"`
+	// The maximum amount of CPU the native build can use, when using the `pod` build strategy.
+	NativeBuildLimitCPU string `property:"native-build-limit-cpu" json:"nativeBuildLimitCPU,omitempty"`
+	// The maximum amount of memory the native build can use, when using the `pod` build strategy.
+	NativeBuildLimitMemory string `property:"native-build-limit-memory" json:"nativeBuildLimitMemory,omitempty"`
 }
 
 func newQuarkusTrait() Trait {
@@ -97,6 +114,16 @@ func (t *quarkusTrait) Matches(trait Trait) bool {
 		return false
 	}
 
+	if pointer.BoolDeref(qt.AppCDS, false) && !pointer.BoolDeref(t.AppCDS, false) {
+		return false
+	}
+
+	if pointer.BoolDeref(t.NativeFallback, false) {
+		// The kit has been built in JVM mode in place of the native executable,
+		// so it only matches the integrations that accept the fallback
+		return pointer.BoolDeref(qt.NativeFallback, false) && containsPackageType(qt.PackageTypes, nativePackageType)
+	}
+
 	if len(t.PackageTypes) == 0 && len(qt.PackageTypes) != 0 && !containsPackageType(qt.PackageTypes, fastJarPackageType) {
 		return false
 	}
@@ -128,58 +155,50 @@ func (t *quarkusTrait) Configure(e *Environment) (bool, error) {
 
 func (t *quarkusTrait) Apply(e *Environment) error {
 	if e.IntegrationInPhase(v1.IntegrationPhaseBuildingKit) {
+		fallback := false
 		if containsPackageType(t.PackageTypes, nativePackageType) {
-			// Native compilation is only supported for a subset of languages,
-			// so let's check for compatibility, and fail-fast the Integration,
-			// to save compute resources and user time.
-			for _, source := range e.Integration.Sources() {
-				if language := source.InferLanguage(); language != v1.LanguageKamelet &&
-					language != v1.LanguageYaml &&
-					language != v1.LanguageXML {
-					t.L.ForIntegration(e.Integration).Infof("Integration %s contains a %s source that cannot be compiled to native executable", e.Integration.Namespace+"/"+e.Integration.Name, language)
+			// Native compilation is only supported for a subset of languages and dependencies,
+			// so let's check for compatibility, and either fall back to the JVM mode,
+			// or fail-fast the Integration, to save compute resources and user time.
+			if reason, message := t.checkNativeCompatibility(e); reason != "" {
+				if !pointer.BoolDeref(t.NativeFallback, false) {
+					t.L.ForIntegration(e.Integration).Infof("Integration %s cannot be compiled to native executable: %s", e.Integration.Namespace+"/"+e.Integration.Name, message)
 					e.Integration.Status.Phase = v1.IntegrationPhaseError
 					e.Integration.Status.SetCondition(
 						v1.IntegrationConditionKitAvailable,
 						corev1.ConditionFalse,
-						v1.IntegrationConditionUnsupportedLanguageReason,
-						fmt.Sprintf("native compilation for language %q is not supported", language))
+						reason,
+						message)
 					// Let the calling controller handle the Integration update
 					return nil
 				}
+				t.L.ForIntegration(e.Integration).Infof("Integration %s cannot be compiled to native executable, falling back to JVM mode: %s", e.Integration.Namespace+"/"+e.Integration.Name, message)
+				fallback = true
 			}
 		}
 
-		switch len(t.PackageTypes) {
-		case 0:
+		switch {
+		case fallback:
 			kit := t.newIntegrationKit(e, fastJarPackageType)
+			if err := t.setKitPackageType(kit, fastJarPackageType, true); err != nil {
+				return err
+			}
 			e.IntegrationKits = append(e.IntegrationKits, *kit)
 
-		case 1:
+		case len(t.PackageTypes) == 0:
+			kit := t.newIntegrationKit(e, fastJarPackageType)
+			e.IntegrationKits = append(e.IntegrationKits, *kit)
+
+		case len(t.PackageTypes) == 1 && t.NativeFallback == nil:
 			kit := t.newIntegrationKit(e, t.PackageTypes[0])
 			e.IntegrationKits = append(e.IntegrationKits, *kit)
 
 		default:
 			for _, packageType := range t.PackageTypes {
 				kit := t.newIntegrationKit(e, packageType)
-				data, err := json.Marshal(kit.Spec.Traits[quarkusTraitID].Configuration)
-				if err != nil {
+				if err := t.setKitPackageType(kit, packageType, false); err != nil {
 					return err
 				}
-				trait := quarkusTrait{}
-				err = json.Unmarshal(data, &trait)
-				if err != nil {
-					return err
-				}
-				trait.PackageTypes = []quarkusPackageType{packageType}
-				data, err = json.Marshal(trait)
-				if err != nil {
-					return err
-				}
-				kit.Spec.Traits[quarkusTraitID] = v1.TraitSpec{
-					Configuration: v1.TraitConfiguration{
-						RawMessage: data,
-					},
-				}
 				e.IntegrationKits = append(e.IntegrationKits, *kit)
 			}
 		}
@@ -213,6 +232,7 @@ func (t *quarkusTrait) Apply(e *Environment) error {
 
 		if native {
 			build.Maven.Properties["quarkus.package.type"] = string(nativePackageType)
+			t.configureNativeBuildResources(build)
 			steps = append(steps, builder.Image.NativeImageContext)
 			// Spectrum does not rely on Dockerfile to assemble the image
 			if e.Platform.Status.Build.PublishStrategy != v1.IntegrationPlatformBuildPublishStrategySpectrum {
@@ -220,10 +240,22 @@ func (t *quarkusTrait) Apply(e *Environment) error {
 			}
 		} else {
 			build.Maven.Properties["quarkus.package.type"] = string(fastJarPackageType)
-			steps = append(steps, builder.Quarkus.ComputeQuarkusDependencies, builder.Image.IncrementalImageContext)
+			steps = append(steps, builder.Quarkus.ComputeQuarkusDependencies)
+			if t.isIncrementalImageBuild(e) {
+				steps = append(steps, builder.Image.IncrementalImageContext)
+			} else {
+				steps = append(steps, builder.Image.StandardImageContext)
+			}
+			if build.VulnerabilityScan != nil {
+				steps = append(steps, builder.Vulnerability.ScanDependencies)
+			}
 			// Spectrum does not rely on Dockerfile to assemble the image
 			if e.Platform.Status.Build.PublishStrategy != v1.IntegrationPlatformBuildPublishStrategySpectrum {
-				steps = append(steps, builder.Image.JvmDockerfile)
+				if t.isAppCDSKit(e) {
+					steps = append(steps, builder.Image.JvmAppCDSDockerfile)
+				} else {
+					steps = append(steps, builder.Image.JvmDockerfile)
+				}
 			}
 		}
 
@@ -251,7 +283,7 @@ func (t *quarkusTrait) Apply(e *Environment) error {
 
 func (t *quarkusTrait) newIntegrationKit(e *Environment, packageType quarkusPackageType) *v1.IntegrationKit {
 	integration := e.Integration
-	kit := v1.NewIntegrationKit(integration.GetIntegrationKitNamespace(e.Platform), fmt.Sprintf("kit-%s", xid.New()))
+	kit := v1.NewIntegrationKit(e.Tenant.IntegrationKitNamespace(integration, e.Platform), fmt.Sprintf("kit-%s", xid.New()))
 
 	kit.Labels = map[string]string{
 		v1.IntegrationKitTypeLabel:            v1.IntegrationKitTypePlatform,
@@ -265,6 +297,14 @@ func (t *quarkusTrait) newIntegrationKit(e *Environment, packageType quarkusPack
 		kubernetes.CamelCreatorLabelVersion:   integration.ResourceVersion,
 	}
 
+	if packageType == fastJarPackageType && t.isAppCDSKit(e) {
+		kit.Labels[v1.IntegrationKitAppCDSLabel] = "true"
+	}
+
+	if t.hasMavenCredentials(e) {
+		kit.Labels[v1.IntegrationKitCredentialsNamespaceLabel] = integration.Namespace
+	}
+
 	if kit.Annotations == nil {
 		kit.Annotations = make(map[string]string)
 	}
@@ -287,6 +327,121 @@ func (t *quarkusTrait) newIntegrationKit(e *Environment, packageType quarkusPack
 	return kit
 }
 
+// setKitPackageType restricts the Quarkus trait configuration of the kit to the given package type,
+// and only retains the native fallback option for the kits built in place of the native executable.
+func (t *quarkusTrait) setKitPackageType(kit *v1.IntegrationKit, packageType quarkusPackageType, fallback bool) error {
+	data, err := json.Marshal(kit.Spec.Traits[quarkusTraitID].Configuration)
+	if err != nil {
+		return err
+	}
+	trait := quarkusTrait{}
+	err = json.Unmarshal(data, &trait)
+	if err != nil {
+		return err
+	}
+	trait.PackageTypes = []quarkusPackageType{packageType}
+	trait.NativeFallback = nil
+	if fallback {
+		trait.NativeFallback = pointer.Bool(true)
+	}
+	data, err = json.Marshal(trait)
+	if err != nil {
+		return err
+	}
+	kit.Spec.Traits[quarkusTraitID] = v1.TraitSpec{
+		Configuration: v1.TraitConfiguration{
+			RawMessage: data,
+		},
+	}
+	return nil
+}
+
+// checkNativeCompatibility returns the condition reason and message, when the integration
+// cannot be compiled to a native executable.
+func (t *quarkusTrait) checkNativeCompatibility(e *Environment) (string, string) {
+	for _, source := range e.Integration.Sources() {
+		if language := source.InferLanguage(); language != v1.LanguageKamelet &&
+			language != v1.LanguageYaml &&
+			language != v1.LanguageXML {
+			return v1.IntegrationConditionUnsupportedLanguageReason,
+				fmt.Sprintf("native compilation for language %q is not supported", language)
+		}
+	}
+
+	for _, dependency := range e.Integration.Status.Dependencies {
+		if !isNativeDependency(e, dependency) {
+			return v1.IntegrationConditionUnsupportedDependencyReason,
+				fmt.Sprintf("native compilation for dependency %q is not supported", dependency)
+		}
+	}
+
+	return "", ""
+}
+
+// isNativeDependency returns whether the dependency is provided by a Quarkus extension, that
+// supports the native compilation, as opposed to third-party artifacts or non-Quarkus Camel components.
+func isNativeDependency(e *Environment, dependency string) bool {
+	switch {
+	case strings.HasPrefix(dependency, "camel:"):
+		if e.CamelCatalog == nil || len(e.CamelCatalog.Artifacts) == 0 {
+			return true
+		}
+		artifactID := strings.TrimPrefix(dependency, "camel:")
+		if !strings.HasPrefix(artifactID, "camel-") {
+			artifactID = "camel-quarkus-" + artifactID
+		}
+		return e.CamelCatalog.HasArtifact(artifactID)
+	case strings.HasPrefix(dependency, "mvn:"):
+		gav := strings.TrimPrefix(dependency, "mvn:")
+		for _, groupID := range []string{"org.apache.camel.quarkus:", "org.apache.camel.k:", "io.quarkus:"} {
+			if strings.HasPrefix(gav, groupID) {
+				return true
+			}
+		}
+		return false
+	case strings.HasPrefix(dependency, "registry-mvn:"),
+		strings.HasPrefix(dependency, "github:"),
+		strings.HasPrefix(dependency, "gitlab:"),
+		strings.HasPrefix(dependency, "bitbucket:"),
+		strings.HasPrefix(dependency, "gitee:"),
+		strings.HasPrefix(dependency, "azure:"):
+		return false
+	default:
+		return true
+	}
+}
+
+func (t *quarkusTrait) configureNativeBuildResources(build *v1.BuilderTask) {
+	for _, r := range []struct {
+		option   string
+		value    string
+		name     corev1.ResourceName
+		requests bool
+	}{
+		{"native-build-request-cpu", t.NativeBuildRequestCPU, corev1.ResourceCPU, true},
+		{"native-build-request-memory", t.NativeBuildRequestMemory, corev1.ResourceMemory, true},
+		{"native-build-limit-cpu", t.NativeBuildLimitCPU, corev1.ResourceCPU, false},
+		{"native-build-limit-memory", t.NativeBuildLimitMemory, corev1.ResourceMemory, false},
+	} {
+		if r.value == "" {
+			continue
+		}
+		v, err := resource.ParseQuantity(r.value)
+		if err != nil {
+			t.L.Error(err, "unable to parse quantity", r.option, r.value)
+			continue
+		}
+		list := &build.ContainerResources.Limits
+		if r.requests {
+			list = &build.ContainerResources.Requests
+		}
+		if *list == nil {
+			*list = make(corev1.ResourceList)
+		}
+		(*list)[r.name] = v
+	}
+}
+
 func (t *quarkusTrait) getKitTraits(e *Environment) map[string]v1.TraitSpec {
 	traits := make(map[string]v1.TraitSpec)
 	for name, spec := range e.Integration.Spec.Traits {
@@ -310,6 +465,41 @@ func (t *quarkusTrait) isNativeKit(e *Environment) (bool, error) {
 	}
 }
 
+func (t *quarkusTrait) isIncrementalImageBuild(e *Environment) bool {
+	// The incremental image build is configured with the builder trait
+	if e.Catalog == nil {
+		return true
+	}
+	if trait, ok := e.Catalog.GetTrait("builder").(*builderTrait); ok {
+		return pointer.BoolDeref(trait.IncrementalImageBuild, true)
+	}
+	return true
+}
+
+// hasMavenCredentials returns whether the kit is built with the Maven repository credentials of the integration,
+// that are configured with the builder trait.
+func (t *quarkusTrait) hasMavenCredentials(e *Environment) bool {
+	if e.Catalog == nil {
+		return false
+	}
+	if trait, ok := e.Catalog.GetTrait("builder").(*builderTrait); ok {
+		return len(trait.MavenCredentials) > 0
+	}
+	return false
+}
+
+// isAppCDSKit returns whether the kit image embeds an AppCDS archive, that requires the image to be built from a Dockerfile.
+func (t *quarkusTrait) isAppCDSKit(e *Environment) bool {
+	if !pointer.BoolDeref(t.AppCDS, false) {
+		return false
+	}
+	if e.Platform != nil && e.Platform.Status.Build.PublishStrategy == v1.IntegrationPlatformBuildPublishStrategySpectrum {
+		t.L.Info("The AppCDS archive is not supported by the Spectrum publish strategy")
+		return false
+	}
+	return true
+}
+
 func (t *quarkusTrait) isNativeIntegration(e *Environment) bool {
 	// The current IntegrationKit determines the Integration runtime type
 	return e.IntegrationKit.Labels[v1.IntegrationKitLayoutLabel] == v1.IntegrationKitLayoutNative
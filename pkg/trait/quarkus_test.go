@@ -18,15 +18,19 @@ limitations under the License.
 package trait
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/builder"
 	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/test"
 )
 
 func TestConfigureQuarkusTraitBuildSubmitted(t *testing.T) {
@@ -47,6 +51,26 @@ func TestConfigureQuarkusTraitBuildSubmitted(t *testing.T) {
 	assert.Len(t, build.Steps, len(builder.Quarkus.CommonSteps)+3)
 }
 
+func TestConfigureQuarkusTraitNonIncrementalImageBuild(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	environment.IntegrationKit.Status.Phase = v1.IntegrationKitPhaseBuildSubmitted
+	environment.Catalog = NewCatalog(nil)
+	builderTrait, _ := environment.Catalog.GetTrait("builder").(*builderTrait)
+	builderTrait.IncrementalImageBuild = pointer.Bool(false)
+
+	configured, err := quarkusTrait.Configure(environment)
+	assert.True(t, configured)
+	assert.Nil(t, err)
+
+	err = quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+
+	build := getBuilderTask(environment.BuildTasks)
+	assert.NotNil(t, build)
+	assert.Contains(t, build.Steps, builder.Image.StandardImageContext.ID())
+	assert.NotContains(t, build.Steps, builder.Image.IncrementalImageContext.ID())
+}
+
 func TestConfigureDisabledQuarkusTraitShouldFail(t *testing.T) {
 	quarkusTrait, environment := createNominalQuarkusTest()
 	quarkusTrait.Enabled = pointer.Bool(false)
@@ -71,6 +95,152 @@ func TestApplyQuarkusTraitDefaultKitLayout(t *testing.T) {
 	assert.Equal(t, environment.IntegrationKits[0].Labels[v1.IntegrationKitLayoutLabel], v1.IntegrationKitLayoutFastJar)
 }
 
+func TestApplyQuarkusTraitAppCDS(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.AppCDS = pointer.Bool(true)
+	environment.Integration.Status.Phase = v1.IntegrationPhaseBuildingKit
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Len(t, environment.IntegrationKits, 1)
+	assert.Equal(t, "true", environment.IntegrationKits[0].Labels[v1.IntegrationKitAppCDSLabel])
+
+	environment.Integration.Status.Phase = v1.IntegrationPhaseNone
+	environment.IntegrationKit.Status.Phase = v1.IntegrationKitPhaseBuildSubmitted
+	err = quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+
+	build := getBuilderTask(environment.BuildTasks)
+	assert.NotNil(t, build)
+	assert.Contains(t, build.Steps, builder.Image.JvmAppCDSDockerfile.ID())
+	assert.NotContains(t, build.Steps, builder.Image.JvmDockerfile.ID())
+}
+
+func TestApplyQuarkusTraitAppCDSWithSpectrum(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.AppCDS = pointer.Bool(true)
+	environment.Platform.Status.Build.PublishStrategy = v1.IntegrationPlatformBuildPublishStrategySpectrum
+	environment.Integration.Status.Phase = v1.IntegrationPhaseBuildingKit
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Len(t, environment.IntegrationKits, 1)
+	assert.NotContains(t, environment.IntegrationKits[0].Labels, v1.IntegrationKitAppCDSLabel)
+}
+
+func TestQuarkusTraitAppCDSMatches(t *testing.T) {
+	kitTrait, _ := newQuarkusTrait().(*quarkusTrait)
+	integrationTrait, _ := newQuarkusTrait().(*quarkusTrait)
+	integrationTrait.AppCDS = pointer.Bool(true)
+	assert.False(t, kitTrait.Matches(integrationTrait))
+
+	kitTrait.AppCDS = pointer.Bool(true)
+	assert.True(t, kitTrait.Matches(integrationTrait))
+
+	integrationTrait.AppCDS = nil
+	assert.True(t, kitTrait.Matches(integrationTrait))
+}
+
+func TestApplyQuarkusTraitNativeUnsupportedLanguage(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.PackageTypes = []quarkusPackageType{nativePackageType}
+	environment.Integration.Status.Phase = v1.IntegrationPhaseBuildingKit
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Empty(t, environment.IntegrationKits)
+	assert.Equal(t, v1.IntegrationPhaseError, environment.Integration.Status.Phase)
+	condition := environment.Integration.Status.GetCondition(v1.IntegrationConditionKitAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1.IntegrationConditionUnsupportedLanguageReason, condition.Reason)
+}
+
+func TestApplyQuarkusTraitNativeUnsupportedDependency(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.PackageTypes = []quarkusPackageType{nativePackageType}
+	environment.Integration.Spec.Sources[0].Language = v1.LanguageYaml
+	environment.Integration.Status.Dependencies = []string{
+		"mvn:org.apache.camel.k:camel-k-runtime",
+		"mvn:com.acme:legacy-client:1.0",
+	}
+	environment.Integration.Status.Phase = v1.IntegrationPhaseBuildingKit
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Empty(t, environment.IntegrationKits)
+	condition := environment.Integration.Status.GetCondition(v1.IntegrationConditionKitAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1.IntegrationConditionUnsupportedDependencyReason, condition.Reason)
+	assert.Contains(t, condition.Message, "mvn:com.acme:legacy-client:1.0")
+}
+
+func TestApplyQuarkusTraitNativeFallback(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.PackageTypes = []quarkusPackageType{nativePackageType}
+	quarkusTrait.NativeFallback = pointer.Bool(true)
+	environment.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"quarkus": test.TraitSpecFromMap(t, map[string]interface{}{
+			"packageTypes":   []string{"native"},
+			"nativeFallback": true,
+		}),
+	}
+	environment.Catalog = NewCatalog(nil)
+	environment.Integration.Status.Phase = v1.IntegrationPhaseBuildingKit
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.IntegrationPhaseBuildingKit, environment.Integration.Status.Phase)
+	assert.Len(t, environment.IntegrationKits, 1)
+	kit := environment.IntegrationKits[0]
+	assert.Equal(t, v1.IntegrationKitLayoutFastJar, kit.Labels[v1.IntegrationKitLayoutLabel])
+
+	kitTrait, _ := newQuarkusTrait().(*quarkusTrait)
+	assert.Nil(t, json.Unmarshal(kit.Spec.Traits[quarkusTraitID].Configuration.RawMessage, kitTrait))
+	assert.Equal(t, []quarkusPackageType{fastJarPackageType}, kitTrait.PackageTypes)
+	assert.True(t, pointer.BoolDeref(kitTrait.NativeFallback, false))
+
+	// The fallback kit only matches the integrations requesting the native build with the fallback
+	assert.True(t, kitTrait.Matches(quarkusTrait))
+	jvm, _ := newQuarkusTrait().(*quarkusTrait)
+	assert.False(t, kitTrait.Matches(jvm))
+	native, _ := newQuarkusTrait().(*quarkusTrait)
+	native.PackageTypes = []quarkusPackageType{nativePackageType}
+	assert.False(t, kitTrait.Matches(native))
+}
+
+func TestApplyQuarkusTraitNativeBuildResources(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.PackageTypes = []quarkusPackageType{nativePackageType}
+	quarkusTrait.NativeBuildRequestMemory = "4Gi"
+	quarkusTrait.NativeBuildLimitCPU = "2"
+	quarkusTrait.NativeBuildLimitMemory = "8Gi"
+	environment.IntegrationKit.Status.Phase = v1.IntegrationKitPhaseBuildSubmitted
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+
+	build := getBuilderTask(environment.BuildTasks)
+	assert.NotNil(t, build)
+	assert.Equal(t, "native", build.Maven.Properties["quarkus.package.type"])
+	assert.Equal(t, resource.MustParse("4Gi"), build.ContainerResources.Requests[corev1.ResourceMemory])
+	assert.NotContains(t, build.ContainerResources.Requests, corev1.ResourceCPU)
+	assert.Equal(t, resource.MustParse("2"), build.ContainerResources.Limits[corev1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("8Gi"), build.ContainerResources.Limits[corev1.ResourceMemory])
+}
+
+func TestApplyQuarkusTraitJvmBuildResources(t *testing.T) {
+	quarkusTrait, environment := createNominalQuarkusTest()
+	quarkusTrait.NativeBuildLimitMemory = "8Gi"
+	environment.IntegrationKit.Status.Phase = v1.IntegrationKitPhaseBuildSubmitted
+
+	err := quarkusTrait.Apply(environment)
+	assert.Nil(t, err)
+
+	build := getBuilderTask(environment.BuildTasks)
+	assert.NotNil(t, build)
+	assert.Empty(t, build.ContainerResources.Limits)
+}
+
 func createNominalQuarkusTest() (*quarkusTrait, *Environment) {
 	trait, _ := newQuarkusTrait().(*quarkusTrait)
 	trait.Enabled = pointer.Bool(true)
@@ -158,7 +158,7 @@ func getOpenShiftRegistrySecret(e *Environment) (string, error) {
 }
 
 func extractMavenServerCredentialsFromSecret(registrySecret string, e *Environment, registryAddress string) (v1.Server, error) {
-	secret, err := kubernetes.GetSecret(e.Ctx, e.Client, registrySecret, e.Platform.Namespace)
+	secret, err := kubernetes.GetSecret(e.Ctx, e.Client, registrySecret, e.Tenant.RegistryNamespace(e.Platform))
 	if err != nil {
 		return v1.Server{}, err
 	}
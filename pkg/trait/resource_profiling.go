@@ -0,0 +1,125 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/utils/pointer"
+)
+
+const (
+	resourceProfilingTraitID = "resource-profiling"
+
+	defaultResourceProfilingWindow   = 24 * time.Hour
+	defaultResourceProfilingInterval = 5 * time.Minute
+	defaultResourceProfilingHeadroom = 20
+)
+
+// The Resource Profiling trait observes the actual CPU and memory usage of the Integration container, and recommends
+// the resource requests and limits to configure it with, e.g., with the `container` trait.
+//
+// The usage of the running Pods is sampled periodically from the Kubernetes resource metrics API, which requires
+// the metrics-server, or an equivalent adapter, to be installed in the cluster. At the end of each observation window,
+// the recommendation is written into the Integration status, and reported by the `ResourceRecommendationAvailable`
+// condition, as well as by the `kamel describe integration` command:
+//
+// * the CPU request is the average usage, and the CPU limit the peak usage
+// * the memory request and limit are both the peak usage, as memory can't be reclaimed from a running container
+//
+// The headroom is added to all the recommended values.
+//
+// +camel-k:trait=resource-profiling.
+type resourceProfilingTrait struct {
+	BaseTrait `property:",squash"`
+	// The duration of the observation window each recommendation is computed from (default `24h`).
+	Window string `property:"window" json:"window,omitempty"`
+	// The interval between two samples of the resource usage (default `5m`).
+	Interval string `property:"interval" json:"interval,omitempty"`
+	// The percentage added to the observed usage for the recommended values (default `20`).
+	Headroom *int `property:"headroom" json:"headroom,omitempty"`
+}
+
+// ResourceProfiling is the configuration of the resource profiling of an Integration.
+type ResourceProfiling struct {
+	Window   time.Duration
+	Interval time.Duration
+	Headroom int
+}
+
+func newResourceProfilingTrait() Trait {
+	return &resourceProfilingTrait{
+		BaseTrait: NewBaseTrait(resourceProfilingTraitID, 2700),
+	}
+}
+
+func (t *resourceProfilingTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if _, err := t.profiling(); err != nil {
+		return false, err
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *resourceProfilingTrait) Apply(e *Environment) error {
+	profiling, err := t.profiling()
+	if err != nil {
+		return err
+	}
+
+	// Make sure the Integration is reconciled again to take the next sample
+	e.RequeueNoLaterThan(profiling.Interval)
+
+	return nil
+}
+
+func (t *resourceProfilingTrait) profiling() (*ResourceProfiling, error) {
+	profiling := ResourceProfiling{
+		Window:   defaultResourceProfilingWindow,
+		Interval: defaultResourceProfilingInterval,
+		Headroom: pointer.IntDeref(t.Headroom, defaultResourceProfilingHeadroom),
+	}
+
+	if t.Window != "" {
+		window, err := time.ParseDuration(t.Window)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid resource profiling window %s", t.Window)
+		}
+		profiling.Window = window
+	}
+	if t.Interval != "" {
+		interval, err := time.ParseDuration(t.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid resource profiling interval %s", t.Interval)
+		}
+		profiling.Interval = interval
+	}
+	if profiling.Interval > profiling.Window {
+		return nil, fmt.Errorf("the resource profiling interval %s must not exceed the window %s", profiling.Interval, profiling.Window)
+	}
+	if profiling.Headroom < 0 {
+		return nil, fmt.Errorf("invalid resource profiling headroom %d, must not be negative", profiling.Headroom)
+	}
+
+	return &profiling, nil
+}
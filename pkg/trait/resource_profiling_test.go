@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestConfigureResourceProfilingTraitWithInvalidOptions(t *testing.T) {
+	resourceProfilingTrait, environment := createResourceProfilingTest()
+
+	resourceProfilingTrait.Window = "a day"
+	configured, err := resourceProfilingTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	resourceProfilingTrait.Window = "1h"
+	resourceProfilingTrait.Interval = "2h"
+	configured, err = resourceProfilingTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	resourceProfilingTrait.Interval = ""
+	resourceProfilingTrait.Headroom = pointer.Int(-10)
+	configured, err = resourceProfilingTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestResourceProfiling(t *testing.T) {
+	resourceProfilingTrait, environment := createResourceProfilingTest()
+	resourceProfilingTrait.Window = "12h"
+
+	configured, err := resourceProfilingTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, resourceProfilingTrait.Apply(environment))
+	assert.Equal(t, defaultResourceProfilingInterval, environment.RequeueAfter)
+
+	environment.ExecutedTraits = append(environment.ExecutedTraits, resourceProfilingTrait)
+	assert.Equal(t, &ResourceProfiling{
+		Window:   12 * time.Hour,
+		Interval: defaultResourceProfilingInterval,
+		Headroom: defaultResourceProfilingHeadroom,
+	}, environment.GetResourceProfiling())
+}
+
+func createResourceProfilingTest() (*resourceProfilingTrait, *Environment) {
+	_, environment := createStorageTest(1)
+
+	trait, _ := newResourceProfilingTrait().(*resourceProfilingTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	return trait, environment
+}
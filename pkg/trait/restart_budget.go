@@ -0,0 +1,135 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const (
+	restartBudgetTraitID = "restart-budget"
+
+	defaultRestartBudgetMaxRestarts = 5
+	defaultRestartBudgetLogLines    = 20
+)
+
+// RestartBudgetAction is the remediation applied when the Integration exceeds its restart budget.
+type RestartBudgetAction string
+
+const (
+	// RestartBudgetActionNotify only reports the Integration is crash-looping.
+	RestartBudgetActionNotify RestartBudgetAction = "notify"
+	// RestartBudgetActionError moves the Integration to the error phase.
+	RestartBudgetActionError RestartBudgetAction = "error"
+	// RestartBudgetActionScaleToZero moves the Integration to the error phase, and scales it to zero replicas.
+	RestartBudgetActionScaleToZero RestartBudgetAction = "scale-to-zero"
+)
+
+// The Restart Budget trait defines the remediation applied when the Integration Pods crash-loop, so that failing
+// Integrations don't consume resources indefinitely.
+//
+// Once a container of an Integration Pod has been restarted more times than the budget allows, the diagnostics are
+// collected from that Pod, i.e., the last termination state of the container, the tail of its previous logs and the Pod
+// warning events, and reported by the `RestartBudgetExceeded` condition, whose changes are notified as Integration events.
+// The remediation then depends on the action:
+//
+// * `notify`: the condition is reported, and the Pods keep being restarted
+// * `error`: the Integration is moved to the error phase
+// * `scale-to-zero`: the Integration is moved to the error phase, and its Deployment is scaled to zero replicas
+//
+// The remediation holds until the Integration is updated. The trait can be configured in the IntegrationPlatform traits,
+// so that the policy applies to all the Integrations it manages.
+// Scaling to zero is only supported for Integrations deployed as Deployments, and falls back to the `error` action otherwise.
+//
+// +camel-k:trait=restart-budget.
+type restartBudgetTrait struct {
+	BaseTrait `property:",squash"`
+	// The number of restarts of any container of an Integration Pod, above which the remediation is applied (default `5`).
+	MaxRestarts *int32 `property:"max-restarts" json:"maxRestarts,omitempty"`
+	// The remediation applied once the restart budget is exceeded, one of `notify`, `error` or `scale-to-zero` (default `error`).
+	Action RestartBudgetAction `property:"action" json:"action,omitempty"`
+	// The number of lines collected from the logs of the crash-looping container (default `20`).
+	LogLines *int64 `property:"log-lines" json:"logLines,omitempty"`
+}
+
+// RestartBudget is the configuration of the restart budget of an Integration.
+type RestartBudget struct {
+	MaxRestarts int32
+	Action      RestartBudgetAction
+	LogLines    int64
+}
+
+func newRestartBudgetTrait() Trait {
+	return &restartBudgetTrait{
+		BaseTrait: NewBaseTrait(restartBudgetTraitID, 2650),
+	}
+}
+
+func (t *restartBudgetTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if _, err := t.budget(); err != nil {
+		return false, err
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *restartBudgetTrait) Apply(e *Environment) error {
+	condition := e.Integration.Status.GetCondition(v1.IntegrationConditionRestartBudgetExceeded)
+	if condition == nil || condition.Reason != v1.IntegrationConditionRestartBudgetScaledToZeroReason {
+		return nil
+	}
+
+	// Keep the Deployment scaled to zero until the Integration is updated
+	if deployment := e.Resources.GetDeploymentForIntegration(e.Integration); deployment != nil {
+		deployment.Spec.Replicas = pointer.Int32(0)
+	}
+
+	return nil
+}
+
+func (t *restartBudgetTrait) budget() (*RestartBudget, error) {
+	budget := RestartBudget{
+		MaxRestarts: pointer.Int32Deref(t.MaxRestarts, defaultRestartBudgetMaxRestarts),
+		Action:      t.Action,
+		LogLines:    pointer.Int64Deref(t.LogLines, defaultRestartBudgetLogLines),
+	}
+
+	switch budget.Action {
+	case "":
+		budget.Action = RestartBudgetActionError
+	case RestartBudgetActionNotify, RestartBudgetActionError, RestartBudgetActionScaleToZero:
+	default:
+		return nil, fmt.Errorf("unsupported restart budget action %s, must be one of notify, error or scale-to-zero", budget.Action)
+	}
+	if budget.MaxRestarts < 0 {
+		return nil, fmt.Errorf("invalid restart budget max restarts %d, must not be negative", budget.MaxRestarts)
+	}
+	if budget.LogLines < 0 {
+		return nil, fmt.Errorf("invalid restart budget log lines %d, must not be negative", budget.LogLines)
+	}
+
+	return &budget, nil
+}
@@ -0,0 +1,127 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+func TestConfigureRestartBudgetTraitDoesSucceed(t *testing.T) {
+	restartBudgetTrait, environment := createRestartBudgetTest(t)
+
+	configured, err := restartBudgetTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	budget, err := restartBudgetTrait.budget()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(5), budget.MaxRestarts)
+	assert.Equal(t, RestartBudgetActionError, budget.Action)
+	assert.Equal(t, int64(20), budget.LogLines)
+}
+
+func TestConfigureRestartBudgetTraitIsDisabledByDefault(t *testing.T) {
+	restartBudgetTrait, environment := createRestartBudgetTest(t)
+	restartBudgetTrait.Enabled = nil
+
+	configured, err := restartBudgetTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureRestartBudgetTraitWithInvalidConfiguration(t *testing.T) {
+	restartBudgetTrait, environment := createRestartBudgetTest(t)
+
+	restartBudgetTrait.Action = "restart"
+	configured, err := restartBudgetTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	restartBudgetTrait.Action = RestartBudgetActionNotify
+	restartBudgetTrait.MaxRestarts = pointer.Int32(-1)
+	configured, err = restartBudgetTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestRestartBudgetKeepsDeploymentScaledToZero(t *testing.T) {
+	restartBudgetTrait, environment := createRestartBudgetTest(t)
+
+	// The generated Deployment is left untouched unless the Integration has been scaled to zero
+	assert.Nil(t, restartBudgetTrait.Apply(environment))
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+	environment.Integration.Status.SetCondition(v1.IntegrationConditionRestartBudgetExceeded, corev1.ConditionTrue,
+		v1.IntegrationConditionRestartBudgetExceededReason, "Pod integration-name-1, container integration restarted 6 times")
+	assert.Nil(t, restartBudgetTrait.Apply(environment))
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+	environment.Integration.Status.SetCondition(v1.IntegrationConditionRestartBudgetExceeded, corev1.ConditionTrue,
+		v1.IntegrationConditionRestartBudgetScaledToZeroReason, "Pod integration-name-1, container integration restarted 6 times")
+	assert.Nil(t, restartBudgetTrait.Apply(environment))
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+}
+
+func createRestartBudgetTest(t *testing.T) (*restartBudgetTrait, *Environment) {
+	t.Helper()
+
+	trait, _ := newRestartBudgetTrait().(*restartBudgetTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+			Labels: map[string]string{
+				v1.IntegrationLabel: "integration-name",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(2),
+		},
+	}
+
+	environment := &Environment{
+		Ctx:     context.TODO(),
+		Catalog: NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "integration-name",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseRunning,
+			},
+		},
+		Resources: kubernetes.NewCollection(deployment),
+	}
+
+	return trait, environment
+}
@@ -0,0 +1,318 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	serving "knative.dev/serving/pkg/apis/serving/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const (
+	rolloutTraitID = "rollout"
+
+	defaultRolloutInterval = time.Minute
+)
+
+var defaultRolloutSteps = []int{10, 25, 50}
+
+// The Rollout trait progressively rolls out the new revisions of the Integration, as canaries, while the previous
+// revision keeps running and serving the rest of the traffic.
+//
+// The share of the traffic routed to the canary is increased at each step, as long as the canary Pods are ready,
+// their Integration container doesn't restart more than allowed, and, when a failure rate threshold is set, their routes
+// don't fail more exchanges than allowed. The canary is promoted once the last step is complete, or rolled back as soon
+// as it's unhealthy, in which case the previous revision keeps serving the whole traffic until the Integration is updated.
+// The progress of the rollout is reported in the Integration status.
+//
+// For an Integration deployed as a Deployment, the canary runs in a separate Deployment, and the traffic is split in
+// proportion of the Pods of each revision, so that the weights are approximated according to the Integration replicas.
+// For an Integration deployed as a Knative service, the traffic is split between the Knative revisions.
+//
+// +camel-k:trait=rollout.
+type rolloutTrait struct {
+	BaseTrait `property:",squash"`
+	// The percentages of the traffic routed to the canary at each step, in increasing order (default `10,25,50`).
+	Steps []int `property:"steps" json:"steps,omitempty"`
+	// The duration of each step, during which the canary must remain healthy (default `1m`).
+	Interval string `property:"interval" json:"interval,omitempty"`
+	// The number of restarts of the canary Integration containers above which the canary is rolled back (default `0`).
+	MaxRestarts *int32 `property:"max-restarts" json:"maxRestarts,omitempty"`
+	// The percentage of the exchanges failed by the canary routes above which the canary is rolled back.
+	// The exchanges are counted from the metrics of the canary Pods, so that the Prometheus trait must be enabled.
+	// The failure rate isn't checked by default.
+	MaxFailureRate *int `property:"max-failure-rate" json:"maxFailureRate,omitempty"`
+}
+
+// Rollout is the configuration of the canary rollout of an Integration.
+type Rollout struct {
+	Steps       []int32
+	Interval    time.Duration
+	MaxRestarts int32
+	// MaxFailureRate is nil when the failure rate of the canary isn't checked
+	MaxFailureRate *int32
+}
+
+func newRolloutTrait() Trait {
+	return &rolloutTrait{
+		BaseTrait: NewBaseTrait(rolloutTraitID, 2350),
+	}
+}
+
+func (t *rolloutTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if _, err := t.rollout(); err != nil {
+		return false, err
+	}
+	if t.MaxFailureRate != nil && !isPrometheusEnabled(e) {
+		return false, fmt.Errorf("the rollout failure rate threshold requires the prometheus trait to be enabled")
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *rolloutTrait) Apply(e *Environment) error {
+	rollout, err := t.rollout()
+	if err != nil {
+		return err
+	}
+
+	if deployment := e.Resources.GetDeploymentForIntegration(e.Integration); deployment != nil {
+		return t.rolloutDeployment(e, rollout, deployment)
+	}
+	if ksvc := e.Resources.GetKnativeService(func(s *serving.Service) bool {
+		return s.Name == e.Integration.Name
+	}); ksvc != nil {
+		return t.rolloutKnativeService(e, rollout, ksvc)
+	}
+
+	return nil
+}
+
+func (t *rolloutTrait) rolloutDeployment(e *Environment, rollout *Rollout, deployment *appsv1.Deployment) error {
+	live := appsv1.Deployment{}
+	err := t.Client.Get(e.Ctx, ctrl.ObjectKeyFromObject(deployment), &live)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	status := t.reconcileStatus(e, rollout, err == nil, "")
+
+	if status.Phase == v1.RolloutPhaseProgressing {
+		stable, canary := splitReplicas(pointer.Int32Deref(deployment.Spec.Replicas, 1), status.Weight)
+		deployment.Spec.Replicas = &stable
+		canaryDeployment := canaryDeploymentFor(deployment)
+		canaryDeployment.Spec.Replicas = &canary
+		e.Resources.Add(canaryDeployment)
+		// Make sure the canary is evaluated at the end of the step
+		e.RequeueNoLaterThan(rollout.Interval)
+	} else {
+		// The canary is deleted once the stable Deployment is updated
+		e.PostActions = append(e.PostActions, func(env *Environment) error {
+			return t.deleteCanaryDeployment(env, deployment)
+		})
+	}
+
+	if status.Phase != v1.RolloutPhasePromoted {
+		// Keep the stable revision running until the canary is promoted
+		deployment.Spec.Template = live.Spec.Template
+	}
+
+	return nil
+}
+
+func (t *rolloutTrait) rolloutKnativeService(e *Environment, rollout *Rollout, ksvc *serving.Service) error {
+	live := serving.Service{}
+	err := t.Client.Get(e.Ctx, ctrl.ObjectKeyFromObject(ksvc), &live)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	ready := err == nil && live.Status.LatestReadyRevisionName != ""
+	status := t.reconcileStatus(e, rollout, ready, live.Status.LatestReadyRevisionName)
+
+	switch status.Phase {
+	case v1.RolloutPhaseProgressing:
+		if latest := live.Status.LatestCreatedRevisionName; latest != status.StableRevision {
+			status.CanaryRevision = latest
+		}
+		ksvc.Spec.Traffic = []serving.TrafficTarget{
+			{
+				RevisionName: status.StableRevision,
+				Percent:      pointer.Int64(int64(100 - status.Weight)),
+			},
+			{
+				LatestRevision: pointer.Bool(true),
+				Percent:        pointer.Int64(int64(status.Weight)),
+			},
+		}
+		e.RequeueNoLaterThan(rollout.Interval)
+	case v1.RolloutPhaseRolledBack:
+		ksvc.Spec.Traffic = []serving.TrafficTarget{
+			{
+				RevisionName: status.StableRevision,
+				Percent:      pointer.Int64(100),
+			},
+		}
+	}
+
+	return nil
+}
+
+// reconcileStatus starts the rollout of the Integration revision being deployed, unless it's the stable revision,
+// or there is no previous revision to roll out from, in which case it's deployed as is.
+func (t *rolloutTrait) reconcileStatus(e *Environment, rollout *Rollout, hasStable bool, stableRevision string) *v1.RolloutStatus {
+	digest := e.Integration.Status.Digest
+	status := e.Integration.Status.Rollout
+
+	switch {
+	case status == nil || !hasStable:
+		status = &v1.RolloutStatus{
+			Phase:          v1.RolloutPhasePromoted,
+			StableDigest:   digest,
+			StableRevision: stableRevision,
+			Weight:         100,
+		}
+	case status.StableDigest == digest:
+		if status.Phase != v1.RolloutPhasePromoted {
+			status = &v1.RolloutStatus{
+				Phase:          v1.RolloutPhasePromoted,
+				StableDigest:   digest,
+				StableRevision: status.StableRevision,
+				Weight:         100,
+				Message:        "the Integration has been reverted to the stable revision",
+			}
+		}
+	case status.CanaryDigest != digest:
+		// The previous canary, if any, is superseded and the stable revision keeps running
+		if status.Phase != v1.RolloutPhasePromoted && status.StableRevision != "" {
+			stableRevision = status.StableRevision
+		}
+		now := metav1.Now()
+		status = &v1.RolloutStatus{
+			Phase:          v1.RolloutPhaseProgressing,
+			StableDigest:   status.StableDigest,
+			StableRevision: stableRevision,
+			CanaryDigest:   digest,
+			Weight:         rollout.Steps[0],
+			StepStartTime:  &now,
+		}
+	}
+
+	e.Integration.Status.Rollout = status
+	return status
+}
+
+func (t *rolloutTrait) deleteCanaryDeployment(e *Environment, deployment *appsv1.Deployment) error {
+	canary := appsv1.Deployment{}
+	key := ctrl.ObjectKey{Namespace: deployment.Namespace, Name: canaryDeploymentName(deployment)}
+	if err := t.Client.Get(e.Ctx, key, &canary); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	return ctrl.IgnoreNotFound(t.Client.Delete(e.Ctx, &canary))
+}
+
+func (t *rolloutTrait) rollout() (*Rollout, error) {
+	rollout := Rollout{
+		Interval:    defaultRolloutInterval,
+		MaxRestarts: pointer.Int32Deref(t.MaxRestarts, 0),
+	}
+
+	steps := t.Steps
+	if len(steps) == 0 {
+		steps = defaultRolloutSteps
+	}
+	for i, step := range steps {
+		if step <= 0 || step >= 100 {
+			return nil, fmt.Errorf("invalid rollout step %d, must be a percentage between 1 and 99", step)
+		}
+		if i > 0 && step <= steps[i-1] {
+			return nil, fmt.Errorf("invalid rollout steps %v, must be in increasing order", steps)
+		}
+		rollout.Steps = append(rollout.Steps, int32(step))
+	}
+	if t.Interval != "" {
+		interval, err := time.ParseDuration(t.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid rollout interval %s", t.Interval)
+		}
+		rollout.Interval = interval
+	}
+	if rollout.MaxRestarts < 0 {
+		return nil, fmt.Errorf("invalid rollout max restarts %d, must not be negative", rollout.MaxRestarts)
+	}
+	if t.MaxFailureRate != nil {
+		if *t.MaxFailureRate < 0 || *t.MaxFailureRate >= 100 {
+			return nil, fmt.Errorf("invalid rollout max failure rate %d, must be a percentage between 0 and 99", *t.MaxFailureRate)
+		}
+		rollout.MaxFailureRate = pointer.Int32(int32(*t.MaxFailureRate))
+	}
+
+	return &rollout, nil
+}
+
+func isPrometheusEnabled(e *Environment) bool {
+	t := e.Catalog.GetTrait("prometheus")
+	return t != nil && pointer.BoolDeref(t.(*prometheusTrait).Enabled, false)
+}
+
+func canaryDeploymentName(deployment *appsv1.Deployment) string {
+	return deployment.Name + "-canary"
+}
+
+func canaryDeploymentFor(deployment *appsv1.Deployment) *appsv1.Deployment {
+	canary := deployment.DeepCopy()
+	canary.Name = canaryDeploymentName(deployment)
+	canary.Spec.Selector.MatchLabels[v1.IntegrationRolloutCanaryLabel] = True
+	if canary.Spec.Template.Labels == nil {
+		canary.Spec.Template.Labels = make(map[string]string)
+	}
+	canary.Spec.Template.Labels[v1.IntegrationRolloutCanaryLabel] = True
+
+	return canary
+}
+
+// splitReplicas returns the number of stable and canary replicas, so that the canary receives approximately
+// the given percentage of the traffic, while at least one replica of each revision runs.
+func splitReplicas(replicas int32, weight int32) (int32, int32) {
+	canary := int32(math.Ceil(float64(replicas) * float64(weight) / 100))
+	if canary < 1 {
+		canary = 1
+	}
+	stable := replicas - canary
+	if stable < 1 {
+		stable = 1
+	}
+
+	return stable, canary
+}
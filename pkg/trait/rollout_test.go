@@ -0,0 +1,298 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	serving "knative.dev/serving/pkg/apis/serving/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestConfigureRolloutTraitWithInvalidSteps(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+
+	rolloutTrait.Steps = []int{10, 100}
+	configured, err := rolloutTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	rolloutTrait.Steps = []int{50, 25}
+	configured, err = rolloutTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	rolloutTrait.Steps = []int{20, 40}
+	rolloutTrait.Interval = "forever"
+	configured, err = rolloutTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureRolloutTraitWithFailureRate(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+
+	rolloutTrait.MaxFailureRate = pointer.Int(5)
+	configured, err := rolloutTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	environment.Catalog.GetTrait("prometheus").(*prometheusTrait).Enabled = pointer.Bool(true)
+	configured, err = rolloutTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+
+	rolloutTrait.MaxFailureRate = pointer.Int(100)
+	configured, err = rolloutTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestRolloutDeploysFirstRevision(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+	injectRolloutClient(t, rolloutTrait)
+
+	assert.Nil(t, rolloutTrait.Apply(environment))
+
+	status := environment.Integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhasePromoted, status.Phase)
+	assert.Equal(t, "v2", status.StableDigest)
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Equal(t, "current", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Nil(t, findCanaryDeployment(environment.Resources))
+}
+
+func TestRolloutStartsCanary(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+	injectRolloutClient(t, rolloutTrait, newLiveRolloutDeployment())
+	environment.Integration.Status.Rollout = &v1.RolloutStatus{
+		Phase:        v1.RolloutPhasePromoted,
+		StableDigest: "v1",
+	}
+
+	assert.Nil(t, rolloutTrait.Apply(environment))
+
+	status := environment.Integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseProgressing, status.Phase)
+	assert.Equal(t, "v1", status.StableDigest)
+	assert.Equal(t, "v2", status.CanaryDigest)
+	assert.Equal(t, int32(10), status.Weight)
+	assert.NotNil(t, status.StepStartTime)
+	assert.Equal(t, defaultRolloutInterval, environment.RequeueAfter)
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Equal(t, "previous", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+
+	canary := findCanaryDeployment(environment.Resources)
+	assert.NotNil(t, canary)
+	assert.Equal(t, "current", canary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(1), *canary.Spec.Replicas)
+	assert.Equal(t, True, canary.Spec.Selector.MatchLabels[v1.IntegrationRolloutCanaryLabel])
+	assert.Equal(t, True, canary.Spec.Template.Labels[v1.IntegrationRolloutCanaryLabel])
+	assert.NotContains(t, deployment.Spec.Selector.MatchLabels, v1.IntegrationRolloutCanaryLabel)
+}
+
+func TestRolloutKeepsStableRevisionWhenRolledBack(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+	injectRolloutClient(t, rolloutTrait, newLiveRolloutDeployment())
+	environment.Integration.Status.Rollout = &v1.RolloutStatus{
+		Phase:        v1.RolloutPhaseRolledBack,
+		StableDigest: "v1",
+		CanaryDigest: "v2",
+	}
+
+	assert.Nil(t, rolloutTrait.Apply(environment))
+
+	assert.Equal(t, v1.RolloutPhaseRolledBack, environment.Integration.Status.Rollout.Phase)
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Equal(t, "previous", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(4), *deployment.Spec.Replicas)
+	assert.Nil(t, findCanaryDeployment(environment.Resources))
+	assert.Len(t, environment.PostActions, 1)
+}
+
+func TestRolloutDeploysPromotedCanary(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+	injectRolloutClient(t, rolloutTrait, newLiveRolloutDeployment(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name-canary",
+		},
+	})
+	environment.Integration.Status.Rollout = &v1.RolloutStatus{
+		Phase:        v1.RolloutPhasePromoted,
+		StableDigest: "v2",
+		CanaryDigest: "v2",
+	}
+
+	assert.Nil(t, rolloutTrait.Apply(environment))
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Equal(t, "current", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Nil(t, findCanaryDeployment(environment.Resources))
+
+	// The canary Deployment is deleted once the stable Deployment is updated
+	assert.Len(t, environment.PostActions, 1)
+	assert.Nil(t, environment.PostActions[0](environment))
+	canary := appsv1.Deployment{}
+	err := rolloutTrait.Client.Get(environment.Ctx, ctrl.ObjectKey{Namespace: "ns", Name: "integration-name-canary"}, &canary)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestRolloutSplitsKnativeTraffic(t *testing.T) {
+	rolloutTrait, environment := createRolloutTest(t)
+	ksvc := &serving.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+		},
+	}
+	environment.Resources = kubernetes.NewCollection(ksvc)
+	live := ksvc.DeepCopy()
+	live.Status.LatestReadyRevisionName = "integration-name-00001"
+	live.Status.LatestCreatedRevisionName = "integration-name-00002"
+	injectRolloutClient(t, rolloutTrait, live)
+	environment.Integration.Status.Rollout = &v1.RolloutStatus{
+		Phase:        v1.RolloutPhasePromoted,
+		StableDigest: "v1",
+	}
+
+	assert.Nil(t, rolloutTrait.Apply(environment))
+
+	status := environment.Integration.Status.Rollout
+	assert.Equal(t, v1.RolloutPhaseProgressing, status.Phase)
+	assert.Equal(t, "integration-name-00001", status.StableRevision)
+	assert.Equal(t, "integration-name-00002", status.CanaryRevision)
+	assert.Equal(t, []serving.TrafficTarget{
+		{RevisionName: "integration-name-00001", Percent: pointer.Int64(90)},
+		{LatestRevision: pointer.Bool(true), Percent: pointer.Int64(10)},
+	}, ksvc.Spec.Traffic)
+
+	status.Phase = v1.RolloutPhaseRolledBack
+	assert.Nil(t, rolloutTrait.Apply(environment))
+	assert.Equal(t, []serving.TrafficTarget{
+		{RevisionName: "integration-name-00001", Percent: pointer.Int64(100)},
+	}, ksvc.Spec.Traffic)
+}
+
+func TestSplitReplicas(t *testing.T) {
+	stable, canary := splitReplicas(1, 10)
+	assert.Equal(t, int32(1), stable)
+	assert.Equal(t, int32(1), canary)
+
+	stable, canary = splitReplicas(10, 25)
+	assert.Equal(t, int32(7), stable)
+	assert.Equal(t, int32(3), canary)
+
+	stable, canary = splitReplicas(2, 50)
+	assert.Equal(t, int32(1), stable)
+	assert.Equal(t, int32(1), canary)
+}
+
+func findCanaryDeployment(resources *kubernetes.Collection) *appsv1.Deployment {
+	return resources.GetDeployment(func(d *appsv1.Deployment) bool {
+		return d.Name == "integration-name-canary"
+	})
+}
+
+func newLiveRolloutDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "integration", Image: "previous"}},
+				},
+			},
+		},
+	}
+}
+
+func injectRolloutClient(t *testing.T, trait *rolloutTrait, objects ...runtime.Object) {
+	t.Helper()
+
+	c, err := test.NewFakeClient(objects...)
+	assert.Nil(t, err)
+	trait.InjectClient(c)
+}
+
+func createRolloutTest(t *testing.T) (*rolloutTrait, *Environment) {
+	t.Helper()
+
+	trait, _ := newRolloutTrait().(*rolloutTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+			Labels: map[string]string{
+				v1.IntegrationLabel: "integration-name",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(4),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					v1.IntegrationLabel: "integration-name",
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "integration", Image: "current"}},
+				},
+			},
+		},
+	}
+
+	environment := &Environment{
+		Ctx:     context.TODO(),
+		Catalog: NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "integration-name",
+			},
+			Status: v1.IntegrationStatus{
+				Phase:  v1.IntegrationPhaseDeploying,
+				Digest: "v2",
+			},
+		},
+		Resources: kubernetes.NewCollection(deployment),
+	}
+
+	return trait, environment
+}
@@ -18,9 +18,11 @@ limitations under the License.
 package trait
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,6 +45,13 @@ import (
 // following parameters to reference them: `tls-certificate-secret`, `tls-key-secret`, `tls-ca-certificate-secret`, `tls-destination-ca-certificate-secret`
 // See the examples section at the end of this page to see the setup options.
 //
+// The host defaults to the one templated by the IntegrationPlatform `exposure` configuration, if any. When the platform
+// secures the exposed hosts with TLS, and no TLS termination is configured, the route uses `edge` termination with the
+// wildcard certificate of the platform, or the default certificate of the router.
+//
+// When a cert-manager issuer is set, a certificate is requested for the host, and the route is created with `edge` termination,
+// unless another termination is configured, once the certificate is issued. The route is updated whenever cert-manager renews it.
+//
 // +camel-k:trait=route
 // nolint: tagliatelle
 type routeTrait struct {
@@ -93,6 +102,10 @@ type routeTrait struct {
 	//
 	// Refer to the OpenShift route documentation for additional information.
 	TLSInsecureEdgeTerminationPolicy string `property:"tls-insecure-edge-termination-policy" json:"tlsInsecureEdgeTerminationPolicy,omitempty"`
+	// The name of the cert-manager issuer the certificate of the host is requested to.
+	CertManagerIssuer string `property:"cert-manager-issuer" json:"certManagerIssuer,omitempty"`
+	// The kind of the cert-manager issuer, either `Issuer` or `ClusterIssuer` (default `Issuer`).
+	CertManagerIssuerKind string `property:"cert-manager-issuer-kind" json:"certManagerIssuerKind,omitempty"`
 
 	service *corev1.Service
 }
@@ -140,6 +153,33 @@ func (t *routeTrait) Configure(e *Environment) (bool, error) {
 		return false, nil
 	}
 
+	if t.Host == "" && e.Platform != nil {
+		exposure := e.Platform.Status.Exposure
+		t.Host = exposure.GetHost(e.Integration.Name, e.Integration.Namespace)
+		if t.Host != "" && exposure.TLS && t.TLSTermination == "" {
+			t.TLSTermination = string(routev1.TLSTerminationEdge)
+			if exposure.TLSSecret != "" {
+				t.TLSCertificateSecret = exposure.TLSSecret + "/" + corev1.TLSCertKey
+				t.TLSKeySecret = exposure.TLSSecret + "/" + corev1.TLSPrivateKeyKey
+			}
+		}
+	}
+
+	if t.CertManagerIssuer != "" {
+		if err := validateCertManagerIssuerKind("route", t.CertManagerIssuerKind); err != nil {
+			return false, err
+		}
+		if t.Host == "" {
+			return false, errors.New("cannot apply route trait: a host is required to request a certificate to cert-manager")
+		}
+		if t.TLSTermination == "" {
+			t.TLSTermination = string(routev1.TLSTerminationEdge)
+		}
+		secret := certificateSecretName(e.Integration)
+		t.TLSCertificateSecret = secret + "/" + corev1.TLSCertKey
+		t.TLSKeySecret = secret + "/" + corev1.TLSPrivateKeyKey
+	}
+
 	return true, nil
 }
 
@@ -150,6 +190,22 @@ func (t *routeTrait) Apply(e *Environment) error {
 		servicePortName = dt.(*containerTrait).ServicePortName
 	}
 
+	if t.CertManagerIssuer != "" {
+		e.Resources.Add(newCertificate(e.Integration, t.service.Namespace, t.Host, t.CertManagerIssuer, t.CertManagerIssuerKind))
+		// The route embeds the certificate, so that it can only be created once cert-manager has issued it
+		secret := certificateSecretName(e.Integration)
+		if kubernetes.LookupSecret(e.Ctx, t.Client, t.service.Namespace, secret) == nil {
+			e.Integration.Status.SetCondition(
+				v1.IntegrationConditionExposureAvailable,
+				corev1.ConditionFalse,
+				v1.IntegrationConditionRouteNotAvailableReason,
+				fmt.Sprintf("waiting for certificate %s to be issued", secret),
+			)
+			e.RequeueNoLaterThan(10 * time.Second)
+			return nil
+		}
+	}
+
 	tlsConfig, err := t.getTLSConfig(e)
 	if err != nil {
 		return err
@@ -18,7 +18,9 @@ limitations under the License.
 package trait
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/rs/xid"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +29,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
@@ -277,6 +281,61 @@ func TestRoute_Host(t *testing.T) {
 	assert.Nil(t, route.Spec.TLS)
 }
 
+func TestRoute_HostTemplate(t *testing.T) {
+	name := xid.New().String()
+	environment := createTestRouteEnvironment(t, name)
+	environment.Platform.Spec.Exposure = v1.IntegrationPlatformExposureSpec{
+		HostTemplate: "{name}.{namespace}.apps.example.com",
+		TLS:          true,
+	}
+	environment.Platform.ResyncStatusFullConfig()
+	traitsCatalog := environment.Catalog
+
+	err := traitsCatalog.apply(environment)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, environment.GetTrait("route"))
+
+	route := environment.Resources.GetRoute(func(r *routev1.Route) bool {
+		return r.ObjectMeta.Name == name
+	})
+
+	assert.NotNil(t, route)
+	assert.Equal(t, name+".test-ns.apps.example.com", route.Spec.Host)
+	assert.NotNil(t, route.Spec.TLS)
+	assert.Equal(t, routev1.TLSTerminationEdge, route.Spec.TLS.Termination)
+	assert.Empty(t, route.Spec.TLS.Certificate)
+	assert.Empty(t, route.Spec.TLS.Key)
+}
+
+func TestRoute_HostTemplate_Overridden(t *testing.T) {
+	name := xid.New().String()
+	environment := createTestRouteEnvironment(t, name)
+	environment.Platform.Spec.Exposure = v1.IntegrationPlatformExposureSpec{
+		HostTemplate: "{name}.{namespace}.apps.example.com",
+		TLS:          true,
+	}
+	environment.Platform.ResyncStatusFullConfig()
+	environment.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"route": test.TraitSpecFromMap(t, map[string]interface{}{
+			"host": host,
+		}),
+	}
+	traitsCatalog := environment.Catalog
+
+	err := traitsCatalog.apply(environment)
+
+	assert.Nil(t, err)
+
+	route := environment.Resources.GetRoute(func(r *routev1.Route) bool {
+		return r.ObjectMeta.Name == name
+	})
+
+	assert.NotNil(t, route)
+	assert.Equal(t, host, route.Spec.Host)
+	assert.Nil(t, route.Spec.TLS)
+}
+
 func TestRoute_TLS_From_Secret_reencrypt(t *testing.T) {
 	name := xid.New().String()
 	environment := createTestRouteEnvironment(t, name)
@@ -529,3 +588,100 @@ func TestRoute_WithCustomServicePort(t *testing.T) {
 		route.Spec.Port.TargetPort.StrVal,
 	)
 }
+
+func TestRoute_CertManager_WaitingForCertificate(t *testing.T) {
+	name := xid.New().String()
+	environment := createTestRouteEnvironment(t, name)
+	traitsCatalog := environment.Catalog
+
+	environment.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"route": test.TraitSpecFromMap(t, map[string]interface{}{
+			"host":              host,
+			"certManagerIssuer": "letsencrypt",
+		}),
+	}
+	err := traitsCatalog.apply(environment)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, environment.GetTrait("route"))
+	assert.True(t, environment.RequeueAfter > 0)
+
+	route := environment.Resources.GetRoute(func(r *routev1.Route) bool {
+		return r.ObjectMeta.Name == name
+	})
+	assert.Nil(t, route)
+
+	certificates := 0
+	environment.Resources.Visit(func(o runtime.Object) {
+		if u, ok := o.(*unstructured.Unstructured); ok && u.GetKind() == "Certificate" {
+			certificates++
+			assert.Equal(t, name+"-tls", u.GetName())
+			assert.Equal(t, "test-ns", u.GetNamespace())
+			kind, _, _ := unstructured.NestedString(u.Object, "spec", "issuerRef", "kind")
+			assert.Equal(t, "Issuer", kind)
+		}
+	})
+	assert.Equal(t, 1, certificates)
+
+	condition := environment.Integration.Status.GetCondition(v1.IntegrationConditionExposureAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, "waiting for certificate "+name+"-tls to be issued", condition.Message)
+}
+
+func TestRoute_CertManager_CertificateIssued(t *testing.T) {
+	name := xid.New().String()
+	environment := createTestRouteEnvironment(t, name)
+	traitsCatalog := environment.Catalog
+
+	environment.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"route": test.TraitSpecFromMap(t, map[string]interface{}{
+			"host":                  host,
+			"certManagerIssuer":     "letsencrypt",
+			"certManagerIssuerKind": "ClusterIssuer",
+		}),
+	}
+	rt, _ := traitsCatalog.GetTrait("route").(*routeTrait)
+	err := rt.Client.Create(context.TODO(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      name + "-tls",
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte(cert),
+			corev1.TLSPrivateKeyKey: []byte(key),
+		},
+	})
+	assert.Nil(t, err)
+
+	err = traitsCatalog.apply(environment)
+
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), environment.RequeueAfter)
+
+	route := environment.Resources.GetRoute(func(r *routev1.Route) bool {
+		return r.ObjectMeta.Name == name
+	})
+	assert.NotNil(t, route)
+	assert.NotNil(t, route.Spec.TLS)
+	assert.Equal(t, routev1.TLSTerminationEdge, route.Spec.TLS.Termination)
+	assert.Equal(t, host, route.Spec.Host)
+	assert.Equal(t, cert, route.Spec.TLS.Certificate)
+	assert.Equal(t, key, route.Spec.TLS.Key)
+}
+
+func TestRoute_CertManager_WithoutHost(t *testing.T) {
+	name := xid.New().String()
+	environment := createTestRouteEnvironment(t, name)
+	traitsCatalog := environment.Catalog
+
+	environment.Integration.Spec.Traits = map[string]v1.TraitSpec{
+		"route": test.TraitSpecFromMap(t, map[string]interface{}{
+			"certManagerIssuer": "letsencrypt",
+		}),
+	}
+	err := traitsCatalog.apply(environment)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, environment.GetTrait("route"))
+}
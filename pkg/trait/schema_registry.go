@@ -0,0 +1,253 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+const (
+	schemaRegistryTraitID = "schema-registry"
+
+	schemaRegistryEnvVarPrefix = "CAMEL_K_SCHEMA_REGISTRY_"
+
+	apicurioProvider  = "apicurio"
+	confluentProvider = "confluent"
+
+	avroFormat     = "avro"
+	protobufFormat = "protobuf"
+
+	apicurioRegistryVersion = "2.2.5.Final"
+	confluentVersion        = "7.1.1"
+)
+
+type schemaRegistryProvider struct {
+	// The Maven dependencies providing the serializers, for each format
+	dependencies map[string][]string
+	// The serializer and deserializer classes, for each format
+	serializers   map[string]string
+	deserializers map[string]string
+	// The Kafka client properties configuring the serializers
+	urlProperty          string
+	autoRegisterProperty string
+	credentials          func(username string, password string) map[string]string
+}
+
+var schemaRegistryProviders = map[string]schemaRegistryProvider{
+	apicurioProvider: {
+		dependencies: map[string][]string{
+			avroFormat:     {"mvn:io.quarkus:quarkus-apicurio-registry-avro"},
+			protobufFormat: {"mvn:io.apicurio:apicurio-registry-serdes-protobuf-serde:" + apicurioRegistryVersion},
+		},
+		serializers: map[string]string{
+			avroFormat:     "io.apicurio.registry.serde.avro.AvroKafkaSerializer",
+			protobufFormat: "io.apicurio.registry.serde.protobuf.ProtobufKafkaSerializer",
+		},
+		deserializers: map[string]string{
+			avroFormat:     "io.apicurio.registry.serde.avro.AvroKafkaDeserializer",
+			protobufFormat: "io.apicurio.registry.serde.protobuf.ProtobufKafkaDeserializer",
+		},
+		urlProperty:          "apicurio.registry.url",
+		autoRegisterProperty: "apicurio.registry.auto-register",
+		credentials: func(username string, password string) map[string]string {
+			return map[string]string{
+				"apicurio.auth.username": username,
+				"apicurio.auth.password": password,
+			}
+		},
+	},
+	confluentProvider: {
+		dependencies: map[string][]string{
+			avroFormat:     {"mvn:io.quarkus:quarkus-confluent-registry-avro", "mvn:io.confluent:kafka-avro-serializer:" + confluentVersion},
+			protobufFormat: {"mvn:io.confluent:kafka-protobuf-serializer:" + confluentVersion},
+		},
+		serializers: map[string]string{
+			avroFormat:     "io.confluent.kafka.serializers.KafkaAvroSerializer",
+			protobufFormat: "io.confluent.kafka.serializers.protobuf.KafkaProtobufSerializer",
+		},
+		deserializers: map[string]string{
+			avroFormat:     "io.confluent.kafka.serializers.KafkaAvroDeserializer",
+			protobufFormat: "io.confluent.kafka.serializers.protobuf.KafkaProtobufDeserializer",
+		},
+		urlProperty:          "schema.registry.url",
+		autoRegisterProperty: "auto.register.schemas",
+		credentials: func(username string, password string) map[string]string {
+			return map[string]string{
+				"basic.auth.credentials.source": "USER_INFO",
+				"basic.auth.user.info":          username + ":" + password,
+			}
+		},
+	},
+}
+
+// The Schema Registry trait configures the Kafka component of the Integration to serialize and deserialize
+// the records with Avro or Protobuf, using the schemas stored in an Apicurio or Confluent schema registry.
+//
+// It adds the serializers dependencies, and sets the serializers, the registry URL and the registry credentials
+// into the Kafka component configuration. The credentials are read from a Secret, containing the `username`
+// and `password` keys, and are injected as environment variables, so that they are never copied into the
+// Integration configuration.
+//
+// NOTE: The Confluent serializers are only available from the Confluent Maven repository, that must be added to
+// the Integration, e.g., with `kamel run --maven-repository https://packages.confluent.io/maven/`.
+//
+// +camel-k:trait=schema-registry.
+type schemaRegistryTrait struct {
+	BaseTrait `property:",squash"`
+	// The schema registry, either `apicurio` or `confluent` (default `apicurio`).
+	Provider string `property:"provider" json:"provider,omitempty"`
+	// The schema registry URL, e.g., `http://my-registry:8080/apis/registry/v2`.
+	URL string `property:"url" json:"url,omitempty"`
+	// The serialization format, either `avro` or `protobuf` (default `avro`).
+	Format string `property:"format" json:"format,omitempty"`
+	// The name of the Secret, containing the `username` and `password` keys, used to authenticate to the registry.
+	Secret string `property:"secret" json:"secret,omitempty"`
+	// Registers the schemas of the produced records that are missing from the registry.
+	AutoRegister *bool `property:"auto-register" json:"autoRegister,omitempty"`
+	// Also serializes and deserializes the record keys with the schema registry (default `false`).
+	Keys *bool `property:"keys" json:"keys,omitempty"`
+}
+
+func newSchemaRegistryTrait() Trait {
+	return &schemaRegistryTrait{
+		// Must run before the container trait, that computes the application properties
+		BaseTrait: NewBaseTrait(schemaRegistryTraitID, 1175),
+	}
+}
+
+func (t *schemaRegistryTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInPhase(v1.IntegrationPhaseInitialization) && !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if _, ok := schemaRegistryProviders[t.provider()]; !ok {
+		return false, fmt.Errorf("unsupported schema registry %s, must be %s or %s", t.Provider, apicurioProvider, confluentProvider)
+	}
+	switch t.format() {
+	case avroFormat, protobufFormat:
+	default:
+		return false, fmt.Errorf("unsupported serialization format %s, must be %s or %s", t.Format, avroFormat, protobufFormat)
+	}
+	if u, err := url.Parse(t.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		return false, fmt.Errorf("invalid schema registry url %q", t.URL)
+	}
+
+	if t.Secret != "" && e.IntegrationInRunningPhases() {
+		secret, err := kubernetes.GetSecret(e.Ctx, e.Client, t.Secret, e.Integration.Namespace)
+		if err != nil {
+			return false, fmt.Errorf("unable to get schema registry secret %s: %w", t.Secret, err)
+		}
+		for _, key := range []string{"username", "password"} {
+			if _, ok := secret.Data[key]; !ok {
+				return false, fmt.Errorf("schema registry secret %s has no %s key", t.Secret, key)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func (t *schemaRegistryTrait) Apply(e *Environment) error {
+	provider := schemaRegistryProviders[t.provider()]
+	format := t.format()
+
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		for _, dependency := range provider.dependencies[format] {
+			util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, dependency)
+		}
+		return nil
+	}
+
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+
+	e.ApplicationProperties["camel.component.kafka.value-serializer"] = provider.serializers[format]
+	e.ApplicationProperties["camel.component.kafka.value-deserializer"] = provider.deserializers[format]
+	if pointer.BoolDeref(t.Keys, false) {
+		e.ApplicationProperties["camel.component.kafka.key-serializer"] = provider.serializers[format]
+		e.ApplicationProperties["camel.component.kafka.key-deserializer"] = provider.deserializers[format]
+	}
+
+	properties := map[string]string{
+		provider.urlProperty: t.URL,
+	}
+	if t.AutoRegister != nil {
+		properties[provider.autoRegisterProperty] = strconv.FormatBool(*t.AutoRegister)
+	}
+	if t.Secret != "" {
+		for _, key := range []string{"username", "password"} {
+			envvar.SetVar(&e.EnvVars, corev1.EnvVar{
+				Name: schemaRegistryEnvVar(key),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: t.Secret,
+						},
+						Key: key,
+					},
+				},
+			})
+		}
+		credentials := provider.credentials(
+			fmt.Sprintf("${%s}", schemaRegistryEnvVar("username")),
+			fmt.Sprintf("${%s}", schemaRegistryEnvVar("password")))
+		for k, v := range credentials {
+			properties[k] = v
+		}
+	}
+	// The serializers are configured with the additional properties of the Kafka clients
+	for k, v := range properties {
+		e.ApplicationProperties[fmt.Sprintf("camel.component.kafka.additional-properties[%s]", k)] = v
+	}
+
+	return nil
+}
+
+func (t *schemaRegistryTrait) provider() string {
+	if t.Provider == "" {
+		return apicurioProvider
+	}
+	return strings.ToLower(t.Provider)
+}
+
+func (t *schemaRegistryTrait) format() string {
+	if t.Format == "" {
+		return avroFormat
+	}
+	return strings.ToLower(t.Format)
+}
+
+func schemaRegistryEnvVar(key string) string {
+	return schemaRegistryEnvVarPrefix + strings.ToUpper(key)
+}
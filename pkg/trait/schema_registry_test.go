@@ -0,0 +1,132 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/envvar"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestConfigureSchemaRegistryTraitWithInvalidOptions(t *testing.T) {
+	schemaRegistryTrait, environment := createSchemaRegistryTest(t)
+
+	schemaRegistryTrait.Provider = "karapace"
+	configured, err := schemaRegistryTrait.Configure(environment)
+	assert.EqualError(t, err, "unsupported schema registry karapace, must be apicurio or confluent")
+	assert.False(t, configured)
+
+	schemaRegistryTrait.Provider = ""
+	schemaRegistryTrait.Format = "json"
+	configured, err = schemaRegistryTrait.Configure(environment)
+	assert.EqualError(t, err, "unsupported serialization format json, must be avro or protobuf")
+	assert.False(t, configured)
+
+	schemaRegistryTrait.Format = ""
+	schemaRegistryTrait.URL = "my-registry"
+	configured, err = schemaRegistryTrait.Configure(environment)
+	assert.EqualError(t, err, `invalid schema registry url "my-registry"`)
+	assert.False(t, configured)
+
+	schemaRegistryTrait.URL = "http://my-registry:8080/apis/registry/v2"
+	schemaRegistryTrait.Secret = "missing"
+	configured, err = schemaRegistryTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestSchemaRegistryDependencies(t *testing.T) {
+	schemaRegistryTrait, environment := createSchemaRegistryTest(t)
+	environment.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+	schemaRegistryTrait.Provider = confluentProvider
+
+	configured, err := schemaRegistryTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, schemaRegistryTrait.Apply(environment))
+	assert.Equal(t, []string{"mvn:io.quarkus:quarkus-confluent-registry-avro", "mvn:io.confluent:kafka-avro-serializer:" + confluentVersion},
+		environment.Integration.Status.Dependencies)
+	assert.Empty(t, environment.ApplicationProperties)
+}
+
+func TestSchemaRegistryApicurio(t *testing.T) {
+	schemaRegistryTrait, environment := createSchemaRegistryTest(t)
+	schemaRegistryTrait.AutoRegister = pointer.Bool(true)
+
+	configured, err := schemaRegistryTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, schemaRegistryTrait.Apply(environment))
+
+	assert.Equal(t, map[string]string{
+		"camel.component.kafka.value-serializer":                                       "io.apicurio.registry.serde.avro.AvroKafkaSerializer",
+		"camel.component.kafka.value-deserializer":                                     "io.apicurio.registry.serde.avro.AvroKafkaDeserializer",
+		"camel.component.kafka.additional-properties[apicurio.registry.url]":           "http://my-registry:8080/apis/registry/v2",
+		"camel.component.kafka.additional-properties[apicurio.registry.auto-register]": "true",
+		"camel.component.kafka.additional-properties[apicurio.auth.username]":          "${CAMEL_K_SCHEMA_REGISTRY_USERNAME}",
+		"camel.component.kafka.additional-properties[apicurio.auth.password]":          "${CAMEL_K_SCHEMA_REGISTRY_PASSWORD}",
+	}, environment.ApplicationProperties)
+	assert.Equal(t, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"}, Key: "password"},
+		*envvar.Get(environment.EnvVars, "CAMEL_K_SCHEMA_REGISTRY_PASSWORD").ValueFrom.SecretKeyRef)
+}
+
+func TestSchemaRegistryConfluentProtobuf(t *testing.T) {
+	schemaRegistryTrait, environment := createSchemaRegistryTest(t)
+	schemaRegistryTrait.Provider = confluentProvider
+	schemaRegistryTrait.Format = protobufFormat
+	schemaRegistryTrait.URL = "https://my-registry"
+	schemaRegistryTrait.Keys = pointer.Bool(true)
+
+	configured, err := schemaRegistryTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, schemaRegistryTrait.Apply(environment))
+
+	properties := environment.ApplicationProperties
+	assert.Equal(t, "io.confluent.kafka.serializers.protobuf.KafkaProtobufSerializer", properties["camel.component.kafka.key-serializer"])
+	assert.Equal(t, "io.confluent.kafka.serializers.protobuf.KafkaProtobufDeserializer", properties["camel.component.kafka.value-deserializer"])
+	assert.Equal(t, "https://my-registry", properties["camel.component.kafka.additional-properties[schema.registry.url]"])
+	assert.Equal(t, "USER_INFO", properties["camel.component.kafka.additional-properties[basic.auth.credentials.source]"])
+	assert.Equal(t, "${CAMEL_K_SCHEMA_REGISTRY_USERNAME}:${CAMEL_K_SCHEMA_REGISTRY_PASSWORD}",
+		properties["camel.component.kafka.additional-properties[basic.auth.user.info]"])
+	assert.NotContains(t, properties, "camel.component.kafka.additional-properties[auto.register.schemas]")
+}
+
+func createSchemaRegistryTest(t *testing.T) (*schemaRegistryTrait, *Environment) {
+	t.Helper()
+
+	_, environment := createStorageTest(1)
+
+	client, err := test.NewFakeClient(newDatabaseSecret("registry-credentials", nil, "username", "password"))
+	assert.Nil(t, err)
+	environment.Client = client
+
+	trait, _ := newSchemaRegistryTrait().(*schemaRegistryTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.URL = "http://my-registry:8080/apis/registry/v2"
+	trait.Secret = "registry-credentials"
+
+	return trait, environment
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package trait
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
@@ -32,6 +34,11 @@ import (
 //
 // It's enabled by default if the integration depends on a Camel component that can expose a HTTP endpoint.
 //
+// The Service can also be exposed outside the cluster, as a `NodePort` or a `LoadBalancer`, in which case the class of the
+// load balancer, its static IP address, or the client IP ranges allowed to access it, can be configured, e.g., for bare-metal
+// load balancers like MetalLB. The routing of the traffic to the integration Pods can be tuned with the session affinity,
+// the external and internal traffic policies, and the topology-aware hints.
+//
 // +camel-k:trait=service.
 type serviceTrait struct {
 	BaseTrait `property:",squash"`
@@ -39,9 +46,35 @@ type serviceTrait struct {
 	Auto *bool `property:"auto" json:"auto,omitempty"`
 	// Enable Service to be exposed as NodePort (default `false`).
 	NodePort *bool `property:"node-port" json:"nodePort,omitempty"`
+	// The type of the Service, either `ClusterIP`, `NodePort` or `LoadBalancer` (default `ClusterIP`).
+	Type string `property:"type" json:"type,omitempty"`
+	// The session affinity of the Service, either `None` or `ClientIP` (default `None`).
+	SessionAffinity string `property:"session-affinity" json:"sessionAffinity,omitempty"`
+	// The maximum time, in seconds, the sessions stick to the same Pod, when the session affinity is `ClientIP` (default `10800`).
+	SessionAffinityTimeout *int32 `property:"session-affinity-timeout" json:"sessionAffinityTimeout,omitempty"`
+	// How the traffic from outside the cluster is routed, either `Cluster` or `Local`.
+	// Only applies to the `NodePort` and `LoadBalancer` Services.
+	ExternalTrafficPolicy string `property:"external-traffic-policy" json:"externalTrafficPolicy,omitempty"`
+	// How the traffic from within the cluster is routed, either `Cluster` or `Local`.
+	InternalTrafficPolicy string `property:"internal-traffic-policy" json:"internalTrafficPolicy,omitempty"`
+	// To route the traffic preferably to the Pods in the same zone as the client, using topology-aware hints.
+	TopologyAwareHints *bool `property:"topology-aware-hints" json:"topologyAwareHints,omitempty"`
+	// The class of the load balancer implementation, for the `LoadBalancer` Services.
+	LoadBalancerClass string `property:"load-balancer-class" json:"loadBalancerClass,omitempty"`
+	// The static IP address requested to the load balancer, for the `LoadBalancer` Services.
+	LoadBalancerIP string `property:"load-balancer-ip" json:"loadBalancerIP,omitempty"`
+	// The client IP ranges allowed to access the load balancer, for the `LoadBalancer` Services.
+	LoadBalancerSourceRanges []string `property:"load-balancer-source-ranges" json:"loadBalancerSourceRanges,omitempty"`
 }
 
-const serviceTraitID = "service"
+const (
+	serviceTraitID = "service"
+
+	// topologyAwareHintsAnnotation enables the topology-aware routing of the Service traffic.
+	topologyAwareHintsAnnotation = "service.kubernetes.io/topology-aware-hints"
+	// maxSessionAffinityTimeout is the maximum session sticky time accepted by Kubernetes, i.e., one day.
+	maxSessionAffinityTimeout int32 = 86400
+)
 
 func newServiceTrait() Trait {
 	return &serviceTrait{
@@ -71,6 +104,10 @@ func (t *serviceTrait) Configure(e *Environment) (bool, error) {
 		return false, nil
 	}
 
+	if err := t.validate(); err != nil {
+		return false, err
+	}
+
 	if pointer.BoolDeref(t.Auto, true) {
 		sources, err := kubernetes.ResolveIntegrationSources(e.Ctx, t.Client, e.Integration, e.Resources)
 		if err != nil {
@@ -109,11 +146,110 @@ func (t *serviceTrait) Apply(e *Environment) error {
 		if pointer.BoolDeref(t.NodePort, false) {
 			svc.Spec.Type = corev1.ServiceTypeNodePort
 		}
+		t.configureService(svc)
 	}
 	e.Resources.Add(svc)
 	return nil
 }
 
+func (t *serviceTrait) validate() error {
+	serviceType := t.serviceType()
+	switch serviceType {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		return fmt.Errorf("cannot apply service trait: unsupported service type %q", t.Type)
+	}
+	if pointer.BoolDeref(t.NodePort, false) && serviceType != corev1.ServiceTypeNodePort {
+		return fmt.Errorf("cannot apply service trait: node-port cannot be enabled for a %s service", serviceType)
+	}
+
+	switch corev1.ServiceAffinity(t.SessionAffinity) {
+	case "", corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP:
+	default:
+		return fmt.Errorf("cannot apply service trait: unsupported session affinity %q", t.SessionAffinity)
+	}
+	if t.SessionAffinityTimeout != nil {
+		if corev1.ServiceAffinity(t.SessionAffinity) != corev1.ServiceAffinityClientIP {
+			return fmt.Errorf("cannot apply service trait: the session affinity timeout requires the %s session affinity", corev1.ServiceAffinityClientIP)
+		}
+		if timeout := *t.SessionAffinityTimeout; timeout <= 0 || timeout > maxSessionAffinityTimeout {
+			return fmt.Errorf("cannot apply service trait: the session affinity timeout must be between 1 and %d seconds", maxSessionAffinityTimeout)
+		}
+	}
+
+	switch corev1.ServiceExternalTrafficPolicyType(t.ExternalTrafficPolicy) {
+	case "":
+	case corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal:
+		if serviceType == corev1.ServiceTypeClusterIP {
+			return fmt.Errorf("cannot apply service trait: the external traffic policy only applies to the %s and %s services",
+				corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer)
+		}
+	default:
+		return fmt.Errorf("cannot apply service trait: unsupported external traffic policy %q", t.ExternalTrafficPolicy)
+	}
+
+	switch corev1.ServiceInternalTrafficPolicyType(t.InternalTrafficPolicy) {
+	case "", corev1.ServiceInternalTrafficPolicyCluster, corev1.ServiceInternalTrafficPolicyLocal:
+	default:
+		return fmt.Errorf("cannot apply service trait: unsupported internal traffic policy %q", t.InternalTrafficPolicy)
+	}
+
+	if serviceType != corev1.ServiceTypeLoadBalancer &&
+		(t.LoadBalancerClass != "" || t.LoadBalancerIP != "" || len(t.LoadBalancerSourceRanges) > 0) {
+		return fmt.Errorf("cannot apply service trait: the load balancer options only apply to the %s services", corev1.ServiceTypeLoadBalancer)
+	}
+
+	return nil
+}
+
+func (t *serviceTrait) serviceType() corev1.ServiceType {
+	if t.Type != "" {
+		return corev1.ServiceType(t.Type)
+	}
+	if pointer.BoolDeref(t.NodePort, false) {
+		return corev1.ServiceTypeNodePort
+	}
+	return corev1.ServiceTypeClusterIP
+}
+
+func (t *serviceTrait) configureService(svc *corev1.Service) {
+	if t.Type != "" {
+		svc.Spec.Type = corev1.ServiceType(t.Type)
+	}
+
+	if t.SessionAffinity != "" {
+		svc.Spec.SessionAffinity = corev1.ServiceAffinity(t.SessionAffinity)
+	}
+	if t.SessionAffinityTimeout != nil {
+		svc.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{
+				TimeoutSeconds: t.SessionAffinityTimeout,
+			},
+		}
+	}
+
+	if t.ExternalTrafficPolicy != "" {
+		svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyType(t.ExternalTrafficPolicy)
+	}
+	if t.InternalTrafficPolicy != "" {
+		policy := corev1.ServiceInternalTrafficPolicyType(t.InternalTrafficPolicy)
+		svc.Spec.InternalTrafficPolicy = &policy
+	}
+
+	if pointer.BoolDeref(t.TopologyAwareHints, false) {
+		if svc.Annotations == nil {
+			svc.Annotations = make(map[string]string)
+		}
+		svc.Annotations[topologyAwareHintsAnnotation] = "auto"
+	}
+
+	if t.LoadBalancerClass != "" {
+		svc.Spec.LoadBalancerClass = pointer.String(t.LoadBalancerClass)
+	}
+	svc.Spec.LoadBalancerIP = t.LoadBalancerIP
+	svc.Spec.LoadBalancerSourceRanges = t.LoadBalancerSourceRanges
+}
+
 func getServiceFor(e *Environment) *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -25,6 +25,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/camel"
@@ -381,3 +382,224 @@ func TestServiceWithNodePort(t *testing.T) {
 
 	assert.Equal(t, corev1.ServiceTypeNodePort, s.Spec.Type)
 }
+
+func TestServiceWithLoadBalancer(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	traitCatalog := NewCatalog(nil)
+
+	compressedRoute, err := gzip.CompressBase64([]byte(`from("netty-http:test").log("hello")`))
+	assert.NoError(t, err)
+
+	environment := Environment{
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ServiceTestName,
+				Namespace: "ns",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name:        "routes.js",
+							Content:     string(compressedRoute),
+							Compression: true,
+						},
+						Language: v1.LanguageJavaScript,
+					},
+				},
+				Traits: map[string]v1.TraitSpec{
+					"service": test.TraitSpecFromMap(t, map[string]interface{}{
+						"enabled":                  true,
+						"auto":                     false,
+						"type":                     "LoadBalancer",
+						"sessionAffinity":          "ClientIP",
+						"sessionAffinityTimeout":   600,
+						"externalTrafficPolicy":    "Local",
+						"internalTrafficPolicy":    "Local",
+						"topologyAwareHints":       true,
+						"loadBalancerClass":        "metallb.universe.tf/metallb",
+						"loadBalancerIP":           "192.168.1.240",
+						"loadBalancerSourceRanges": []string{"10.0.0.0/8"},
+					}),
+				},
+			},
+		},
+		IntegrationKit: &v1.IntegrationKit{
+			Status: v1.IntegrationKitStatus{
+				Phase: v1.IntegrationKitPhaseReady,
+			},
+		},
+		Platform: &v1.IntegrationPlatform{
+			Spec: v1.IntegrationPlatformSpec{
+				Cluster: v1.IntegrationPlatformClusterOpenShift,
+				Build: v1.IntegrationPlatformBuildSpec{
+					PublishStrategy: v1.IntegrationPlatformBuildPublishStrategyS2I,
+					Registry:        v1.RegistrySpec{Address: "registry"},
+				},
+			},
+		},
+		EnvVars:        make([]corev1.EnvVar, 0),
+		ExecutedTraits: make([]Trait, 0),
+		Resources:      kubernetes.NewCollection(),
+	}
+	environment.Platform.ResyncStatusFullConfig()
+
+	err = traitCatalog.apply(&environment)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, environment.ExecutedTraits)
+	assert.NotNil(t, environment.GetTrait("deployment"))
+	assert.NotNil(t, environment.GetTrait("service"))
+	assert.NotNil(t, environment.GetTrait("container"))
+
+	s := environment.Resources.GetService(func(service *corev1.Service) bool {
+		return service.Name == ServiceTestName
+	})
+	d := environment.Resources.GetDeployment(func(deployment *appsv1.Deployment) bool {
+		return deployment.Name == ServiceTestName
+	})
+
+	assert.NotNil(t, d)
+	assert.NotNil(t, s)
+
+	assert.Len(t, s.Spec.Ports, 1)
+	assert.Equal(t, int32(80), s.Spec.Ports[0].Port)
+
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, s.Spec.Type)
+	assert.Equal(t, corev1.ServiceAffinityClientIP, s.Spec.SessionAffinity)
+	assert.Equal(t, pointer.Int32(600), s.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
+	assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeLocal, s.Spec.ExternalTrafficPolicy)
+	assert.Equal(t, corev1.ServiceInternalTrafficPolicyLocal, *s.Spec.InternalTrafficPolicy)
+	assert.Equal(t, "auto", s.Annotations["service.kubernetes.io/topology-aware-hints"])
+	assert.Equal(t, pointer.String("metallb.universe.tf/metallb"), s.Spec.LoadBalancerClass)
+	assert.Equal(t, "192.168.1.240", s.Spec.LoadBalancerIP)
+	assert.Equal(t, []string{"10.0.0.0/8"}, s.Spec.LoadBalancerSourceRanges)
+}
+
+func TestServiceWithInvalidConfiguration(t *testing.T) {
+	tests := []struct {
+		name  string
+		trait serviceTrait
+	}{
+		{name: "type", trait: serviceTrait{Type: "ExternalName"}},
+		{name: "node-port", trait: serviceTrait{Type: "LoadBalancer", NodePort: pointer.Bool(true)}},
+		{name: "session-affinity", trait: serviceTrait{SessionAffinity: "Cookie"}},
+		{name: "session-affinity-timeout", trait: serviceTrait{SessionAffinityTimeout: pointer.Int32(60)}},
+		{name: "session-affinity-timeout-max", trait: serviceTrait{SessionAffinity: "ClientIP", SessionAffinityTimeout: pointer.Int32(86401)}},
+		{name: "external-traffic-policy", trait: serviceTrait{Type: "NodePort", ExternalTrafficPolicy: "Zone"}},
+		{name: "external-traffic-policy-cluster-ip", trait: serviceTrait{ExternalTrafficPolicy: "Local"}},
+		{name: "internal-traffic-policy", trait: serviceTrait{InternalTrafficPolicy: "Zone"}},
+		{name: "load-balancer-ip", trait: serviceTrait{Type: "NodePort", LoadBalancerIP: "192.168.1.240"}},
+		{name: "load-balancer-class", trait: serviceTrait{LoadBalancerClass: "metallb.universe.tf/metallb"}},
+	}
+
+	for _, tc := range tests {
+		trait := tc.trait
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Error(t, trait.validate())
+		})
+	}
+
+	valid := serviceTrait{NodePort: pointer.Bool(true), ExternalTrafficPolicy: "Local", InternalTrafficPolicy: "Cluster"}
+	assert.NoError(t, valid.validate())
+}
+
+func TestServiceWithAdditionalPorts(t *testing.T) {
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	traitCatalog := NewCatalog(nil)
+
+	compressedRoute, err := gzip.CompressBase64([]byte(`from("netty-http:test").log("hello")`))
+	assert.NoError(t, err)
+
+	environment := Environment{
+		CamelCatalog: catalog,
+		Catalog:      traitCatalog,
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ServiceTestName,
+				Namespace: "ns",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Sources: []v1.SourceSpec{
+					{
+						DataSpec: v1.DataSpec{
+							Name:        "routes.js",
+							Content:     string(compressedRoute),
+							Compression: true,
+						},
+						Language: v1.LanguageJavaScript,
+					},
+				},
+				Traits: map[string]v1.TraitSpec{
+					"service": test.TraitSpecFromMap(t, map[string]interface{}{
+						"enabled": true,
+						"auto":    false,
+					}),
+					"container": test.TraitSpecFromMap(t, map[string]interface{}{
+						"ports": []string{"http:8081:8080", "grpc:9090", "metrics:9779:9000"},
+					}),
+				},
+			},
+		},
+		IntegrationKit: &v1.IntegrationKit{
+			Status: v1.IntegrationKitStatus{
+				Phase: v1.IntegrationKitPhaseReady,
+			},
+		},
+		Platform: &v1.IntegrationPlatform{
+			Spec: v1.IntegrationPlatformSpec{
+				Cluster: v1.IntegrationPlatformClusterOpenShift,
+				Build: v1.IntegrationPlatformBuildSpec{
+					PublishStrategy: v1.IntegrationPlatformBuildPublishStrategyS2I,
+					Registry:        v1.RegistrySpec{Address: "registry"},
+				},
+			},
+		},
+		EnvVars:        make([]corev1.EnvVar, 0),
+		ExecutedTraits: make([]Trait, 0),
+		Resources:      kubernetes.NewCollection(),
+	}
+	environment.Platform.ResyncStatusFullConfig()
+
+	err = traitCatalog.apply(&environment)
+	assert.Nil(t, err)
+
+	s := environment.Resources.GetService(func(service *corev1.Service) bool {
+		return service.Name == ServiceTestName
+	})
+	d := environment.Resources.GetDeployment(func(deployment *appsv1.Deployment) bool {
+		return deployment.Name == ServiceTestName
+	})
+
+	assert.NotNil(t, d)
+	assert.NotNil(t, s)
+
+	assert.Len(t, s.Spec.Ports, 3)
+	assert.Equal(t, "http", s.Spec.Ports[0].Name)
+	assert.Equal(t, int32(8080), s.Spec.Ports[0].Port)
+	assert.Equal(t, "grpc", s.Spec.Ports[1].Name)
+	assert.Equal(t, int32(9090), s.Spec.Ports[1].Port)
+	assert.Equal(t, "grpc", s.Spec.Ports[1].TargetPort.String())
+	assert.Equal(t, "metrics", s.Spec.Ports[2].Name)
+	assert.Equal(t, int32(9000), s.Spec.Ports[2].Port)
+
+	ports := d.Spec.Template.Spec.Containers[0].Ports
+	assert.Len(t, ports, 3)
+	assert.Equal(t, int32(8081), ports[0].ContainerPort)
+	assert.Equal(t, corev1.ContainerPort{Name: "grpc", ContainerPort: 9090, Protocol: corev1.ProtocolTCP}, ports[1])
+	assert.Equal(t, corev1.ContainerPort{Name: "metrics", ContainerPort: 9779, Protocol: corev1.ProtocolTCP}, ports[2])
+}
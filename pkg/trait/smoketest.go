@@ -0,0 +1,137 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const (
+	smokeTestTraitID = "smoke-test"
+
+	defaultSmokeTestTimeout = 5
+)
+
+// The Smoke Test trait verifies the Integration once deployed, by calling a sequence of HTTP endpoints
+// exposed by the Integration Pods, e.g., the consumer endpoint of a Camel route, before the Integration
+// is reported to be running.
+//
+// When any of the probes fails, the Integration is moved to the error phase and, with the `rollback` option enabled,
+// its Deployment is rolled back to the previous revision, until the Integration is updated.
+// The rollback is not supported for Integrations deployed as Knative services or CronJobs.
+//
+// +camel-k:trait=smoke-test.
+type smokeTestTrait struct {
+	BaseTrait `property:",squash"`
+	// The paths of the HTTP GET requests, sent in sequence to each Integration Pod, that must all succeed, e.g. `/hello`.
+	Probes []string `property:"probes" json:"probes,omitempty"`
+	// The port the probes are sent to. It defaults to the Integration container port.
+	Port int `property:"port" json:"port,omitempty"`
+	// Number of seconds after which each probe times out (default `5`).
+	Timeout int32 `property:"timeout" json:"timeout,omitempty"`
+	// Rolls the Integration Deployment back to its previous revision when the smoke test fails.
+	Rollback *bool `property:"rollback" json:"rollback,omitempty"`
+}
+
+func newSmokeTestTrait() Trait {
+	return &smokeTestTrait{
+		BaseTrait: NewBaseTrait(smokeTestTraitID, 2600),
+	}
+}
+
+func (t *smokeTestTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if len(t.Probes) == 0 {
+		return false, fmt.Errorf("smoke test requires at least one probe")
+	}
+	for _, p := range t.Probes {
+		if !strings.HasPrefix(p, "/") {
+			return false, fmt.Errorf("invalid smoke test probe %q, it must be an absolute path", p)
+		}
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *smokeTestTrait) Apply(e *Environment) error {
+	condition := e.Integration.Status.GetCondition(v1.IntegrationConditionSmokeTestPassed)
+	if condition == nil || condition.Reason != v1.IntegrationConditionSmokeTestRolledBackReason {
+		return nil
+	}
+
+	// Keep the Deployment rolled back until the Integration is updated
+	deployment := e.Resources.GetDeploymentForIntegration(e.Integration)
+	if deployment == nil {
+		return nil
+	}
+	live := appsv1.Deployment{}
+	if err := t.Client.Get(e.Ctx, ctrl.ObjectKeyFromObject(deployment), &live); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	deployment.Spec.Template = live.Spec.Template
+
+	return nil
+}
+
+func (t *smokeTestTrait) probes(e *Environment) []corev1.Probe {
+	port := t.Port
+	if port == 0 {
+		port = defaultContainerPort
+		if ct := e.Catalog.GetTrait(containerTraitID); ct != nil {
+			port = ct.(*containerTrait).Port
+		}
+	}
+
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = defaultSmokeTestTimeout
+	}
+
+	probes := make([]corev1.Probe, 0, len(t.Probes))
+	for _, path := range t.Probes {
+		probes = append(probes, corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path:   path,
+					Port:   intstr.FromInt(port),
+					Scheme: corev1.URISchemeHTTP,
+				},
+			},
+			TimeoutSeconds: timeout,
+		})
+	}
+
+	return probes
+}
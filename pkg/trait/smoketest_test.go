@@ -0,0 +1,161 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestConfigureSmokeTestTraitDoesSucceed(t *testing.T) {
+	smokeTestTrait, environment := createSmokeTestTest(t)
+
+	configured, err := smokeTestTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+}
+
+func TestConfigureSmokeTestTraitIsDisabledByDefault(t *testing.T) {
+	smokeTestTrait, environment := createSmokeTestTest(t)
+	smokeTestTrait.Enabled = nil
+
+	configured, err := smokeTestTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureSmokeTestTraitWithInvalidProbes(t *testing.T) {
+	smokeTestTrait, environment := createSmokeTestTest(t)
+
+	smokeTestTrait.Probes = nil
+	configured, err := smokeTestTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	smokeTestTrait.Probes = []string{"hello"}
+	configured, err = smokeTestTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestSmokeTestProbes(t *testing.T) {
+	smokeTestTrait, environment := createSmokeTestTest(t)
+	environment.ExecutedTraits = append(environment.ExecutedTraits, smokeTestTrait)
+
+	probes := environment.GetSmokeTestProbes()
+	assert.Len(t, probes, 2)
+	assert.Equal(t, "/hello", probes[0].HTTPGet.Path)
+	assert.Equal(t, "/world", probes[1].HTTPGet.Path)
+	assert.Equal(t, defaultContainerPort, probes[0].HTTPGet.Port.IntValue())
+	assert.Equal(t, corev1.URISchemeHTTP, probes[0].HTTPGet.Scheme)
+	assert.Equal(t, int32(defaultSmokeTestTimeout), probes[0].TimeoutSeconds)
+	assert.False(t, environment.IsSmokeTestRollbackEnabled())
+
+	smokeTestTrait.Port = 8081
+	smokeTestTrait.Timeout = 10
+	smokeTestTrait.Rollback = pointer.Bool(true)
+
+	probes = environment.GetSmokeTestProbes()
+	assert.Equal(t, 8081, probes[0].HTTPGet.Port.IntValue())
+	assert.Equal(t, int32(10), probes[0].TimeoutSeconds)
+	assert.True(t, environment.IsSmokeTestRollbackEnabled())
+}
+
+func TestSmokeTestKeepsRolledBackDeployment(t *testing.T) {
+	smokeTestTrait, environment := createSmokeTestTest(t)
+
+	live := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "integration", Image: "previous"}},
+				},
+			},
+		},
+	}
+	c, err := test.NewFakeClient(live)
+	assert.Nil(t, err)
+	smokeTestTrait.InjectClient(c)
+
+	// The generated Deployment is left untouched unless the Integration has been rolled back
+	assert.Nil(t, smokeTestTrait.Apply(environment))
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.Equal(t, "current", deployment.Spec.Template.Spec.Containers[0].Image)
+
+	environment.Integration.Status.SetCondition(v1.IntegrationConditionSmokeTestPassed, corev1.ConditionFalse,
+		v1.IntegrationConditionSmokeTestRolledBackReason, "rolled back to revision 1")
+	assert.Nil(t, smokeTestTrait.Apply(environment))
+	assert.Equal(t, "previous", deployment.Spec.Template.Spec.Containers[0].Image)
+}
+
+func createSmokeTestTest(t *testing.T) (*smokeTestTrait, *Environment) {
+	t.Helper()
+
+	trait, _ := newSmokeTestTrait().(*smokeTestTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.Probes = []string{"/hello", "/world"}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+			Labels: map[string]string{
+				v1.IntegrationLabel: "integration-name",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "integration", Image: "current"}},
+				},
+			},
+		},
+	}
+
+	environment := &Environment{
+		Ctx:     context.TODO(),
+		Catalog: NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "integration-name",
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(deployment),
+	}
+
+	return trait, environment
+}
@@ -0,0 +1,193 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"path"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const (
+	storageTraitID    = "storage"
+	storageVolumeName = "storage"
+
+	defaultStoragePath  = "/var/lib/camel"
+	defaultStorageSize  = "1Gi"
+	storagePathProperty = "camel.k.storage.path"
+)
+
+// The Storage trait provisions a directory whose content outlives the Integration Pods, e.g., to store the state
+// of file idempotent repositories, or the local state stores of Kafka Streams.
+//
+// The directory is backed by a Persistent Volume Claim, or by a generic ephemeral volume, that only lives as long
+// as the Pod, with the `ephemeral` option enabled. Its path is exposed by the `camel.k.storage.path` property,
+// that can be used in the routes, e.g., `file:{{camel.k.storage.path}}/inbox`.
+//
+// When the Integration has more than one replica, and the storage is persistent, the Integration is deployed as
+// a StatefulSet, so that each replica gets its own Persistent Volume Claim. The first replica keeps the claim
+// used when the Integration runs a single replica, so that its state is preserved when the Integration is scaled.
+// The claims of the other replicas are not deleted when the Integration is scaled down, or deleted.
+//
+// +camel-k:trait=storage.
+type storageTrait struct {
+	BaseTrait `property:",squash"`
+	// The path of the storage directory in the Integration container (default `/var/lib/camel`).
+	Path string `property:"path" json:"path,omitempty"`
+	// The size of the volume (default `1Gi`).
+	Size string `property:"size" json:"size,omitempty"`
+	// The storage class of the volume. The cluster default storage class is used if not set.
+	StorageClass string `property:"storage-class" json:"storageClass,omitempty"`
+	// Uses a generic ephemeral volume, whose content is deleted with the Pod, instead of a Persistent Volume Claim.
+	Ephemeral *bool `property:"ephemeral" json:"ephemeral,omitempty"`
+}
+
+func newStorageTrait() Trait {
+	return &storageTrait{
+		// Must run before the container trait, that computes the application properties
+		BaseTrait: NewBaseTrait(storageTraitID, 1150),
+	}
+}
+
+func (t *storageTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if t.Path != "" && !path.IsAbs(t.Path) {
+		return false, fmt.Errorf("invalid storage path %s, it must be an absolute path", t.Path)
+	}
+	if t.Size != "" {
+		if _, err := resource.ParseQuantity(t.Size); err != nil {
+			return false, fmt.Errorf("invalid storage size %s: %w", t.Size, err)
+		}
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *storageTrait) Apply(e *Environment) error {
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+	e.ApplicationProperties[storagePathProperty] = t.path()
+
+	if !pointer.BoolDeref(t.Ephemeral, false) {
+		// The claim of the first replica is always provisioned, whatever the number of replicas,
+		// so that it is not garbage collected when the Integration is scaled.
+		e.Resources.Add(t.getPersistentVolumeClaimFor(e))
+	}
+
+	// The volume is configured once the Integration container and its controller are generated
+	e.PostProcessors = append(e.PostProcessors, t.configureVolume)
+
+	return nil
+}
+
+func (t *storageTrait) configureVolume(e *Environment) error {
+	container := e.GetIntegrationContainer()
+	if container == nil {
+		return fmt.Errorf("unable to find integration container: %s", e.Integration.Name)
+	}
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      storageVolumeName,
+		MountPath: t.path(),
+	})
+
+	if pointer.BoolDeref(t.Ephemeral, false) {
+		e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+			spec.Volumes = append(spec.Volumes, corev1.Volume{
+				Name: storageVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Ephemeral: &corev1.EphemeralVolumeSource{
+						VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+							Spec: t.getPersistentVolumeClaimSpec(),
+						},
+					},
+				},
+			})
+		})
+		return nil
+	}
+
+	if deployment := e.Resources.GetDeploymentForIntegration(e.Integration); deployment != nil {
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > 1 {
+			statefulSet := replaceDeploymentWithStatefulSet(e)
+			statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates,
+				newVolumeClaimTemplate(e, storageVolumeName, t.getPersistentVolumeClaimSpec()))
+			return nil
+		}
+		// The claim cannot be attached to the Pods of different revisions running on different nodes
+		deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		}
+	}
+
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: storageVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: storageClaimName(e.Integration, 0),
+				},
+			},
+		})
+	})
+
+	return nil
+}
+
+func (t *storageTrait) getPersistentVolumeClaimFor(e *Environment) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PersistentVolumeClaim",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      storageClaimName(e.Integration, 0),
+			Namespace: e.Integration.Namespace,
+			Labels: map[string]string{
+				v1.IntegrationLabel: e.Integration.Name,
+			},
+		},
+		Spec: t.getPersistentVolumeClaimSpec(),
+	}
+}
+
+func (t *storageTrait) getPersistentVolumeClaimSpec() corev1.PersistentVolumeClaimSpec {
+	return newPersistentVolumeClaimSpec(t.Size, defaultStorageSize, t.StorageClass)
+}
+
+func (t *storageTrait) path() string {
+	if t.Path != "" {
+		return t.Path
+	}
+	return defaultStoragePath
+}
+
+// storageClaimName returns the name of the claim the StatefulSet controller creates for the given replica.
+func storageClaimName(integration *v1.Integration, ordinal int) string {
+	return fmt.Sprintf("%s-%s-%d", storageVolumeName, integration.Name, ordinal)
+}
@@ -0,0 +1,178 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+func TestConfigureStorageTraitIsDisabledByDefault(t *testing.T) {
+	storageTrait, environment := createStorageTest(1)
+	storageTrait.Enabled = nil
+
+	configured, err := storageTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.False(t, configured)
+}
+
+func TestConfigureStorageTraitWithInvalidOptions(t *testing.T) {
+	storageTrait, environment := createStorageTest(1)
+
+	storageTrait.Path = "data"
+	configured, err := storageTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	storageTrait.Path = ""
+	storageTrait.Size = "large"
+	configured, err = storageTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestStoragePersistentVolumeClaim(t *testing.T) {
+	storageTrait, environment := createStorageTest(1)
+	storageTrait.Size = "5Gi"
+	storageTrait.StorageClass = "fast"
+
+	configured, err := storageTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, storageTrait.Apply(environment))
+	assert.Equal(t, defaultStoragePath, environment.ApplicationProperties[storagePathProperty])
+	assert.Nil(t, runPostProcessors(environment))
+
+	var claim *corev1.PersistentVolumeClaim
+	environment.Resources.Visit(func(o runtime.Object) {
+		if pvc, ok := o.(*corev1.PersistentVolumeClaim); ok {
+			claim = pvc
+		}
+	})
+	assert.NotNil(t, claim)
+	assert.Equal(t, "storage-integration-name-0", claim.Name)
+	assert.Equal(t, "fast", *claim.Spec.StorageClassName)
+	assert.Equal(t, resource.MustParse("5Gi"), claim.Spec.Resources.Requests[corev1.ResourceStorage])
+
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, deployment)
+	assert.Equal(t, appsv1.RecreateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, "storage-integration-name-0", deployment.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, defaultStoragePath, deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath)
+}
+
+func TestStorageStatefulSetWithReplicas(t *testing.T) {
+	storageTrait, environment := createStorageTest(3)
+	storageTrait.Path = "/data"
+
+	assert.Nil(t, storageTrait.Apply(environment))
+	assert.Equal(t, "/data", environment.ApplicationProperties[storagePathProperty])
+	assert.Nil(t, runPostProcessors(environment))
+
+	assert.Nil(t, environment.Resources.GetDeploymentForIntegration(environment.Integration))
+	statefulSet := environment.Resources.GetStatefulSet(func(*appsv1.StatefulSet) bool { return true })
+	assert.NotNil(t, statefulSet)
+	assert.Equal(t, "integration-name", statefulSet.Name)
+	assert.Equal(t, int32(3), *statefulSet.Spec.Replicas)
+	assert.Equal(t, appsv1.ParallelPodManagement, statefulSet.Spec.PodManagementPolicy)
+	assert.Len(t, statefulSet.Spec.VolumeClaimTemplates, 1)
+	assert.Equal(t, storageVolumeName, statefulSet.Spec.VolumeClaimTemplates[0].Name)
+	assert.Empty(t, statefulSet.Spec.Template.Spec.Volumes)
+	assert.Equal(t, corev1.VolumeMount{Name: storageVolumeName, MountPath: "/data"}, statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts[0])
+}
+
+func TestStorageEphemeralVolume(t *testing.T) {
+	storageTrait, environment := createStorageTest(3)
+	storageTrait.Ephemeral = pointer.Bool(true)
+
+	assert.Nil(t, storageTrait.Apply(environment))
+	assert.Nil(t, runPostProcessors(environment))
+
+	environment.Resources.Visit(func(o runtime.Object) {
+		_, ok := o.(*corev1.PersistentVolumeClaim)
+		assert.False(t, ok)
+	})
+	deployment := environment.Resources.GetDeploymentForIntegration(environment.Integration)
+	assert.NotNil(t, deployment)
+	assert.NotNil(t, deployment.Spec.Template.Spec.Volumes[0].Ephemeral)
+	assert.Equal(t, storageVolumeName, deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name)
+}
+
+func runPostProcessors(e *Environment) error {
+	for _, processor := range e.PostProcessors {
+		if err := processor(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createStorageTest(replicas int32) (*storageTrait, *Environment) {
+	trait, _ := newStorageTrait().(*storageTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "integration-name",
+			Labels: map[string]string{
+				v1.IntegrationLabel: "integration-name",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: defaultContainerName}},
+				},
+			},
+		},
+	}
+
+	environment := &Environment{
+		Ctx:     context.TODO(),
+		Catalog: NewCatalog(nil),
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "integration-name",
+			},
+			Spec: v1.IntegrationSpec{
+				Replicas: pointer.Int32(replicas),
+			},
+			Status: v1.IntegrationStatus{
+				Phase: v1.IntegrationPhaseDeploying,
+			},
+		},
+		Resources: kubernetes.NewCollection(deployment),
+	}
+
+	return trait, environment
+}
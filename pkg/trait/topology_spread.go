@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/kubernetes"
+)
+
+// This trait sets topology spread constraints over Integration pods, so that the replicas are spread across
+// failure domains, such as zones or nodes, for high availability.
+// See https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/ for more details.
+//
+// A constraint should be expressed as `TopologyKey[:MaxSkew][:WhenUnsatisfiable]`, where values in square brackets are optional.
+// The max skew defaults to `1`, and the pods are scheduled anyway when the constraint cannot be satisfied, unless
+// `DoNotSchedule` is set. The constraints only count the pods of the Integration.
+//
+// For examples:
+//
+// - `topology.kubernetes.io/zone`
+// - `topology.kubernetes.io/zone:1:DoNotSchedule`
+// - `kubernetes.io/hostname:2`
+//
+// NOTE: With Knative, the `kubernetes.podspec-topologyspreadconstraints` feature flag must be enabled in the
+// `config-features` ConfigMap of Knative Serving.
+//
+// It's disabled by default.
+//
+// +camel-k:trait=topology-spread.
+type topologySpreadTrait struct {
+	BaseTrait `property:",squash"`
+	// The list of topology spread constraints, in the form `TopologyKey[:MaxSkew][:WhenUnsatisfiable]`
+	Constraints []string `property:"constraints" json:"constraints,omitempty"`
+}
+
+func newTopologySpreadTrait() Trait {
+	return &topologySpreadTrait{
+		BaseTrait: NewBaseTrait("topology-spread", 1500),
+	}
+}
+
+func (t *topologySpreadTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if len(t.Constraints) == 0 {
+		return false, fmt.Errorf("no topology spread constraint was provided")
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *topologySpreadTrait) Apply(e *Environment) error {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			v1.IntegrationLabel: e.Integration.Name,
+		},
+	}
+	constraints, err := kubernetes.NewTopologySpreadConstraints(t.Constraints, selector)
+	if err != nil {
+		return err
+	}
+	podSpec := e.GetIntegrationPodSpec()
+
+	if podSpec == nil {
+		return fmt.Errorf("could not find any integration deployment for %v", e.Integration.Name)
+	}
+	if podSpec.TopologySpreadConstraints == nil {
+		podSpec.TopologySpreadConstraints = make([]corev1.TopologySpreadConstraint, 0)
+	}
+	podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, constraints...)
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestConfigureTopologySpreadTraitMissingConstraint(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	topologySpreadTrait := createNominalTopologySpreadTrait()
+
+	success, err := topologySpreadTrait.Configure(environment)
+
+	assert.Equal(t, false, success)
+	assert.NotNil(t, err)
+}
+
+func TestApplyTopologySpreadTraitMalformedConstraint(t *testing.T) {
+	environment, _ := createNominalDeploymentTraitTest()
+	topologySpreadTrait := createNominalTopologySpreadTrait()
+	topologySpreadTrait.Constraints = append(topologySpreadTrait.Constraints, "topology.kubernetes.io/zone:Always")
+
+	err := topologySpreadTrait.Apply(environment)
+
+	assert.NotNil(t, err)
+}
+
+func TestApplyTopologySpreadMissingDeployment(t *testing.T) {
+	topologySpreadTrait := createNominalTopologySpreadTrait()
+	topologySpreadTrait.Constraints = append(topologySpreadTrait.Constraints, "topology.kubernetes.io/zone")
+
+	environment := createNominalMissingDeploymentTraitTest()
+	err := topologySpreadTrait.Apply(environment)
+
+	assert.NotNil(t, err)
+}
+
+func TestApplyTopologySpreadConstraints(t *testing.T) {
+	topologySpreadTrait := createNominalTopologySpreadTrait()
+	topologySpreadTrait.Constraints = append(topologySpreadTrait.Constraints, "topology.kubernetes.io/zone:2:DoNotSchedule")
+
+	environment, deployment := createNominalDeploymentTraitTest()
+	testApplyTopologySpreadConstraints(t, topologySpreadTrait, environment, &deployment.Spec.Template.Spec.TopologySpreadConstraints)
+
+	environment, knativeService := createNominalKnativeServiceTraitTest()
+	testApplyTopologySpreadConstraints(t, topologySpreadTrait, environment, &knativeService.Spec.Template.Spec.TopologySpreadConstraints)
+
+	environment, cronJob := createNominalCronJobTraitTest()
+	testApplyTopologySpreadConstraints(t, topologySpreadTrait, environment, &cronJob.Spec.JobTemplate.Spec.Template.Spec.TopologySpreadConstraints)
+}
+
+func testApplyTopologySpreadConstraints(t *testing.T, trait *topologySpreadTrait, environment *Environment, constraints *[]corev1.TopologySpreadConstraint) {
+	t.Helper()
+
+	err := trait.Apply(environment)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(*constraints))
+	constraint := (*constraints)[0]
+	assert.Equal(t, "topology.kubernetes.io/zone", constraint.TopologyKey)
+	assert.Equal(t, int32(2), constraint.MaxSkew)
+	assert.Equal(t, corev1.DoNotSchedule, constraint.WhenUnsatisfiable)
+	assert.Equal(t, environment.Integration.Name, constraint.LabelSelector.MatchLabels[v1.IntegrationLabel])
+}
+
+func createNominalTopologySpreadTrait() *topologySpreadTrait {
+	topologySpreadTrait, _ := newTopologySpreadTrait().(*topologySpreadTrait)
+	topologySpreadTrait.Enabled = pointer.Bool(true)
+	topologySpreadTrait.Constraints = make([]string, 0)
+
+	return topologySpreadTrait
+}
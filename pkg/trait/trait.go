@@ -96,6 +96,12 @@ func newEnvironment(ctx context.Context, c client.Client, integration *v1.Integr
 		return nil, err
 	}
 
+	tenant, err := platform.GetTenant(ctx, c, obj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	pl = tenant.ApplyTo(pl)
+
 	if kit == nil {
 		kit, err = getIntegrationKit(ctx, c, integration)
 		if err != nil {
@@ -110,6 +116,7 @@ func newEnvironment(ctx context.Context, c client.Client, integration *v1.Integr
 	env := Environment{
 		Ctx:                   ctx,
 		Platform:              pl,
+		Tenant:                tenant,
 		Client:                c,
 		IntegrationKit:        kit,
 		Integration:           integration,
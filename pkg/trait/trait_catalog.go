@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/structs"
 	"github.com/pkg/errors"
@@ -42,12 +43,10 @@ func NewCatalog(c client.Client) *Catalog {
 	for _, factory := range FactoryList {
 		traitList = append(traitList, factory())
 	}
-	sort.Slice(traitList, func(i, j int) bool {
-		if traitList[i].Order() != traitList[j].Order() {
-			return traitList[i].Order() < traitList[j].Order()
-		}
-		return string(traitList[i].ID()) < string(traitList[j].ID())
-	})
+	traitList, err := sortTraits(traitList)
+	if err != nil {
+		panic(err)
+	}
 
 	catalog := Catalog{
 		L:      log.Log.WithName("trait"),
@@ -62,6 +61,77 @@ func NewCatalog(c client.Client) *Catalog {
 	return &catalog
 }
 
+// sortTraits orders the traits so that each trait is executed after the traits it requires,
+// and before the traits it influences. Independent traits keep the order given by their
+// Order value, then by their ID. Dependencies on traits that are not part of the list are ignored.
+func sortTraits(traits []Trait) ([]Trait, error) {
+	sorted := append([]Trait(nil), traits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Order() != sorted[j].Order() {
+			return sorted[i].Order() < sorted[j].Order()
+		}
+		return string(sorted[i].ID()) < string(sorted[j].ID())
+	})
+
+	index := make(map[ID]int, len(sorted))
+	for i, t := range sorted {
+		index[t.ID()] = i
+	}
+
+	// successors[i] holds the traits that must be executed after sorted[i]
+	successors := make([][]int, len(sorted))
+	inDegree := make([]int, len(sorted))
+	addEdge := func(from int, to int) {
+		for _, s := range successors[from] {
+			if s == to {
+				return
+			}
+		}
+		successors[from] = append(successors[from], to)
+		inDegree[to]++
+	}
+	for i, t := range sorted {
+		for _, id := range t.RequiresTraits() {
+			if j, ok := index[id]; ok && j != i {
+				addEdge(j, i)
+			}
+		}
+		for _, id := range t.InfluencesTraits() {
+			if j, ok := index[id]; ok && j != i {
+				addEdge(i, j)
+			}
+		}
+	}
+
+	res := make([]Trait, 0, len(sorted))
+	done := make([]bool, len(sorted))
+	for len(res) < len(sorted) {
+		next := -1
+		for i := range sorted {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			cycle := make([]string, 0)
+			for i, t := range sorted {
+				if !done[i] {
+					cycle = append(cycle, string(t.ID()))
+				}
+			}
+			return nil, errors.Errorf("cyclic dependency between traits: %s", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		res = append(res, sorted[next])
+		for _, s := range successors[next] {
+			inDegree[s]--
+		}
+	}
+
+	return res, nil
+}
+
 func (c *Catalog) AllTraits() []Trait {
 	return append([]Trait(nil), c.traits...)
 }
@@ -102,13 +172,17 @@ func (c *Catalog) apply(environment *Environment) error {
 			continue
 		}
 		applicable = true
+		start := time.Now()
 		enabled, err := trait.Configure(environment)
+		c.observeTraitDuration(environment, trait, traitStepConfigure, start)
 		if err != nil {
 			return err
 		}
 
 		if enabled {
+			start = time.Now()
 			err = trait.Apply(environment)
+			c.observeTraitDuration(environment, trait, traitStepApply, start)
 			if err != nil {
 				return err
 			}
@@ -145,6 +219,25 @@ func (c *Catalog) apply(environment *Environment) error {
 	return nil
 }
 
+// observeTraitDuration records the duration of the given trait step, and logs the trait when it's slow,
+// e.g., because it looks up resources from the API server.
+func (c *Catalog) observeTraitDuration(environment *Environment, trait Trait, step string, start time.Time) {
+	duration := time.Since(start)
+	traitDuration.WithLabelValues(string(trait.ID()), step).Observe(duration.Seconds())
+
+	l := c.L
+	if environment.Integration != nil {
+		l = l.ForIntegration(environment.Integration)
+	} else if environment.IntegrationKit != nil {
+		l = l.ForIntegrationKit(environment.IntegrationKit)
+	}
+	if duration >= slowTraitThreshold {
+		l.Infof("Slow trait %s: %s took %s", trait.ID(), step, duration)
+	} else {
+		l.Debugf("Trait %s: %s took %s", trait.ID(), step, duration)
+	}
+}
+
 // GetTrait returns the trait with the given ID.
 func (c *Catalog) GetTrait(id string) Trait {
 	for _, t := range c.AllTraits() {
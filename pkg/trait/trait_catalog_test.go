@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type dependentTrait struct {
+	BaseTrait
+	requires   []ID
+	influences []ID
+}
+
+func newDependentTrait(id string, order int, requires []ID, influences []ID) Trait {
+	return &dependentTrait{
+		BaseTrait:  NewBaseTrait(id, order),
+		requires:   requires,
+		influences: influences,
+	}
+}
+
+func (t *dependentTrait) Configure(*Environment) (bool, error) {
+	return true, nil
+}
+
+func (t *dependentTrait) Apply(*Environment) error {
+	return nil
+}
+
+func (t *dependentTrait) RequiresTraits() []ID {
+	return t.requires
+}
+
+func (t *dependentTrait) InfluencesTraits() []ID {
+	return t.influences
+}
+
+func traitIDs(traits []Trait) []ID {
+	ids := make([]ID, 0, len(traits))
+	for _, t := range traits {
+		ids = append(ids, t.ID())
+	}
+	return ids
+}
+
+func TestSortTraitsByOrder(t *testing.T) {
+	sorted, err := sortTraits([]Trait{
+		newDependentTrait("c", 300, nil, nil),
+		newDependentTrait("b", 100, nil, nil),
+		newDependentTrait("a", 100, nil, nil),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ID{"a", "b", "c"}, traitIDs(sorted))
+}
+
+func TestSortTraitsByDependencies(t *testing.T) {
+	sorted, err := sortTraits([]Trait{
+		newDependentTrait("a", 100, []ID{"c"}, nil),
+		newDependentTrait("b", 200, nil, nil),
+		newDependentTrait("c", 300, nil, nil),
+		newDependentTrait("d", 400, nil, []ID{"b"}),
+		newDependentTrait("e", 500, []ID{"missing"}, []ID{"missing"}),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []ID{"c", "a", "d", "b", "e"}, traitIDs(sorted))
+}
+
+func TestSortTraitsWithCycle(t *testing.T) {
+	_, err := sortTraits([]Trait{
+		newDependentTrait("a", 100, []ID{"c"}, nil),
+		newDependentTrait("b", 200, []ID{"a"}, []ID{"c"}),
+		newDependentTrait("c", 300, nil, nil),
+		newDependentTrait("d", 400, nil, nil),
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "cyclic dependency between traits: a, b, c", err.Error())
+}
+
+func TestCatalogHonoursTraitDependencies(t *testing.T) {
+	traits := NewCatalog(nil).AllTraits()
+	position := make(map[ID]int, len(traits))
+	for i, trait := range traits {
+		position[trait.ID()] = i
+	}
+
+	for i, trait := range traits {
+		for _, id := range trait.RequiresTraits() {
+			if j, ok := position[id]; ok {
+				assert.Less(t, j, i, "trait %s must be executed before %s", id, trait.ID())
+			}
+		}
+		for _, id := range trait.InfluencesTraits() {
+			if j, ok := position[id]; ok {
+				assert.Greater(t, j, i, "trait %s must be executed after %s", id, trait.ID())
+			}
+		}
+	}
+}
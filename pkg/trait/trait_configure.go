@@ -27,49 +27,68 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 
+	corev1 "k8s.io/api/core/v1"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 )
 
 func (c *Catalog) configure(env *Environment) error {
 	if env.Platform != nil {
 		if env.Platform.Status.Traits != nil {
-			if err := c.configureTraits(env.Platform.Status.Traits); err != nil {
+			if err := c.configureTraits(env, env.Platform.Status.Traits); err != nil {
 				return err
 			}
 		}
-		if err := c.configureTraitsFromAnnotations(env.Platform.Annotations); err != nil {
+		if _, err := c.configureTraitsFromAnnotations(env, env.Platform.Annotations); err != nil {
 			return err
 		}
 	}
 	if env.IntegrationKit != nil {
 		if env.IntegrationKit.Spec.Traits != nil {
-			if err := c.configureTraits(env.IntegrationKit.Spec.Traits); err != nil {
+			if err := c.configureTraits(env, env.IntegrationKit.Spec.Traits); err != nil {
 				return err
 			}
 		}
-		if err := c.configureTraitsFromAnnotations(env.IntegrationKit.Annotations); err != nil {
+		if _, err := c.configureTraitsFromAnnotations(env, env.IntegrationKit.Annotations); err != nil {
 			return err
 		}
 	}
 	if env.Integration != nil {
 		if env.Integration.Spec.Traits != nil {
-			if err := c.configureTraits(env.Integration.Spec.Traits); err != nil {
+			if err := c.configureTraits(env, env.Integration.Spec.Traits); err != nil {
+				return err
+			}
+		}
+		profile, err := env.Integration.ActiveEnvironmentProfile(env.Platform)
+		if err != nil {
+			return err
+		}
+		env.environmentProfile = profile
+		if profile != nil && profile.Traits != nil {
+			if err := c.configureTraits(env, profile.Traits); err != nil {
 				return err
 			}
 		}
-		if err := c.configureTraitsFromAnnotations(env.Integration.Annotations); err != nil {
+		// The annotations take precedence over the Integration spec and the environment profile,
+		// so that they can be used to override the traits configuration, e.g., from GitOps overlays
+		overrides, err := c.configureTraitsFromAnnotations(env, env.Integration.Annotations)
+		if err != nil {
 			return err
 		}
+		reportTraitOverrides(env.Integration, overrides)
 	}
 
 	return nil
 }
 
-func (c *Catalog) configureTraits(traits map[string]v1.TraitSpec) error {
+func (c *Catalog) configureTraits(env *Environment, traits map[string]v1.TraitSpec) error {
 	for id, traitSpec := range traits {
 		catTrait := c.GetTrait(id)
 		if catTrait != nil {
 			trait := traitSpec
+			if err := env.interpolateTraitSpec(&trait); err != nil {
+				return errors.Wrapf(err, "cannot configure trait %s", id)
+			}
 			if err := decodeTraitSpec(&trait, catTrait); err != nil {
 				return err
 			}
@@ -88,53 +107,68 @@ func decodeTraitSpec(in *v1.TraitSpec, target interface{}) error {
 	return json.Unmarshal(data, &target)
 }
 
-func (c *Catalog) configureTraitsFromAnnotations(annotations map[string]string) error {
+// configureTraitsFromAnnotations configures the traits from the `trait.camel.apache.org/<trait>.<property>` annotations,
+// and returns the overridden properties, with their effective values, in the `<trait>.<property>=<value>` form.
+func (c *Catalog) configureTraitsFromAnnotations(env *Environment, annotations map[string]string) ([]string, error) {
 	options := make(map[string]map[string]interface{}, len(annotations))
-	for k, v := range annotations {
-		if strings.HasPrefix(k, v1.TraitAnnotationPrefix) {
-			configKey := strings.TrimPrefix(k, v1.TraitAnnotationPrefix)
-			if strings.Contains(configKey, ".") {
-				parts := strings.SplitN(configKey, ".", 2)
-				id := parts[0]
-				prop := parts[1]
-				if _, ok := options[id]; !ok {
-					options[id] = make(map[string]interface{})
-				}
-
-				propParts := util.ConfigTreePropertySplit(prop)
-				var current = options[id]
-				if len(propParts) > 1 {
-					c, err := util.NavigateConfigTree(current, propParts[0:len(propParts)-1])
-					if err != nil {
-						return err
-					}
-					if cc, ok := c.(map[string]interface{}); ok {
-						current = cc
-					} else {
-						return errors.New(`invalid array specification: to set an array value use the ["v1", "v2"] format`)
-					}
-				}
-				current[prop] = v
+	var overrides []string
+	for _, k := range util.SortedStringMapKeys(annotations) {
+		if !strings.HasPrefix(k, v1.TraitAnnotationPrefix) {
+			continue
+		}
+		v := annotations[k]
+		configKey := strings.TrimPrefix(k, v1.TraitAnnotationPrefix)
+		if !strings.Contains(configKey, ".") {
+			return nil, fmt.Errorf("wrong format for trait annotation %q: missing trait ID", k)
+		}
+		parts := strings.SplitN(configKey, ".", 2)
+		id := parts[0]
+		prop := parts[1]
+		if c.GetTrait(id) == nil {
+			return nil, &ConfigurationError{Trait: ID(id), Err: fmt.Errorf("unknown trait in annotation %q", k)}
+		}
+		if _, ok := options[id]; !ok {
+			options[id] = make(map[string]interface{})
+		}
 
+		propParts := util.ConfigTreePropertySplit(prop)
+		var current = options[id]
+		if len(propParts) > 1 {
+			c, err := util.NavigateConfigTree(current, propParts[0:len(propParts)-1])
+			if err != nil {
+				return nil, err
+			}
+			if cc, ok := c.(map[string]interface{}); ok {
+				current = cc
 			} else {
-				return fmt.Errorf("wrong format for trait annotation %q: missing trait ID", k)
+				return nil, errors.New(`invalid array specification: to set an array value use the ["v1", "v2"] format`)
 			}
 		}
+		value, err := env.interpolate(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot configure trait %s", id)
+		}
+		current[prop] = value
+		overrides = append(overrides, configKey+"="+value)
 	}
-	return c.configureFromOptions(options)
-}
 
-func (c *Catalog) configureFromOptions(traits map[string]map[string]interface{}) error {
-	for id, config := range traits {
-		t := c.GetTrait(id)
-		if t != nil {
-			err := configureTrait(id, config, t)
-			if err != nil {
-				return err
-			}
+	for id, config := range options {
+		if err := configureTrait(id, config, c.GetTrait(id)); err != nil {
+			return nil, &ConfigurationError{Trait: ID(id), Err: errors.Wrap(err, "cannot decode the trait annotations")}
 		}
 	}
-	return nil
+
+	return overrides, nil
+}
+
+// reportTraitOverrides surfaces the trait properties that are overridden by the Integration annotations.
+func reportTraitOverrides(integration *v1.Integration, overrides []string) {
+	if len(overrides) == 0 {
+		integration.Status.RemoveCondition(v1.IntegrationConditionTraitOverrides)
+		return
+	}
+	integration.Status.SetCondition(v1.IntegrationConditionTraitOverrides, corev1.ConditionTrue,
+		v1.IntegrationConditionTraitOverridesReason, "trait properties overridden by annotations: "+strings.Join(overrides, ", "))
 }
 
 func configureTrait(id string, config map[string]interface{}, trait interface{}) error {
@@ -170,3 +204,17 @@ func configureTrait(id string, config map[string]interface{}, trait interface{})
 
 	return decoder.Decode(config)
 }
+
+// ConfigurationError reports a trait configuration that cannot be applied, until the user fixes it.
+type ConfigurationError struct {
+	Trait ID
+	Err   error
+}
+
+func (e *ConfigurationError) Error() string {
+	return fmt.Sprintf("invalid %s trait configuration: %v", e.Trait, e.Err)
+}
+
+func (e *ConfigurationError) Unwrap() error {
+	return e.Err
+}
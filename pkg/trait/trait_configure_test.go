@@ -18,10 +18,12 @@ limitations under the License.
 package trait
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
@@ -166,3 +168,146 @@ func TestTraitSplitConfiguration(t *testing.T) {
 	assert.NoError(t, c.configure(&env))
 	assert.Equal(t, []string{"opt1", "opt2"}, c.GetTrait("owner").(*ownerTrait).TargetLabels)
 }
+
+func TestTraitConfigurationFromEnvironmentProfile(t *testing.T) {
+	env := Environment{
+		Platform: &v1.IntegrationPlatform{
+			Status: v1.IntegrationPlatformStatus{
+				IntegrationPlatformSpec: v1.IntegrationPlatformSpec{
+					EnvironmentProfile: "prod",
+				},
+			},
+		},
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"trait.camel.apache.org/cron.components": "annotated-cmp",
+				},
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"cron": test.TraitSpecFromMap(t, map[string]interface{}{
+						"schedule":   "schedule1",
+						"components": "cmp1",
+					}),
+				},
+				Configuration: []v1.ConfigurationSpec{
+					{Type: "property", Value: "my.property=default"},
+					{Type: "property", Value: "my.other.property=default"},
+				},
+				Profiles: []v1.EnvironmentProfileSpec{
+					{
+						Name: "dev",
+						Traits: map[string]v1.TraitSpec{
+							"cron": test.TraitSpecFromMap(t, map[string]interface{}{
+								"schedule": "dev-schedule",
+							}),
+						},
+					},
+					{
+						Name: "prod",
+						Configuration: []v1.ConfigurationSpec{
+							{Type: "property", Value: "my.property=prod"},
+						},
+						Traits: map[string]v1.TraitSpec{
+							"cron": test.TraitSpecFromMap(t, map[string]interface{}{
+								"schedule":   "prod-schedule",
+								"components": "prod-cmp",
+							}),
+						},
+					},
+				},
+			},
+		},
+	}
+	c := NewCatalog(nil)
+	assert.NoError(t, c.configure(&env))
+	assert.Equal(t, "prod-schedule", c.GetTrait("cron").(*cronTrait).Schedule)
+	assert.Equal(t, "annotated-cmp", c.GetTrait("cron").(*cronTrait).Components)
+	assert.Equal(t, []Variable{
+		{Name: "my.property", Value: "prod", Source: ConfigurationSourceProfile},
+		{Name: "my.other.property", Value: "default", Source: ConfigurationSourceIntegration},
+	}, env.collectConfigurationPairs("property"))
+
+	env.Integration.Annotations[v1.EnvironmentProfileAnnotation] = "dev"
+	c = NewCatalog(nil)
+	assert.NoError(t, c.configure(&env))
+	assert.Equal(t, "dev-schedule", c.GetTrait("cron").(*cronTrait).Schedule)
+
+	env.Integration.Annotations[v1.EnvironmentProfileAnnotation] = "stage"
+	assert.Error(t, NewCatalog(nil).configure(&env))
+}
+
+func TestFailOnUnknownTraitAnnotations(t *testing.T) {
+	env := Environment{
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"trait.camel.apache.org/missing-trait.enabled": "true",
+				},
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+			},
+		},
+	}
+	err := NewCatalog(nil).configure(&env)
+	var cerr *ConfigurationError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, ID("missing-trait"), cerr.Trait)
+}
+
+func TestFailOnInvalidTraitAnnotationValue(t *testing.T) {
+	env := Environment{
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"trait.camel.apache.org/cron.fallback": "maybe",
+				},
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+			},
+		},
+	}
+	err := NewCatalog(nil).configure(&env)
+	var cerr *ConfigurationError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, ID("cron"), cerr.Trait)
+	assert.Contains(t, err.Error(), "fallback")
+}
+
+func TestTraitOverridesFromAnnotationsCondition(t *testing.T) {
+	env := Environment{
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"trait.camel.apache.org/cron.schedule": "schedule2",
+					"trait.camel.apache.org/cron.fallback": "true",
+				},
+			},
+			Spec: v1.IntegrationSpec{
+				Profile: v1.TraitProfileKubernetes,
+				Traits: map[string]v1.TraitSpec{
+					"cron": test.TraitSpecFromMap(t, map[string]interface{}{
+						"schedule": "schedule1",
+					}),
+				},
+			},
+		},
+	}
+	c := NewCatalog(nil)
+	assert.NoError(t, c.configure(&env))
+	assert.Equal(t, "schedule2", c.GetTrait("cron").(*cronTrait).Schedule)
+	assert.True(t, *c.GetTrait("cron").(*cronTrait).Fallback)
+
+	condition := env.Integration.Status.GetCondition(v1.IntegrationConditionTraitOverrides)
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, "trait properties overridden by annotations: cron.fallback=true, cron.schedule=schedule2", condition.Message)
+
+	env.Integration.Annotations = nil
+	assert.NoError(t, NewCatalog(nil).configure(&env))
+	assert.Nil(t, env.Integration.Status.GetCondition(v1.IntegrationConditionTraitOverrides))
+}
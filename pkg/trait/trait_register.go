@@ -20,40 +20,63 @@ package trait
 func init() {
 	// List of default trait factories.
 	// Declaration order is not important, but let's keep them sorted for debugging.
+	AddToTraits(newAccessLogTrait)
 	AddToTraits(newAffinityTrait)
 	AddToTraits(newBuilderTrait)
 	AddToTraits(newCamelTrait)
+	AddToTraits(newCloudIdentityTrait)
+	AddToTraits(newConcurrencyTrait)
 	AddToTraits(newContainerTrait)
+	AddToTraits(newCorrelationTrait)
 	AddToTraits(newCronTrait)
+	AddToTraits(newDatasourceTrait)
 	AddToTraits(newDependenciesTrait)
 	AddToTraits(newDeployerTrait)
 	AddToTraits(newDeploymentTrait)
+	AddToTraits(newDNSTrait)
 	AddToTraits(newEnvironmentTrait)
 	AddToTraits(newErrorHandlerTrait)
 	AddToTraits(newGarbageCollectorTrait)
+	AddToTraits(newGatewayAPITrait)
 	AddToTraits(newHealthTrait)
 	AddToTraits(NewInitTrait)
+	AddToTraits(newInitContainersTrait)
 	AddToTraits(newIngressTrait)
 	AddToTraits(newIstioTrait)
+	AddToTraits(newJBangTrait)
+	AddToTraits(newJobTrait)
 	AddToTraits(newJolokiaTrait)
 	AddToTraits(newJvmTrait)
 	AddToTraits(newKameletsTrait)
+	AddToTraits(newKerberosTrait)
 	AddToTraits(newKnativeTrait)
 	AddToTraits(newKnativeServiceTrait)
 	AddToTraits(newLoggingTraitTrait)
+	AddToTraits(newMigrationTrait)
 	AddToTraits(newMountTrait)
 	AddToTraits(newOpenAPITrait)
 	AddToTraits(newOwnerTrait)
 	AddToTraits(newPdbTrait)
 	AddToTraits(newPlatformTrait)
 	AddToTraits(newPodTrait)
+	AddToTraits(newPrebuiltRoutesTrait)
 	AddToTraits(newPrometheusTrait)
 	AddToTraits(newPullSecretTrait)
 	AddToTraits(newQuarkusTrait)
 	AddToTraits(newRegistryTrait)
+	AddToTraits(newResourceProfilingTrait)
+	AddToTraits(newRestartBudgetTrait)
+	AddToTraits(newRolloutTrait)
 	AddToTraits(newRouteTrait)
+	AddToTraits(newSchemaRegistryTrait)
 	AddToTraits(newServiceTrait)
 	AddToTraits(newServiceBindingTrait)
+	AddToTraits(newSmokeTestTrait)
+	AddToTraits(newStorageTrait)
 	AddToTraits(newTolerationTrait)
+	AddToTraits(newTopologySpreadTrait)
+	AddToTraits(newTransactionTrait)
+	AddToTraits(newTruststoreTrait)
+	AddToTraits(newWaitForTrait)
 	// ^^ Declaration order is not important, but let's keep them sorted for debugging.
 }
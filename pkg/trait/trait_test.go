@@ -21,6 +21,8 @@ import (
 	"path"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 
 	routev1 "github.com/openshift/api/route/v1"
@@ -121,6 +123,20 @@ func TestKubernetesTraits(t *testing.T) {
 	}))
 }
 
+func TestTraitDurationMetrics(t *testing.T) {
+	applied := traitStepSampleCount(t, "deployment", traitStepApply)
+	configured := traitStepSampleCount(t, "service", traitStepConfigure)
+	notApplied := traitStepSampleCount(t, "service", traitStepApply)
+
+	env := createTestEnv(t, v1.IntegrationPlatformClusterKubernetes, "from('timer:tick').to('log:info')")
+	processTestEnv(t, env)
+
+	assert.Equal(t, applied+1, traitStepSampleCount(t, "deployment", traitStepApply))
+	// The duration is recorded for the traits that are configured but not applied
+	assert.Equal(t, configured+1, traitStepSampleCount(t, "service", traitStepConfigure))
+	assert.Equal(t, notApplied, traitStepSampleCount(t, "service", traitStepApply))
+}
+
 func TestKubernetesTraitsWithWeb(t *testing.T) {
 	env := createTestEnv(t, v1.IntegrationPlatformClusterKubernetes, "from('servlet:http').to('log:info')")
 	res := processTestEnv(t, env)
@@ -543,6 +559,18 @@ func findVVolumeMount(vols []corev1.VolumeMount, condition func(corev1.VolumeMou
 	return nil
 }
 
+func traitStepSampleCount(t *testing.T, id string, step string) uint64 {
+	t.Helper()
+
+	metric := dto.Metric{}
+	observer, err := traitDuration.GetMetricWithLabelValues(id, step)
+	assert.Nil(t, err)
+	histogram, ok := observer.(prometheus.Histogram)
+	assert.True(t, ok)
+	assert.Nil(t, histogram.Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
 func processTestEnv(t *testing.T, env *Environment) *kubernetes.Collection {
 	t.Helper()
 
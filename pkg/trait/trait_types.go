@@ -23,17 +23,21 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/builder"
 	"github.com/apache/camel-k/pkg/client"
 	"github.com/apache/camel-k/pkg/platform"
 	"github.com/apache/camel-k/pkg/util"
@@ -41,6 +45,7 @@ import (
 	"github.com/apache/camel-k/pkg/util/kubernetes"
 	"github.com/apache/camel-k/pkg/util/log"
 	"github.com/apache/camel-k/pkg/util/property"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 const (
@@ -81,6 +86,12 @@ type Trait interface {
 
 	// Order is the order in which the trait should be executed in the normal flow
 	Order() int
+
+	// RequiresTraits lists the traits that must be executed before this trait, when they are part of the catalog
+	RequiresTraits() []ID
+
+	// InfluencesTraits lists the traits that must be executed after this trait, when they are part of the catalog
+	InfluencesTraits() []ID
 }
 
 type Comparable interface {
@@ -159,6 +170,16 @@ func (trait *BaseTrait) Order() int {
 	return trait.ExecutionOrder
 }
 
+// RequiresTraits declares no dependency by default.
+func (trait *BaseTrait) RequiresTraits() []ID {
+	return nil
+}
+
+// InfluencesTraits declares no dependent trait by default.
+func (trait *BaseTrait) InfluencesTraits() []ID {
+	return nil
+}
+
 // ControllerStrategySelector is the interface for traits that can determine the kind of controller that will run the integration.
 type ControllerStrategySelector interface {
 	// SelectControllerStrategy tells if the trait with current configuration can select a specific controller to use
@@ -176,8 +197,10 @@ type Environment struct {
 	Ctx context.Context
 	// The client to the API server
 	Client client.Client
-	// The active Platform
+	// The active Platform, with the registry of the tenant if any
 	Platform *v1.IntegrationPlatform
+	// The tenant configuration of the namespace, if any
+	Tenant *platform.Tenant
 	// The current Integration
 	Integration *v1.Integration
 	// The IntegrationKit associated to the Integration
@@ -196,6 +219,13 @@ type Environment struct {
 	ApplicationProperties map[string]string
 	Interceptors          []string
 	ServiceBindingSecret  string
+	// The delay after which the Integration must be reconciled again, e.g., to apply a time based configuration
+	RequeueAfter time.Duration
+
+	// The cluster facts resolved during the reconciliation
+	facts map[string]string
+	// The environment profile of the Integration that is active
+	environmentProfile *v1.EnvironmentProfileSpec
 }
 
 // ControllerStrategy is used to determine the kind of controller that needs to be created for the integration.
@@ -206,6 +236,7 @@ const (
 	ControllerStrategyDeployment     ControllerStrategy = "deployment"
 	ControllerStrategyKnativeService ControllerStrategy = "knative-service"
 	ControllerStrategyCronJob        ControllerStrategy = "cron-job"
+	ControllerStrategyJob            ControllerStrategy = "job"
 
 	DefaultControllerStrategy = ControllerStrategyDeployment
 )
@@ -235,7 +266,14 @@ func (e *Environment) IntegrationInPhase(phases ...v1.IntegrationPhase) bool {
 }
 
 func (e *Environment) IntegrationInRunningPhases() bool {
-	return e.IntegrationInPhase(v1.IntegrationPhaseDeploying, v1.IntegrationPhaseRunning, v1.IntegrationPhaseError)
+	return e.IntegrationInPhase(v1.IntegrationPhaseDeploying, v1.IntegrationPhaseRunning, v1.IntegrationPhaseCompleted, v1.IntegrationPhaseError)
+}
+
+// RequeueNoLaterThan requests the Integration to be reconciled again, no later than after the given delay.
+func (e *Environment) RequeueNoLaterThan(after time.Duration) {
+	if e.RequeueAfter == 0 || after < e.RequeueAfter {
+		e.RequeueAfter = after
+	}
 }
 
 func (e *Environment) IntegrationKitInPhase(phases ...v1.IntegrationKitPhase) bool {
@@ -333,6 +371,14 @@ func (e *Environment) GetIntegrationPodSpec() *corev1.PodSpec {
 		return &cronJob.Spec.JobTemplate.Spec.Template.Spec
 	}
 
+	// Job
+	job := e.Resources.GetJob(func(j *batchv1.Job) bool {
+		return j.Name == e.Integration.Name
+	})
+	if job != nil {
+		return &job.Spec.Template.Spec
+	}
+
 	return nil
 }
 
@@ -393,6 +439,10 @@ func (e *Environment) addSourcesProperties() {
 	for i, s := range e.Integration.Sources() {
 		srcName := strings.TrimPrefix(s.Name, "/")
 		src := "file:" + path.Join(camel.SourcesMountPath, srcName)
+		if upload.IsRef(s.ContentRef) {
+			// the uploaded sources are added to the Integration image
+			src = "file:" + path.Join(builder.DeploymentDir, upload.SourcePath(s.Name))
+		}
 		e.ApplicationProperties[fmt.Sprintf("camel.k.sources[%d].location", i)] = src
 
 		simpleName := srcName
@@ -436,6 +486,9 @@ func (e *Environment) configureVolumesAndMounts(vols *[]corev1.Volume, mnts *[]c
 	// Volumes :: Sources
 	//
 	for i, s := range e.Integration.Sources() {
+		if upload.IsRef(s.ContentRef) {
+			continue
+		}
 		cmName := fmt.Sprintf("%s-source-%03d", e.Integration.Name, i)
 		if s.ContentRef != "" {
 			cmName = s.ContentRef
@@ -649,19 +702,53 @@ func getMountPoint(resourceName string, mountPoint string, storagetype, resource
 }
 
 func (e *Environment) collectConfigurationValues(configurationType string) []string {
-	return collectConfigurationValues(configurationType, e.Platform, e.IntegrationKit, e.Integration)
+	return collectConfigurationValues(configurationType, e.Platform, e.IntegrationKit, e.Integration, e.environmentProfile)
 }
 
-type variable struct {
+// Variable is an environment variable, or a property, resolved from the given source.
+type Variable struct {
 	Name, Value string
+	Source      ConfigurationSource
+}
+
+func (e *Environment) collectConfigurationPairs(configurationType string) []Variable {
+	return collectConfigurationPairs(configurationType, e.Platform, e.IntegrationKit, e.Integration, e.environmentProfile)
 }
 
-func (e *Environment) collectConfigurationPairs(configurationType string) []variable {
-	return collectConfigurationPairs(configurationType, e.Platform, e.IntegrationKit, e.Integration)
+// collectEnvVars returns the environment variables from the platform, kit, integration and environment profile
+// configuration, overridden by the user environment variables.
+func (e *Environment) collectEnvVars() []Variable {
+	vars := e.collectConfigurationPairs("env")
+	if t, ok := e.GetTrait(environmentTraitID).(*environmentTrait); ok {
+		for _, v := range t.userEnvVars() {
+			vars = setVariable(vars, v)
+		}
+	}
+	return vars
+}
+
+// EffectiveConfiguration returns the environment variables and the properties of the Integration, resolved by precedence
+// from the platform, kit and integration configuration, and the user environment variables.
+// The environment variables injected by the traits are only known once the Integration is deployed, and are not included.
+func EffectiveConfiguration(platform *v1.IntegrationPlatform, kit *v1.IntegrationKit, integration *v1.Integration) ([]Variable, []Variable, error) {
+	e := Environment{
+		Catalog:        NewCatalog(nil),
+		Platform:       platform,
+		IntegrationKit: kit,
+		Integration:    integration,
+	}
+	if err := e.Catalog.configure(&e); err != nil {
+		return nil, nil, err
+	}
+	if t := e.Catalog.GetTrait(environmentTraitID); t != nil && pointer.BoolDeref(t.(*environmentTrait).Enabled, true) {
+		e.ExecutedTraits = append(e.ExecutedTraits, t)
+	}
+
+	return e.collectEnvVars(), e.collectConfigurationPairs("property"), nil
 }
 
 func (e *Environment) collectConfigurations(configurationType string) []map[string]string {
-	return collectConfigurations(configurationType, e.Platform, e.IntegrationKit, e.Integration)
+	return collectConfigurations(configurationType, e.Platform, e.IntegrationKit, e.Integration, e.environmentProfile)
 }
 
 func (e *Environment) GetIntegrationContainerName() string {
@@ -702,6 +789,65 @@ func (e *Environment) getIntegrationContainerPort() *corev1.ContainerPort {
 	return nil
 }
 
+// GetSmokeTestProbes returns the HTTP probes to be called once the Integration is deployed,
+// or nil if the smoke-test trait is not enabled.
+func (e *Environment) GetSmokeTestProbes() []corev1.Probe {
+	t := e.GetTrait(smokeTestTraitID)
+	if t == nil {
+		return nil
+	}
+	return t.(*smokeTestTrait).probes(e)
+}
+
+// IsReconcilePaused returns whether the resources owned by the Integration must be left as they are on the cluster.
+func (e *Environment) IsReconcilePaused() bool {
+	return e.Integration != nil && e.Integration.IsReconcilePaused(e.Platform)
+}
+
+// IsSmokeTestRollbackEnabled returns whether the Integration must be rolled back when the smoke test fails.
+func (e *Environment) IsSmokeTestRollbackEnabled() bool {
+	t := e.GetTrait(smokeTestTraitID)
+	if t == nil {
+		return false
+	}
+	return pointer.BoolDeref(t.(*smokeTestTrait).Rollback, false)
+}
+
+// GetWaitForContainerName returns the name of the init container that waits for the Integration dependencies
+// to be reachable, or an empty string if the wait-for trait is not enabled.
+func (e *Environment) GetWaitForContainerName() string {
+	if e.GetTrait(waitForTraitID) == nil {
+		return ""
+	}
+	return waitForContainerName
+}
+
+// GetRollout returns the configuration of the canary rollout of the Integration, or nil if it's disabled.
+func (e *Environment) GetRollout() *Rollout {
+	t := e.GetTrait(rolloutTraitID)
+	if t == nil {
+		return nil
+	}
+	rollout, err := t.(*rolloutTrait).rollout()
+	if err != nil {
+		return nil
+	}
+	return rollout
+}
+
+// GetResourceProfiling returns the configuration of the resource profiling of the Integration, or nil if it's disabled.
+func (e *Environment) GetResourceProfiling() *ResourceProfiling {
+	t := e.GetTrait(resourceProfilingTraitID)
+	if t == nil {
+		return nil
+	}
+	profiling, err := t.(*resourceProfilingTrait).profiling()
+	if err != nil {
+		return nil
+	}
+	return profiling
+}
+
 // nolint: unused
 func (e *Environment) getAllInterceptors() []string {
 	res := make([]string, 0)
@@ -714,3 +860,16 @@ func (e *Environment) getAllInterceptors() []string {
 	}
 	return res
 }
+
+// GetRestartBudget returns the configuration of the restart budget of the Integration, or nil if it's disabled.
+func (e *Environment) GetRestartBudget() *RestartBudget {
+	t := e.GetTrait(restartBudgetTraitID)
+	if t == nil {
+		return nil
+	}
+	budget, err := t.(*restartBudgetTrait).budget()
+	if err != nil {
+		return nil
+	}
+	return budget
+}
@@ -18,11 +18,16 @@ limitations under the License.
 package trait
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/upload"
 )
 
 func TestMultilinePropertiesHandled(t *testing.T) {
@@ -107,10 +112,36 @@ func TestCollectConfigurationPairs(t *testing.T) {
 	e.Platform.ResyncStatusFullConfig()
 
 	pairs := e.collectConfigurationPairs("property")
-	assert.Equal(t, pairs, []variable{
-		{Name: "p1", Value: "integration"},
-		{Name: "p2", Value: "kit"},
-		{Name: "p3", Value: "platform"},
-		{Name: "p4", Value: "integration"},
+	assert.Equal(t, pairs, []Variable{
+		{Name: "p1", Value: "integration", Source: ConfigurationSourceIntegration},
+		{Name: "p2", Value: "kit", Source: ConfigurationSourceKit},
+		{Name: "p3", Value: "platform", Source: ConfigurationSourcePlatform},
+		{Name: "p4", Value: "integration", Source: ConfigurationSourceIntegration},
 	})
 }
+
+func TestUploadedSourcesAreNotMounted(t *testing.T) {
+	ref := upload.Ref("ns", upload.DigestPrefix+strings.Repeat("a", 64))
+	e := Environment{
+		Integration: &v1.Integration{
+			ObjectMeta: metav1.ObjectMeta{Name: "it"},
+			Spec: v1.IntegrationSpec{
+				Sources: []v1.SourceSpec{
+					{DataSpec: v1.DataSpec{Name: "routes.java", Content: "from(\"timer:tick\")"}},
+					{DataSpec: v1.DataSpec{Name: "uploaded.yaml", ContentRef: ref}},
+				},
+			},
+		},
+	}
+
+	e.addSourcesProperties()
+	assert.Equal(t, "file:/etc/camel/sources/routes.java", e.ApplicationProperties["camel.k.sources[0].location"])
+	assert.Equal(t, "file:/deployments/camel-k-sources/uploaded.yaml", e.ApplicationProperties["camel.k.sources[1].location"])
+
+	vols := make([]corev1.Volume, 0)
+	mnts := make([]corev1.VolumeMount, 0)
+	e.configureVolumesAndMounts(&vols, &mnts)
+	assert.Len(t, vols, 1)
+	assert.Len(t, mnts, 1)
+	assert.Equal(t, "it-source-000", vols[0].ConfigMap.Name)
+}
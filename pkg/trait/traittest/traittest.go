@@ -0,0 +1,403 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package traittest runs declarative trait tests, that apply the traits to an Integration, and assert on the generated
+// resources, without a cluster.
+package traittest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clientscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/jsonpath"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apache/camel-k/pkg/apis"
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/client"
+	"github.com/apache/camel-k/pkg/platform"
+	"github.com/apache/camel-k/pkg/trait"
+	"github.com/apache/camel-k/pkg/util/camel"
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+const (
+	// Kind is the kind of the trait test documents.
+	Kind = "TraitTest"
+
+	// DefaultNamespace is the namespace of the Integration, when the test doesn't set it.
+	DefaultNamespace = "default"
+	// KitImage is the image of the kit the Integration runs, when the test doesn't set the kit of the Integration.
+	KitImage = "camel-k-kit:test"
+)
+
+// TraitTest declares an Integration, and the resources the traits are expected to generate for it.
+type TraitTest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TraitTestSpec `json:"spec"`
+}
+
+// TraitTestSpec defines the input and the expectations of a trait test.
+type TraitTestSpec struct {
+	// the Integration the traits are applied to
+	Integration v1.Integration `json:"integration"`
+	// the phase the traits are applied in, Deploying by default
+	Phase v1.IntegrationPhase `json:"phase,omitempty"`
+	// the platform the Integration runs on, a Kubernetes cluster platform by default
+	Platform *v1.IntegrationPlatformSpec `json:"platform,omitempty"`
+	// the resources existing in the namespace of the Integration, e.g., the ConfigMaps it mounts
+	Resources []unstructured.Unstructured `json:"resources,omitempty"`
+	// the error the traits are expected to fail with, if any
+	Error string `json:"error,omitempty"`
+	// the expectations on the generated resources
+	Expect []Expectation `json:"expect,omitempty"`
+}
+
+// Expectation asserts on a generated resource, or on the Integration itself when the kind is Integration.
+type Expectation struct {
+	// the kind of the resource
+	Kind string `json:"kind"`
+	// the name of the resource, the name of the Integration by default
+	Name string `json:"name,omitempty"`
+	// whether the resource must not be generated
+	Absent bool `json:"absent,omitempty"`
+	// the assertions on the resource
+	Assertions []Assertion `json:"assertions,omitempty"`
+}
+
+// Assertion checks the value at a JSONPath of a resource.
+type Assertion struct {
+	// the JSONPath expression, e.g., {.spec.replicas}
+	Path string `json:"path"`
+	// the expected value, a list when the path matches several values, or any value when omitted
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Result is the outcome of a trait test.
+type Result struct {
+	// the name of the test
+	Name string
+	// the failed expectations, if any
+	Failures []string
+}
+
+// Passed returns whether all the expectations of the test are met.
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Load decodes the trait tests of a YAML, or JSON, stream of documents.
+func Load(r io.Reader) ([]TraitTest, error) {
+	tests := make([]TraitTest, 0)
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var t TraitTest
+		if err := decoder.Decode(&t); err != nil {
+			if errors.Is(err, io.EOF) {
+				return tests, nil
+			}
+			return nil, err
+		}
+		if t.Kind == "" && t.Name == "" {
+			// empty document
+			continue
+		}
+		if t.Kind != Kind {
+			return nil, fmt.Errorf("unexpected kind %q, trait tests must be of kind %s", t.Kind, Kind)
+		}
+		if t.Name == "" {
+			return nil, errors.New("trait tests must have a name")
+		}
+		tests = append(tests, t)
+	}
+}
+
+// Run applies the traits to the Integration of the test, against a fake client populated with the platform, the kit
+// and the resources of the test, and checks the expectations on the generated resources. The error is only returned
+// when the test cannot be set up.
+func Run(ctx context.Context, t TraitTest) (*Result, error) {
+	c, it, err := setup(t)
+	if err != nil {
+		return nil, err
+	}
+
+	result := Result{Name: t.Name}
+
+	env, err := trait.Apply(ctx, c, it, nil)
+	switch {
+	case err != nil && t.Spec.Error == "":
+		result.Failures = append(result.Failures, fmt.Sprintf("traits failed: %v", err))
+		return &result, nil
+	case err != nil && !strings.Contains(err.Error(), t.Spec.Error):
+		result.Failures = append(result.Failures, fmt.Sprintf("traits failed with %q, expected %q", err.Error(), t.Spec.Error))
+		return &result, nil
+	case err != nil:
+		return &result, nil
+	case t.Spec.Error != "":
+		result.Failures = append(result.Failures, fmt.Sprintf("traits succeeded, expected to fail with %q", t.Spec.Error))
+		return &result, nil
+	}
+
+	for _, e := range t.Spec.Expect {
+		result.Failures = append(result.Failures, check(c, env, e)...)
+	}
+
+	return &result, nil
+}
+
+// Generate applies the traits to the Integration of the test, like Run does, and returns the generated resources,
+// sorted by kind and name, e.g., to preview the resources a change of the Integration results in. The expectations
+// of the test are ignored.
+func Generate(ctx context.Context, t TraitTest) ([]unstructured.Unstructured, error) {
+	c, it, err := setup(t)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := trait.Apply(ctx, c, it, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]unstructured.Unstructured, 0, env.Resources.Size())
+	for _, o := range env.Resources.Items() {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return nil, err
+		}
+		u := unstructured.Unstructured{Object: content}
+		if u.GetKind() == "" {
+			kinds, _, err := c.GetScheme().ObjectKinds(o)
+			if err != nil {
+				return nil, err
+			}
+			u.SetGroupVersionKind(kinds[0])
+		}
+		resources = append(resources, u)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].GetKind() != resources[j].GetKind() {
+			return resources[i].GetKind() < resources[j].GetKind()
+		}
+		return resources[i].GetName() < resources[j].GetName()
+	})
+
+	return resources, nil
+}
+
+// setup returns the Integration of the test, defaulted, and a fake client populated with its environment.
+func setup(t TraitTest) (client.Client, *v1.Integration, error) {
+	it := t.Spec.Integration.DeepCopy()
+	if it.Name == "" {
+		it.Name = t.Name
+	}
+	if it.Namespace == "" {
+		it.Namespace = DefaultNamespace
+	}
+	it.Status.Phase = t.Spec.Phase
+	if it.Status.Phase == v1.IntegrationPhaseNone {
+		it.Status.Phase = v1.IntegrationPhaseDeploying
+	}
+
+	objects, err := environmentObjects(t, it)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := test.NewFakeClient(objects...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c, it, nil
+}
+
+// environmentObjects returns the objects the fake client is populated with: the platform, the Camel catalog bundled
+// with the CLI, the kit of the Integration, and the resources of the test.
+func environmentObjects(t TraitTest, it *v1.Integration) ([]runtime.Object, error) {
+	catalog, err := camel.DefaultCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	pl := v1.NewIntegrationPlatform(it.Namespace, platform.DefaultPlatformName)
+	if t.Spec.Platform != nil {
+		pl.Spec = *t.Spec.Platform.DeepCopy()
+	}
+	if pl.Spec.Cluster == "" {
+		pl.Spec.Cluster = v1.IntegrationPlatformClusterKubernetes
+	}
+	if pl.Spec.Build.RuntimeVersion == "" {
+		pl.Spec.Build.RuntimeVersion = catalog.Runtime.Version
+	}
+	pl.Status.Phase = v1.IntegrationPlatformPhaseReady
+	pl.ResyncStatusFullConfig()
+
+	cc := v1.NewCamelCatalogWithSpecs(it.Namespace, "camel-catalog-"+strings.ToLower(catalog.Runtime.Version), catalog.CamelCatalogSpec)
+
+	objects := []runtime.Object{&pl, &cc}
+
+	if it.Status.IntegrationKit == nil && it.Spec.IntegrationKit == nil {
+		kit := v1.NewIntegrationKit(it.Namespace, "kit-"+it.Name)
+		kit.Status.Phase = v1.IntegrationKitPhaseReady
+		kit.Status.Image = KitImage
+		kit.Status.RuntimeVersion = catalog.Runtime.Version
+		kit.Status.RuntimeProvider = catalog.Runtime.Provider
+		it.Status.IntegrationKit = &corev1.ObjectReference{
+			Namespace: kit.Namespace,
+			Name:      kit.Name,
+		}
+		objects = append(objects, kit)
+	}
+
+	scheme := clientscheme.Scheme
+	if err := apis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	for i := range t.Spec.Resources {
+		u := t.Spec.Resources[i]
+		if u.GetNamespace() == "" {
+			u.SetNamespace(it.Namespace)
+		}
+		o, err := scheme.New(u.GroupVersionKind())
+		if err != nil {
+			return nil, errors.Wrapf(err, "unsupported resource %s %s", u.GetKind(), u.GetName())
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, o); err != nil {
+			return nil, errors.Wrapf(err, "invalid resource %s %s", u.GetKind(), u.GetName())
+		}
+		objects = append(objects, o)
+	}
+
+	return objects, nil
+}
+
+func check(c client.Client, env *trait.Environment, e Expectation) []string {
+	name := e.Name
+	if name == "" {
+		name = env.Integration.Name
+	}
+	id := e.Kind + " " + name
+
+	var resource ctrl.Object
+	if e.Kind == v1.IntegrationKind && name == env.Integration.Name {
+		resource = env.Integration
+	} else {
+		for _, o := range env.Resources.Items() {
+			if o.GetName() == name && kindOf(c, o) == e.Kind {
+				resource = o
+				break
+			}
+		}
+	}
+
+	switch {
+	case resource == nil && e.Absent:
+		return nil
+	case resource == nil:
+		return []string{id + " is not generated"}
+	case e.Absent:
+		return []string{id + " is generated, expected absent"}
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resource)
+	if err != nil {
+		return []string{fmt.Sprintf("%s cannot be converted: %v", id, err)}
+	}
+
+	failures := make([]string, 0)
+	for _, a := range e.Assertions {
+		if failure := evaluate(content, a); failure != "" {
+			failures = append(failures, id+": "+failure)
+		}
+	}
+	return failures
+}
+
+func kindOf(c client.Client, o ctrl.Object) string {
+	if kind := o.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	kinds, _, err := c.GetScheme().ObjectKinds(o)
+	if err != nil || len(kinds) == 0 {
+		return ""
+	}
+	return kinds[0].Kind
+}
+
+// evaluate returns the failure of the assertion on the content, or an empty string. The actual and expected values are
+// compared in JSON form, so that numbers compare regardless of their Go types.
+func evaluate(content map[string]interface{}, a Assertion) string {
+	path := a.Path
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+
+	j := jsonpath.New(a.Path)
+	if err := j.Parse(path); err != nil {
+		return fmt.Sprintf("invalid path %s: %v", a.Path, err)
+	}
+	results, err := j.FindResults(content)
+	if err != nil {
+		return fmt.Sprintf("%s not found", a.Path)
+	}
+
+	values := make([]interface{}, 0)
+	for _, r := range results {
+		for _, v := range r {
+			values = append(values, v.Interface())
+		}
+	}
+	if len(values) == 0 {
+		return fmt.Sprintf("%s not found", a.Path)
+	}
+	if a.Value == nil {
+		return ""
+	}
+
+	var actual interface{} = values
+	if len(values) == 1 {
+		actual = values[0]
+	}
+	actualJSON, err := json.Marshal(actual)
+	if err != nil {
+		return fmt.Sprintf("%s cannot be compared: %v", a.Path, err)
+	}
+	expectedJSON, err := json.Marshal(a.Value)
+	if err != nil {
+		return fmt.Sprintf("%s cannot be compared: %v", a.Path, err)
+	}
+	if !bytes.Equal(actualJSON, expectedJSON) {
+		return fmt.Sprintf("%s is %s, expected %s", a.Path, actualJSON, expectedJSON)
+	}
+	return ""
+}
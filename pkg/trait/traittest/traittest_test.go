@@ -0,0 +1,175 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package traittest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const deploymentTest = `
+apiVersion: camel.apache.org/v1alpha1
+kind: TraitTest
+metadata:
+  name: replicas
+spec:
+  integration:
+    metadata:
+      name: hello
+    spec:
+      replicas: 3
+      sources:
+      - name: hello.yaml
+        content: |
+          - from:
+              uri: "timer:tick"
+              steps:
+              - to: "log:info"
+  expect:
+  - kind: Deployment
+    assertions:
+    - path: "{.spec.replicas}"
+      value: 3
+    - path: "{.spec.template.spec.containers[0].image}"
+      value: camel-k-kit:test
+    - path: ".metadata.labels"
+  - kind: Service
+    absent: true
+---
+apiVersion: camel.apache.org/v1alpha1
+kind: TraitTest
+metadata:
+  name: wrong-replicas
+spec:
+  integration:
+    metadata:
+      name: hello
+    spec:
+      sources:
+      - name: hello.yaml
+        content: |
+          - from:
+              uri: "timer:tick"
+              steps:
+              - to: "log:info"
+  expect:
+  - kind: Deployment
+    assertions:
+    - path: "{.spec.replicas}"
+      value: 2
+  - kind: CronJob
+`
+
+func TestLoad(t *testing.T) {
+	tests, err := Load(strings.NewReader(deploymentTest))
+	assert.Nil(t, err)
+	assert.Len(t, tests, 2)
+	assert.Equal(t, "replicas", tests[0].Name)
+	assert.Equal(t, "hello", tests[0].Spec.Integration.Name)
+	assert.Len(t, tests[0].Spec.Expect, 2)
+	assert.Equal(t, "wrong-replicas", tests[1].Name)
+}
+
+func TestLoadInvalidKind(t *testing.T) {
+	_, err := Load(strings.NewReader("apiVersion: camel.apache.org/v1\nkind: Integration\nmetadata:\n  name: hello\n"))
+	assert.NotNil(t, err)
+}
+
+func TestRun(t *testing.T) {
+	tests, err := Load(strings.NewReader(deploymentTest))
+	assert.Nil(t, err)
+
+	result, err := Run(context.TODO(), tests[0])
+	assert.Nil(t, err)
+	assert.True(t, result.Passed(), "failures: %v", result.Failures)
+
+	result, err = Run(context.TODO(), tests[1])
+	assert.Nil(t, err)
+	assert.False(t, result.Passed())
+	assert.Equal(t, []string{
+		"Deployment hello: {.spec.replicas} is 1, expected 2",
+		"CronJob hello is not generated",
+	}, result.Failures)
+}
+
+func TestRunExpectedError(t *testing.T) {
+	tests, err := Load(strings.NewReader(`
+apiVersion: camel.apache.org/v1alpha1
+kind: TraitTest
+metadata:
+  name: invalid-trait
+spec:
+  integration:
+    metadata:
+      name: hello
+    spec:
+      traits:
+        container:
+          configuration:
+            port: "not-a-number"
+  error: port
+`))
+	assert.Nil(t, err)
+
+	result, err := Run(context.TODO(), tests[0])
+	assert.Nil(t, err)
+	assert.True(t, result.Passed(), "failures: %v", result.Failures)
+}
+
+func TestGenerate(t *testing.T) {
+	tests, err := Load(strings.NewReader(deploymentTest))
+	assert.Nil(t, err)
+
+	resources, err := Generate(context.TODO(), tests[0])
+	assert.Nil(t, err)
+
+	var deployment *unstructured.Unstructured
+	for i := range resources {
+		if resources[i].GetKind() == "Deployment" {
+			deployment = &resources[i]
+		}
+	}
+	assert.NotNil(t, deployment)
+	assert.Equal(t, "hello", deployment.GetName())
+	assert.Equal(t, "apps/v1", deployment.GetAPIVersion())
+	replicas, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+	assert.Equal(t, int64(3), replicas)
+}
+
+func TestEvaluate(t *testing.T) {
+	content := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"ports": []interface{}{
+				map[string]interface{}{"name": "http", "port": int64(8080)},
+				map[string]interface{}{"name": "metrics", "port": int64(9090)},
+			},
+		},
+	}
+
+	assert.Equal(t, "", evaluate(content, Assertion{Path: "{.spec.replicas}", Value: float64(2)}))
+	assert.Equal(t, "", evaluate(content, Assertion{Path: ".spec.replicas"}))
+	assert.Equal(t, "", evaluate(content, Assertion{Path: "{.spec.ports[*].name}", Value: []interface{}{"http", "metrics"}}))
+	assert.Equal(t, "{.spec.replicas} is 2, expected 3", evaluate(content, Assertion{Path: "{.spec.replicas}", Value: float64(3)}))
+	assert.Equal(t, "{.spec.missing} not found", evaluate(content, Assertion{Path: "{.spec.missing}"}))
+}
@@ -0,0 +1,178 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util"
+)
+
+const (
+	transactionTraitID    = "transaction"
+	transactionVolumeName = "transaction-log"
+
+	defaultTransactionLogPath = "/var/lib/narayana"
+	defaultTransactionLogSize = "256Mi"
+
+	// The Narayana node identifier must not be longer than 28 bytes.
+	transactionNodeNameMaxLength = 28
+)
+
+// The Transaction trait configures the Narayana JTA transaction manager, for Integrations using transacted routes
+// that span several resources, e.g., JMS brokers and databases, with XA transactions.
+//
+// The transaction logs, that the recovery manager reads to complete the in-doubt transactions after a failure,
+// are stored in a Persistent Volume Claim. The Integration is deployed as a StatefulSet, so that each replica
+// gets its own claim, and a stable name that's used as the transaction manager node identifier.
+// The name of the Integration suffixed with the ordinal of the last replica must therefore not exceed 28 characters.
+//
+// The claims are not deleted when the Integration is scaled down, or deleted, so that the pending transactions
+// can still be recovered.
+//
+// +camel-k:trait=transaction.
+type transactionTrait struct {
+	BaseTrait `property:",squash"`
+	// The path of the transaction logs directory in the Integration container (default `/var/lib/narayana`).
+	Path string `property:"path" json:"path,omitempty"`
+	// The size of the transaction logs volume (default `256Mi`).
+	Size string `property:"size" json:"size,omitempty"`
+	// The storage class of the transaction logs volume. The cluster default storage class is used if not set.
+	StorageClass string `property:"storage-class" json:"storageClass,omitempty"`
+	// Runs the periodic recovery of the in-doubt transactions (default `true`).
+	Recovery *bool `property:"recovery" json:"recovery,omitempty"`
+	// The default transaction timeout, e.g., `60s`.
+	Timeout string `property:"timeout" json:"timeout,omitempty"`
+	// Enlists the connections of the Quarkus default datasource into XA transactions (default `true`).
+	XADatasource *bool `property:"xa-datasource" json:"xaDatasource,omitempty"`
+}
+
+func newTransactionTrait() Trait {
+	return &transactionTrait{
+		// Must run before the container trait, that computes the application properties
+		BaseTrait: NewBaseTrait(transactionTraitID, 1160),
+	}
+}
+
+func (t *transactionTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if !e.IntegrationInPhase(v1.IntegrationPhaseInitialization) && !e.IntegrationInRunningPhases() {
+		return false, nil
+	}
+
+	if t.Path != "" && !path.IsAbs(t.Path) {
+		return false, fmt.Errorf("invalid transaction log path %s, it must be an absolute path", t.Path)
+	}
+	if t.Size != "" {
+		if _, err := resource.ParseQuantity(t.Size); err != nil {
+			return false, fmt.Errorf("invalid transaction log size %s: %w", t.Size, err)
+		}
+	}
+	if t.Timeout != "" {
+		if _, err := time.ParseDuration(t.Timeout); err != nil {
+			return false, fmt.Errorf("invalid transaction timeout %s: %w", t.Timeout, err)
+		}
+	}
+
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		return true, nil
+	}
+
+	strategy, err := e.DetermineControllerStrategy()
+	if err != nil {
+		return false, err
+	}
+	if strategy != ControllerStrategyDeployment {
+		return false, fmt.Errorf("the transaction trait isn't supported with %s controller strategy", strategy)
+	}
+
+	replicas := int32(1)
+	if e.Integration.Spec.Replicas != nil && *e.Integration.Spec.Replicas > 1 {
+		replicas = *e.Integration.Spec.Replicas
+	}
+	if nodeName := fmt.Sprintf("%s-%d", e.Integration.Name, replicas-1); len(nodeName) > transactionNodeNameMaxLength {
+		return false, fmt.Errorf("the transaction manager node name %s exceeds %d characters, the Integration name must be shortened",
+			nodeName, transactionNodeNameMaxLength)
+	}
+
+	return true, nil
+}
+
+func (t *transactionTrait) Apply(e *Environment) error {
+	if e.IntegrationInPhase(v1.IntegrationPhaseInitialization) {
+		util.StringSliceUniqueAdd(&e.Integration.Status.Dependencies, "camel-quarkus:jta")
+		return nil
+	}
+
+	if e.ApplicationProperties == nil {
+		e.ApplicationProperties = make(map[string]string)
+	}
+	e.ApplicationProperties["quarkus.transaction-manager.object-store-directory"] = t.path()
+	// The Pod name is stable across restarts of the StatefulSet replicas
+	e.ApplicationProperties["quarkus.transaction-manager.node-name"] = "${HOSTNAME}"
+	e.ApplicationProperties["quarkus.transaction-manager.enable-recovery"] = strconv.FormatBool(pointer.BoolDeref(t.Recovery, true))
+	if t.Timeout != "" {
+		e.ApplicationProperties["quarkus.transaction-manager.default-transaction-timeout"] = t.Timeout
+	}
+	if pointer.BoolDeref(t.XADatasource, true) {
+		e.ApplicationProperties["quarkus.datasource.jdbc.transactions"] = "xa"
+	}
+
+	// The transaction logs volume is configured once the Integration container and its controller are generated
+	e.PostProcessors = append(e.PostProcessors, t.configureVolume)
+
+	return nil
+}
+
+func (t *transactionTrait) configureVolume(e *Environment) error {
+	container := e.GetIntegrationContainer()
+	if container == nil {
+		return fmt.Errorf("unable to find integration container: %s", e.Integration.Name)
+	}
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      transactionVolumeName,
+		MountPath: t.path(),
+	})
+
+	statefulSet := replaceDeploymentWithStatefulSet(e)
+	if statefulSet == nil {
+		return fmt.Errorf("unable to find integration deployment: %s", e.Integration.Name)
+	}
+	statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates,
+		newVolumeClaimTemplate(e, transactionVolumeName, newPersistentVolumeClaimSpec(t.Size, defaultTransactionLogSize, t.StorageClass)))
+
+	return nil
+}
+
+func (t *transactionTrait) path() string {
+	if t.Path != "" {
+		return t.Path
+	}
+	return defaultTransactionLogPath
+}
@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestConfigureTransactionTraitWithInvalidOptions(t *testing.T) {
+	transactionTrait, environment := createTransactionTest(1)
+
+	transactionTrait.Timeout = "forever"
+	configured, err := transactionTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	transactionTrait.Timeout = ""
+	environment.Integration.Name = "a-very-long-integration-name"
+	configured, err = transactionTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestTransactionDependencies(t *testing.T) {
+	transactionTrait, environment := createTransactionTest(1)
+	environment.Integration.Status.Phase = v1.IntegrationPhaseInitialization
+
+	configured, err := transactionTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, transactionTrait.Apply(environment))
+	assert.Equal(t, []string{"camel-quarkus:jta"}, environment.Integration.Status.Dependencies)
+	assert.Empty(t, environment.PostProcessors)
+}
+
+func TestTransactionStatefulSet(t *testing.T) {
+	transactionTrait, environment := createTransactionTest(2)
+	transactionTrait.Recovery = pointer.Bool(false)
+	transactionTrait.Timeout = "120s"
+	transactionTrait.Size = "1Gi"
+
+	configured, err := transactionTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, transactionTrait.Apply(environment))
+	assert.Equal(t, map[string]string{
+		"quarkus.transaction-manager.object-store-directory":      defaultTransactionLogPath,
+		"quarkus.transaction-manager.node-name":                   "${HOSTNAME}",
+		"quarkus.transaction-manager.enable-recovery":             "false",
+		"quarkus.transaction-manager.default-transaction-timeout": "120s",
+		"quarkus.datasource.jdbc.transactions":                    "xa",
+	}, environment.ApplicationProperties)
+	assert.Nil(t, runPostProcessors(environment))
+
+	assert.Nil(t, environment.Resources.GetDeploymentForIntegration(environment.Integration))
+	statefulSet := environment.Resources.GetStatefulSet(func(*appsv1.StatefulSet) bool { return true })
+	assert.NotNil(t, statefulSet)
+	assert.Equal(t, int32(2), *statefulSet.Spec.Replicas)
+	assert.Len(t, statefulSet.Spec.VolumeClaimTemplates, 1)
+	assert.Equal(t, transactionVolumeName, statefulSet.Spec.VolumeClaimTemplates[0].Name)
+	assert.Equal(t, resource.MustParse("1Gi"), statefulSet.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage])
+	assert.Equal(t, corev1.VolumeMount{Name: transactionVolumeName, MountPath: defaultTransactionLogPath},
+		statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts[0])
+}
+
+func TestTransactionWithStorage(t *testing.T) {
+	transactionTrait, environment := createTransactionTest(3)
+	storageTrait, _ := newStorageTrait().(*storageTrait)
+	storageTrait.Enabled = pointer.Bool(true)
+
+	assert.Nil(t, storageTrait.Apply(environment))
+	assert.Nil(t, transactionTrait.Apply(environment))
+	assert.Nil(t, runPostProcessors(environment))
+
+	statefulSet := environment.Resources.GetStatefulSet(func(*appsv1.StatefulSet) bool { return true })
+	assert.NotNil(t, statefulSet)
+	assert.Len(t, statefulSet.Spec.VolumeClaimTemplates, 2)
+	assert.Equal(t, storageVolumeName, statefulSet.Spec.VolumeClaimTemplates[0].Name)
+	assert.Equal(t, transactionVolumeName, statefulSet.Spec.VolumeClaimTemplates[1].Name)
+	assert.Len(t, statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts, 2)
+}
+
+func createTransactionTest(replicas int32) (*transactionTrait, *Environment) {
+	_, environment := createStorageTest(replicas)
+
+	trait, _ := newTransactionTrait().(*transactionTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	return trait, environment
+}
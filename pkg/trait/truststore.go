@@ -0,0 +1,188 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util/camel"
+	utilResource "github.com/apache/camel-k/pkg/util/resource"
+)
+
+const (
+	truststoreTraitID        = "truststore"
+	truststoreContainerName  = "truststore"
+	truststoreVolumeName     = "truststore"
+	truststoreCAVolumePrefix = "truststore-ca"
+
+	truststorePassword = "changeit"
+)
+
+var (
+	truststoreMountPath   = path.Join(camel.BasePath, "truststore")
+	truststoreFile        = path.Join(truststoreMountPath, "truststore")
+	truststoreCAMountPath = path.Join(camel.BasePath, "cacerts")
+
+	// The CA certificates of the cluster, that are mounted with the service account token
+	clusterCABundles = []string{
+		"/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+		// The OpenShift service serving certificates CA
+		"/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
+	}
+)
+
+// The Truststore trait generates a Java truststore, containing the CA certificates read from ConfigMaps or Secrets,
+// so that the Integration can call services whose TLS certificates are issued by an internal CA, without building
+// a custom image.
+//
+// The CA certificates must be PEM encoded, and a single key can contain a bundle of several certificates.
+// The truststore is generated by an init container, that runs the Integration image, and the `javax.net.ssl.trustStore`
+// and `javax.net.ssl.trustStorePassword` system properties are set on the Integration JVM.
+//
+// +camel-k:trait=truststore.
+type truststoreTrait struct {
+	BaseTrait `property:",squash"`
+	// The CA certificates to add to the truststore.
+	// Syntax: [configmap|secret]:name[/key], where name represents the resource name and key optionally represents
+	// the resource key containing the certificates. All the keys of the resource are added if the key is not set.
+	CACerts []string `property:"ca-certs" json:"caCerts,omitempty"`
+	// Adds the CA certificates of the cluster, that are mounted in the Pods with the service account token,
+	// including the OpenShift service serving certificates CA (default `true`).
+	ClusterCA *bool `property:"cluster-ca" json:"clusterCA,omitempty"`
+	// Adds the CA certificates trusted by default by the JVM (default `true`).
+	DefaultCA *bool `property:"default-ca" json:"defaultCA,omitempty"`
+
+	caCerts []*utilResource.Config
+}
+
+func newTruststoreTrait() Trait {
+	return &truststoreTrait{
+		// Must run after the container trait, and before the JVM trait, that builds the container command
+		BaseTrait: NewBaseTrait(truststoreTraitID, 1630),
+	}
+}
+
+func (t *truststoreTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	t.caCerts = make([]*utilResource.Config, 0, len(t.CACerts))
+	for _, c := range t.CACerts {
+		conf, err := utilResource.ParseConfig(c)
+		if err != nil {
+			return false, err
+		}
+		if conf.StorageType() != utilResource.StorageTypeConfigmap && conf.StorageType() != utilResource.StorageTypeSecret {
+			return false, fmt.Errorf("unsupported CA certificates %s, it must be a configmap or a secret", c)
+		}
+		t.caCerts = append(t.caCerts, conf)
+	}
+
+	if len(t.caCerts) == 0 && !pointer.BoolDeref(t.ClusterCA, true) {
+		return false, fmt.Errorf("no CA certificates to add to the truststore")
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+// RequiresTraits makes sure the integration container is configured before the truststore is mounted into it.
+func (t *truststoreTrait) RequiresTraits() []ID {
+	return []ID{containerTraitID}
+}
+
+func (t *truststoreTrait) Apply(e *Environment) error {
+	container := e.GetIntegrationContainer()
+	if container == nil {
+		return fmt.Errorf("unable to find integration container: %s", e.Integration.Name)
+	}
+
+	volumes := []corev1.Volume{{
+		Name: truststoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}}
+	initContainer := corev1.Container{
+		Name:    truststoreContainerName,
+		Image:   e.Integration.Status.Image,
+		Command: []string{"/bin/sh", "-c", t.script()},
+		VolumeMounts: []corev1.VolumeMount{
+			*getMount(truststoreVolumeName, truststoreMountPath, "", false),
+		},
+	}
+	for i, conf := range t.caCerts {
+		name := truststoreCAVolumePrefix + "-" + strconv.Itoa(i)
+		volumes = append(volumes, *getVolume(name, string(conf.StorageType()), conf.Name(), conf.Key(), conf.Key()))
+		initContainer.VolumeMounts = append(initContainer.VolumeMounts,
+			*getMount(name, path.Join(truststoreCAMountPath, strconv.Itoa(i)), "", true))
+	}
+
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		spec.Volumes = append(spec.Volumes, volumes...)
+		spec.InitContainers = append(spec.InitContainers, *initContainer.DeepCopy())
+	})
+
+	container.VolumeMounts = append(container.VolumeMounts, *getMount(truststoreVolumeName, truststoreMountPath, "", true))
+	// The JVM trait appends its own options to the arguments contributed by the other traits
+	container.Args = append(container.Args,
+		"-Djavax.net.ssl.trustStore="+truststoreFile,
+		"-Djavax.net.ssl.trustStorePassword="+truststorePassword,
+	)
+
+	return nil
+}
+
+// script returns the shell script that imports the CA certificates into the truststore. The PEM bundles are split
+// into single certificates, as keytool only imports the first certificate of a file.
+func (t *truststoreTrait) script() string {
+	bundles := []string{path.Join(truststoreCAMountPath, "*", "*")}
+	if pointer.BoolDeref(t.ClusterCA, true) {
+		bundles = append(bundles, clusterCABundles...)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("set -e\n")
+	fmt.Fprintf(&sb, "certs=%s/certs\n", truststoreMountPath)
+	sb.WriteString("mkdir -p \"$certs\"\n")
+	if pointer.BoolDeref(t.DefaultCA, true) {
+		sb.WriteString("java_home=${JAVA_HOME:-$(dirname \"$(dirname \"$(readlink -f \"$(command -v java)\")\")\")}\n")
+		fmt.Fprintf(&sb, "cp \"$java_home/lib/security/cacerts\" %s\n", truststoreFile)
+		fmt.Fprintf(&sb, "chmod u+w %s\n", truststoreFile)
+	}
+	sb.WriteString("i=0\n")
+	fmt.Fprintf(&sb, "for bundle in %s; do\n", strings.Join(bundles, " "))
+	sb.WriteString("  [ -f \"$bundle\" ] || continue\n")
+	sb.WriteString("  i=$((i+1))\n")
+	sb.WriteString("  awk -v prefix=\"$certs/ca-$i-\" '/-----BEGIN CERTIFICATE-----/{n++} n{print > (prefix n \".pem\")}' \"$bundle\"\n")
+	sb.WriteString("done\n")
+	sb.WriteString("for cert in \"$certs\"/*.pem; do\n")
+	sb.WriteString("  [ -f \"$cert\" ] || continue\n")
+	fmt.Fprintf(&sb, "  keytool -importcert -noprompt -alias \"$(basename \"$cert\" .pem)\" -file \"$cert\" -keystore %s -storepass %s\n",
+		truststoreFile, truststorePassword)
+	sb.WriteString("done\n")
+	sb.WriteString("rm -rf \"$certs\"\n")
+
+	return sb.String()
+}
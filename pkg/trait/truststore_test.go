@@ -0,0 +1,97 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestConfigureTruststoreTraitWithInvalidOptions(t *testing.T) {
+	truststoreTrait, environment := createTruststoreTest()
+
+	truststoreTrait.CACerts = []string{"pvc:certs"}
+	configured, err := truststoreTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+
+	truststoreTrait.CACerts = nil
+	truststoreTrait.ClusterCA = pointer.Bool(false)
+	configured, err = truststoreTrait.Configure(environment)
+	assert.NotNil(t, err)
+	assert.False(t, configured)
+}
+
+func TestTruststore(t *testing.T) {
+	truststoreTrait, environment := createTruststoreTest()
+	truststoreTrait.CACerts = []string{"configmap:internal-ca/ca.crt", "secret:partner-ca"}
+
+	configured, err := truststoreTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, truststoreTrait.Apply(environment))
+
+	spec := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 3)
+	assert.NotNil(t, spec.Volumes[0].EmptyDir)
+	assert.Equal(t, "internal-ca", spec.Volumes[1].ConfigMap.Name)
+	assert.Equal(t, "ca.crt", spec.Volumes[1].ConfigMap.Items[0].Key)
+	assert.Equal(t, "partner-ca", spec.Volumes[2].Secret.SecretName)
+
+	assert.Len(t, spec.InitContainers, 1)
+	initContainer := spec.InitContainers[0]
+	assert.Equal(t, "integration-image", initContainer.Image)
+	assert.Len(t, initContainer.VolumeMounts, 3)
+	assert.Equal(t, "/etc/camel/cacerts/1", initContainer.VolumeMounts[2].MountPath)
+	script := initContainer.Command[2]
+	assert.Contains(t, script, "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
+	assert.Contains(t, script, "lib/security/cacerts")
+	assert.Contains(t, script, "keytool -importcert")
+
+	container := spec.Containers[0]
+	assert.Equal(t, "/etc/camel/truststore", container.VolumeMounts[0].MountPath)
+	assert.True(t, container.VolumeMounts[0].ReadOnly)
+	assert.Contains(t, container.Args, "-Djavax.net.ssl.trustStore=/etc/camel/truststore/truststore")
+}
+
+func TestTruststoreWithoutDefaultCA(t *testing.T) {
+	truststoreTrait, environment := createTruststoreTest()
+	truststoreTrait.DefaultCA = pointer.Bool(false)
+
+	configured, err := truststoreTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, truststoreTrait.Apply(environment))
+
+	spec := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec
+	assert.Len(t, spec.Volumes, 1)
+	assert.NotContains(t, spec.InitContainers[0].Command[2], "lib/security/cacerts")
+}
+
+func createTruststoreTest() (*truststoreTrait, *Environment) {
+	_, environment := createStorageTest(1)
+	environment.Integration.Status.Image = "integration-image"
+
+	trait, _ := newTruststoreTrait().(*truststoreTrait)
+	trait.Enabled = pointer.Bool(true)
+
+	return trait, environment
+}
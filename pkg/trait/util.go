@@ -28,6 +28,12 @@ import (
 	user "github.com/mitchellh/go-homedir"
 	"github.com/scylladb/go-set/strset"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
@@ -108,8 +114,44 @@ func collectConfigurations(configurationType string, configurable ...v1.Configur
 	return result
 }
 
-func collectConfigurationPairs(configurationType string, configurable ...v1.Configurable) []variable {
-	result := make([]variable, 0)
+// ConfigurationSource identifies where an environment variable, or a property, is defined.
+type ConfigurationSource string
+
+// The configuration sources, from the lowest to the highest precedence.
+const (
+	// ConfigurationSourceTrait is the source of the environment variables injected by the traits.
+	ConfigurationSourceTrait ConfigurationSource = "trait"
+	// ConfigurationSourcePlatform is the source of the IntegrationPlatform configuration.
+	ConfigurationSourcePlatform ConfigurationSource = "platform"
+	// ConfigurationSourceKit is the source of the IntegrationKit configuration.
+	ConfigurationSourceKit ConfigurationSource = "kit"
+	// ConfigurationSourceIntegration is the source of the Integration configuration.
+	ConfigurationSourceIntegration ConfigurationSource = "integration"
+	// ConfigurationSourceProfile is the source of the configuration of the active Integration environment profile.
+	ConfigurationSourceProfile ConfigurationSource = "profile"
+	// ConfigurationSourceUser is the source of the environment variables set with the environment trait,
+	// e.g., using `kamel run --env`.
+	ConfigurationSourceUser ConfigurationSource = "user"
+)
+
+func configurationSourceOf(c v1.Configurable) ConfigurationSource {
+	switch c.(type) {
+	case *v1.IntegrationPlatform:
+		return ConfigurationSourcePlatform
+	case *v1.IntegrationKit:
+		return ConfigurationSourceKit
+	case *v1.EnvironmentProfileSpec:
+		return ConfigurationSourceProfile
+	default:
+		return ConfigurationSourceIntegration
+	}
+}
+
+// collectConfigurationPairs returns the key/value pairs of the given configuration type, in order of declaration.
+// The configurables must be passed from the lowest to the highest precedence, so that a pair declared more than once
+// is resolved to the value with the highest precedence.
+func collectConfigurationPairs(configurationType string, configurable ...v1.Configurable) []Variable {
+	result := make([]Variable, 0)
 
 	for _, c := range configurable {
 		c := c
@@ -123,24 +165,14 @@ func collectConfigurationPairs(configurationType string, configurable ...v1.Conf
 			continue
 		}
 
+		source := configurationSourceOf(c)
 		for _, entry := range entries {
 			if entry.Type == configurationType {
 				k, v := property.SplitPropertyFileEntry(entry.Value)
 				if k == "" {
 					continue
 				}
-
-				ok := false
-				for i, variable := range result {
-					if variable.Name == k {
-						result[i].Value = v
-						ok = true
-						break
-					}
-				}
-				if !ok {
-					result = append(result, variable{Name: k, Value: v})
-				}
+				result = setVariable(result, Variable{Name: k, Value: v, Source: source})
 			}
 		}
 	}
@@ -148,6 +180,17 @@ func collectConfigurationPairs(configurationType string, configurable ...v1.Conf
 	return result
 }
 
+// setVariable overrides the variable with the same name, or appends it.
+func setVariable(variables []Variable, variable Variable) []Variable {
+	for i := range variables {
+		if variables[i].Name == variable.Name {
+			variables[i] = variable
+			return variables
+		}
+	}
+	return append(variables, variable)
+}
+
 var keyValuePairRegexp = regexp.MustCompile(`^(\w+)=(.+)$`)
 
 func keyValuePairArrayAsStringMap(pairs []string) (map[string]string, error) {
@@ -221,3 +264,73 @@ func AddSourceDependencies(source v1.SourceSpec, catalog *camel.RuntimeCatalog)
 
 	return dependencies
 }
+
+// replaceDeploymentWithStatefulSet returns the StatefulSet running the Integration, that replaces its Deployment,
+// for the traits requiring stable Pod identities, or a Persistent Volume Claim per replica.
+// It returns nil if the Integration is not deployed with a Deployment.
+func replaceDeploymentWithStatefulSet(e *Environment) *appsv1.StatefulSet {
+	statefulSet := e.Resources.GetStatefulSet(func(s *appsv1.StatefulSet) bool {
+		return s.Labels[v1.IntegrationLabel] == e.Integration.Name
+	})
+	if statefulSet != nil {
+		return statefulSet
+	}
+
+	deployment := e.Resources.GetDeploymentForIntegration(e.Integration)
+	if deployment == nil {
+		return nil
+	}
+	e.Resources.RemoveDeployment(func(d *appsv1.Deployment) bool {
+		return d == deployment
+	})
+
+	statefulSet = &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: *deployment.ObjectMeta.DeepCopy(),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: deployment.Spec.Replicas,
+			Selector: deployment.Spec.Selector,
+			Template: deployment.Spec.Template,
+			// The replicas do not depend on each other
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+		},
+	}
+	e.Resources.Add(statefulSet)
+
+	return statefulSet
+}
+
+func newVolumeClaimTemplate(e *Environment, name string, spec corev1.PersistentVolumeClaimSpec) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				v1.IntegrationLabel: e.Integration.Name,
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func newPersistentVolumeClaimSpec(size string, defaultSize string, storageClass string) corev1.PersistentVolumeClaimSpec {
+	if size == "" {
+		size = defaultSize
+	}
+
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(size),
+			},
+		},
+	}
+	if storageClass != "" {
+		spec.StorageClassName = pointer.String(storageClass)
+	}
+
+	return spec
+}
@@ -0,0 +1,189 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	waitForTraitID       = "wait-for"
+	waitForContainerName = "wait-for"
+
+	defaultWaitForImage    = "docker.io/library/busybox:1.35"
+	defaultWaitForTimeout  = 5 * time.Minute
+	defaultWaitForInterval = 2 * time.Second
+)
+
+// waitForScript checks the endpoints in turn, until they are all reachable or the timeout expires.
+// The unreachable endpoint is written into the termination message of the container, so that it's reported
+// into the Integration status.
+const waitForScript = `deadline=$(($(date +%s) + WAIT_FOR_TIMEOUT))
+check() {
+  case "$1" in
+    http://*|https://*) wget -q -T 5 -O /dev/null "$1" ;;
+    *) nc -z -w 5 "${1%:*}" "${1##*:}" ;;
+  esac
+}
+for endpoint in $WAIT_FOR_ENDPOINTS; do
+  until check "$endpoint"; do
+    if [ "$(date +%s)" -ge "$deadline" ]; then
+      echo "$endpoint is unreachable after ${WAIT_FOR_TIMEOUT}s" | tee /dev/termination-log
+      exit 1
+    fi
+    echo "waiting for $endpoint"
+    sleep "$WAIT_FOR_INTERVAL"
+  done
+  echo "$endpoint is reachable"
+done
+`
+
+// The Wait For trait delays the start of the Integration until the external services it depends on,
+// like a message broker, a database, or an HTTP API, are reachable.
+//
+// The endpoints are checked by an init container, that runs before any other init container, so that the Integration
+// does not crash loop while the infrastructure is warming up. TCP endpoints are reachable once a connection can be
+// opened, and HTTP endpoints once they respond with a successful status code. When the endpoints are still unreachable
+// after the timeout, the init container fails and the Integration is reported in error, with the unreachable endpoint
+// in the `DependenciesReachable` condition.
+//
+// +camel-k:trait=wait-for.
+type waitForTrait struct {
+	BaseTrait `property:",squash"`
+	// The TCP endpoints to check, with the `host:port` format, e.g., `my-broker:61616`.
+	TCP []string `property:"tcp" json:"tcp,omitempty"`
+	// The HTTP endpoints to check, e.g., `http://my-api:8080/health`.
+	HTTP []string `property:"http" json:"http,omitempty"`
+	// How long to wait for the endpoints to be reachable, e.g., `10m` (default `5m`).
+	Timeout string `property:"timeout" json:"timeout,omitempty"`
+	// The delay between two checks of an unreachable endpoint (default `2s`).
+	Interval string `property:"interval" json:"interval,omitempty"`
+	// The image of the init container, that must provide the `nc` and `wget` commands (default `busybox`).
+	Image string `property:"image" json:"image,omitempty"`
+}
+
+func newWaitForTrait() Trait {
+	return &waitForTrait{
+		// Must run after the container trait, that creates the Integration Pod template
+		BaseTrait: NewBaseTrait(waitForTraitID, 1615),
+	}
+}
+
+func (t *waitForTrait) Configure(e *Environment) (bool, error) {
+	if !pointer.BoolDeref(t.Enabled, false) {
+		return false, nil
+	}
+
+	if len(t.TCP) == 0 && len(t.HTTP) == 0 {
+		return false, fmt.Errorf("at least one TCP or HTTP endpoint is required")
+	}
+	for _, endpoint := range t.TCP {
+		if _, port, err := net.SplitHostPort(endpoint); err != nil || port == "" {
+			return false, fmt.Errorf("invalid TCP endpoint %q, expected host:port", endpoint)
+		}
+	}
+	for _, endpoint := range t.HTTP {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return false, fmt.Errorf("invalid HTTP endpoint %q, expected an http or https URL", endpoint)
+		}
+	}
+	if _, err := t.timeout(); err != nil {
+		return false, err
+	}
+	if _, err := t.interval(); err != nil {
+		return false, err
+	}
+
+	return e.IntegrationInRunningPhases(), nil
+}
+
+func (t *waitForTrait) Apply(e *Environment) error {
+	container, err := t.getContainer()
+	if err != nil {
+		return err
+	}
+
+	e.Resources.VisitPodSpec(func(spec *corev1.PodSpec) {
+		// The dependencies must be reachable before the other init containers run, e.g., the database migrations
+		spec.InitContainers = append([]corev1.Container{*container.DeepCopy()}, spec.InitContainers...)
+	})
+
+	return nil
+}
+
+func (t *waitForTrait) getContainer() (corev1.Container, error) {
+	timeout, err := t.timeout()
+	if err != nil {
+		return corev1.Container{}, err
+	}
+	interval, err := t.interval()
+	if err != nil {
+		return corev1.Container{}, err
+	}
+
+	image := t.Image
+	if image == "" {
+		image = defaultWaitForImage
+	}
+
+	endpoints := make([]string, 0, len(t.TCP)+len(t.HTTP))
+	endpoints = append(endpoints, t.TCP...)
+	endpoints = append(endpoints, t.HTTP...)
+
+	return corev1.Container{
+		Name:            waitForContainerName,
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/bin/sh", "-c", waitForScript},
+		Env: []corev1.EnvVar{
+			{Name: "WAIT_FOR_ENDPOINTS", Value: strings.Join(endpoints, " ")},
+			{Name: "WAIT_FOR_TIMEOUT", Value: strconv.Itoa(int(timeout.Seconds()))},
+			{Name: "WAIT_FOR_INTERVAL", Value: strconv.Itoa(int(interval.Seconds()))},
+		},
+	}, nil
+}
+
+func (t *waitForTrait) timeout() (time.Duration, error) {
+	return parseWaitForDuration("timeout", t.Timeout, defaultWaitForTimeout)
+}
+
+func (t *waitForTrait) interval() (time.Duration, error) {
+	return parseWaitForDuration("interval", t.Interval, defaultWaitForInterval)
+}
+
+func parseWaitForDuration(name string, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	if d < time.Second {
+		return 0, fmt.Errorf("invalid %s %q: must be at least 1s", name, value)
+	}
+	return d, nil
+}
@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trait
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/utils/pointer"
+
+	"github.com/apache/camel-k/pkg/util/envvar"
+)
+
+func TestConfigureWaitForTraitWithInvalidOptions(t *testing.T) {
+	waitForTrait, environment := createWaitForTest()
+
+	waitForTrait.TCP = nil
+	configured, err := waitForTrait.Configure(environment)
+	assert.EqualError(t, err, "at least one TCP or HTTP endpoint is required")
+	assert.False(t, configured)
+
+	waitForTrait.TCP = []string{"my-broker"}
+	configured, err = waitForTrait.Configure(environment)
+	assert.EqualError(t, err, `invalid TCP endpoint "my-broker", expected host:port`)
+	assert.False(t, configured)
+
+	waitForTrait.TCP = nil
+	waitForTrait.HTTP = []string{"my-api:8080/health"}
+	configured, err = waitForTrait.Configure(environment)
+	assert.EqualError(t, err, `invalid HTTP endpoint "my-api:8080/health", expected an http or https URL`)
+	assert.False(t, configured)
+
+	waitForTrait.HTTP = []string{"http://my-api:8080/health"}
+	waitForTrait.Interval = "500ms"
+	configured, err = waitForTrait.Configure(environment)
+	assert.EqualError(t, err, `invalid interval "500ms": must be at least 1s`)
+	assert.False(t, configured)
+}
+
+func TestWaitFor(t *testing.T) {
+	waitForTrait, environment := createWaitForTest()
+	waitForTrait.HTTP = []string{"http://my-api:8080/health"}
+	waitForTrait.Timeout = "10m"
+
+	migrationTrait, _ := newMigrationTrait().(*migrationTrait)
+	migrationTrait.URL = "jdbc:postgresql://postgres:5432/orders"
+	migrationTrait.ConfigMaps = []string{"migrations"}
+	assert.Nil(t, migrationTrait.Apply(environment))
+
+	configured, err := waitForTrait.Configure(environment)
+	assert.Nil(t, err)
+	assert.True(t, configured)
+	assert.Nil(t, waitForTrait.Apply(environment))
+
+	spec := environment.Resources.GetDeploymentForIntegration(environment.Integration).Spec.Template.Spec
+	assert.Len(t, spec.InitContainers, 2)
+	assert.Equal(t, migrationContainerName, spec.InitContainers[1].Name)
+
+	container := spec.InitContainers[0]
+	assert.Equal(t, waitForContainerName, container.Name)
+	assert.Equal(t, defaultWaitForImage, container.Image)
+	assert.Equal(t, "my-broker:61616 postgres:5432 http://my-api:8080/health", envvar.Get(container.Env, "WAIT_FOR_ENDPOINTS").Value)
+	assert.Equal(t, "600", envvar.Get(container.Env, "WAIT_FOR_TIMEOUT").Value)
+	assert.Equal(t, "2", envvar.Get(container.Env, "WAIT_FOR_INTERVAL").Value)
+}
+
+func createWaitForTest() (*waitForTrait, *Environment) {
+	_, environment := createStorageTest(1)
+
+	trait, _ := newWaitForTrait().(*waitForTrait)
+	trait.Enabled = pointer.Bool(true)
+	trait.TCP = []string{"my-broker:61616", "postgres:5432"}
+
+	return trait, environment
+}
@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildcache
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewConfigMapStore returns a store that persists the records in the given ConfigMap, created on the first record.
+func NewConfigMapStore(reader ctrl.Reader, writer ctrl.Writer, key ctrl.ObjectKey) Store {
+	return &configMapStore{
+		reader: reader,
+		writer: writer,
+		key:    key,
+	}
+}
+
+type configMapStore struct {
+	reader ctrl.Reader
+	writer ctrl.Writer
+	key    ctrl.ObjectKey
+}
+
+func (s *configMapStore) Get(ctx context.Context, key string) (*Record, error) {
+	cm := corev1.ConfigMap{}
+	if err := s.reader.Get(ctx, s.key, &cm); err != nil && k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	data, ok := cm.Data[key]
+	if !ok {
+		return nil, nil
+	}
+	record := Record{}
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *configMapStore) Put(ctx context.Context, key string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.update(ctx, true, func(records map[string]string) {
+		records[key] = string(data)
+	})
+}
+
+func (s *configMapStore) Delete(ctx context.Context, key string) error {
+	return s.update(ctx, false, func(records map[string]string) {
+		delete(records, key)
+	})
+}
+
+// update applies the mutation to the latest ConfigMap, retrying on the conflicts with the other operators,
+// and creates the ConfigMap if it does not exist and create is set.
+func (s *configMapStore) update(ctx context.Context, create bool, mutate func(records map[string]string)) error {
+	retriable := func(err error) bool {
+		return k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err)
+	}
+	return retry.OnError(retry.DefaultRetry, retriable, func() error {
+		cm := corev1.ConfigMap{}
+		if err := s.reader.Get(ctx, s.key, &cm); err != nil && k8serrors.IsNotFound(err) {
+			if !create {
+				return nil
+			}
+			cm = corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "ConfigMap",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: s.key.Namespace,
+					Name:      s.key.Name,
+					Labels: map[string]string{
+						"app": "camel-k",
+					},
+				},
+				Data: map[string]string{},
+			}
+			mutate(cm.Data)
+			return s.writer.Create(ctx, &cm)
+		} else if err != nil {
+			return err
+		}
+
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		mutate(cm.Data)
+		return s.writer.Update(ctx, &cm)
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package buildcache persists the metadata of the builds run by the operator, so that it survives the operator
+// restarts, and is shared by the operator replicas.
+package buildcache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// StoreEnvVariable is the environment variable selecting the backend of the operator build cache store,
+	// i.e., memory (the default) or configmap.
+	StoreEnvVariable = "KAMEL_BUILD_CACHE_STORE"
+	// StoreMemory keeps the build cache metadata in the operator memory, so that it's lost when the operator restarts.
+	StoreMemory = "memory"
+	// StoreConfigMap persists the build cache metadata in a ConfigMap of the operator namespace.
+	StoreConfigMap = "configmap"
+	// ConfigMapName is the name of the ConfigMap of the configmap store.
+	ConfigMapName = "camel-k-build-cache"
+)
+
+// Record is the metadata of a build routine run by an operator.
+type Record struct {
+	// the name of the operator Pod running the build
+	Holder string `json:"holder"`
+	// the time the operator has started running the build
+	StartedAt metav1.Time `json:"startedAt"`
+}
+
+// Store persists the build records, keyed by build.
+type Store interface {
+	// Get returns the record of the build, or nil if none exists
+	Get(ctx context.Context, key string) (*Record, error)
+	// Put creates or replaces the record of the build
+	Put(ctx context.Context, key string, record Record) error
+	// Delete removes the record of the build, if any
+	Delete(ctx context.Context, key string) error
+}
+
+// Key returns the key of the build record, that is valid as a ConfigMap key.
+func Key(namespace string, name string) string {
+	return namespace + "." + name
+}
+
+// Kind returns the backend of the store configured for the operator.
+func Kind() string {
+	if kind, ok := os.LookupEnv(StoreEnvVariable); ok && strings.TrimSpace(kind) != "" {
+		return strings.ToLower(strings.TrimSpace(kind))
+	}
+	return StoreMemory
+}
+
+// NewStore returns the store of the given backend. The configmap store reads the ConfigMap with the given reader,
+// that must not be a cached one, so that the concurrent updates are detected.
+func NewStore(kind string, reader ctrl.Reader, writer ctrl.Writer, namespace string) (Store, error) {
+	switch kind {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StoreConfigMap:
+		if namespace == "" {
+			return nil, fmt.Errorf("the %s build cache store requires the operator namespace", kind)
+		}
+		return NewConfigMapStore(reader, writer, ctrl.ObjectKey{Namespace: namespace, Name: ConfigMapName}), nil
+	default:
+		return nil, fmt.Errorf("unsupported build cache store %s, expected %s or %s", kind, StoreMemory, StoreConfigMap)
+	}
+}
+
+// NewMemoryStore returns a store that keeps the records in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+type memoryStore struct {
+	records sync.Map
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (*Record, error) {
+	if record, ok := s.records.Load(key); ok {
+		r := record.(Record)
+		return &r, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Put(_ context.Context, key string, record Record) error {
+	s.records.Store(key, record)
+	return nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.records.Delete(key)
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildcache
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apache/camel-k/pkg/util/test"
+)
+
+func TestKind(t *testing.T) {
+	os.Unsetenv(StoreEnvVariable)
+	assert.Equal(t, StoreMemory, Kind())
+
+	os.Setenv(StoreEnvVariable, " ConfigMap ")
+	defer os.Unsetenv(StoreEnvVariable)
+	assert.Equal(t, StoreConfigMap, Kind())
+}
+
+func TestNewStore(t *testing.T) {
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+
+	store, err := NewStore(StoreMemory, c, c, "")
+	assert.Nil(t, err)
+	assert.IsType(t, &memoryStore{}, store)
+
+	store, err = NewStore(StoreConfigMap, c, c, "operator")
+	assert.Nil(t, err)
+	assert.IsType(t, &configMapStore{}, store)
+
+	_, err = NewStore(StoreConfigMap, c, c, "")
+	assert.NotNil(t, err)
+
+	_, err = NewStore("redis", c, c, "operator")
+	assert.EqualError(t, err, "unsupported build cache store redis, expected memory or configmap")
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestConfigMapStore(t *testing.T) {
+	c, err := test.NewFakeClient()
+	assert.Nil(t, err)
+	key := ctrl.ObjectKey{Namespace: "operator", Name: ConfigMapName}
+
+	// Deleting a record does not create the ConfigMap
+	store := NewConfigMapStore(c, c, key)
+	assert.Nil(t, store.Delete(context.TODO(), Key("ns", "kit-1")))
+	cm := corev1.ConfigMap{}
+	assert.NotNil(t, c.Get(context.TODO(), key, &cm))
+
+	testStore(t, store)
+
+	assert.Nil(t, c.Get(context.TODO(), key, &cm))
+	assert.Equal(t, "camel-k", cm.Labels["app"])
+
+	// The records are shared by the stores of the restarted operators
+	assert.Nil(t, store.Put(context.TODO(), Key("ns", "kit-2"), Record{Holder: "camel-k-operator-1"}))
+	record, err := NewConfigMapStore(c, c, key).Get(context.TODO(), Key("ns", "kit-2"))
+	assert.Nil(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, "camel-k-operator-1", record.Holder)
+}
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+
+	record, err := store.Get(context.TODO(), Key("ns", "kit-1"))
+	assert.Nil(t, err)
+	assert.Nil(t, record)
+
+	startedAt := metav1.Unix(1000, 0)
+	assert.Nil(t, store.Put(context.TODO(), Key("ns", "kit-1"), Record{Holder: "camel-k-operator-1", StartedAt: startedAt}))
+	assert.Nil(t, store.Put(context.TODO(), Key("other", "kit-1"), Record{Holder: "camel-k-operator-1", StartedAt: startedAt}))
+	assert.Nil(t, store.Put(context.TODO(), Key("ns", "kit-1"), Record{Holder: "camel-k-operator-2", StartedAt: startedAt}))
+
+	record, err = store.Get(context.TODO(), Key("ns", "kit-1"))
+	assert.Nil(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, "camel-k-operator-2", record.Holder)
+	assert.True(t, startedAt.Equal(&record.StartedAt))
+
+	assert.Nil(t, store.Delete(context.TODO(), Key("ns", "kit-1")))
+	record, err = store.Get(context.TODO(), Key("ns", "kit-1"))
+	assert.Nil(t, err)
+	assert.Nil(t, record)
+
+	record, err = store.Get(context.TODO(), Key("other", "kit-1"))
+	assert.Nil(t, err)
+	assert.NotNil(t, record)
+}
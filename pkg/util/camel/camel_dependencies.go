@@ -26,6 +26,7 @@ import (
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/jitpack"
 	"github.com/apache/camel-k/pkg/util/maven"
+	"github.com/apache/camel-k/pkg/util/upload"
 	"github.com/rs/xid"
 )
 
@@ -129,6 +130,8 @@ func addDependencies(project *maven.Project, dependencies []string, catalog *Run
 				},
 			}
 			plugin.Executions = append(plugin.Executions, exec)
+		case strings.HasPrefix(d, upload.DependencyPrefix):
+			// The uploaded files are copied into the image context by the builder
 		default:
 			if dep := jitpack.ToDependency(d); dep != nil {
 				project.AddDependency(*dep)
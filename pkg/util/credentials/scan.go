@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentials detects the credentials that are likely hard-coded into the Integration sources,
+// rather than being retrieved from Secrets with property placeholders.
+package credentials
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Finding is a likely inline credential. It purposely does not hold the credential itself, so that it can be
+// reported, e.g., into the Integration status, without leaking it.
+type Finding struct {
+	// the line, starting from 1, the credential has been found at
+	Line int
+	// the kind of credential found
+	Kind string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s at line %d", f.Kind, f.Line)
+}
+
+type rule struct {
+	kind string
+	// the expression matching the credential, whose value is captured by the first group
+	expression *regexp.Regexp
+}
+
+var rules = []rule{
+	{
+		kind:       "password in URI user info",
+		expression: regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@'"?#]+:([^/\s@'"?#]+)@`),
+	},
+	{
+		kind:       "password in URI query",
+		expression: regexp.MustCompile(`(?i)[?&](?:password|passwd|pwd)=([^&\s'"]+)`),
+	},
+	{
+		kind:       "AWS access key ID",
+		expression: regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`),
+	},
+	{
+		kind:       "AWS secret access key",
+		expression: regexp.MustCompile(`(?i)(?:secret.?key|secret.?access.?key)['"]?\s*[=:]\s*['"]?(?:RAW\()?([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+]|$)`),
+	},
+}
+
+// Scan returns the likely inline credentials found in the given content, ordered by line.
+// The values resolved at runtime, i.e., property placeholders and bean references, are not reported.
+func Scan(content string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(content, "\n") {
+		for _, r := range rules {
+			for _, match := range r.expression.FindAllStringSubmatch(line, -1) {
+				if isResolved(match[1]) {
+					continue
+				}
+				findings = append(findings, Finding{Line: i + 1, Kind: r.kind})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// isResolved returns whether the value is resolved at runtime, rather than being the credential itself.
+func isResolved(value string) bool {
+	value = strings.TrimPrefix(value, "RAW(")
+	return strings.Contains(value, "{{") || strings.Contains(value, "${") || strings.HasPrefix(value, "#")
+}
@@ -31,6 +31,8 @@ import (
 	"strconv"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util"
 	"github.com/apache/camel-k/pkg/util/defaults"
@@ -51,6 +53,9 @@ func ComputeForIntegration(integration *v1.Integration) (string, error) {
 			return "", err
 		}
 	}
+	if _, err := hash.Write([]byte(integration.Spec.IntegrationKitDigest)); err != nil {
+		return "", err
+	}
 	// Profile is relevant
 	if _, err := hash.Write([]byte(integration.Spec.Profile)); err != nil {
 		return "", err
@@ -129,6 +134,22 @@ func ComputeForIntegration(integration *v1.Integration) (string, error) {
 		}
 	}
 
+	// Integration environment profiles
+	if len(integration.Spec.Profiles) > 0 {
+		profiles, err := json.Marshal(integration.Spec.Profiles)
+		if err != nil {
+			return "", err
+		}
+		if _, err := hash.Write(profiles); err != nil {
+			return "", err
+		}
+	}
+	if profile, ok := integration.Annotations[v1.EnvironmentProfileAnnotation]; ok {
+		if _, err := hash.Write([]byte(fmt.Sprintf("%s=%v,", v1.EnvironmentProfileAnnotation, profile))); err != nil {
+			return "", err
+		}
+	}
+
 	// Add a letter at the beginning and use URL safe encoding
 	digest := "v" + base64.RawURLEncoding.EncodeToString(hash.Sum(nil))
 	return digest, nil
@@ -232,6 +253,65 @@ func ComputeForSource(s v1.SourceSpec) (string, error) {
 	return digest, nil
 }
 
+// ComputeForConfigMapsAndSecrets returns a digest of the content of the given configmaps and secrets.
+func ComputeForConfigMapsAndSecrets(configmaps []corev1.ConfigMap, secrets []corev1.Secret) (string, error) {
+	hash := sha256.New()
+
+	for _, cm := range configmaps {
+		if _, err := hash.Write([]byte("configmap:" + cm.Name)); err != nil {
+			return "", err
+		}
+		for _, k := range sortedStringMapKeys(cm.Data) {
+			if _, err := hash.Write([]byte(k + "=" + cm.Data[k])); err != nil {
+				return "", err
+			}
+		}
+		for _, k := range sortedBytesMapKeys(cm.BinaryData) {
+			if _, err := hash.Write([]byte(k + "=")); err != nil {
+				return "", err
+			}
+			if _, err := hash.Write(cm.BinaryData[k]); err != nil {
+				return "", err
+			}
+		}
+	}
+	for _, secret := range secrets {
+		if _, err := hash.Write([]byte("secret:" + secret.Name)); err != nil {
+			return "", err
+		}
+		for _, k := range sortedBytesMapKeys(secret.Data) {
+			if _, err := hash.Write([]byte(k + "=")); err != nil {
+				return "", err
+			}
+			if _, err := hash.Write(secret.Data[k]); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Add a letter at the beginning and use URL safe encoding
+	digest := "v" + base64.RawURLEncoding.EncodeToString(hash.Sum(nil))
+	return digest, nil
+}
+
+func sortedStringMapKeys(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func sortedBytesMapKeys(m map[string][]byte) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
 func sortedTraitSpecMapKeys(m map[string]v1.TraitSpec) []string {
 	res := make([]string, len(m))
 	i := 0
@@ -47,6 +47,31 @@ func TestDigestUsesAnnotations(t *testing.T) {
 	assert.NotEqual(t, digest1, digest3)
 }
 
+func TestDigestUsesEnvironmentProfiles(t *testing.T) {
+	it := v1.Integration{}
+	digest1, err := ComputeForIntegration(&it)
+	assert.NoError(t, err)
+
+	it.Spec.Profiles = []v1.EnvironmentProfileSpec{
+		{
+			Name: "prod",
+			Configuration: []v1.ConfigurationSpec{
+				{Type: "property", Value: "my.property=prod"},
+			},
+		},
+	}
+	digest2, err := ComputeForIntegration(&it)
+	assert.NoError(t, err)
+	assert.NotEqual(t, digest1, digest2)
+
+	it.Annotations = map[string]string{
+		v1.EnvironmentProfileAnnotation: "prod",
+	}
+	digest3, err := ComputeForIntegration(&it)
+	assert.NoError(t, err)
+	assert.NotEqual(t, digest2, digest3)
+}
+
 func TestDigestSHA1FromTempFile(t *testing.T) {
 	var tmpFile *os.File
 	var err error
@@ -74,3 +74,41 @@ func ToYamlDSL(flows []v1.Flow) ([]byte, error) {
 
 	return yamldata, nil
 }
+
+// WithRouteIDs returns a copy of the flows, where the routes that have no id are given one, made of the given prefix
+// and of the position of the route in the flows, so that it does not change from one deployment to the other.
+func WithRouteIDs(flows []v1.Flow, prefix string) ([]v1.Flow, error) {
+	result := make([]v1.Flow, 0, len(flows))
+	index := 0
+	for _, flow := range flows {
+		element := make(map[string]interface{})
+		if err := json.Unmarshal(flow.RawMessage, &element); err != nil {
+			return nil, err
+		}
+
+		if from, ok := element["from"].(map[string]interface{}); ok {
+			index++
+			if _, ok := from["id"]; !ok {
+				element = map[string]interface{}{
+					"route": map[string]interface{}{
+						"id":   fmt.Sprintf("%s-%d", prefix, index),
+						"from": from,
+					},
+				}
+			}
+		} else if route, ok := element["route"].(map[string]interface{}); ok {
+			index++
+			if _, ok := route["id"]; !ok {
+				route["id"] = fmt.Sprintf("%s-%d", prefix, index)
+			}
+		}
+
+		data, err := json.Marshal(element)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v1.Flow{RawMessage: data})
+	}
+
+	return result, nil
+}
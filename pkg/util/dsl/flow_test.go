@@ -51,3 +51,57 @@ func TestReadWriteYaml(t *testing.T) {
 	assert.NotNil(t, data)
 	assert.Equal(t, yaml, string(data))
 }
+
+func TestWithRouteIDs(t *testing.T) {
+	flows, err := FromYamlDSLString(`
+- from:
+    uri: timer:tick
+    steps:
+    - to: log:info
+- route:
+    from:
+      uri: timer:tock
+- route:
+    id: my-route
+    from:
+      uri: timer:tack
+- from:
+    id: my-other-route
+    uri: timer:tuck
+- rest:
+    path: /api
+- from:
+    uri: timer:tyck
+`)
+	assert.NoError(t, err)
+
+	flows, err = WithRouteIDs(flows, "my-integration")
+	assert.NoError(t, err)
+
+	data, err := ToYamlDSL(flows)
+	assert.NoError(t, err)
+	assert.Equal(t, `- route:
+    from:
+      steps:
+      - to: log:info
+      uri: timer:tick
+    id: my-integration-1
+- route:
+    from:
+      uri: timer:tock
+    id: my-integration-2
+- route:
+    from:
+      uri: timer:tack
+    id: my-route
+- from:
+    id: my-other-route
+    uri: timer:tuck
+- rest:
+    path: /api
+- route:
+    from:
+      uri: timer:tyck
+    id: my-integration-5
+`, string(data))
+}
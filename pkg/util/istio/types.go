@@ -0,0 +1,130 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package istio contains the subset of the Istio API used by the operator,
+// so that the Istio resources can be generated without depending on the Istio client.
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// NetworkingGroupVersion is the group version of the Istio traffic management API.
+	NetworkingGroupVersion = schema.GroupVersion{Group: "networking.istio.io", Version: "v1beta1"}
+	// SecurityGroupVersion is the group version of the Istio security API.
+	SecurityGroupVersion = schema.GroupVersion{Group: "security.istio.io", Version: "v1beta1"}
+)
+
+// +kubebuilder:object:root=true
+
+// VirtualService defines the traffic routing rules applied when a host is addressed.
+type VirtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualServiceSpec `json:"spec"`
+}
+
+// VirtualServiceSpec is the spec for a VirtualService resource.
+type VirtualServiceSpec struct {
+	Hosts []string    `json:"hosts,omitempty"`
+	HTTP  []HTTPRoute `json:"http,omitempty"`
+}
+
+// HTTPRoute describes the destinations the HTTP traffic is routed to.
+type HTTPRoute struct {
+	Route []HTTPRouteDestination `json:"route,omitempty"`
+}
+
+// HTTPRouteDestination is a weighted destination of a route.
+type HTTPRouteDestination struct {
+	Destination Destination `json:"destination"`
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// Destination identifies the service the traffic is forwarded to.
+type Destination struct {
+	Host string `json:"host"`
+	// +optional
+	Subset string `json:"subset,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DestinationRule defines the policies applied to the traffic intended for a host, once routed.
+type DestinationRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DestinationRuleSpec `json:"spec"`
+}
+
+// DestinationRuleSpec is the spec for a DestinationRule resource.
+type DestinationRuleSpec struct {
+	Host string `json:"host"`
+	// +optional
+	TrafficPolicy *TrafficPolicy `json:"trafficPolicy,omitempty"`
+}
+
+// TrafficPolicy describes the load balancing and TLS policies of a destination.
+type TrafficPolicy struct {
+	// +optional
+	LoadBalancer *LoadBalancerSettings `json:"loadBalancer,omitempty"`
+	// +optional
+	TLS *ClientTLSSettings `json:"tls,omitempty"`
+}
+
+// LoadBalancerSettings describes the load balancing algorithm of a destination.
+type LoadBalancerSettings struct {
+	Simple string `json:"simple,omitempty"`
+}
+
+// ClientTLSSettings describes the TLS settings of the connections to a destination.
+type ClientTLSSettings struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PeerAuthentication defines how the traffic is tunneled, or not, to the sidecars of the selected workloads.
+type PeerAuthentication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PeerAuthenticationSpec `json:"spec"`
+}
+
+// PeerAuthenticationSpec is the spec for a PeerAuthentication resource.
+type PeerAuthenticationSpec struct {
+	// +optional
+	Selector *WorkloadSelector `json:"selector,omitempty"`
+	// +optional
+	MTLS *PeerAuthenticationMutualTLS `json:"mtls,omitempty"`
+}
+
+// WorkloadSelector selects the workloads a policy applies to.
+type WorkloadSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// PeerAuthenticationMutualTLS describes the mutual TLS mode of the selected workloads.
+type PeerAuthenticationMutualTLS struct {
+	Mode string `json:"mode,omitempty"`
+}
@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	VirtualServiceKind     = "VirtualService"
+	DestinationRuleKind    = "DestinationRule"
+	PeerAuthenticationKind = "PeerAuthentication"
+)
+
+func NewVirtualService(namespace string, name string) VirtualService {
+	return VirtualService{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: NetworkingGroupVersion.String(),
+			Kind:       VirtualServiceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+}
+
+func NewDestinationRule(namespace string, name string) DestinationRule {
+	return DestinationRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: NetworkingGroupVersion.String(),
+			Kind:       DestinationRuleKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+}
+
+func NewPeerAuthentication(namespace string, name string) PeerAuthentication {
+	return PeerAuthentication{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SecurityGroupVersion.String(),
+			Kind:       PeerAuthenticationKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+}
@@ -20,12 +20,15 @@ package knative
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"net/url"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 
@@ -38,11 +41,15 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/tracker"
 	serving "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 
 	"github.com/apache/camel-k/pkg/client"
 	util "github.com/apache/camel-k/pkg/util/kubernetes"
 )
 
+// VisibilityLabel labels the Knative Services that are only exposed on the cluster network, with the `cluster-local` value.
+const VisibilityLabel = "networking.knative.dev/visibility"
+
 func CreateSubscription(channelReference corev1.ObjectReference, serviceName string, path string) *messaging.Subscription {
 	return &messaging.Subscription{
 		TypeMeta: metav1.TypeMeta{
@@ -73,14 +80,27 @@ func CreateSubscription(channelReference corev1.ObjectReference, serviceName str
 	}
 }
 
-func CreateTrigger(brokerReference corev1.ObjectReference, serviceName string, eventType string, path string) *eventing.Trigger {
+// CreateTrigger returns a Trigger subscribing the service to the events of the Broker of the given type, if any, and
+// matching the given CloudEvents attributes. The Triggers filtering on other attributes than the type are suffixed with
+// a hash of these attributes, so that the Triggers of the same type don't clash.
+func CreateTrigger(brokerReference corev1.ObjectReference, serviceName string, eventType string, filters map[string]string, path string) *eventing.Trigger {
+	attributes := make(map[string]string, len(filters)+1)
+	for k, v := range filters {
+		attributes[k] = v
+	}
+	if eventType != "" {
+		delete(attributes, "type")
+	}
 	nameSuffix := ""
-	var attributes map[string]string
+	if len(attributes) > 0 {
+		nameSuffix = "-" + attributesHash(attributes)
+	}
 	if eventType != "" {
-		nameSuffix = fmt.Sprintf("-%s", util.SanitizeLabel(eventType))
-		attributes = map[string]string{
-			"type": eventType,
-		}
+		nameSuffix = fmt.Sprintf("-%s", util.SanitizeLabel(eventType)) + nameSuffix
+		attributes["type"] = eventType
+	}
+	if len(attributes) == 0 {
+		attributes = nil
 	}
 	return &eventing.Trigger{
 		TypeMeta: metav1.TypeMeta{
@@ -110,6 +130,46 @@ func CreateTrigger(brokerReference corev1.ObjectReference, serviceName string, e
 	}
 }
 
+func attributesHash(attributes map[string]string) string {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := fnv.New32a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, attributes[k])
+	}
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// WithSQLFilter returns the Trigger, with its events also filtered by the given CloudEvents SQL expression. The
+// expression is set in the `filters` field of the Trigger, along with its attribute filters, as Knative ignores the
+// `filter` field when the `filters` one is set. The field requires the `new-trigger-filters` feature of Knative
+// Eventing, and is not part of the API the operator is built against, hence the Trigger is returned unstructured.
+func WithSQLFilter(trigger *eventing.Trigger, expression string) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(trigger)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+
+	filters := make([]interface{}, 0, 2)
+	if trigger.Spec.Filter != nil && len(trigger.Spec.Filter.Attributes) > 0 {
+		exact := make(map[string]interface{}, len(trigger.Spec.Filter.Attributes))
+		for k, v := range trigger.Spec.Filter.Attributes {
+			exact[k] = v
+		}
+		filters = append(filters, map[string]interface{}{"exact": exact})
+	}
+	filters = append(filters, map[string]interface{}{"cesql": expression})
+	if err := unstructured.SetNestedSlice(u.Object, filters, "spec", "filters"); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
 func CreateSinkBinding(source corev1.ObjectReference, target corev1.ObjectReference) *sources.SinkBinding {
 	return &sources.SinkBinding{
 		TypeMeta: metav1.TypeMeta{
@@ -141,6 +201,28 @@ func CreateSinkBinding(source corev1.ObjectReference, target corev1.ObjectRefere
 	}
 }
 
+// CreateDomainMapping returns a DomainMapping that exposes the Knative Service on the given domain.
+func CreateDomainMapping(namespace string, domain string, serviceName string) *servingv1beta1.DomainMapping {
+	return &servingv1beta1.DomainMapping{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: servingv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DomainMapping",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      domain,
+		},
+		Spec: servingv1beta1.DomainMappingSpec{
+			Ref: duckv1.KReference{
+				APIVersion: serving.SchemeGroupVersion.String(),
+				Kind:       "Service",
+				Namespace:  namespace,
+				Name:       serviceName,
+			},
+		},
+	}
+}
+
 // GetAddressableReference looks up the resource among all given types and returns an object reference to it.
 func GetAddressableReference(ctx context.Context, c client.Client,
 	possibleReferences []corev1.ObjectReference, namespace string, name string) (*corev1.ObjectReference, error) {
@@ -20,6 +20,7 @@ package knative
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	knativev1 "github.com/apache/camel-k/pkg/apis/camel/v1/knative"
 	uriutils "github.com/apache/camel-k/pkg/util/uri"
@@ -35,6 +36,8 @@ const (
 	paramAPIVersion = "apiVersion"
 	paramKind       = "kind"
 	paramBrokerName = "name"
+
+	paramFilterPrefix = "filter."
 )
 
 // FilterURIs returns all Knative URIs of the given type from a slice.
@@ -88,6 +91,20 @@ func ExtractEventType(uri string) string {
 	return matchOrEmpty(uriRegexp, 2, uri)
 }
 
+// ExtractEventFilters returns the CloudEvents attributes the events consumed from a Broker are filtered on, from the
+// `filter.<attribute>` parameters of an event URI, e.g., `knative:event/order.created?filter.source=orders`.
+// The attributes are also accepted prefixed with `ce-`, the same as the HTTP headers conveying them.
+func ExtractEventFilters(uri string) map[string]string {
+	filters := make(map[string]string)
+	for name, value := range uriutils.GetQueryParametersWithPrefix(uri, paramFilterPrefix) {
+		attribute := strings.TrimPrefix(strings.ToLower(name), "ce-")
+		if attribute != "" {
+			filters[attribute] = value
+		}
+	}
+	return filters
+}
+
 func matchOrEmpty(reg *regexp.Regexp, index int, str string) string {
 	match := reg.FindStringSubmatch(str)
 	if len(match) > index {
@@ -104,3 +104,12 @@ func TestNormalizeToUri(t *testing.T) {
 	assert.Equal(t, "knative://endpoint/xx", NormalizeToURI(knative.CamelServiceTypeEndpoint, "xx"))
 	assert.Equal(t, "direct:xxx", NormalizeToURI(knative.CamelServiceTypeChannel, "direct:xxx"))
 }
+
+func TestEventFilters(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"source":  "orders",
+		"subject": "eu",
+		"tenant":  "acme",
+	}, ExtractEventFilters("knative:event/order.created?name=broker&filter.source=orders&filter.ce-subject=eu&filter.Tenant=acme"))
+	assert.Empty(t, ExtractEventFilters("knative:event/order.created?name=broker"))
+}
@@ -0,0 +1,73 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	clusterDomainEnvVariable = "CLUSTER_DOMAIN"
+	defaultClusterDomain     = "cluster.local"
+)
+
+var (
+	clusterDomain     string
+	clusterDomainOnce sync.Once
+)
+
+// GetClusterDomain returns the DNS domain of the cluster, either set with the CLUSTER_DOMAIN environment variable,
+// or derived from the search domains of the Pod DNS configuration, and defaulting to `cluster.local`.
+func GetClusterDomain() string {
+	clusterDomainOnce.Do(func() {
+		if domain, ok := os.LookupEnv(clusterDomainEnvVariable); ok && domain != "" {
+			clusterDomain = domain
+			return
+		}
+		clusterDomain = defaultClusterDomain
+		f, err := os.Open("/etc/resolv.conf")
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		if domain := clusterDomainFromResolvConf(bufio.NewScanner(f)); domain != "" {
+			clusterDomain = domain
+		}
+	})
+	return clusterDomain
+}
+
+// clusterDomainFromResolvConf returns the cluster domain from the `svc.<domain>` search domain, that the kubelet
+// adds to the DNS configuration of the Pods.
+func clusterDomainFromResolvConf(scanner *bufio.Scanner) string {
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "search" {
+			continue
+		}
+		for _, domain := range fields[1:] {
+			if strings.HasPrefix(domain, "svc.") {
+				return strings.TrimSuffix(strings.TrimPrefix(domain, "svc."), ".")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterDomainFromResolvConf(t *testing.T) {
+	resolvConf := `
+nameserver 10.96.0.10
+search camel-k.svc.my.cluster svc.my.cluster my.cluster
+options ndots:5
+`
+	assert.Equal(t, "my.cluster", clusterDomainFromResolvConf(bufio.NewScanner(strings.NewReader(resolvConf))))
+
+	resolvConf = `
+nameserver 192.168.1.1
+search example.com
+`
+	assert.Equal(t, "", clusterDomainFromResolvConf(bufio.NewScanner(strings.NewReader(resolvConf))))
+}
@@ -19,6 +19,7 @@ package kubernetes
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -165,6 +166,26 @@ func (c *Collection) RemoveDeployment(filter func(*appsv1.Deployment) bool) *app
 	return deploy
 }
 
+// VisitStatefulSet executes the visitor function on all StatefulSet resources.
+func (c *Collection) VisitStatefulSet(visitor func(*appsv1.StatefulSet)) {
+	c.Visit(func(res runtime.Object) {
+		if conv, ok := res.(*appsv1.StatefulSet); ok {
+			visitor(conv)
+		}
+	})
+}
+
+// GetStatefulSet returns a StatefulSet that matches the given function.
+func (c *Collection) GetStatefulSet(filter func(*appsv1.StatefulSet) bool) *appsv1.StatefulSet {
+	var retValue *appsv1.StatefulSet
+	c.VisitStatefulSet(func(re *appsv1.StatefulSet) {
+		if filter(re) {
+			retValue = re
+		}
+	})
+	return retValue
+}
+
 // VisitConfigMap executes the visitor function on all ConfigMap resources.
 func (c *Collection) VisitConfigMap(visitor func(*corev1.ConfigMap)) {
 	c.Visit(func(res runtime.Object) {
@@ -308,6 +329,37 @@ func (c *Collection) VisitCronJobE(visitor func(*v1beta1.CronJob) error) error {
 	})
 }
 
+// GetJob returns a Job that matches the given function.
+func (c *Collection) GetJob(filter func(job *batchv1.Job) bool) *batchv1.Job {
+	var retValue *batchv1.Job
+	c.VisitJob(func(re *batchv1.Job) {
+		if filter(re) {
+			retValue = re
+		}
+	})
+	return retValue
+}
+
+// VisitJob executes the visitor function on all Job resources.
+func (c *Collection) VisitJob(visitor func(*batchv1.Job)) {
+	c.Visit(func(res runtime.Object) {
+		if conv, ok := res.(*batchv1.Job); ok {
+			visitor(conv)
+		}
+	})
+}
+
+// VisitJobE executes the visitor function on all Job resources.
+func (c *Collection) VisitJobE(visitor func(*batchv1.Job) error) error {
+	return c.VisitE(func(res runtime.Object) error {
+		if conv, ok := res.(*batchv1.Job); ok {
+			return visitor(conv)
+		}
+
+		return nil
+	})
+}
+
 // VisitKnativeService executes the visitor function on all Knative serving Service resources.
 func (c *Collection) VisitKnativeService(visitor func(*serving.Service)) {
 	c.Visit(func(res runtime.Object) {
@@ -389,9 +441,21 @@ func (c *Collection) VisitContainer(visitor func(container *corev1.Container)) {
 			visitor(cntref)
 		}
 	})
+	c.VisitJob(func(j *batchv1.Job) {
+		for idx := range j.Spec.Template.Spec.Containers {
+			cntref := &j.Spec.Template.Spec.Containers[idx]
+			visitor(cntref)
+		}
+	})
+	c.VisitStatefulSet(func(s *appsv1.StatefulSet) {
+		for idx := range s.Spec.Template.Spec.Containers {
+			cntref := &s.Spec.Template.Spec.Containers[idx]
+			visitor(cntref)
+		}
+	})
 }
 
-// GetController returns the controller associated with the integration (e.g. Deployment, Knative Service or CronJob).
+// GetController returns the controller associated with the integration (e.g. Deployment, Knative Service, CronJob, Job or StatefulSet).
 func (c *Collection) GetController(filter func(object ctrl.Object) bool) ctrl.Object {
 	d := c.GetDeployment(func(deployment *appsv1.Deployment) bool {
 		return filter(deployment)
@@ -411,6 +475,18 @@ func (c *Collection) GetController(filter func(object ctrl.Object) bool) ctrl.Ob
 	if cj != nil {
 		return cj
 	}
+	j := c.GetJob(func(job *batchv1.Job) bool {
+		return filter(job)
+	})
+	if j != nil {
+		return j
+	}
+	sts := c.GetStatefulSet(func(set *appsv1.StatefulSet) bool {
+		return filter(set)
+	})
+	if sts != nil {
+		return sts
+	}
 	return nil
 }
 
@@ -425,6 +501,12 @@ func (c *Collection) VisitPodSpec(visitor func(container *corev1.PodSpec)) {
 	c.VisitCronJob(func(d *v1beta1.CronJob) {
 		visitor(&d.Spec.JobTemplate.Spec.Template.Spec)
 	})
+	c.VisitJob(func(j *batchv1.Job) {
+		visitor(&j.Spec.Template.Spec)
+	})
+	c.VisitStatefulSet(func(s *appsv1.StatefulSet) {
+		visitor(&s.Spec.Template.Spec)
+	})
 }
 
 // VisitPodTemplateMeta executes the visitor function on all PodTemplate metadata inside deployments or other resources.
@@ -438,6 +520,12 @@ func (c *Collection) VisitPodTemplateMeta(visitor func(meta *metav1.ObjectMeta))
 	c.VisitCronJob(func(d *v1beta1.CronJob) {
 		visitor(&d.Spec.JobTemplate.Spec.Template.ObjectMeta)
 	})
+	c.VisitJob(func(j *batchv1.Job) {
+		visitor(&j.Spec.Template.ObjectMeta)
+	})
+	c.VisitStatefulSet(func(s *appsv1.StatefulSet) {
+		visitor(&s.Spec.Template.ObjectMeta)
+	})
 }
 
 // VisitKnativeConfigurationSpec executes the visitor function on all knative ConfigurationSpec inside serving Services.
@@ -503,3 +591,21 @@ func (c *Collection) GetPodMonitor(filter func(*monitoringv1.PodMonitor) bool) *
 	})
 	return retValue
 }
+
+func (c *Collection) VisitPrometheusRule(visitor func(*monitoringv1.PrometheusRule)) {
+	c.Visit(func(res runtime.Object) {
+		if conv, ok := res.(*monitoringv1.PrometheusRule); ok {
+			visitor(conv)
+		}
+	})
+}
+
+func (c *Collection) GetPrometheusRule(filter func(*monitoringv1.PrometheusRule) bool) *monitoringv1.PrometheusRule {
+	var retValue *monitoringv1.PrometheusRule
+	c.VisitPrometheusRule(func(rule *monitoringv1.PrometheusRule) {
+		if filter(rule) {
+			retValue = rule
+		}
+	})
+	return retValue
+}
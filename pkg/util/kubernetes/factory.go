@@ -31,6 +31,7 @@ var (
 	validTaintRegexp                = regexp.MustCompile(`^([\w\/_\-\.]+)(=)?([\w_\-\.]+)?:(NoSchedule|NoExecute|PreferNoSchedule):?(\d*)?$`)
 	validNodeSelectorRegexp         = regexp.MustCompile(`^([\w\/_\-\.]+)=([\w_\-\.]+)$`)
 	validResourceRequirementsRegexp = regexp.MustCompile(`^(requests|limits)\.(memory|cpu)=([\w\.]+)$`)
+	validTopologySpreadRegexp       = regexp.MustCompile(`^([\w\/_\-\.]+)(?::(\d+))?(?::(DoNotSchedule|ScheduleAnyway))?$`)
 )
 
 // ConfigMapAutogenLabel -- .
@@ -87,6 +88,42 @@ func NewNodeSelectors(nsArray []string) (map[string]string, error) {
 	return nodeSelectors, nil
 }
 
+// NewTopologySpreadConstraints build an array of TopologySpreadConstraints, applying to the pods matching the selector,
+// from an array of string in the form `TopologyKey[:MaxSkew][:WhenUnsatisfiable]`.
+// The max skew defaults to 1, and the pods are scheduled anyway when the constraint can't be satisfied, unless stated otherwise.
+func NewTopologySpreadConstraints(constraints []string, selector *metav1.LabelSelector) ([]corev1.TopologySpreadConstraint, error) {
+	topologySpreadConstraints := make([]corev1.TopologySpreadConstraint, 0, len(constraints))
+	for _, c := range constraints {
+		if !validTopologySpreadRegexp.MatchString(c) {
+			return nil, fmt.Errorf("could not match topology spread constraint %v", c)
+		}
+		// Parse the regexp groups
+		groups := validTopologySpreadRegexp.FindStringSubmatch(c)
+		constraint := corev1.TopologySpreadConstraint{
+			TopologyKey:       groups[1],
+			MaxSkew:           1,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     selector,
+		}
+		if groups[2] != "" {
+			maxSkew, err := strconv.ParseInt(groups[2], 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			if maxSkew < 1 {
+				return nil, fmt.Errorf("invalid topology spread constraint %v, the max skew must be greater than 0", c)
+			}
+			constraint.MaxSkew = int32(maxSkew)
+		}
+		if groups[3] != "" {
+			constraint.WhenUnsatisfiable = corev1.UnsatisfiableConstraintAction(groups[3])
+		}
+		topologySpreadConstraints = append(topologySpreadConstraints, constraint)
+	}
+
+	return topologySpreadConstraints, nil
+}
+
 // NewResourceRequirements will build a CPU and memory requirements from an array of requests
 // matching <requestType.requestResource=value> (ie, limits.memory=256Mi).
 func NewResourceRequirements(reqs []string) (corev1.ResourceRequirements, error) {
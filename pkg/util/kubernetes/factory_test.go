@@ -170,3 +170,37 @@ func TestMissingResourceRequirements(t *testing.T) {
 	_, err := NewResourceRequirements(strings.Split(resReq, ","))
 	assert.NotNil(t, err)
 }
+
+func TestValueTopologySpreadConstraints(t *testing.T) {
+	constraints, err := NewTopologySpreadConstraints([]string{
+		"topology.kubernetes.io/zone",
+		"kubernetes.io/hostname:2:DoNotSchedule",
+		"topology.kubernetes.io/region:ScheduleAnyway",
+	}, nil)
+
+	assert.Nil(t, err)
+	assert.Len(t, constraints, 3)
+	assert.Equal(t, "topology.kubernetes.io/zone", constraints[0].TopologyKey)
+	assert.Equal(t, int32(1), constraints[0].MaxSkew)
+	assert.Equal(t, v1.ScheduleAnyway, constraints[0].WhenUnsatisfiable)
+	assert.Equal(t, "kubernetes.io/hostname", constraints[1].TopologyKey)
+	assert.Equal(t, int32(2), constraints[1].MaxSkew)
+	assert.Equal(t, v1.DoNotSchedule, constraints[1].WhenUnsatisfiable)
+	assert.Equal(t, int32(1), constraints[2].MaxSkew)
+	assert.Equal(t, v1.ScheduleAnyway, constraints[2].WhenUnsatisfiable)
+}
+
+func TestInvalidTopologySpreadConstraints(t *testing.T) {
+	invalidConstraints := [][]string{
+		{""},
+		{"zone:"},
+		{"zone:0"},
+		{"zone:1:Something"},
+		{"zone@wrong:1"},
+		{"zone:DoNotSchedule:1"},
+	}
+	for _, c := range invalidConstraints {
+		_, err := NewTopologySpreadConstraints(c, nil)
+		assert.NotNil(t, err, "constraint %v should be invalid", c)
+	}
+}
@@ -20,9 +20,11 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	"github.com/apache/camel-k/pkg/util/gzip"
+	"github.com/apache/camel-k/pkg/util/upload"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	controller "sigs.k8s.io/controller-runtime/pkg/client"
@@ -56,6 +58,17 @@ func ResolveResource(elements []v1.ResourceSpec, mapLookup func(string) (*corev1
 
 // Resolve --.
 func Resolve(data *v1.DataSpec, mapLookup func(string) (*corev1.ConfigMap, error)) error {
+	// if it is a reference to an uploaded file, get the content
+	// from the operator upload store
+	if upload.IsRef(data.ContentRef) {
+		content, err := readUpload(data.ContentRef)
+		if err != nil {
+			return err
+		}
+		data.Content = content
+		data.ContentRef = ""
+	}
+
 	// if it is a reference, get the content from the
 	// referenced ConfigMap
 	if data.ContentRef != "" {
@@ -94,6 +107,22 @@ func Resolve(data *v1.DataSpec, mapLookup func(string) (*corev1.ConfigMap, error
 	return nil
 }
 
+func readUpload(ref string) (string, error) {
+	dir := upload.Dir()
+	if dir == "" {
+		return "", fmt.Errorf("unable to read the uploaded file %s, the upload store is not configured", ref)
+	}
+	namespace, digest, err := upload.ParseRef(ref)
+	if err != nil {
+		return "", err
+	}
+	var content strings.Builder
+	if err := upload.NewStore(dir).Fetch(context.Background(), namespace, digest, &content); err != nil {
+		return "", fmt.Errorf("unable to read the uploaded file %s: %w", ref, err)
+	}
+	return content.String(), nil
+}
+
 // ResolveIntegrationSources --.
 func ResolveIntegrationSources(
 	context context.Context,
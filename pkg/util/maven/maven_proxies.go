@@ -28,8 +28,31 @@ var ProxyFromEnvironment = proxyFromEnvironment{}
 type proxyFromEnvironment struct{}
 
 func (proxyFromEnvironment) apply(settings *Settings) error {
-	if httpProxy := os.Getenv("HTTP_PROXY"); httpProxy != "" {
-		proxy, err := parseProxyFromEnvVar(httpProxy)
+	return proxies{
+		httpProxy:  os.Getenv("HTTP_PROXY"),
+		httpsProxy: os.Getenv("HTTPS_PROXY"),
+		noProxy:    os.Getenv("NO_PROXY"),
+	}.apply(settings)
+}
+
+// Proxies configures the proxies from the given proxy URLs, and the comma separated list of hosts accessed directly.
+func Proxies(httpProxy string, httpsProxy string, noProxy string) SettingsOption {
+	return proxies{
+		httpProxy:  httpProxy,
+		httpsProxy: httpsProxy,
+		noProxy:    noProxy,
+	}
+}
+
+type proxies struct {
+	httpProxy  string
+	httpsProxy string
+	noProxy    string
+}
+
+func (p proxies) apply(settings *Settings) error {
+	if p.httpProxy != "" {
+		proxy, err := parseProxyFromEnvVar(p.httpProxy, p.noProxy)
 		if err != nil {
 			return err
 		}
@@ -37,8 +60,8 @@ func (proxyFromEnvironment) apply(settings *Settings) error {
 		settings.Proxies = append(settings.Proxies, proxy)
 	}
 
-	if httpsProxy := os.Getenv("HTTPS_PROXY"); httpsProxy != "" {
-		proxy, err := parseProxyFromEnvVar(httpsProxy)
+	if p.httpsProxy != "" {
+		proxy, err := parseProxyFromEnvVar(p.httpsProxy, p.noProxy)
 		if err != nil {
 			return err
 		}
@@ -49,7 +72,7 @@ func (proxyFromEnvironment) apply(settings *Settings) error {
 	return nil
 }
 
-func parseProxyFromEnvVar(proxyEnvVar string) (Proxy, error) {
+func parseProxyFromEnvVar(proxyEnvVar string, noProxy string) (Proxy, error) {
 	u, err := url.Parse(proxyEnvVar)
 	if err != nil {
 		return Proxy{}, err
@@ -74,7 +97,7 @@ func parseProxyFromEnvVar(proxyEnvVar string) (Proxy, error) {
 			proxy.Password = password
 		}
 	}
-	if noProxy := os.Getenv("NO_PROXY"); noProxy != "" {
+	if noProxy != "" {
 		// Convert to the format expected by the JVM http.nonProxyHosts system property
 		hosts := strings.Split(strings.ReplaceAll(noProxy, " ", ""), ",")
 		for i, host := range hosts {
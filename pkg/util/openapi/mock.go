@@ -0,0 +1,343 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml2 "gopkg.in/yaml.v2"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// maxSchemaDepth limits the nesting of the bodies generated from the schemas, e.g., for recursive schemas.
+const maxSchemaDepth = 8
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Operation is an operation of an OpenAPI spec, with the response it's mocked with.
+type Operation struct {
+	ID          string
+	Method      string
+	Path        string
+	Status      int
+	ContentType string
+	Body        string
+}
+
+// Operations returns the operations declared by the given OpenAPI spec, either in JSON or YAML format, sorted by id.
+// Both OpenAPI 2.0 (Swagger) and OpenAPI 3 specs are supported. The response of each operation is the one with
+// the lowest success status code, and its body is the response example, or a value generated from the response schema.
+func Operations(spec []byte) ([]Operation, error) {
+	data, err := yaml.ToJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	_, swagger := doc["swagger"]
+	paths, _ := doc["paths"].(map[string]interface{})
+
+	operations := make([]Operation, 0)
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for _, method := range methods {
+			op, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			if id == "" {
+				return nil, fmt.Errorf("operation %s %s has no operationId", strings.ToUpper(method), path)
+			}
+			operation := Operation{
+				ID:     id,
+				Method: strings.ToUpper(method),
+				Path:   path,
+				Status: http.StatusOK,
+			}
+			if swagger {
+				mockSwaggerResponse(doc, op, &operation)
+			} else {
+				mockResponse(doc, op, &operation)
+			}
+			operations = append(operations, operation)
+		}
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].ID < operations[j].ID
+	})
+
+	return operations, nil
+}
+
+// MockRoutes returns the Camel YAML DSL routes that implement the given operations, by replying with their mocked
+// response. The routes consume from the `direct:<operationId>` endpoints, that the REST DSL generated from the spec
+// calls.
+func MockRoutes(operations []Operation) ([]byte, error) {
+	routes := make([]interface{}, 0, len(operations))
+	for _, operation := range operations {
+		steps := []interface{}{
+			setHeader("CamelHttpResponseCode", strconv.Itoa(operation.Status)),
+		}
+		if operation.ContentType != "" {
+			steps = append(steps, setHeader("Content-Type", operation.ContentType))
+		}
+		steps = append(steps, map[string]interface{}{
+			"setBody": map[string]interface{}{
+				"constant": operation.Body,
+			},
+		})
+		routes = append(routes, map[string]interface{}{
+			"from": map[string]interface{}{
+				"uri":   "direct:" + operation.ID,
+				"steps": steps,
+			},
+		})
+	}
+
+	return yaml2.Marshal(routes)
+}
+
+func setHeader(name string, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"setHeader": map[string]interface{}{
+			"name":     name,
+			"constant": value,
+		},
+	}
+}
+
+// successResponse returns the status code and the definition of the response with the lowest success status code,
+// or of the default response.
+func successResponse(op map[string]interface{}) (int, map[string]interface{}) {
+	responses, _ := op["responses"].(map[string]interface{})
+	status := 0
+	for code := range responses {
+		if c, err := strconv.Atoi(code); err == nil && c >= 200 && c < 300 && (status == 0 || c < status) {
+			status = c
+		}
+	}
+	if status != 0 {
+		response, _ := responses[strconv.Itoa(status)].(map[string]interface{})
+		return status, response
+	}
+	response, _ := responses["default"].(map[string]interface{})
+	return http.StatusOK, response
+}
+
+func mockResponse(doc map[string]interface{}, op map[string]interface{}, operation *Operation) {
+	status, response := successResponse(op)
+	operation.Status = status
+	response = resolve(doc, response)
+
+	content, _ := response["content"].(map[string]interface{})
+	contentType := preferredContentType(content)
+	if contentType == "" {
+		return
+	}
+	operation.ContentType = contentType
+
+	media, _ := content[contentType].(map[string]interface{})
+	if example, ok := media["example"]; ok {
+		operation.Body = serialize(contentType, example)
+		return
+	}
+	if examples, ok := media["examples"].(map[string]interface{}); ok && len(examples) > 0 {
+		names := make([]string, 0, len(examples))
+		for name := range examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if example, ok := resolve(doc, examples[names[0]])["value"]; ok {
+			operation.Body = serialize(contentType, example)
+			return
+		}
+	}
+	if schema, ok := media["schema"]; ok {
+		operation.Body = serialize(contentType, sample(doc, schema, 0))
+	}
+}
+
+func mockSwaggerResponse(doc map[string]interface{}, op map[string]interface{}, operation *Operation) {
+	status, response := successResponse(op)
+	operation.Status = status
+	response = resolve(doc, response)
+
+	examples, _ := response["examples"].(map[string]interface{})
+	schema, hasSchema := response["schema"]
+	if len(examples) == 0 && !hasSchema {
+		return
+	}
+
+	contentType := preferredContentType(examples)
+	if contentType == "" {
+		produces, _ := op["produces"].([]interface{})
+		if len(produces) == 0 {
+			produces, _ = doc["produces"].([]interface{})
+		}
+		contentType = "application/json"
+		for _, p := range produces {
+			if s, ok := p.(string); ok {
+				contentType = s
+				break
+			}
+		}
+	}
+	operation.ContentType = contentType
+
+	if example, ok := examples[contentType]; ok {
+		operation.Body = serialize(contentType, example)
+		return
+	}
+	operation.Body = serialize(contentType, sample(doc, schema, 0))
+}
+
+// preferredContentType returns the JSON content type if declared, or the first content type in alphabetical order.
+func preferredContentType(content map[string]interface{}) string {
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+func serialize(contentType string, value interface{}) string {
+	if s, ok := value.(string); ok && !strings.Contains(contentType, "json") {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// resolve returns the object referenced by the given object, if it's a local reference, e.g.,
+// `#/components/schemas/Pet`, or the object itself.
+func resolve(doc map[string]interface{}, object interface{}) map[string]interface{} {
+	o, _ := object.(map[string]interface{})
+	for i := 0; i < maxSchemaDepth; i++ {
+		ref, ok := o["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return o
+		}
+		var current interface{} = doc
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			m, _ := current.(map[string]interface{})
+			current = m[token]
+		}
+		o, _ = current.(map[string]interface{})
+	}
+	return o
+}
+
+// sample generates a value that is valid against the given schema.
+func sample(doc map[string]interface{}, object interface{}, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+	schema := resolve(doc, object)
+	if schema == nil {
+		return nil
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok && len(allOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, s := range allOf {
+			if m, ok := sample(doc, s, depth+1).(map[string]interface{}); ok {
+				for k, v := range m {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if schemas, ok := schema[keyword].([]interface{}); ok && len(schemas) > 0 {
+			return sample(doc, schemas[0], depth+1)
+		}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	switch {
+	case schemaType == "array":
+		return []interface{}{sample(doc, schema["items"], depth+1)}
+	case schemaType == "object" || schema["properties"] != nil:
+		result := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			result[name] = sample(doc, property, depth+1)
+		}
+		return result
+	case schemaType == "integer":
+		return 0
+	case schemaType == "number":
+		return 0.0
+	case schemaType == "boolean":
+		return true
+	case schemaType == "string":
+		return sampleString(schema)
+	default:
+		return nil
+	}
+}
+
+func sampleString(schema map[string]interface{}) string {
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date":
+		return "1970-01-01"
+	case "date-time":
+		return "1970-01-01T00:00:00Z"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "http://example.com"
+	default:
+		return "string"
+	}
+}
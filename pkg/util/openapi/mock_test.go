@@ -0,0 +1,171 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPI3Spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: The pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: createPet
+      responses:
+        "201":
+          description: Created
+        default:
+          description: Error
+  /pets/{petId}:
+    get:
+      operationId: showPetById
+      responses:
+        "200":
+          description: The pet
+          content:
+            application/json:
+              example:
+                id: 42
+                name: Rex
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+          enum: [dog, cat]
+`
+
+const swaggerSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Greetings", "version": "1.0.0"},
+  "produces": ["text/plain"],
+  "paths": {
+    "/greetings/{name}": {
+      "get": {
+        "operationId": "greet",
+        "responses": {
+          "200": {"description": "The greeting", "schema": {"type": "string", "example": "Hello"}}
+        }
+      }
+    }
+  }
+}`
+
+func TestOperationsFromOpenAPI3(t *testing.T) {
+	operations, err := Operations([]byte(openAPI3Spec))
+	assert.NoError(t, err)
+	assert.Equal(t, []Operation{
+		{
+			ID:     "createPet",
+			Method: "POST",
+			Path:   "/pets",
+			Status: 201,
+		},
+		{
+			ID:          "listPets",
+			Method:      "GET",
+			Path:        "/pets",
+			Status:      200,
+			ContentType: "application/json",
+			Body:        `[{"id":0,"name":"string","tag":"dog"}]`,
+		},
+		{
+			ID:          "showPetById",
+			Method:      "GET",
+			Path:        "/pets/{petId}",
+			Status:      200,
+			ContentType: "application/json",
+			Body:        `{"id":42,"name":"Rex"}`,
+		},
+	}, operations)
+}
+
+func TestOperationsFromSwagger(t *testing.T) {
+	operations, err := Operations([]byte(swaggerSpec))
+	assert.NoError(t, err)
+	assert.Equal(t, []Operation{
+		{
+			ID:          "greet",
+			Method:      "GET",
+			Path:        "/greetings/{name}",
+			Status:      200,
+			ContentType: "text/plain",
+			Body:        "Hello",
+		},
+	}, operations)
+}
+
+func TestOperationsWithoutID(t *testing.T) {
+	_, err := Operations([]byte(`
+openapi: 3.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: The pets
+`))
+	assert.Error(t, err)
+}
+
+func TestMockRoutes(t *testing.T) {
+	routes, err := MockRoutes([]Operation{
+		{
+			ID:          "showPetById",
+			Status:      200,
+			ContentType: "application/json",
+			Body:        `{"id":42}`,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `- from:
+    steps:
+    - setHeader:
+        constant: "200"
+        name: CamelHttpResponseCode
+    - setHeader:
+        constant: application/json
+        name: Content-Type
+    - setBody:
+        constant: '{"id":42}'
+    uri: direct:showPetById
+`, string(routes))
+}
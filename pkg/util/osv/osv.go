@@ -0,0 +1,202 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package osv provides a client for the OSV vulnerability database API (https://osv.dev),
+// that can be served by an offline mirror as well.
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDatabase is the URL of the public OSV database API
+	DefaultDatabase = "https://api.osv.dev"
+	// EcosystemMaven identifies the Maven packages
+	EcosystemMaven = "Maven"
+
+	// the maximum number of queries per batch accepted by the API
+	batchSize = 1000
+)
+
+// Package identifies a version of a package, e.g., a Maven artifact named after its `groupId:artifactId`.
+type Package struct {
+	Name    string
+	Version string
+}
+
+// Vulnerability is the subset of the OSV schema required to rate a vulnerability.
+type Vulnerability struct {
+	ID               string `json:"id"`
+	Summary          string `json:"summary,omitempty"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity,omitempty"`
+	} `json:"database_specific,omitempty"`
+}
+
+// Severity returns the severity of the vulnerability as rated by the database, e.g., `HIGH`,
+// or an empty string if not rated.
+func (v *Vulnerability) Severity() string {
+	return strings.ToUpper(v.DatabaseSpecific.Severity)
+}
+
+// Client queries an OSV database API.
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient returns a client for the OSV database API served at the given URL, or the public one if empty.
+func NewClient(database string) *Client {
+	if database == "" {
+		database = DefaultDatabase
+	}
+	return &Client{
+		url: strings.TrimSuffix(database, "/"),
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+type query struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version   string `json:"version"`
+	PageToken string `json:"page_token,omitempty"`
+}
+
+type batchQuery struct {
+	Queries []query `json:"queries"`
+}
+
+type batchResult struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+		NextPageToken string `json:"next_page_token,omitempty"`
+	} `json:"results"`
+}
+
+// Query returns the IDs of the vulnerabilities affecting each of the given packages of the ecosystem,
+// in the same order as the packages. The queries whose results are paginated are sent again with the
+// returned page token, until all the pages are retrieved.
+func (c *Client) Query(ctx context.Context, ecosystem string, packages []Package) ([][]string, error) {
+	queries := make([]query, 0, len(packages))
+	ids := make([][]string, 0, len(packages))
+	// the indexes of the queries with pages left to retrieve
+	pending := make([]int, 0, len(packages))
+	for i, p := range packages {
+		q := query{Version: p.Version}
+		q.Package.Name = p.Name
+		q.Package.Ecosystem = ecosystem
+		queries = append(queries, q)
+		ids = append(ids, make([]string, 0))
+		pending = append(pending, i)
+	}
+
+	for len(pending) > 0 {
+		next := make([]int, 0)
+
+		for start := 0; start < len(pending); start += batchSize {
+			end := start + batchSize
+			if end > len(pending) {
+				end = len(pending)
+			}
+
+			batch := batchQuery{Queries: make([]query, 0, end-start)}
+			for _, i := range pending[start:end] {
+				batch.Queries = append(batch.Queries, queries[i])
+			}
+
+			var result batchResult
+			if err := c.do(ctx, http.MethodPost, "/v1/querybatch", batch, &result); err != nil {
+				return nil, err
+			}
+			if len(result.Results) != len(batch.Queries) {
+				return nil, fmt.Errorf("unexpected number of results from %s: %d, expected %d", c.url, len(result.Results), len(batch.Queries))
+			}
+
+			for j, r := range result.Results {
+				i := pending[start+j]
+				for _, v := range r.Vulns {
+					ids[i] = append(ids[i], v.ID)
+				}
+				if r.NextPageToken == "" {
+					continue
+				}
+				if r.NextPageToken == queries[i].PageToken {
+					return nil, fmt.Errorf("page token %s of package %s repeated by %s", r.NextPageToken, packages[i].Name, c.url)
+				}
+				queries[i].PageToken = r.NextPageToken
+				next = append(next, i)
+			}
+		}
+
+		pending = next
+	}
+
+	return ids, nil
+}
+
+// Get returns the vulnerability with the given ID.
+func (c *Client) Get(ctx context.Context, id string) (*Vulnerability, error) {
+	var vuln Vulnerability
+	if err := c.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(id), nil, &vuln); err != nil {
+		return nil, err
+	}
+	return &vuln, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var content bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&content).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, &content)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s%s: %s", c.url, path, res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
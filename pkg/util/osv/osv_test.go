@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAndGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			var batch batchQuery
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&batch))
+			assert.Len(t, batch.Queries, 2)
+			assert.Equal(t, EcosystemMaven, batch.Queries[0].Package.Ecosystem)
+			assert.Equal(t, "com.fasterxml.jackson.core:jackson-databind", batch.Queries[0].Package.Name)
+			assert.Equal(t, "2.9.8", batch.Queries[0].Version)
+			_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-1"},{"id":"GHSA-2"}]},{}]}`))
+		case "/v1/vulns/GHSA-1":
+			_, _ = w.Write([]byte(`{"id":"GHSA-1","database_specific":{"severity":"CRITICAL"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL + "/")
+
+	ids, err := c.Query(context.TODO(), EcosystemMaven, []Package{
+		{Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.8"},
+		{Name: "org.apache.camel:camel-core", Version: "3.14.0"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"GHSA-1", "GHSA-2"}, {}}, ids)
+
+	vuln, err := c.Get(context.TODO(), "GHSA-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "CRITICAL", vuln.Severity())
+
+	_, err = c.Get(context.TODO(), "GHSA-3")
+	assert.NotNil(t, err)
+}
+
+func TestQueryPaginated(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var batch batchQuery
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&batch))
+
+		switch requests {
+		case 1:
+			// The first package has three pages of vulnerabilities, the second one only a page
+			assert.Len(t, batch.Queries, 2)
+			assert.Empty(t, batch.Queries[0].PageToken)
+			assert.Empty(t, batch.Queries[1].PageToken)
+			_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-1"}],"next_page_token":"page-2"},{"vulns":[{"id":"GHSA-9"}]}]}`))
+		case 2:
+			// Only the paginated query is sent again
+			assert.Len(t, batch.Queries, 1)
+			assert.Equal(t, "com.fasterxml.jackson.core:jackson-databind", batch.Queries[0].Package.Name)
+			assert.Equal(t, "page-2", batch.Queries[0].PageToken)
+			_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-2"}],"next_page_token":"page-3"}]}`))
+		case 3:
+			assert.Len(t, batch.Queries, 1)
+			assert.Equal(t, "page-3", batch.Queries[0].PageToken)
+			_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-3"}]}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+
+	ids, err := c.Query(context.TODO(), EcosystemMaven, []Package{
+		{Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.8"},
+		{Name: "org.apache.camel:camel-core", Version: "3.14.0"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, [][]string{{"GHSA-1", "GHSA-2", "GHSA-3"}, {"GHSA-9"}}, ids)
+}
+
+func TestQueryRepeatedPageToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[{"vulns":[{"id":"GHSA-1"}],"next_page_token":"page-2"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+
+	_, err := c.Query(context.TODO(), EcosystemMaven, []Package{
+		{Name: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.8"},
+	})
+	assert.NotNil(t, err)
+}
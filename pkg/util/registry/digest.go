@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// ResolveDigest returns the image reference pinned to the digest of its manifest in the registry,
+// e.g., `registry/group/image@sha256:...`. The references that are already pinned are returned unchanged.
+// The credentials are read from the Docker configuration, when provided, then from the default keychain.
+func ResolveDigest(ctx context.Context, image string, insecure bool, dockerConfig []byte) (string, error) {
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+
+	var options []name.Option
+	if insecure {
+		options = append(options, name.Insecure)
+	}
+	ref, err := name.ParseReference(image, options...)
+	if err != nil {
+		return "", err
+	}
+
+	keychain := authn.DefaultKeychain
+	if len(dockerConfig) > 0 {
+		config := DockerConfigList{}
+		if err := json.Unmarshal(dockerConfig, &config); err != nil {
+			return "", err
+		}
+		keychain = authn.NewMultiKeychain(config, authn.DefaultKeychain)
+	}
+
+	descriptor, err := remote.Head(ref, remote.WithAuthFromKeychain(keychain), remote.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	return ref.Context().Digest(descriptor.Digest.String()).Name(), nil
+}
+
+// Resolve returns the credentials of the registry hosting the resource, so that the configuration can be used as a keychain.
+func (l DockerConfigList) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	for server, config := range l.Auths {
+		if serverHost(server) == resource.RegistryStr() {
+			return authn.FromConfig(authn.AuthConfig{
+				Username: config.Username,
+				Password: config.Password,
+				Auth:     config.Auth,
+			}), nil
+		}
+	}
+	return authn.Anonymous, nil
+}
+
+// serverHost returns the host of the server, that may be declared as a URL, e.g., `https://index.docker.io/v1/`.
+func serverHost(server string) string {
+	host := server
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	return host
+}
@@ -0,0 +1,93 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+func TestResolveDigest(t *testing.T) {
+	server := httptest.NewServer(ggcrregistry.New())
+	defer server.Close()
+
+	image := strings.TrimPrefix(server.URL, "http://") + "/camel-k/integration:1.0"
+	ref, err := name.ParseReference(image, name.Insecure)
+	assert.Nil(t, err)
+	img, err := random.Image(1024, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, remote.Write(ref, img))
+	digest, err := img.Digest()
+	assert.Nil(t, err)
+
+	pinned, err := ResolveDigest(context.TODO(), image, true, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.TrimSuffix(image, ":1.0")+"@"+digest.String(), pinned)
+
+	pinned, err = ResolveDigest(context.TODO(), pinned, true, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.TrimSuffix(image, ":1.0")+"@"+digest.String(), pinned)
+
+	_, err = ResolveDigest(context.TODO(), strings.TrimSuffix(image, ":1.0")+":2.0", true, nil)
+	assert.Error(t, err)
+}
+
+func TestDockerConfigListKeychain(t *testing.T) {
+	config := DockerConfigList{
+		Auths: map[string]DockerConfig{
+			"https://index.docker.io/v1/": {Auth: "bmljOnBhc3M="},
+			"quay.io":                     {Username: "nic", Password: "pass"},
+		},
+	}
+
+	auth := resolveAuth(t, config, "docker.io/library/busybox")
+	assert.Equal(t, "bmljOnBhc3M=", auth.Auth)
+
+	auth = resolveAuth(t, config, "quay.io/camel-k/integration")
+	assert.Equal(t, "nic", auth.Username)
+	assert.Equal(t, "pass", auth.Password)
+
+	ref, err := name.ParseReference("gcr.io/camel-k/integration")
+	assert.Nil(t, err)
+	authenticator, err := config.Resolve(ref.Context())
+	assert.Nil(t, err)
+	assert.Equal(t, authn.Anonymous, authenticator)
+}
+
+func resolveAuth(t *testing.T, keychain authn.Keychain, image string) *authn.AuthConfig {
+	t.Helper()
+
+	ref, err := name.ParseReference(image)
+	assert.Nil(t, err)
+	authenticator, err := keychain.Resolve(ref.Context())
+	assert.Nil(t, err)
+	auth, err := authenticator.Authorization()
+	assert.Nil(t, err)
+
+	return auth
+}
@@ -0,0 +1,164 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// ProviderECR obtains the tokens of Amazon Elastic Container Registry, with the IAM role of the operator
+	// service account (IRSA).
+	ProviderECR = "ecr"
+	// ProviderGCR obtains the tokens of Google Container Registry and Artifact Registry, with the Google service
+	// account of the operator (GKE Workload Identity).
+	ProviderGCR = "gcr"
+	// ProviderACR obtains the tokens of Azure Container Registry, with the managed identity of the operator
+	// (Azure Workload Identity).
+	ProviderACR = "acr"
+
+	// tokenExpiryMargin is how long before its expiration a token is renewed.
+	tokenExpiryMargin = 5 * time.Minute
+	// tokenRequestTimeout bounds the requests to the identity and registry services.
+	tokenRequestTimeout = 30 * time.Second
+)
+
+// Token is a short-lived registry credential.
+type Token struct {
+	Username  string
+	Password  string
+	ExpiresAt time.Time
+}
+
+// Expired returns whether the token must be renewed, as it expires within the expiry margin.
+func (t Token) Expired(now time.Time) bool {
+	return !now.Add(tokenExpiryMargin).Before(t.ExpiresAt)
+}
+
+// RenewAfter returns how long the token can be used before it must be renewed.
+func (t Token) RenewAfter(now time.Time) time.Duration {
+	return t.ExpiresAt.Sub(now) - tokenExpiryMargin
+}
+
+// TokenProvider exchanges the workload identity credentials of the operator for the tokens of a registry.
+type TokenProvider interface {
+	// Token returns a token pulling the images of the given registry, e.g., `123456789012.dkr.ecr.eu-west-1.amazonaws.com`
+	Token(ctx context.Context, registry string) (Token, error)
+}
+
+var (
+	tokenProviders     = map[string]TokenProvider{}
+	tokenProvidersLock sync.Mutex
+)
+
+// GetTokenProvider returns the token provider of the given name, configured from the environment of the operator.
+// The providers are shared, so that their tokens are cached until they expire.
+func GetTokenProvider(name string) (TokenProvider, error) {
+	tokenProvidersLock.Lock()
+	defer tokenProvidersLock.Unlock()
+
+	if provider, ok := tokenProviders[name]; ok {
+		return provider, nil
+	}
+
+	var provider TokenProvider
+	switch name {
+	case ProviderECR:
+		provider = newECRTokenProviderFromEnv()
+	case ProviderGCR:
+		provider = newGCRTokenProviderFromEnv()
+	case ProviderACR:
+		provider = newACRTokenProviderFromEnv()
+	default:
+		return nil, fmt.Errorf("unsupported registry token provider %s, expected %s, %s or %s", name, ProviderECR, ProviderGCR, ProviderACR)
+	}
+	provider = newCachingTokenProvider(provider)
+	tokenProviders[name] = provider
+
+	return provider, nil
+}
+
+// newCachingTokenProvider returns a provider that caches the tokens of the given provider, per registry,
+// until they expire.
+func newCachingTokenProvider(provider TokenProvider) TokenProvider {
+	return &cachingTokenProvider{
+		provider: provider,
+		tokens:   map[string]Token{},
+		now:      time.Now,
+	}
+}
+
+type cachingTokenProvider struct {
+	provider TokenProvider
+	tokens   map[string]Token
+	lock     sync.Mutex
+	now      func() time.Time
+}
+
+func (p *cachingTokenProvider) Token(ctx context.Context, registry string) (Token, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if token, ok := p.tokens[registry]; ok && !token.Expired(p.now()) {
+		return token, nil
+	}
+	token, err := p.provider.Token(ctx, registry)
+	if err != nil {
+		return Token{}, err
+	}
+	p.tokens[registry] = token
+
+	return token, nil
+}
+
+// newTokenHTTPClient returns the HTTP client of the requests to the identity and registry services.
+func newTokenHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: tokenRequestTimeout,
+	}
+}
+
+// doJSON sends the request, and decodes the JSON response into the given value.
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(req, resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// checkResponse returns an error holding the start of the response body if the request failed.
+func checkResponse(req *http.Request, resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%s %s%s failed with status %d: %s", req.Method, req.URL.Host, req.URL.Path, resp.StatusCode, string(body))
+}
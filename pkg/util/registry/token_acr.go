@@ -0,0 +1,173 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// The environment variables set by the Azure Workload Identity webhook
+	acrClientIDEnvVariable           = "AZURE_CLIENT_ID"
+	acrTenantIDEnvVariable           = "AZURE_TENANT_ID"
+	acrFederatedTokenFileEnvVariable = "AZURE_FEDERATED_TOKEN_FILE"
+	acrAuthorityHostEnvVariable      = "AZURE_AUTHORITY_HOST"
+	acrDefaultAuthorityHost          = "https://login.microsoftonline.com/"
+	acrManagementScope               = "https://management.azure.com/.default"
+	// acrUsername is the user name of the registry logins with a refresh token.
+	acrUsername = "00000000-0000-0000-0000-000000000000"
+	// acrRefreshTokenTTL is the lifetime of the refresh tokens whose expiration cannot be read.
+	acrRefreshTokenTTL = 3 * time.Hour
+)
+
+// acrTokenProvider exchanges the federated token of the operator service account for an Azure AD access token,
+// and the latter for an Azure Container Registry refresh token.
+type acrTokenProvider struct {
+	client        *http.Client
+	authorityHost string
+	tenantID      string
+	clientID      string
+	tokenFile     string
+	// the scheme of the registry token exchange endpoint
+	registryScheme string
+	now            func() time.Time
+}
+
+func newACRTokenProviderFromEnv() *acrTokenProvider {
+	authorityHost := os.Getenv(acrAuthorityHostEnvVariable)
+	if authorityHost == "" {
+		authorityHost = acrDefaultAuthorityHost
+	}
+	return &acrTokenProvider{
+		client:         newTokenHTTPClient(),
+		authorityHost:  authorityHost,
+		tenantID:       os.Getenv(acrTenantIDEnvVariable),
+		clientID:       os.Getenv(acrClientIDEnvVariable),
+		tokenFile:      os.Getenv(acrFederatedTokenFileEnvVariable),
+		registryScheme: "https",
+		now:            time.Now,
+	}
+}
+
+func (p *acrTokenProvider) Token(ctx context.Context, registry string) (Token, error) {
+	if p.tenantID == "" || p.clientID == "" || p.tokenFile == "" {
+		return Token{}, fmt.Errorf("the operator is not configured with Azure Workload Identity: %s, %s and %s must be set",
+			acrTenantIDEnvVariable, acrClientIDEnvVariable, acrFederatedTokenFileEnvVariable)
+	}
+	accessToken, err := p.accessToken(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "access_token")
+	form.Set("service", registry)
+	form.Set("tenant", p.tenantID)
+	form.Set("access_token", accessToken)
+	req, err := newFormRequest(ctx, p.registryScheme+"://"+registry+"/oauth2/exchange", form)
+	if err != nil {
+		return Token{}, err
+	}
+	var response struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := doJSON(p.client, req, &response); err != nil {
+		return Token{}, err
+	}
+	if response.RefreshToken == "" {
+		return Token{}, fmt.Errorf("no refresh token returned by registry %s", registry)
+	}
+
+	expiresAt, ok := jwtExpiration(response.RefreshToken)
+	if !ok {
+		expiresAt = p.now().Add(acrRefreshTokenTTL)
+	}
+	return Token{
+		Username:  acrUsername,
+		Password:  response.RefreshToken,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// accessToken exchanges the federated token for an Azure AD access token of the management API.
+func (p *acrTokenProvider) accessToken(ctx context.Context) (string, error) {
+	assertion, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("client_id", p.clientID)
+	form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+	form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", acrManagementScope)
+	req, err := newFormRequest(ctx, strings.TrimSuffix(p.authorityHost, "/")+"/"+p.tenantID+"/oauth2/v2.0/token", form)
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doJSON(p.client, req, &response); err != nil {
+		return "", err
+	}
+	if response.AccessToken == "" {
+		return "", errors.New("no access token returned by Azure AD")
+	}
+
+	return response.AccessToken, nil
+}
+
+func newFormRequest(ctx context.Context, target string, form url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// jwtExpiration returns the expiration of the JWT, that is not verified, as it's only forwarded to the registry.
+func jwtExpiration(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
@@ -0,0 +1,254 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// The environment variables set by the EKS Pod Identity webhook (IRSA)
+	ecrRoleARNEnvVariable              = "AWS_ROLE_ARN"
+	ecrWebIdentityTokenFileEnvVariable = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	ecrRegionEnvVariable               = "AWS_REGION"
+	ecrDefaultRegionEnvVariable        = "AWS_DEFAULT_REGION"
+	ecrRoleSessionName                 = "camel-k-operator"
+	ecrGetAuthorizationTokenTarget     = "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken"
+)
+
+// ecrRegistryRegexp matches the private registries of Amazon ECR, e.g., `123456789012.dkr.ecr.eu-west-1.amazonaws.com`.
+var ecrRegistryRegexp = regexp.MustCompile(`^(\d{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.(amazonaws\.com(?:\.cn)?)$`)
+
+// ecrTokenProvider exchanges the web identity token of the operator service account for the credentials of its IAM role,
+// and calls the ECR GetAuthorizationToken API with the latter.
+type ecrTokenProvider struct {
+	client    *http.Client
+	roleARN   string
+	tokenFile string
+	region    string
+	// the endpoints of the STS and ECR APIs, for the given region and domain
+	stsEndpoint func(region string, domain string) string
+	ecrEndpoint func(region string, domain string) string
+	now         func() time.Time
+}
+
+type awsCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+}
+
+func newECRTokenProviderFromEnv() *ecrTokenProvider {
+	region := os.Getenv(ecrRegionEnvVariable)
+	if region == "" {
+		region = os.Getenv(ecrDefaultRegionEnvVariable)
+	}
+	return &ecrTokenProvider{
+		client:    newTokenHTTPClient(),
+		roleARN:   os.Getenv(ecrRoleARNEnvVariable),
+		tokenFile: os.Getenv(ecrWebIdentityTokenFileEnvVariable),
+		region:    region,
+		stsEndpoint: func(region string, domain string) string {
+			return fmt.Sprintf("https://sts.%s.%s/", region, domain)
+		},
+		ecrEndpoint: func(region string, domain string) string {
+			return fmt.Sprintf("https://api.ecr.%s.%s/", region, domain)
+		},
+		now: time.Now,
+	}
+}
+
+func (p *ecrTokenProvider) Token(ctx context.Context, registry string) (Token, error) {
+	if p.roleARN == "" || p.tokenFile == "" {
+		return Token{}, fmt.Errorf("the operator is not configured with IAM roles for service accounts: %s and %s must be set",
+			ecrRoleARNEnvVariable, ecrWebIdentityTokenFileEnvVariable)
+	}
+	region, domain := p.region, "amazonaws.com"
+	var registryIDs []string
+	if m := ecrRegistryRegexp.FindStringSubmatch(registry); m != nil {
+		registryIDs = []string{m[1]}
+		region, domain = m[2], m[3]
+	}
+	if region == "" {
+		return Token{}, fmt.Errorf("cannot determine the AWS region of registry %s, %s must be set", registry, ecrRegionEnvVariable)
+	}
+
+	credentials, err := p.assumeRole(ctx, region, domain)
+	if err != nil {
+		return Token{}, err
+	}
+
+	body, err := json.Marshal(struct {
+		RegistryIDs []string `json:"registryIds,omitempty"`
+	}{
+		RegistryIDs: registryIDs,
+	})
+	if err != nil {
+		return Token{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.ecrEndpoint(region, domain), strings.NewReader(string(body)))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", ecrGetAuthorizationTokenTarget)
+	signV4(req, body, credentials, region, "ecr", p.now())
+
+	var response struct {
+		AuthorizationData []struct {
+			AuthorizationToken string  `json:"authorizationToken"`
+			ExpiresAt          float64 `json:"expiresAt"`
+		} `json:"authorizationData"`
+	}
+	if err := doJSON(p.client, req, &response); err != nil {
+		return Token{}, err
+	}
+	if len(response.AuthorizationData) == 0 {
+		return Token{}, fmt.Errorf("no authorization token returned for registry %s", registry)
+	}
+	data := response.AuthorizationData[0]
+	decoded, err := base64.StdEncoding.DecodeString(data.AuthorizationToken)
+	if err != nil {
+		return Token{}, err
+	}
+	credentialParts := strings.SplitN(string(decoded), ":", 2)
+	if len(credentialParts) != 2 {
+		return Token{}, errors.New("invalid ECR authorization token")
+	}
+	seconds, fraction := math.Modf(data.ExpiresAt)
+
+	return Token{
+		Username:  credentialParts[0],
+		Password:  credentialParts[1],
+		ExpiresAt: time.Unix(int64(seconds), int64(fraction*float64(time.Second))),
+	}, nil
+}
+
+// assumeRole exchanges the web identity token for the temporary credentials of the IAM role.
+func (p *ecrTokenProvider) assumeRole(ctx context.Context, region string, domain string) (awsCredentials, error) {
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithWebIdentity")
+	form.Set("Version", "2011-06-15")
+	form.Set("RoleArn", p.roleARN)
+	form.Set("RoleSessionName", ecrRoleSessionName)
+	form.Set("WebIdentityToken", strings.TrimSpace(string(token)))
+	req, err := newFormRequest(ctx, p.stsEndpoint(region, domain), form)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(req, resp); err != nil {
+		return awsCredentials{}, err
+	}
+
+	var response struct {
+		Credentials awsCredentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return awsCredentials{}, err
+	}
+	if response.Credentials.AccessKeyID == "" {
+		return awsCredentials{}, errors.New("no credentials returned by AWS STS")
+	}
+
+	return response.Credentials, nil
+}
+
+// signV4 signs the request with the AWS Signature Version 4, including all its headers.
+func signV4(req *http.Request, body []byte, credentials awsCredentials, region string, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	uri := req.URL.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uri,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,81 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// gcrMetadataHostEnvVariable overrides the host of the metadata server, as the Google client libraries do.
+	gcrMetadataHostEnvVariable = "GCE_METADATA_HOST"
+	gcrDefaultMetadataHost     = "metadata.google.internal"
+	gcrTokenPath               = "/computeMetadata/v1/instance/service-accounts/default/token"
+	// gcrUsername is the user name of the registry logins with an OAuth2 access token.
+	gcrUsername = "oauth2accesstoken"
+)
+
+// gcrTokenProvider obtains the OAuth2 access tokens of the Google service account of the operator from the
+// metadata server, that pull the images of Container Registry and Artifact Registry.
+type gcrTokenProvider struct {
+	client *http.Client
+	// the URL of the access token endpoint of the metadata server
+	tokenURL string
+	now      func() time.Time
+}
+
+func newGCRTokenProviderFromEnv() *gcrTokenProvider {
+	host := gcrDefaultMetadataHost
+	if h, ok := os.LookupEnv(gcrMetadataHostEnvVariable); ok && h != "" {
+		host = h
+	}
+	return &gcrTokenProvider{
+		client:   newTokenHTTPClient(),
+		tokenURL: "http://" + host + gcrTokenPath,
+		now:      time.Now,
+	}
+}
+
+func (p *gcrTokenProvider) Token(ctx context.Context, _ string) (Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.tokenURL, nil)
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doJSON(p.client, req, &response); err != nil {
+		return Token{}, err
+	}
+	if response.AccessToken == "" {
+		return Token{}, errors.New("no access token returned by the metadata server")
+	}
+
+	return Token{
+		Username:  gcrUsername,
+		Password:  response.AccessToken,
+		ExpiresAt: p.now().Add(time.Duration(response.ExpiresIn) * time.Second),
+	}, nil
+}
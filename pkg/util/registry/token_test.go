@@ -0,0 +1,247 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignV4(t *testing.T) {
+	// The get-vanilla case of the AWS Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	assert.Nil(t, err)
+	credentials := awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now, err := time.Parse("20060102T150405Z", "20150830T123600Z")
+	assert.Nil(t, err)
+
+	signV4(req, nil, credentials, "us-east-1", "service", now)
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestECRTokenProvider(t *testing.T) {
+	tokenFile := path.Join(t.TempDir(), "token")
+	assert.Nil(t, ioutil.WriteFile(tokenFile, []byte("web-identity-token\n"), 0o600))
+
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/camel-k", r.PostForm.Get("RoleArn"))
+		assert.Equal(t, "web-identity-token", r.PostForm.Get("WebIdentityToken"))
+		fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2022-05-01T13:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`)
+	}))
+	defer sts.Close()
+
+	ecr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"registryIds":["123456789012"]}`, string(body))
+		assert.Equal(t, ecrGetAuthorizationTokenTarget, r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=ASIAEXAMPLE/20220501/eu-west-1/ecr/aws4_request, "))
+		token := base64.StdEncoding.EncodeToString([]byte("AWS:ecr-password"))
+		fmt.Fprintf(w, `{"authorizationData":[{"authorizationToken":"%s","expiresAt":1651453200.5}]}`, token)
+	}))
+	defer ecr.Close()
+
+	var regions []string
+	provider := &ecrTokenProvider{
+		client:    ecr.Client(),
+		roleARN:   "arn:aws:iam::123456789012:role/camel-k",
+		tokenFile: tokenFile,
+		region:    "us-east-1",
+		stsEndpoint: func(region string, domain string) string {
+			regions = append(regions, region+"."+domain)
+			return sts.URL
+		},
+		ecrEndpoint: func(region string, domain string) string {
+			regions = append(regions, region+"."+domain)
+			return ecr.URL
+		},
+		now: func() time.Time {
+			return time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+		},
+	}
+
+	token, err := provider.Token(context.TODO(), "123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "AWS", token.Username)
+	assert.Equal(t, "ecr-password", token.Password)
+	assert.Equal(t, time.Unix(1651453200, int64(500*time.Millisecond)), token.ExpiresAt)
+	// The region of the registry prevails over the one of the operator
+	assert.Equal(t, []string{"eu-west-1.amazonaws.com", "eu-west-1.amazonaws.com"}, regions)
+
+	provider.roleARN = ""
+	_, err = provider.Token(context.TODO(), "123456789012.dkr.ecr.eu-west-1.amazonaws.com")
+	assert.NotNil(t, err)
+}
+
+func TestGCRTokenProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, gcrTokenPath, r.URL.Path)
+		fmt.Fprint(w, `{"access_token":"gcr-token","expires_in":3599,"token_type":"Bearer"}`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	provider := &gcrTokenProvider{
+		client:   server.Client(),
+		tokenURL: server.URL + gcrTokenPath,
+		now: func() time.Time {
+			return now
+		},
+	}
+
+	token, err := provider.Token(context.TODO(), "europe-docker.pkg.dev")
+	assert.Nil(t, err)
+	assert.Equal(t, Token{Username: gcrUsername, Password: "gcr-token", ExpiresAt: now.Add(3599 * time.Second)}, token)
+}
+
+func TestACRTokenProvider(t *testing.T) {
+	tokenFile := path.Join(t.TempDir(), "token")
+	assert.Nil(t, ioutil.WriteFile(tokenFile, []byte("federated-token"), 0o600))
+
+	authority := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/my-tenant/oauth2/v2.0/token", r.URL.Path)
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "my-client", r.PostForm.Get("client_id"))
+		assert.Equal(t, "federated-token", r.PostForm.Get("client_assertion"))
+		assert.Equal(t, acrManagementScope, r.PostForm.Get("scope"))
+		fmt.Fprint(w, `{"access_token":"aad-token","expires_in":3599}`)
+	}))
+	defer authority.Close()
+
+	// A refresh token expiring at 2022-05-01T15:00:00Z
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1651417200}`))
+	refreshToken := "header." + claims + ".signature"
+	var registry string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/oauth2/exchange", r.URL.Path)
+		assert.Nil(t, r.ParseForm())
+		assert.Equal(t, "access_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, registry, r.PostForm.Get("service"))
+		assert.Equal(t, "my-tenant", r.PostForm.Get("tenant"))
+		assert.Equal(t, "aad-token", r.PostForm.Get("access_token"))
+		fmt.Fprintf(w, `{"refresh_token":"%s"}`, refreshToken)
+	}))
+	defer server.Close()
+	registry = strings.TrimPrefix(server.URL, "http://")
+
+	provider := &acrTokenProvider{
+		client:         server.Client(),
+		authorityHost:  authority.URL + "/",
+		tenantID:       "my-tenant",
+		clientID:       "my-client",
+		tokenFile:      tokenFile,
+		registryScheme: "http",
+		now:            time.Now,
+	}
+
+	token, err := provider.Token(context.TODO(), registry)
+	assert.Nil(t, err)
+	assert.Equal(t, acrUsername, token.Username)
+	assert.Equal(t, refreshToken, token.Password)
+	assert.Equal(t, time.Unix(1651417200, 0), token.ExpiresAt)
+
+	provider.tokenFile = ""
+	_, err = provider.Token(context.TODO(), registry)
+	assert.NotNil(t, err)
+}
+
+type fakeTokenProvider struct {
+	calls int
+	ttl   time.Duration
+	now   time.Time
+}
+
+func (p *fakeTokenProvider) Token(_ context.Context, registry string) (Token, error) {
+	p.calls++
+	return Token{Username: registry, Password: fmt.Sprintf("token-%d", p.calls), ExpiresAt: p.now.Add(p.ttl)}, nil
+}
+
+func TestCachingTokenProvider(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	fake := &fakeTokenProvider{ttl: time.Hour, now: now}
+	provider := newCachingTokenProvider(fake).(*cachingTokenProvider)
+	provider.now = func() time.Time {
+		return now
+	}
+
+	token, err := provider.Token(context.TODO(), "registry-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token.Password)
+	assert.Equal(t, 55*time.Minute, token.RenewAfter(now))
+
+	token, err = provider.Token(context.TODO(), "registry-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "token-1", token.Password)
+
+	// The tokens are cached per registry
+	token, err = provider.Token(context.TODO(), "registry-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", token.Password)
+
+	// The tokens are renewed before they expire
+	now = now.Add(56 * time.Minute)
+	token, err = provider.Token(context.TODO(), "registry-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "token-3", token.Password)
+	assert.Equal(t, 3, fake.calls)
+}
+
+func TestGetTokenProvider(t *testing.T) {
+	provider, err := GetTokenProvider(ProviderGCR)
+	assert.Nil(t, err)
+	assert.NotNil(t, provider)
+
+	shared, err := GetTokenProvider(ProviderGCR)
+	assert.Nil(t, err)
+	assert.Same(t, provider, shared)
+
+	_, err = GetTokenProvider("quay")
+	assert.EqualError(t, err, "unsupported registry token provider quay, expected ecr, gcr or acr")
+}
@@ -91,6 +91,8 @@ const (
 	StorageTypeFile StorageType = "file"
 	// StorageTypePVC --.
 	StorageTypePVC StorageType = "pvc"
+	// StorageTypeImage is a file stored in the Integration image, relative to the deployments directory.
+	StorageTypeImage StorageType = "image"
 )
 
 // ContentType represent what kind of a content is, either data or purely text configuration.
@@ -106,6 +108,7 @@ const (
 var (
 	validConfigSecretRegexp = regexp.MustCompile(`^(configmap|secret)\:([\w\.\-\_\:\/@]+)$`)
 	validFileRegexp         = regexp.MustCompile(`^file\:([\w\.\-\_\:\/@" ]+)$`)
+	validImageRegexp        = regexp.MustCompile(`^image\:([\w\.\-\_\/]+)@([\w\.\-\_\/]+)$`)
 	validResourceRegexp     = regexp.MustCompile(`^([\w\.\-\_\:]+)(\/([\w\.\-\_\:]+))?(\@([\w\.\-\_\:\/]+))?$`)
 )
 
@@ -121,7 +124,7 @@ func newConfig(storageType StorageType, contentType ContentType, value string) *
 }
 
 func parseResourceValue(storageType StorageType, value string) (resource string, maybeKey string, maybeDestinationPath string) {
-	if storageType == StorageTypeFile {
+	if storageType == StorageTypeFile || storageType == StorageTypeImage {
 		resource, maybeDestinationPath = ParseFileValue(value)
 		return resource, "", maybeDestinationPath
 	}
@@ -194,6 +197,10 @@ func parse(item string, contentType ContentType) (*Config, error) {
 		groups := validFileRegexp.FindStringSubmatch(item)
 		cot = StorageTypeFile
 		value = groups[1]
+	case contentType == ContentTypeData && validImageRegexp.MatchString(item):
+		// parse as a file stored in the Integration image
+		cot = StorageTypeImage
+		value = strings.TrimPrefix(item, "image:")
 	default:
 		return nil, fmt.Errorf("could not match config, secret or file configuration as %s", item)
 	}
@@ -141,3 +141,20 @@ func TestParseConfigOptionAllParams(t *testing.T) {
 	assert.Equal(t, "", parsedFile3.Key())
 	assert.Equal(t, "", parsedFile3.DestinationPath())
 }
+
+func TestParseImageResource(t *testing.T) {
+	parsed, err := ParseResource("image:resources/4d7d9f12/data.bin@/etc/camel/resources/data.bin")
+	assert.Nil(t, err)
+	assert.Equal(t, StorageTypeImage, parsed.StorageType())
+	assert.Equal(t, "resources/4d7d9f12/data.bin", parsed.Name())
+	assert.Equal(t, "", parsed.Key())
+	assert.Equal(t, "/etc/camel/resources/data.bin", parsed.DestinationPath())
+	assert.Equal(t, "image:resources/4d7d9f12/data.bin@/etc/camel/resources/data.bin", parsed.String())
+
+	// The destination path is mandatory
+	_, err = ParseResource("image:resources/4d7d9f12/data.bin")
+	assert.NotNil(t, err)
+	// Only resources can be stored in the image
+	_, err = ParseConfig("image:resources/4d7d9f12/data.bin@/etc/camel/resources/data.bin")
+	assert.NotNil(t, err)
+}
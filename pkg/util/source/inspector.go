@@ -62,6 +62,8 @@ var (
 	xtokenizeRegexp         = regexp.MustCompile(`.*\.xtokenize\s*\(.*\).*`)
 	singleQuotedKameletEip  = regexp.MustCompile(`kamelet\s*\(\s*'(?://)?([a-z0-9-.]+(/[a-z0-9-.]+)?)(?:$|[^a-z0-9-.].*)'`)
 	doubleQuotedKameletEip  = regexp.MustCompile(`kamelet\s*\(\s*"(?://)?([a-z0-9-.]+(/[a-z0-9-.]+)?)(?:$|[^a-z0-9-.].*)"`)
+	singleQuotedRouteID     = regexp.MustCompile(`\.routeId\s*\(\s*'([^']+)'\s*\)`)
+	doubleQuotedRouteID     = regexp.MustCompile(`\.routeId\s*\(\s*"([^"]+)"\s*\)`)
 
 	sourceCapabilities = map[*regexp.Regexp][]string{
 		circuitBreakerRegexp: {v1.CapabilityCircuitBreaker},
@@ -302,6 +304,15 @@ func (i *baseInspector) discoverKamelets(source v1.SourceSpec, meta *Metadata) {
 	}
 }
 
+// discoverRouteIDs extracts the ids explicitly given to the routes
+func (i *baseInspector) discoverRouteIDs(source v1.SourceSpec, meta *Metadata) {
+	meta.RouteIDs = append(meta.RouteIDs, util.FindAllDistinctStringSubmatch(
+		source.Content,
+		singleQuotedRouteID,
+		doubleQuotedRouteID,
+	)...)
+}
+
 func (i *baseInspector) addDependency(dependency string, meta *Metadata) {
 	meta.Dependencies.Add(dependency)
 }
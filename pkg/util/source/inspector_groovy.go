@@ -61,6 +61,7 @@ func (i GroovyInspector) Extract(source v1.SourceSpec, meta *Metadata) error {
 	i.discoverCapabilities(source, meta)
 	i.discoverDependencies(source, meta)
 	i.discoverKamelets(source, meta)
+	i.discoverRouteIDs(source, meta)
 
 	hasRest := restRegexp.MatchString(source.Content) || restClosureRegexp.MatchString(source.Content)
 	if hasRest {
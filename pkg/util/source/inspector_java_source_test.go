@@ -90,3 +90,32 @@ func TestJavaSourceKamelet(t *testing.T) {
 		})
 	}
 }
+
+func TestJavaSourceRouteIDs(t *testing.T) {
+	code := v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Content: `
+from("timer:tick").routeId("ticker")
+    .to("log:info");
+from("direct:start")
+    .routeId( "starter" )
+    .to("log:info");
+from("direct:other")
+    .to("log:info");
+`,
+		},
+	}
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	meta := NewMetadata()
+	inspector := JavaSourceInspector{
+		baseInspector: baseInspector{
+			catalog: catalog,
+		},
+	}
+
+	assert.Nil(t, inspector.Extract(code, &meta))
+	assert.ElementsMatch(t, []string{"ticker", "starter"}, meta.RouteIDs)
+}
@@ -56,6 +56,12 @@ func (i XMLInspector) Extract(source v1.SourceSpec, meta *Metadata) error {
 						}
 					}
 				}
+			case "route":
+				for _, a := range se.Attr {
+					if a.Name.Local == "id" {
+						meta.RouteIDs = append(meta.RouteIDs, a.Value)
+					}
+				}
 			case "from", "fromF":
 				for _, a := range se.Attr {
 					if a.Name.Local == "uri" {
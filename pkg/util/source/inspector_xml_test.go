@@ -103,3 +103,35 @@ func TestXMLKamelet(t *testing.T) {
 		})
 	}
 }
+
+func TestXMLRouteIDs(t *testing.T) {
+	code := v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Content: `
+<routes xmlns="http://camel.apache.org/schema/spring">
+  <route id="ticker">
+    <from uri="timer:tick"/>
+    <to uri="log:info"/>
+  </route>
+  <route>
+    <from uri="direct:start"/>
+    <to uri="log:info"/>
+  </route>
+</routes>
+`,
+		},
+	}
+
+	catalog, err := camel.DefaultCatalog()
+	assert.Nil(t, err)
+
+	meta := NewMetadata()
+	inspector := XMLInspector{
+		baseInspector: baseInspector{
+			catalog: catalog,
+		},
+	}
+
+	assert.Nil(t, inspector.Extract(code, &meta))
+	assert.Equal(t, []string{"ticker"}, meta.RouteIDs)
+}
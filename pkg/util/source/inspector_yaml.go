@@ -42,6 +42,13 @@ func (i YAMLInspector) Extract(source v1.SourceSpec, meta *Metadata) error {
 
 	for _, definition := range definitions {
 		for k, v := range definition {
+			if k == "route" || k == "from" {
+				if route, ok := v.(map[interface{}]interface{}); ok {
+					if id, ok := route["id"].(string); ok {
+						meta.RouteIDs = append(meta.RouteIDs, id)
+					}
+				}
+			}
 			if err := i.parseStep(k, v, meta); err != nil {
 				return err
 			}
@@ -557,3 +557,36 @@ func TestYAMLExplicitParameters(t *testing.T) {
 		})
 	}
 }
+
+const YAMLRouteIDs = `
+- route:
+    id: ticker
+    from:
+      uri: timer:tick
+      steps:
+      - to: log:info
+- from:
+    id: starter
+    uri: direct:start
+    steps:
+    - to: log:info
+- from:
+    uri: direct:other
+    steps:
+    - to: log:info
+`
+
+func TestYAMLRouteIDs(t *testing.T) {
+	code := v1.SourceSpec{
+		DataSpec: v1.DataSpec{
+			Name:    "route.yaml",
+			Content: YAMLRouteIDs,
+		},
+		Language: v1.LanguageYaml,
+	}
+
+	meta := NewMetadata()
+	inspector := NewtestYAMLInspector(t)
+	assert.Nil(t, inspector.Extract(code, &meta))
+	assert.Equal(t, []string{"ticker", "starter"}, meta.RouteIDs)
+}
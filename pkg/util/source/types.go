@@ -39,6 +39,8 @@ type Metadata struct {
 	RequiredCapabilities *strset.Set
 	// All kamelets
 	Kamelets []string
+	// The ids explicitly given to the routes
+	RouteIDs []string
 }
 
 // NewMetadata --.
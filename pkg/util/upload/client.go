@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// Client fetches the uploaded files from the operator API, on behalf of a Build, e.g., from the builder Pods
+// that cannot access the operator store.
+type Client struct {
+	url   string
+	token string
+	build string
+	http  *http.Client
+}
+
+var _ Fetcher = &Client{}
+
+// NewClient returns a client fetching the files added to the given Build from the operator API at the given URL,
+// authenticated with the token of a service account allowed to get the Build.
+func NewClient(operatorURL, token, buildNamespace, buildName string) *Client {
+	return &Client{
+		url:   operatorURL,
+		token: token,
+		build: buildNamespace + "/" + buildName,
+		http:  http.DefaultClient,
+	}
+}
+
+// Fetch copies the uploaded file with the given digest in the namespace.
+func (c *Client) Fetch(ctx context.Context, namespace, digest string, w io.Writer) error {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, Path, namespace, digest)
+	u.RawQuery = url.Values{BuildParam: []string{c.build}}.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set(TokenHeader, c.token)
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		_, err = io.Copy(w, response.Body)
+		return err
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("cannot fetch %s from the operator: %s: %s", Ref(namespace, digest), response.Status, message)
+	}
+}
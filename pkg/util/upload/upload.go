@@ -0,0 +1,362 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// Path is the path of the operator endpoint receiving the files uploaded by the CLI.
+	Path = "/upload/"
+	// TokenHeader is the request header holding the bearer token of the user, as the API server does not forward
+	// the Authorization header through the Pod proxy.
+	TokenHeader = "X-Camel-K-Token"
+	// DependencyPrefix is the prefix of the Integration dependencies referencing an uploaded file,
+	// e.g., `upload:my-ns/sha256:0123...@camel-k-resources/1a2b3c4d/data.bin`.
+	DependencyPrefix = "upload:"
+	// DigestPrefix is the prefix of the digests identifying the uploaded files.
+	DigestPrefix = "sha256:"
+	// SourcesDir is the directory of the uploaded sources in the Integration image, relative to the deployments directory.
+	SourcesDir = "camel-k-sources"
+	// DirEnvVariable is the environment variable holding the directory of the operator upload store,
+	// e.g., the mount path of a persistent volume.
+	DirEnvVariable = "KAMEL_UPLOAD_DIR"
+	// URLEnvVariable is the environment variable holding the URL of the operator API, that the builder Pods fetch
+	// the uploaded files from.
+	URLEnvVariable = "KAMEL_UPLOAD_URL"
+	// BuildParam is the query parameter naming the Build, as namespace/name, the uploaded files are fetched for.
+	BuildParam = "build"
+
+	partialDir = ".partial"
+	// partialTTL is how long an upload that is not committed is kept.
+	partialTTL = 1 * time.Hour
+)
+
+// Response is the response of the upload endpoint.
+type Response struct {
+	// the identifier of the upload, returned when it's created
+	ID string `json:"id,omitempty"`
+	// the size of the data received so far
+	Size int64 `json:"size,omitempty"`
+	// the digest of the file, returned when the upload is committed
+	Digest string `json:"digest,omitempty"`
+}
+
+// ErrNotFound is returned when an upload, or an uploaded file, does not exist.
+var ErrNotFound = errors.New("upload not found")
+
+// ErrOffsetMismatch is returned when a chunk does not start at the end of the data already received.
+var ErrOffsetMismatch = errors.New("chunk offset does not match the size of the upload")
+
+var (
+	namespaceRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	idRegexp        = regexp.MustCompile(`^[a-f0-9]{32}$`)
+	digestRegexp    = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// Dir returns the directory of the operator upload store, or an empty string if the store is not configured.
+func Dir() string {
+	return os.Getenv(DirEnvVariable)
+}
+
+// Ref returns the reference to the uploaded file, e.g., the content reference of an uploaded source.
+func Ref(namespace, digest string) string {
+	return fmt.Sprintf("%s%s/%s", DependencyPrefix, namespace, digest)
+}
+
+// IsRef returns true if the content reference points to an uploaded file, rather than a ConfigMap.
+func IsRef(ref string) bool {
+	return strings.HasPrefix(ref, DependencyPrefix)
+}
+
+// ParseRef returns the namespace and the digest of the uploaded file.
+func ParseRef(ref string) (string, string, error) {
+	namespace, digest, _ := cut(strings.TrimPrefix(ref, DependencyPrefix), "/")
+	if !IsRef(ref) || !namespaceRegexp.MatchString(namespace) || !digestRegexp.MatchString(digest) {
+		return "", "", fmt.Errorf("invalid upload reference %s, expected upload:namespace/digest", ref)
+	}
+	return namespace, digest, nil
+}
+
+// Dependency returns the Integration dependency that adds the uploaded file to the Integration image,
+// at the given path relative to the deployments directory.
+func Dependency(namespace, digest, targetPath string) string {
+	return Ref(namespace, digest) + "@" + targetPath
+}
+
+// HasDependency returns true if one of the dependencies adds an uploaded file to the Integration image.
+func HasDependency(dependencies []string) bool {
+	for _, d := range dependencies {
+		if strings.HasPrefix(d, DependencyPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SourcePath returns the path of the uploaded source in the Integration image, relative to the deployments directory.
+func SourcePath(name string) string {
+	return path.Join(SourcesDir, strings.TrimPrefix(name, "/"))
+}
+
+// ParseDependency returns the namespace, the digest and the target path of an upload dependency.
+func ParseDependency(dependency string) (string, string, string, error) {
+	ref, targetPath, ok := cut(dependency, "@")
+	if !ok || targetPath == "" {
+		return "", "", "", fmt.Errorf("invalid upload dependency %s, expected upload:namespace/digest@path", dependency)
+	}
+	namespace, digest, err := ParseRef(ref)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid upload dependency %s, expected upload:namespace/digest@path", dependency)
+	}
+	if filepath.IsAbs(targetPath) || strings.HasPrefix(filepath.Clean(targetPath), "..") {
+		return "", "", "", fmt.Errorf("invalid upload dependency %s, the path must be relative to the deployments directory", dependency)
+	}
+	return namespace, digest, targetPath, nil
+}
+
+// Fetcher copies the uploaded files, e.g., to the image context of the builds.
+type Fetcher interface {
+	Fetch(ctx context.Context, namespace, digest string, w io.Writer) error
+}
+
+var _ Fetcher = &Store{}
+
+// Store is a content-addressed file store, in which the files are uploaded in chunks, then committed with the
+// digest of their content, so that they are stored once per namespace, whatever the number of Integrations using them.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store persisting the files in the given directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Create starts a new upload in the namespace and returns its identifier.
+func (s *Store) Create(namespace string) (string, error) {
+	if !namespaceRegexp.MatchString(namespace) {
+		return "", fmt.Errorf("invalid namespace %q", namespace)
+	}
+	dir := filepath.Join(s.dir, namespace, partialDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	s.prunePartials(dir)
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+	f, err := os.OpenFile(filepath.Join(dir, id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	return id, f.Close()
+}
+
+// Append writes a chunk at the end of an upload, and returns the size of the data received so far.
+// The offset must be the size of the data already received, so that chunks that are retried are not appended twice.
+func (s *Store) Append(namespace, id string, offset int64, r io.Reader) (int64, error) {
+	p, err := s.partialPath(namespace, id)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY, 0o600)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if offset != size {
+		return size, ErrOffsetMismatch
+	}
+	n, err := io.Copy(f, r)
+	return size + n, err
+}
+
+// Commit checks the content of an upload against the expected digest, and moves it to the store.
+func (s *Store) Commit(namespace, id, digest string) error {
+	if !digestRegexp.MatchString(digest) {
+		return fmt.Errorf("invalid digest %q, expected sha256:<hex>", digest)
+	}
+	p, err := s.partialPath(namespace, id)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	actual, err := Digest(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if actual != digest {
+		// nolint: errcheck
+		os.Remove(p)
+		return fmt.Errorf("digest mismatch: expected %s, received %s", digest, actual)
+	}
+
+	return os.Rename(p, filepath.Join(s.dir, namespace, strings.TrimPrefix(digest, DigestPrefix)))
+}
+
+// Open returns the file stored with the given digest in the namespace.
+func (s *Store) Open(namespace, digest string) (*os.File, error) {
+	p, err := s.Path(namespace, digest)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+// Path returns the location of the file stored with the given digest in the namespace.
+func (s *Store) Path(namespace, digest string) (string, error) {
+	if !namespaceRegexp.MatchString(namespace) || !digestRegexp.MatchString(digest) {
+		return "", ErrNotFound
+	}
+	p := filepath.Join(s.dir, namespace, strings.TrimPrefix(digest, DigestPrefix))
+	if _, err := os.Stat(p); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return p, nil
+}
+
+// Fetch copies the file stored with the given digest in the namespace.
+func (s *Store) Fetch(_ context.Context, namespace, digest string, w io.Writer) error {
+	f, err := s.Open(namespace, digest)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Prune removes the files committed for longer than the retention, that are not used anymore, as well as the uploads
+// that have not been committed in time, and returns the references to the removed files. The files are committed
+// again when they are uploaded again, so that the retention protects the files uploaded before the Integrations
+// using them are created.
+func (s *Store) Prune(retention time.Duration, inUse func(namespace, digest string) bool) ([]string, error) {
+	namespaces, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	removed := make([]string, 0)
+	for _, ns := range namespaces {
+		if !ns.IsDir() || !namespaceRegexp.MatchString(ns.Name()) {
+			continue
+		}
+		dir := filepath.Join(s.dir, ns.Name())
+		s.prunePartials(filepath.Join(dir, partialDir))
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return removed, err
+		}
+		for _, e := range entries {
+			digest := DigestPrefix + e.Name()
+			if e.IsDir() || !digestRegexp.MatchString(digest) || inUse(ns.Name(), digest) {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil || time.Since(info.ModTime()) <= retention {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil && !os.IsNotExist(err) {
+				return removed, err
+			}
+			removed = append(removed, Ref(ns.Name(), digest))
+		}
+	}
+
+	return removed, nil
+}
+
+func (s *Store) partialPath(namespace, id string) (string, error) {
+	if !namespaceRegexp.MatchString(namespace) || !idRegexp.MatchString(id) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.dir, namespace, partialDir, id), nil
+}
+
+// prunePartials removes the uploads that have not been committed in time, e.g., because the CLI has been interrupted.
+func (s *Store) prunePartials(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > partialTTL {
+			// nolint: errcheck
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+// Digest returns the digest identifying the content in the store.
+func Digest(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return DigestPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
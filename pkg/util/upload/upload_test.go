@@ -0,0 +1,201 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreUploadInChunks(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	id, err := store.Create("ns")
+	assert.Nil(t, err)
+
+	size, err := store.Append("ns", id, 0, strings.NewReader("hello "))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), size)
+
+	// a retried chunk is not appended twice
+	size, err = store.Append("ns", id, 0, strings.NewReader("hello "))
+	assert.Equal(t, ErrOffsetMismatch, err)
+	assert.Equal(t, int64(6), size)
+
+	size, err = store.Append("ns", id, 6, strings.NewReader("world"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), size)
+
+	digest, err := Digest(strings.NewReader("hello world"))
+	assert.Nil(t, err)
+	assert.Nil(t, store.Commit("ns", id, digest))
+
+	f, err := store.Open("ns", digest)
+	assert.Nil(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", string(data))
+
+	_, err = store.Open("other", digest)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestStoreCommitDigestMismatch(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	id, err := store.Create("ns")
+	assert.Nil(t, err)
+	_, err = store.Append("ns", id, 0, strings.NewReader("hello"))
+	assert.Nil(t, err)
+
+	digest, err := Digest(strings.NewReader("world"))
+	assert.Nil(t, err)
+	assert.NotNil(t, store.Commit("ns", id, digest))
+
+	_, err = store.Open("ns", digest)
+	assert.Equal(t, ErrNotFound, err)
+	// the upload is discarded
+	assert.Equal(t, ErrNotFound, store.Commit("ns", id, digest))
+}
+
+func TestStoreRejectsInvalidReferences(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	_, err := store.Create("../ns")
+	assert.NotNil(t, err)
+
+	_, err = store.Append("ns", "../../etc/passwd", 0, strings.NewReader(""))
+	assert.Equal(t, ErrNotFound, err)
+
+	_, err = store.Open("ns", "sha256:../../etc/passwd")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestParseDependency(t *testing.T) {
+	digest := DigestPrefix + strings.Repeat("a", 64)
+	dependency := Dependency("ns", digest, "camel-k-resources/1a2b3c4d/data.bin")
+	assert.Equal(t, "upload:ns/"+digest+"@camel-k-resources/1a2b3c4d/data.bin", dependency)
+
+	namespace, d, targetPath, err := ParseDependency(dependency)
+	assert.Nil(t, err)
+	assert.Equal(t, "ns", namespace)
+	assert.Equal(t, digest, d)
+	assert.Equal(t, "camel-k-resources/1a2b3c4d/data.bin", targetPath)
+
+	for _, invalid := range []string{
+		"upload:ns/" + digest,
+		"upload:ns/sha256:abc@data.bin",
+		"upload:ns/" + digest + "@../data.bin",
+		"upload:ns/" + digest + "@/etc/data.bin",
+		"mvn:ns/" + digest + "@data.bin",
+	} {
+		_, _, _, err := ParseDependency(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	digest := DigestPrefix + strings.Repeat("a", 64)
+	ref := Ref("ns", digest)
+	assert.True(t, IsRef(ref))
+	assert.False(t, IsRef("my-configmap"))
+
+	namespace, d, err := ParseRef(ref)
+	assert.Nil(t, err)
+	assert.Equal(t, "ns", namespace)
+	assert.Equal(t, digest, d)
+
+	_, _, err = ParseRef("upload:ns/sha256:abc")
+	assert.NotNil(t, err)
+	assert.Equal(t, "camel-k-sources/routes.yaml", SourcePath("/routes.yaml"))
+}
+
+func TestStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	commit := func(namespace, content string) string {
+		id, err := store.Create(namespace)
+		assert.Nil(t, err)
+		_, err = store.Append(namespace, id, 0, strings.NewReader(content))
+		assert.Nil(t, err)
+		digest, err := Digest(strings.NewReader(content))
+		assert.Nil(t, err)
+		assert.Nil(t, store.Commit(namespace, id, digest))
+		return digest
+	}
+	used := commit("ns", "used")
+	unused := commit("ns", "unused")
+	recent := commit("other", "recent")
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, digest := range []string{used, unused} {
+		p, err := store.Path("ns", digest)
+		assert.Nil(t, err)
+		assert.Nil(t, os.Chtimes(p, old, old))
+	}
+
+	removed, err := store.Prune(time.Hour, func(namespace, digest string) bool {
+		return namespace == "ns" && digest == used
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{Ref("ns", unused)}, removed)
+
+	_, err = store.Path("ns", used)
+	assert.Nil(t, err)
+	_, err = store.Path("ns", unused)
+	assert.Equal(t, ErrNotFound, err)
+	_, err = store.Path("other", recent)
+	assert.Nil(t, err)
+
+	removed, err = NewStore(filepath.Join(dir, "missing")).Prune(time.Hour, func(string, string) bool { return false })
+	assert.Nil(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestClientFetch(t *testing.T) {
+	digest := DigestPrefix + strings.Repeat("a", 64)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "token", r.Header.Get(TokenHeader))
+		assert.Equal(t, "build-ns/build", r.URL.Query().Get(BuildParam))
+		if r.URL.Path != Path+"ns/"+digest {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", "build-ns", "build")
+	var data bytes.Buffer
+	assert.Nil(t, client.Fetch(context.Background(), "ns", digest, &data))
+	assert.Equal(t, "data", data.String())
+
+	assert.Equal(t, ErrNotFound, client.Fetch(context.Background(), "other", digest, &data))
+}
@@ -57,6 +57,29 @@ func GetQueryParameter(uri string, param string) string {
 	return res
 }
 
+// GetQueryParametersWithPrefix returns the parameters of the uri whose name starts with the given prefix, keyed by
+// their name without the prefix.
+func GetQueryParametersWithPrefix(uri string, prefix string) map[string]string {
+	params := make(map[string]string)
+	i := strings.Index(uri, "?")
+	if i < 0 {
+		return params
+	}
+	for _, param := range strings.Split(uri[i+1:], "&") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || !strings.HasPrefix(kv[0], prefix) {
+			continue
+		}
+		val, err := url.QueryUnescape(kv[1])
+		if err != nil {
+			log.Error(err, fmt.Sprintf("Invalid character sequence in parameter %q", kv[0]))
+			continue
+		}
+		params[strings.TrimPrefix(kv[0], prefix)] = val
+	}
+	return params
+}
+
 // GetPathSegment returns the path segment of the URI corresponding to the given position (0 based), if present.
 func GetPathSegment(uri string, pos int) string {
 	match := pathExtractorRegexp.FindStringSubmatch(uri)
@@ -90,6 +90,15 @@ func TestQueryParameter(t *testing.T) {
 	}
 }
 
+func TestQueryParametersWithPrefix(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"source":    "orders",
+		"ce-tenant": "acme corp",
+	}, GetQueryParametersWithPrefix("knative:event/order.created?filter.source=orders&name=broker&filter.ce-tenant=acme%20corp", "filter."))
+	assert.Empty(t, GetQueryParametersWithPrefix("knative:event/order.created", "filter."))
+	assert.Empty(t, GetQueryParametersWithPrefix("knative:event/order.created?name=broker", "filter."))
+}
+
 func TestAppendParameters(t *testing.T) {
 	tests := []struct {
 		prefix   string